@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/indexer"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+// runBackfill implements "indexer backfill": index the history of both
+// programs within a slot range, then exit.
+func runBackfill(args []string) error {
+	fs := newFlagSet("backfill", "Index historical transactions of both programs, newest first, then exit.")
+	fromSlot := fs.Uint64("from-slot", 0, "oldest slot to index, inclusive (default the first transaction)")
+	toSlot := fs.Uint64("to-slot", 0, "newest slot to index, inclusive (default the newest transaction)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := applyConfigFlags(fs); err != nil {
+		return err
+	}
+
+	idx, err := newBatchIndexer()
+	if err != nil {
+		return err
+	}
+	defer idx.Shutdown(context.Background())
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	n, err := idx.Backfill(ctx, indexer.BackfillOptions{FromSlot: *fromSlot, ToSlot: *toSlot})
+	if err != nil {
+		return err
+	}
+	log.Printf("backfilled %d transactions", n)
+	return nil
+}
+
+// runReindex implements "indexer reindex": delete the events of a slot range
+// and index it again, e.g. after a decoder fix.
+func runReindex(args []string) error {
+	fs := newFlagSet("reindex", "Delete the stored events of a slot range and index the range again.")
+	fromSlot := fs.Uint64("from-slot", 0, "first slot to re-index, inclusive (required)")
+	toSlot := fs.Uint64("to-slot", 0, "last slot to re-index, inclusive (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := applyConfigFlags(fs); err != nil {
+		return err
+	}
+	if *fromSlot == 0 || *toSlot == 0 || *fromSlot > *toSlot {
+		return fmt.Errorf("-from-slot and -to-slot are required and must form a range")
+	}
+
+	idx, err := newBatchIndexer()
+	if err != nil {
+		return err
+	}
+	defer idx.Shutdown(context.Background())
+
+	deleter, ok := repository.Unwrap(idx.Repository()).(repository.EventDeleter)
+	if !ok {
+		return fmt.Errorf("%T does not support deleting events", repository.Unwrap(idx.Repository()))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	deleted, err := deleter.DeleteEvents(ctx, repository.EventFilter{FromSlot: *fromSlot, ToSlot: *toSlot, Network: idx.Network()})
+	if err != nil {
+		return fmt.Errorf("delete events: %w", err)
+	}
+	log.Printf("deleted %d events in slots %d-%d", deleted, *fromSlot, *toSlot)
+
+	n, err := idx.Backfill(ctx, indexer.BackfillOptions{FromSlot: *fromSlot, ToSlot: *toSlot, Reprocess: true})
+	if err != nil {
+		return err
+	}
+	log.Printf("re-indexed %d transactions", n)
+	return nil
+}
+
+func newBatchIndexer() (*indexer.Indexer, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+	if cfg.Network == "" && len(cfg.Networks) > 0 {
+		return nil, fmt.Errorf("NETWORKS is set: choose the network with -network")
+	}
+	idx, err := indexer.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("create indexer: %w", err)
+	}
+	return idx, nil
+}
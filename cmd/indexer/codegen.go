@@ -0,0 +1,106 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/codegen"
+)
+
+// runCodegen implements "indexer codegen": generate the instruction,
+// account and event types of a program and their Borsh decoders from its
+// Anchor IDL.
+func runCodegen(args []string) error {
+	fs := flag.NewFlagSet("codegen", flag.ExitOnError)
+	idlPath := fs.String("idl", "idl/starter_program.json", "Anchor IDL file")
+	outputPath := fs.String("output", "pkg/generated/starterprogram", "output directory")
+	pkg := fs.String("package", "", "Go package name (default the output directory name)")
+	templates := fs.String("template", "", "comma separated text/template files to execute on the IDL, e.g. for a GraphQL schema")
+	templateOutput := fs.String("template-output", "", "output directory of the templates (default the directory of each template)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: indexer codegen [flags]\n\nGenerate Go types and Borsh decoders from an Anchor IDL, and other\nartifacts from text/template files.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *pkg == "" {
+		*pkg = filepath.Base(*outputPath)
+	}
+
+	f, err := os.Open(*idlPath)
+	if err != nil {
+		return fmt.Errorf("open IDL: %w", err)
+	}
+	defer f.Close()
+	idl, err := codegen.ReadIDL(f)
+	if err != nil {
+		return err
+	}
+	files, err := codegen.Generate(idl, *pkg)
+	if err != nil {
+		return fmt.Errorf("generate code: %w", err)
+	}
+
+	if err := os.MkdirAll(*outputPath, 0755); err != nil {
+		return fmt.Errorf("create output directory: %w", err)
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		path := filepath.Join(*outputPath, name)
+		if err := os.WriteFile(path, files[name], 0644); err != nil {
+			return fmt.Errorf("write %s: %w", path, err)
+		}
+	}
+
+	log.Printf("generated %d instructions, %d accounts and %d events from %s into %s",
+		len(idl.Instructions), len(idl.Accounts), len(idl.Events), *idlPath, *outputPath)
+
+	for _, path := range strings.Split(*templates, ",") {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+		if err := executeTemplate(idl, path, *templateOutput); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// executeTemplate writes the output of a template to a file named after
+// it without the .tmpl extension, e.g. schema.graphql for
+// schema.graphql.tmpl.
+func executeTemplate(idl *codegen.IDL, path, outputDir string) error {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read template: %w", err)
+	}
+	out, err := codegen.ExecuteTemplate(idl, filepath.Base(path), string(text))
+	if err != nil {
+		return fmt.Errorf("template %s: %w", path, err)
+	}
+
+	if outputDir == "" {
+		outputDir = filepath.Dir(path)
+	} else if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("create template output directory: %w", err)
+	}
+	outPath := filepath.Join(outputDir, strings.TrimSuffix(filepath.Base(path), ".tmpl"))
+	if outPath == path {
+		return fmt.Errorf("template %s: name it with a .tmpl extension", path)
+	}
+	if err := os.WriteFile(outPath, out, 0644); err != nil {
+		return fmt.Errorf("write %s: %w", outPath, err)
+	}
+	log.Printf("generated %s from %s", outPath, path)
+	return nil
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/joho/godotenv"
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+)
+
+// runConfig implements "indexer config export" and "indexer config import",
+// converting between the environment and a versioned manifest file, and
+// "indexer config validate".
+func runConfig(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: indexer config export|import|validate [flags]")
+	}
+	switch args[0] {
+	case "export":
+		return runConfigExport(args[1:])
+	case "import":
+		return runConfigImport(args[1:])
+	case "validate":
+		return runConfigValidate(args[1:])
+	default:
+		return fmt.Errorf("unknown config command %q, want export, import or validate", args[0])
+	}
+}
+
+func runConfigExport(args []string) error {
+	fs := newFlagSet("config export", "Write the current configuration as a JSON manifest.")
+	output := fs.String("output", "-", "output file, - for stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := applyConfigFlags(fs); err != nil {
+		return err
+	}
+
+	// Refuse to export a configuration the indexer would not start with.
+	if _, err := config.Load(); err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	m, err := config.ExportManifest()
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(*output, func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(m)
+	})
+}
+
+func runConfigImport(args []string) error {
+	fs := newFlagSet("config import", "Validate a manifest and write it as KEY=value lines for a .env file.")
+	output := fs.String("output", "-", "output file, - for stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: indexer config import [-output file] <manifest>")
+	}
+	if err := applyConfigFlags(fs); err != nil {
+		return err
+	}
+
+	m, err := config.ReadManifestFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	env := m.Env()
+	for name, value := range env {
+		if err := os.Setenv(name, value); err != nil {
+			return fmt.Errorf("set %s: %w", name, err)
+		}
+	}
+	if _, err := config.Load(); err != nil {
+		return fmt.Errorf("manifest is invalid: %w", err)
+	}
+
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return writeOutput(*output, func(w io.Writer) error {
+		for _, name := range names {
+			if _, err := fmt.Fprintf(w, "%s=%s\n", name, env[name]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// runConfigValidate loads the configuration the indexer would start with:
+// the manifest given, or CONFIG_MANIFEST, overridden by the environment and
+// .env file.
+func runConfigValidate(args []string) error {
+	fs := newFlagSet("config validate", "Check the configuration, optionally with a JSON or YAML manifest, without starting the indexer.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return fmt.Errorf("usage: indexer config validate [manifest]")
+	}
+	if err := applyConfigFlags(fs); err != nil {
+		return err
+	}
+
+	_ = godotenv.Load()
+	if fs.NArg() == 1 {
+		if err := os.Setenv("CONFIG_MANIFEST", fs.Arg(0)); err != nil {
+			return fmt.Errorf("set CONFIG_MANIFEST: %w", err)
+		}
+	}
+
+	var overridden []string
+	if path := os.Getenv("CONFIG_MANIFEST"); path != "" {
+		m, err := config.ReadManifestFile(path)
+		if err != nil {
+			return err
+		}
+		for name := range m.Env() {
+			if _, ok := os.LookupEnv(name); ok {
+				overridden = append(overridden, name)
+			}
+		}
+		sort.Strings(overridden)
+	}
+
+	if _, err := config.Load(); err != nil {
+		return fmt.Errorf("configuration is invalid: %w", err)
+	}
+	fmt.Println("configuration is valid")
+	for _, name := range overridden {
+		fmt.Printf("  %s: set by the environment, overriding the manifest\n", name)
+	}
+	return nil
+}
+
+func writeOutput(path string, write func(io.Writer) error) error {
+	if path == "-" {
+		return write(os.Stdout)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", path, err)
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
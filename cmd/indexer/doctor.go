@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/lugondev/go-indexer-solana-starter/internal/codegen"
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/decoder"
+	"github.com/lugondev/go-indexer-solana-starter/internal/indexer"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+	solanaClient "github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// doctorReport prints the result of each check as it runs and counts the
+// failures.
+type doctorReport struct {
+	failed int
+}
+
+func (r *doctorReport) ok(check, format string, args ...interface{}) {
+	fmt.Printf("[ok]   %s: %s\n", check, fmt.Sprintf(format, args...))
+}
+
+func (r *doctorReport) warn(check, format string, args ...interface{}) {
+	fmt.Printf("[warn] %s: %s\n", check, fmt.Sprintf(format, args...))
+}
+
+func (r *doctorReport) fail(check string, err error) {
+	r.failed++
+	fmt.Printf("[fail] %s: %v\n", check, err)
+}
+
+// runDoctor implements "indexer doctor": it checks that the indexer could
+// start and index with the current configuration, without starting it.
+func runDoctor(args []string) error {
+	fs := newFlagSet("doctor", "Check the configuration, RPC endpoints, database and programs, and print a readiness report.")
+	idlPath := fs.String("idl", "idl/starter_program.json", "Anchor IDL of the starter program, compared with the one deployed on-chain")
+	programHash := fs.String("program-hash", "", "expected hash of the deployed starter program, as solana-verify get-program-hash prints it")
+	timeout := fs.Duration("timeout", 30*time.Second, "time allowed for all checks")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := applyConfigFlags(fs); err != nil {
+		return err
+	}
+
+	report := &doctorReport{}
+	cfg, err := config.Load()
+	if err != nil {
+		report.fail("config", err)
+		return fmt.Errorf("configuration is invalid")
+	}
+	report.ok("config", "valid, %s database", cfg.DatabaseType)
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	client := doctorRPC(ctx, report, cfg)
+	doctorDatabase(ctx, report, cfg)
+	if client != nil {
+		doctorPrograms(ctx, report, cfg, client, *idlPath, *programHash)
+	}
+
+	if report.failed > 0 {
+		return fmt.Errorf("%d checks failed", report.failed)
+	}
+	fmt.Println("ready")
+	return nil
+}
+
+// doctorRPC checks every RPC endpoint and returns a client of the first
+// healthy one, nil if none is.
+func doctorRPC(ctx context.Context, report *doctorReport, cfg *config.Config) *solanaClient.Client {
+	endpoints, err := cfg.RPCEndpoints()
+	if err != nil {
+		report.fail("rpc", err)
+		return nil
+	}
+	var healthy *solanaClient.Client
+	for _, e := range endpoints {
+		check := "rpc " + e.Name
+		client, err := solanaClient.NewClient(e.URL, cfg.SolanaWSURL)
+		if err != nil {
+			report.fail(check, err)
+			continue
+		}
+		start := time.Now()
+		slot, err := client.GetSlot(ctx, rpc.CommitmentConfirmed)
+		if err != nil {
+			report.fail(check, fmt.Errorf("%s: %w", e.URL, err))
+			continue
+		}
+		report.ok(check, "%s at slot %d (%v)", e.URL, slot, time.Since(start).Round(time.Millisecond))
+		if healthy == nil {
+			healthy = client
+		}
+	}
+	return healthy
+}
+
+// doctorDatabase connects to the database and, for PostgreSQL, checks that
+// no migration is pending.
+func doctorDatabase(ctx context.Context, report *doctorReport, cfg *config.Config) {
+	repo, err := indexer.NewRepository(cfg)
+	if err != nil {
+		report.fail("database", err)
+		return
+	}
+	defer repo.Close(context.Background())
+	report.ok("database", "connected to %s", cfg.DatabaseType)
+
+	r, ok := repository.Unwrap(repo).(*repository.PostgresRepository)
+	if !ok {
+		return
+	}
+	statuses, err := r.MigrationStatus(ctx)
+	if err != nil {
+		report.fail("migrations", err)
+		return
+	}
+	var pending []string
+	for _, s := range statuses {
+		if !s.Applied {
+			pending = append(pending, fmt.Sprintf("%04d_%s", s.Version, s.Name))
+		}
+	}
+	switch {
+	case len(pending) == 0:
+		report.ok("migrations", "%d applied", len(statuses))
+	case cfg.DatabaseAutoMigrate:
+		report.warn("migrations", "%s pending, applied at start", strings.Join(pending, ", "))
+	default:
+		report.fail("migrations", fmt.Errorf("%s pending and DATABASE_AUTO_MIGRATE is off, run indexer migrate", strings.Join(pending, ", ")))
+	}
+}
+
+type doctorProgram struct {
+	name, id string
+}
+
+// doctorPrograms checks that the indexed programs are deployed, and that
+// the starter program matches its IDL and, if given, its expected hash.
+func doctorPrograms(ctx context.Context, report *doctorReport, cfg *config.Config, client *solanaClient.Client, idlPath, programHash string) {
+	programs := []doctorProgram{{"starter", cfg.StarterProgramID}}
+	for _, d := range cfg.CounterDeployments() {
+		name := "counter"
+		if d.Label != "" {
+			name += " " + d.Label
+		}
+		programs = append(programs, doctorProgram{name, d.ProgramID})
+	}
+	if cfg.LogGrammarFile != "" {
+		grammars, err := decoder.ReadLogGrammarFile(cfg.LogGrammarFile)
+		if err != nil {
+			report.fail("log grammars", err)
+		}
+		for _, g := range grammars {
+			programs = append(programs, doctorProgram{g.Name, g.Program.String()})
+		}
+	}
+
+	for _, p := range programs {
+		check := "program " + p.name
+		program, err := solana.PublicKeyFromBase58(p.id)
+		if err != nil {
+			report.fail(check, fmt.Errorf("%q: %w", p.id, err))
+			continue
+		}
+		executable, err := client.GetProgramExecutable(ctx, program)
+		if err != nil {
+			report.fail(check, err)
+			continue
+		}
+		hash := solanaClient.ProgramHash(executable)
+		if p.name == "starter" && programHash != "" && !strings.EqualFold(hash, programHash) {
+			report.fail(check, fmt.Errorf("%s is deployed with hash %s, want %s", program, hash, programHash))
+			continue
+		}
+		report.ok(check, "%s deployed, hash %s", program, hash)
+	}
+
+	starter, err := solana.PublicKeyFromBase58(cfg.StarterProgramID)
+	if err == nil {
+		doctorIDL(ctx, report, client, starter, idlPath)
+	}
+}
+
+// doctorIDL compares the IDL at idlPath with the one uploaded for program,
+// if any.
+func doctorIDL(ctx context.Context, report *doctorReport, client *solanaClient.Client, program solana.PublicKey, idlPath string) {
+	f, err := os.Open(idlPath)
+	if err != nil {
+		report.fail("idl", fmt.Errorf("open IDL: %w", err))
+		return
+	}
+	defer f.Close()
+	local, err := codegen.ReadIDL(f)
+	if err != nil {
+		report.fail("idl", err)
+		return
+	}
+	if local.Address != "" && local.Address != program.String() {
+		report.fail("idl", fmt.Errorf("%s is the IDL of %s, not of STARTER_PROGRAM_ID %s", idlPath, local.Address, program))
+		return
+	}
+
+	raw, err := codegen.FetchIDL(ctx, client, program)
+	if errors.Is(err, solanaClient.ErrAccountNotFound) {
+		report.warn("idl", "no IDL uploaded for %s, it cannot be compared with %s", program, idlPath)
+		return
+	}
+	if err != nil {
+		report.fail("idl", err)
+		return
+	}
+	deployed, err := codegen.ReadIDL(bytes.NewReader(raw))
+	if err != nil {
+		report.fail("idl", fmt.Errorf("deployed IDL: %w", err))
+		return
+	}
+	if diffs := codegen.DiffIDL(local, deployed); len(diffs) > 0 {
+		report.fail("idl", fmt.Errorf("%s differs from the deployed IDL:\n       %s", idlPath, strings.Join(diffs, "\n       ")))
+		return
+	}
+	report.ok("idl", "%s matches the deployed IDL", idlPath)
+}
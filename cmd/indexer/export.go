@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/export"
+	"github.com/lugondev/go-indexer-solana-starter/internal/indexer"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+// runExport implements "indexer export": it streams stored events matching
+// the flags to stdout or a file as CSV or JSONL.
+func runExport(args []string) error {
+	fs := newFlagSet("export", "Stream stored events matching the filters to stdout or a file as CSV or JSONL.")
+	types := fs.String("type", "", "comma separated event types, e.g. CounterIncrementedEvent (default all)")
+	from := fs.String("from", "", "only events at or after this time (RFC 3339 or YYYY-MM-DD)")
+	to := fs.String("to", "", "only events before this time (RFC 3339 or YYYY-MM-DD)")
+	fromSlot := fs.Uint64("from-slot", 0, "only events at or after this slot")
+	toSlot := fs.Uint64("to-slot", 0, "only events at or before this slot")
+	account := fs.String("account", "", "only events referencing this account address")
+	format := fs.String("format", "jsonl", "output format: csv or jsonl")
+	output := fs.String("output", "-", "output file, - for stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := applyConfigFlags(fs); err != nil {
+		return err
+	}
+
+	filter, err := exportFilter(*types, *from, *to, *account)
+	if err != nil {
+		return err
+	}
+	filter.FromSlot, filter.ToSlot = *fromSlot, *toSlot
+	outFormat, err := export.ParseFormat(*format)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	filter.Network = cfg.Network
+	repo, err := indexer.NewRepository(cfg)
+	if err != nil {
+		return err
+	}
+	defer repo.Close(context.Background())
+
+	streamer, ok := repository.Unwrap(repo).(repository.EventStreamer)
+	if !ok {
+		return fmt.Errorf("%T does not support exports", repository.Unwrap(repo))
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("create output file: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+	buffered := bufio.NewWriter(out)
+
+	w, err := export.NewWriter(buffered, outFormat)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	n, err := export.Events(ctx, streamer, filter, w)
+	if flushErr := buffered.Flush(); err == nil {
+		err = flushErr
+	}
+	if err != nil {
+		return fmt.Errorf("export events: %w", err)
+	}
+	log.Printf("exported %d events", n)
+	return nil
+}
+
+func exportFilter(types, from, to, account string) (repository.EventFilter, error) {
+	var filter repository.EventFilter
+	for _, t := range strings.Split(types, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			filter.EventTypes = append(filter.EventTypes, models.EventType(t))
+		}
+	}
+
+	var err error
+	if filter.From, err = parseExportTime(from); err != nil {
+		return filter, fmt.Errorf("-from: %w", err)
+	}
+	if filter.To, err = parseExportTime(to); err != nil {
+		return filter, fmt.Errorf("-to: %w", err)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return filter, fmt.Errorf("-from must be before -to")
+	}
+
+	if account != "" {
+		key, err := solana.PublicKeyFromBase58(account)
+		if err != nil {
+			return filter, fmt.Errorf("-account: %w", err)
+		}
+		filter.Account = &key
+	}
+	return filter, nil
+}
+
+func parseExportTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("must be RFC 3339 or YYYY-MM-DD")
+	}
+	return t, nil
+}
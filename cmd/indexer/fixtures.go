@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana/mock"
+)
+
+// runFixtures implements "indexer fixtures record" and "indexer fixtures
+// serve": recording RPC responses of real transactions and serving them to
+// an indexer running offline.
+func runFixtures(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: indexer fixtures record|serve [flags]")
+	}
+	switch args[0] {
+	case "record":
+		return runFixturesRecord(args[1:])
+	case "serve":
+		return runFixturesServe(args[1:])
+	default:
+		return fmt.Errorf("unknown fixtures command %q, want record or serve", args[0])
+	}
+}
+
+func runFixturesRecord(args []string) error {
+	fs := newFlagSet("fixtures record", "Record the latest signatures of addresses and their transactions from\n"+
+		"SOLANA_RPC_URL, one fixture file per address named after it.")
+	addresses := fs.String("addresses", "", "comma separated addresses (default the starter and counter programs)")
+	limit := fs.Int("limit", 20, "signatures recorded per address")
+	dir := fs.String("dir", "testdata", "output directory")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := applyConfigFlags(fs); err != nil {
+		return err
+	}
+	_ = godotenv.Load()
+
+	rpcURL := os.Getenv("SOLANA_RPC_URL")
+	if rpcURL == "" {
+		return fmt.Errorf("SOLANA_RPC_URL is required")
+	}
+	var list []string
+	for _, a := range strings.Split(*addresses, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			list = append(list, a)
+		}
+	}
+	if len(list) == 0 {
+		list = []string{os.Getenv("STARTER_PROGRAM_ID"), os.Getenv("COUNTER_PROGRAM_ID")}
+	}
+	if err := os.MkdirAll(*dir, 0755); err != nil {
+		return fmt.Errorf("create output directory: %w", err)
+	}
+
+	recorder := mock.NewRecorder(rpcURL)
+	for _, address := range list {
+		if address == "" {
+			return fmt.Errorf("-addresses is required unless STARTER_PROGRAM_ID and COUNTER_PROGRAM_ID are set")
+		}
+		fixture, err := recorder.Record(context.Background(), address, *limit)
+		if err != nil {
+			return fmt.Errorf("record %s: %w", address, err)
+		}
+		path := filepath.Join(*dir, address+".json")
+		if err := fixture.WriteFile(path); err != nil {
+			return err
+		}
+		log.Printf("recorded %d transactions of %s to %s", len(fixture.Signatures), address, path)
+	}
+	return nil
+}
+
+func runFixturesServe(args []string) error {
+	fs := newFlagSet("fixtures serve", "Serve recorded fixtures as a JSON-RPC endpoint; point SOLANA_RPC_URL of\n"+
+		"the indexer at it to index them offline.")
+	dir := fs.String("dir", "testdata", "fixture directory")
+	listen := fs.String("listen", "127.0.0.1:8899", "listen address")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	fixtures, err := mock.LoadDir(*dir)
+	if err != nil {
+		return err
+	}
+	if len(fixtures) == 0 {
+		return fmt.Errorf("no fixtures in %s", *dir)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := &http.Server{Addr: *listen, Handler: mock.NewServer(fixtures...), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("serving %d fixtures on http://%s", len(fixtures), *listen)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serve fixtures: %w", err)
+	}
+	return nil
+}
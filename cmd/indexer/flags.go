@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// configFlags mirrors the most used environment variables as flags. A flag
+// that is set on the command line overrides the environment and .env file.
+var configFlags = []struct {
+	name  string
+	env   string
+	usage string
+}{
+	{"network", "NETWORK", "network of NETWORKS whose prefixed variables to use"},
+	{"rpc-url", "SOLANA_RPC_URL", "Solana RPC endpoint"},
+	{"ws-url", "SOLANA_WS_URL", "Solana websocket endpoint"},
+	{"starter-program-id", "STARTER_PROGRAM_ID", "starter program address"},
+	{"counter-program-id", "COUNTER_PROGRAM_ID", "counter program address"},
+	{"start-from", "START_FROM", "genesis, latest, slot or signature"},
+	{"start-slot", "START_SLOT", "slot to start indexing from"},
+	{"start-signature", "START_SIGNATURE", "signature to start indexing after"},
+	{"poll-interval-ms", "POLL_INTERVAL_MS", "poll interval in milliseconds"},
+	{"batch-size", "BATCH_SIZE", "signatures fetched per poll"},
+	{"max-concurrency", "MAX_CONCURRENCY", "maximum concurrent RPC requests"},
+	{"program-data-mode", "PROGRAM_DATA_MODE", "strict or lenient"},
+	{"database-type", "DATABASE_TYPE", "mongodb or postgres"},
+	{"database-url", "DATABASE_URL", "database connection URL"},
+	{"database-name", "DATABASE_NAME", "database name"},
+	{"mongo-collection-layout", "MONGO_COLLECTION_LAYOUT", "single, per_type or per_program"},
+	{"redis-url", "REDIS_URL", "Redis URL for caching and pub/sub"},
+	{"server-port", "SERVER_PORT", "API server port"},
+	{"log-level", "LOG_LEVEL", "log level"},
+}
+
+// newFlagSet returns a flag set for a command with the config flags
+// registered. Call applyConfigFlags after parsing it.
+func newFlagSet(name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	for _, f := range configFlags {
+		fs.String(f.name, "", fmt.Sprintf("%s (env %s)", f.usage, f.env))
+	}
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: indexer %s [flags]\n\n%s\n\nFlags:\n", name, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// applyConfigFlags exports the config flags set on the command line as
+// environment variables for config.Load.
+func applyConfigFlags(fs *flag.FlagSet) error {
+	envByFlag := make(map[string]string, len(configFlags))
+	for _, f := range configFlags {
+		envByFlag[f.name] = f.env
+	}
+
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		env, ok := envByFlag[f.Name]
+		if !ok || err != nil {
+			return
+		}
+		err = os.Setenv(env, strings.TrimSpace(f.Value.String()))
+	})
+	return err
+}
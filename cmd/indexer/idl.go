@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/codegen"
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	solanaClient "github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// runIDL implements "indexer idl fetch".
+func runIDL(args []string) error {
+	if len(args) == 0 || args[0] != "fetch" {
+		return fmt.Errorf("usage: indexer idl fetch [flags]")
+	}
+	return runIDLFetch(args[1:])
+}
+
+// runIDLFetch writes the Anchor IDL uploaded for a program, e.g. to run
+// codegen on without shipping the IDL file.
+func runIDLFetch(args []string) error {
+	fs := newFlagSet("idl fetch", "Fetch the Anchor IDL uploaded for a program from its on-chain IDL account.")
+	programID := fs.String("program", "", "program address (default STARTER_PROGRAM_ID)")
+	output := fs.String("output", "-", "output file, - for stdout")
+	timeout := fs.Duration("timeout", 30*time.Second, "time allowed for the RPC calls")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := applyConfigFlags(fs); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	if *programID == "" {
+		*programID = cfg.StarterProgramID
+	}
+	program, err := solana.PublicKeyFromBase58(*programID)
+	if err != nil {
+		return fmt.Errorf("-program: %w", err)
+	}
+	client, err := solanaClient.NewClient(cfg.SolanaRPCURL, cfg.SolanaWSURL)
+	if err != nil {
+		return fmt.Errorf("create solana client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	idl, err := codegen.FetchIDL(ctx, client, program)
+	if err != nil {
+		return err
+	}
+	return writeOutput(*output, func(w io.Writer) error {
+		_, err := w.Write(idl)
+		return err
+	})
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/loadgen"
+)
+
+// runLoadgen implements "indexer loadgen": it produces synthetic counter and
+// starter program traffic for capacity planning and soak tests, either
+// served from a built-in mock RPC or sent to a local validator.
+func runLoadgen(args []string) error {
+	fs := newFlagSet("loadgen", "Generate synthetic counter and starter program transactions at fixed rates.\n\n"+
+		"With -target mock, transactions are served from a mock JSON-RPC endpoint on -listen;\n"+
+		"point SOLANA_RPC_URL of the indexer under test at it. With -target validator, counter\n"+
+		"increments are sent to -rpc-url (e.g. solana-test-validator) for an initialized -counter.")
+	target := fs.String("target", "mock", "mock or validator")
+	listen := fs.String("listen", "127.0.0.1:8899", "mock RPC listen address")
+	counterRate := fs.Float64("counter-rate", 10, "counter transactions per second")
+	starterRate := fs.Float64("starter-rate", 10, "starter transactions per second (mock only)")
+	duration := fs.Duration("duration", 0, "stop generating after this long (default until interrupted)")
+	workers := fs.Int("workers", 8, "concurrent sends")
+	accounts := fs.Int("accounts", 16, "synthetic counters, payers and mints (mock only)")
+	retain := fs.Int("retain", 100000, "transactions the mock RPC keeps (mock only)")
+	keypair := fs.String("keypair", defaultKeypairPath(), "fee payer keypair file (validator only)")
+	counter := fs.String("counter", "", "initialized counter account to increment (validator only)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := applyConfigFlags(fs); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	counterProgram, err := solana.PublicKeyFromBase58(cfg.CounterProgramID)
+	if err != nil {
+		return fmt.Errorf("parse counter program ID: %w", err)
+	}
+	starterProgram, err := solana.PublicKeyFromBase58(cfg.StarterProgramID)
+	if err != nil {
+		return fmt.Errorf("parse starter program ID: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	opts := loadgen.Options{
+		CounterRate: *counterRate,
+		StarterRate: *starterRate,
+		Duration:    *duration,
+		Workers:     *workers,
+	}
+
+	switch *target {
+	case "mock":
+		generator, err := loadgen.NewGenerator(counterProgram, starterProgram, *accounts, time.Now().UnixNano())
+		if err != nil {
+			return err
+		}
+		mock := loadgen.NewMockRPC(loadgen.Clock{Start: time.Now(), StartSlot: cfg.StartSlot}, *retain)
+		server := &http.Server{Addr: *listen, Handler: mock, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("mock rpc: %v", err)
+				stop()
+			}
+		}()
+		log.Printf("mock rpc listening on http://%s", *listen)
+
+		logStats(loadgen.Run(ctx, loadgen.NewMockTarget(generator, mock), opts))
+
+		// Keep serving so the indexer under test can drain the ledger.
+		if ctx.Err() == nil {
+			log.Printf("generation finished; serving the mock rpc until interrupted")
+			<-ctx.Done()
+		}
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+
+	case "validator":
+		if *starterRate > 0 {
+			log.Printf("warning: starter load is only generated with -target mock; ignoring -starter-rate")
+			opts.StarterRate = 0
+		}
+		if *counter == "" {
+			return fmt.Errorf("-counter is required with -target validator")
+		}
+		counterAccount, err := solana.PublicKeyFromBase58(*counter)
+		if err != nil {
+			return fmt.Errorf("parse -counter: %w", err)
+		}
+		payer, err := solana.PrivateKeyFromSolanaKeygenFile(*keypair)
+		if err != nil {
+			return fmt.Errorf("load keypair: %w", err)
+		}
+
+		log.Printf("sending counter increments to %s", cfg.SolanaRPCURL)
+		logStats(loadgen.Run(ctx, loadgen.NewValidatorTarget(cfg.SolanaRPCURL, payer, counterProgram, counterAccount), opts))
+		return nil
+
+	default:
+		return fmt.Errorf("unknown target %q, want mock or validator", *target)
+	}
+}
+
+func logStats(stats loadgen.Stats) {
+	for _, program := range []loadgen.Program{loadgen.ProgramCounter, loadgen.ProgramStarter} {
+		sent := stats.Sent[program]
+		if sent == 0 && stats.Failed[program] == 0 && stats.Skipped[program] == 0 {
+			continue
+		}
+		log.Printf("%s: sent %d (%.1f tx/s), failed %d, skipped %d in %s", program, sent,
+			float64(sent)/stats.Elapsed.Seconds(), stats.Failed[program], stats.Skipped[program], stats.Elapsed.Round(time.Second))
+	}
+}
+
+func defaultKeypairPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "solana", "id.json")
+}
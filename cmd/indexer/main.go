@@ -1,60 +1,75 @@
 package main
 
 import (
-	"context"
 	"fmt"
 	"log"
 	"os"
-	"os/signal"
-	"syscall"
+	"strings"
 
-	"github.com/lugondev/go-indexer-solana-starter/internal/config"
-	"github.com/lugondev/go-indexer-solana-starter/internal/indexer"
+	"github.com/lugondev/go-indexer-solana-starter/internal/version"
 )
 
-func main() {
-	// Load configuration
-	cfg, err := config.Load()
-	if err != nil {
-		log.Fatalf("failed to load config: %v", err)
-	}
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands = []command{
+	{"run", "run the indexer and API server (default)", runIndexer},
+	{"backfill", "index historical transactions in a slot range", runBackfill},
+	{"reindex", "delete and re-index the events of a slot range", runReindex},
+	{"export", "export events as CSV or JSONL", runExport},
+	{"resink", "publish stored events to a newly added sink", runResink},
+	{"query", "query indexed events and token holders", runQuery},
+	{"snapshot", "write indexed accounts as validator account files", runSnapshot},
+	{"state", "back up or restore the indexer state", runState},
+	{"migrate", "apply database migrations and indexes", runMigrate},
+	{"loadgen", "generate synthetic program traffic for load tests", runLoadgen},
+	{"fixtures", "record RPC fixtures or serve them offline", runFixtures},
+	{"config", "export, import or validate a configuration manifest", runConfig},
+	{"doctor", "check the config, RPC, database and programs before a deploy", runDoctor},
+	{"codegen", "generate Go bindings from an Anchor IDL", runCodegen},
+	{"idl", "fetch a program's Anchor IDL from chain", runIDL},
+	{"version", "print the version", runVersion},
+}
 
-	// Create context with cancellation
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+func main() {
+	args := os.Args[1:]
 
-	// Initialize indexer
-	idx, err := indexer.New(cfg)
-	if err != nil {
-		log.Fatalf("failed to create indexer: %v", err)
+	// Without a command, or with only flags, run the daemon as before.
+	name := "run"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
 	}
 
-	// Start indexer in goroutine
-	errChan := make(chan error, 1)
-	go func() {
-		if err := idx.Start(ctx); err != nil {
-			errChan <- fmt.Errorf("indexer error: %w", err)
+	if name == "help" {
+		usage()
+		return
+	}
+	for _, cmd := range commands {
+		if cmd.name == name {
+			if err := cmd.run(args); err != nil {
+				log.Fatalf("%s: %v", name, err)
+			}
+			return
 		}
-	}()
-
-	// Setup signal handling
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-
-	// Wait for shutdown signal or error
-	select {
-	case err := <-errChan:
-		log.Printf("indexer failed: %v", err)
-		cancel()
-	case sig := <-sigChan:
-		log.Printf("received signal %v, shutting down gracefully...", sig)
-		cancel()
 	}
 
-	// Wait for cleanup
-	if err := idx.Shutdown(context.Background()); err != nil {
-		log.Printf("error during shutdown: %v", err)
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: indexer <command> [flags]\n\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.summary)
 	}
+	fmt.Fprintf(os.Stderr, "\nRun \"indexer <command> -h\" for the flags of a command. Every flag\nthat mirrors an environment variable overrides it.\n")
+}
 
-	log.Println("indexer stopped successfully")
+func runVersion(args []string) error {
+	fmt.Println(version.Get())
+	return nil
 }
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/indexer"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+// runMigrate implements "indexer migrate": prepare the database schema
+// without starting the indexer. For MongoDB this creates the indexes; for
+// PostgreSQL it applies the pending versioned migrations.
+func runMigrate(args []string) error {
+	fs := newFlagSet("migrate", "Bring the database schema up to date and exit.")
+	status := fs.Bool("status", false, "list PostgreSQL migrations and whether they are applied, without applying any")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := applyConfigFlags(fs); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	repo, err := indexer.NewRepository(cfg)
+	if err != nil {
+		return err
+	}
+	defer repo.Close(context.Background())
+
+	ctx := context.Background()
+	switch r := repository.Unwrap(repo).(type) {
+	case *repository.MongoRepository:
+		if *status {
+			return fmt.Errorf("-status is only supported for postgres")
+		}
+		if err := r.CreateIndexes(ctx); err != nil {
+			return err
+		}
+	case *repository.PostgresRepository:
+		if *status {
+			statuses, err := r.MigrationStatus(ctx)
+			if err != nil {
+				return err
+			}
+			for _, s := range statuses {
+				state := "pending"
+				if s.Applied {
+					state = "applied"
+				}
+				fmt.Printf("%04d_%s\t%s\n", s.Version, s.Name, state)
+			}
+			return nil
+		}
+		applied, err := r.Migrate(ctx)
+		if err != nil {
+			return err
+		}
+		for _, m := range applied {
+			log.Printf("applied migration %04d_%s", m.Version, m.Name)
+		}
+	default:
+		return fmt.Errorf("migrations are not supported for %s", cfg.DatabaseType)
+	}
+	log.Println("database is up to date")
+	return nil
+}
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/indexer"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/output"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+const maxQueryLimit = 500
+
+// queryCommands are the "indexer query" subcommands. Each reads one page of
+// results from the configured database.
+var queryCommands = []struct {
+	name string
+	run  func(args []string) error
+}{
+	{"events", runQueryEvents},
+	{"event", runQueryEvent},
+	{"account", runQueryAccount},
+	{"holders", runQueryHolders},
+}
+
+// eventColumns are the table columns of event lists without -columns.
+var eventColumns = []string{"slot", "signature", "event_type", "block_time"}
+
+// runQuery implements "indexer query": inspecting indexed data from a
+// terminal as a table, JSON or YAML.
+func runQuery(args []string) error {
+	var names []string
+	for _, cmd := range queryCommands {
+		names = append(names, cmd.name)
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("usage: indexer query %s [flags]", strings.Join(names, "|"))
+	}
+	for _, cmd := range queryCommands {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:])
+		}
+	}
+	return fmt.Errorf("unknown query command %q, want one of %s", args[0], strings.Join(names, ", "))
+}
+
+// queryFlags are the output and paging flags shared by the query commands.
+type queryFlags struct {
+	// tableColumns are shown in tables without -columns; nil shows all fields.
+	tableColumns []string
+
+	fs      *flag.FlagSet
+	tenant  *string
+	output  *string
+	columns *string
+	limit   *int
+	cursor  *string
+	order   *string
+}
+
+func newQueryFlags(name, usage string, tableColumns []string, paged bool) *queryFlags {
+	q := &queryFlags{tableColumns: tableColumns, fs: newFlagSet("query "+name, usage)}
+	q.tenant = q.fs.String("tenant", "", "only read the events of this tenant")
+	q.output = q.fs.String("output", "table", "output format: table, json or yaml")
+	q.columns = q.fs.String("columns", "", "comma separated fields to show (default a summary for tables, everything otherwise)")
+	if paged {
+		q.limit = q.fs.Int("limit", 50, fmt.Sprintf("results per page, at most %d", maxQueryLimit))
+		q.cursor = q.fs.String("cursor", "", "continue from the cursor printed after a previous page")
+	}
+	return q
+}
+
+// withOrder adds the -order flag of the event lists.
+func (q *queryFlags) withOrder() *queryFlags {
+	q.order = q.fs.String("order", "desc", "asc for oldest first, desc for newest first")
+	return q
+}
+
+func (q *queryFlags) parse(args []string) error {
+	if err := q.fs.Parse(args); err != nil {
+		return err
+	}
+	if q.fs.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q; all query parameters are flags", q.fs.Arg(0))
+	}
+	if q.limit != nil && (*q.limit <= 0 || *q.limit > maxQueryLimit) {
+		return fmt.Errorf("-limit must be between 1 and %d", maxQueryLimit)
+	}
+	if q.order != nil && *q.order != "asc" && *q.order != "desc" {
+		return fmt.Errorf("-order must be asc or desc")
+	}
+	return applyConfigFlags(q.fs)
+}
+
+func (q *queryFlags) page() (repository.PageOptions, error) {
+	page := repository.PageOptions{Limit: *q.limit, Ascending: *q.order == "asc"}
+	if *q.cursor != "" {
+		cursor, err := repository.ParseCursor(*q.cursor)
+		if err != nil {
+			return page, fmt.Errorf("-cursor: %w", err)
+		}
+		page.After = cursor
+	}
+	return page, nil
+}
+
+// run opens the repository, fetches the results with fetch and writes them
+// to stdout. fetch returns a slice of results and the cursor of the next
+// page, if any, which is printed to stderr to keep stdout parseable.
+func (q *queryFlags) run(fetch func(ctx context.Context, repo repository.Repository) (interface{}, string, error)) error {
+	format, err := output.ParseFormat(*q.output)
+	if err != nil {
+		return err
+	}
+	var columns []string
+	for _, c := range strings.Split(*q.columns, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			columns = append(columns, c)
+		}
+	}
+	if len(columns) == 0 && format == output.FormatTable {
+		columns = q.tableColumns
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	repo, err := indexer.NewRepository(cfg)
+	if err != nil {
+		return err
+	}
+	defer repo.Close(context.Background())
+
+	ctx := context.Background()
+	if *q.tenant != "" {
+		ctx = repository.WithTenant(ctx, *q.tenant)
+	}
+	results, next, err := fetch(ctx, repo)
+	if err != nil {
+		return err
+	}
+	if err := decryptEvents(cfg, results); err != nil {
+		return err
+	}
+	records, err := output.Records(results)
+	if err != nil {
+		return err
+	}
+	if err := output.Write(os.Stdout, format, records, columns); err != nil {
+		return err
+	}
+	if next != "" {
+		fmt.Fprintf(os.Stderr, "more results: -cursor %s\n", next)
+	}
+	return nil
+}
+
+// decryptEvents decrypts the ENCRYPTED_FIELDS of the events of results.
+func decryptEvents(cfg *config.Config, results interface{}) error {
+	events, ok := results.([]interface{})
+	if !ok {
+		return nil
+	}
+	fieldCipher, err := cfg.FieldCipher()
+	if err != nil || fieldCipher == nil {
+		return err
+	}
+	for _, event := range events {
+		if err := fieldCipher.Decrypt(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runQueryEvents(args []string) error {
+	q := newQueryFlags("events", "List stored events of one type, newest first.", eventColumns, true).withOrder()
+	eventType := q.fs.String("type", "", "event type, e.g. CounterIncrementedEvent (required)")
+	if err := q.parse(args); err != nil {
+		return err
+	}
+	if *eventType == "" {
+		return fmt.Errorf("-type is required")
+	}
+	if !models.EventType(*eventType).Known() {
+		return fmt.Errorf("-type: unknown event type %q", *eventType)
+	}
+	page, err := q.page()
+	if err != nil {
+		return err
+	}
+
+	return q.run(func(ctx context.Context, repo repository.Repository) (interface{}, string, error) {
+		result, err := repo.GetEventsByType(ctx, models.EventType(*eventType), page)
+		if err != nil {
+			return nil, "", fmt.Errorf("query events: %w", err)
+		}
+		return eventsOrEmpty(result.Events), eventCursor(result), nil
+	})
+}
+
+func runQueryEvent(args []string) error {
+	q := newQueryFlags("event", "Show the stored event of a transaction.", nil, false)
+	signature := q.fs.String("signature", "", "transaction signature (required)")
+	if err := q.parse(args); err != nil {
+		return err
+	}
+	if _, err := solana.SignatureFromBase58(*signature); err != nil {
+		return fmt.Errorf("-signature must be a base58 transaction signature")
+	}
+
+	return q.run(func(ctx context.Context, repo repository.Repository) (interface{}, string, error) {
+		event, err := repo.GetEventBySignature(ctx, *signature)
+		if err != nil {
+			return nil, "", fmt.Errorf("query event: %w", err)
+		}
+		if event == nil {
+			return nil, "", fmt.Errorf("no event stored for %s", *signature)
+		}
+		return []interface{}{event}, "", nil
+	})
+}
+
+func runQueryAccount(args []string) error {
+	q := newQueryFlags("account", "List the stored events referencing an account in any role, newest first.", eventColumns, true).withOrder()
+	account := q.fs.String("account", "", "account address (required)")
+	types := q.fs.String("type", "", "comma separated event types (default all)")
+	if err := q.parse(args); err != nil {
+		return err
+	}
+	key, err := solana.PublicKeyFromBase58(*account)
+	if err != nil {
+		return fmt.Errorf("-account must be a base58 public key")
+	}
+	page, err := q.page()
+	if err != nil {
+		return err
+	}
+	opts := repository.AccountEventsOptions{PageOptions: page}
+	for _, t := range strings.Split(*types, ",") {
+		if t = strings.TrimSpace(t); t == "" {
+			continue
+		}
+		if !models.EventType(t).Known() {
+			return fmt.Errorf("-type: unknown event type %q", t)
+		}
+		opts.EventTypes = append(opts.EventTypes, models.EventType(t))
+	}
+
+	return q.run(func(ctx context.Context, repo repository.Repository) (interface{}, string, error) {
+		result, err := repo.GetEventsByAccount(ctx, key, opts)
+		if err != nil {
+			return nil, "", fmt.Errorf("query account events: %w", err)
+		}
+		return eventsOrEmpty(result.Events), eventCursor(result), nil
+	})
+}
+
+func runQueryHolders(args []string) error {
+	q := newQueryFlags("holders", "List the holders of a token mint, largest balance first.", []string{"owner", "balance", "slot"}, true)
+	mint := q.fs.String("mint", "", "token mint address (required)")
+	if err := q.parse(args); err != nil {
+		return err
+	}
+	if _, err := solana.PublicKeyFromBase58(*mint); err != nil {
+		return fmt.Errorf("-mint must be a base58 public key")
+	}
+	opts := repository.HolderPageOptions{Limit: *q.limit}
+	if *q.cursor != "" {
+		cursor, err := repository.ParseHolderCursor(*q.cursor)
+		if err != nil {
+			return fmt.Errorf("-cursor: %w", err)
+		}
+		opts.After = cursor
+	}
+
+	return q.run(func(ctx context.Context, repo repository.Repository) (interface{}, string, error) {
+		store, ok := repository.Unwrap(repo).(repository.TokenHolderStore)
+		if !ok {
+			return nil, "", fmt.Errorf("%T does not keep token holders", repository.Unwrap(repo))
+		}
+		page, err := store.GetTokenHolders(ctx, *mint, opts)
+		if err != nil {
+			return nil, "", fmt.Errorf("query token holders: %w", err)
+		}
+		next := ""
+		if page.Next != nil {
+			next = page.Next.String()
+		}
+		if page.Holders == nil {
+			return []models.TokenHolder{}, next, nil
+		}
+		return page.Holders, next, nil
+	})
+}
+
+func eventsOrEmpty(events []interface{}) []interface{} {
+	if events == nil {
+		return []interface{}{}
+	}
+	return events
+}
+
+func eventCursor(page *repository.EventPage) string {
+	if page.Next == nil {
+		return ""
+	}
+	return page.Next.String()
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/indexer"
+)
+
+// runResink implements "indexer resink": it publishes already stored events
+// to a sink, e.g. one added after the events were indexed.
+func runResink(args []string) error {
+	fs := newFlagSet("resink", "Publish stored events to a sink, oldest first, without fetching them from RPC again.")
+	sinkName := fs.String("sink", "", "sink to publish to: kafka, webhook, stdout, mongodb or postgres (required)")
+	fromSlot := fs.Uint64("from-slot", 0, "first slot to publish, inclusive (default the oldest event)")
+	toSlot := fs.Uint64("to-slot", 0, "last slot to publish, inclusive (default the newest event)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := applyConfigFlags(fs); err != nil {
+		return err
+	}
+	if *sinkName == "" {
+		return fmt.Errorf("-sink is required")
+	}
+	if *toSlot > 0 && *fromSlot > *toSlot {
+		return fmt.Errorf("-from-slot must not be after -to-slot")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	if cfg.Network == "" && len(cfg.Networks) > 0 {
+		return fmt.Errorf("NETWORKS is set: choose the network with -network")
+	}
+	repo, err := indexer.NewRepository(cfg)
+	if err != nil {
+		return err
+	}
+	defer repo.Close(context.Background())
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	n, err := indexer.Resink(ctx, cfg, repo, indexer.ResinkOptions{Sink: *sinkName, FromSlot: *fromSlot, ToSlot: *toSlot})
+	if err != nil {
+		return fmt.Errorf("resink after %d events: %w", n, err)
+	}
+	log.Printf("published %d events to the %s sink", n, *sinkName)
+	return nil
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/api"
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/indexer"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+	"github.com/lugondev/go-indexer-solana-starter/internal/version"
+)
+
+// runIndexer implements "indexer run": the indexer daemon and API server.
+func runIndexer(args []string) error {
+	fs := newFlagSet("run", "Run the indexer and the API server until interrupted.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := applyConfigFlags(fs); err != nil {
+		return err
+	}
+
+	log.Printf("solana indexer %s", version.Get())
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	// With NETWORKS set and no NETWORK chosen, every network is indexed at
+	// once; the API server runs with the settings of the first and serves
+	// the others by their network parameter.
+	configs := []*config.Config{cfg}
+	if cfg.Network == "" && len(cfg.Networks) > 0 {
+		configs = configs[:0]
+		for _, network := range cfg.Networks {
+			c, err := config.LoadNetwork(network)
+			if err != nil {
+				return fmt.Errorf("load config of network %s: %w", network, err)
+			}
+			configs = append(configs, c)
+		}
+		cfg = configs[0]
+	}
+
+	users, err := api.ParseUsers(cfg.APIUsers)
+	if err != nil {
+		return fmt.Errorf("parse API_USERS: %w", err)
+	}
+	for _, user := range users {
+		if user.Tenant != "" && !slices.Contains(slices.Collect(maps.Values(cfg.ProgramTenants)), user.Tenant) {
+			return fmt.Errorf("API_USERS: user %s belongs to tenant %q, which owns no program in PROGRAM_TENANTS", user.Name, user.Tenant)
+		}
+	}
+	v1Deprecation, err := api.ParseDeprecation(cfg.APIV1DeprecatedSince, cfg.APIV1Sunset)
+	if err != nil {
+		return fmt.Errorf("parse API_V1_DEPRECATED_SINCE and API_V1_SUNSET: %w", err)
+	}
+
+	// Create context with cancellation
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize indexers. Networks on the same database share one
+	// repository, so they number their events without handing out a
+	// sequence twice; it is closed once every indexer has stopped.
+	repos := make(map[string]repository.Repository)
+	defer func() {
+		for _, repo := range repos {
+			if err := repo.Close(context.Background()); err != nil {
+				log.Printf("error closing repository: %v", err)
+			}
+		}
+	}()
+	indexers := make([]*indexer.Indexer, 0, len(configs))
+	for _, c := range configs {
+		key := fmt.Sprintf("%s %s %s", c.DatabaseType, c.DatabaseURL, c.DatabaseName)
+		repo, ok := repos[key]
+		if !ok {
+			repo, err = indexer.NewRepository(c)
+			if err != nil {
+				return err
+			}
+			repos[key] = repo
+		}
+		idx, err := indexer.NewWithRepository(c, repo)
+		if err != nil {
+			if c.Network != "" {
+				return fmt.Errorf("create indexer of network %s: %w", c.Network, err)
+			}
+			return fmt.Errorf("create indexer: %w", err)
+		}
+		indexers = append(indexers, idx)
+	}
+
+	// Initialize API server
+	var networks []*api.Server
+	for n, idx := range indexers[1:] {
+		opts, err := serverOptions(configs[n+1], idx)
+		if err != nil {
+			return err
+		}
+		networks = append(networks, api.NewServer(cfg.ServerPort, idx.Repository(), idx, opts))
+	}
+	opts, err := serverOptions(cfg, indexers[0])
+	if err != nil {
+		return err
+	}
+	opts.RateLimitPerMinute = cfg.APIRateLimitPerMinute
+	opts.Users = users
+	opts.V1Deprecation = v1Deprecation
+	opts.Networks = networks
+	server := api.NewServer(cfg.ServerPort, indexers[0].Repository(), indexers[0], opts)
+
+	// Start indexers and API server in goroutines
+	errChan := make(chan error, len(indexers)+1)
+	for _, idx := range indexers {
+		go func() {
+			if err := idx.Start(ctx); err != nil {
+				errChan <- fmt.Errorf("indexer error: %w", err)
+			}
+		}()
+	}
+	go func() {
+		if err := server.Start(); err != nil {
+			errChan <- err
+		}
+	}()
+
+	// Setup signal handling; SIGHUP reloads the configuration
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+
+	// Wait for shutdown signal or error
+wait:
+	for {
+		select {
+		case err := <-errChan:
+			log.Printf("indexer failed: %v", err)
+			cancel()
+			break wait
+		case sig := <-sigChan:
+			if sig == syscall.SIGHUP {
+				for _, idx := range indexers {
+					if _, err := idx.ReloadConfig(ctx); err != nil {
+						log.Printf("error reloading configuration: %v", err)
+					}
+				}
+				continue
+			}
+			log.Printf("received signal %v, shutting down gracefully...", sig)
+			cancel()
+			break wait
+		}
+	}
+
+	// Wait for cleanup
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer shutdownCancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("error shutting down api server: %v", err)
+	}
+
+	for _, idx := range indexers {
+		if err := idx.Shutdown(context.Background()); err != nil {
+			log.Printf("error during shutdown: %v", err)
+		}
+	}
+
+	log.Println("indexer stopped successfully")
+	return nil
+}
+
+// serverOptions returns the API options of the network idx indexes with
+// cfg, without those shared by every network.
+func serverOptions(cfg *config.Config, idx *indexer.Indexer) (api.Options, error) {
+	fieldCipher, err := cfg.FieldCipher()
+	if err != nil {
+		return api.Options{}, err
+	}
+	return api.Options{
+		CounterMinFeeLamports: cfg.CounterMinFeeLamports,
+		ConsumerLag:           idx,
+		Windows:               idx,
+		RPC:                   idx,
+		Seen:                  idx,
+		AccountAlerts:         idx,
+		Watchdog:              idx,
+		Buffer:                idx,
+		Pipeline:              idx,
+		Watermarks:            idx,
+		Coverage:              idx,
+		Errors:                idx,
+		Deployments:           idx,
+		Reloader:              idx,
+		Decoder:               idx,
+		Watchlist:             idx,
+		Backfiller:            idx,
+		Pauser:                idx,
+		Reindexer:             idx,
+		Cipher:                fieldCipher,
+		Network:               cfg.Network,
+	}, nil
+}
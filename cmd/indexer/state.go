@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/backup"
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/indexer"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+// runState implements "indexer state export" and "indexer state import",
+// which back up the indexer state (events, checkpoints and projections)
+// and restore it, e.g. to clone production into staging.
+func runState(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: indexer state export|import [flags]")
+	}
+	switch args[0] {
+	case "export":
+		return runStateExport(args[1:])
+	case "import":
+		return runStateImport(args[1:])
+	default:
+		return fmt.Errorf("unknown state command %q, want export or import", args[0])
+	}
+}
+
+func runStateExport(args []string) error {
+	fs := newFlagSet("state export", "Write the events, checkpoints and projections to a backup file.")
+	output := fs.String("output", "-", "output file, - for stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := applyConfigFlags(fs); err != nil {
+		return err
+	}
+
+	cfg, store, closeRepo, err := openStateStore()
+	if err != nil {
+		return err
+	}
+	defer closeRepo()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var counts backup.Counts
+	err = writeOutput(*output, func(w io.Writer) error {
+		counts, err = backup.Write(ctx, w, store, string(cfg.DatabaseType), time.Now())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("exported %d documents of %d collections", counts.Total(), len(counts))
+	return nil
+}
+
+func runStateImport(args []string) error {
+	fs := newFlagSet("state import", "Restore the events, checkpoints and projections from a backup file. Stop the indexer first.")
+	input := fs.String("input", "", "backup file written by state export (required)")
+	replace := fs.Bool("replace", false, "delete the current state first; without it the database must be empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := applyConfigFlags(fs); err != nil {
+		return err
+	}
+	if *input == "" {
+		return fmt.Errorf("-input is required")
+	}
+
+	cfg, store, closeRepo, err := openStateStore()
+	if err != nil {
+		return err
+	}
+	defer closeRepo()
+
+	// Check the whole backup before touching the database.
+	f, err := os.Open(*input)
+	if err != nil {
+		return fmt.Errorf("open backup: %w", err)
+	}
+	defer f.Close()
+	header, _, err := backup.Verify(f, string(cfg.DatabaseType))
+	if err != nil {
+		return fmt.Errorf("verify %s: %w", *input, err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("rewind backup: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	counts, err := backup.Restore(ctx, f, store, string(cfg.DatabaseType), *replace)
+	if err != nil {
+		return fmt.Errorf("restore %s (%d documents restored): %w", *input, counts.Total(), err)
+	}
+	log.Printf("restored %d documents of %d collections from the backup of %s",
+		counts.Total(), len(counts), header.CreatedAt.Format(time.RFC3339))
+	return nil
+}
+
+func openStateStore() (*config.Config, repository.StateStore, func(), error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("load config: %w", err)
+	}
+	repo, err := indexer.NewRepository(cfg)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	closeRepo := func() { repo.Close(context.Background()) }
+	store, ok := repository.Unwrap(repo).(repository.StateStore)
+	if !ok {
+		closeRepo()
+		return nil, nil, nil, fmt.Errorf("%T does not support state backups", repository.Unwrap(repo))
+	}
+	return cfg, store, closeRepo, nil
+}
@@ -0,0 +1,245 @@
+// Package accountmon samples the size and balance of the accounts owned by
+// the indexed programs, keeps their history and alerts on accounts that are
+// short of rent or close to the size limit.
+package accountmon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+	solanaClient "github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+const (
+	// MaxAccountSize is the largest an account can grow to
+	// (MAX_PERMITTED_DATA_LENGTH).
+	MaxAccountSize = 10 * 1024 * 1024
+	// MaxReallocIncrease is the most one instruction can grow an account by
+	// (MAX_PERMITTED_DATA_INCREASE).
+	MaxReallocIncrease = 10 * 1024
+)
+
+type AlertKind string
+
+const (
+	// AlertRent is raised for accounts holding less than their rent-exempt
+	// minimum.
+	AlertRent AlertKind = "rent"
+	// AlertSize is raised for accounts close to MaxAccountSize.
+	AlertSize AlertKind = "size"
+)
+
+type Alert struct {
+	Kind    AlertKind            `json:"kind"`
+	Message string               `json:"message"`
+	Sample  models.AccountSample `json:"sample"`
+}
+
+type Options struct {
+	Interval time.Duration
+	// SizeWarnRatio raises a size alert for accounts larger than this share
+	// of MaxAccountSize.
+	SizeWarnRatio float64
+}
+
+// Source reads program accounts; *solanaClient.Client implements it.
+type Source interface {
+	GetProgramAccounts(ctx context.Context, program solana.PublicKey) ([]solanaClient.ProgramAccount, error)
+	GetRentExemptMinimum(ctx context.Context, size uint64) (uint64, error)
+}
+
+// Monitor samples the accounts of programs every interval. Samples are kept
+// in store when it is not nil; the alerts of the last sampling are kept in
+// memory.
+type Monitor struct {
+	source   Source
+	store    repository.AccountSampleStore
+	programs []solana.PublicKey
+	opts     Options
+	now      func() time.Time
+
+	// rent caches the rent-exempt minimum by account size. Only SampleOnce
+	// uses it.
+	rent map[uint64]uint64
+
+	mu     sync.RWMutex
+	alerts []Alert
+}
+
+func NewMonitor(source Source, store repository.AccountSampleStore, programs []solana.PublicKey, opts Options) *Monitor {
+	if opts.Interval <= 0 {
+		opts.Interval = 5 * time.Minute
+	}
+	if opts.SizeWarnRatio <= 0 || opts.SizeWarnRatio > 1 {
+		opts.SizeWarnRatio = 0.9
+	}
+	return &Monitor{
+		source:   source,
+		store:    store,
+		programs: programs,
+		opts:     opts,
+		now:      time.Now,
+		rent:     make(map[uint64]uint64),
+	}
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.SampleOnce(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("error sampling program accounts: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SampleOnce samples every account of the programs, stores the samples and
+// replaces the current alerts. New alerts are logged.
+func (m *Monitor) SampleOnce(ctx context.Context) error {
+	sampledAt := m.now().UTC()
+	var samples []models.AccountSample
+	for _, program := range m.programs {
+		accounts, err := m.source.GetProgramAccounts(ctx, program)
+		if err != nil {
+			return fmt.Errorf("list accounts of %s: %w", program, err)
+		}
+		for _, a := range accounts {
+			minimum, err := m.rentExemptMinimum(ctx, a.Size)
+			if err != nil {
+				return err
+			}
+			samples = append(samples, models.AccountSample{
+				Address:           a.Address.String(),
+				Program:           program.String(),
+				Size:              a.Size,
+				Lamports:          a.Lamports,
+				RentExemptMinimum: minimum,
+				SampledAt:         sampledAt,
+			})
+		}
+	}
+
+	var alerts []Alert
+	for _, s := range samples {
+		alerts = append(alerts, Check(s, m.opts.SizeWarnRatio)...)
+	}
+
+	m.mu.Lock()
+	previous := m.alerts
+	m.alerts = alerts
+	m.mu.Unlock()
+
+	for _, a := range alerts {
+		known := slices.ContainsFunc(previous, func(p Alert) bool {
+			return p.Kind == a.Kind && p.Sample.Address == a.Sample.Address
+		})
+		if !known {
+			log.Printf("warning: account %s: %s", a.Sample.Address, a.Message)
+		}
+	}
+
+	if m.store != nil {
+		if err := m.store.SaveAccountSamples(ctx, samples); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Monitor) rentExemptMinimum(ctx context.Context, size uint64) (uint64, error) {
+	if lamports, ok := m.rent[size]; ok {
+		return lamports, nil
+	}
+	lamports, err := m.source.GetRentExemptMinimum(ctx, size)
+	if err != nil {
+		return 0, err
+	}
+	m.rent[size] = lamports
+	return lamports, nil
+}
+
+// Alerts returns the alerts of the last sampling; it is never nil.
+func (m *Monitor) Alerts() []Alert {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	alerts := make([]Alert, len(m.alerts))
+	copy(alerts, m.alerts)
+	return alerts
+}
+
+// Check returns the alerts for one sample.
+func Check(s models.AccountSample, sizeWarnRatio float64) []Alert {
+	var alerts []Alert
+	if s.Lamports < s.RentExemptMinimum {
+		alerts = append(alerts, Alert{
+			Kind: AlertRent,
+			Message: fmt.Sprintf("holds %d lamports, %d short of the rent-exempt minimum of %d for %d bytes",
+				s.Lamports, s.RentExemptMinimum-s.Lamports, s.RentExemptMinimum, s.Size),
+			Sample: s,
+		})
+	}
+	if float64(s.Size) >= sizeWarnRatio*MaxAccountSize {
+		headroom := uint64(0)
+		if s.Size < MaxAccountSize {
+			headroom = MaxAccountSize - s.Size
+		}
+		alerts = append(alerts, Alert{
+			Kind: AlertSize,
+			Message: fmt.Sprintf("is %d bytes, %.1f%% of the %d byte limit; %d bytes or %d reallocs of %d bytes left",
+				s.Size, 100*float64(s.Size)/MaxAccountSize, MaxAccountSize,
+				headroom, (headroom+MaxReallocIncrease-1)/MaxReallocIncrease, MaxReallocIncrease),
+			Sample: s,
+		})
+	}
+	return alerts
+}
+
+// Trend is the change of an account over a series of samples.
+type Trend struct {
+	From           time.Time `json:"from"`
+	To             time.Time `json:"to"`
+	SizeChange     int64     `json:"size_change"`
+	LamportsChange int64     `json:"lamports_change"`
+	// BytesPerDay is the average growth rate.
+	BytesPerDay float64 `json:"bytes_per_day"`
+	// DaysToLimit is when the account reaches MaxAccountSize at that rate;
+	// nil when it is not growing.
+	DaysToLimit *float64 `json:"days_to_limit,omitempty"`
+}
+
+// TrendOf summarizes samples ordered oldest first; it returns nil for fewer
+// than two samples.
+func TrendOf(samples []models.AccountSample) *Trend {
+	if len(samples) < 2 {
+		return nil
+	}
+	first, last := samples[0], samples[len(samples)-1]
+	t := &Trend{
+		From:           first.SampledAt,
+		To:             last.SampledAt,
+		SizeChange:     int64(last.Size) - int64(first.Size),
+		LamportsChange: int64(last.Lamports) - int64(first.Lamports),
+	}
+	if days := last.SampledAt.Sub(first.SampledAt).Hours() / 24; days > 0 {
+		t.BytesPerDay = float64(t.SizeChange) / days
+	}
+	if t.BytesPerDay > 0 && last.Size < MaxAccountSize {
+		days := float64(MaxAccountSize-last.Size) / t.BytesPerDay
+		t.DaysToLimit = &days
+	}
+	return t
+}
@@ -0,0 +1,141 @@
+package accountmon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	solanaClient "github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+type fakeSource struct {
+	accounts  map[solana.PublicKey][]solanaClient.ProgramAccount
+	rentCalls int
+}
+
+func (s *fakeSource) GetProgramAccounts(ctx context.Context, program solana.PublicKey) ([]solanaClient.ProgramAccount, error) {
+	return s.accounts[program], nil
+}
+
+func (s *fakeSource) GetRentExemptMinimum(ctx context.Context, size uint64) (uint64, error) {
+	s.rentCalls++
+	return (size + 128) * 6960, nil
+}
+
+type fakeStore struct {
+	samples []models.AccountSample
+}
+
+func (s *fakeStore) SaveAccountSamples(ctx context.Context, samples []models.AccountSample) error {
+	s.samples = append(s.samples, samples...)
+	return nil
+}
+
+func (s *fakeStore) GetAccountSamples(ctx context.Context, address string, from, to time.Time, limit int) ([]models.AccountSample, error) {
+	return nil, nil
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample models.AccountSample
+		want   []AlertKind
+	}{
+		{"healthy", models.AccountSample{Size: 100, Lamports: 2000, RentExemptMinimum: 1000}, nil},
+		{"exactly rent exempt", models.AccountSample{Size: 100, Lamports: 1000, RentExemptMinimum: 1000}, nil},
+		{"short of rent", models.AccountSample{Size: 100, Lamports: 999, RentExemptMinimum: 1000}, []AlertKind{AlertRent}},
+		{"below size ratio", models.AccountSample{Size: MaxAccountSize * 8 / 10, Lamports: 1, RentExemptMinimum: 1}, nil},
+		{"at size ratio", models.AccountSample{Size: MaxAccountSize * 9 / 10, Lamports: 1, RentExemptMinimum: 1}, []AlertKind{AlertSize}},
+		{"full and short", models.AccountSample{Size: MaxAccountSize, Lamports: 0, RentExemptMinimum: 1}, []AlertKind{AlertRent, AlertSize}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts := Check(tt.sample, 0.9)
+			var got []AlertKind
+			for _, a := range alerts {
+				got = append(got, a.Kind)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Check() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Check()[%d] = %s, want %s", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTrendOf(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	samples := []models.AccountSample{
+		{Size: 1000, Lamports: 5000, SampledAt: start},
+		{Size: 1500, Lamports: 4000, SampledAt: start.Add(24 * time.Hour)},
+		{Size: 3000, Lamports: 3000, SampledAt: start.Add(48 * time.Hour)},
+	}
+
+	trend := TrendOf(samples)
+	if trend == nil {
+		t.Fatal("TrendOf() = nil")
+	}
+	if trend.SizeChange != 2000 {
+		t.Errorf("SizeChange = %d, want 2000", trend.SizeChange)
+	}
+	if trend.LamportsChange != -2000 {
+		t.Errorf("LamportsChange = %d, want -2000", trend.LamportsChange)
+	}
+	if trend.BytesPerDay != 1000 {
+		t.Errorf("BytesPerDay = %g, want 1000", trend.BytesPerDay)
+	}
+	if want := float64(MaxAccountSize-3000) / 1000; trend.DaysToLimit == nil || *trend.DaysToLimit != want {
+		t.Errorf("DaysToLimit = %v, want %g", trend.DaysToLimit, want)
+	}
+
+	if got := TrendOf(samples[:1]); got != nil {
+		t.Errorf("TrendOf(one sample) = %+v, want nil", got)
+	}
+	shrinking := []models.AccountSample{samples[2], {Size: 100, SampledAt: start.Add(72 * time.Hour)}}
+	if got := TrendOf(shrinking); got.DaysToLimit != nil {
+		t.Errorf("DaysToLimit of a shrinking account = %g, want nil", *got.DaysToLimit)
+	}
+}
+
+func TestMonitor_SampleOnce(t *testing.T) {
+	program := solana.MustPublicKeyFromBase58("CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc")
+	healthy := solana.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112")
+	unfunded := solana.MustPublicKeyFromBase58("11111111111111111111111111111111")
+	source := &fakeSource{accounts: map[solana.PublicKey][]solanaClient.ProgramAccount{
+		program: {
+			{Address: healthy, Size: 64, Lamports: 10_000_000},
+			{Address: unfunded, Size: 64, Lamports: 1},
+		},
+	}}
+	store := &fakeStore{}
+	m := NewMonitor(source, store, []solana.PublicKey{program}, Options{})
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+
+	for range 2 {
+		if err := m.SampleOnce(context.Background()); err != nil {
+			t.Fatalf("SampleOnce() error = %v", err)
+		}
+	}
+
+	if len(store.samples) != 4 {
+		t.Fatalf("stored %d samples, want 4", len(store.samples))
+	}
+	if s := store.samples[0]; s.Program != program.String() || s.RentExemptMinimum != (64+128)*6960 || !s.SampledAt.Equal(now) {
+		t.Errorf("sample = %+v", s)
+	}
+	if source.rentCalls != 1 {
+		t.Errorf("rent minimum fetched %d times, want 1", source.rentCalls)
+	}
+
+	alerts := m.Alerts()
+	if len(alerts) != 1 || alerts[0].Kind != AlertRent || alerts[0].Sample.Address != unfunded.String() {
+		t.Errorf("Alerts() = %+v, want one rent alert for %s", alerts, unfunded)
+	}
+}
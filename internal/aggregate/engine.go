@@ -0,0 +1,200 @@
+package aggregate
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// Window is the value of a metric over [Start, End).
+type Window struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Value float64   `json:"value"`
+	// Partial marks the window that is still open.
+	Partial bool `json:"partial,omitempty"`
+}
+
+// Series is the recent windows of one metric, newest first.
+type Series struct {
+	Name        string           `json:"name"`
+	EventType   models.EventType `json:"event_type"`
+	Aggregation Aggregation      `json:"aggregation"`
+	Field       string           `json:"field,omitempty"`
+	Size        string           `json:"size"`
+	Slide       string           `json:"slide"`
+	Windows     []Window         `json:"windows"`
+}
+
+// Engine aggregates published events into windows keyed by block time. It
+// implements sink.Sink, so it sees every event after it has been stored.
+// Windows are kept in memory only and start empty on every restart.
+type Engine struct {
+	mu      sync.Mutex
+	metrics []*metric
+	history int
+	now     func() time.Time
+}
+
+type metric struct {
+	spec Spec
+	// buckets hold the events of one slide each, keyed by start time
+	// divided by the slide.
+	buckets map[int64]*bucket
+}
+
+type bucket struct {
+	sum    float64
+	unique map[string]struct{}
+}
+
+// NewEngine keeps the last history windows of each spec.
+func NewEngine(specs []Spec, history int) *Engine {
+	if history <= 0 {
+		history = 12
+	}
+	e := &Engine{history: history, now: time.Now}
+	for _, spec := range specs {
+		e.metrics = append(e.metrics, &metric{spec: spec, buckets: make(map[int64]*bucket)})
+	}
+	return e
+}
+
+func (e *Engine) Publish(ctx context.Context, envelope models.Envelope) error {
+	base := envelope.Base
+	event, err := envelope.Event()
+	if err != nil {
+		return err
+	}
+	at := base.BlockTime
+	if at.IsZero() {
+		at = e.now()
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.now()
+	for _, m := range e.metrics {
+		if m.spec.EventType != base.EventType {
+			continue
+		}
+		num, key, ok := m.spec.value(event)
+		if !ok {
+			continue
+		}
+
+		idx := bucketIndex(at, m.spec.Slide)
+		if idx < m.oldest(now, e.history) {
+			// Too old to show up in any retained window, e.g. while
+			// backfilling history.
+			continue
+		}
+		b := m.buckets[idx]
+		if b == nil {
+			b = &bucket{}
+			m.buckets[idx] = b
+		}
+		if m.spec.Aggregation == AggregationUnique {
+			if b.unique == nil {
+				b.unique = make(map[string]struct{})
+			}
+			b.unique[key] = struct{}{}
+		} else {
+			b.sum += num
+		}
+	}
+	return nil
+}
+
+func (e *Engine) Close(ctx context.Context) error {
+	return nil
+}
+
+// Snapshot returns the current windows of every metric.
+func (e *Engine) Snapshot() []Series {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.now()
+	series := make([]Series, 0, len(e.metrics))
+	for _, m := range e.metrics {
+		m.prune(now, e.history)
+		series = append(series, m.series(now, e.history))
+	}
+	return series
+}
+
+func bucketIndex(t time.Time, slide time.Duration) int64 {
+	return t.UnixNano() / int64(slide)
+}
+
+// span is the number of buckets in one window.
+func (m *metric) span() int64 {
+	return int64(m.spec.Size / m.spec.Slide)
+}
+
+// oldest is the first bucket of the oldest retained window.
+func (m *metric) oldest(now time.Time, history int) int64 {
+	return bucketIndex(now, m.spec.Slide) - int64(history) - m.span() + 2
+}
+
+func (m *metric) prune(now time.Time, history int) {
+	oldest := m.oldest(now, history)
+	for idx := range m.buckets {
+		if idx < oldest {
+			delete(m.buckets, idx)
+		}
+	}
+}
+
+func (m *metric) series(now time.Time, history int) Series {
+	s := Series{
+		Name:        m.spec.Name,
+		EventType:   m.spec.EventType,
+		Aggregation: m.spec.Aggregation,
+		Field:       m.spec.Field,
+		Size:        m.spec.Size.String(),
+		Slide:       m.spec.Slide.String(),
+		Windows:     make([]Window, 0, history),
+	}
+
+	current := bucketIndex(now, m.spec.Slide)
+	slide := int64(m.spec.Slide)
+	for k := int64(0); k < int64(history); k++ {
+		last := current - k
+		first := last - m.span() + 1
+		s.Windows = append(s.Windows, Window{
+			Start:   time.Unix(0, first*slide).UTC(),
+			End:     time.Unix(0, (last+1)*slide).UTC(),
+			Value:   m.value(first, last),
+			Partial: k == 0,
+		})
+	}
+	return s
+}
+
+// value aggregates the buckets first through last.
+func (m *metric) value(first, last int64) float64 {
+	if m.spec.Aggregation != AggregationUnique {
+		var sum float64
+		for idx := first; idx <= last; idx++ {
+			if b := m.buckets[idx]; b != nil {
+				sum += b.sum
+			}
+		}
+		return sum
+	}
+
+	seen := make(map[string]struct{})
+	for idx := first; idx <= last; idx++ {
+		if b := m.buckets[idx]; b != nil {
+			for key := range b.unique {
+				seen[key] = struct{}{}
+			}
+		}
+	}
+	return float64(len(seen))
+}
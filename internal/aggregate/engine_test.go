@@ -0,0 +1,94 @@
+package aggregate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+func publishPayment(e *Engine, at time.Time, payer solana.PublicKey, payment uint64) {
+	event := &models.CounterPaymentReceivedEvent{
+		BaseEvent: models.BaseEvent{EventType: models.EventTypeCounterPaymentReceived, BlockTime: at},
+		Payer:     payer,
+		Payment:   payment,
+	}
+	envelope, _ := models.NewEnvelope(event.BaseEvent, event)
+	e.Publish(context.Background(), envelope)
+}
+
+func values(s Series) []float64 {
+	out := make([]float64, len(s.Windows))
+	for i, w := range s.Windows {
+		out[i] = w.Value
+	}
+	return out
+}
+
+func TestEngine_Windows(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 7, 0, 0, time.UTC)
+	alice := solana.PublicKey{1}
+	bob := solana.PublicKey{2}
+
+	e := NewEngine([]Spec{
+		{Name: "payments_5m", EventType: models.EventTypeCounterPaymentReceived, Aggregation: AggregationCount, Size: 5 * time.Minute, Slide: 5 * time.Minute},
+		{Name: "lamports_5m", EventType: models.EventTypeCounterPaymentReceived, Aggregation: AggregationSum, Field: "payment", Size: 5 * time.Minute, Slide: 5 * time.Minute},
+		{Name: "payers_10m", EventType: models.EventTypeCounterPaymentReceived, Aggregation: AggregationUnique, Field: "payer", Size: 10 * time.Minute, Slide: 5 * time.Minute},
+	}, 3)
+	e.now = func() time.Time { return now }
+
+	publishPayment(e, now.Add(-1*time.Minute), alice, 10)    // 12:06, current window
+	publishPayment(e, now.Add(-2*time.Minute), alice, 20)    // 12:05
+	publishPayment(e, now.Add(-4*time.Minute), bob, 5)       // 12:03, previous window
+	publishPayment(e, now.Add(-12*time.Minute), bob, 1)      // 11:55
+	publishPayment(e, now.Add(-2*time.Hour), alice, 1000000) // dropped
+
+	series := e.Snapshot()
+	if len(series) != 3 {
+		t.Fatalf("Snapshot() returned %d series, want 3", len(series))
+	}
+
+	tests := []struct {
+		series Series
+		want   []float64
+	}{
+		{series[0], []float64{2, 1, 1}},
+		{series[1], []float64{30, 5, 1}},
+		{series[2], []float64{2, 1, 1}},
+	}
+	for _, tt := range tests {
+		got := values(tt.series)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s windows = %v, want %v", tt.series.Name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s windows = %v, want %v", tt.series.Name, got, tt.want)
+				break
+			}
+		}
+	}
+
+	current := series[0].Windows[0]
+	if !current.Partial || !current.Start.Equal(time.Date(2026, 5, 1, 12, 5, 0, 0, time.UTC)) || !current.End.Equal(time.Date(2026, 5, 1, 12, 10, 0, 0, time.UTC)) {
+		t.Errorf("current window = %+v", current)
+	}
+	if sliding := series[2].Windows[0]; !sliding.Start.Equal(time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("sliding window start = %v, want 12:00", sliding.Start)
+	}
+}
+
+func TestEngine_IgnoresOtherTypes(t *testing.T) {
+	e := NewEngine([]Spec{
+		{Name: "resets", EventType: models.EventTypeCounterReset, Aggregation: AggregationCount, Size: time.Minute, Slide: time.Minute},
+	}, 1)
+
+	publishPayment(e, time.Now(), solana.PublicKey{1}, 1)
+
+	if got := e.Snapshot()[0].Windows[0].Value; got != 0 {
+		t.Errorf("value = %v, want 0", got)
+	}
+}
@@ -0,0 +1,141 @@
+// Package aggregate computes windowed metrics over the event stream in
+// memory, for dashboards that need real-time numbers without querying the
+// database.
+package aggregate
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+type Aggregation string
+
+const (
+	// AggregationCount counts events.
+	AggregationCount Aggregation = "count"
+	// AggregationSum adds up a numeric field.
+	AggregationSum Aggregation = "sum"
+	// AggregationUnique counts distinct values of a field, e.g. payers.
+	AggregationUnique Aggregation = "unique"
+)
+
+// Spec defines one windowed metric.
+type Spec struct {
+	Name        string
+	EventType   models.EventType
+	Aggregation Aggregation
+	// Field is the JSON name of the event field read by sum and unique.
+	Field string
+	Size  time.Duration
+	// Slide is how far consecutive windows are apart. It equals Size for
+	// tumbling windows and divides it for sliding ones.
+	Slide time.Duration
+}
+
+// ParseSpec parses "<event type>:<aggregation>:<size>[/<slide>]", where the
+// aggregation is count, sum(<field>) or unique(<field>), e.g.
+// "CounterPaymentReceivedEvent:unique(payer):1h/5m".
+func ParseSpec(name, s string) (Spec, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return Spec{}, fmt.Errorf("%s: want <event type>:<aggregation>:<size>[/<slide>], got %q", name, s)
+	}
+
+	spec := Spec{Name: name, EventType: models.EventType(parts[0])}
+	if !spec.EventType.Known() {
+		return Spec{}, fmt.Errorf("%s: unknown event type %q", name, parts[0])
+	}
+
+	agg := parts[1]
+	if agg == string(AggregationCount) {
+		spec.Aggregation = AggregationCount
+	} else {
+		fn, field, ok := strings.Cut(strings.TrimSuffix(agg, ")"), "(")
+		if !ok || !strings.HasSuffix(agg, ")") || field == "" {
+			return Spec{}, fmt.Errorf("%s: aggregation must be count, sum(<field>) or unique(<field>), got %q", name, agg)
+		}
+		switch Aggregation(fn) {
+		case AggregationSum, AggregationUnique:
+			spec.Aggregation, spec.Field = Aggregation(fn), field
+		default:
+			return Spec{}, fmt.Errorf("%s: unknown aggregation %q", name, fn)
+		}
+	}
+
+	size, slide, hasSlide := strings.Cut(parts[2], "/")
+	var err error
+	if spec.Size, err = time.ParseDuration(size); err != nil || spec.Size <= 0 {
+		return Spec{}, fmt.Errorf("%s: invalid window size %q", name, size)
+	}
+	spec.Slide = spec.Size
+	if hasSlide {
+		if spec.Slide, err = time.ParseDuration(slide); err != nil || spec.Slide <= 0 {
+			return Spec{}, fmt.Errorf("%s: invalid slide %q", name, slide)
+		}
+		if spec.Slide > spec.Size || spec.Size%spec.Slide != 0 {
+			return Spec{}, fmt.Errorf("%s: slide %s must divide window size %s", name, spec.Slide, spec.Size)
+		}
+	}
+	return spec, nil
+}
+
+// value extracts the number or identity a spec aggregates from an event.
+// ok is false when the event lacks the field or, for sums, it is not
+// numeric.
+func (s Spec) value(event interface{}) (num float64, key string, ok bool) {
+	if s.Aggregation == AggregationCount {
+		return 1, "", true
+	}
+
+	v := reflect.ValueOf(event)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return 0, "", false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return 0, "", false
+	}
+	field, found := fieldByJSONName(v, s.Field)
+	if !found {
+		return 0, "", false
+	}
+
+	if s.Aggregation == AggregationUnique {
+		return 0, fmt.Sprint(field.Interface()), true
+	}
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(field.Int()), "", true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(field.Uint()), "", true
+	case reflect.Float32, reflect.Float64:
+		return field.Float(), "", true
+	}
+	return 0, "", false
+}
+
+// fieldByJSONName finds a field by its JSON tag, searching embedded
+// structs such as BaseEvent.
+func fieldByJSONName(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			if field, ok := fieldByJSONName(v.Field(i), name); ok {
+				return field, true
+			}
+			continue
+		}
+		tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if tag == name && f.IsExported() {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
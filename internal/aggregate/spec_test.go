@@ -0,0 +1,46 @@
+package aggregate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+func TestParseSpec(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Spec
+		wantErr bool
+	}{
+		{
+			in:   "TokensTransferredEvent:count:5m",
+			want: Spec{Name: "m", EventType: models.EventTypeTokensTransferred, Aggregation: AggregationCount, Size: 5 * time.Minute, Slide: 5 * time.Minute},
+		},
+		{
+			in:   "CounterPaymentReceivedEvent:unique(payer):1h/5m",
+			want: Spec{Name: "m", EventType: models.EventTypeCounterPaymentReceived, Aggregation: AggregationUnique, Field: "payer", Size: time.Hour, Slide: 5 * time.Minute},
+		},
+		{
+			in:   "CounterPaymentReceivedEvent:sum(payment):1h",
+			want: Spec{Name: "m", EventType: models.EventTypeCounterPaymentReceived, Aggregation: AggregationSum, Field: "payment", Size: time.Hour, Slide: time.Hour},
+		},
+		{in: "Transfers:count:5m", wantErr: true},
+		{in: "TokensTransferredEvent:avg(amount):5m", wantErr: true},
+		{in: "TokensTransferredEvent:sum():5m", wantErr: true},
+		{in: "TokensTransferredEvent:count:5m/7m", wantErr: true},
+		{in: "TokensTransferredEvent:count:1h/7m", wantErr: true},
+		{in: "TokensTransferredEvent:count", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSpec("m", tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSpec(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseSpec(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
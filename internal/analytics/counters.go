@@ -0,0 +1,102 @@
+package analytics
+
+import (
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// CounterEventTypes are the events that set the value of a counter.
+var CounterEventTypes = []models.EventType{
+	models.EventTypeCounterInitialized,
+	models.EventTypeCounterIncremented,
+	models.EventTypeCounterDecremented,
+	models.EventTypeCounterAdded,
+	models.EventTypeCounterReset,
+	models.EventTypeCounterPaymentReceived,
+}
+
+// CounterChange is the value of a counter after one event.
+type CounterChange struct {
+	Time      time.Time        `json:"time"`
+	Slot      uint64           `json:"slot"`
+	Signature string           `json:"signature"`
+	EventType models.EventType `json:"event_type"`
+	Value     uint64           `json:"value"`
+}
+
+// CounterChangeOf returns the counter an event sets and the value it
+// leaves; ok is false for other events.
+func CounterChangeOf(event interface{}) (counter solana.PublicKey, change CounterChange, ok bool) {
+	var base *models.BaseEvent
+	switch e := event.(type) {
+	case *models.CounterInitializedEvent:
+		counter, base, change.Value = e.Counter, &e.BaseEvent, e.InitialCount
+	case *models.CounterIncrementedEvent:
+		counter, base, change.Value = e.Counter, &e.BaseEvent, e.NewValue
+	case *models.CounterDecrementedEvent:
+		counter, base, change.Value = e.Counter, &e.BaseEvent, e.NewValue
+	case *models.CounterAddedEvent:
+		counter, base, change.Value = e.Counter, &e.BaseEvent, e.NewValue
+	case *models.CounterResetEvent:
+		counter, base, change.Value = e.Counter, &e.BaseEvent, 0
+	case *models.CounterPaymentReceivedEvent:
+		counter, base, change.Value = e.Counter, &e.BaseEvent, e.NewCount
+	default:
+		return counter, change, false
+	}
+	change.Time = base.BlockTime
+	change.Slot = base.Slot
+	change.Signature = base.Signature
+	change.EventType = base.EventType
+	return counter, change, true
+}
+
+// CounterBucket is the value of a counter over [Start, Start+interval):
+// Open before its first change, Close after its last, and the range in
+// between.
+type CounterBucket struct {
+	Start   time.Time `json:"start"`
+	Open    uint64    `json:"open"`
+	Close   uint64    `json:"close"`
+	Min     uint64    `json:"min"`
+	Max     uint64    `json:"max"`
+	Changes int       `json:"changes"`
+}
+
+// DownsampleCounter groups changes, oldest first, into buckets of interval
+// aligned to the Unix epoch covering [from, to). start is the value before
+// from, nil when unknown. Buckets without changes carry the value over;
+// those before the first known value are left out.
+func DownsampleCounter(changes []CounterChange, start *uint64, from, to time.Time, interval time.Duration) []CounterBucket {
+	buckets := []CounterBucket{}
+	known := start != nil
+	var value uint64
+	if known {
+		value = *start
+	}
+
+	epoch := time.Unix(0, 0).UTC()
+	i := 0
+	for t := epoch.Add(from.Sub(epoch) / interval * interval); t.Before(to); t = t.Add(interval) {
+		end := t.Add(interval)
+		b := CounterBucket{Start: t, Open: value, Close: value, Min: value, Max: value}
+		for ; i < len(changes) && changes[i].Time.Before(end); i++ {
+			v := changes[i].Value
+			if !known {
+				known = true
+				b.Open, b.Min, b.Max = v, v, v
+			}
+			b.Close = v
+			b.Min = min(b.Min, v)
+			b.Max = max(b.Max, v)
+			b.Changes++
+			value = v
+		}
+		if known {
+			buckets = append(buckets, b)
+		}
+	}
+	return buckets
+}
@@ -0,0 +1,61 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+func TestCounterChangeOf(t *testing.T) {
+	counter := solana.MustPublicKeyFromBase58("7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU")
+	tests := []struct {
+		event  interface{}
+		want   uint64
+		wantOk bool
+	}{
+		{&models.CounterInitializedEvent{Counter: counter, InitialCount: 5}, 5, true},
+		{&models.CounterIncrementedEvent{Counter: counter, OldValue: 5, NewValue: 6}, 6, true},
+		{&models.CounterAddedEvent{Counter: counter, OldValue: 6, AddedValue: 4, NewValue: 10}, 10, true},
+		{&models.CounterResetEvent{Counter: counter, OldValue: 10}, 0, true},
+		{&models.CounterPaymentReceivedEvent{Counter: counter, NewCount: 1}, 1, true},
+		{&models.TokensMintedEvent{}, 0, false},
+	}
+	for _, tt := range tests {
+		c, change, ok := CounterChangeOf(tt.event)
+		if ok != tt.wantOk || change.Value != tt.want || (ok && c != counter) {
+			t.Errorf("CounterChangeOf(%T) = %s, %d, %v, want %d, %v", tt.event, c, change.Value, ok, tt.want, tt.wantOk)
+		}
+	}
+}
+
+func TestDownsampleCounter(t *testing.T) {
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hour, value int) CounterChange {
+		return CounterChange{Time: day.Add(time.Duration(hour) * time.Hour), Value: uint64(value)}
+	}
+	changes := []CounterChange{at(1, 3), at(2, 8), at(3, 1), at(9, 2)}
+
+	start := uint64(4)
+	got := DownsampleCounter(changes, &start, day, day.Add(12*time.Hour), 6*time.Hour)
+	want := []CounterBucket{
+		{Start: day, Open: 4, Close: 1, Min: 1, Max: 8, Changes: 3},
+		{Start: day.Add(6 * time.Hour), Open: 1, Close: 2, Min: 1, Max: 2, Changes: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("DownsampleCounter() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("bucket %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	// Without a start value, buckets before the first change are left out
+	// and later empty ones carry the value over.
+	got = DownsampleCounter(changes[3:], nil, day, day.Add(24*time.Hour), 6*time.Hour)
+	if len(got) != 3 || got[0].Open != 2 || got[0].Changes != 1 || got[2].Close != 2 || got[2].Changes != 0 {
+		t.Errorf("DownsampleCounter() without start = %+v", got)
+	}
+}
@@ -0,0 +1,93 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+// SalesTracker maintains collection sales stats as a sink: mints record the
+// collection of each NFT and sales are added to the stats of theirs. Store
+// failures are logged rather than returned so stats never block indexing,
+// unless the stats are updated in the transaction saving the event, see
+// Project.
+type SalesTracker struct {
+	store repository.NftSalesStore
+}
+
+func NewSalesTracker(store repository.NftSalesStore) *SalesTracker {
+	return &SalesTracker{store: store}
+}
+
+func (t *SalesTracker) Publish(ctx context.Context, envelope models.Envelope) error {
+	if err := t.Project(ctx, envelope); err != nil {
+		log.Printf("warning: %v", err)
+	}
+	return nil
+}
+
+// Project updates the stats for an event and returns store failures.
+func (t *SalesTracker) Project(ctx context.Context, envelope models.Envelope) error {
+	event, err := envelope.Event()
+	if err != nil {
+		return err
+	}
+	switch e := event.(type) {
+	case *models.NftMintedEvent:
+		if err := t.store.RecordNftCollection(ctx, e.NftMint.String(), e.Collection.String()); err != nil {
+			return fmt.Errorf("failed to record collection of nft %s: %w", e.NftMint, err)
+		}
+	case *models.NftSoldEvent:
+		if err := t.store.RecordNftSale(ctx, e); err != nil {
+			return fmt.Errorf("failed to record sale of nft %s in %s: %w", e.NftMint, envelope.Base.Signature, err)
+		}
+	}
+	return nil
+}
+
+func (t *SalesTracker) Close(ctx context.Context) error {
+	return nil
+}
+
+// SalesTotals summarizes a range of periods.
+type SalesTotals struct {
+	Sales  int64  `json:"sales"`
+	Volume uint64 `json:"volume"`
+	Floor  uint64 `json:"floor"`
+	High   uint64 `json:"high"`
+}
+
+// FillSalesPeriods returns one entry per period starting in [from, to),
+// oldest first, with zero entries for periods without sales, and the totals
+// over all of them.
+func FillSalesPeriods(stats []models.CollectionSalesStats, period models.SalesPeriod, from, to time.Time) ([]models.CollectionSalesStats, SalesTotals) {
+	byStart := make(map[time.Time]models.CollectionSalesStats, len(stats))
+	for _, s := range stats {
+		byStart[s.Start.UTC()] = s
+	}
+
+	var filled []models.CollectionSalesStats
+	var totals SalesTotals
+	for start := period.Start(from); start.Before(to); start = period.Next(start) {
+		s, ok := byStart[start]
+		if !ok {
+			s = models.CollectionSalesStats{Period: period, Start: start}
+		}
+		filled = append(filled, s)
+
+		if s.Sales == 0 {
+			continue
+		}
+		if totals.Sales == 0 || s.Floor < totals.Floor {
+			totals.Floor = s.Floor
+		}
+		totals.Sales += s.Sales
+		totals.Volume += s.Volume
+		totals.High = max(totals.High, s.High)
+	}
+	return filled, totals
+}
@@ -0,0 +1,98 @@
+package analytics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models/modelstest"
+)
+
+func TestSalesPeriod_Start(t *testing.T) {
+	tests := []struct {
+		period models.SalesPeriod
+		at     time.Time
+		want   time.Time
+	}{
+		{models.SalesPeriodDay, time.Date(2026, 1, 7, 15, 4, 5, 0, time.UTC), time.Date(2026, 1, 7, 0, 0, 0, 0, time.UTC)},
+		// 2026-01-07 is a Wednesday.
+		{models.SalesPeriodWeek, time.Date(2026, 1, 7, 15, 4, 5, 0, time.UTC), time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{models.SalesPeriodWeek, time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{models.SalesPeriodWeek, time.Date(2026, 1, 11, 23, 0, 0, 0, time.UTC), time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		if got := tt.period.Start(tt.at); !got.Equal(tt.want) {
+			t.Errorf("%s.Start(%v) = %v, want %v", tt.period, tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestFillSalesPeriods(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+	stats := []models.CollectionSalesStats{
+		{Start: day(2), Sales: 2, Volume: 300, Floor: 100, High: 200},
+		{Start: day(4), Sales: 1, Volume: 50, Floor: 50, High: 50},
+	}
+
+	periods, totals := FillSalesPeriods(stats, models.SalesPeriodDay, day(1), day(5))
+	if len(periods) != 4 {
+		t.Fatalf("got %d periods, want 4", len(periods))
+	}
+	for i, want := range []int64{0, 2, 0, 1} {
+		if periods[i].Sales != want || !periods[i].Start.Equal(day(i+1)) {
+			t.Errorf("period %d = %+v, want %d sales on %v", i, periods[i], want, day(i+1))
+		}
+	}
+
+	want := SalesTotals{Sales: 3, Volume: 350, Floor: 50, High: 200}
+	if totals != want {
+		t.Errorf("totals = %+v, want %+v", totals, want)
+	}
+}
+
+type fakeSalesStore struct {
+	collections map[string]string
+	sales       []*models.NftSoldEvent
+}
+
+func (s *fakeSalesStore) RecordNftCollection(ctx context.Context, mint, collection string) error {
+	s.collections[mint] = collection
+	return nil
+}
+
+func (s *fakeSalesStore) RecordNftSale(ctx context.Context, sale *models.NftSoldEvent) error {
+	s.sales = append(s.sales, sale)
+	return nil
+}
+
+func (s *fakeSalesStore) GetCollectionStats(ctx context.Context, collection string, period models.SalesPeriod, from, to time.Time) ([]models.CollectionSalesStats, error) {
+	return nil, nil
+}
+
+func TestSalesTracker_Publish(t *testing.T) {
+	store := &fakeSalesStore{collections: make(map[string]string)}
+	tracker := NewSalesTracker(store)
+
+	mint := solana.MustPublicKeyFromBase58("7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU")
+	collection := solana.MustPublicKeyFromBase58("9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM")
+	events := []models.Event{
+		&models.NftMintedEvent{NftMint: mint, Collection: collection},
+		&models.NftSoldEvent{NftMint: mint, Price: 100},
+		&models.TokensMintedEvent{Mint: mint},
+	}
+	for _, event := range events {
+		if err := tracker.Publish(context.Background(), modelstest.Envelope(t, models.BaseEvent{}, event)); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+
+	if got := store.collections[mint.String()]; got != collection.String() {
+		t.Errorf("collection of mint = %q, want %q", got, collection)
+	}
+	if len(store.sales) != 1 || store.sales[0].Price != 100 {
+		t.Errorf("sales = %+v, want one sale of 100", store.sales)
+	}
+}
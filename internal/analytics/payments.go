@@ -0,0 +1,74 @@
+package analytics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// DailyPaymentStats summarizes the CounterPaymentReceived payments of one
+// UTC day. Amounts are in lamports.
+type DailyPaymentStats struct {
+	Date        string `json:"date"`
+	Count       int    `json:"count"`
+	Total       uint64 `json:"total"`
+	Min         uint64 `json:"min"`
+	Median      uint64 `json:"median"`
+	P95         uint64 `json:"p95"`
+	Max         uint64 `json:"max"`
+	BelowMinFee int    `json:"below_min_fee"`
+}
+
+// PaymentStatsByDay groups payments by the UTC day of their block time and
+// computes distribution stats for each day, oldest first. Payments strictly
+// below minFee are counted in BelowMinFee; a zero minFee disables the check.
+func PaymentStatsByDay(payments []models.CounterPaymentReceivedEvent, minFee uint64) []DailyPaymentStats {
+	byDay := make(map[string][]uint64)
+	for _, p := range payments {
+		day := p.BlockTime.UTC().Format(time.DateOnly)
+		byDay[day] = append(byDay[day], p.Payment)
+	}
+
+	days := make([]string, 0, len(byDay))
+	for day := range byDay {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+
+	stats := make([]DailyPaymentStats, 0, len(days))
+	for _, day := range days {
+		amounts := byDay[day]
+		sort.Slice(amounts, func(i, j int) bool { return amounts[i] < amounts[j] })
+
+		s := DailyPaymentStats{
+			Date:   day,
+			Count:  len(amounts),
+			Min:    amounts[0],
+			Median: percentile(amounts, 50),
+			P95:    percentile(amounts, 95),
+			Max:    amounts[len(amounts)-1],
+		}
+		for _, amount := range amounts {
+			s.Total += amount
+			if amount < minFee {
+				s.BelowMinFee++
+			}
+		}
+		stats = append(stats, s)
+	}
+
+	return stats
+}
+
+// percentile returns the nearest-rank percentile of sorted values.
+func percentile(sorted []uint64, p int) uint64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
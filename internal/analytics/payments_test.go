@@ -0,0 +1,53 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+func payment(day int, hour int, amount uint64) models.CounterPaymentReceivedEvent {
+	e := models.CounterPaymentReceivedEvent{Payment: amount}
+	e.BlockTime = time.Date(2026, 1, day, hour, 0, 0, 0, time.UTC)
+	return e
+}
+
+func TestPaymentStatsByDay(t *testing.T) {
+	var payments []models.CounterPaymentReceivedEvent
+	for i := uint64(1); i <= 20; i++ {
+		payments = append(payments, payment(2, int(i%24), i*100))
+	}
+	payments = append(payments, payment(1, 23, 5000))
+
+	stats := PaymentStatsByDay(payments, 1000)
+	if len(stats) != 2 {
+		t.Fatalf("got %d days, want 2", len(stats))
+	}
+
+	first := stats[0]
+	if first.Date != "2026-01-01" || first.Count != 1 || first.Min != 5000 || first.Median != 5000 || first.P95 != 5000 {
+		t.Errorf("day 1 stats = %+v", first)
+	}
+
+	second := stats[1]
+	want := DailyPaymentStats{
+		Date:        "2026-01-02",
+		Count:       20,
+		Total:       21000,
+		Min:         100,
+		Median:      1000,
+		P95:         1900,
+		Max:         2000,
+		BelowMinFee: 9,
+	}
+	if second != want {
+		t.Errorf("day 2 stats = %+v, want %+v", second, want)
+	}
+}
+
+func TestPaymentStatsByDay_Empty(t *testing.T) {
+	if stats := PaymentStatsByDay(nil, 0); len(stats) != 0 {
+		t.Errorf("got %d days, want 0", len(stats))
+	}
+}
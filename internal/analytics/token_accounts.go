@@ -0,0 +1,124 @@
+package analytics
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+	solanaClient "github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// tokenAccountCacheEntries bounds the wallet and mint pairs remembered as
+// stored; the cache is cleared when it is full.
+const tokenAccountCacheEntries = 100000
+
+// tokenPrograms are the programs associated token accounts are derived for,
+// since events do not say which one a mint belongs to.
+var tokenPrograms = []solana.PublicKey{solanaClient.TokenProgramID, solanaClient.Token2022ProgramID}
+
+type walletMint struct {
+	wallet, mint solana.PublicKey
+}
+
+// TokenAccountTracker stores the associated token accounts of the wallets in
+// token events as a sink. Like SalesTracker it logs store failures rather
+// than returning them.
+type TokenAccountTracker struct {
+	store repository.TokenAccountStore
+
+	mu   sync.Mutex
+	seen map[walletMint]bool
+}
+
+func NewTokenAccountTracker(store repository.TokenAccountStore) *TokenAccountTracker {
+	return &TokenAccountTracker{store: store, seen: make(map[walletMint]bool)}
+}
+
+func (t *TokenAccountTracker) Publish(ctx context.Context, envelope models.Envelope) error {
+	base := envelope.Base
+	event, err := envelope.Event()
+	if err != nil {
+		return err
+	}
+	var mint solana.PublicKey
+	switch e := event.(type) {
+	case *models.TokensMintedEvent:
+		mint = e.Mint
+	case *models.TokensTransferredEvent:
+		mint = e.Mint
+	case *models.TokensBurnedEvent:
+		mint = e.Mint
+	default:
+		return nil
+	}
+
+	var accounts []models.TokenAccount
+	var pairs []walletMint
+	for _, wallet := range models.WalletAddresses(event) {
+		pair := walletMint{wallet: wallet, mint: mint}
+		if t.isSeen(pair) {
+			continue
+		}
+		derived, err := AssociatedTokenAccounts(wallet, mint, base.Slot)
+		if err != nil {
+			log.Printf("warning: %v", err)
+			continue
+		}
+		accounts = append(accounts, derived...)
+		pairs = append(pairs, pair)
+	}
+	if len(accounts) == 0 {
+		return nil
+	}
+
+	if err := t.store.SaveTokenAccounts(ctx, accounts); err != nil {
+		log.Printf("warning: failed to save token accounts for %s: %v", base.Signature, err)
+		return nil
+	}
+	t.markSeen(pairs)
+	return nil
+}
+
+func (t *TokenAccountTracker) Close(ctx context.Context) error {
+	return nil
+}
+
+func (t *TokenAccountTracker) isSeen(pair walletMint) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.seen[pair]
+}
+
+func (t *TokenAccountTracker) markSeen(pairs []walletMint) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.seen)+len(pairs) > tokenAccountCacheEntries {
+		t.seen = make(map[walletMint]bool)
+	}
+	for _, pair := range pairs {
+		t.seen[pair] = true
+	}
+}
+
+// AssociatedTokenAccounts derives the associated token accounts of wallet
+// for mint under both token programs.
+func AssociatedTokenAccounts(wallet, mint solana.PublicKey, slot uint64) ([]models.TokenAccount, error) {
+	accounts := make([]models.TokenAccount, 0, len(tokenPrograms))
+	for _, program := range tokenPrograms {
+		addr, err := solanaClient.AssociatedTokenAddress(wallet, mint, program)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, models.TokenAccount{
+			Address:      addr.String(),
+			Owner:        wallet.String(),
+			Mint:         mint.String(),
+			TokenProgram: program.String(),
+			Slot:         slot,
+		})
+	}
+	return accounts, nil
+}
@@ -0,0 +1,61 @@
+package analytics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models/modelstest"
+	solanaClient "github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+type fakeTokenAccountStore struct {
+	saved []models.TokenAccount
+}
+
+func (s *fakeTokenAccountStore) SaveTokenAccounts(ctx context.Context, accounts []models.TokenAccount) error {
+	s.saved = append(s.saved, accounts...)
+	return nil
+}
+
+func (s *fakeTokenAccountStore) GetTokenAccount(ctx context.Context, address string) (*models.TokenAccount, error) {
+	return nil, nil
+}
+
+func (s *fakeTokenAccountStore) GetTokenAccountsByOwner(ctx context.Context, owner string) ([]models.TokenAccount, error) {
+	return nil, nil
+}
+
+func TestTokenAccountTracker_Publish(t *testing.T) {
+	store := &fakeTokenAccountStore{}
+	tracker := NewTokenAccountTracker(store)
+
+	mint := solana.MustPublicKeyFromBase58("7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU")
+	alice := solana.MustPublicKeyFromBase58("9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM")
+	bob := solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+	events := []models.Event{
+		&models.TokensTransferredEvent{Mint: mint, From: alice, To: bob, Amount: 1},
+		// Both pairs are already stored.
+		&models.TokensTransferredEvent{Mint: mint, From: bob, To: alice, Amount: 1},
+		&models.NftSoldEvent{NftMint: mint, Seller: alice},
+	}
+	for _, event := range events {
+		if err := tracker.Publish(context.Background(), modelstest.Envelope(t, models.BaseEvent{Slot: 9}, event)); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+
+	// One account per wallet and token program.
+	if len(store.saved) != 4 {
+		t.Fatalf("saved %d token accounts, want 4: %+v", len(store.saved), store.saved)
+	}
+	want, _, _ := solana.FindProgramAddress([][]byte{alice[:], solanaClient.TokenProgramID[:], mint[:]}, solanaClient.AssociatedTokenProgramID)
+	got := store.saved[0]
+	if got.Address != want.String() || got.Owner != alice.String() || got.Mint != mint.String() || got.Slot != 9 {
+		t.Errorf("saved[0] = %+v, want address %s owned by %s", got, want, alice)
+	}
+	if store.saved[1].TokenProgram != solanaClient.Token2022ProgramID.String() {
+		t.Errorf("saved[1].TokenProgram = %s, want %s", store.saved[1].TokenProgram, solanaClient.Token2022ProgramID)
+	}
+}
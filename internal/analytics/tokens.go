@@ -0,0 +1,69 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+// TokenTracker maintains token supplies and holder balances as a sink. Like
+// SalesTracker it logs store failures rather than returning them.
+type TokenTracker struct {
+	store repository.TokenHolderStore
+}
+
+func NewTokenTracker(store repository.TokenHolderStore) *TokenTracker {
+	return &TokenTracker{store: store}
+}
+
+func (t *TokenTracker) Publish(ctx context.Context, envelope models.Envelope) error {
+	if err := t.Project(ctx, envelope); err != nil {
+		log.Printf("warning: %v", err)
+	}
+	return nil
+}
+
+// Project updates the supplies and balances for an event and returns store
+// failures.
+func (t *TokenTracker) Project(ctx context.Context, envelope models.Envelope) error {
+	base := envelope.Base
+	event, err := envelope.Event()
+	if err != nil {
+		return err
+	}
+	m, ok := TokenMovement(base, event)
+	if !ok {
+		return nil
+	}
+	if err := t.store.RecordTokenMovement(ctx, m); err != nil {
+		return fmt.Errorf("failed to record token movement of mint %s in %s: %w", m.Mint, base.Signature, err)
+	}
+	return nil
+}
+
+func (t *TokenTracker) Close(ctx context.Context) error {
+	return nil
+}
+
+// TokenMovement converts a token mint, transfer or burn event to the
+// movement it makes. Events carry no index within their transaction, so two
+// identical movements in one transaction share an ID and count once.
+func TokenMovement(base models.BaseEvent, event interface{}) (models.TokenMovement, bool) {
+	var m models.TokenMovement
+	switch e := event.(type) {
+	case *models.TokensMintedEvent:
+		m = models.TokenMovement{Mint: e.Mint.String(), To: e.Recipient.String(), Amount: e.Amount}
+	case *models.TokensTransferredEvent:
+		m = models.TokenMovement{Mint: e.Mint.String(), From: e.From.String(), To: e.To.String(), Amount: e.Amount}
+	case *models.TokensBurnedEvent:
+		m = models.TokenMovement{Mint: e.Mint.String(), From: e.Owner.String(), Amount: e.Amount}
+	default:
+		return m, false
+	}
+	m.Slot = base.Slot
+	m.ID = fmt.Sprintf("%s:%s:%s:%s:%s:%d", base.Signature, base.EventType, m.Mint, m.From, m.To, m.Amount)
+	return m, true
+}
@@ -0,0 +1,63 @@
+package analytics
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+func TestTokenMovement(t *testing.T) {
+	mint := solana.MustPublicKeyFromBase58("7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU")
+	alice := solana.MustPublicKeyFromBase58("9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM")
+	bob := solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+
+	tests := []struct {
+		name   string
+		event  interface{}
+		want   models.TokenMovement
+		wantOK bool
+	}{
+		{
+			name:   "mint",
+			event:  &models.TokensMintedEvent{Mint: mint, Recipient: alice, Amount: 100},
+			want:   models.TokenMovement{Mint: mint.String(), To: alice.String(), Amount: 100},
+			wantOK: true,
+		},
+		{
+			name:   "transfer",
+			event:  &models.TokensTransferredEvent{Mint: mint, From: alice, To: bob, Amount: 40},
+			want:   models.TokenMovement{Mint: mint.String(), From: alice.String(), To: bob.String(), Amount: 40},
+			wantOK: true,
+		},
+		{
+			name:   "burn",
+			event:  &models.TokensBurnedEvent{Mint: mint, Owner: bob, Amount: 10},
+			want:   models.TokenMovement{Mint: mint.String(), From: bob.String(), Amount: 10},
+			wantOK: true,
+		},
+		{
+			name:  "other event",
+			event: &models.NftSoldEvent{NftMint: mint},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := TokenMovement(models.BaseEvent{Signature: "sig", Slot: 7}, tt.event)
+			if ok != tt.wantOK {
+				t.Fatalf("TokenMovement() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if got.ID == "" || got.Slot != 7 {
+				t.Errorf("TokenMovement() = %+v, want an ID and slot 7", got)
+			}
+			got.ID, got.Slot = "", 0
+			if got != tt.want {
+				t.Errorf("TokenMovement() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/accountmon"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+// handleAccountEvents returns the timeline of an address: every event that
+// references it as a wallet, mint, collection, counter or any other account,
+// newest first unless order=asc. Events reference owners rather than token
+// accounts, so a known associated token account shows its owner's timeline.
+func (s *Server) handleAccountEvents(w http.ResponseWriter, r *http.Request) *Problem {
+	query := r.URL.Query()
+
+	var errs []FieldError
+	account, err := solana.PublicKeyFromBase58(r.PathValue("pubkey"))
+	if err != nil {
+		errs = append(errs, FieldError{Field: "pubkey", Message: "must be a base58 public key"})
+	}
+
+	page, pageErrs := parsePage(query)
+	errs = append(errs, pageErrs...)
+	opts := repository.AccountEventsOptions{PageOptions: page}
+	if raw := query.Get("type"); raw != "" {
+		for _, name := range strings.Split(raw, ",") {
+			eventType := models.EventType(strings.TrimSpace(name))
+			if !eventType.Known() {
+				errs = append(errs, FieldError{Field: "type", Message: fmt.Sprintf("unknown event type %q", name)})
+				continue
+			}
+			opts.EventTypes = append(opts.EventTypes, eventType)
+		}
+	}
+	opts.Deployment, errs = s.parseDeployment(query, errs)
+	if len(errs) > 0 {
+		return ValidationProblem(errs...)
+	}
+
+	var tokenAccount *models.TokenAccount
+	if store, ok := repository.Unwrap(s.repo).(repository.TokenAccountStore); ok {
+		tokenAccount, err = store.GetTokenAccount(r.Context(), account.String())
+		if err != nil {
+			return upstreamProblem(err)
+		}
+		if tokenAccount != nil {
+			account = solana.MustPublicKeyFromBase58(tokenAccount.Owner)
+		}
+	}
+
+	result, err := s.repo.GetEventsByAccount(r.Context(), account, opts)
+	if err != nil {
+		return upstreamProblem(err)
+	}
+	if p := s.decrypt(result.Events...); p != nil {
+		return p
+	}
+
+	body := map[string]interface{}{
+		"account":     account.String(),
+		"events":      eventsOrEmpty(result.Events),
+		"count":       len(result.Events),
+		"next_cursor": nextCursor(result),
+	}
+	if tokenAccount != nil {
+		body["token_account"] = tokenAccount
+	}
+	return writeJSON(w, http.StatusOK, body)
+}
+
+// maxAccountSamples caps the samples returned for a range; past it the
+// newest are returned.
+const maxAccountSamples = 2000
+
+// handleAccountSamples returns the size and balance history of a program
+// account with its trend, for the last 7 days unless from and to are given.
+func (s *Server) handleAccountSamples(w http.ResponseWriter, r *http.Request) *Problem {
+	store, ok := repository.Unwrap(s.repo).(repository.AccountSampleStore)
+	if !ok {
+		return NewProblem(CodeNotImplemented, "account samples are not supported by the configured database")
+	}
+	query := r.URL.Query()
+
+	var errs []FieldError
+	account, err := solana.PublicKeyFromBase58(r.PathValue("pubkey"))
+	if err != nil {
+		errs = append(errs, FieldError{Field: "pubkey", Message: "must be a base58 public key"})
+	}
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if raw := query.Get("to"); raw != "" {
+		d, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			errs = append(errs, FieldError{Field: "to", Message: "must be a date in YYYY-MM-DD format"})
+		} else {
+			to = d
+		}
+	}
+	from := to.AddDate(0, 0, -(defaultAnalyticsDays - 1))
+	if raw := query.Get("from"); raw != "" {
+		d, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			errs = append(errs, FieldError{Field: "from", Message: "must be a date in YYYY-MM-DD format"})
+		} else {
+			from = d
+		}
+	}
+	if len(errs) > 0 {
+		return ValidationProblem(errs...)
+	}
+	if from.After(to) {
+		return ValidationProblem(FieldError{Field: "from", Message: "must not be after to"})
+	}
+
+	// The range is inclusive of the whole "to" day.
+	samples, err := store.GetAccountSamples(r.Context(), account.String(), from, to.AddDate(0, 0, 1), maxAccountSamples)
+	if err != nil {
+		return upstreamProblem(err)
+	}
+	if samples == nil {
+		samples = []models.AccountSample{}
+	}
+
+	return writeJSON(w, http.StatusOK, map[string]interface{}{
+		"account": account.String(),
+		"from":    from.Format(time.DateOnly),
+		"to":      to.Format(time.DateOnly),
+		"samples": samples,
+		"count":   len(samples),
+		"trend":   accountmon.TrendOf(samples),
+	})
+}
+
+func (s *Server) handleAccountAlerts(w http.ResponseWriter, r *http.Request) *Problem {
+	alerts := s.accountAlerts()
+	return writeJSON(w, http.StatusOK, map[string]interface{}{
+		"alerts": alerts,
+		"count":  len(alerts),
+	})
+}
+
+func (s *Server) accountAlerts() []accountmon.Alert {
+	var alerts []accountmon.Alert
+	if s.accounts != nil {
+		alerts = s.accounts.AccountAlerts()
+	}
+	if alerts == nil {
+		alerts = []accountmon.Alert{}
+	}
+	return alerts
+}
@@ -0,0 +1,203 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+type Role string
+
+const (
+	// RoleReader may read everything except the admin endpoints.
+	RoleReader Role = "reader"
+	// RoleOperator may also change state, e.g. save reports, and use the
+	// admin endpoints.
+	RoleOperator Role = "operator"
+	// RoleAdmin may also control indexing: pause, resume and reindex.
+	RoleAdmin Role = "admin"
+)
+
+// roleRanks orders the roles; each may do everything the lower ones may.
+var roleRanks = map[Role]int{RoleReader: 1, RoleOperator: 2, RoleAdmin: 3}
+
+// Allows reports whether r may make requests requiring role.
+func (r Role) Allows(role Role) bool {
+	return roleRanks[r] >= roleRanks[role]
+}
+
+type userKey struct{}
+
+// userFromContext returns the authenticated caller, nil when the API is
+// open.
+func userFromContext(ctx context.Context) *User {
+	user, _ := ctx.Value(userKey{}).(*User)
+	return user
+}
+
+// User is an API caller identified by a bearer token. Only the SHA-256 of
+// the token is kept.
+type User struct {
+	Name      string
+	Role      Role
+	TokenHash [sha256.Size]byte
+	// Tenant limits the user to the event endpoints, answered from the
+	// tenant's events only; empty means all events.
+	Tenant string
+}
+
+// publicPaths stay reachable without a token, for probes and scrapers.
+var publicPaths = map[string]bool{
+	"/health":  true,
+	"/version": true,
+	"/metrics": true,
+}
+
+// ParseUsers reads users from name=role:sha256hex[:tenant] entries, where
+// the hash is the hex SHA-256 of the user's token
+// (`printf %s "$TOKEN" | sha256sum`). "viewer", the former name of the
+// reader role, is still accepted.
+func ParseUsers(entries map[string]string) ([]User, error) {
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	users := make([]User, 0, len(entries))
+	for _, name := range names {
+		role, hash, ok := strings.Cut(entries[name], ":")
+		if !ok {
+			return nil, fmt.Errorf("user %s: want role:sha256hex", name)
+		}
+		hash, tenant, scoped := strings.Cut(hash, ":")
+		if scoped && tenant == "" {
+			return nil, fmt.Errorf("user %s: empty tenant", name)
+		}
+		if role == "viewer" {
+			role = string(RoleReader)
+		}
+		user := User{Name: name, Role: Role(role), Tenant: tenant}
+		if _, ok := roleRanks[user.Role]; !ok {
+			return nil, fmt.Errorf("user %s: role must be %q, %q or %q", name, RoleReader, RoleOperator, RoleAdmin)
+		}
+		raw, err := hex.DecodeString(hash)
+		if err != nil || len(raw) != sha256.Size {
+			return nil, fmt.Errorf("user %s: token hash must be 64 hex characters", name)
+		}
+		copy(user.TokenHash[:], raw)
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// authenticate requires a bearer token of a known user on every non-public
+// path once users are configured. Readers are limited to reads outside
+// /api/<version>/admin/; routes may require more with requires.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	if len(s.users) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if publicPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		user := s.lookupUser(r)
+		if user == nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="solana-indexer"`)
+			writeProblem(w, r, NewProblem(CodeUnauthorized, "a valid bearer token is required"))
+			return
+		}
+		if role := requiredRole(r); !user.Role.Allows(role) {
+			writeProblem(w, r, NewProblem(CodeForbidden, "this request requires the "+string(role)+" role"))
+			return
+		}
+		if user.Tenant != "" {
+			if !tenantScoped(r) {
+				writeProblem(w, r, NewProblem(CodeForbidden, "this endpoint is not available to tenant users"))
+				return
+			}
+			r = r.WithContext(repository.WithTenant(r.Context(), user.Tenant))
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userKey{}, user)))
+	})
+}
+
+// requires lets only users with at least role call h, on top of the role
+// authenticate requires of the request.
+func requires(role Role, h handlerFunc) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) *Problem {
+		if user := userFromContext(r.Context()); user != nil && !user.Role.Allows(role) {
+			return NewProblem(CodeForbidden, "this request requires the "+string(role)+" role")
+		}
+		return h(w, r)
+	}
+}
+
+func (s *Server) lookupUser(r *http.Request) *User {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return nil
+	}
+	hash := sha256.Sum256([]byte(token))
+
+	var found *User
+	for i := range s.users {
+		if subtle.ConstantTimeCompare(hash[:], s.users[i].TokenHash[:]) == 1 {
+			found = &s.users[i]
+		}
+	}
+	return found
+}
+
+// tenantScoped reports whether r reads only events, which the repository
+// can restrict to a tenant. Derived data such as token holders or NFTs is
+// shared between programs and stays out of reach of tenant users.
+func tenantScoped(r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	_, rest, ok := splitVersion(r.URL.Path)
+	if !ok {
+		return false
+	}
+	switch {
+	case rest == "/status", rest == "/events", rest == "/analytics/counter-payments":
+		return true
+	case strings.HasPrefix(rest, "/events/"):
+		return true
+	case strings.HasPrefix(rest, "/accounts/") && strings.HasSuffix(rest, "/events"):
+		return true
+	case strings.HasPrefix(rest, "/counters/") && strings.HasSuffix(rest, "/history"):
+		return true
+	case strings.HasPrefix(rest, "/programs/") && strings.HasSuffix(rest, "/changes"):
+		return true
+	case strings.HasPrefix(rest, "/queries/") && strings.HasSuffix(rest, "/results"):
+		return true
+	}
+	return false
+}
+
+func requiredRole(r *http.Request) Role {
+	_, rest, ok := splitVersion(r.URL.Path)
+	if r.Method == http.MethodPost && ok && rest == "/decode" {
+		// Decoding changes nothing.
+		return RoleReader
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return RoleOperator
+	}
+	if ok && strings.HasPrefix(rest, "/admin/") {
+		return RoleOperator
+	}
+	return RoleReader
+}
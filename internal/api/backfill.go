@@ -0,0 +1,58 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+const maxBackfillBody = 4 << 10
+
+// Backfiller backfills slot ranges in the background of the running
+// indexer.
+type Backfiller interface {
+	StartBackfill(fromSlot, toSlot uint64) (*models.BackfillStatus, error)
+	// BackfillStatus returns the last backfill started, or nil.
+	BackfillStatus() *models.BackfillStatus
+}
+
+type backfillRequest struct {
+	FromSlot uint64 `json:"from_slot"`
+	ToSlot   uint64 `json:"to_slot"`
+}
+
+// handleStartBackfill starts a backfill. It answers 409 while another is
+// running.
+func (s *Server) handleStartBackfill(w http.ResponseWriter, r *http.Request) *Problem {
+	if s.backfiller == nil {
+		return NewProblem(CodeNotImplemented, "backfills are not supported")
+	}
+
+	var req backfillRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBackfillBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		return ValidationProblem(FieldError{Field: "body", Message: "must be a JSON slot range: " + err.Error()})
+	}
+	if req.ToSlot > 0 && req.FromSlot > req.ToSlot {
+		return ValidationProblem(FieldError{Field: "from_slot", Message: "must not be after to_slot"})
+	}
+
+	status, err := s.backfiller.StartBackfill(req.FromSlot, req.ToSlot)
+	if err != nil {
+		return NewProblem(CodeConflict, err.Error())
+	}
+	return writeJSON(w, http.StatusAccepted, status)
+}
+
+func (s *Server) handleGetBackfill(w http.ResponseWriter, r *http.Request) *Problem {
+	if s.backfiller == nil {
+		return NewProblem(CodeNotImplemented, "backfills are not supported")
+	}
+	status := s.backfiller.BackfillStatus()
+	if status == nil {
+		return NewProblem(CodeNotFound, "no backfill has been started")
+	}
+	return writeJSON(w, http.StatusOK, status)
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+// handleProgramChanges returns the events of a program stored after a
+// sequence number, oldest first, for consumers that follow the event log
+// and resume from the last sequence they read. Each network numbers the
+// events of a program separately; without a network parameter those of
+// the network of s are returned.
+func (s *Server) handleProgramChanges(w http.ResponseWriter, r *http.Request) *Problem {
+	query := r.URL.Query()
+
+	var errs []FieldError
+	program, err := solana.PublicKeyFromBase58(r.PathValue("program"))
+	if err != nil {
+		errs = append(errs, FieldError{Field: "program", Message: "must be a base58 public key"})
+	}
+	var after uint64
+	if raw := query.Get("after"); raw != "" {
+		after, err = strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			errs = append(errs, FieldError{Field: "after", Message: "must be a non-negative integer"})
+		}
+	}
+	limit := defaultEventsLimit
+	if raw := query.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 || n > maxEventsLimit {
+			errs = append(errs, FieldError{Field: "limit", Message: fmt.Sprintf("must be an integer between 1 and %d", maxEventsLimit)})
+		} else {
+			limit = n
+		}
+	}
+	if len(errs) > 0 {
+		return ValidationProblem(errs...)
+	}
+
+	store, ok := repository.Unwrap(s.repo).(repository.SequenceStore)
+	if !ok {
+		return NewProblem(CodeNotImplemented, "event sequences are not supported by the configured database")
+	}
+	network := query.Get("network")
+	if network == "" {
+		network = s.network
+	}
+	events, err := store.GetEventsAfterSequence(r.Context(), network, program, after, limit)
+	if err != nil {
+		return upstreamProblem(err)
+	}
+	if p := s.decrypt(events...); p != nil {
+		return p
+	}
+
+	// Without new events the consumer polls again from where it was.
+	next := after
+	if len(events) > 0 {
+		if e, ok := events[len(events)-1].(models.Event); ok {
+			next = e.Base().Sequence
+		}
+	}
+	return writeJSON(w, http.StatusOK, map[string]interface{}{
+		"program":    program.String(),
+		"events":     events,
+		"count":      len(events),
+		"next_after": next,
+	})
+}
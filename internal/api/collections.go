@@ -0,0 +1,91 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/analytics"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+// defaultSalesPeriods is how many periods are returned without a from date.
+const defaultSalesPeriods = 12
+
+// handleCollectionStats returns the sales count, volume and floor price of
+// a collection per day or week, oldest first.
+func (s *Server) handleCollectionStats(w http.ResponseWriter, r *http.Request) *Problem {
+	query := r.URL.Query()
+
+	var errs []FieldError
+	collection, err := solana.PublicKeyFromBase58(r.PathValue("collection"))
+	if err != nil {
+		errs = append(errs, FieldError{Field: "collection", Message: "must be a base58 public key"})
+	}
+
+	period := models.SalesPeriod(query.Get("period"))
+	switch period {
+	case "":
+		period = models.SalesPeriodDay
+	case models.SalesPeriodDay, models.SalesPeriodWeek:
+	default:
+		errs = append(errs, FieldError{Field: "period", Message: "must be 'day' or 'week'"})
+	}
+
+	to := period.Start(time.Now())
+	if raw := query.Get("to"); raw != "" {
+		d, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			errs = append(errs, FieldError{Field: "to", Message: "must be a date in YYYY-MM-DD format"})
+		} else {
+			to = period.Start(d)
+		}
+	}
+	days := 1
+	if period == models.SalesPeriodWeek {
+		days = 7
+	}
+	from := to.AddDate(0, 0, -days*(defaultSalesPeriods-1))
+	if raw := query.Get("from"); raw != "" {
+		d, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			errs = append(errs, FieldError{Field: "from", Message: "must be a date in YYYY-MM-DD format"})
+		} else {
+			from = period.Start(d)
+		}
+	}
+	if len(errs) > 0 {
+		return ValidationProblem(errs...)
+	}
+
+	if from.After(to) {
+		return ValidationProblem(FieldError{Field: "from", Message: "must not be after to"})
+	}
+	if to.Sub(from) >= maxAnalyticsDays*24*time.Hour {
+		return ValidationProblem(FieldError{Field: "from", Message: fmt.Sprintf("range must not exceed %d days", maxAnalyticsDays)})
+	}
+
+	store, ok := repository.Unwrap(s.repo).(repository.NftSalesStore)
+	if !ok {
+		return NewProblem(CodeNotImplemented, "collection stats are not supported by the configured database")
+	}
+
+	// The range is inclusive of the whole period containing "to".
+	end := period.Next(to)
+	stats, err := store.GetCollectionStats(r.Context(), collection.String(), period, from, end)
+	if err != nil {
+		return upstreamProblem(err)
+	}
+	periods, totals := analytics.FillSalesPeriods(stats, period, from, end)
+
+	return writeJSON(w, http.StatusOK, map[string]interface{}{
+		"collection": collection.String(),
+		"period":     period,
+		"from":       from.Format(time.DateOnly),
+		"to":         to.Format(time.DateOnly),
+		"periods":    periods,
+		"totals":     totals,
+	})
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// Pauser pauses and resumes the polling of the running indexer.
+type Pauser interface {
+	Pause()
+	Resume()
+	Paused() bool
+}
+
+// Reindexer deletes and indexes again the events of slot ranges in the
+// background of the running indexer.
+type Reindexer interface {
+	StartReindex(fromSlot, toSlot uint64) (*models.BackfillStatus, error)
+}
+
+func (s *Server) handlePause(w http.ResponseWriter, r *http.Request) *Problem {
+	if s.pauser == nil {
+		return NewProblem(CodeNotImplemented, "pausing is not supported")
+	}
+	s.pauser.Pause()
+	return writeJSON(w, http.StatusOK, map[string]bool{"paused": true})
+}
+
+func (s *Server) handleResume(w http.ResponseWriter, r *http.Request) *Problem {
+	if s.pauser == nil {
+		return NewProblem(CodeNotImplemented, "pausing is not supported")
+	}
+	s.pauser.Resume()
+	return writeJSON(w, http.StatusOK, map[string]bool{"paused": false})
+}
+
+// handleStartReindex starts a reindex, whose progress is that of the
+// backfill. It answers 409 while a backfill or reindex is running.
+func (s *Server) handleStartReindex(w http.ResponseWriter, r *http.Request) *Problem {
+	if s.reindexer == nil {
+		return NewProblem(CodeNotImplemented, "reindexing is not supported")
+	}
+
+	var req backfillRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBackfillBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		return ValidationProblem(FieldError{Field: "body", Message: "must be a JSON slot range: " + err.Error()})
+	}
+	var errs []FieldError
+	if req.FromSlot == 0 {
+		errs = append(errs, FieldError{Field: "from_slot", Message: "is required"})
+	}
+	if req.ToSlot == 0 {
+		errs = append(errs, FieldError{Field: "to_slot", Message: "is required"})
+	} else if req.FromSlot > req.ToSlot {
+		errs = append(errs, FieldError{Field: "from_slot", Message: "must not be after to_slot"})
+	}
+	if len(errs) > 0 {
+		return ValidationProblem(errs...)
+	}
+
+	status, err := s.reindexer.StartReindex(req.FromSlot, req.ToSlot)
+	if err != nil {
+		return NewProblem(CodeConflict, err.Error())
+	}
+	return writeJSON(w, http.StatusAccepted, status)
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/analytics"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+const (
+	// maxCounterHistoryEvents caps the events read for a history; past it
+	// the oldest changes of the range are left out.
+	maxCounterHistoryEvents = 10_000
+	// maxCounterBuckets caps the buckets of a downsampled history.
+	maxCounterBuckets = 2000
+)
+
+// handleCounterHistory returns the values of a counter over a date range,
+// the last 7 days by default: every change, or with interval, buckets of
+// the open, close, min and max values for charting.
+func (s *Server) handleCounterHistory(w http.ResponseWriter, r *http.Request) *Problem {
+	query := r.URL.Query()
+
+	var errs []FieldError
+	counter, err := solana.PublicKeyFromBase58(r.PathValue("pubkey"))
+	if err != nil {
+		errs = append(errs, FieldError{Field: "pubkey", Message: "must be a base58 public key"})
+	}
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if raw := query.Get("to"); raw != "" {
+		d, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			errs = append(errs, FieldError{Field: "to", Message: "must be a date in YYYY-MM-DD format"})
+		} else {
+			to = d
+		}
+	}
+	from := to.AddDate(0, 0, -(defaultAnalyticsDays - 1))
+	if raw := query.Get("from"); raw != "" {
+		d, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			errs = append(errs, FieldError{Field: "from", Message: "must be a date in YYYY-MM-DD format"})
+		} else {
+			from = d
+		}
+	}
+	var interval time.Duration
+	if raw := query.Get("interval"); raw != "" {
+		interval, err = time.ParseDuration(raw)
+		if err != nil || interval < time.Minute || interval%time.Minute != 0 {
+			errs = append(errs, FieldError{Field: "interval", Message: "must be a whole number of minutes, e.g. 15m or 24h"})
+		}
+	}
+	deployment, errs := s.parseDeployment(query, errs)
+	if len(errs) > 0 {
+		return ValidationProblem(errs...)
+	}
+
+	if from.After(to) {
+		return ValidationProblem(FieldError{Field: "from", Message: "must not be after to"})
+	}
+	if to.Sub(from) >= maxAnalyticsDays*24*time.Hour {
+		return ValidationProblem(FieldError{Field: "from", Message: fmt.Sprintf("range must not exceed %d days", maxAnalyticsDays)})
+	}
+	// The range is inclusive of the whole "to" day.
+	end := to.AddDate(0, 0, 1)
+	if interval > 0 && end.Sub(from)/interval > maxCounterBuckets {
+		return ValidationProblem(FieldError{Field: "interval", Message: fmt.Sprintf("range must not exceed %d intervals", maxCounterBuckets)})
+	}
+
+	// Read back from the newest event, so the first event before the range
+	// gives the value the range starts with.
+	var (
+		changes   []analytics.CounterChange
+		start     *uint64
+		scanned   int
+		truncated bool
+	)
+	opts := repository.AccountEventsOptions{
+		PageOptions: repository.PageOptions{Limit: maxEventsLimit, Deployment: deployment},
+		EventTypes:  analytics.CounterEventTypes,
+	}
+scan:
+	for {
+		page, err := s.repo.GetEventsByAccount(r.Context(), counter, opts)
+		if err != nil {
+			return upstreamProblem(err)
+		}
+		for _, event := range page.Events {
+			scanned++
+			c, change, ok := analytics.CounterChangeOf(event)
+			if !ok || c != counter || !change.Time.Before(end) {
+				continue
+			}
+			if change.Time.Before(from) {
+				start = &change.Value
+				break scan
+			}
+			changes = append(changes, change)
+		}
+		if page.Next == nil {
+			break
+		}
+		if scanned >= maxCounterHistoryEvents {
+			truncated = true
+			break
+		}
+		opts.After = page.Next
+	}
+	slices.Reverse(changes)
+
+	body := map[string]interface{}{
+		"counter":     counter.String(),
+		"from":        from.Format(time.DateOnly),
+		"to":          to.Format(time.DateOnly),
+		"start_value": start,
+		"truncated":   truncated,
+	}
+	if interval > 0 {
+		buckets := analytics.DownsampleCounter(changes, start, from, end, interval)
+		body["interval"] = interval.String()
+		body["buckets"] = buckets
+		body["count"] = len(buckets)
+	} else {
+		if changes == nil {
+			changes = []analytics.CounterChange{}
+		}
+		body["changes"] = changes
+		body["count"] = len(changes)
+	}
+	return writeJSON(w, http.StatusOK, body)
+}
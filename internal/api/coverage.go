@@ -0,0 +1,83 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+// defaultCoverageDays is how many days are returned without a from date.
+const defaultCoverageDays = 30
+
+type dailyCoverage struct {
+	models.DailyDecoderCoverage
+	Ratio float64 `json:"ratio"`
+}
+
+type coverageTotals struct {
+	models.DecoderCoverage
+	Ratio float64 `json:"ratio"`
+}
+
+// handleDecoderCoverage returns the daily decoder coverage report: per UTC
+// day, how many "Program data:" payloads were found, decoded, unknown and
+// failed, and the share that decoded.
+func (s *Server) handleDecoderCoverage(w http.ResponseWriter, r *http.Request) *Problem {
+	query := r.URL.Query()
+
+	var errs []FieldError
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if raw := query.Get("to"); raw != "" {
+		d, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			errs = append(errs, FieldError{Field: "to", Message: "must be a date in YYYY-MM-DD format"})
+		} else {
+			to = d
+		}
+	}
+	from := to.AddDate(0, 0, -(defaultCoverageDays - 1))
+	if raw := query.Get("from"); raw != "" {
+		d, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			errs = append(errs, FieldError{Field: "from", Message: "must be a date in YYYY-MM-DD format"})
+		} else {
+			from = d
+		}
+	}
+	if len(errs) > 0 {
+		return ValidationProblem(errs...)
+	}
+
+	if from.After(to) {
+		return ValidationProblem(FieldError{Field: "from", Message: "must not be after to"})
+	}
+	if to.Sub(from) >= maxAnalyticsDays*24*time.Hour {
+		return ValidationProblem(FieldError{Field: "from", Message: fmt.Sprintf("range must not exceed %d days", maxAnalyticsDays)})
+	}
+
+	store, ok := repository.Unwrap(s.repo).(repository.CoverageStore)
+	if !ok {
+		return NewProblem(CodeNotImplemented, "decoder coverage reports are not supported by the configured database")
+	}
+
+	stored, err := store.GetDecoderCoverage(r.Context(), from.Format(time.DateOnly), to.Format(time.DateOnly))
+	if err != nil {
+		return upstreamProblem(err)
+	}
+	days := make([]dailyCoverage, 0, len(stored))
+	var totals models.DecoderCoverage
+	for _, d := range stored {
+		days = append(days, dailyCoverage{DailyDecoderCoverage: d, Ratio: d.Ratio()})
+		totals.Add(d.DecoderCoverage)
+	}
+
+	return writeJSON(w, http.StatusOK, map[string]interface{}{
+		"from":   from.Format(time.DateOnly),
+		"to":     to.Format(time.DateOnly),
+		"days":   days,
+		"totals": coverageTotals{DecoderCoverage: totals, Ratio: totals.Ratio()},
+	})
+}
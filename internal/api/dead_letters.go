@@ -0,0 +1,60 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/failure"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+// handleDeadLetters lists the transactions the pipeline failed on, most
+// recently failed first, optionally of one error kind.
+func (s *Server) handleDeadLetters(w http.ResponseWriter, r *http.Request) *Problem {
+	query := r.URL.Query()
+
+	var errs []FieldError
+	kind := query.Get("kind")
+	if kind != "" && !knownFailureKind(kind) {
+		errs = append(errs, FieldError{Field: "kind", Message: "must be rpc, decode, storage, reorg or other"})
+	}
+	limit := defaultEventsLimit
+	if raw := query.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 || n > maxEventsLimit {
+			errs = append(errs, FieldError{Field: "limit", Message: fmt.Sprintf("must be an integer between 1 and %d", maxEventsLimit)})
+		}
+		limit = n
+	}
+	if len(errs) > 0 {
+		return ValidationProblem(errs...)
+	}
+
+	store, ok := repository.Unwrap(s.repo).(repository.DeadLetterStore)
+	if !ok {
+		return NewProblem(CodeNotImplemented, "dead letters are not supported by the configured database")
+	}
+	letters, err := store.GetDeadLetters(r.Context(), kind, limit)
+	if err != nil {
+		return upstreamProblem(err)
+	}
+	if letters == nil {
+		letters = []models.DeadLetter{}
+	}
+
+	return writeJSON(w, http.StatusOK, map[string]interface{}{
+		"dead_letters": letters,
+		"count":        len(letters),
+	})
+}
+
+func knownFailureKind(kind string) bool {
+	for _, k := range failure.Kinds {
+		if string(k) == kind {
+			return true
+		}
+	}
+	return false
+}
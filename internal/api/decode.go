@@ -0,0 +1,64 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+const maxDecodeBody = 1 << 20
+
+// Decoder decodes events with the decoders of the running indexer.
+type Decoder interface {
+	DecodeEventData(data []byte) models.DecodedEvent
+	DecodeTransaction(tx *rpc.GetTransactionResult) ([]models.DecodedEvent, error)
+}
+
+// decodeRequest holds either the base64 bytes of one event or a
+// getTransaction result.
+type decodeRequest struct {
+	Data        string          `json:"data"`
+	Transaction json.RawMessage `json:"transaction"`
+}
+
+// handleDecode decodes the event bytes or transaction of the request body
+// without indexing anything.
+func (s *Server) handleDecode(w http.ResponseWriter, r *http.Request) *Problem {
+	if s.decoder == nil {
+		return NewProblem(CodeNotImplemented, "decoding is not supported")
+	}
+
+	var req decodeRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxDecodeBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		return ValidationProblem(FieldError{Field: "body", Message: "must be a JSON decode request: " + err.Error()})
+	}
+	if (req.Data == "") == (len(req.Transaction) == 0) {
+		return ValidationProblem(FieldError{Field: "body", Message: "must have either data or transaction"})
+	}
+
+	var events []models.DecodedEvent
+	if req.Data != "" {
+		data, err := base64.StdEncoding.DecodeString(req.Data)
+		if err != nil {
+			return ValidationProblem(FieldError{Field: "data", Message: "must be base64: " + err.Error()})
+		}
+		events = []models.DecodedEvent{s.decoder.DecodeEventData(data)}
+	} else {
+		var tx rpc.GetTransactionResult
+		if err := json.Unmarshal(req.Transaction, &tx); err != nil {
+			return ValidationProblem(FieldError{Field: "transaction", Message: "must be a getTransaction result: " + err.Error()})
+		}
+		var err error
+		if events, err = s.decoder.DecodeTransaction(&tx); err != nil {
+			return ValidationProblem(FieldError{Field: "transaction", Message: err.Error()})
+		}
+	}
+	return writeJSON(w, http.StatusOK, map[string]interface{}{
+		"events": events,
+	})
+}
@@ -0,0 +1,264 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/accountmon"
+	"github.com/lugondev/go-indexer-solana-starter/internal/failure"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/seen"
+	"github.com/lugondev/go-indexer-solana-starter/internal/spool"
+	"github.com/lugondev/go-indexer-solana-starter/internal/watchdog"
+	solanaClient "github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// handleMetrics serves gauges in the Prometheus text exposition format.
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) *Problem {
+	var b strings.Builder
+
+	writeMetricHeader(&b, "solana_indexer_current_slot", "Last slot processed by the indexer.")
+	fmt.Fprintf(&b, "solana_indexer_current_slot %d\n", s.status.GetCurrentSlot())
+
+	lags := s.consumerLag()
+	if len(lags) > 0 {
+		writeMetricHeader(&b, "solana_indexer_sink_backlog_messages", "Messages published to a sink destination but not yet received by a consumer.")
+		for _, lag := range lags {
+			fmt.Fprintf(&b, "solana_indexer_sink_backlog_messages{%s} %d\n", lagLabels(lag.Sink, lag.Target), lag.Backlog)
+		}
+		writeMetricHeader(&b, "solana_indexer_sink_in_flight_messages", "Messages received by a consumer but not yet acknowledged.")
+		for _, lag := range lags {
+			fmt.Fprintf(&b, "solana_indexer_sink_in_flight_messages{%s} %d\n", lagLabels(lag.Sink, lag.Target), lag.InFlight)
+		}
+		writeMetricHeader(&b, "solana_indexer_sink_lag_up", "Whether the last consumer lag check succeeded.")
+		for _, lag := range lags {
+			up := 1
+			if lag.Error != "" {
+				up = 0
+			}
+			fmt.Fprintf(&b, "solana_indexer_sink_lag_up{%s} %d\n", lagLabels(lag.Sink, lag.Target), up)
+		}
+	}
+
+	if series := s.streamWindows(); len(series) > 0 {
+		writeMetricHeader(&b, "solana_indexer_stream_window_value", "Value of a streaming window metric; window is current (still open) or previous (last closed).")
+		for _, m := range series {
+			for i, label := range []string{"current", "previous"} {
+				if i < len(m.Windows) {
+					fmt.Fprintf(&b, "solana_indexer_stream_window_value{metric=\"%s\",window=\"%s\"} %g\n", labelEscaper.Replace(m.Name), label, m.Windows[i].Value)
+				}
+			}
+		}
+	}
+
+	if s.rpc != nil {
+		writeRPCMetrics(&b, s.rpc.RPCStatus())
+	}
+	if s.seen != nil {
+		if stats := s.seen.SeenStats(); stats != nil {
+			writeSeenMetrics(&b, *stats)
+		}
+	}
+	if s.buffer != nil {
+		if stats := s.buffer.BufferStats(); stats != nil {
+			writeBufferMetrics(&b, *stats)
+		}
+	}
+	if s.pipeline != nil {
+		if queues := s.pipeline.PipelineQueues(); len(queues) > 0 {
+			writePipelineMetrics(&b, queues)
+		}
+	}
+	if s.watermarks != nil {
+		writeWatermarkMetrics(&b, s.watermarks.Watermarks())
+	}
+	if s.failures != nil {
+		writeErrorMetrics(&b, s.failures.PipelineErrors())
+	}
+	if s.coverage != nil {
+		writeCoverageMetrics(&b, s.coverage.DecoderCoverage())
+	}
+	if s.accounts != nil {
+		if alerts := s.accounts.AccountAlerts(); alerts != nil {
+			writeAccountAlertMetrics(&b, alerts)
+		}
+	}
+	if s.watchdog != nil {
+		if alerts := s.watchdog.WatchdogAlerts(); alerts != nil {
+			writeWatchdogMetrics(&b, alerts)
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write([]byte(b.String()))
+	return nil
+}
+
+func writeRPCMetrics(b *strings.Builder, status solanaClient.RPCStatus) {
+	counters := []struct {
+		name, help string
+		value      func(solanaClient.MethodStats) string
+	}{
+		{"solana_indexer_rpc_requests_total", "RPC calls made, by method.", func(m solanaClient.MethodStats) string { return fmt.Sprint(m.Calls) }},
+		{"solana_indexer_rpc_errors_total", "RPC calls that failed, by method.", func(m solanaClient.MethodStats) string { return fmt.Sprint(m.Errors) }},
+		{"solana_indexer_rpc_rejected_total", "RPC calls not made because the circuit breaker was open, by method.", func(m solanaClient.MethodStats) string { return fmt.Sprint(m.Rejected) }},
+		{"solana_indexer_rpc_request_duration_seconds_total", "Time spent in RPC calls, by method.", func(m solanaClient.MethodStats) string { return fmt.Sprintf("%g", m.DurationSeconds) }},
+	}
+	if len(status.Methods) > 0 {
+		for _, c := range counters {
+			writeCounterHeader(b, c.name, c.help)
+			for _, m := range status.Methods {
+				fmt.Fprintf(b, "%s{method=\"%s\"} %s\n", c.name, labelEscaper.Replace(m.Method), c.value(m))
+			}
+		}
+	}
+
+	if len(status.Endpoints) > 0 {
+		writeCounterHeader(b, "solana_indexer_rpc_endpoint_requests_total", "RPC calls made, by endpoint.")
+		for _, e := range status.Endpoints {
+			fmt.Fprintf(b, "solana_indexer_rpc_endpoint_requests_total{endpoint=\"%s\"} %d\n", labelEscaper.Replace(e.Name), e.Requests)
+		}
+		writeCounterHeader(b, "solana_indexer_rpc_endpoint_budget_skipped_total", "Times a call found no room in the request budget of an endpoint.")
+		for _, e := range status.Endpoints {
+			fmt.Fprintf(b, "solana_indexer_rpc_endpoint_budget_skipped_total{endpoint=\"%s\"} %d\n", labelEscaper.Replace(e.Name), e.Skipped)
+		}
+		writeMetricHeader(b, "solana_indexer_rpc_endpoint_budget_used_today", "RPC calls made to an endpoint this UTC day.")
+		for _, e := range status.Endpoints {
+			fmt.Fprintf(b, "solana_indexer_rpc_endpoint_budget_used_today{endpoint=\"%s\"} %d\n", labelEscaper.Replace(e.Name), e.UsedToday)
+		}
+		writeMetricHeader(b, "solana_indexer_rpc_endpoint_budget_per_day", "Daily request budget of an endpoint; absent when unlimited.")
+		for _, e := range status.Endpoints {
+			if e.PerDay > 0 {
+				fmt.Fprintf(b, "solana_indexer_rpc_endpoint_budget_per_day{endpoint=\"%s\"} %d\n", labelEscaper.Replace(e.Name), e.PerDay)
+			}
+		}
+	}
+
+	if status.Circuit != nil {
+		writeMetricHeader(b, "solana_indexer_rpc_circuit_open", "Whether the RPC circuit breaker is open or half open.")
+		open := 0
+		if status.Circuit.State != solanaClient.BreakerClosed {
+			open = 1
+		}
+		fmt.Fprintf(b, "solana_indexer_rpc_circuit_open %d\n", open)
+	}
+
+	if sched := status.Scheduler; sched != nil {
+		writeMetricHeader(b, "solana_indexer_rpc_scheduler_limit", "RPC calls currently allowed in flight; shrinks while calls fail.")
+		fmt.Fprintf(b, "solana_indexer_rpc_scheduler_limit %d\n", sched.Limit)
+		writeMetricHeader(b, "solana_indexer_rpc_scheduler_waiting", "RPC calls waiting for a slot, by priority.")
+		for _, p := range sched.Priorities {
+			fmt.Fprintf(b, "solana_indexer_rpc_scheduler_waiting{priority=\"%s\"} %d\n", p.Priority, p.Waiting)
+		}
+		writeCounterHeader(b, "solana_indexer_rpc_scheduler_granted_total", "RPC call slots granted, by priority.")
+		for _, p := range sched.Priorities {
+			fmt.Fprintf(b, "solana_indexer_rpc_scheduler_granted_total{priority=\"%s\"} %d\n", p.Priority, p.Granted)
+		}
+	}
+}
+
+func writeSeenMetrics(b *strings.Builder, stats seen.Stats) {
+	counters := []struct {
+		name, help string
+		value      uint64
+	}{
+		{"solana_indexer_seen_checks_total", "Signatures checked against the seen signature cache.", stats.Checks},
+		{"solana_indexer_seen_recent_hits_total", "Signatures found in the recent signature LRU.", stats.RecentHits},
+		{"solana_indexer_seen_bloom_positives_total", "Signatures the bloom filter reported as possibly seen.", stats.BloomPositives},
+		{"solana_indexer_seen_false_positives_total", "Bloom filter positives the event store did not confirm.", stats.FalsePositives},
+	}
+	for _, c := range counters {
+		writeCounterHeader(b, c.name, c.help)
+		fmt.Fprintf(b, "%s %d\n", c.name, c.value)
+	}
+	writeMetricHeader(b, "solana_indexer_seen_bloom_signatures", "Signatures added to the bloom filter.")
+	fmt.Fprintf(b, "solana_indexer_seen_bloom_signatures %d\n", stats.BloomCount)
+	writeMetricHeader(b, "solana_indexer_seen_bloom_estimated_false_positive_rate", "Expected bloom filter false positive rate at its current fill.")
+	fmt.Fprintf(b, "solana_indexer_seen_bloom_estimated_false_positive_rate %g\n", stats.EstimatedFalsePositiveRate)
+}
+
+func writeBufferMetrics(b *strings.Builder, stats spool.Stats) {
+	writeMetricHeader(b, "solana_indexer_offline_buffer_events", "Events buffered on disk while the database is unreachable.")
+	fmt.Fprintf(b, "solana_indexer_offline_buffer_events %d\n", stats.Records)
+	writeMetricHeader(b, "solana_indexer_offline_buffer_bytes", "Size of the events buffered on disk.")
+	fmt.Fprintf(b, "solana_indexer_offline_buffer_bytes %d\n", stats.Bytes)
+	writeMetricHeader(b, "solana_indexer_offline_buffer_max_bytes", "Buffer size at which indexing pauses.")
+	fmt.Fprintf(b, "solana_indexer_offline_buffer_max_bytes %d\n", stats.MaxBytes)
+}
+
+func writePipelineMetrics(b *strings.Builder, queues []models.PipelineQueue) {
+	writeMetricHeader(b, "solana_indexer_pipeline_queue_depth", "Transactions waiting for a pipeline stage, by program and stage: decode or store.")
+	for _, q := range queues {
+		fmt.Fprintf(b, "solana_indexer_pipeline_queue_depth{program=\"%s\",stage=\"%s\"} %d\n", q.Program, q.Stage, q.Depth)
+	}
+	writeMetricHeader(b, "solana_indexer_pipeline_queue_capacity", "Transactions a pipeline queue holds before the stage in front of it waits.")
+	for _, q := range queues {
+		fmt.Fprintf(b, "solana_indexer_pipeline_queue_capacity{program=\"%s\",stage=\"%s\"} %d\n", q.Program, q.Stage, q.Capacity)
+	}
+}
+
+func writeWatermarkMetrics(b *strings.Builder, w models.Watermarks) {
+	writeMetricHeader(b, "solana_indexer_watermark_slot", "Slot watermarks, by level: processed (newest indexed), confirmed (cluster) or finalized (data complete and final).")
+	fmt.Fprintf(b, "solana_indexer_watermark_slot{level=\"processed\"} %d\n", w.Processed)
+	fmt.Fprintf(b, "solana_indexer_watermark_slot{level=\"confirmed\"} %d\n", w.Confirmed)
+	fmt.Fprintf(b, "solana_indexer_watermark_slot{level=\"finalized\"} %d\n", w.Finalized)
+}
+
+func writeErrorMetrics(b *strings.Builder, counts map[failure.Kind]uint64) {
+	writeCounterHeader(b, "solana_indexer_pipeline_errors_total", "Pipeline errors, by kind: rpc, decode, storage, reorg or other.")
+	for _, kind := range failure.Kinds {
+		fmt.Fprintf(b, "solana_indexer_pipeline_errors_total{kind=\"%s\"} %d\n", kind, counts[kind])
+	}
+}
+
+func writeCoverageMetrics(b *strings.Builder, c models.DecoderCoverage) {
+	writeCounterHeader(b, "solana_indexer_decoder_transactions_total", "Starter program transactions whose program data payloads were decoded.")
+	fmt.Fprintf(b, "solana_indexer_decoder_transactions_total %d\n", c.Transactions)
+	writeCounterHeader(b, "solana_indexer_decoder_payloads_total", "Program data payloads, by result: found, decoded, unknown (not a known event) or failed.")
+	for _, r := range []struct {
+		result string
+		value  uint64
+	}{{"found", c.Found}, {"decoded", c.Decoded}, {"unknown", c.Unknown}, {"failed", c.Failed}} {
+		fmt.Fprintf(b, "solana_indexer_decoder_payloads_total{result=\"%s\"} %d\n", r.result, r.value)
+	}
+	writeMetricHeader(b, "solana_indexer_decoder_coverage_ratio", "Share of the program data payloads found that decoded.")
+	fmt.Fprintf(b, "solana_indexer_decoder_coverage_ratio %g\n", c.Ratio())
+}
+
+func writeAccountAlertMetrics(b *strings.Builder, alerts []accountmon.Alert) {
+	counts := map[accountmon.AlertKind]int{accountmon.AlertRent: 0, accountmon.AlertSize: 0}
+	for _, a := range alerts {
+		counts[a.Kind]++
+	}
+	writeMetricHeader(b, "solana_indexer_account_alerts", "Program accounts short of rent or close to the size limit at the last sampling, by kind.")
+	for _, kind := range []accountmon.AlertKind{accountmon.AlertRent, accountmon.AlertSize} {
+		fmt.Fprintf(b, "solana_indexer_account_alerts{kind=\"%s\"} %d\n", kind, counts[kind])
+	}
+}
+
+func writeWatchdogMetrics(b *strings.Builder, alerts []watchdog.Alert) {
+	writeMetricHeader(b, "solana_indexer_watchdog_alert", "Whether the watchdog alert of a kind is raised.")
+	for _, kind := range watchdog.AlertKinds {
+		raised := 0
+		if slices.ContainsFunc(alerts, func(a watchdog.Alert) bool { return a.Kind == kind }) {
+			raised = 1
+		}
+		fmt.Fprintf(b, "solana_indexer_watchdog_alert{kind=\"%s\"} %d\n", kind, raised)
+	}
+}
+
+func writeMetricHeader(b *strings.Builder, name, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+}
+
+func writeCounterHeader(b *strings.Builder, name, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func lagLabels(sinkName, target string) string {
+	return fmt.Sprintf(`sink="%s",target="%s"`, labelEscaper.Replace(sinkName), labelEscaper.Replace(target))
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+func (s *Server) nftStore() (repository.NftMetadataStore, *Problem) {
+	store, ok := repository.Unwrap(s.repo).(repository.NftMetadataStore)
+	if !ok {
+		return nil, NewProblem(CodeNotImplemented, "NFT metadata is not supported by the configured database")
+	}
+	return store, nil
+}
+
+// handleSearchNfts matches fetched NFT metadata by free text (q), exact
+// attributes (attribute=trait:value, repeatable) and collection.
+func (s *Server) handleSearchNfts(w http.ResponseWriter, r *http.Request) *Problem {
+	query := r.URL.Query()
+
+	var errs []FieldError
+	search := repository.NftSearch{
+		Text:       strings.TrimSpace(query.Get("q")),
+		Collection: query.Get("collection"),
+		Limit:      defaultEventsLimit,
+	}
+	for _, raw := range query["attribute"] {
+		trait, value, ok := strings.Cut(raw, ":")
+		if !ok || trait == "" {
+			errs = append(errs, FieldError{Field: "attribute", Message: fmt.Sprintf("%q must be trait:value", raw)})
+			continue
+		}
+		search.Attributes = append(search.Attributes, models.NftAttribute{TraitType: trait, Value: value})
+	}
+	if search.Collection != "" {
+		if _, err := solana.PublicKeyFromBase58(search.Collection); err != nil {
+			errs = append(errs, FieldError{Field: "collection", Message: "must be a base58 public key"})
+		}
+	}
+	if raw := query.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 || n > maxEventsLimit {
+			errs = append(errs, FieldError{Field: "limit", Message: fmt.Sprintf("must be an integer between 1 and %d", maxEventsLimit)})
+		} else {
+			search.Limit = n
+		}
+	}
+	if search.Text == "" && len(search.Attributes) == 0 && search.Collection == "" {
+		errs = append(errs, FieldError{Field: "q", Message: "q, attribute or collection is required"})
+	}
+	if len(errs) > 0 {
+		return ValidationProblem(errs...)
+	}
+
+	store, p := s.nftStore()
+	if p != nil {
+		return p
+	}
+	nfts, err := store.SearchNfts(r.Context(), search)
+	if err != nil {
+		return upstreamProblem(err)
+	}
+	if nfts == nil {
+		nfts = []models.NftMetadata{}
+	}
+
+	return writeJSON(w, http.StatusOK, map[string]interface{}{
+		"nfts":  nfts,
+		"count": len(nfts),
+	})
+}
+
+func (s *Server) handleGetNft(w http.ResponseWriter, r *http.Request) *Problem {
+	mint := r.PathValue("mint")
+	if _, err := solana.PublicKeyFromBase58(mint); err != nil {
+		return ValidationProblem(FieldError{Field: "mint", Message: "must be a base58 public key"})
+	}
+
+	store, p := s.nftStore()
+	if p != nil {
+		return p
+	}
+	nft, err := store.GetNftMetadata(r.Context(), mint)
+	if err != nil {
+		return upstreamProblem(err)
+	}
+	if nft == nil {
+		return NewProblem(CodeNotFound, "no metadata for nft "+mint)
+	}
+
+	return writeJSON(w, http.StatusOK, nft)
+}
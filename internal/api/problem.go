@@ -0,0 +1,101 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const problemContentType = "application/problem+json"
+
+// ErrorCode is a stable, machine-readable identifier for a class of API
+// failure. Clients should branch on Code rather than on Title or Detail.
+type ErrorCode string
+
+const (
+	CodeValidation          ErrorCode = "VALIDATION_ERROR"
+	CodeUnauthorized        ErrorCode = "UNAUTHORIZED"
+	CodeForbidden           ErrorCode = "FORBIDDEN"
+	CodeNotFound            ErrorCode = "NOT_FOUND"
+	CodeMethodNotAllowed    ErrorCode = "METHOD_NOT_ALLOWED"
+	CodeConflict            ErrorCode = "CONFLICT"
+	CodeRateLimited         ErrorCode = "RATE_LIMITED"
+	CodeUpstreamUnavailable ErrorCode = "UPSTREAM_UNAVAILABLE"
+	CodeNotImplemented      ErrorCode = "NOT_IMPLEMENTED"
+	CodeInternal            ErrorCode = "INTERNAL_ERROR"
+)
+
+var problemStatus = map[ErrorCode]int{
+	CodeValidation:          http.StatusBadRequest,
+	CodeUnauthorized:        http.StatusUnauthorized,
+	CodeForbidden:           http.StatusForbidden,
+	CodeNotFound:            http.StatusNotFound,
+	CodeMethodNotAllowed:    http.StatusMethodNotAllowed,
+	CodeConflict:            http.StatusConflict,
+	CodeRateLimited:         http.StatusTooManyRequests,
+	CodeUpstreamUnavailable: http.StatusServiceUnavailable,
+	CodeNotImplemented:      http.StatusNotImplemented,
+	CodeInternal:            http.StatusInternalServerError,
+}
+
+// Problem is an RFC 7807 problem details document extended with a typed
+// code and optional per-field validation errors.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     ErrorCode    `json:"code"`
+	Errors   []FieldError `json:"errors,omitempty"`
+
+	retryAfter time.Duration
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func NewProblem(code ErrorCode, detail string) *Problem {
+	status, ok := problemStatus[code]
+	if !ok {
+		code, status = CodeInternal, http.StatusInternalServerError
+	}
+	return &Problem{
+		Type:   "urn:solana-indexer:problem:" + strings.ToLower(string(code)),
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+func ValidationProblem(errs ...FieldError) *Problem {
+	p := NewProblem(CodeValidation, "request parameters are invalid")
+	p.Errors = errs
+	return p
+}
+
+func (p *Problem) WithRetryAfter(d time.Duration) *Problem {
+	p.retryAfter = d
+	return p
+}
+
+func (p *Problem) Error() string {
+	return string(p.Code) + ": " + p.Detail
+}
+
+func writeProblem(w http.ResponseWriter, r *http.Request, p *Problem) {
+	if p.Instance == "" && r != nil {
+		p.Instance = r.URL.Path
+	}
+	if p.retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(p.retryAfter.Round(time.Second).Seconds())))
+	}
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
@@ -0,0 +1,236 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+	"github.com/lugondev/go-indexer-solana-starter/internal/savedquery"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const maxQueryFields = 50
+
+type savedQueryRequest struct {
+	Description string                   `json:"description"`
+	Filter      models.SavedQueryFilter  `json:"filter"`
+	Order       string                   `json:"order"`
+	Fields      []string                 `json:"fields"`
+	Limit       int                      `json:"limit"`
+	Params      []models.SavedQueryParam `json:"params"`
+}
+
+func (s *Server) savedQueryStore() (repository.SavedQueryStore, *Problem) {
+	store, ok := repository.Unwrap(s.repo).(repository.SavedQueryStore)
+	if !ok {
+		return nil, NewProblem(CodeNotImplemented, "saved queries are not supported by the configured database")
+	}
+	return store, nil
+}
+
+func (s *Server) handleListQueries(w http.ResponseWriter, r *http.Request) *Problem {
+	store, p := s.savedQueryStore()
+	if p != nil {
+		return p
+	}
+
+	queries, err := store.GetQueries(r.Context())
+	if err != nil {
+		return upstreamProblem(err)
+	}
+	if queries == nil {
+		queries = []models.SavedQuery{}
+	}
+
+	return writeJSON(w, http.StatusOK, map[string]interface{}{
+		"queries": queries,
+		"count":   len(queries),
+	})
+}
+
+func (s *Server) handleGetQuery(w http.ResponseWriter, r *http.Request) *Problem {
+	query, p := s.savedQuery(r)
+	if p != nil {
+		return p
+	}
+	return writeJSON(w, http.StatusOK, query)
+}
+
+func (s *Server) savedQuery(r *http.Request) (*models.SavedQuery, *Problem) {
+	name := r.PathValue("name")
+	store, p := s.savedQueryStore()
+	if p != nil {
+		return nil, p
+	}
+
+	query, err := store.GetQuery(r.Context(), name)
+	if err != nil {
+		return nil, upstreamProblem(err)
+	}
+	if query == nil {
+		return nil, NewProblem(CodeNotFound, "no saved query named "+name)
+	}
+	return query, nil
+}
+
+// handlePutQuery creates or replaces a saved query.
+func (s *Server) handlePutQuery(w http.ResponseWriter, r *http.Request) *Problem {
+	name := r.PathValue("name")
+	if !reportNamePattern.MatchString(name) {
+		return ValidationProblem(FieldError{Field: "name", Message: "must be 1 to 64 letters, digits, '-' or '_'"})
+	}
+
+	var req savedQueryRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxReportBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		return ValidationProblem(FieldError{Field: "body", Message: "must be a JSON query definition: " + err.Error()})
+	}
+
+	now := time.Now().UTC()
+	query := &models.SavedQuery{
+		Name:        name,
+		Description: req.Description,
+		Filter:      req.Filter,
+		Order:       req.Order,
+		Fields:      req.Fields,
+		Limit:       req.Limit,
+		Params:      req.Params,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if errs := validateSavedQuery(query); len(errs) > 0 {
+		return ValidationProblem(errs...)
+	}
+
+	store, p := s.savedQueryStore()
+	if p != nil {
+		return p
+	}
+	existing, err := store.GetQuery(r.Context(), name)
+	if err != nil {
+		return upstreamProblem(err)
+	}
+	status := http.StatusCreated
+	if existing != nil {
+		query.CreatedAt = existing.CreatedAt
+		status = http.StatusOK
+	}
+	if err := store.SaveQuery(r.Context(), query); err != nil {
+		return upstreamProblem(err)
+	}
+
+	return writeJSON(w, status, query)
+}
+
+func (s *Server) handleDeleteQuery(w http.ResponseWriter, r *http.Request) *Problem {
+	name := r.PathValue("name")
+	store, p := s.savedQueryStore()
+	if p != nil {
+		return p
+	}
+
+	deleted, err := store.DeleteQuery(r.Context(), name)
+	if err != nil {
+		return upstreamProblem(err)
+	}
+	if !deleted {
+		return NewProblem(CodeNotFound, "no saved query named "+name)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// handleQueryResults runs a saved query. Query string parameters other
+// than limit, cursor and order fill in the query's parameters.
+func (s *Server) handleQueryResults(w http.ResponseWriter, r *http.Request) *Problem {
+	query, p := s.savedQuery(r)
+	if p != nil {
+		return p
+	}
+	querier, ok := repository.Unwrap(s.repo).(repository.EventQuerier)
+	if !ok {
+		return NewProblem(CodeNotImplemented, "saved queries are not supported by the configured database")
+	}
+
+	values := r.URL.Query()
+	if !values.Has("order") && query.Order != "" {
+		values.Set("order", query.Order)
+	}
+	if !values.Has("limit") && query.Limit > 0 {
+		values.Set("limit", fmt.Sprint(query.Limit))
+	}
+	page, errs := parsePage(values)
+	args := make(map[string]string)
+	for name := range values {
+		if !savedquery.Reserved[name] {
+			args[name] = values.Get(name)
+		}
+	}
+	filter, err := savedquery.Resolve(query, args, time.Now().UTC())
+	var resolveErr *savedquery.Error
+	if errors.As(err, &resolveErr) {
+		errs = append(errs, FieldError{Field: resolveErr.Field, Message: resolveErr.Message})
+	} else if err != nil {
+		return upstreamProblem(err)
+	}
+	if len(errs) > 0 {
+		return ValidationProblem(errs...)
+	}
+
+	fields := query.Fields
+	if s.cipher != nil && len(fields) > 0 && !slices.Contains(fields, "event_type") {
+		// Encrypted values are bound to the type of their event.
+		fields = append(slices.Clip(fields), "event_type")
+	}
+	result, err := querier.QueryEvents(r.Context(), repository.EventQuery{Filter: filter, Page: page, Fields: fields})
+	if err != nil {
+		return upstreamProblem(err)
+	}
+	if p := s.decrypt(result.Events...); p != nil {
+		return p
+	}
+	if len(fields) > len(query.Fields) {
+		for _, event := range result.Events {
+			switch doc := event.(type) {
+			case bson.M:
+				delete(doc, "event_type")
+			case map[string]interface{}:
+				delete(doc, "event_type")
+			}
+		}
+	}
+
+	return writeJSON(w, http.StatusOK, map[string]interface{}{
+		"query":       query.Name,
+		"events":      eventsOrEmpty(result.Events),
+		"count":       len(result.Events),
+		"next_cursor": nextCursor(result),
+	})
+}
+
+func validateSavedQuery(query *models.SavedQuery) []FieldError {
+	var errs []FieldError
+	for _, e := range savedquery.Validate(query) {
+		errs = append(errs, FieldError{Field: e.Field, Message: e.Message})
+	}
+	if query.Limit < 0 || query.Limit > maxEventsLimit {
+		errs = append(errs, FieldError{Field: "limit", Message: fmt.Sprintf("must be between 1 and %d, or 0 for the default", maxEventsLimit)})
+	}
+	if len(query.Fields) > maxQueryFields {
+		errs = append(errs, FieldError{Field: "fields", Message: fmt.Sprintf("must list at most %d fields", maxQueryFields)})
+	}
+	for _, field := range query.Fields {
+		if field == "" {
+			errs = append(errs, FieldError{Field: "fields", Message: "must not contain empty names"})
+			break
+		}
+	}
+	return errs
+}
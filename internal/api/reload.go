@@ -0,0 +1,21 @@
+package api
+
+import (
+	"log"
+	"net/http"
+)
+
+// handleReload reloads the configuration, applying what can be applied
+// without a restart. An invalid configuration leaves the running one as
+// it is.
+func (s *Server) handleReload(w http.ResponseWriter, r *http.Request) *Problem {
+	if s.reloader == nil {
+		return NewProblem(CodeNotImplemented, "configuration reloads are not supported")
+	}
+	result, err := s.reloader.ReloadConfig(r.Context())
+	if err != nil {
+		log.Printf("api: reload configuration: %v", err)
+		return NewProblem(CodeValidation, "configuration not reloaded: "+err.Error())
+	}
+	return writeJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"regexp"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+	"github.com/lugondev/go-indexer-solana-starter/internal/savedquery"
+)
+
+const (
+	minReportInterval = 5 * time.Minute
+	maxReportWindow   = maxAnalyticsDays * 24 * time.Hour
+	maxReportBody     = 64 << 10
+)
+
+var reportNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+type reportRequest struct {
+	Query    models.ReportQuery    `json:"query"`
+	Every    string                `json:"every"`
+	Delivery models.ReportDelivery `json:"delivery"`
+}
+
+func (s *Server) reportStore() (repository.ReportStore, *Problem) {
+	store, ok := repository.Unwrap(s.repo).(repository.ReportStore)
+	if !ok {
+		return nil, NewProblem(CodeNotImplemented, "scheduled reports are not supported by the configured database")
+	}
+	return store, nil
+}
+
+func (s *Server) handleListReports(w http.ResponseWriter, r *http.Request) *Problem {
+	store, p := s.reportStore()
+	if p != nil {
+		return p
+	}
+
+	reports, err := store.GetReports(r.Context())
+	if err != nil {
+		return upstreamProblem(err)
+	}
+	if reports == nil {
+		reports = []models.Report{}
+	}
+
+	return writeJSON(w, http.StatusOK, map[string]interface{}{
+		"reports": reports,
+		"count":   len(reports),
+	})
+}
+
+func (s *Server) handleGetReport(w http.ResponseWriter, r *http.Request) *Problem {
+	name := r.PathValue("name")
+	store, p := s.reportStore()
+	if p != nil {
+		return p
+	}
+
+	report, err := store.GetReport(r.Context(), name)
+	if err != nil {
+		return upstreamProblem(err)
+	}
+	if report == nil {
+		return NewProblem(CodeNotFound, "no report named "+name)
+	}
+
+	return writeJSON(w, http.StatusOK, report)
+}
+
+// handlePutReport creates or replaces a report. Replacing keeps the run
+// history, so the next run stays on the existing schedule.
+func (s *Server) handlePutReport(w http.ResponseWriter, r *http.Request) *Problem {
+	name := r.PathValue("name")
+	if !reportNamePattern.MatchString(name) {
+		return ValidationProblem(FieldError{Field: "name", Message: "must be 1 to 64 letters, digits, '-' or '_'"})
+	}
+
+	var req reportRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxReportBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		return ValidationProblem(FieldError{Field: "body", Message: "must be a JSON report definition: " + err.Error()})
+	}
+	if errs := validateReport(&req); len(errs) > 0 {
+		return ValidationProblem(errs...)
+	}
+
+	if req.Query.SavedQuery != "" {
+		if p := s.checkReportSavedQuery(r, req.Query); p != nil {
+			return p
+		}
+	}
+
+	store, p := s.reportStore()
+	if p != nil {
+		return p
+	}
+	existing, err := store.GetReport(r.Context(), name)
+	if err != nil {
+		return upstreamProblem(err)
+	}
+
+	report := &models.Report{
+		Name:      name,
+		Query:     req.Query,
+		Every:     req.Every,
+		Delivery:  req.Delivery,
+		CreatedAt: time.Now().UTC(),
+	}
+	status := http.StatusCreated
+	if existing != nil {
+		report.CreatedAt = existing.CreatedAt
+		report.LastRunAt = existing.LastRunAt
+		report.LastError = existing.LastError
+		status = http.StatusOK
+	}
+	if err := store.SaveReport(r.Context(), report); err != nil {
+		return upstreamProblem(err)
+	}
+
+	return writeJSON(w, status, report)
+}
+
+// checkReportSavedQuery rejects reports of saved queries that do not exist
+// or that the report's parameters do not fit.
+func (s *Server) checkReportSavedQuery(r *http.Request, query models.ReportQuery) *Problem {
+	queries, p := s.savedQueryStore()
+	if p != nil {
+		return p
+	}
+	saved, err := queries.GetQuery(r.Context(), query.SavedQuery)
+	if err != nil {
+		return upstreamProblem(err)
+	}
+	if saved == nil {
+		return ValidationProblem(FieldError{Field: "query.saved_query", Message: "no saved query named " + query.SavedQuery})
+	}
+	if _, err := savedquery.Resolve(saved, query.Params, time.Now()); err != nil {
+		return ValidationProblem(FieldError{Field: "query.params", Message: err.Error()})
+	}
+	return nil
+}
+
+func (s *Server) handleDeleteReport(w http.ResponseWriter, r *http.Request) *Problem {
+	name := r.PathValue("name")
+	store, p := s.reportStore()
+	if p != nil {
+		return p
+	}
+
+	deleted, err := store.DeleteReport(r.Context(), name)
+	if err != nil {
+		return upstreamProblem(err)
+	}
+	if !deleted {
+		return NewProblem(CodeNotFound, "no report named "+name)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func validateReport(req *reportRequest) []FieldError {
+	var errs []FieldError
+
+	if every, err := time.ParseDuration(req.Every); err != nil || every < minReportInterval {
+		errs = append(errs, FieldError{Field: "every", Message: fmt.Sprintf("must be a duration of at least %s, e.g. 24h", minReportInterval)})
+	}
+	if window, err := time.ParseDuration(req.Query.Window); err != nil || window <= 0 || window > maxReportWindow {
+		errs = append(errs, FieldError{Field: "query.window", Message: fmt.Sprintf("must be a positive duration of at most %d days, e.g. 24h", maxAnalyticsDays)})
+	}
+	if req.Query.SavedQuery != "" {
+		if req.Query.Account != "" || len(req.Query.EventTypes) > 0 {
+			errs = append(errs, FieldError{Field: "query.saved_query", Message: "cannot be combined with event_types or account"})
+		}
+		if !reportNamePattern.MatchString(req.Query.SavedQuery) {
+			errs = append(errs, FieldError{Field: "query.saved_query", Message: "must be the name of a saved query"})
+		}
+	} else if len(req.Query.Params) > 0 {
+		errs = append(errs, FieldError{Field: "query.params", Message: "requires saved_query"})
+	}
+	if req.Query.Account != "" {
+		if _, err := solana.PublicKeyFromBase58(req.Query.Account); err != nil {
+			errs = append(errs, FieldError{Field: "query.account", Message: "must be a base58 public key"})
+		}
+	}
+	for _, eventType := range req.Query.EventTypes {
+		if eventType == "" {
+			errs = append(errs, FieldError{Field: "query.event_types", Message: "must not contain empty types"})
+			break
+		}
+	}
+
+	if len(req.Delivery.Email) == 0 && req.Delivery.SheetID == "" {
+		errs = append(errs, FieldError{Field: "delivery", Message: "must set email or sheet_id"})
+	}
+	for _, address := range req.Delivery.Email {
+		parsed, err := mail.ParseAddress(address)
+		if err != nil || parsed.Address != address {
+			errs = append(errs, FieldError{Field: "delivery.email", Message: fmt.Sprintf("%q is not a plain email address", address)})
+		}
+	}
+	if req.Delivery.SheetRange != "" && req.Delivery.SheetID == "" {
+		errs = append(errs, FieldError{Field: "delivery.sheet_range", Message: "requires sheet_id"})
+	}
+	return errs
+}
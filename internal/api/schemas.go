@@ -0,0 +1,75 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"reflect"
+	"sync"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/jsonschema"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// logEventSchema names the schema of the events of log grammars, whose
+// types are only known from the configuration.
+const logEventSchema = "LogEvent"
+
+type schemaInfo struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// eventSchemas are the JSON Schemas of the event payloads by name: one per
+// event type with a model, and LogEvent.
+var eventSchemas = sync.OnceValue(func() map[string]*jsonschema.Schema {
+	schemas := make(map[string]*jsonschema.Schema)
+	add := func(name string, event models.Event, description string) {
+		s := jsonschema.For(reflect.TypeOf(event).Elem())
+		s.Schema = jsonschema.Draft
+		s.ID = "/schemas/" + name
+		s.Title = name
+		s.Description = description
+		schemas[name] = s
+	}
+	for _, eventType := range models.EventTypes() {
+		if event, ok := models.NewEventModel(eventType); ok {
+			add(string(eventType), event, "")
+		}
+	}
+	add(logEventSchema, &models.LogEvent{}, "An event of a program indexed through a log grammar; event_type is the one the grammar gives it.")
+	return schemas
+})
+
+// handleListSchemas lists the event payload schemas.
+func (s *Server) handleListSchemas(w http.ResponseWriter, r *http.Request) *Problem {
+	schemas := []schemaInfo{}
+	for _, eventType := range models.EventTypes() {
+		if _, ok := eventSchemas()[string(eventType)]; ok {
+			schemas = append(schemas, schemaInfo{Name: string(eventType), URL: "/schemas/" + string(eventType)})
+		}
+	}
+	schemas = append(schemas, schemaInfo{Name: logEventSchema, URL: "/schemas/" + logEventSchema})
+	return writeJSON(w, http.StatusOK, map[string]interface{}{
+		"schemas": schemas,
+		"count":   len(schemas),
+	})
+}
+
+// handleGetSchema returns the JSON Schema of an event payload, as stored,
+// returned by the API and sent to the sinks.
+func (s *Server) handleGetSchema(w http.ResponseWriter, r *http.Request) *Problem {
+	name := r.PathValue("name")
+	schema, ok := eventSchemas()[name]
+	if !ok {
+		return NewProblem(CodeNotFound, "no schema for "+name)
+	}
+	body, err := json.Marshal(schema)
+	if err != nil {
+		log.Printf("api: encode schema: %v", err)
+		return NewProblem(CodeInternal, "failed to encode schema")
+	}
+	w.Header().Set("Content-Type", "application/schema+json")
+	_, _ = w.Write(body)
+	return nil
+}
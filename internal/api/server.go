@@ -0,0 +1,840 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/accountmon"
+	"github.com/lugondev/go-indexer-solana-starter/internal/aggregate"
+	"github.com/lugondev/go-indexer-solana-starter/internal/analytics"
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/failure"
+	"github.com/lugondev/go-indexer-solana-starter/internal/fieldcrypt"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+	"github.com/lugondev/go-indexer-solana-starter/internal/seen"
+	"github.com/lugondev/go-indexer-solana-starter/internal/sink"
+	"github.com/lugondev/go-indexer-solana-starter/internal/spool"
+	"github.com/lugondev/go-indexer-solana-starter/internal/version"
+	"github.com/lugondev/go-indexer-solana-starter/internal/watchdog"
+	solanaClient "github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+const (
+	defaultEventsLimit = 50
+	maxEventsLimit     = 500
+
+	defaultAnalyticsDays = 7
+	maxAnalyticsDays     = 366
+)
+
+type StatusProvider interface {
+	GetCurrentSlot() uint64
+	IsRunning() bool
+}
+
+// WindowProvider reports the current windows of the streaming metrics.
+type WindowProvider interface {
+	StreamWindows() []aggregate.Series
+}
+
+// RPCProvider reports the health of the Solana RPC endpoint.
+type RPCProvider interface {
+	RPCStatus() solanaClient.RPCStatus
+}
+
+// SeenProvider reports the seen signature cache stats; nil when disabled.
+type SeenProvider interface {
+	SeenStats() *seen.Stats
+}
+
+// BufferProvider reports the offline event buffer; nil when disabled.
+type BufferProvider interface {
+	BufferStats() *spool.Stats
+}
+
+// PipelineProvider reports the queues between the indexing stages.
+type PipelineProvider interface {
+	PipelineQueues() []models.PipelineQueue
+}
+
+// WatermarkProvider reports how far the indexed data is complete and final.
+type WatermarkProvider interface {
+	Watermarks() models.Watermarks
+}
+
+// CoverageProvider reports the decoder coverage since start.
+type CoverageProvider interface {
+	DecoderCoverage() models.DecoderCoverage
+}
+
+// ErrorProvider reports the pipeline errors since start by kind.
+type ErrorProvider interface {
+	PipelineErrors() map[failure.Kind]uint64
+}
+
+// AccountAlertProvider reports the alerts of the program account monitor;
+// nil when disabled.
+type AccountAlertProvider interface {
+	AccountAlerts() []accountmon.Alert
+}
+
+// WatchdogProvider reports the raised watchdog alerts; nil when disabled.
+type WatchdogProvider interface {
+	WatchdogAlerts() []watchdog.Alert
+}
+
+// DeploymentProvider reports the labels of the indexed counter
+// deployments.
+type DeploymentProvider interface {
+	DeploymentLabels() []string
+}
+
+// Reloader reloads the configuration of the running indexer.
+type Reloader interface {
+	ReloadConfig(ctx context.Context) (*config.ReloadResult, error)
+}
+
+// LagProvider reports the last known lag of downstream sink consumers.
+type LagProvider interface {
+	ConsumerLag() []sink.ConsumerLag
+}
+
+type Options struct {
+	// RateLimitPerMinute caps requests per client IP; zero disables it.
+	RateLimitPerMinute int
+	// CounterMinFeeLamports is the default min_fee for payment analytics.
+	CounterMinFeeLamports uint64
+	// ConsumerLag backs the sink lag admin endpoint and metrics; optional.
+	ConsumerLag LagProvider
+	// Windows backs the streaming window endpoints and metrics; optional.
+	Windows WindowProvider
+	// RPC adds the RPC circuit breaker to /health and RPC metrics; optional.
+	RPC RPCProvider
+	// Seen adds the seen signature cache to the metrics; optional.
+	Seen SeenProvider
+	// AccountAlerts backs the account alerts admin endpoint and metrics;
+	// optional.
+	AccountAlerts AccountAlertProvider
+	// Watchdog adds the stall and error rate alerts to /health and the
+	// metrics; optional.
+	Watchdog WatchdogProvider
+	// Buffer adds the offline event buffer to the metrics; optional.
+	Buffer BufferProvider
+	// Pipeline adds the indexing pipeline queue depths to the metrics;
+	// optional.
+	Pipeline PipelineProvider
+	// Watermarks adds the slot watermarks to /status and the metrics;
+	// optional.
+	Watermarks WatermarkProvider
+	// Coverage adds the decoder coverage to the metrics; optional.
+	Coverage CoverageProvider
+	// Errors adds the pipeline error counts to the metrics; optional.
+	Errors ErrorProvider
+	// Users enables bearer token authentication; empty leaves the API open.
+	Users []User
+	// V1Deprecation marks /api/v1 deprecated in favour of /api/v2; optional.
+	V1Deprecation *Deprecation
+	// Deployments are the counter deployment labels events can be filtered
+	// by; optional.
+	Deployments DeploymentProvider
+	// Reloader backs the config reload admin endpoint; optional.
+	Reloader Reloader
+	// Decoder backs the decode endpoint; optional.
+	Decoder Decoder
+	// Watchlist backs the watchlist admin endpoints; optional.
+	Watchlist Watchlist
+	// Backfiller backs the backfill admin endpoint; optional.
+	Backfiller Backfiller
+	// Pauser backs the pause and resume admin endpoints and adds paused to
+	// /status; optional.
+	Pauser Pauser
+	// Reindexer backs the reindex admin endpoint; optional.
+	Reindexer Reindexer
+	// Cipher decrypts the ENCRYPTED_FIELDS of the events returned; optional.
+	Cipher *fieldcrypt.Cipher
+	// Network is the network the status and the other providers report
+	// on, empty unless NETWORKS is set.
+	Network string
+	// Networks serve the requests whose network parameter names another
+	// network; optional. Only their routes are used.
+	Networks []*Server
+}
+
+type Server struct {
+	httpServer  *http.Server
+	repo        repository.Repository
+	status      StatusProvider
+	limiter     *rateLimiter
+	minFee      uint64
+	lag         LagProvider
+	windows     WindowProvider
+	rpc         RPCProvider
+	seen        SeenProvider
+	accounts    AccountAlertProvider
+	watchdog    WatchdogProvider
+	buffer      BufferProvider
+	pipeline    PipelineProvider
+	watermarks  WatermarkProvider
+	coverage    CoverageProvider
+	failures    ErrorProvider
+	users       []User
+	versions    []apiVersion
+	deployments DeploymentProvider
+	reloader    Reloader
+	decoder     Decoder
+	watchlist   Watchlist
+	backfiller  Backfiller
+	pauser      Pauser
+	reindexer   Reindexer
+	cipher      *fieldcrypt.Cipher
+	network     string
+	networks    []*Server
+	startedAt   time.Time
+}
+
+func NewServer(port int, repo repository.Repository, status StatusProvider, opts Options) *Server {
+	s := &Server{
+		repo:        repo,
+		status:      status,
+		minFee:      opts.CounterMinFeeLamports,
+		lag:         opts.ConsumerLag,
+		windows:     opts.Windows,
+		rpc:         opts.RPC,
+		seen:        opts.Seen,
+		accounts:    opts.AccountAlerts,
+		watchdog:    opts.Watchdog,
+		buffer:      opts.Buffer,
+		pipeline:    opts.Pipeline,
+		watermarks:  opts.Watermarks,
+		coverage:    opts.Coverage,
+		failures:    opts.Errors,
+		users:       opts.Users,
+		versions:    apiVersions(opts.V1Deprecation),
+		deployments: opts.Deployments,
+		reloader:    opts.Reloader,
+		decoder:     opts.Decoder,
+		watchlist:   opts.Watchlist,
+		backfiller:  opts.Backfiller,
+		pauser:      opts.Pauser,
+		reindexer:   opts.Reindexer,
+		cipher:      opts.Cipher,
+		network:     opts.Network,
+		networks:    opts.Networks,
+		startedAt:   time.Now(),
+	}
+	if opts.RateLimitPerMinute > 0 {
+		s.limiter = newRateLimiter(opts.RateLimitPerMinute, time.Minute)
+	}
+
+	s.httpServer = &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s
+}
+
+func (s *Server) Handler() http.Handler {
+	return s.recoverer(s.rateLimit(s.authenticate(s.selectNetwork(s.mux()))))
+}
+
+// selectNetwork serves the requests whose network parameter names one of
+// s.networks with the routes of its server, and the others with next.
+func (s *Server) selectNetwork(next http.Handler) http.Handler {
+	if len(s.networks) == 0 {
+		return next
+	}
+	handlers := map[string]http.Handler{s.network: next}
+	names := []string{s.network}
+	for _, n := range s.networks {
+		handlers[n.network] = n.mux()
+		names = append(names, n.network)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		network := r.URL.Query().Get("network")
+		if network == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		h, ok := handlers[network]
+		if !ok {
+			writeProblem(w, r, ValidationProblem(FieldError{Field: "network", Message: "must be one of " + strings.Join(names, ", ")}))
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// mux routes the requests to the handlers of s.
+func (s *Server) mux() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/health", methods(http.MethodGet, s.handleHealth))
+	mux.Handle("/version", methods(http.MethodGet, s.handleVersion))
+	mux.Handle("/metrics", methods(http.MethodGet, s.handleMetrics))
+	mux.Handle("/api/versions", methods(http.MethodGet, s.handleVersions))
+	mux.Handle("/schemas", methods(http.MethodGet, s.handleListSchemas))
+	mux.Handle("/schemas/{name}", methods(http.MethodGet, s.handleGetSchema))
+	for _, v := range s.versions {
+		for _, rt := range s.routes() {
+			mux.Handle("/api/"+v.name+rt.pattern, v.handle(rt.pattern, rt.handler))
+		}
+	}
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeProblem(w, r, NewProblem(CodeNotFound, "no route for "+r.URL.Path))
+	})
+	return mux
+}
+
+type route struct {
+	pattern string
+	handler http.Handler
+}
+
+// routes are the versioned API routes, relative to /api/<version>.
+func (s *Server) routes() []route {
+	return []route{
+		{"/status", methods(http.MethodGet, s.handleStatus)},
+		{"/events", methods(http.MethodGet, s.handleListEvents)},
+		{"/events/latest", methods(http.MethodGet, s.handleLatestEvents)},
+		{"/events/{signature}", methods(http.MethodGet, s.handleGetEvent)},
+		{"/accounts/{pubkey}/events", methods(http.MethodGet, s.handleAccountEvents)},
+		{"/accounts/{pubkey}/samples", methods(http.MethodGet, s.handleAccountSamples)},
+		{"/config/history", methods(http.MethodGet, s.handleConfigHistory)},
+		{"/analytics/counter-payments", methods(http.MethodGet, s.handleCounterPayments)},
+		{"/analytics/event-stats", methods(http.MethodGet, s.handleEventStats)},
+		{"/counters/{pubkey}/history", methods(http.MethodGet, s.handleCounterHistory)},
+		{"/programs/{program}/changes", methods(http.MethodGet, s.handleProgramChanges)},
+		{"/collections/{collection}/stats", methods(http.MethodGet, s.handleCollectionStats)},
+		{"/tokens/{mint}", methods(http.MethodGet, s.handleGetToken)},
+		{"/tokens/{mint}/holders", methods(http.MethodGet, s.handleTokenHolders)},
+		{"/token-accounts/{address}", methods(http.MethodGet, s.handleGetTokenAccount)},
+		{"/wallets/{owner}/token-accounts", methods(http.MethodGet, s.handleWalletTokenAccounts)},
+		{"/nfts/search", methods(http.MethodGet, s.handleSearchNfts)},
+		{"/nfts/{mint}", methods(http.MethodGet, s.handleGetNft)},
+		{"/streams/windows", methods(http.MethodGet, s.handleListWindows)},
+		{"/streams/windows/{name}", methods(http.MethodGet, s.handleGetWindows)},
+		{"/admin/sinks/lag", methods(http.MethodGet, s.handleSinkLag)},
+		{"/admin/accounts/alerts", methods(http.MethodGet, s.handleAccountAlerts)},
+		{"/admin/decoder/coverage", methods(http.MethodGet, s.handleDecoderCoverage)},
+		{"/admin/dead-letters", methods(http.MethodGet, s.handleDeadLetters)},
+		{"/admin/reload", methods(http.MethodPost, requires(RoleAdmin, s.handleReload))},
+		{"/admin/backfill", methodSet{
+			http.MethodGet:  s.handleGetBackfill,
+			http.MethodPost: requires(RoleAdmin, s.handleStartBackfill),
+		}},
+		{"/admin/pause", methods(http.MethodPost, requires(RoleAdmin, s.handlePause))},
+		{"/admin/resume", methods(http.MethodPost, requires(RoleAdmin, s.handleResume))},
+		{"/admin/reindex", methods(http.MethodPost, requires(RoleAdmin, s.handleStartReindex))},
+		{"/admin/watchlist", methods(http.MethodGet, s.handleListWatchlist)},
+		{"/admin/watchlist/{address}", methodSet{
+			http.MethodGet:    s.handleGetWatched,
+			http.MethodPut:    s.handlePutWatched,
+			http.MethodDelete: s.handleDeleteWatched,
+		}},
+		{"/decode", methods(http.MethodPost, s.handleDecode)},
+		{"/reports", methods(http.MethodGet, s.handleListReports)},
+		{"/reports/{name}", methodSet{
+			http.MethodGet:    s.handleGetReport,
+			http.MethodPut:    s.handlePutReport,
+			http.MethodDelete: s.handleDeleteReport,
+		}},
+		{"/queries", methods(http.MethodGet, s.handleListQueries)},
+		{"/queries/{name}", methodSet{
+			http.MethodGet:    s.handleGetQuery,
+			http.MethodPut:    s.handlePutQuery,
+			http.MethodDelete: s.handleDeleteQuery,
+		}},
+		{"/queries/{name}/results", methods(http.MethodGet, s.handleQueryResults)},
+	}
+}
+
+func (s *Server) Start() error {
+	log.Printf("api server listening on %s", s.httpServer.Addr)
+	if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("api server: %w", err)
+	}
+	return nil
+}
+
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.httpServer.Shutdown(ctx)
+}
+
+// handlerFunc is an http.HandlerFunc that may fail with a Problem.
+type handlerFunc func(w http.ResponseWriter, r *http.Request) *Problem
+
+func methods(method string, h handlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			writeProblem(w, r, NewProblem(CodeMethodNotAllowed, r.Method+" is not supported on "+r.URL.Path))
+			return
+		}
+		if p := h(w, r); p != nil {
+			writeProblem(w, r, p)
+		}
+	})
+}
+
+// methodSet routes a path that accepts several methods.
+type methodSet map[string]handlerFunc
+
+func (m methodSet) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h, ok := m[r.Method]
+	if !ok {
+		allowed := make([]string, 0, len(m))
+		for method := range m {
+			allowed = append(allowed, method)
+		}
+		sort.Strings(allowed)
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		writeProblem(w, r, NewProblem(CodeMethodNotAllowed, r.Method+" is not supported on "+r.URL.Path))
+		return
+	}
+	if p := h(w, r); p != nil {
+		writeProblem(w, r, p)
+	}
+}
+
+// handleHealth always answers 200 so that probes do not restart the
+// indexer over an RPC outage; an open circuit breaker reports "degraded".
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) *Problem {
+	body := map[string]interface{}{
+		"status":       "ok",
+		"current_slot": s.status.GetCurrentSlot(),
+		"is_running":   s.status.IsRunning(),
+	}
+	if s.rpc != nil {
+		if circuit := s.rpc.RPCStatus().Circuit; circuit != nil {
+			body["rpc_circuit"] = circuit
+			if circuit.State != solanaClient.BreakerClosed {
+				body["status"] = "degraded"
+			}
+		}
+	}
+	if s.watchdog != nil {
+		if alerts := s.watchdog.WatchdogAlerts(); alerts != nil {
+			body["alerts"] = alerts
+			if len(alerts) > 0 {
+				body["status"] = "degraded"
+			}
+		}
+	}
+	return writeJSON(w, http.StatusOK, body)
+}
+
+func (s *Server) handleVersion(w http.ResponseWriter, r *http.Request) *Problem {
+	return writeJSON(w, http.StatusOK, version.Get())
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) *Problem {
+	status := map[string]interface{}{
+		"is_running":     s.status.IsRunning(),
+		"current_slot":   s.status.GetCurrentSlot(),
+		"uptime_seconds": int64(time.Since(s.startedAt).Seconds()),
+	}
+	if s.network != "" {
+		status["network"] = s.network
+	}
+	if s.pauser != nil {
+		status["paused"] = s.pauser.Paused()
+	}
+	if s.watermarks != nil {
+		status["watermarks"] = s.watermarks.Watermarks()
+	}
+	return writeJSON(w, http.StatusOK, status)
+}
+
+func (s *Server) handleListEvents(w http.ResponseWriter, r *http.Request) *Problem {
+	query := r.URL.Query()
+
+	var errs []FieldError
+	eventType := models.EventType(query.Get("type"))
+	if eventType == "" {
+		errs = append(errs, FieldError{Field: "type", Message: "is required"})
+	}
+	page, pageErrs := parsePage(query)
+	errs = append(errs, pageErrs...)
+	page.Deployment, errs = s.parseDeployment(query, errs)
+	if len(errs) > 0 {
+		return ValidationProblem(errs...)
+	}
+
+	result, err := s.repo.GetEventsByType(r.Context(), eventType, page)
+	if err != nil {
+		return upstreamProblem(err)
+	}
+	if p := s.decrypt(result.Events...); p != nil {
+		return p
+	}
+
+	return writeJSON(w, http.StatusOK, map[string]interface{}{
+		"events":      eventsOrEmpty(result.Events),
+		"count":       len(result.Events),
+		"next_cursor": nextCursor(result),
+	})
+}
+
+// handleLatestEvents lists the newest events of every type, ordered by
+// slot and sequence, for recent activity feeds.
+func (s *Server) handleLatestEvents(w http.ResponseWriter, r *http.Request) *Problem {
+	reader, ok := repository.Unwrap(s.repo).(repository.LatestReader)
+	if !ok {
+		return NewProblem(CodeNotImplemented, "latest events are not supported by this repository")
+	}
+
+	query := r.URL.Query()
+	page, errs := parsePage(query)
+	if page.Ascending {
+		errs = append(errs, FieldError{Field: "order", Message: "must be 'desc'"})
+	}
+	page.Deployment, errs = s.parseDeployment(query, errs)
+	if len(errs) > 0 {
+		return ValidationProblem(errs...)
+	}
+
+	result, err := reader.GetLatestEvents(r.Context(), page)
+	if err != nil {
+		return upstreamProblem(err)
+	}
+	if p := s.decrypt(result.Events...); p != nil {
+		return p
+	}
+
+	return writeJSON(w, http.StatusOK, map[string]interface{}{
+		"events":      eventsOrEmpty(result.Events),
+		"count":       len(result.Events),
+		"next_cursor": nextCursor(result),
+	})
+}
+
+// parsePage reads the limit, cursor, network and order parameters shared
+// by the paginated event endpoints.
+func parsePage(query url.Values) (repository.PageOptions, []FieldError) {
+	var errs []FieldError
+	page := repository.PageOptions{Limit: defaultEventsLimit}
+
+	if raw := query.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 || n > maxEventsLimit {
+			errs = append(errs, FieldError{Field: "limit", Message: fmt.Sprintf("must be an integer between 1 and %d", maxEventsLimit)})
+		} else {
+			page.Limit = n
+		}
+	}
+	if raw := query.Get("cursor"); raw != "" {
+		cursor, err := repository.ParseCursor(raw)
+		if err != nil {
+			errs = append(errs, FieldError{Field: "cursor", Message: "must be a next_cursor returned by a previous page"})
+		} else {
+			page.After = cursor
+		}
+	}
+	page.Network = query.Get("network")
+	switch query.Get("order") {
+	case "", "desc":
+	case "asc":
+		page.Ascending = true
+	default:
+		errs = append(errs, FieldError{Field: "order", Message: "must be 'asc' or 'desc'"})
+	}
+	return page, errs
+}
+
+// parseDeployment reads the deployment parameter, which must be one of the
+// configured counter deployment labels.
+func (s *Server) parseDeployment(query url.Values, errs []FieldError) (string, []FieldError) {
+	deployment := query.Get("deployment")
+	if deployment == "" {
+		return "", errs
+	}
+	var labels []string
+	if s.deployments != nil {
+		labels = s.deployments.DeploymentLabels()
+	}
+	if slices.Contains(labels, deployment) {
+		return deployment, errs
+	}
+	if len(labels) == 0 {
+		return "", append(errs, FieldError{Field: "deployment", Message: "no counter deployments are configured"})
+	}
+	return "", append(errs, FieldError{Field: "deployment", Message: "must be one of " + strings.Join(labels, ", ")})
+}
+
+func nextCursor(page *repository.EventPage) interface{} {
+	if page.Next == nil {
+		return nil
+	}
+	return page.Next.String()
+}
+
+func eventsOrEmpty(events []interface{}) []interface{} {
+	if events == nil {
+		return []interface{}{}
+	}
+	return events
+}
+
+// decrypt decrypts the encrypted fields of events read back in place.
+func (s *Server) decrypt(events ...interface{}) *Problem {
+	if s.cipher == nil {
+		return nil
+	}
+	for _, event := range events {
+		if err := s.cipher.Decrypt(event); err != nil {
+			log.Printf("api: decrypt event: %v", err)
+			return NewProblem(CodeInternal, "failed to decrypt event")
+		}
+	}
+	return nil
+}
+
+func (s *Server) handleGetEvent(w http.ResponseWriter, r *http.Request) *Problem {
+	signature := r.PathValue("signature")
+	if len(signature) < 64 || len(signature) > 88 {
+		return ValidationProblem(FieldError{Field: "signature", Message: "must be a base58 transaction signature"})
+	}
+
+	event, err := s.repo.GetEventBySignature(r.Context(), signature)
+	if err != nil {
+		return upstreamProblem(err)
+	}
+	if event == nil {
+		return NewProblem(CodeNotFound, "no event indexed for signature "+signature)
+	}
+	if p := s.decrypt(event); p != nil {
+		return p
+	}
+
+	return writeJSON(w, http.StatusOK, event)
+}
+
+func (s *Server) handleConfigHistory(w http.ResponseWriter, r *http.Request) *Problem {
+	limit := defaultEventsLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 || n > maxEventsLimit {
+			return ValidationProblem(FieldError{Field: "limit", Message: fmt.Sprintf("must be an integer between 1 and %d", maxEventsLimit)})
+		}
+		limit = n
+	}
+
+	changes, err := s.repo.GetConfigHistory(r.Context(), limit)
+	if err != nil {
+		return upstreamProblem(err)
+	}
+	if changes == nil {
+		changes = []models.ConfigChange{}
+	}
+
+	var current *models.ProgramConfig
+	if len(changes) > 0 {
+		current = &changes[0].Config
+	}
+
+	return writeJSON(w, http.StatusOK, map[string]interface{}{
+		"current": current,
+		"changes": changes,
+		"count":   len(changes),
+	})
+}
+
+func (s *Server) handleCounterPayments(w http.ResponseWriter, r *http.Request) *Problem {
+	store, ok := repository.Unwrap(s.repo).(repository.CounterPaymentStore)
+	if !ok {
+		return NewProblem(CodeNotImplemented, "counter payment analytics are not supported by the configured database")
+	}
+	query := r.URL.Query()
+	now := time.Now().UTC()
+
+	var errs []FieldError
+	to := now.Truncate(24 * time.Hour)
+	if raw := query.Get("to"); raw != "" {
+		d, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			errs = append(errs, FieldError{Field: "to", Message: "must be a date in YYYY-MM-DD format"})
+		} else {
+			to = d
+		}
+	}
+
+	from := to.AddDate(0, 0, -(defaultAnalyticsDays - 1))
+	if raw := query.Get("from"); raw != "" {
+		d, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			errs = append(errs, FieldError{Field: "from", Message: "must be a date in YYYY-MM-DD format"})
+		} else {
+			from = d
+		}
+	}
+
+	minFee := s.minFee
+	if raw := query.Get("min_fee"); raw != "" {
+		n, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			errs = append(errs, FieldError{Field: "min_fee", Message: "must be a non-negative integer amount of lamports"})
+		} else {
+			minFee = n
+		}
+	}
+	if len(errs) > 0 {
+		return ValidationProblem(errs...)
+	}
+
+	if from.After(to) {
+		return ValidationProblem(FieldError{Field: "from", Message: "must not be after to"})
+	}
+	if to.Sub(from) >= maxAnalyticsDays*24*time.Hour {
+		return ValidationProblem(FieldError{Field: "from", Message: fmt.Sprintf("range must not exceed %d days", maxAnalyticsDays)})
+	}
+
+	// The range is inclusive of the whole "to" day.
+	payments, err := store.GetCounterPayments(r.Context(), from, to.AddDate(0, 0, 1).Add(-time.Nanosecond))
+	if err != nil {
+		return upstreamProblem(err)
+	}
+
+	return writeJSON(w, http.StatusOK, map[string]interface{}{
+		"from":    from.Format(time.DateOnly),
+		"to":      to.Format(time.DateOnly),
+		"min_fee": minFee,
+		"days":    analytics.PaymentStatsByDay(payments, minFee),
+	})
+}
+
+func (s *Server) handleSinkLag(w http.ResponseWriter, r *http.Request) *Problem {
+	lags := s.consumerLag()
+	return writeJSON(w, http.StatusOK, map[string]interface{}{
+		"sinks": lags,
+		"count": len(lags),
+	})
+}
+
+func (s *Server) consumerLag() []sink.ConsumerLag {
+	var lags []sink.ConsumerLag
+	if s.lag != nil {
+		lags = s.lag.ConsumerLag()
+	}
+	if lags == nil {
+		lags = []sink.ConsumerLag{}
+	}
+	return lags
+}
+
+func upstreamProblem(err error) *Problem {
+	log.Printf("api: repository error: %v", err)
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return NewProblem(CodeUpstreamUnavailable, "the database did not respond in time")
+	}
+	return NewProblem(CodeUpstreamUnavailable, "the database is currently unavailable")
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) *Problem {
+	if mw, ok := w.(*mappedWriter); ok {
+		v = mw.mapper(v)
+	}
+	body, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("api: encode response: %v", err)
+		return NewProblem(CodeInternal, "failed to encode response")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+	return nil
+}
+
+func (s *Server) recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				log.Printf("api: panic serving %s: %v", r.URL.Path, rec)
+				writeProblem(w, r, NewProblem(CodeInternal, "unexpected server error"))
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) rateLimit(next http.Handler) http.Handler {
+	if s.limiter == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if retryAfter, ok := s.limiter.allow(clientIP(r), time.Now()); !ok {
+			writeProblem(w, r, NewProblem(CodeRateLimited, "too many requests").WithRetryAfter(retryAfter))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// rateLimiter is a fixed-window per-client request counter.
+type rateLimiter struct {
+	mu      sync.Mutex
+	limit   int
+	window  time.Duration
+	clients map[string]*rateWindow
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{
+		limit:   limit,
+		window:  window,
+		clients: make(map[string]*rateWindow),
+	}
+}
+
+func (l *rateLimiter) allow(client string, now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	w, ok := l.clients[client]
+	if !ok || now.Sub(w.start) >= l.window {
+		if len(l.clients) > 10000 {
+			l.evict(now)
+		}
+		l.clients[client] = &rateWindow{start: now, count: 1}
+		return 0, true
+	}
+
+	if w.count >= l.limit {
+		return w.start.Add(l.window).Sub(now), false
+	}
+	w.count++
+	return 0, true
+}
+
+func (l *rateLimiter) evict(now time.Time) {
+	for client, w := range l.clients {
+		if now.Sub(w.start) >= l.window {
+			delete(l.clients, client)
+		}
+	}
+}
@@ -0,0 +1,1905 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/lugondev/go-indexer-solana-starter/internal/aggregate"
+	"github.com/lugondev/go-indexer-solana-starter/internal/analytics"
+	"github.com/lugondev/go-indexer-solana-starter/internal/jsonschema"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+	"github.com/lugondev/go-indexer-solana-starter/internal/sink"
+	"github.com/lugondev/go-indexer-solana-starter/internal/version"
+	"github.com/lugondev/go-indexer-solana-starter/internal/watchdog"
+	solanaClient "github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+type fakeRepo struct {
+	events map[string]interface{}
+	err    error
+
+	page        repository.PageOptions
+	next        *repository.Cursor
+	accountOpts repository.AccountEventsOptions
+	tenant      string
+}
+
+func (r *fakeRepo) SaveEvent(ctx context.Context, event models.Event) error { return nil }
+
+func (r *fakeRepo) GetEventsByTimeRange(ctx context.Context, from, to time.Time) ([]models.BaseEvent, error) {
+	return nil, r.err
+}
+
+func (r *fakeRepo) GetEventsByType(ctx context.Context, eventType models.EventType, page repository.PageOptions) (*repository.EventPage, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	r.page = page
+	r.tenant = repository.TenantFromContext(ctx)
+	var out []interface{}
+	for _, e := range r.events {
+		out = append(out, e)
+	}
+	return &repository.EventPage{Events: out, Next: r.next}, nil
+}
+
+func (r *fakeRepo) GetLatestEvents(ctx context.Context, page repository.PageOptions) (*repository.EventPage, error) {
+	return r.GetEventsByType(ctx, "", page)
+}
+
+func (r *fakeRepo) GetEventBySignature(ctx context.Context, signature string) (interface{}, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.events[signature], nil
+}
+
+func (r *fakeRepo) GetEventsByAccount(ctx context.Context, account solana.PublicKey, opts repository.AccountEventsOptions) (*repository.EventPage, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	r.accountOpts = opts
+	var out []interface{}
+	for _, e := range r.events {
+		out = append(out, e)
+	}
+	return &repository.EventPage{Events: out, Next: r.next}, nil
+}
+
+func (r *fakeRepo) SaveConfigChange(ctx context.Context, change *models.ConfigChange) error {
+	return nil
+}
+
+func (r *fakeRepo) GetConfigHistory(ctx context.Context, limit int) ([]models.ConfigChange, error) {
+	return nil, r.err
+}
+
+func (r *fakeRepo) Close(ctx context.Context) error { return nil }
+
+type fakeStatus struct{}
+
+func (fakeStatus) GetCurrentSlot() uint64 { return 42 }
+func (fakeStatus) IsRunning() bool        { return true }
+
+var testSignature = strings.Repeat("5", 87)
+
+const testAccount = "So11111111111111111111111111111111111111112"
+
+func TestServer_Problems(t *testing.T) {
+	repo := &fakeRepo{events: map[string]interface{}{
+		testSignature: models.BaseEvent{Signature: testSignature, EventType: models.EventTypeCounterReset},
+	}}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		repoErr    error
+		wantStatus int
+		wantCode   ErrorCode
+		wantField  string
+	}{
+		{name: "unknown route", method: http.MethodGet, path: "/nope", wantStatus: http.StatusNotFound, wantCode: CodeNotFound},
+		{name: "wrong method", method: http.MethodPost, path: "/health", wantStatus: http.StatusMethodNotAllowed, wantCode: CodeMethodNotAllowed},
+		{name: "missing type", method: http.MethodGet, path: "/api/v1/events", wantStatus: http.StatusBadRequest, wantCode: CodeValidation, wantField: "type"},
+		{
+			name:       "bad limit",
+			method:     http.MethodGet,
+			path:       "/api/v1/events?type=CounterResetEvent&limit=-1",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   CodeValidation,
+			wantField:  "limit",
+		},
+		{name: "bad account", method: http.MethodGet, path: "/api/v1/accounts/nope/events", wantStatus: http.StatusBadRequest, wantCode: CodeValidation, wantField: "pubkey"},
+		{
+			name:       "unknown account event type",
+			method:     http.MethodGet,
+			path:       "/api/v1/accounts/" + testAccount + "/events?type=Minted",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   CodeValidation,
+			wantField:  "type",
+		},
+		{name: "bad cursor", method: http.MethodGet, path: "/api/v1/events?type=CounterResetEvent&cursor=!!", wantStatus: http.StatusBadRequest, wantCode: CodeValidation, wantField: "cursor"},
+		{name: "bad order", method: http.MethodGet, path: "/api/v1/events?type=CounterResetEvent&order=up", wantStatus: http.StatusBadRequest, wantCode: CodeValidation, wantField: "order"},
+		{name: "bad config history limit", method: http.MethodGet, path: "/api/v1/config/history?limit=0", wantStatus: http.StatusBadRequest, wantCode: CodeValidation, wantField: "limit"},
+		{name: "bad signature", method: http.MethodGet, path: "/api/v1/events/abc", wantStatus: http.StatusBadRequest, wantCode: CodeValidation, wantField: "signature"},
+		{name: "unknown signature", method: http.MethodGet, path: "/api/v1/events/" + strings.Repeat("4", 87), wantStatus: http.StatusNotFound, wantCode: CodeNotFound},
+		{
+			name:       "database down",
+			method:     http.MethodGet,
+			path:       "/api/v1/events/" + testSignature,
+			repoErr:    errors.New("connection refused"),
+			wantStatus: http.StatusServiceUnavailable,
+			wantCode:   CodeUpstreamUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo.err = tt.repoErr
+			srv := NewServer(0, repo, fakeStatus{}, Options{})
+
+			rec := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != problemContentType {
+				t.Errorf("Content-Type = %q, want %q", ct, problemContentType)
+			}
+
+			var p Problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+			if p.Code != tt.wantCode || p.Status != tt.wantStatus || p.Type == "" || p.Instance == "" {
+				t.Errorf("problem = %+v", p)
+			}
+			if tt.wantField != "" && (len(p.Errors) == 0 || p.Errors[0].Field != tt.wantField) {
+				t.Errorf("errors = %+v, want field %s", p.Errors, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestServer_GetEvent(t *testing.T) {
+	repo := &fakeRepo{events: map[string]interface{}{
+		testSignature: models.BaseEvent{Signature: testSignature, EventType: models.EventTypeCounterReset},
+	}}
+	srv := NewServer(0, repo, fakeStatus{}, Options{})
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events/"+testSignature, nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), testSignature) {
+		t.Errorf("body = %s, want signature", rec.Body.String())
+	}
+}
+
+func TestServer_ListEventsPagination(t *testing.T) {
+	repo := &fakeRepo{
+		events: map[string]interface{}{
+			testSignature: models.BaseEvent{Signature: testSignature, EventType: models.EventTypeCounterReset},
+		},
+		next: &repository.Cursor{Slot: 42, Signature: testSignature},
+	}
+	srv := NewServer(0, repo, fakeStatus{}, Options{})
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events?type=CounterResetEvent&limit=1", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		NextCursor string `json:"next_cursor"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body.NextCursor == "" {
+		t.Fatalf("next_cursor missing: %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events?type=CounterResetEvent&cursor="+body.NextCursor, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if repo.page.After == nil || *repo.page.After != *repo.next {
+		t.Errorf("After = %+v, want %+v", repo.page.After, repo.next)
+	}
+}
+
+func TestServer_LatestEvents(t *testing.T) {
+	repo := &fakeRepo{
+		events: map[string]interface{}{
+			testSignature: models.BaseEvent{Signature: testSignature, EventType: models.EventTypeCounterReset},
+		},
+		next: &repository.Cursor{Slot: 42, Sequence: 7, Signature: testSignature},
+	}
+	srv := NewServer(0, repo, fakeStatus{}, Options{})
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events/latest?limit=1", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Count      int    `json:"count"`
+		NextCursor string `json:"next_cursor"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body.Count != 1 || body.NextCursor != repo.next.String() {
+		t.Errorf("body = %s, want one event and a next_cursor", rec.Body.String())
+	}
+	if repo.page.Limit != 1 || repo.page.Ascending {
+		t.Errorf("page = %+v, want limit 1, newest first", repo.page)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events/latest?order=asc", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("order=asc status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestServer_AccountEvents(t *testing.T) {
+	repo := &fakeRepo{events: map[string]interface{}{
+		testSignature: models.BaseEvent{Signature: testSignature, EventType: models.EventTypeTokensTransferred},
+	}}
+	srv := NewServer(0, repo, fakeStatus{}, Options{})
+
+	path := "/api/v1/accounts/" + testAccount + "/events?type=TokensTransferredEvent,TokensMintedEvent&order=asc&limit=10"
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Account string `json:"account"`
+		Count   int    `json:"count"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body.Account != testAccount || body.Count != 1 {
+		t.Errorf("body = %s", rec.Body.String())
+	}
+
+	opts := repo.accountOpts
+	if len(opts.EventTypes) != 2 || opts.Limit != 10 || !opts.Ascending {
+		t.Errorf("options = %+v", opts)
+	}
+}
+
+func TestServer_RateLimit(t *testing.T) {
+	srv := NewServer(0, &fakeRepo{}, fakeStatus{}, Options{RateLimitPerMinute: 2})
+	handler := srv.Handler()
+
+	var last *httptest.ResponseRecorder
+	for i := 0; i < 3; i++ {
+		last = httptest.NewRecorder()
+		handler.ServeHTTP(last, httptest.NewRequest(http.MethodGet, "/health", nil))
+	}
+
+	if last.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", last.Code)
+	}
+	if last.Header().Get("Retry-After") == "" {
+		t.Error("missing Retry-After header")
+	}
+
+	var p Problem
+	if err := json.Unmarshal(last.Body.Bytes(), &p); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if p.Code != CodeRateLimited {
+		t.Errorf("code = %s, want %s", p.Code, CodeRateLimited)
+	}
+}
+
+type fakePaymentRepo struct {
+	fakeRepo
+	payments []models.CounterPaymentReceivedEvent
+}
+
+func (r *fakePaymentRepo) GetCounterPayments(ctx context.Context, from, to time.Time) ([]models.CounterPaymentReceivedEvent, error) {
+	return r.payments, r.err
+}
+
+func TestServer_CounterPayments(t *testing.T) {
+	day := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	repo := &fakePaymentRepo{payments: []models.CounterPaymentReceivedEvent{
+		{BaseEvent: models.BaseEvent{BlockTime: day}, Payment: 500},
+		{BaseEvent: models.BaseEvent{BlockTime: day}, Payment: 1500},
+	}}
+	srv := NewServer(0, repo, fakeStatus{}, Options{CounterMinFeeLamports: 1000})
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/analytics/counter-payments?from=2026-01-01&to=2026-01-03", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	var body struct {
+		MinFee uint64 `json:"min_fee"`
+		Days   []struct {
+			Date        string `json:"date"`
+			Count       int    `json:"count"`
+			BelowMinFee int    `json:"below_min_fee"`
+		} `json:"days"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.MinFee != 1000 || len(body.Days) != 1 {
+		t.Fatalf("body = %+v", body)
+	}
+	if d := body.Days[0]; d.Date != "2026-01-02" || d.Count != 2 || d.BelowMinFee != 1 {
+		t.Errorf("days[0] = %+v", d)
+	}
+
+	for _, path := range []string{
+		"/api/v1/analytics/counter-payments?from=yesterday",
+		"/api/v1/analytics/counter-payments?from=2026-01-05&to=2026-01-01",
+		"/api/v1/analytics/counter-payments?min_fee=-1",
+	} {
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", path, rec.Code)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	NewServer(0, &fakeRepo{}, fakeStatus{}, Options{}).Handler().
+		ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/analytics/counter-payments", nil))
+	if rec.Code != http.StatusNotImplemented {
+		t.Errorf("unsupported status = %d, want %d", rec.Code, http.StatusNotImplemented)
+	}
+}
+
+type fakeAggregatingRepo struct {
+	fakeRepo
+	filter repository.EventFilter
+	window time.Duration
+}
+
+func (r *fakeAggregatingRepo) CountEventsByType(ctx context.Context, filter repository.EventFilter) (map[models.EventType]int64, error) {
+	r.filter = filter
+	return map[models.EventType]int64{models.EventTypeCounterReset: 2}, nil
+}
+
+func (r *fakeAggregatingRepo) CountEventsByWindow(ctx context.Context, filter repository.EventFilter, window time.Duration) ([]repository.WindowCount, error) {
+	r.window = window
+	return []repository.WindowCount{{Start: filter.From, EventType: models.EventTypeCounterReset, Count: 2}}, nil
+}
+
+func (r *fakeAggregatingRepo) SumFieldByWindow(ctx context.Context, filter repository.EventFilter, field string, window time.Duration) ([]repository.WindowSum, error) {
+	return nil, nil
+}
+
+func TestServer_EventStats(t *testing.T) {
+	repo := &fakeAggregatingRepo{}
+	srv := NewServer(0, repo, fakeStatus{}, Options{})
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/analytics/event-stats?from=2026-03-01&to=2026-05-31", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	var body struct {
+		From   string                     `json:"from"`
+		To     string                     `json:"to"`
+		Totals map[models.EventType]int64 `json:"totals"`
+		Days   []repository.WindowCount   `json:"days"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	if body.From != "2026-03-01" || body.To != "2026-05-31" || body.Totals[models.EventTypeCounterReset] != 2 {
+		t.Errorf("body = %+v", body)
+	}
+	if len(body.Days) != 1 || !body.Days[0].Start.Equal(from) || body.Days[0].Count != 2 {
+		t.Errorf("days = %+v, want one count of 2 on %s", body.Days, from)
+	}
+	if want := (repository.EventFilter{From: from, To: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)}); !reflect.DeepEqual(repo.filter, want) {
+		t.Errorf("filter = %+v, want %+v", repo.filter, want)
+	}
+	if repo.window != 24*time.Hour {
+		t.Errorf("window = %v, want 24h", repo.window)
+	}
+
+	for _, path := range []string{
+		"/api/v1/analytics/event-stats?from=march",
+		"/api/v1/analytics/event-stats?from=2026-05-01&to=2026-03-01",
+		"/api/v1/analytics/event-stats?from=2024-01-01&to=2026-01-01",
+	} {
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", path, rec.Code)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	NewServer(0, &fakeRepo{}, fakeStatus{}, Options{}).Handler().
+		ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/analytics/event-stats", nil))
+	if rec.Code != http.StatusNotImplemented {
+		t.Errorf("unsupported status = %d, want %d", rec.Code, http.StatusNotImplemented)
+	}
+}
+
+func TestServer_Version(t *testing.T) {
+	srv := NewServer(0, &fakeRepo{}, fakeStatus{}, Options{})
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+
+	var info version.Info
+	if err := json.Unmarshal(rec.Body.Bytes(), &info); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if info.Version == "" || info.Commit == "" || info.GoVersion == "" {
+		t.Errorf("version = %+v", info)
+	}
+}
+
+func TestServer_Schemas(t *testing.T) {
+	srv := NewServer(0, &fakeRepo{}, fakeStatus{}, Options{})
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/schemas", nil))
+	var list struct {
+		Schemas []schemaInfo `json:"schemas"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(list.Schemas) < 2 || list.Schemas[len(list.Schemas)-1].Name != logEventSchema {
+		t.Fatalf("schemas = %+v, want the event models then LogEvent", list.Schemas)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/schemas/CounterPaymentReceivedEvent", nil))
+	if ct := rec.Header().Get("Content-Type"); rec.Code != http.StatusOK || ct != "application/schema+json" {
+		t.Fatalf("status = %d, content type = %q", rec.Code, ct)
+	}
+	var schema jsonschema.Schema
+	if err := json.Unmarshal(rec.Body.Bytes(), &schema); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	for _, field := range []string{"signature", "slot", "payer", "payment", "payment_sol"} {
+		if schema.Properties[field] == nil {
+			t.Errorf("schema lacks %s: %s", field, rec.Body)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/schemas/NoSuchEvent", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown schema status = %d, want 404", rec.Code)
+	}
+}
+
+// counterRepo returns its events, newest first, as the only page of any
+// account.
+type counterRepo struct {
+	fakeRepo
+	events []interface{}
+}
+
+func (r *counterRepo) GetEventsByAccount(ctx context.Context, account solana.PublicKey, opts repository.AccountEventsOptions) (*repository.EventPage, error) {
+	r.accountOpts = opts
+	return &repository.EventPage{Events: r.events}, nil
+}
+
+func TestServer_CounterHistory(t *testing.T) {
+	counter := solana.MustPublicKeyFromBase58("7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU")
+	at := func(day, hour int) models.BaseEvent {
+		return models.BaseEvent{BlockTime: time.Date(2026, 3, day, hour, 0, 0, 0, time.UTC)}
+	}
+	repo := &counterRepo{events: []interface{}{
+		&models.CounterIncrementedEvent{BaseEvent: at(4, 1), Counter: counter, NewValue: 9},
+		&models.CounterAddedEvent{BaseEvent: at(3, 12), Counter: counter, NewValue: 8},
+		&models.CounterResetEvent{BaseEvent: at(3, 6), Counter: counter},
+		&models.CounterIncrementedEvent{BaseEvent: at(2, 20), Counter: counter, NewValue: 5},
+		&models.CounterIncrementedEvent{BaseEvent: at(1, 0), Counter: counter, NewValue: 4},
+	}}
+	srv := NewServer(0, repo, fakeStatus{}, Options{})
+	base := "/api/v1/counters/" + counter.String() + "/history?from=2026-03-03&to=2026-03-03"
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, base, nil))
+	var raw struct {
+		StartValue *uint64                   `json:"start_value"`
+		Changes    []analytics.CounterChange `json:"changes"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &raw); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if raw.StartValue == nil || *raw.StartValue != 5 || len(raw.Changes) != 2 || raw.Changes[0].Value != 0 || raw.Changes[1].Value != 8 {
+		t.Errorf("history = %s, want start 5 then 0 and 8", rec.Body)
+	}
+	if !slices.Contains(repo.accountOpts.EventTypes, models.EventTypeCounterReset) {
+		t.Errorf("event types = %v, want the counter events", repo.accountOpts.EventTypes)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, base+"&interval=12h", nil))
+	var sampled struct {
+		Buckets []analytics.CounterBucket `json:"buckets"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &sampled); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	want := []analytics.CounterBucket{
+		{Start: time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC), Open: 5, Close: 0, Min: 0, Max: 5, Changes: 1},
+		{Start: time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC), Open: 0, Close: 8, Min: 0, Max: 8, Changes: 1},
+	}
+	if !slices.Equal(sampled.Buckets, want) {
+		t.Errorf("buckets = %+v, want %+v", sampled.Buckets, want)
+	}
+
+	for _, query := range []string{"from=2026-03-03&interval=30s", "from=2026-01-01&to=2026-03-03&interval=1m"} {
+		rec = httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/counters/"+counter.String()+"/history?"+query, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", query, rec.Code)
+		}
+	}
+}
+
+// sequenceRepo numbers its events from 1 and serves them after a sequence.
+type sequenceRepo struct {
+	fakeRepo
+	events  []interface{}
+	network string
+}
+
+func (r *sequenceRepo) GetEventsAfterSequence(ctx context.Context, network string, program solana.PublicKey, after uint64, limit int) ([]interface{}, error) {
+	r.network = network
+	if after >= uint64(len(r.events)) {
+		return []interface{}{}, nil
+	}
+	return r.events[after:min(uint64(len(r.events)), after+uint64(limit))], nil
+}
+
+func TestServer_ProgramChanges(t *testing.T) {
+	program := solana.MustPublicKeyFromBase58("CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc")
+	repo := &sequenceRepo{}
+	for n := uint64(1); n <= 3; n++ {
+		repo.events = append(repo.events, &models.CounterResetEvent{BaseEvent: models.BaseEvent{Sequence: n}})
+	}
+	srv := NewServer(0, repo, fakeStatus{}, Options{})
+	base := "/api/v1/programs/" + program.String() + "/changes"
+
+	tests := []struct {
+		query     string
+		wantCount int
+		wantNext  uint64
+	}{
+		{"", 3, 3},
+		{"?after=1&limit=1", 1, 2},
+		{"?after=3", 0, 3},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, base+tt.query, nil))
+		var body struct {
+			Count     int    `json:"count"`
+			NextAfter uint64 `json:"next_after"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: decode: %v", tt.query, err)
+		}
+		if body.Count != tt.wantCount || body.NextAfter != tt.wantNext {
+			t.Errorf("%s: count, next_after = %d, %d, want %d, %d", tt.query, body.Count, body.NextAfter, tt.wantCount, tt.wantNext)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, base+"?network=devnet", nil))
+	if repo.network != "devnet" {
+		t.Errorf("network = %q, want devnet", repo.network)
+	}
+	rec = httptest.NewRecorder()
+	NewServer(0, repo, fakeStatus{}, Options{Network: "mainnet-beta"}).Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, base, nil))
+	if repo.network != "mainnet-beta" {
+		t.Errorf("network without parameter = %q, want the server's mainnet-beta", repo.network)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, base+"?after=-1", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("negative after status = %d, want 400", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	NewServer(0, &fakeRepo{}, fakeStatus{}, Options{}).Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, base, nil))
+	if rec.Code != http.StatusNotImplemented {
+		t.Errorf("unsupported status = %d, want 501", rec.Code)
+	}
+}
+
+type fakeLag []sink.ConsumerLag
+
+func (l fakeLag) ConsumerLag() []sink.ConsumerLag { return l }
+
+func TestServer_SinkLag(t *testing.T) {
+	lag := fakeLag{
+		{Sink: "sqs", Target: "https://sqs/events", Backlog: 42, InFlight: 3},
+		{Sink: "sqs", Target: "https://sqs/nfts", Error: "access denied"},
+	}
+	srv := NewServer(0, &fakeRepo{}, fakeStatus{}, Options{ConsumerLag: lag})
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/admin/sinks/lag", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Sinks []sink.ConsumerLag `json:"sinks"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(body.Sinks) != 2 || body.Sinks[0].Backlog != 42 {
+		t.Errorf("sinks = %+v", body.Sinks)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	metrics := rec.Body.String()
+	for _, want := range []string{
+		"solana_indexer_current_slot 42",
+		`solana_indexer_sink_backlog_messages{sink="sqs",target="https://sqs/events"} 42`,
+		`solana_indexer_sink_in_flight_messages{sink="sqs",target="https://sqs/events"} 3`,
+		`solana_indexer_sink_lag_up{sink="sqs",target="https://sqs/nfts"} 0`,
+	} {
+		if !strings.Contains(metrics, want) {
+			t.Errorf("metrics missing %q:\n%s", want, metrics)
+		}
+	}
+}
+
+type fakeWindows []aggregate.Series
+
+func (f fakeWindows) StreamWindows() []aggregate.Series { return f }
+
+func TestServer_StreamWindows(t *testing.T) {
+	windows := fakeWindows{{
+		Name:        "payments_5m",
+		EventType:   models.EventTypeCounterPaymentReceived,
+		Aggregation: aggregate.AggregationCount,
+		Windows:     []aggregate.Window{{Value: 7, Partial: true}, {Value: 12}},
+	}}
+	srv := NewServer(0, &fakeRepo{}, fakeStatus{}, Options{Windows: windows})
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/streams/windows/payments_5m", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var series aggregate.Series
+	if err := json.Unmarshal(rec.Body.Bytes(), &series); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if series.Name != "payments_5m" || len(series.Windows) != 2 {
+		t.Errorf("series = %+v", series)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/streams/windows/nope", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown metric status = %d, want 404", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	for _, want := range []string{
+		`solana_indexer_stream_window_value{metric="payments_5m",window="current"} 7`,
+		`solana_indexer_stream_window_value{metric="payments_5m",window="previous"} 12`,
+	} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("metrics missing %q:\n%s", want, rec.Body.String())
+		}
+	}
+}
+
+type fakeReportRepo struct {
+	fakeRepo
+	reports map[string]models.Report
+}
+
+func (r *fakeReportRepo) SaveReport(ctx context.Context, report *models.Report) error {
+	r.reports[report.Name] = *report
+	return nil
+}
+
+func (r *fakeReportRepo) GetReports(ctx context.Context) ([]models.Report, error) {
+	var out []models.Report
+	for _, report := range r.reports {
+		out = append(out, report)
+	}
+	return out, nil
+}
+
+func (r *fakeReportRepo) GetReport(ctx context.Context, name string) (*models.Report, error) {
+	report, ok := r.reports[name]
+	if !ok {
+		return nil, nil
+	}
+	return &report, nil
+}
+
+func (r *fakeReportRepo) DeleteReport(ctx context.Context, name string) (bool, error) {
+	_, ok := r.reports[name]
+	delete(r.reports, name)
+	return ok, nil
+}
+
+func (r *fakeReportRepo) RecordReportRun(ctx context.Context, name string, at time.Time, runErr string) error {
+	return nil
+}
+
+func TestServer_Reports(t *testing.T) {
+	lastRun := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	repo := &fakeReportRepo{reports: map[string]models.Report{
+		"existing": {Name: "existing", Every: "24h", LastRunAt: &lastRun},
+	}}
+	handler := NewServer(0, repo, fakeStatus{}, Options{}).Handler()
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantField  string
+	}{
+		{
+			name:       "create",
+			method:     http.MethodPut,
+			path:       "/api/v1/reports/daily",
+			body:       `{"query":{"event_types":["CounterResetEvent"],"window":"24h"},"every":"24h","delivery":{"email":["ops@example.com"]}}`,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "replace keeps history",
+			method:     http.MethodPut,
+			path:       "/api/v1/reports/existing",
+			body:       `{"query":{"window":"1h"},"every":"1h","delivery":{"sheet_id":"abc"}}`,
+			wantStatus: http.StatusOK,
+		},
+		{name: "bad name", method: http.MethodPut, path: "/api/v1/reports/a.b", body: `{}`, wantStatus: http.StatusBadRequest, wantField: "name"},
+		{
+			name:       "schedule too frequent",
+			method:     http.MethodPut,
+			path:       "/api/v1/reports/fast",
+			body:       `{"query":{"window":"1h"},"every":"1m","delivery":{"sheet_id":"abc"}}`,
+			wantStatus: http.StatusBadRequest,
+			wantField:  "every",
+		},
+		{
+			name:       "no delivery",
+			method:     http.MethodPut,
+			path:       "/api/v1/reports/nowhere",
+			body:       `{"query":{"window":"1h"},"every":"1h"}`,
+			wantStatus: http.StatusBadRequest,
+			wantField:  "delivery",
+		},
+		{
+			name:       "bad email",
+			method:     http.MethodPut,
+			path:       "/api/v1/reports/bad-mail",
+			body:       `{"query":{"window":"1h"},"every":"1h","delivery":{"email":["Ops <ops@example.com>"]}}`,
+			wantStatus: http.StatusBadRequest,
+			wantField:  "delivery.email",
+		},
+		{name: "get", method: http.MethodGet, path: "/api/v1/reports/daily", wantStatus: http.StatusOK},
+		{name: "list", method: http.MethodGet, path: "/api/v1/reports", wantStatus: http.StatusOK},
+		{name: "delete", method: http.MethodDelete, path: "/api/v1/reports/daily", wantStatus: http.StatusNoContent},
+		{name: "delete missing", method: http.MethodDelete, path: "/api/v1/reports/daily", wantStatus: http.StatusNotFound},
+		{name: "wrong method", method: http.MethodPost, path: "/api/v1/reports/daily", wantStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body = %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantField != "" {
+				var p Problem
+				if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+					t.Fatalf("decode problem: %v", err)
+				}
+				if len(p.Errors) == 0 || p.Errors[0].Field != tt.wantField {
+					t.Errorf("errors = %+v, want field %q", p.Errors, tt.wantField)
+				}
+			}
+		})
+	}
+
+	if got := repo.reports["existing"]; got.LastRunAt == nil || !got.LastRunAt.Equal(lastRun) || got.Every != "1h" {
+		t.Errorf("replaced report = %+v, want updated schedule with run history", got)
+	}
+}
+
+func TestServer_ReportsNotSupported(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewServer(0, &fakeRepo{}, fakeStatus{}, Options{}).Handler().
+		ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/reports", nil))
+	if rec.Code != http.StatusNotImplemented {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotImplemented)
+	}
+}
+
+type fakeQueryRepo struct {
+	fakeRepo
+	queries map[string]models.SavedQuery
+	query   repository.EventQuery
+}
+
+func (r *fakeQueryRepo) SaveQuery(ctx context.Context, query *models.SavedQuery) error {
+	r.queries[query.Name] = *query
+	return nil
+}
+
+func (r *fakeQueryRepo) GetQueries(ctx context.Context) ([]models.SavedQuery, error) {
+	var out []models.SavedQuery
+	for _, query := range r.queries {
+		out = append(out, query)
+	}
+	return out, nil
+}
+
+func (r *fakeQueryRepo) GetQuery(ctx context.Context, name string) (*models.SavedQuery, error) {
+	query, ok := r.queries[name]
+	if !ok {
+		return nil, nil
+	}
+	return &query, nil
+}
+
+func (r *fakeQueryRepo) DeleteQuery(ctx context.Context, name string) (bool, error) {
+	_, ok := r.queries[name]
+	delete(r.queries, name)
+	return ok, nil
+}
+
+func (r *fakeQueryRepo) QueryEvents(ctx context.Context, query repository.EventQuery) (*repository.EventPage, error) {
+	r.query = query
+	return &repository.EventPage{Events: []interface{}{map[string]interface{}{"slot": 7}}}, nil
+}
+
+func TestServer_SavedQueries(t *testing.T) {
+	repo := &fakeQueryRepo{queries: map[string]models.SavedQuery{}}
+	handler := NewServer(0, repo, fakeStatus{}, Options{}).Handler()
+	wallet := "9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin"
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantField  string
+	}{
+		{
+			name:       "create",
+			method:     http.MethodPut,
+			path:       "/api/v1/queries/wallet",
+			body:       `{"filter":{"account":"{{wallet}}","window":"24h"},"order":"asc","fields":["slot","signature"],"limit":20,"params":[{"name":"wallet"}]}`,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "undeclared parameter",
+			method:     http.MethodPut,
+			path:       "/api/v1/queries/broken",
+			body:       `{"filter":{"account":"{{wallet}}"}}`,
+			wantStatus: http.StatusBadRequest,
+			wantField:  "filter.account",
+		},
+		{
+			name:       "limit too large",
+			method:     http.MethodPut,
+			path:       "/api/v1/queries/big",
+			body:       `{"limit":100000}`,
+			wantStatus: http.StatusBadRequest,
+			wantField:  "limit",
+		},
+		{name: "get", method: http.MethodGet, path: "/api/v1/queries/wallet", wantStatus: http.StatusOK},
+		{name: "list", method: http.MethodGet, path: "/api/v1/queries", wantStatus: http.StatusOK},
+		{name: "run", method: http.MethodGet, path: "/api/v1/queries/wallet/results?wallet=" + wallet, wantStatus: http.StatusOK},
+		{name: "run without parameter", method: http.MethodGet, path: "/api/v1/queries/wallet/results", wantStatus: http.StatusBadRequest, wantField: "wallet"},
+		{name: "run unknown", method: http.MethodGet, path: "/api/v1/queries/nope/results", wantStatus: http.StatusNotFound},
+		{name: "delete", method: http.MethodDelete, path: "/api/v1/queries/wallet", wantStatus: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body = %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantField != "" {
+				var p Problem
+				if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+					t.Fatalf("decode problem: %v", err)
+				}
+				if len(p.Errors) == 0 || p.Errors[0].Field != tt.wantField {
+					t.Errorf("errors = %+v, want field %q", p.Errors, tt.wantField)
+				}
+			}
+		})
+	}
+
+	got := repo.query
+	if got.Filter.Account == nil || got.Filter.Account.String() != wallet || got.Filter.From.IsZero() {
+		t.Errorf("queried filter = %+v, want the wallet over the last day", got.Filter)
+	}
+	if got.Page.Limit != 20 || !got.Page.Ascending || !reflect.DeepEqual(got.Fields, []string{"slot", "signature"}) {
+		t.Errorf("queried page = %+v, fields = %v, want the saved defaults", got.Page, got.Fields)
+	}
+}
+
+type fakeNftRepo struct {
+	fakeRepo
+	nfts       map[string]models.NftMetadata
+	lastSearch repository.NftSearch
+}
+
+func (r *fakeNftRepo) QueueNftMetadata(ctx context.Context, nft *models.NftMetadata) error {
+	return nil
+}
+
+func (r *fakeNftRepo) PendingNftMetadata(ctx context.Context, now time.Time, limit int) ([]models.NftMetadata, error) {
+	return nil, nil
+}
+
+func (r *fakeNftRepo) SaveNftMetadata(ctx context.Context, nft *models.NftMetadata) error {
+	return nil
+}
+
+func (r *fakeNftRepo) GetNftMetadata(ctx context.Context, mint string) (*models.NftMetadata, error) {
+	nft, ok := r.nfts[mint]
+	if !ok {
+		return nil, nil
+	}
+	return &nft, nil
+}
+
+func (r *fakeNftRepo) SearchNfts(ctx context.Context, search repository.NftSearch) ([]models.NftMetadata, error) {
+	r.lastSearch = search
+	var out []models.NftMetadata
+	for _, nft := range r.nfts {
+		out = append(out, nft)
+	}
+	return out, nil
+}
+
+func TestServer_Nfts(t *testing.T) {
+	mint := "7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU"
+	repo := &fakeNftRepo{nfts: map[string]models.NftMetadata{
+		mint: {Mint: mint, Name: "Ape #1", Status: models.NftMetadataFetched},
+	}}
+	handler := NewServer(0, repo, fakeStatus{}, Options{}).Handler()
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantField  string
+	}{
+		{name: "search", path: "/api/v1/nfts/search?q=ape&attribute=Background:Blue&limit=5", wantStatus: http.StatusOK},
+		{name: "no criteria", path: "/api/v1/nfts/search", wantStatus: http.StatusBadRequest, wantField: "q"},
+		{name: "bad attribute", path: "/api/v1/nfts/search?attribute=Blue", wantStatus: http.StatusBadRequest, wantField: "attribute"},
+		{name: "bad collection", path: "/api/v1/nfts/search?collection=nope", wantStatus: http.StatusBadRequest, wantField: "collection"},
+		{name: "get", path: "/api/v1/nfts/" + mint, wantStatus: http.StatusOK},
+		{name: "get unknown", path: "/api/v1/nfts/9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM", wantStatus: http.StatusNotFound},
+		{name: "get bad mint", path: "/api/v1/nfts/nope", wantStatus: http.StatusBadRequest, wantField: "mint"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body = %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantField != "" {
+				var p Problem
+				if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+					t.Fatalf("decode problem: %v", err)
+				}
+				if len(p.Errors) == 0 || p.Errors[0].Field != tt.wantField {
+					t.Errorf("errors = %+v, want field %q", p.Errors, tt.wantField)
+				}
+			}
+		})
+	}
+
+	want := repository.NftSearch{
+		Text:       "ape",
+		Attributes: []models.NftAttribute{{TraitType: "Background", Value: "Blue"}},
+		Limit:      5,
+	}
+	if !reflect.DeepEqual(repo.lastSearch, want) {
+		t.Errorf("search = %+v, want %+v", repo.lastSearch, want)
+	}
+
+	rec := httptest.NewRecorder()
+	NewServer(0, &fakeRepo{}, fakeStatus{}, Options{}).Handler().
+		ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/nfts/search?q=ape", nil))
+	if rec.Code != http.StatusNotImplemented {
+		t.Errorf("unsupported status = %d, want %d", rec.Code, http.StatusNotImplemented)
+	}
+}
+
+type fakeSalesRepo struct {
+	fakeRepo
+	stats    []models.CollectionSalesStats
+	from, to time.Time
+}
+
+func (r *fakeSalesRepo) RecordNftCollection(ctx context.Context, mint, collection string) error {
+	return nil
+}
+
+func (r *fakeSalesRepo) RecordNftSale(ctx context.Context, sale *models.NftSoldEvent) error {
+	return nil
+}
+
+func (r *fakeSalesRepo) GetCollectionStats(ctx context.Context, collection string, period models.SalesPeriod, from, to time.Time) ([]models.CollectionSalesStats, error) {
+	r.from, r.to = from, to
+	return r.stats, nil
+}
+
+func TestServer_CollectionStats(t *testing.T) {
+	collection := "7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU"
+	repo := &fakeSalesRepo{stats: []models.CollectionSalesStats{
+		{Start: time.Date(2026, 1, 12, 0, 0, 0, 0, time.UTC), Sales: 3, Volume: 600, Floor: 100, High: 300},
+	}}
+	handler := NewServer(0, repo, fakeStatus{}, Options{}).Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet,
+		"/api/v1/collections/"+collection+"/stats?period=week&from=2026-01-07&to=2026-01-14", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	var body struct {
+		From    string                        `json:"from"`
+		To      string                        `json:"to"`
+		Periods []models.CollectionSalesStats `json:"periods"`
+		Totals  struct {
+			Sales int64  `json:"sales"`
+			Floor uint64 `json:"floor"`
+		} `json:"totals"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.From != "2026-01-05" || body.To != "2026-01-12" || len(body.Periods) != 2 {
+		t.Errorf("response = %+v, want the weeks of 2026-01-05 and 2026-01-12", body)
+	}
+	if body.Totals.Sales != 3 || body.Totals.Floor != 100 {
+		t.Errorf("totals = %+v", body.Totals)
+	}
+	if want := time.Date(2026, 1, 19, 0, 0, 0, 0, time.UTC); !repo.to.Equal(want) {
+		t.Errorf("queried to %v, want %v", repo.to, want)
+	}
+
+	for _, path := range []string{
+		"/api/v1/collections/nope/stats",
+		"/api/v1/collections/" + collection + "/stats?period=month",
+		"/api/v1/collections/" + collection + "/stats?from=2026-02-01&to=2026-01-01",
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", path, rec.Code)
+		}
+	}
+}
+
+func TestParseUsers(t *testing.T) {
+	hash := sha256.Sum256([]byte("secret"))
+	hexHash := hex.EncodeToString(hash[:])
+
+	tests := []struct {
+		name    string
+		entries map[string]string
+		want    []User
+		wantErr bool
+	}{
+		{name: "empty", entries: nil, want: []User{}},
+		{
+			name:    "users",
+			entries: map[string]string{"bob": "viewer:" + hexHash, "alice": "operator:" + hexHash},
+			want: []User{
+				{Name: "alice", Role: RoleOperator, TokenHash: hash},
+				{Name: "bob", Role: RoleReader, TokenHash: hash},
+			},
+		},
+		{name: "missing hash", entries: map[string]string{"alice": "operator"}, wantErr: true},
+		{
+			name:    "admin",
+			entries: map[string]string{"dave": "admin:" + hexHash},
+			want:    []User{{Name: "dave", Role: RoleAdmin, TokenHash: hash}},
+		},
+		{name: "unknown role", entries: map[string]string{"alice": "root:" + hexHash}, wantErr: true},
+		{name: "short hash", entries: map[string]string{"alice": "viewer:abcd"}, wantErr: true},
+		{
+			name:    "tenant",
+			entries: map[string]string{"carol": "reader:" + hexHash + ":payments"},
+			want:    []User{{Name: "carol", Role: RoleReader, TokenHash: hash, Tenant: "payments"}},
+		},
+		{name: "empty tenant", entries: map[string]string{"carol": "viewer:" + hexHash + ":"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseUsers(tt.entries)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseUsers() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseUsers() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_Authentication(t *testing.T) {
+	viewerHash := sha256.Sum256([]byte("viewer-token"))
+	operatorHash := sha256.Sum256([]byte("operator-token"))
+	adminHash := sha256.Sum256([]byte("admin-token"))
+	repo := &fakeReportRepo{reports: map[string]models.Report{}}
+	pauser := &fakePauser{}
+	handler := NewServer(0, repo, fakeStatus{}, Options{Pauser: pauser, Users: []User{
+		{Name: "viewer", Role: RoleReader, TokenHash: viewerHash},
+		{Name: "operator", Role: RoleOperator, TokenHash: operatorHash},
+		{Name: "admin", Role: RoleAdmin, TokenHash: adminHash},
+	}}).Handler()
+
+	report := `{"query":{"window":"1h"},"every":"1h","delivery":{"sheet_id":"abc"}}`
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		token      string
+		wantStatus int
+	}{
+		{name: "health is public", method: http.MethodGet, path: "/health", wantStatus: http.StatusOK},
+		{name: "metrics are public", method: http.MethodGet, path: "/metrics", wantStatus: http.StatusOK},
+		{name: "no token", method: http.MethodGet, path: "/api/v1/status", wantStatus: http.StatusUnauthorized},
+		{name: "unknown token", method: http.MethodGet, path: "/api/v1/status", token: "nope", wantStatus: http.StatusUnauthorized},
+		{name: "viewer reads", method: http.MethodGet, path: "/api/v1/status", token: "viewer-token", wantStatus: http.StatusOK},
+		{name: "viewer cannot write", method: http.MethodPut, path: "/api/v1/reports/daily", token: "viewer-token", wantStatus: http.StatusForbidden},
+		{name: "viewer cannot use admin", method: http.MethodGet, path: "/api/v1/admin/sinks/lag", token: "viewer-token", wantStatus: http.StatusForbidden},
+		{name: "operator writes", method: http.MethodPut, path: "/api/v1/reports/daily", token: "operator-token", wantStatus: http.StatusCreated},
+		{name: "operator uses admin", method: http.MethodGet, path: "/api/v1/admin/sinks/lag", token: "operator-token", wantStatus: http.StatusOK},
+		{name: "viewer cannot use v2 admin", method: http.MethodGet, path: "/api/v2/admin/sinks/lag", token: "viewer-token", wantStatus: http.StatusForbidden},
+		{name: "viewer decodes", method: http.MethodPost, path: "/api/v1/decode", token: "viewer-token", wantStatus: http.StatusNotImplemented},
+		{name: "viewer cannot pause", method: http.MethodPost, path: "/api/v1/admin/pause", token: "viewer-token", wantStatus: http.StatusForbidden},
+		{name: "operator cannot pause", method: http.MethodPost, path: "/api/v1/admin/pause", token: "operator-token", wantStatus: http.StatusForbidden},
+		{name: "operator cannot reindex", method: http.MethodPost, path: "/api/v1/admin/reindex", token: "operator-token", wantStatus: http.StatusForbidden},
+		{name: "operator cannot backfill", method: http.MethodPost, path: "/api/v1/admin/backfill", token: "operator-token", wantStatus: http.StatusForbidden},
+		{name: "operator cannot reload", method: http.MethodPost, path: "/api/v1/admin/reload", token: "operator-token", wantStatus: http.StatusForbidden},
+		{name: "operator reads backfill", method: http.MethodGet, path: "/api/v1/admin/backfill", token: "operator-token", wantStatus: http.StatusNotImplemented},
+		{name: "admin pauses", method: http.MethodPost, path: "/api/v1/admin/pause", token: "admin-token", wantStatus: http.StatusOK},
+		{name: "admin uses admin", method: http.MethodGet, path: "/api/v1/admin/sinks/lag", token: "admin-token", wantStatus: http.StatusOK},
+		{name: "admin writes", method: http.MethodPut, path: "/api/v1/reports/weekly", token: "admin-token", wantStatus: http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(report))
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body = %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("missing WWW-Authenticate header")
+			}
+		})
+	}
+	if !pauser.paused {
+		t.Error("indexing not paused by the admin")
+	}
+}
+
+type fakePauser struct {
+	paused bool
+}
+
+func (p *fakePauser) Pause()       { p.paused = true }
+func (p *fakePauser) Resume()      { p.paused = false }
+func (p *fakePauser) Paused() bool { return p.paused }
+
+func TestServer_TenantUsers(t *testing.T) {
+	tenantHash := sha256.Sum256([]byte("tenant-token"))
+	viewerHash := sha256.Sum256([]byte("viewer-token"))
+	repo := &fakeRepo{}
+	handler := NewServer(0, repo, fakeStatus{}, Options{Users: []User{
+		{Name: "payments", Role: RoleOperator, TokenHash: tenantHash, Tenant: "payments"},
+		{Name: "viewer", Role: RoleReader, TokenHash: viewerHash},
+	}}).Handler()
+
+	tests := []struct {
+		name       string
+		path       string
+		token      string
+		wantStatus int
+		wantTenant string
+	}{
+		{name: "tenant lists events", path: "/api/v1/events?type=CounterIncrementedEvent", token: "tenant-token", wantStatus: http.StatusOK, wantTenant: "payments"},
+		{name: "tenant reads status", path: "/api/v2/status", token: "tenant-token", wantStatus: http.StatusOK},
+		{name: "tenant cannot read holders", path: "/api/v1/tokens/7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU/holders", token: "tenant-token", wantStatus: http.StatusForbidden},
+		{name: "tenant cannot use admin", path: "/api/v1/admin/sinks/lag", token: "tenant-token", wantStatus: http.StatusForbidden},
+		{name: "viewer lists all events", path: "/api/v1/events?type=CounterIncrementedEvent", token: "viewer-token", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo.tenant = ""
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body = %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if repo.tenant != tt.wantTenant {
+				t.Errorf("repository queried for tenant %q, want %q", repo.tenant, tt.wantTenant)
+			}
+		})
+	}
+}
+
+type fakeTokenRepo struct {
+	fakeRepo
+	supply *models.TokenSupply
+	page   repository.HolderPage
+	opts   repository.HolderPageOptions
+}
+
+func (r *fakeTokenRepo) RecordTokenMovement(ctx context.Context, m models.TokenMovement) error {
+	return nil
+}
+
+func (r *fakeTokenRepo) GetTokenSupply(ctx context.Context, mint string) (*models.TokenSupply, error) {
+	return r.supply, nil
+}
+
+func (r *fakeTokenRepo) GetTokenHolders(ctx context.Context, mint string, opts repository.HolderPageOptions) (*repository.HolderPage, error) {
+	r.opts = opts
+	return &r.page, nil
+}
+
+func TestServer_TokenHolders(t *testing.T) {
+	mint := "7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU"
+	repo := &fakeTokenRepo{
+		supply: &models.TokenSupply{Mint: mint, Minted: 100, Burned: 10, Supply: 90, Holders: 2},
+		page: repository.HolderPage{
+			Holders: []models.TokenHolder{{Owner: "alice", Balance: 60}},
+			Next:    &repository.HolderCursor{Balance: 60, Owner: "alice"},
+		},
+	}
+	handler := NewServer(0, repo, fakeStatus{}, Options{}).Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/tokens/"+mint, nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"supply":90`) {
+		t.Errorf("token: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	cursor := repository.HolderCursor{Balance: 75, Owner: "bob"}
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet,
+		"/api/v1/tokens/"+mint+"/holders?limit=1&cursor="+cursor.String(), nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("holders: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Holders    []models.TokenHolder `json:"holders"`
+		NextCursor string               `json:"next_cursor"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(body.Holders) != 1 || body.NextCursor != repo.page.Next.String() {
+		t.Errorf("holders response = %+v", body)
+	}
+	if repo.opts.Limit != 1 || repo.opts.After == nil || *repo.opts.After != cursor {
+		t.Errorf("queried with %+v, want limit 1 after %+v", repo.opts, cursor)
+	}
+
+	tests := []struct {
+		name string
+		repo repository.Repository
+		path string
+		want int
+	}{
+		{"bad cursor", repo, "/api/v1/tokens/" + mint + "/holders?cursor=nope", http.StatusBadRequest},
+		{"bad mint", repo, "/api/v1/tokens/nope/holders", http.StatusBadRequest},
+		{"unknown mint", &fakeTokenRepo{}, "/api/v1/tokens/" + mint, http.StatusNotFound},
+		{"unsupported", &fakeRepo{}, "/api/v1/tokens/" + mint + "/holders", http.StatusNotImplemented},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			NewServer(0, tt.repo, fakeStatus{}, Options{}).Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d, body = %s", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
+
+type fakeRPC solanaClient.RPCStatus
+
+func (f fakeRPC) RPCStatus() solanaClient.RPCStatus { return solanaClient.RPCStatus(f) }
+
+func TestServer_RPCHealth(t *testing.T) {
+	rpc := fakeRPC{
+		Methods: []solanaClient.MethodStats{{Method: "getTransaction", Calls: 10, Errors: 4, Rejected: 2, DurationSeconds: 1.5}},
+		Circuit: &solanaClient.BreakerStatus{State: solanaClient.BreakerOpen, ConsecutiveFailures: 5},
+	}
+	handler := NewServer(0, &fakeRepo{}, fakeStatus{}, Options{RPC: rpc}).Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	var health struct {
+		Status     string                     `json:"status"`
+		RPCCircuit solanaClient.BreakerStatus `json:"rpc_circuit"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &health); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if rec.Code != http.StatusOK || health.Status != "degraded" || health.RPCCircuit.State != solanaClient.BreakerOpen {
+		t.Errorf("health: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	for _, want := range []string{
+		`solana_indexer_rpc_requests_total{method="getTransaction"} 10`,
+		`solana_indexer_rpc_errors_total{method="getTransaction"} 4`,
+		`solana_indexer_rpc_rejected_total{method="getTransaction"} 2`,
+		`solana_indexer_rpc_request_duration_seconds_total{method="getTransaction"} 1.5`,
+		"solana_indexer_rpc_circuit_open 1",
+	} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("metrics missing %q:\n%s", want, rec.Body.String())
+		}
+	}
+}
+
+type fakeWatchdog []watchdog.Alert
+
+func (f fakeWatchdog) WatchdogAlerts() []watchdog.Alert { return f }
+
+func TestServer_WatchdogHealth(t *testing.T) {
+	alerts := fakeWatchdog{{Kind: watchdog.AlertStall, Message: "no progress for 5m0s", Since: time.Now().UTC()}}
+	handler := NewServer(0, &fakeRepo{}, fakeStatus{}, Options{Watchdog: alerts}).Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	var health struct {
+		Status string           `json:"status"`
+		Alerts []watchdog.Alert `json:"alerts"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &health); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if health.Status != "degraded" || len(health.Alerts) != 1 || health.Alerts[0].Kind != watchdog.AlertStall {
+		t.Errorf("health: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	for _, want := range []string{
+		`solana_indexer_watchdog_alert{kind="stall"} 1`,
+		`solana_indexer_watchdog_alert{kind="errors"} 0`,
+	} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("metrics missing %q:\n%s", want, rec.Body.String())
+		}
+	}
+}
+
+type fakeBackfiller struct {
+	status *models.BackfillStatus
+}
+
+func (b *fakeBackfiller) StartBackfill(fromSlot, toSlot uint64) (*models.BackfillStatus, error) {
+	if b.status != nil && b.status.Running {
+		return nil, errors.New("a backfill is already running")
+	}
+	b.status = &models.BackfillStatus{FromSlot: fromSlot, ToSlot: toSlot, Running: true}
+	return b.status, nil
+}
+
+func (b *fakeBackfiller) BackfillStatus() *models.BackfillStatus {
+	return b.status
+}
+
+func TestServer_Backfill(t *testing.T) {
+	handler := NewServer(0, &fakeRepo{}, fakeStatus{}, Options{Backfiller: &fakeBackfiller{}}).Handler()
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		wantStatus int
+	}{
+		{"no backfill yet", http.MethodGet, "", http.StatusNotFound},
+		{"inverted range", http.MethodPost, `{"from_slot":20,"to_slot":10}`, http.StatusBadRequest},
+		{"start", http.MethodPost, `{"from_slot":10,"to_slot":20}`, http.StatusAccepted},
+		{"already running", http.MethodPost, `{"from_slot":30}`, http.StatusConflict},
+		{"status", http.MethodGet, "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(tt.method, "/api/v1/admin/backfill", strings.NewReader(tt.body)))
+		if rec.Code != tt.wantStatus {
+			t.Errorf("%s: status = %d, want %d: %s", tt.name, rec.Code, tt.wantStatus, rec.Body.String())
+		}
+	}
+}
+
+func TestServer_Networks(t *testing.T) {
+	adminHash := sha256.Sum256([]byte("admin-token"))
+	operatorHash := sha256.Sum256([]byte("operator-token"))
+	devnetPauser, mainnetPauser := &fakePauser{}, &fakePauser{}
+	devnetBackfill, mainnetBackfill := &fakeBackfiller{}, &fakeBackfiller{}
+	mainnet := NewServer(0, &fakeRepo{}, fakeStatus{}, Options{
+		Network:    "mainnet-beta",
+		Pauser:     mainnetPauser,
+		Backfiller: mainnetBackfill,
+	})
+	handler := NewServer(0, &fakeRepo{}, fakeStatus{}, Options{
+		Network:    "devnet",
+		Pauser:     devnetPauser,
+		Backfiller: devnetBackfill,
+		Networks:   []*Server{mainnet},
+		Users: []User{
+			{Name: "admin", Role: RoleAdmin, TokenHash: adminHash},
+			{Name: "operator", Role: RoleOperator, TokenHash: operatorHash},
+		},
+	}).Handler()
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		token      string
+		body       string
+		wantStatus int
+	}{
+		{name: "operator cannot pause mainnet", method: http.MethodPost, path: "/api/v1/admin/pause?network=mainnet-beta", token: "operator-token", wantStatus: http.StatusForbidden},
+		{name: "no token for mainnet", method: http.MethodGet, path: "/api/v1/status?network=mainnet-beta", wantStatus: http.StatusUnauthorized},
+		{name: "admin pauses mainnet", method: http.MethodPost, path: "/api/v1/admin/pause?network=mainnet-beta", token: "admin-token", wantStatus: http.StatusOK},
+		{name: "admin backfills mainnet", method: http.MethodPost, path: "/api/v1/admin/backfill?network=mainnet-beta", token: "admin-token", body: `{"from_slot":10,"to_slot":20}`, wantStatus: http.StatusAccepted},
+		{name: "devnet has no backfill", method: http.MethodGet, path: "/api/v1/admin/backfill", token: "admin-token", wantStatus: http.StatusNotFound},
+		{name: "unknown network", method: http.MethodPost, path: "/api/v1/admin/pause?network=testnet", token: "admin-token", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body = %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+	if !mainnetPauser.paused || devnetPauser.paused {
+		t.Errorf("paused devnet, mainnet = %v, %v, want false, true", devnetPauser.paused, mainnetPauser.paused)
+	}
+	if mainnetBackfill.status == nil || devnetBackfill.status != nil {
+		t.Errorf("backfills devnet, mainnet = %+v, %+v, want only mainnet", devnetBackfill.status, mainnetBackfill.status)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/status?network=mainnet-beta", nil)
+	req.Header.Set("Authorization", "Bearer admin-token")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	var status struct {
+		Network string `json:"network"`
+		Paused  bool   `json:"paused"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("decode status: %v", err)
+	}
+	if status.Network != "mainnet-beta" || !status.Paused {
+		t.Errorf("status = %+v, want mainnet-beta paused", status)
+	}
+}
+
+type fakeTokenAccountRepo struct {
+	fakeRepo
+	accounts map[string]models.TokenAccount
+	account  solana.PublicKey
+}
+
+func (r *fakeTokenAccountRepo) GetEventsByAccount(ctx context.Context, account solana.PublicKey, opts repository.AccountEventsOptions) (*repository.EventPage, error) {
+	r.account = account
+	return &repository.EventPage{}, nil
+}
+
+func (r *fakeTokenAccountRepo) SaveTokenAccounts(ctx context.Context, accounts []models.TokenAccount) error {
+	return nil
+}
+
+func (r *fakeTokenAccountRepo) GetTokenAccount(ctx context.Context, address string) (*models.TokenAccount, error) {
+	if a, ok := r.accounts[address]; ok {
+		return &a, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeTokenAccountRepo) GetTokenAccountsByOwner(ctx context.Context, owner string) ([]models.TokenAccount, error) {
+	var out []models.TokenAccount
+	for _, a := range r.accounts {
+		if a.Owner == owner {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+func TestServer_TokenAccounts(t *testing.T) {
+	ata := "7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU"
+	owner := "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM"
+	repo := &fakeTokenAccountRepo{accounts: map[string]models.TokenAccount{
+		ata: {Address: ata, Owner: owner, Mint: "9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin"},
+	}}
+	handler := NewServer(0, repo, fakeStatus{}, Options{}).Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/accounts/"+ata+"/events", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"token_account"`) {
+		t.Errorf("timeline: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if repo.account.String() != owner {
+		t.Errorf("timeline queried %s, want owner %s", repo.account, owner)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/wallets/"+owner+"/token-accounts", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"count":1`) {
+		t.Errorf("wallet token accounts: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/api/v1/token-accounts/" + ata, http.StatusOK},
+		{"/api/v1/token-accounts/" + owner, http.StatusNotFound},
+		{"/api/v1/token-accounts/nope", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rec.Code != tt.want {
+			t.Errorf("GET %s status = %d, want %d", tt.path, rec.Code, tt.want)
+		}
+	}
+}
+
+func TestServer_APIVersions(t *testing.T) {
+	repo := &fakeRepo{
+		events: map[string]interface{}{
+			testSignature: models.BaseEvent{Signature: testSignature, EventType: models.EventTypeCounterReset},
+		},
+		next: &repository.Cursor{Slot: 42, Signature: testSignature},
+	}
+	deprecation, err := ParseDeprecation("2026-01-01", "2026-12-31")
+	if err != nil {
+		t.Fatalf("ParseDeprecation() error = %v", err)
+	}
+	handler := NewServer(0, repo, fakeStatus{}, Options{V1Deprecation: deprecation}).Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v2/events?type=CounterResetEvent", nil))
+	var v2 struct {
+		Data []models.BaseEvent `json:"data"`
+		Page struct {
+			Count      int    `json:"count"`
+			NextCursor string `json:"next_cursor"`
+		} `json:"page"`
+		Events []models.BaseEvent `json:"events"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &v2); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(v2.Data) != 1 || v2.Page.Count != 1 || v2.Page.NextCursor == "" || v2.Events != nil {
+		t.Errorf("v2 body = %s, want the events in a data and page envelope", rec.Body.String())
+	}
+	if rec.Header().Get("Deprecation") != "" {
+		t.Errorf("v2 is marked deprecated")
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events?type=CounterResetEvent", nil))
+	if !strings.Contains(rec.Body.String(), `"events":`) {
+		t.Errorf("v1 body = %s, want the v1 shape", rec.Body.String())
+	}
+	headers := map[string]string{
+		"Deprecation": "@1767225600",
+		"Sunset":      "Thu, 31 Dec 2026 00:00:00 GMT",
+		"Link":        `</api/v2/events>; rel="successor-version"`,
+	}
+	for name, want := range headers {
+		if got := rec.Header().Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v3/events", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown version status = %d, want 404", rec.Code)
+	}
+}
+
+type fakeDecoder struct {
+	data []byte
+}
+
+func (d *fakeDecoder) DecodeEventData(data []byte) models.DecodedEvent {
+	d.data = data
+	return models.DecodedEvent{ProgramID: testAccount, EventType: models.EventTypeTokensMinted}
+}
+
+func (d *fakeDecoder) DecodeTransaction(tx *rpc.GetTransactionResult) ([]models.DecodedEvent, error) {
+	if tx.Meta == nil {
+		return nil, errors.New("transaction has no meta")
+	}
+	return []models.DecodedEvent{{ProgramID: testAccount, Error: "unknown event"}}, nil
+}
+
+func TestServer_Decode(t *testing.T) {
+	decoder := &fakeDecoder{}
+	handler := NewServer(0, &fakeRepo{}, fakeStatus{}, Options{Decoder: decoder}).Handler()
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantEvent  models.DecodedEvent
+	}{
+		{
+			name:       "event data",
+			body:       `{"data":"AQID"}`,
+			wantStatus: http.StatusOK,
+			wantEvent:  models.DecodedEvent{ProgramID: testAccount, EventType: models.EventTypeTokensMinted},
+		},
+		{
+			name:       "transaction",
+			body:       `{"transaction":{"slot":7,"meta":{"logMessages":[]}}}`,
+			wantStatus: http.StatusOK,
+			wantEvent:  models.DecodedEvent{ProgramID: testAccount, Error: "unknown event"},
+		},
+		{name: "transaction without meta", body: `{"transaction":{"slot":7}}`, wantStatus: http.StatusBadRequest},
+		{name: "bad base64", body: `{"data":"not base64!"}`, wantStatus: http.StatusBadRequest},
+		{name: "neither", body: `{}`, wantStatus: http.StatusBadRequest},
+		{name: "both", body: `{"data":"AQID","transaction":{}}`, wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/decode", strings.NewReader(tt.body)))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body = %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var body struct {
+				Events []models.DecodedEvent `json:"events"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if len(body.Events) != 1 || !reflect.DeepEqual(body.Events[0], tt.wantEvent) {
+				t.Errorf("events = %+v, want [%+v]", body.Events, tt.wantEvent)
+			}
+		})
+	}
+	if string(decoder.data) != "\x01\x02\x03" {
+		t.Errorf("decoded data = %x, want 010203", decoder.data)
+	}
+}
+
+type fakePipeline []models.PipelineQueue
+
+func (f fakePipeline) PipelineQueues() []models.PipelineQueue { return f }
+
+func TestServer_PipelineMetrics(t *testing.T) {
+	pipeline := fakePipeline{
+		{Program: "starter", Stage: "decode", Depth: 3, Capacity: 100},
+		{Program: "starter", Stage: "store", Depth: 100, Capacity: 100},
+	}
+	srv := NewServer(0, &fakeRepo{}, fakeStatus{}, Options{Pipeline: pipeline})
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	for _, want := range []string{
+		`solana_indexer_pipeline_queue_depth{program="starter",stage="decode"} 3`,
+		`solana_indexer_pipeline_queue_depth{program="starter",stage="store"} 100`,
+		`solana_indexer_pipeline_queue_capacity{program="starter",stage="store"} 100`,
+	} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("metrics missing %q:\n%s", want, rec.Body.String())
+		}
+	}
+}
+
+type fakeWatermarks models.Watermarks
+
+func (f fakeWatermarks) Watermarks() models.Watermarks { return models.Watermarks(f) }
+
+func TestServer_StatusWatermarks(t *testing.T) {
+	watermarks := fakeWatermarks{Processed: 120, Confirmed: 150, Finalized: 118}
+	srv := NewServer(0, &fakeRepo{}, fakeStatus{}, Options{Watermarks: watermarks})
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/status", nil))
+	var body struct {
+		Watermarks models.Watermarks `json:"watermarks"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode status: %v", err)
+	}
+	if body.Watermarks.Processed != 120 || body.Watermarks.Confirmed != 150 || body.Watermarks.Finalized != 118 {
+		t.Errorf("status watermarks = %+v, want processed 120, confirmed 150, finalized 118", body.Watermarks)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if want := `solana_indexer_watermark_slot{level="finalized"} 118`; !strings.Contains(rec.Body.String(), want) {
+		t.Errorf("metrics missing %q:\n%s", want, rec.Body.String())
+	}
+}
+
+type fakeWatchlistRepo struct {
+	fakeRepo
+	watched map[string]models.WatchedAddress
+}
+
+func (r *fakeWatchlistRepo) SaveWatchedAddress(ctx context.Context, watched *models.WatchedAddress) error {
+	r.watched[watched.Address] = *watched
+	return nil
+}
+
+func (r *fakeWatchlistRepo) GetWatchedAddresses(ctx context.Context) ([]models.WatchedAddress, error) {
+	var out []models.WatchedAddress
+	for _, watched := range r.watched {
+		out = append(out, watched)
+	}
+	return out, nil
+}
+
+func (r *fakeWatchlistRepo) GetWatchedAddress(ctx context.Context, address string) (*models.WatchedAddress, error) {
+	watched, ok := r.watched[address]
+	if !ok {
+		return nil, nil
+	}
+	return &watched, nil
+}
+
+func (r *fakeWatchlistRepo) DeleteWatchedAddress(ctx context.Context, address string) (bool, error) {
+	_, ok := r.watched[address]
+	delete(r.watched, address)
+	return ok, nil
+}
+
+type fakeWatchlist struct {
+	indexed solana.PublicKey
+	syncs   int
+}
+
+func (f *fakeWatchlist) IndexedAddress(address solana.PublicKey) bool { return address == f.indexed }
+
+func (f *fakeWatchlist) SyncWatchlist() { f.syncs++ }
+
+func TestServer_Watchlist(t *testing.T) {
+	created := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	existing, fresh, program := solana.PublicKey{1}, solana.PublicKey{2}, solana.PublicKey{3}
+	repo := &fakeWatchlistRepo{watched: map[string]models.WatchedAddress{
+		existing.String(): {Address: existing.String(), Label: "treasury", CreatedAt: created},
+	}}
+	watchlist := &fakeWatchlist{indexed: program}
+	handler := NewServer(0, repo, fakeStatus{}, Options{Watchlist: watchlist}).Handler()
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantField  string
+	}{
+		{name: "add", method: http.MethodPut, path: "/api/v1/admin/watchlist/" + fresh.String(), body: `{"label":"hot wallet","webhook":"https://example.com/hook"}`, wantStatus: http.StatusCreated},
+		{name: "replace keeps created_at", method: http.MethodPut, path: "/api/v1/admin/watchlist/" + existing.String(), body: `{"label":"cold wallet"}`, wantStatus: http.StatusOK},
+		{name: "bad address", method: http.MethodPut, path: "/api/v1/admin/watchlist/not-a-key", body: `{}`, wantStatus: http.StatusBadRequest, wantField: "address"},
+		{name: "already indexed", method: http.MethodPut, path: "/api/v1/admin/watchlist/" + program.String(), body: `{}`, wantStatus: http.StatusBadRequest, wantField: "address"},
+		{name: "bad webhook", method: http.MethodPut, path: "/api/v1/admin/watchlist/" + fresh.String(), body: `{"webhook":"ftp://example.com"}`, wantStatus: http.StatusBadRequest, wantField: "webhook"},
+		{name: "get", method: http.MethodGet, path: "/api/v1/admin/watchlist/" + fresh.String(), wantStatus: http.StatusOK},
+		{name: "list", method: http.MethodGet, path: "/api/v1/admin/watchlist", wantStatus: http.StatusOK},
+		{name: "delete", method: http.MethodDelete, path: "/api/v1/admin/watchlist/" + fresh.String(), wantStatus: http.StatusNoContent},
+		{name: "delete missing", method: http.MethodDelete, path: "/api/v1/admin/watchlist/" + fresh.String(), wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body = %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantField != "" {
+				var p Problem
+				if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+					t.Fatalf("decode problem: %v", err)
+				}
+				if len(p.Errors) == 0 || p.Errors[0].Field != tt.wantField {
+					t.Errorf("errors = %+v, want field %q", p.Errors, tt.wantField)
+				}
+			}
+		})
+	}
+
+	if got := repo.watched[existing.String()]; !got.CreatedAt.Equal(created) || got.Label != "cold wallet" {
+		t.Errorf("replaced entry = %+v, want new label with original created_at", got)
+	}
+	if watchlist.syncs != 3 {
+		t.Errorf("syncs = %d, want 3", watchlist.syncs)
+	}
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+// handleEventStats counts the events of a date range, the last 7 days by
+// default, by type and by UTC day and type. The counting is done by the
+// database, so ranges of months do not load their events.
+func (s *Server) handleEventStats(w http.ResponseWriter, r *http.Request) *Problem {
+	aggregator, ok := repository.Unwrap(s.repo).(repository.EventAggregator)
+	if !ok {
+		return NewProblem(CodeNotImplemented, "event stats are not supported by the configured database")
+	}
+	query := r.URL.Query()
+
+	var errs []FieldError
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if raw := query.Get("to"); raw != "" {
+		d, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			errs = append(errs, FieldError{Field: "to", Message: "must be a date in YYYY-MM-DD format"})
+		} else {
+			to = d
+		}
+	}
+	from := to.AddDate(0, 0, -(defaultAnalyticsDays - 1))
+	if raw := query.Get("from"); raw != "" {
+		d, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			errs = append(errs, FieldError{Field: "from", Message: "must be a date in YYYY-MM-DD format"})
+		} else {
+			from = d
+		}
+	}
+	if len(errs) > 0 {
+		return ValidationProblem(errs...)
+	}
+
+	if from.After(to) {
+		return ValidationProblem(FieldError{Field: "from", Message: "must not be after to"})
+	}
+	if to.Sub(from) >= maxAnalyticsDays*24*time.Hour {
+		return ValidationProblem(FieldError{Field: "from", Message: fmt.Sprintf("range must not exceed %d days", maxAnalyticsDays)})
+	}
+
+	// The range is inclusive of the whole "to" day.
+	filter := repository.EventFilter{From: from, To: to.AddDate(0, 0, 1)}
+	totals, err := aggregator.CountEventsByType(r.Context(), filter)
+	if err != nil {
+		return upstreamProblem(err)
+	}
+	days, err := aggregator.CountEventsByWindow(r.Context(), filter, 24*time.Hour)
+	if err != nil {
+		return upstreamProblem(err)
+	}
+
+	return writeJSON(w, http.StatusOK, map[string]interface{}{
+		"from":   from.Format(time.DateOnly),
+		"to":     to.Format(time.DateOnly),
+		"totals": totals,
+		"days":   days,
+	})
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+func (s *Server) tokenStore() (repository.TokenHolderStore, *Problem) {
+	store, ok := repository.Unwrap(s.repo).(repository.TokenHolderStore)
+	if !ok {
+		return nil, NewProblem(CodeNotImplemented, "token holders are not supported by the configured database")
+	}
+	return store, nil
+}
+
+// handleGetToken returns the supply and holder count of a mint.
+func (s *Server) handleGetToken(w http.ResponseWriter, r *http.Request) *Problem {
+	mint := r.PathValue("mint")
+	if _, err := solana.PublicKeyFromBase58(mint); err != nil {
+		return ValidationProblem(FieldError{Field: "mint", Message: "must be a base58 public key"})
+	}
+
+	store, p := s.tokenStore()
+	if p != nil {
+		return p
+	}
+	supply, err := store.GetTokenSupply(r.Context(), mint)
+	if err != nil {
+		return upstreamProblem(err)
+	}
+	if supply == nil {
+		return NewProblem(CodeNotFound, "no token events indexed for mint "+mint)
+	}
+
+	return writeJSON(w, http.StatusOK, supply)
+}
+
+// handleTokenHolders returns the holders of a mint, largest balance first.
+func (s *Server) handleTokenHolders(w http.ResponseWriter, r *http.Request) *Problem {
+	query := r.URL.Query()
+
+	var errs []FieldError
+	mint := r.PathValue("mint")
+	if _, err := solana.PublicKeyFromBase58(mint); err != nil {
+		errs = append(errs, FieldError{Field: "mint", Message: "must be a base58 public key"})
+	}
+	opts := repository.HolderPageOptions{Limit: defaultEventsLimit}
+	if raw := query.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 || n > maxEventsLimit {
+			errs = append(errs, FieldError{Field: "limit", Message: fmt.Sprintf("must be an integer between 1 and %d", maxEventsLimit)})
+		} else {
+			opts.Limit = n
+		}
+	}
+	if raw := query.Get("cursor"); raw != "" {
+		cursor, err := repository.ParseHolderCursor(raw)
+		if err != nil {
+			errs = append(errs, FieldError{Field: "cursor", Message: "must be a next_cursor returned by a previous page"})
+		} else {
+			opts.After = cursor
+		}
+	}
+	if len(errs) > 0 {
+		return ValidationProblem(errs...)
+	}
+
+	store, p := s.tokenStore()
+	if p != nil {
+		return p
+	}
+	page, err := store.GetTokenHolders(r.Context(), mint, opts)
+	if err != nil {
+		return upstreamProblem(err)
+	}
+
+	holders := page.Holders
+	if holders == nil {
+		holders = []models.TokenHolder{}
+	}
+	var next interface{}
+	if page.Next != nil {
+		next = page.Next.String()
+	}
+	return writeJSON(w, http.StatusOK, map[string]interface{}{
+		"mint":        mint,
+		"holders":     holders,
+		"count":       len(holders),
+		"next_cursor": next,
+	})
+}
+
+func (s *Server) tokenAccountStore() (repository.TokenAccountStore, *Problem) {
+	store, ok := repository.Unwrap(s.repo).(repository.TokenAccountStore)
+	if !ok {
+		return nil, NewProblem(CodeNotImplemented, "token accounts are not supported by the configured database")
+	}
+	return store, nil
+}
+
+// handleGetTokenAccount returns the owner and mint of an associated token
+// account.
+func (s *Server) handleGetTokenAccount(w http.ResponseWriter, r *http.Request) *Problem {
+	address := r.PathValue("address")
+	if _, err := solana.PublicKeyFromBase58(address); err != nil {
+		return ValidationProblem(FieldError{Field: "address", Message: "must be a base58 public key"})
+	}
+
+	store, p := s.tokenAccountStore()
+	if p != nil {
+		return p
+	}
+	account, err := store.GetTokenAccount(r.Context(), address)
+	if err != nil {
+		return upstreamProblem(err)
+	}
+	if account == nil {
+		return NewProblem(CodeNotFound, "no token account indexed for "+address)
+	}
+
+	return writeJSON(w, http.StatusOK, account)
+}
+
+// handleWalletTokenAccounts returns the associated token accounts of a
+// wallet for the mints it appeared with in token events.
+func (s *Server) handleWalletTokenAccounts(w http.ResponseWriter, r *http.Request) *Problem {
+	owner := r.PathValue("owner")
+	if _, err := solana.PublicKeyFromBase58(owner); err != nil {
+		return ValidationProblem(FieldError{Field: "owner", Message: "must be a base58 public key"})
+	}
+
+	store, p := s.tokenAccountStore()
+	if p != nil {
+		return p
+	}
+	accounts, err := store.GetTokenAccountsByOwner(r.Context(), owner)
+	if err != nil {
+		return upstreamProblem(err)
+	}
+	if accounts == nil {
+		accounts = []models.TokenAccount{}
+	}
+
+	return writeJSON(w, http.StatusOK, map[string]interface{}{
+		"owner":          owner,
+		"token_accounts": accounts,
+		"count":          len(accounts),
+	})
+}
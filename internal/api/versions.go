@@ -0,0 +1,166 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Deprecation announces that an API version is going away.
+type Deprecation struct {
+	// Since is when the version was deprecated.
+	Since time.Time
+	// Sunset is when it stops being served; zero while undecided.
+	Sunset time.Time
+}
+
+// ParseDeprecation reads YYYY-MM-DD dates; it returns nil when since is
+// empty.
+func ParseDeprecation(since, sunset string) (*Deprecation, error) {
+	if since == "" {
+		if sunset != "" {
+			return nil, fmt.Errorf("a sunset requires a deprecation date")
+		}
+		return nil, nil
+	}
+	var d Deprecation
+	var err error
+	if d.Since, err = time.Parse(time.DateOnly, since); err != nil {
+		return nil, fmt.Errorf("deprecation date must be YYYY-MM-DD")
+	}
+	if sunset != "" {
+		if d.Sunset, err = time.Parse(time.DateOnly, sunset); err != nil {
+			return nil, fmt.Errorf("sunset date must be YYYY-MM-DD")
+		}
+		if d.Sunset.Before(d.Since) {
+			return nil, fmt.Errorf("sunset must not be before the deprecation date")
+		}
+	}
+	return &d, nil
+}
+
+// dtoMapper reshapes the body a handler writes into a version's format.
+type dtoMapper func(body interface{}) interface{}
+
+// apiVersion is a major version of the API, served under /api/<name>.
+// Every version is served by the same handlers, which write the v1 shape;
+// a breaking change to a response ships as a mapper of the route in a new
+// version, so older versions keep their shape.
+type apiVersion struct {
+	name        string
+	mappers     map[string]dtoMapper
+	deprecation *Deprecation
+}
+
+// latestVersion is the version deprecated versions point to.
+const latestVersion = "v2"
+
+func apiVersions(v1Deprecation *Deprecation) []apiVersion {
+	return []apiVersion{
+		{name: "v1", deprecation: v1Deprecation},
+		{
+			// v2 wraps paginated lists in {"data": [...], "page": {...}}.
+			name: "v2",
+			mappers: map[string]dtoMapper{
+				"/events":                   pageEnvelope("events"),
+				"/accounts/{pubkey}/events": pageEnvelope("events"),
+				"/tokens/{mint}/holders":    pageEnvelope("holders"),
+				"/queries/{name}/results":   pageEnvelope("events"),
+			},
+		},
+	}
+}
+
+// handle serves pattern of this version, adding deprecation headers and
+// mapping the response body.
+func (v apiVersion) handle(pattern string, h http.Handler) http.Handler {
+	mapper := v.mappers[pattern]
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if d := v.deprecation; d != nil {
+			// RFC 9745 and RFC 8594.
+			w.Header().Set("Deprecation", "@"+strconv.FormatInt(d.Since.Unix(), 10))
+			if !d.Sunset.IsZero() {
+				w.Header().Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+			}
+			if _, rest, ok := splitVersion(r.URL.Path); ok {
+				w.Header().Set("Link", "</api/"+latestVersion+rest+`>; rel="successor-version"`)
+			}
+		}
+		if mapper != nil {
+			w = &mappedWriter{ResponseWriter: w, mapper: mapper}
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// mappedWriter carries a version's mapper to writeJSON.
+type mappedWriter struct {
+	http.ResponseWriter
+	mapper dtoMapper
+}
+
+// splitVersion splits /api/<version>/<rest> into the version and /<rest>.
+func splitVersion(path string) (version, rest string, ok bool) {
+	rest, ok = strings.CutPrefix(path, "/api/")
+	if !ok {
+		return "", "", false
+	}
+	version, rest, ok = strings.Cut(rest, "/")
+	if !ok || version == "" {
+		return "", "", false
+	}
+	return version, "/" + rest, true
+}
+
+// pageEnvelope moves the items under key and the count and next_cursor of
+// a v1 list body into data and page.
+func pageEnvelope(key string) dtoMapper {
+	return func(body interface{}) interface{} {
+		m, ok := body.(map[string]interface{})
+		if !ok {
+			return body
+		}
+		out := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			switch k {
+			case key, "count", "next_cursor":
+			default:
+				out[k] = v
+			}
+		}
+		out["data"] = m[key]
+		out["page"] = map[string]interface{}{
+			"count":       m["count"],
+			"next_cursor": m["next_cursor"],
+		}
+		return out
+	}
+}
+
+func (s *Server) handleVersions(w http.ResponseWriter, r *http.Request) *Problem {
+	type versionInfo struct {
+		Version         string     `json:"version"`
+		Path            string     `json:"path"`
+		Deprecated      bool       `json:"deprecated"`
+		DeprecatedSince *time.Time `json:"deprecated_since,omitempty"`
+		Sunset          *time.Time `json:"sunset,omitempty"`
+	}
+	versions := make([]versionInfo, 0, len(s.versions))
+	for _, v := range s.versions {
+		info := versionInfo{Version: v.name, Path: "/api/" + v.name}
+		if d := v.deprecation; d != nil {
+			info.Deprecated = true
+			info.DeprecatedSince = &d.Since
+			if !d.Sunset.IsZero() {
+				info.Sunset = &d.Sunset
+			}
+		}
+		versions = append(versions, info)
+	}
+	return writeJSON(w, http.StatusOK, map[string]interface{}{
+		"versions": versions,
+		"latest":   latestVersion,
+	})
+}
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+const (
+	maxWatchBody  = 16 << 10
+	maxWatchLabel = 128
+)
+
+// Watchlist runs the address watchlist of the indexer.
+type Watchlist interface {
+	// IndexedAddress reports whether address is indexed otherwise, so
+	// cannot be watched.
+	IndexedAddress(address solana.PublicKey) bool
+	// SyncWatchlist applies the stored watchlist.
+	SyncWatchlist()
+}
+
+type watchRequest struct {
+	Label   string `json:"label"`
+	Webhook string `json:"webhook"`
+}
+
+func (s *Server) watchlistStore() (repository.WatchlistStore, *Problem) {
+	if s.watchlist == nil {
+		return nil, NewProblem(CodeNotImplemented, "the address watchlist is not supported")
+	}
+	store, ok := repository.Unwrap(s.repo).(repository.WatchlistStore)
+	if !ok {
+		return nil, NewProblem(CodeNotImplemented, "the address watchlist is not supported by the configured database")
+	}
+	return store, nil
+}
+
+func (s *Server) handleListWatchlist(w http.ResponseWriter, r *http.Request) *Problem {
+	store, p := s.watchlistStore()
+	if p != nil {
+		return p
+	}
+
+	addresses, err := store.GetWatchedAddresses(r.Context())
+	if err != nil {
+		return upstreamProblem(err)
+	}
+	if addresses == nil {
+		addresses = []models.WatchedAddress{}
+	}
+
+	return writeJSON(w, http.StatusOK, map[string]interface{}{
+		"addresses": addresses,
+		"count":     len(addresses),
+	})
+}
+
+func (s *Server) handleGetWatched(w http.ResponseWriter, r *http.Request) *Problem {
+	address := r.PathValue("address")
+	store, p := s.watchlistStore()
+	if p != nil {
+		return p
+	}
+
+	watched, err := store.GetWatchedAddress(r.Context(), address)
+	if err != nil {
+		return upstreamProblem(err)
+	}
+	if watched == nil {
+		return NewProblem(CodeNotFound, address+" is not watched")
+	}
+
+	return writeJSON(w, http.StatusOK, watched)
+}
+
+// handlePutWatched adds an address to the watchlist or replaces its label
+// and webhook. The indexer starts on it right away.
+func (s *Server) handlePutWatched(w http.ResponseWriter, r *http.Request) *Problem {
+	address := r.PathValue("address")
+	key, err := solana.PublicKeyFromBase58(address)
+	if err != nil {
+		return ValidationProblem(FieldError{Field: "address", Message: "must be a base58 public key"})
+	}
+
+	var req watchRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxWatchBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		return ValidationProblem(FieldError{Field: "body", Message: "must be a JSON watchlist entry: " + err.Error()})
+	}
+	if errs := validateWatch(&req); len(errs) > 0 {
+		return ValidationProblem(errs...)
+	}
+
+	store, p := s.watchlistStore()
+	if p != nil {
+		return p
+	}
+	if s.watchlist.IndexedAddress(key) {
+		return ValidationProblem(FieldError{Field: "address", Message: "is already indexed as a program, SPL token mint or SOL_WATCHLIST address"})
+	}
+	existing, err := store.GetWatchedAddress(r.Context(), key.String())
+	if err != nil {
+		return upstreamProblem(err)
+	}
+
+	watched := &models.WatchedAddress{
+		Address:   key.String(),
+		Label:     req.Label,
+		Webhook:   req.Webhook,
+		CreatedAt: time.Now().UTC(),
+	}
+	status := http.StatusCreated
+	if existing != nil {
+		watched.CreatedAt = existing.CreatedAt
+		status = http.StatusOK
+	}
+	if err := store.SaveWatchedAddress(r.Context(), watched); err != nil {
+		return upstreamProblem(err)
+	}
+	s.watchlist.SyncWatchlist()
+
+	return writeJSON(w, status, watched)
+}
+
+func (s *Server) handleDeleteWatched(w http.ResponseWriter, r *http.Request) *Problem {
+	address := r.PathValue("address")
+	store, p := s.watchlistStore()
+	if p != nil {
+		return p
+	}
+
+	deleted, err := store.DeleteWatchedAddress(r.Context(), address)
+	if err != nil {
+		return upstreamProblem(err)
+	}
+	if !deleted {
+		return NewProblem(CodeNotFound, address+" is not watched")
+	}
+	s.watchlist.SyncWatchlist()
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func validateWatch(req *watchRequest) []FieldError {
+	var errs []FieldError
+	if len(req.Label) > maxWatchLabel {
+		errs = append(errs, FieldError{Field: "label", Message: "must be at most 128 characters"})
+	}
+	if req.Webhook != "" {
+		u, err := url.Parse(req.Webhook)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, FieldError{Field: "webhook", Message: "must be an http or https URL"})
+		}
+	}
+	return errs
+}
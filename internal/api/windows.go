@@ -0,0 +1,36 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/aggregate"
+)
+
+func (s *Server) handleListWindows(w http.ResponseWriter, r *http.Request) *Problem {
+	series := s.streamWindows()
+	return writeJSON(w, http.StatusOK, map[string]interface{}{
+		"metrics": series,
+		"count":   len(series),
+	})
+}
+
+func (s *Server) handleGetWindows(w http.ResponseWriter, r *http.Request) *Problem {
+	name := r.PathValue("name")
+	for _, series := range s.streamWindows() {
+		if series.Name == name {
+			return writeJSON(w, http.StatusOK, series)
+		}
+	}
+	return NewProblem(CodeNotFound, "no stream window metric named "+name)
+}
+
+func (s *Server) streamWindows() []aggregate.Series {
+	var series []aggregate.Series
+	if s.windows != nil {
+		series = s.windows.StreamWindows()
+	}
+	if series == nil {
+		series = []aggregate.Series{}
+	}
+	return series
+}
@@ -0,0 +1,195 @@
+// Package backup writes the state of the indexer, that is the events with
+// their dedup metadata, the checkpoints and the projections, to a file and
+// restores it, to clone an environment or roll one back to a known-good
+// point.
+//
+// A backup is gzip compressed JSON lines: a Header, then one record per
+// document.
+package backup
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+// Version is the format version Write produces and Restore accepts.
+const Version = 1
+
+// restoreBatchSize is the number of documents restored at once.
+const restoreBatchSize = 1000
+
+// maxRecordSize bounds a line of a backup; MongoDB documents are at most
+// 16 MiB and grow a little as extended JSON.
+const maxRecordSize = 64 << 20
+
+// Header starts a backup.
+type Header struct {
+	Version int `json:"version"`
+	// Backend is the database type the documents are encoded for.
+	Backend   string    `json:"backend"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type record struct {
+	Collection string          `json:"collection"`
+	Document   json.RawMessage `json:"document"`
+}
+
+// Counts is the number of documents of each collection.
+type Counts map[string]int64
+
+// Total returns the number of documents of all collections.
+func (c Counts) Total() int64 {
+	var total int64
+	for _, n := range c {
+		total += n
+	}
+	return total
+}
+
+var errNotEmpty = errors.New("not empty")
+
+// Write writes every collection of store to w.
+func Write(ctx context.Context, w io.Writer, store repository.StateStore, backend string, now time.Time) (Counts, error) {
+	collections, err := store.StateCollections(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	zw := gzip.NewWriter(w)
+	enc := json.NewEncoder(zw)
+	if err := enc.Encode(Header{Version: Version, Backend: backend, CreatedAt: now.UTC()}); err != nil {
+		return nil, fmt.Errorf("write header: %w", err)
+	}
+	counts := make(Counts, len(collections))
+	for _, collection := range collections {
+		err := store.DumpState(ctx, collection, func(doc json.RawMessage) error {
+			counts[collection]++
+			return enc.Encode(record{Collection: collection, Document: doc})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("dump %s: %w", collection, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("write backup: %w", err)
+	}
+	return counts, nil
+}
+
+// Verify reads a backup to its end, checking that it is complete and was
+// written for backend.
+func Verify(r io.Reader, backend string) (*Header, Counts, error) {
+	counts := make(Counts)
+	header, err := read(r, backend, func(rec record) error {
+		counts[rec.Collection]++
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return header, counts, nil
+}
+
+// Restore replaces the state of store with a backup. Unless replace is
+// set, store must hold no documents. A backup that turns out to be
+// corrupt halfway leaves a partial state, so Verify it first.
+func Restore(ctx context.Context, r io.Reader, store repository.StateStore, backend string, replace bool) (Counts, error) {
+	current, err := store.StateCollections(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, collection := range current {
+		if replace {
+			if err := store.ClearState(ctx, collection); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		err := store.DumpState(ctx, collection, func(json.RawMessage) error { return errNotEmpty })
+		if errors.Is(err, errNotEmpty) {
+			return nil, fmt.Errorf("collection %s is not empty; restore with replace to drop the current state", collection)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	counts := make(Counts)
+	var batch []json.RawMessage
+	var batchCollection string
+	flush := func() error {
+		if err := store.RestoreState(ctx, batchCollection, batch); err != nil {
+			return err
+		}
+		counts[batchCollection] += int64(len(batch))
+		batch = batch[:0]
+		return nil
+	}
+	_, err = read(r, backend, func(rec record) error {
+		if len(batch) > 0 && (rec.Collection != batchCollection || len(batch) == restoreBatchSize) {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		batchCollection = rec.Collection
+		batch = append(batch, rec.Document)
+		return nil
+	})
+	if err == nil && len(batch) > 0 {
+		err = flush()
+	}
+	return counts, err
+}
+
+// read calls fn with every record of a backup.
+func read(r io.Reader, backend string, fn func(record) error) (*Header, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("read backup: %w", err)
+	}
+	scanner := bufio.NewScanner(zr)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRecordSize)
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("read header: %w", err)
+		}
+		return nil, fmt.Errorf("read header: backup is empty")
+	}
+	var header Header
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		return nil, fmt.Errorf("decode header: %w", err)
+	}
+	if header.Version != Version {
+		return nil, fmt.Errorf("backup version %d is not supported, want %d", header.Version, Version)
+	}
+	if header.Backend != backend {
+		return nil, fmt.Errorf("backup of a %q database cannot be restored into %q", header.Backend, backend)
+	}
+
+	for line := 2; scanner.Scan(); line++ {
+		var rec record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if rec.Collection == "" || len(rec.Document) == 0 {
+			return nil, fmt.Errorf("line %d: record without collection or document", line)
+		}
+		if err := fn(rec); err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read backup: %w", err)
+	}
+	return &header, nil
+}
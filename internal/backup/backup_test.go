@@ -0,0 +1,134 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memoryStore is a StateStore keeping documents in memory.
+type memoryStore struct {
+	collections map[string][]json.RawMessage
+	batches     int
+}
+
+func (s *memoryStore) StateCollections(ctx context.Context) ([]string, error) {
+	var names []string
+	for name := range s.collections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *memoryStore) DumpState(ctx context.Context, collection string, fn func(json.RawMessage) error) error {
+	for _, doc := range s.collections[collection] {
+		if err := fn(doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *memoryStore) ClearState(ctx context.Context, collection string) error {
+	s.collections[collection] = nil
+	return nil
+}
+
+func (s *memoryStore) RestoreState(ctx context.Context, collection string, docs []json.RawMessage) error {
+	s.batches++
+	s.collections[collection] = append(s.collections[collection], docs...)
+	return nil
+}
+
+func docs(n int) []json.RawMessage {
+	out := make([]json.RawMessage, n)
+	for i := range out {
+		out[i] = json.RawMessage(fmt.Sprintf(`{"_id":%d}`, i))
+	}
+	return out
+}
+
+func TestWriteRestore(t *testing.T) {
+	source := &memoryStore{collections: map[string][]json.RawMessage{
+		"checkpoints":  {json.RawMessage(`{"_id":"counter","slot":{"$numberLong":"42"}}`)},
+		"events":       docs(restoreBatchSize + 1),
+		"token_supply": nil,
+	}}
+	var buf bytes.Buffer
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	counts, err := Write(context.Background(), &buf, source, "mongodb", now)
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if counts.Total() != restoreBatchSize+2 {
+		t.Errorf("Write() counts = %v, want %d documents", counts, restoreBatchSize+2)
+	}
+
+	header, verified, err := Verify(bytes.NewReader(buf.Bytes()), "mongodb")
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if !header.CreatedAt.Equal(now) || !reflect.DeepEqual(verified, counts) {
+		t.Errorf("Verify() = %+v, %v, want created at %v, %v", header, verified, now, counts)
+	}
+
+	target := &memoryStore{collections: map[string][]json.RawMessage{
+		"checkpoints": {json.RawMessage(`{"_id":"counter","slot":{"$numberLong":"99"}}`)},
+		"later":       docs(3),
+	}}
+	if _, err := Restore(context.Background(), bytes.NewReader(buf.Bytes()), target, "mongodb", false); err == nil || !strings.Contains(err.Error(), "not empty") {
+		t.Fatalf("Restore() without replace error = %v, want not empty", err)
+	}
+	restored, err := Restore(context.Background(), bytes.NewReader(buf.Bytes()), target, "mongodb", true)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if !reflect.DeepEqual(restored, counts) {
+		t.Errorf("Restore() counts = %v, want %v", restored, counts)
+	}
+	if target.batches != 3 {
+		t.Errorf("Restore() wrote %d batches, want 3", target.batches)
+	}
+	if len(target.collections["later"]) != 0 {
+		t.Errorf("Restore() kept %d documents of a collection missing from the backup", len(target.collections["later"]))
+	}
+	for name, want := range source.collections {
+		if got := target.collections[name]; len(got) != len(want) || (len(want) > 0 && string(got[0]) != string(want[0])) {
+			t.Errorf("Restore() %s = %d documents, want %d", name, len(got), len(want))
+		}
+	}
+}
+
+func TestVerify_Invalid(t *testing.T) {
+	source := &memoryStore{collections: map[string][]json.RawMessage{"events": docs(100)}}
+	var buf bytes.Buffer
+	if _, err := Write(context.Background(), &buf, source, "mongodb", time.Now()); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		backend string
+		wantErr string
+	}{
+		{name: "other backend", data: buf.Bytes(), backend: "postgres", wantErr: "cannot be restored"},
+		{name: "truncated", data: buf.Bytes()[:buf.Len()-10], backend: "mongodb", wantErr: "read backup"},
+		{name: "not gzip", data: []byte(`{"version":1}`), backend: "mongodb", wantErr: "read backup"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Verify(bytes.NewReader(tt.data), tt.backend)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Verify() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
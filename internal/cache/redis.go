@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const defaultRedisPoolSize = 8
+
+// RedisClient wraps a go-redis client with the handful of commands the
+// indexer needs.
+type RedisClient struct {
+	client *redis.Client
+}
+
+// NewRedisClient connects using a redis:// or rediss:// URL, e.g.
+// redis://:password@localhost:6379/0.
+func NewRedisClient(rawURL string) (*RedisClient, error) {
+	opts, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse redis url: %w", err)
+	}
+	opts.PoolSize = defaultRedisPoolSize
+	opts.DialTimeout = 5 * time.Second
+
+	c := &RedisClient{client: redis.NewClient(opts)}
+	ctx, cancel := context.WithTimeout(context.Background(), opts.DialTimeout)
+	defer cancel()
+	if err := c.client.Ping(ctx).Err(); err != nil {
+		c.client.Close()
+		return nil, fmt.Errorf("ping redis: %w", err)
+	}
+	return c, nil
+}
+
+func (c *RedisClient) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (c *RedisClient) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, key, value, ttl).Err()
+}
+
+func (c *RedisClient) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return c.client.Del(ctx, keys...).Err()
+}
+
+func (c *RedisClient) Incr(ctx context.Context, key string) (int64, error) {
+	return c.client.Incr(ctx, key).Result()
+}
+
+// MGet returns the values of keys in order, nil for missing keys.
+func (c *RedisClient) MGet(ctx context.Context, keys ...string) ([][]byte, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	items, err := c.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	values := make([][]byte, len(items))
+	for i, item := range items {
+		if s, ok := item.(string); ok {
+			values[i] = []byte(s)
+		}
+	}
+	return values, nil
+}
+
+func (c *RedisClient) Append(ctx context.Context, key string, value []byte) error {
+	return c.client.Append(ctx, key, string(value)).Err()
+}
+
+func (c *RedisClient) PExpire(ctx context.Context, key string, ttl time.Duration) error {
+	return c.client.PExpire(ctx, key, ttl).Err()
+}
+
+func (c *RedisClient) ZAdd(ctx context.Context, key string, score float64, member string) error {
+	return c.client.ZAdd(ctx, key, redis.Z{Score: score, Member: member}).Err()
+}
+
+// ZRevRangeByLex returns up to count members between max and min, which use
+// the ZRANGEBYLEX syntax ("[a", "(a", "+" or "-"), highest first.
+func (c *RedisClient) ZRevRangeByLex(ctx context.Context, key, max, min string, count int) ([]string, error) {
+	return c.client.ZRevRangeByLex(ctx, key, &redis.ZRangeBy{Min: min, Max: max, Count: int64(count)}).Result()
+}
+
+func (c *RedisClient) ZRemRangeByLex(ctx context.Context, key, min, max string) error {
+	return c.client.ZRemRangeByLex(ctx, key, min, max).Err()
+}
+
+func (c *RedisClient) Publish(ctx context.Context, channel string, message []byte) error {
+	return c.client.Publish(ctx, channel, message).Err()
+}
+
+func (c *RedisClient) Close() error {
+	return c.client.Close()
+}
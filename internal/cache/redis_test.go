@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func newTestRedis(t *testing.T, password string) (*miniredis.Miniredis, *RedisClient) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	url := "redis://" + server.Addr()
+	if password != "" {
+		server.RequireAuth(password)
+		url = "redis://:" + password + "@" + server.Addr() + "/0"
+	}
+	client, err := NewRedisClient(url)
+	if err != nil {
+		t.Fatalf("NewRedisClient() error = %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return server, client
+}
+
+func TestRedisClient_Commands(t *testing.T) {
+	server, client := newTestRedis(t, "s3cret")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, ok, err := client.Get(ctx, "missing"); err != nil || ok {
+		t.Errorf("Get(missing) = ok %v, err %v; want miss", ok, err)
+	}
+
+	value := []byte("binary\r\nvalue\x00")
+	if err := client.Set(ctx, "key", value, time.Minute); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	got, ok, err := client.Get(ctx, "key")
+	if err != nil || !ok || string(got) != string(value) {
+		t.Errorf("Get(key) = %q, %v, %v; want %q", got, ok, err, value)
+	}
+	if ttl := server.TTL("key"); ttl != time.Minute {
+		t.Errorf("TTL(key) = %v, want %v", ttl, time.Minute)
+	}
+
+	for want := int64(1); want <= 2; want++ {
+		n, err := client.Incr(ctx, "counter")
+		if err != nil || n != want {
+			t.Errorf("Incr() = %d, %v; want %d", n, err, want)
+		}
+	}
+
+	if err := client.Delete(ctx, "key"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, ok, _ := client.Get(ctx, "key"); ok {
+		t.Error("Get(key) after Delete should miss")
+	}
+
+	for _, part := range []string{"a", "b"} {
+		if err := client.Append(ctx, "appended", []byte(part)); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+	values, err := client.MGet(ctx, "appended", "missing")
+	if err != nil || len(values) != 2 || string(values[0]) != "ab" || values[1] != nil {
+		t.Errorf("MGet() = %q, %v; want [ab <nil>]", values, err)
+	}
+
+	for _, member := range []string{"a", "b", "c"} {
+		if err := client.ZAdd(ctx, "set", 0, member); err != nil {
+			t.Fatalf("ZAdd() error = %v", err)
+		}
+	}
+	members, err := client.ZRevRangeByLex(ctx, "set", "+", "-", 2)
+	if err != nil || len(members) != 2 || members[0] != "c" || members[1] != "b" {
+		t.Errorf("ZRevRangeByLex() = %v, %v; want [c b]", members, err)
+	}
+	if err := client.ZRemRangeByLex(ctx, "set", "-", "(c"); err != nil {
+		t.Fatalf("ZRemRangeByLex() error = %v", err)
+	}
+	if members, _ := server.ZMembers("set"); len(members) != 1 || members[0] != "c" {
+		t.Errorf("members after ZRemRangeByLex = %v, want [c]", members)
+	}
+
+	sub := server.NewSubscriber()
+	defer sub.Close()
+	sub.Subscribe("events")
+	published := make(chan string, 1)
+	go func() { published <- (<-sub.Messages()).Message }()
+	if err := client.Publish(ctx, "events", []byte("payload")); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	select {
+	case msg := <-published:
+		if msg != "payload" {
+			t.Errorf("published = %q, want payload", msg)
+		}
+	case <-time.After(time.Second):
+		t.Error("Publish() did not reach the subscriber")
+	}
+}
+
+func TestNewRedisClient_Errors(t *testing.T) {
+	for _, raw := range []string{"http://localhost:6379", "redis://localhost:6379/abc"} {
+		if _, err := NewRedisClient(raw); err == nil {
+			t.Errorf("NewRedisClient(%q) expected error", raw)
+		}
+	}
+
+	server := miniredis.RunT(t)
+	server.RequireAuth("s3cret")
+	if _, err := NewRedisClient("redis://:wrong@" + server.Addr()); err == nil {
+		t.Error("NewRedisClient() with a wrong password expected error")
+	}
+}
@@ -0,0 +1,536 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// primitives maps the IDL primitives the generator supports to Go types.
+// Each decodes with bin.Decoder.Decode.
+var primitives = map[string]string{
+	"bool":      "bool",
+	"u8":        "uint8",
+	"i8":        "int8",
+	"u16":       "uint16",
+	"i16":       "int16",
+	"u32":       "uint32",
+	"i32":       "int32",
+	"u64":       "uint64",
+	"i64":       "int64",
+	"f32":       "float32",
+	"f64":       "float64",
+	"string":    "string",
+	"bytes":     "[]byte",
+	"pubkey":    "solana.PublicKey",
+	"publicKey": "solana.PublicKey",
+}
+
+// initialisms are the name parts written in upper case.
+var initialisms = map[string]string{"id": "ID", "uri": "URI", "url": "URL"}
+
+// imports are the packages generated code may use, by the identifier it
+// refers to them with; the standard library first.
+var imports = []struct{ name, path string }{
+	{"errors", `"errors"`},
+	{"fmt", `"fmt"`},
+	{"bin", `bin "github.com/gagliardetto/binary"`},
+	{"solana", `"github.com/gagliardetto/solana-go"`},
+}
+
+const firstThirdPartyImport = 2
+
+// Generate returns the Go source files of package pkg for idl by name:
+// types.go with the types shared by the others, instructions.go,
+// accounts.go and events.go.
+func Generate(idl *IDL, pkg string) (map[string][]byte, error) {
+	g := &generator{pkg: pkg, program: idl.Metadata.Name, types: make(map[string]*IDLTypeDef, len(idl.Types))}
+	if g.program == "" {
+		g.program = pkg
+	}
+	for n := range idl.Types {
+		g.types[idl.Types[n].Name] = &idl.Types[n]
+	}
+
+	// Accounts and events are generated in their own files, the types they
+	// and the instructions use in types.go.
+	used := make(map[string]bool)
+	var own []string
+	for _, a := range idl.Accounts {
+		if err := g.useTop("account", a.Name, used); err != nil {
+			return nil, err
+		}
+		own = append(own, a.Name)
+	}
+	for _, e := range idl.Events {
+		if err := g.useTop("event", e.Name, used); err != nil {
+			return nil, err
+		}
+		own = append(own, e.Name)
+	}
+	for _, ix := range idl.Instructions {
+		for _, a := range ix.Accounts {
+			if len(a.Accounts) > 0 && string(a.Accounts) != "null" {
+				return nil, fmt.Errorf("instruction %s: account group %s is not supported", ix.Name, a.Name)
+			}
+		}
+		for _, arg := range ix.Args {
+			if err := g.useType(&arg.Type, used); err != nil {
+				return nil, fmt.Errorf("instruction %s: argument %s: %w", ix.Name, arg.Name, err)
+			}
+		}
+	}
+	var shared []string
+	for name := range used {
+		if !slices.Contains(own, name) {
+			shared = append(shared, name)
+		}
+	}
+	sort.Strings(shared)
+
+	files := make(map[string][]byte, 4)
+	for name, body := range map[string]func() (string, error){
+		"types.go":        func() (string, error) { return g.typesFile(shared) },
+		"instructions.go": func() (string, error) { return g.instructionsFile(idl.Instructions) },
+		"accounts.go":     func() (string, error) { return g.accountsFile(idl.Accounts) },
+		"events.go":       func() (string, error) { return g.eventsFile(idl.Events) },
+	} {
+		src, err := body()
+		if err != nil {
+			return nil, err
+		}
+		if files[name], err = g.file(src, name == "types.go"); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return files, nil
+}
+
+type generator struct {
+	pkg     string
+	program string
+	types   map[string]*IDLTypeDef
+	buf     bytes.Buffer
+}
+
+// file returns the formatted source of a generated file with body, adding
+// the imports it uses.
+func (g *generator) file(body string, doc bool) ([]byte, error) {
+	var src strings.Builder
+	src.WriteString("// Code generated by \"indexer codegen\"; DO NOT EDIT.\n\n")
+	if doc {
+		fmt.Fprintf(&src, "// Package %s holds the instruction, account and event types of the\n// %s program with their Borsh decoders.\n", g.pkg, g.program)
+	}
+	fmt.Fprintf(&src, "package %s\n\n", g.pkg)
+	src.WriteString("import (\n")
+	for n, imp := range imports {
+		if n == firstThirdPartyImport {
+			src.WriteString("\n")
+		}
+		if strings.Contains(body, imp.name+".") {
+			src.WriteString(imp.path + "\n")
+		}
+	}
+	src.WriteString(")\n\n")
+	src.WriteString(body)
+
+	out, err := format.Source([]byte(src.String()))
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+	return out, nil
+}
+
+// take returns what was written to the buffer and resets it.
+func (g *generator) take() string {
+	s := g.buf.String()
+	g.buf.Reset()
+	return s
+}
+
+func (g *generator) typesFile(shared []string) (string, error) {
+	g.buf.WriteString(`// decodeLength decodes the u32 length of a Borsh vector.
+func decodeLength(decoder *bin.Decoder) (int, error) {
+	n, err := decoder.ReadUint32(bin.LE)
+	if err != nil {
+		return 0, err
+	}
+	if int(n) > decoder.Remaining() {
+		return 0, fmt.Errorf("length %d exceeds the remaining %d bytes", n, decoder.Remaining())
+	}
+	return int(n), nil
+}
+
+`)
+	for _, name := range shared {
+		def := g.types[name]
+		var err error
+		if def.Type.Kind == "enum" {
+			err = g.enum(def)
+		} else {
+			err = g.structType(def, fmt.Sprintf("%s is a type of the program.", def.Name))
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return g.take(), nil
+}
+
+func (g *generator) instructionsFile(instructions []IDLInstruction) (string, error) {
+	g.buf.WriteString(`// InstructionAccount is an account an instruction takes.
+type InstructionAccount struct {
+	Name     string
+	Writable bool
+	Signer   bool
+}
+
+// ErrUnknownInstruction is returned by DecodeInstruction for data without
+// the discriminator of a known instruction.
+var ErrUnknownInstruction = errors.New("unknown instruction")
+
+`)
+	g.table("InstructionNames", "instruction", len(instructions), func(n int) ([8]byte, string) {
+		return instructions[n].Discriminator, instructions[n].Name
+	})
+
+	g.buf.WriteString("// DecodeInstruction decodes the data of any instruction of the program\n// into its arguments.\n")
+	g.buf.WriteString("func DecodeInstruction(data []byte) (interface{}, error) {\n")
+	g.buf.WriteString("if len(data) < 8 {\nreturn nil, fmt.Errorf(\"%w: data too short for discriminator\", ErrUnknownInstruction)\n}\n")
+	g.buf.WriteString("switch [8]byte(data[:8]) {\n")
+	for _, ix := range instructions {
+		name := typeName(ix.Name)
+		fmt.Fprintf(&g.buf, "case %sInstructionDiscriminator:\nreturn Decode%sArgs(data)\n", name, name)
+	}
+	g.buf.WriteString("}\nreturn nil, fmt.Errorf(\"%w: discriminator %x\", ErrUnknownInstruction, data[:8])\n}\n\n")
+
+	for _, ix := range instructions {
+		name := typeName(ix.Name)
+		def := &IDLTypeDef{Name: name + "Args", Docs: ix.Docs}
+		def.Type.Kind = "struct"
+		def.Type.Fields = ix.Args
+		if err := g.structType(def, fmt.Sprintf("%sArgs are the arguments of the %s instruction.", name, ix.Name)); err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(&g.buf, "// %sInstructionDiscriminator prefixes the data of %s instructions.\n", name, ix.Name)
+		fmt.Fprintf(&g.buf, "var %sInstructionDiscriminator = %s\n\n", name, discriminatorLiteral(ix.Discriminator))
+
+		fmt.Fprintf(&g.buf, "// %sAccounts are the accounts %s takes, in order.\n", name, ix.Name)
+		fmt.Fprintf(&g.buf, "var %sAccounts = []InstructionAccount{\n", name)
+		for _, a := range ix.Accounts {
+			fmt.Fprintf(&g.buf, "{Name: %q, Writable: %t, Signer: %t},\n", a.Name, a.Writable, a.Signer)
+		}
+		g.buf.WriteString("}\n\n")
+
+		g.decodeFunc(name+"Args", name+"InstructionDiscriminator", ix.Name+" instruction")
+	}
+	return g.take(), nil
+}
+
+func (g *generator) accountsFile(accounts []IDLAccount) (string, error) {
+	g.buf.WriteString(`// ErrUnknownAccount is returned by DecodeAccount for data without the
+// discriminator of a known account.
+var ErrUnknownAccount = errors.New("unknown account")
+
+`)
+	g.table("AccountNames", "account", len(accounts), func(n int) ([8]byte, string) {
+		return accounts[n].Discriminator, accounts[n].Name
+	})
+	g.dispatch("DecodeAccount", "account", "ErrUnknownAccount", len(accounts), func(n int) string { return accounts[n].Name })
+
+	for _, a := range accounts {
+		if err := g.structType(g.types[a.Name], fmt.Sprintf("%s is the data of a %s account.", a.Name, a.Name)); err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&g.buf, "// %sDiscriminator prefixes the data of %s accounts.\n", a.Name, a.Name)
+		fmt.Fprintf(&g.buf, "var %sDiscriminator = %s\n\n", a.Name, discriminatorLiteral(a.Discriminator))
+		g.decodeFunc(a.Name, a.Name+"Discriminator", a.Name+" account")
+	}
+	return g.take(), nil
+}
+
+func (g *generator) eventsFile(events []IDLEvent) (string, error) {
+	g.buf.WriteString(`// ErrUnknownEvent is returned by DecodeEvent for data without the
+// discriminator of a known event.
+var ErrUnknownEvent = errors.New("unknown event")
+
+`)
+	g.table("EventNames", "event", len(events), func(n int) ([8]byte, string) {
+		return events[n].Discriminator, events[n].Name
+	})
+	g.dispatch("DecodeEvent", "event", "ErrUnknownEvent", len(events), func(n int) string { return events[n].Name })
+
+	for _, e := range events {
+		if err := g.structType(g.types[e.Name], fmt.Sprintf("%s is an event of the program.", e.Name)); err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&g.buf, "// %sDiscriminator prefixes the data of %s events.\n", e.Name, e.Name)
+		fmt.Fprintf(&g.buf, "var %sDiscriminator = %s\n\n", e.Name, discriminatorLiteral(e.Discriminator))
+		g.decodeFunc(e.Name, e.Name+"Discriminator", e.Name+" event")
+	}
+	return g.take(), nil
+}
+
+// table writes a map from discriminators to IDL names.
+func (g *generator) table(name, what string, n int, entry func(int) ([8]byte, string)) {
+	fmt.Fprintf(&g.buf, "// %s maps %s discriminators to IDL names.\n", name, what)
+	fmt.Fprintf(&g.buf, "var %s = map[[8]byte]string{\n", name)
+	for i := 0; i < n; i++ {
+		d, idlName := entry(i)
+		fmt.Fprintf(&g.buf, "%s: %q,\n", discriminatorLiteral(d)[len("[8]byte"):], idlName)
+	}
+	g.buf.WriteString("}\n\n")
+}
+
+// dispatch writes a function decoding any of the types by discriminator.
+func (g *generator) dispatch(fn, what, errUnknown string, n int, name func(int) string) {
+	fmt.Fprintf(&g.buf, "// %s decodes the data of any %s of the program.\n", fn, what)
+	fmt.Fprintf(&g.buf, "func %s(data []byte) (interface{}, error) {\n", fn)
+	fmt.Fprintf(&g.buf, "if len(data) < 8 {\nreturn nil, fmt.Errorf(\"%%w: data too short for discriminator\", %s)\n}\n", errUnknown)
+	g.buf.WriteString("switch [8]byte(data[:8]) {\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&g.buf, "case %sDiscriminator:\nreturn Decode%s(data)\n", name(i), name(i))
+	}
+	fmt.Fprintf(&g.buf, "}\nreturn nil, fmt.Errorf(\"%%w: discriminator %%x\", %s, data[:8])\n}\n\n", errUnknown)
+}
+
+// decodeFunc writes Decode<name>, decoding the data following the
+// discriminator.
+func (g *generator) decodeFunc(name, discriminator, what string) {
+	fmt.Fprintf(&g.buf, "// Decode%s decodes the data of a %s.\n", name, what)
+	fmt.Fprintf(&g.buf, "func Decode%s(data []byte) (*%s, error) {\n", name, name)
+	fmt.Fprintf(&g.buf, "if len(data) < 8 || [8]byte(data[:8]) != %s {\n", discriminator)
+	fmt.Fprintf(&g.buf, "return nil, fmt.Errorf(\"not a %s\")\n}\n", what)
+	fmt.Fprintf(&g.buf, "v := &%s{}\n", name)
+	fmt.Fprintf(&g.buf, "if err := v.decode(bin.NewBorshDecoder(data[8:])); err != nil {\n")
+	fmt.Fprintf(&g.buf, "return nil, fmt.Errorf(\"decode %s: %%w\", err)\n}\nreturn v, nil\n}\n\n", name)
+}
+
+// useTop checks the account or event name is a struct and adds it and the
+// types it uses to used.
+func (g *generator) useTop(kind, name string, used map[string]bool) error {
+	def, ok := g.types[name]
+	if !ok {
+		return fmt.Errorf("%s %s: no type definition", kind, name)
+	}
+	if def.Type.Kind != "struct" {
+		return fmt.Errorf("%s %s: want a struct, got %s", kind, name, def.Type.Kind)
+	}
+	return g.use(def, used)
+}
+
+// use adds the defined types def refers to, directly or not, to used and
+// checks every type is supported.
+func (g *generator) use(def *IDLTypeDef, used map[string]bool) error {
+	if used[def.Name] {
+		return nil
+	}
+	used[def.Name] = true
+
+	switch def.Type.Kind {
+	case "enum":
+		for _, v := range def.Type.Variants {
+			if len(v.Fields) > 0 && string(v.Fields) != "null" && string(v.Fields) != "[]" {
+				return fmt.Errorf("enum %s: variant %s has fields, which is not supported", def.Name, v.Name)
+			}
+		}
+		return nil
+	case "struct":
+		for _, f := range def.Type.Fields {
+			if err := g.useType(&f.Type, used); err != nil {
+				return fmt.Errorf("%s.%s: %w", def.Name, f.Name, err)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("type %s: kind %q is not supported", def.Name, def.Type.Kind)
+	}
+}
+
+func (g *generator) useType(t *IDLType, used map[string]bool) error {
+	switch {
+	case t.Option != nil:
+		return g.useType(t.Option, used)
+	case t.Vec != nil:
+		return g.useType(t.Vec, used)
+	case t.Array != nil:
+		return g.useType(t.Array, used)
+	case t.Defined != "":
+		def, ok := g.types[t.Defined]
+		if !ok {
+			return fmt.Errorf("undefined type %s", t.Defined)
+		}
+		return g.use(def, used)
+	}
+	if _, ok := primitives[t.Primitive]; !ok {
+		return fmt.Errorf("type %q is not supported", t.Primitive)
+	}
+	return nil
+}
+
+func (g *generator) structType(def *IDLTypeDef, doc string) error {
+	writeDocs(&g.buf, append([]string{doc}, def.Docs...))
+	fmt.Fprintf(&g.buf, "type %s struct {\n", def.Name)
+	for _, f := range def.Type.Fields {
+		goType, err := goType(&f.Type)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", def.Name, f.Name, err)
+		}
+		writeDocs(&g.buf, f.Docs)
+		fmt.Fprintf(&g.buf, "%s %s `json:\"%s\"`\n", typeName(f.Name), goType, f.Name)
+	}
+	g.buf.WriteString("}\n\n")
+
+	fmt.Fprintf(&g.buf, "func (v *%s) decode(decoder *bin.Decoder) error {\n", def.Name)
+	for _, f := range def.Type.Fields {
+		if err := g.decode("v."+typeName(f.Name), &f.Type, f.Name, 0); err != nil {
+			return fmt.Errorf("%s.%s: %w", def.Name, f.Name, err)
+		}
+	}
+	g.buf.WriteString("return nil\n}\n\n")
+	return nil
+}
+
+func (g *generator) enum(def *IDLTypeDef) error {
+	writeDocs(&g.buf, append([]string{fmt.Sprintf("%s is an enum of the program.", def.Name)}, def.Docs...))
+	fmt.Fprintf(&g.buf, "type %s uint8\n\nconst (\n", def.Name)
+	names := make([]string, len(def.Type.Variants))
+	for n, v := range def.Type.Variants {
+		names[n] = fmt.Sprintf("%q", v.Name)
+		if n == 0 {
+			fmt.Fprintf(&g.buf, "%s%s %s = iota\n", def.Name, v.Name, def.Name)
+		} else {
+			fmt.Fprintf(&g.buf, "%s%s\n", def.Name, v.Name)
+		}
+	}
+	g.buf.WriteString(")\n\n")
+
+	namesVar := strings.ToLower(def.Name[:1]) + def.Name[1:] + "Names"
+	fmt.Fprintf(&g.buf, "var %s = [...]string{%s}\n\n", namesVar, strings.Join(names, ", "))
+	fmt.Fprintf(&g.buf, "func (v %s) String() string {\n", def.Name)
+	fmt.Fprintf(&g.buf, "if int(v) < len(%s) {\nreturn %s[v]\n}\n", namesVar, namesVar)
+	fmt.Fprintf(&g.buf, "return fmt.Sprintf(\"%s(%%d)\", uint8(v))\n}\n\n", def.Name)
+
+	fmt.Fprintf(&g.buf, "func (v *%s) decode(decoder *bin.Decoder) error {\n", def.Name)
+	g.buf.WriteString("b, err := decoder.ReadUint8()\nif err != nil {\nreturn err\n}\n")
+	fmt.Fprintf(&g.buf, "if int(b) >= len(%s) {\nreturn fmt.Errorf(\"invalid %s variant %%d\", b)\n}\n", namesVar, def.Name)
+	fmt.Fprintf(&g.buf, "*v = %s(b)\nreturn nil\n}\n\n", def.Name)
+	return nil
+}
+
+func goType(t *IDLType) (string, error) {
+	switch {
+	case t.Option != nil:
+		inner, err := goType(t.Option)
+		return "*" + inner, err
+	case t.Vec != nil:
+		inner, err := goType(t.Vec)
+		return "[]" + inner, err
+	case t.Array != nil:
+		inner, err := goType(t.Array)
+		return fmt.Sprintf("[%d]%s", t.Len, inner), err
+	case t.Defined != "":
+		return t.Defined, nil
+	}
+	goType, ok := primitives[t.Primitive]
+	if !ok {
+		return "", fmt.Errorf("type %q is not supported", t.Primitive)
+	}
+	return goType, nil
+}
+
+// decode writes the statements decoding a value of type t into dst.
+func (g *generator) decode(dst string, t *IDLType, field string, depth int) error {
+	fail := fmt.Sprintf("return fmt.Errorf(\"decode %s: %%w\", err)\n", field)
+	switch {
+	case t.Option != nil:
+		inner, err := goType(t.Option)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&g.buf, "{\nsome, err := decoder.ReadOption()\nif err != nil {\n%s}\n", fail)
+		fmt.Fprintf(&g.buf, "if some {\n%s = new(%s)\n", dst, inner)
+		if err := g.decode("(*"+dst+")", t.Option, field, depth+1); err != nil {
+			return err
+		}
+		g.buf.WriteString("}\n}\n")
+	case t.Vec != nil:
+		inner, err := goType(t.Vec)
+		if err != nil {
+			return err
+		}
+		i := loopVar(depth)
+		fmt.Fprintf(&g.buf, "{\nn, err := decodeLength(decoder)\nif err != nil {\n%s}\n", fail)
+		fmt.Fprintf(&g.buf, "%s = make([]%s, n)\nfor %s := range %s {\n", dst, inner, i, dst)
+		if err := g.decode(dst+"["+i+"]", t.Vec, field, depth+1); err != nil {
+			return err
+		}
+		g.buf.WriteString("}\n}\n")
+	case t.Array != nil:
+		i := loopVar(depth)
+		fmt.Fprintf(&g.buf, "for %s := range %s {\n", i, dst)
+		if err := g.decode(dst+"["+i+"]", t.Array, field, depth+1); err != nil {
+			return err
+		}
+		g.buf.WriteString("}\n")
+	case t.Defined != "":
+		fmt.Fprintf(&g.buf, "if err := %s.decode(decoder); err != nil {\n%s}\n", dst, fail)
+	default:
+		if _, ok := primitives[t.Primitive]; !ok {
+			return fmt.Errorf("type %q is not supported", t.Primitive)
+		}
+		fmt.Fprintf(&g.buf, "if err := decoder.Decode(%s); err != nil {\n%s}\n", addr(dst), fail)
+	}
+	return nil
+}
+
+// addr returns the address of dst, which is "(*p)" for the value of an
+// option.
+func addr(dst string) string {
+	if strings.HasPrefix(dst, "(*") && strings.HasSuffix(dst, ")") && strings.Count(dst, "(") == 1 {
+		return dst[2 : len(dst)-1]
+	}
+	return "&" + dst
+}
+
+func loopVar(depth int) string {
+	if depth < 3 {
+		return []string{"i", "j", "k"}[depth]
+	}
+	return fmt.Sprintf("i%d", depth)
+}
+
+func discriminatorLiteral(d [8]byte) string {
+	parts := make([]string, len(d))
+	for n, b := range d {
+		parts[n] = fmt.Sprintf("%d", b)
+	}
+	return "[8]byte{" + strings.Join(parts, ", ") + "}"
+}
+
+// typeName turns a snake_case IDL name into a Go name, e.g. proposal_id
+// into ProposalID.
+func typeName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+		if upper, ok := initialisms[part]; ok {
+			b.WriteString(upper)
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+func writeDocs(buf *bytes.Buffer, docs []string) {
+	for _, line := range docs {
+		fmt.Fprintf(buf, "// %s\n", line)
+	}
+}
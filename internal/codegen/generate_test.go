@@ -0,0 +1,121 @@
+package codegen
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// The generated starter program code must match the IDL.
+func TestGenerate_Starter(t *testing.T) {
+	f, err := os.Open("../../idl/starter_program.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	idl, err := ReadIDL(f)
+	if err != nil {
+		t.Fatalf("ReadIDL() error = %v", err)
+	}
+	files, err := Generate(idl, "starterprogram")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	for name, got := range files {
+		want, err := os.ReadFile(filepath.Join("../../pkg/generated/starterprogram", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("pkg/generated/starterprogram/%s is out of date; run indexer codegen", name)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name    string
+		idl     string
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "nested types",
+			idl: `{"accounts": [{"name": "Pool", "discriminator": [1, 2, 3, 4, 5, 6, 7, 8]}],
+				"instructions": [{"name": "set_state", "discriminator": [9, 9, 9, 9, 9, 9, 9, 9],
+					"accounts": [{"name": "pool", "writable": true}, {"name": "admin", "signer": true}],
+					"args": [{"name": "state", "type": {"defined": {"name": "State"}}}]}],
+				"events": [{"name": "PoolOpened", "discriminator": [7, 7, 7, 7, 7, 7, 7, 7]}],
+				"types": [
+					{"name": "PoolOpened", "type": {"kind": "struct", "fields": [{"name": "pool", "type": "pubkey"}]}},
+					{"name": "Pool", "type": {"kind": "struct", "fields": [
+						{"name": "owner_id", "type": "pubkey"},
+						{"name": "fees", "type": {"option": {"vec": "u16"}}},
+						{"name": "state", "type": {"defined": {"name": "State"}}},
+						{"name": "slots", "type": {"array": [{"defined": "State"}, 4]}}
+					]}},
+					{"name": "State", "type": {"kind": "enum", "variants": [{"name": "Open"}, {"name": "Closed"}]}}
+				]}`,
+			want: []string{
+				"OwnerID solana.PublicKey `json:\"owner_id\"`",
+				"Fees    *[]uint16",
+				"Slots   [4]State",
+				"var PoolDiscriminator = [8]byte{1, 2, 3, 4, 5, 6, 7, 8}",
+				"for j := range *v.Fees {",
+				"if err := decoder.Decode(&(*v.Fees)[j]); err != nil {",
+				"StateClosed",
+				"type SetStateArgs struct {",
+				`{Name: "admin", Writable: false, Signer: true},`,
+				"{9, 9, 9, 9, 9, 9, 9, 9}: \"set_state\",",
+				"case PoolOpenedDiscriminator:",
+			},
+		},
+		{
+			name:    "unsupported primitive",
+			idl:     `{"accounts": [{"name": "A"}], "types": [{"name": "A", "type": {"kind": "struct", "fields": [{"name": "x", "type": "u128"}]}}]}`,
+			wantErr: `A.x: type "u128" is not supported`,
+		},
+		{
+			name:    "enum with fields",
+			idl:     `{"accounts": [{"name": "A"}], "types": [{"name": "A", "type": {"kind": "struct", "fields": [{"name": "e", "type": {"defined": "E"}}]}}, {"name": "E", "type": {"kind": "enum", "variants": [{"name": "V", "fields": ["u8"]}]}}]}`,
+			wantErr: "enum E: variant V has fields",
+		},
+		{
+			name:    "account group",
+			idl:     `{"instructions": [{"name": "swap", "accounts": [{"name": "pools", "accounts": [{"name": "a"}]}]}]}`,
+			wantErr: "instruction swap: account group pools is not supported",
+		},
+		{
+			name:    "no type definition",
+			idl:     `{"accounts": [{"name": "A"}]}`,
+			wantErr: "account A: no type definition",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idl, err := ReadIDL(strings.NewReader(tt.idl))
+			if err != nil {
+				t.Fatalf("ReadIDL() error = %v", err)
+			}
+			files, err := Generate(idl, "pool")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Generate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			src := bytes.Join([][]byte{files["types.go"], files["instructions.go"], files["accounts.go"], files["events.go"]}, nil)
+			for _, want := range tt.want {
+				if !bytes.Contains(src, []byte(want)) {
+					t.Errorf("generated code misses %q:\n%s", want, src)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+// Package codegen generates Go code from an Anchor IDL: the types of a
+// program's instructions, accounts and events with their Borsh decoders and
+// discriminator tables.
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// IDL is the part of an Anchor IDL (0.30 and later) the generators read.
+type IDL struct {
+	Address  string `json:"address"`
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Instructions []IDLInstruction `json:"instructions"`
+	Accounts     []IDLAccount     `json:"accounts"`
+	Events       []IDLEvent       `json:"events"`
+	Types        []IDLTypeDef     `json:"types"`
+}
+
+type IDLInstruction struct {
+	Name          string                  `json:"name"`
+	Docs          []string                `json:"docs"`
+	Discriminator [8]byte                 `json:"discriminator"`
+	Accounts      []IDLInstructionAccount `json:"accounts"`
+	Args          []IDLField              `json:"args"`
+}
+
+// IDLInstructionAccount is an account an instruction takes. Nested account
+// groups are not supported.
+type IDLInstructionAccount struct {
+	Name     string          `json:"name"`
+	Writable bool            `json:"writable"`
+	Signer   bool            `json:"signer"`
+	Accounts json.RawMessage `json:"accounts"`
+}
+
+type IDLAccount struct {
+	Name          string  `json:"name"`
+	Discriminator [8]byte `json:"discriminator"`
+}
+
+type IDLEvent struct {
+	Name          string  `json:"name"`
+	Discriminator [8]byte `json:"discriminator"`
+}
+
+// IDLTypeDef is a struct or enum of the IDL's types section.
+type IDLTypeDef struct {
+	Name string   `json:"name"`
+	Docs []string `json:"docs"`
+	Type struct {
+		Kind     string       `json:"kind"`
+		Fields   []IDLField   `json:"fields"`
+		Variants []IDLVariant `json:"variants"`
+	} `json:"type"`
+}
+
+type IDLField struct {
+	Name string   `json:"name"`
+	Docs []string `json:"docs"`
+	Type IDLType  `json:"type"`
+}
+
+type IDLVariant struct {
+	Name   string          `json:"name"`
+	Fields json.RawMessage `json:"fields"`
+}
+
+// IDLType is a field type: a primitive such as "u64" or "pubkey", or an
+// option, vec, array or defined type.
+type IDLType struct {
+	Primitive string
+	Option    *IDLType
+	Vec       *IDLType
+	Array     *IDLType
+	Len       int
+	Defined   string
+}
+
+func (t *IDLType) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &t.Primitive); err == nil {
+		return nil
+	}
+
+	var v struct {
+		Option  *IDLType          `json:"option"`
+		Vec     *IDLType          `json:"vec"`
+		Array   []json.RawMessage `json:"array"`
+		Defined json.RawMessage   `json:"defined"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("type %s: %w", data, err)
+	}
+	switch {
+	case v.Option != nil:
+		t.Option = v.Option
+	case v.Vec != nil:
+		t.Vec = v.Vec
+	case v.Array != nil:
+		if len(v.Array) != 2 {
+			return fmt.Errorf("array type %s: want [type, length]", data)
+		}
+		t.Array = new(IDLType)
+		if err := json.Unmarshal(v.Array[0], t.Array); err != nil {
+			return err
+		}
+		if err := json.Unmarshal(v.Array[1], &t.Len); err != nil {
+			return fmt.Errorf("array type %s: length must be a number", data)
+		}
+	case v.Defined != nil:
+		// Older IDLs name the type directly, newer ones in an object.
+		if err := json.Unmarshal(v.Defined, &t.Defined); err != nil {
+			var named struct {
+				Name string `json:"name"`
+			}
+			if err := json.Unmarshal(v.Defined, &named); err != nil {
+				return fmt.Errorf("defined type %s: %w", data, err)
+			}
+			t.Defined = named.Name
+		}
+	default:
+		return fmt.Errorf("unknown type %s", data)
+	}
+	return nil
+}
+
+// ReadIDL reads an Anchor IDL in JSON.
+func ReadIDL(r io.Reader) (*IDL, error) {
+	var idl IDL
+	if err := json.NewDecoder(r).Decode(&idl); err != nil {
+		return nil, fmt.Errorf("decode IDL: %w", err)
+	}
+	return &idl, nil
+}
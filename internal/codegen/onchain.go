@@ -0,0 +1,130 @@
+package codegen
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/gagliardetto/solana-go"
+	solanaClient "github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// idlAccountHeaderSize is the size of the discriminator, authority and data
+// length preceding the compressed IDL in an Anchor IDL account.
+const idlAccountHeaderSize = 8 + 32 + 4
+
+// IDLAddress returns the account "anchor idl init" uploads the IDL of
+// program to.
+func IDLAddress(program solana.PublicKey) (solana.PublicKey, error) {
+	base, _, err := solana.FindProgramAddress([][]byte{}, program)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("find IDL base address: %w", err)
+	}
+	return solana.CreateWithSeed(base, "anchor:idl", program)
+}
+
+// FetchIDL returns the IDL uploaded for program, in JSON. Its error wraps
+// solanaClient.ErrAccountNotFound when none was.
+func FetchIDL(ctx context.Context, client *solanaClient.Client, program solana.PublicKey) ([]byte, error) {
+	address, err := IDLAddress(program)
+	if err != nil {
+		return nil, err
+	}
+	data, _, err := client.GetAccountData(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("IDL account of %s: %w", program, err)
+	}
+	idl, err := IDLAccountJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("IDL account %s: %w", address, err)
+	}
+	return idl, nil
+}
+
+// IDLAccountJSON decompresses the IDL of an Anchor IDL account.
+func IDLAccountJSON(data []byte) ([]byte, error) {
+	if len(data) < idlAccountHeaderSize {
+		return nil, fmt.Errorf("IDL account is %d bytes, too short for its header", len(data))
+	}
+	size := binary.LittleEndian.Uint32(data[idlAccountHeaderSize-4:])
+	compressed := data[idlAccountHeaderSize:]
+	if uint64(size) > uint64(len(compressed)) {
+		return nil, fmt.Errorf("IDL account holds %d bytes of IDL, want %d", len(compressed), size)
+	}
+	r, err := zlib.NewReader(bytes.NewReader(compressed[:size]))
+	if err != nil {
+		return nil, fmt.Errorf("decompress IDL: %w", err)
+	}
+	defer r.Close()
+	idl, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("decompress IDL: %w", err)
+	}
+	return idl, nil
+}
+
+// DecodeIDLAccount reads the IDL of an Anchor IDL account.
+func DecodeIDLAccount(data []byte) (*IDL, error) {
+	idl, err := IDLAccountJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return ReadIDL(bytes.NewReader(idl))
+}
+
+// DiffIDL lists how deployed differs from local in what the indexer relies
+// on: the program address and the discriminators of instructions, accounts
+// and events. It returns none when they match.
+func DiffIDL(local, deployed *IDL) []string {
+	var diffs []string
+	if local.Address != deployed.Address {
+		diffs = append(diffs, fmt.Sprintf("address is %s, deployed %s", local.Address, deployed.Address))
+	}
+	diff := func(kind string, local, deployed map[string][8]byte) {
+		for name, d := range local {
+			if other, ok := deployed[name]; !ok {
+				diffs = append(diffs, fmt.Sprintf("%s %s is not deployed", kind, name))
+			} else if other != d {
+				diffs = append(diffs, fmt.Sprintf("%s %s has discriminator %v, deployed %v", kind, name, d, other))
+			}
+		}
+		for name := range deployed {
+			if _, ok := local[name]; !ok {
+				diffs = append(diffs, fmt.Sprintf("%s %s is deployed but not in the IDL", kind, name))
+			}
+		}
+	}
+	diff("instruction", instructionDiscriminators(local), instructionDiscriminators(deployed))
+	diff("account", accountDiscriminators(local), accountDiscriminators(deployed))
+	diff("event", eventDiscriminators(local), eventDiscriminators(deployed))
+	sort.Strings(diffs)
+	return diffs
+}
+
+func instructionDiscriminators(idl *IDL) map[string][8]byte {
+	m := make(map[string][8]byte, len(idl.Instructions))
+	for _, ix := range idl.Instructions {
+		m[ix.Name] = ix.Discriminator
+	}
+	return m
+}
+
+func accountDiscriminators(idl *IDL) map[string][8]byte {
+	m := make(map[string][8]byte, len(idl.Accounts))
+	for _, a := range idl.Accounts {
+		m[a.Name] = a.Discriminator
+	}
+	return m
+}
+
+func eventDiscriminators(idl *IDL) map[string][8]byte {
+	m := make(map[string][8]byte, len(idl.Events))
+	for _, e := range idl.Events {
+		m[e.Name] = e.Discriminator
+	}
+	return m
+}
@@ -0,0 +1,79 @@
+package codegen
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func readStarterIDL(t *testing.T) *IDL {
+	t.Helper()
+	f, err := os.Open("../../idl/starter_program.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	idl, err := ReadIDL(f)
+	if err != nil {
+		t.Fatalf("ReadIDL() error = %v", err)
+	}
+	return idl
+}
+
+func TestDecodeIDLAccount(t *testing.T) {
+	raw, err := os.ReadFile("../../idl/starter_program.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	zw.Write(raw)
+	zw.Close()
+
+	data := make([]byte, idlAccountHeaderSize-4)
+	data = binary.LittleEndian.AppendUint32(data, uint32(compressed.Len()))
+	data = append(data, compressed.Bytes()...)
+	// Accounts are allocated larger than the IDL for later upgrades.
+	data = append(data, make([]byte, 128)...)
+
+	got, err := DecodeIDLAccount(data)
+	if err != nil {
+		t.Fatalf("DecodeIDLAccount() error = %v", err)
+	}
+	if want := readStarterIDL(t); !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeIDLAccount() = %+v, want the starter IDL", got.Metadata)
+	}
+
+	if _, err := DecodeIDLAccount(data[:idlAccountHeaderSize+10]); err == nil {
+		t.Error("DecodeIDLAccount() of a truncated account error = nil")
+	}
+}
+
+func TestDiffIDL(t *testing.T) {
+	local := readStarterIDL(t)
+	if diffs := DiffIDL(local, local); len(diffs) != 0 {
+		t.Errorf("DiffIDL() of the same IDL = %v, want none", diffs)
+	}
+
+	deployed := *local
+	deployed.Events = append([]IDLEvent{}, local.Events[1:]...)
+	deployed.Events[0].Discriminator[0]++
+	deployed.Instructions = append(append([]IDLInstruction{}, local.Instructions...), IDLInstruction{Name: "migrate"})
+	want := []string{
+		"event " + local.Events[0].Name + " is not deployed",
+		"event " + local.Events[1].Name + " has discriminator",
+		"instruction migrate is deployed but not in the IDL",
+	}
+	diffs := DiffIDL(local, &deployed)
+	if len(diffs) != len(want) {
+		t.Fatalf("DiffIDL() = %q, want %d differences", diffs, len(want))
+	}
+	for n, prefix := range want {
+		if !bytes.HasPrefix([]byte(diffs[n]), []byte(prefix)) {
+			t.Errorf("DiffIDL()[%d] = %q, want it to start with %q", n, diffs[n], prefix)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ExecuteTemplate runs a text/template on the IDL, so artifacts such as a
+// GraphQL schema or TypeScript types can be generated next to the Go code.
+// The template's data is the IDL; besides the text/template builtins it
+// can call:
+//
+//	camel       snake_case name to CamelCase, e.g. proposal_id to ProposalID
+//	lowerCamel  snake_case name to lowerCamelCase, e.g. proposal_id to proposalID
+//	goType      the Go type of an IDLType, as in the generated code
+//	idlType     an IDLType in IDL notation, e.g. option<vec<u8>>
+//	typeDef     the IDLTypeDef with a name, e.g. the fields of an event
+//	join        strings.Join
+func ExecuteTemplate(idl *IDL, name, text string) ([]byte, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(templateFuncs(idl)).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, idl); err != nil {
+		return nil, fmt.Errorf("execute template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func templateFuncs(idl *IDL) template.FuncMap {
+	return template.FuncMap{
+		"camel":      typeName,
+		"lowerCamel": lowerCamel,
+		"goType":     goType,
+		"idlType":    idlType,
+		"typeDef": func(name string) (*IDLTypeDef, error) {
+			for n := range idl.Types {
+				if idl.Types[n].Name == name {
+					return &idl.Types[n], nil
+				}
+			}
+			return nil, fmt.Errorf("type %s has no definition", name)
+		},
+		"join": strings.Join,
+	}
+}
+
+func lowerCamel(name string) string {
+	camel := typeName(name)
+	if camel == "" {
+		return ""
+	}
+	// A leading initialism is lowered as a whole: id_hash to idHash.
+	first, _, _ := strings.Cut(strings.TrimLeft(name, "_"), "_")
+	if upper, ok := initialisms[first]; ok {
+		return first + camel[len(upper):]
+	}
+	r, size := utf8.DecodeRuneInString(camel)
+	return string(unicode.ToLower(r)) + camel[size:]
+}
+
+func idlType(t *IDLType) string {
+	switch {
+	case t.Option != nil:
+		return "option<" + idlType(t.Option) + ">"
+	case t.Vec != nil:
+		return "vec<" + idlType(t.Vec) + ">"
+	case t.Array != nil:
+		return fmt.Sprintf("[%s; %d]", idlType(t.Array), t.Len)
+	case t.Defined != "":
+		return t.Defined
+	}
+	return t.Primitive
+}
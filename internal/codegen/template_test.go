@@ -0,0 +1,88 @@
+package codegen
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestExecuteTemplate(t *testing.T) {
+	idl, err := ReadIDL(strings.NewReader(`{"events": [{"name": "PoolOpened", "discriminator": [7, 7, 7, 7, 7, 7, 7, 7]}],
+		"types": [{"name": "PoolOpened", "type": {"kind": "struct", "fields": [
+			{"name": "pool_id", "type": "pubkey"},
+			{"name": "fees", "type": {"option": {"vec": "u16"}}},
+			{"name": "slots", "type": {"array": [{"defined": "State"}, 4]}}
+		]}}]}`))
+	if err != nil {
+		t.Fatalf("ReadIDL() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "fields",
+			text: `{{range .Events}}{{with typeDef .Name}}{{range .Type.Fields}}{{camel .Name}} {{lowerCamel .Name}} {{idlType .Type}} {{goType .Type}}
+{{end}}{{end}}{{end}}`,
+			want: "PoolID poolID pubkey solana.PublicKey\nFees fees option<vec<u16>> *[]uint16\nSlots slots [State; 4] [4]State\n",
+		},
+		{name: "parse error", text: `{{range .Events}}`, wantErr: "parse template"},
+		{name: "missing type", text: `{{typeDef "Pool"}}`, wantErr: "type Pool has no definition"},
+		{name: "missing key", text: `{{.Program}}`, wantErr: "execute template"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExecuteTemplate(idl, tt.name, tt.text)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ExecuteTemplate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExecuteTemplate() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("ExecuteTemplate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteTemplate_Example(t *testing.T) {
+	f, err := os.Open("../../idl/starter_program.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	idl, err := ReadIDL(f)
+	if err != nil {
+		t.Fatalf("ReadIDL() error = %v", err)
+	}
+	text, err := os.ReadFile("../../idl/templates/events.graphql.tmpl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ExecuteTemplate(idl, "events.graphql.tmpl", string(text))
+	if err != nil {
+		t.Fatalf("ExecuteTemplate() error = %v", err)
+	}
+	for _, want := range []string{"type VoteCastEvent {\n  proposalID: BigInt!\n", "enum RoleType {"} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("ExecuteTemplate() = %s, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestLowerCamel(t *testing.T) {
+	tests := map[string]string{"pool_id": "poolID", "id_hash": "idHash", "uri": "uri", "owner": "owner", "": ""}
+	for name, want := range tests {
+		if got := lowerCamel(name); got != want {
+			t.Errorf("lowerCamel(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
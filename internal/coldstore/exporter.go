@@ -0,0 +1,151 @@
+package coldstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+type Options struct {
+	// After is the age at which events move to cold storage.
+	After    time.Duration
+	Interval time.Duration
+	// BatchSize bounds how many events are read from the hot store at once.
+	BatchSize int
+	// Prefix is prepended to every object key.
+	Prefix string
+}
+
+// Exporter periodically moves old events from the hot store into Parquet
+// files, laid out as <prefix>/dt=YYYY-MM-DD/event_type=<type>/<file> so that
+// Athena and BigQuery can treat dt and event_type as partition columns.
+type Exporter struct {
+	source repository.ColdSource
+	store  ObjectStore
+	opts   Options
+	now    func() time.Time
+}
+
+func NewExporter(source repository.ColdSource, store ObjectStore, opts Options) *Exporter {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 5000
+	}
+	return &Exporter{
+		source: source,
+		store:  store,
+		opts:   opts,
+		now:    time.Now,
+	}
+}
+
+func (e *Exporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		exported, err := e.ExportOnce(ctx)
+		if err != nil {
+			log.Printf("error exporting events to cold storage: %v", err)
+		} else if exported > 0 {
+			log.Printf("moved %d events to cold storage", exported)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ExportOnce moves every event older than Options.After to cold storage.
+func (e *Exporter) ExportOnce(ctx context.Context) (int64, error) {
+	return e.source.ExportEvents(ctx, e.now().Add(-e.opts.After), e.opts.BatchSize, e.upload)
+}
+
+type partition struct {
+	date      string
+	eventType models.EventType
+}
+
+func (e *Exporter) upload(ctx context.Context, events []repository.ExportedEvent) error {
+	partitions := make(map[partition][]repository.ExportedEvent)
+	for _, event := range events {
+		p := partition{date: event.BlockTime.UTC().Format("2006-01-02"), eventType: event.EventType}
+		partitions[p] = append(partitions[p], event)
+	}
+
+	keys := make([]partition, 0, len(partitions))
+	for p := range partitions {
+		keys = append(keys, p)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].date != keys[j].date {
+			return keys[i].date < keys[j].date
+		}
+		return keys[i].eventType < keys[j].eventType
+	})
+
+	for _, p := range keys {
+		var file bytes.Buffer
+		if err := encodeEvents(&file, partitions[p]); err != nil {
+			return fmt.Errorf("encode parquet: %w", err)
+		}
+		if err := e.store.Put(ctx, e.objectKey(p, partitions[p]), file.Bytes()); err != nil {
+			return fmt.Errorf("upload parquet: %w", err)
+		}
+	}
+	return nil
+}
+
+// objectKey names a file after the slots and signatures it holds, so that a
+// batch uploaded again after a failed delete overwrites its earlier copy.
+func (e *Exporter) objectKey(p partition, events []repository.ExportedEvent) string {
+	hash := sha256.New()
+	minSlot, maxSlot := events[0].Slot, events[0].Slot
+	for _, event := range events {
+		hash.Write([]byte(event.Signature))
+		hash.Write(event.Document)
+		minSlot = min(minSlot, event.Slot)
+		maxSlot = max(maxSlot, event.Slot)
+	}
+	name := fmt.Sprintf("%d-%d-%s.parquet", minSlot, maxSlot, hex.EncodeToString(hash.Sum(nil))[:16])
+	return path.Join(e.opts.Prefix, "dt="+p.date, "event_type="+string(p.eventType), name)
+}
+
+// encodeEvents writes events as a Parquet file. The event type and date are
+// left out because they are encoded in the object key.
+func encodeEvents(w *bytes.Buffer, events []repository.ExportedEvent) error {
+	signatures := make([]string, len(events))
+	programIDs := make([]string, len(events))
+	slots := make([]int64, len(events))
+	blockTimes := make([]int64, len(events))
+	versions := make([]string, len(events))
+	documents := make([]string, len(events))
+	for i, event := range events {
+		signatures[i] = event.Signature
+		programIDs[i] = event.ProgramID.String()
+		slots[i] = int64(event.Slot)
+		blockTimes[i] = event.BlockTime.UnixMilli()
+		versions[i] = event.IndexerVersion
+		documents[i] = string(event.Document)
+	}
+
+	return writeParquet(w, []parquetColumn{
+		{name: "signature", strings: signatures},
+		{name: "program_id", strings: programIDs},
+		{name: "slot", int64s: slots},
+		{name: "block_time", timestamp: true, int64s: blockTimes},
+		{name: "indexer_version", strings: versions},
+		{name: "document", strings: documents},
+	}, len(events))
+}
@@ -0,0 +1,145 @@
+package coldstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+type fakeSource struct {
+	events  []repository.ExportedEvent
+	before  time.Time
+	deleted int
+}
+
+func (s *fakeSource) ExportEvents(ctx context.Context, before time.Time, batchSize int, export func(ctx context.Context, events []repository.ExportedEvent) error) (int64, error) {
+	s.before = before
+	if err := export(ctx, s.events); err != nil {
+		return 0, err
+	}
+	s.deleted = len(s.events)
+	return int64(len(s.events)), nil
+}
+
+type fakeStore struct {
+	objects map[string][]byte
+	err     error
+}
+
+func (s *fakeStore) Put(ctx context.Context, key string, body []byte) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.objects[key] = body
+	return nil
+}
+
+func exportedEvent(eventType models.EventType, slot uint64, blockTime time.Time) repository.ExportedEvent {
+	return repository.ExportedEvent{
+		BaseEvent: models.BaseEvent{EventType: eventType, Signature: "sig", Slot: slot, BlockTime: blockTime},
+		Document:  []byte(`{"slot":1}`),
+	}
+}
+
+func TestExporter_ExportOnce(t *testing.T) {
+	now := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	day1 := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	day2 := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	source := &fakeSource{events: []repository.ExportedEvent{
+		exportedEvent(models.EventTypeCounterIncremented, 10, day1),
+		exportedEvent(models.EventTypeCounterIncremented, 12, day1),
+		exportedEvent(models.EventTypeCounterReset, 11, day1),
+		exportedEvent(models.EventTypeCounterIncremented, 20, day2),
+	}}
+	store := &fakeStore{objects: make(map[string][]byte)}
+	exporter := NewExporter(source, store, Options{After: 30 * 24 * time.Hour, Prefix: "events"})
+	exporter.now = func() time.Time { return now }
+
+	exported, err := exporter.ExportOnce(context.Background())
+	if err != nil {
+		t.Fatalf("ExportOnce() error = %v", err)
+	}
+	if exported != 4 {
+		t.Errorf("ExportOnce() = %d, want 4", exported)
+	}
+	if want := now.Add(-30 * 24 * time.Hour); !source.before.Equal(want) {
+		t.Errorf("cutoff = %v, want %v", source.before, want)
+	}
+
+	var keys []string
+	for key, body := range store.objects {
+		keys = append(keys, key[:strings.LastIndex(key, "-")])
+		if !strings.HasPrefix(string(body), parquetMagic) {
+			t.Errorf("%s is not a parquet file", key)
+		}
+	}
+	sort.Strings(keys)
+	want := []string{
+		"events/dt=2026-01-01/event_type=CounterIncrementedEvent/10-12",
+		"events/dt=2026-01-01/event_type=CounterResetEvent/11-11",
+		"events/dt=2026-01-02/event_type=CounterIncrementedEvent/20-20",
+	}
+	if strings.Join(keys, ",") != strings.Join(want, ",") {
+		t.Errorf("keys = %v, want %v", keys, want)
+	}
+}
+
+func TestExporter_UploadFailureKeepsEvents(t *testing.T) {
+	source := &fakeSource{events: []repository.ExportedEvent{
+		exportedEvent(models.EventTypeCounterReset, 1, time.Now()),
+	}}
+	store := &fakeStore{err: errors.New("access denied")}
+
+	if _, err := NewExporter(source, store, Options{}).ExportOnce(context.Background()); err == nil {
+		t.Fatal("ExportOnce() error = nil, want upload error")
+	}
+	if source.deleted != 0 {
+		t.Errorf("deleted %d events after a failed upload", source.deleted)
+	}
+}
+
+func TestS3Store_Put(t *testing.T) {
+	var gotPath, gotAuth, gotHash string
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		gotAuth = r.Header.Get("Authorization")
+		gotHash = r.Header.Get("X-Amz-Content-Sha256")
+		gotBody, _ = io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	store, err := NewS3Store(S3Options{
+		Bucket:      "archive",
+		Region:      "us-east-1",
+		Endpoint:    srv.URL,
+		Credentials: aws.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"},
+	})
+	if err != nil {
+		t.Fatalf("NewS3Store() error = %v", err)
+	}
+
+	if err := store.Put(context.Background(), "events/dt=2026-01-01/a b.parquet", []byte("PAR1")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if want := "/archive/events/dt%3D2026-01-01/a%20b.parquet"; gotPath != want {
+		t.Errorf("path = %s, want %s", gotPath, want)
+	}
+	if !strings.HasPrefix(gotAuth, "AWS4-HMAC-SHA256 Credential=AKID/") || !strings.Contains(gotAuth, "x-amz-content-sha256") {
+		t.Errorf("Authorization = %q", gotAuth)
+	}
+	if gotHash == "" || string(gotBody) != "PAR1" {
+		t.Errorf("hash = %q, body = %q", gotHash, gotBody)
+	}
+}
@@ -0,0 +1,216 @@
+package coldstore
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Parquet format constants, see parquet.thrift.
+const (
+	parquetMagic = "PAR1"
+
+	parquetTypeInt64     = 2
+	parquetTypeByteArray = 6
+
+	parquetRequired = 0
+
+	parquetConvertedUTF8            = 0
+	parquetConvertedTimestampMillis = 9
+
+	parquetEncodingPlain = 0
+	parquetEncodingRLE   = 3
+
+	parquetCodecGzip = 2
+
+	parquetDataPage = 0
+)
+
+// parquetColumn is a required column holding either int64s or strings.
+type parquetColumn struct {
+	name string
+	// timestamp marks an int64 column as milliseconds since the Unix epoch.
+	timestamp bool
+	int64s    []int64
+	strings   []string
+}
+
+func (c parquetColumn) physicalType() int32 {
+	if c.strings != nil {
+		return parquetTypeByteArray
+	}
+	return parquetTypeInt64
+}
+
+func (c parquetColumn) convertedType() (int32, bool) {
+	switch {
+	case c.strings != nil:
+		return parquetConvertedUTF8, true
+	case c.timestamp:
+		return parquetConvertedTimestampMillis, true
+	}
+	return 0, false
+}
+
+// plain encodes the column values with the PLAIN encoding. Required,
+// non-nested columns carry no repetition or definition levels.
+func (c parquetColumn) plain() []byte {
+	var buf bytes.Buffer
+	if c.strings != nil {
+		for _, s := range c.strings {
+			_ = binary.Write(&buf, binary.LittleEndian, uint32(len(s)))
+			buf.WriteString(s)
+		}
+		return buf.Bytes()
+	}
+	for _, v := range c.int64s {
+		_ = binary.Write(&buf, binary.LittleEndian, v)
+	}
+	return buf.Bytes()
+}
+
+type columnChunk struct {
+	column           parquetColumn
+	offset           int64
+	uncompressedSize int64
+	compressedSize   int64
+}
+
+// writeParquet writes a Parquet file with a single row group and one gzip
+// compressed data page per column. All columns must hold numRows values.
+// It is deliberately minimal: enough for Athena and BigQuery to read the
+// exported events without pulling in a Parquet library.
+func writeParquet(w io.Writer, columns []parquetColumn, numRows int) error {
+	out := &countingWriter{w: w}
+	if _, err := io.WriteString(out, parquetMagic); err != nil {
+		return err
+	}
+
+	chunks := make([]columnChunk, 0, len(columns))
+	for _, column := range columns {
+		if n := len(column.int64s) + len(column.strings); n != numRows {
+			return fmt.Errorf("column %s has %d values, want %d", column.name, n, numRows)
+		}
+
+		data := column.plain()
+		var compressed bytes.Buffer
+		zw := gzip.NewWriter(&compressed)
+		if _, err := zw.Write(data); err != nil {
+			return fmt.Errorf("compress column %s: %w", column.name, err)
+		}
+		if err := zw.Close(); err != nil {
+			return fmt.Errorf("compress column %s: %w", column.name, err)
+		}
+
+		header := pageHeader(numRows, len(data), compressed.Len())
+		chunk := columnChunk{
+			column:           column,
+			offset:           out.n,
+			uncompressedSize: int64(len(header) + len(data)),
+			compressedSize:   int64(len(header) + compressed.Len()),
+		}
+		if _, err := out.Write(header); err != nil {
+			return err
+		}
+		if _, err := out.Write(compressed.Bytes()); err != nil {
+			return err
+		}
+		chunks = append(chunks, chunk)
+	}
+
+	footer := fileMetaData(chunks, numRows)
+	if _, err := out.Write(footer); err != nil {
+		return err
+	}
+	if err := binary.Write(out, binary.LittleEndian, uint32(len(footer))); err != nil {
+		return err
+	}
+	_, err := io.WriteString(out, parquetMagic)
+	return err
+}
+
+func pageHeader(numValues, uncompressedSize, compressedSize int) []byte {
+	var w compactWriter
+	w.beginStruct()
+	w.i32Field(1, parquetDataPage)
+	w.i32Field(2, int32(uncompressedSize))
+	w.i32Field(3, int32(compressedSize))
+	w.structField(5) // DataPageHeader
+	w.i32Field(1, int32(numValues))
+	w.i32Field(2, parquetEncodingPlain)
+	w.i32Field(3, parquetEncodingRLE)
+	w.i32Field(4, parquetEncodingRLE)
+	w.endStruct()
+	w.endStruct()
+	return w.buf.Bytes()
+}
+
+func fileMetaData(chunks []columnChunk, numRows int) []byte {
+	var w compactWriter
+	w.beginStruct()
+	w.i32Field(1, 1) // version
+
+	w.listField(2, compactStruct, len(chunks)+1)
+	w.beginStruct()
+	w.stringField(4, "schema")
+	w.i32Field(5, int32(len(chunks)))
+	w.endStruct()
+	for _, chunk := range chunks {
+		w.beginStruct()
+		w.i32Field(1, chunk.column.physicalType())
+		w.i32Field(3, parquetRequired)
+		w.stringField(4, chunk.column.name)
+		if converted, ok := chunk.column.convertedType(); ok {
+			w.i32Field(6, converted)
+		}
+		w.endStruct()
+	}
+
+	w.i64Field(3, int64(numRows))
+
+	var totalSize int64
+	for _, chunk := range chunks {
+		totalSize += chunk.uncompressedSize
+	}
+	w.listField(4, compactStruct, 1)
+	w.beginStruct()
+	w.listField(1, compactStruct, len(chunks))
+	for _, chunk := range chunks {
+		w.beginStruct()
+		w.i64Field(2, chunk.offset)
+		w.structField(3) // ColumnMetaData
+		w.i32Field(1, chunk.column.physicalType())
+		w.listField(2, compactI32, 2)
+		w.i32(parquetEncodingPlain)
+		w.i32(parquetEncodingRLE)
+		w.listField(3, compactBinary, 1)
+		w.string(chunk.column.name)
+		w.i32Field(4, parquetCodecGzip)
+		w.i64Field(5, int64(numRows))
+		w.i64Field(6, chunk.uncompressedSize)
+		w.i64Field(7, chunk.compressedSize)
+		w.i64Field(9, chunk.offset)
+		w.endStruct()
+		w.endStruct()
+	}
+	w.i64Field(2, totalSize)
+	w.i64Field(3, int64(numRows))
+	w.endStruct()
+
+	w.stringField(6, "go-indexer-solana-starter")
+	w.endStruct()
+	return w.buf.Bytes()
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
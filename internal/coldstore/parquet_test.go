@@ -0,0 +1,174 @@
+package coldstore
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"testing"
+)
+
+// thriftReader decodes compact protocol structs into maps keyed by field id,
+// enough to check the metadata written by writeParquet.
+type thriftReader struct {
+	b   []byte
+	pos int
+}
+
+func (r *thriftReader) byte() (byte, error) {
+	if r.pos >= len(r.b) {
+		return 0, io.ErrUnexpectedEOF
+	}
+	r.pos++
+	return r.b[r.pos-1], nil
+}
+
+func (r *thriftReader) uvarint() (uint64, error) {
+	v, n := binary.Uvarint(r.b[r.pos:])
+	if n <= 0 {
+		return 0, fmt.Errorf("bad varint at %d", r.pos)
+	}
+	r.pos += n
+	return v, nil
+}
+
+func (r *thriftReader) zigzag() (int64, error) {
+	v, err := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1), err
+}
+
+func (r *thriftReader) readStruct() (map[int16]interface{}, error) {
+	fields := make(map[int16]interface{})
+	var last int16
+	for {
+		header, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		if header == 0 {
+			return fields, nil
+		}
+		id := last + int16(header>>4)
+		if header>>4 == 0 {
+			long, err := r.zigzag()
+			if err != nil {
+				return nil, err
+			}
+			id = int16(long)
+		}
+		last = id
+		if fields[id], err = r.readValue(header & 0x0f); err != nil {
+			return nil, fmt.Errorf("field %d: %w", id, err)
+		}
+	}
+}
+
+func (r *thriftReader) readValue(valueType byte) (interface{}, error) {
+	switch valueType {
+	case compactI32, compactI64:
+		return r.zigzag()
+	case compactBinary:
+		n, err := r.uvarint()
+		if err != nil {
+			return nil, err
+		}
+		if uint64(len(r.b)-r.pos) < n {
+			return nil, io.ErrUnexpectedEOF
+		}
+		s := string(r.b[r.pos : r.pos+int(n)])
+		r.pos += int(n)
+		return s, nil
+	case compactList:
+		header, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		size := uint64(header >> 4)
+		if size == 15 {
+			if size, err = r.uvarint(); err != nil {
+				return nil, err
+			}
+		}
+		list := make([]interface{}, size)
+		for i := range list {
+			if list[i], err = r.readValue(header & 0x0f); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	case compactStruct:
+		return r.readStruct()
+	}
+	return nil, fmt.Errorf("unsupported thrift type %d", valueType)
+}
+
+func TestWriteParquet(t *testing.T) {
+	columns := []parquetColumn{
+		{name: "signature", strings: []string{"sig-a", "sig-b", "sig-c"}},
+		{name: "block_time", timestamp: true, int64s: []int64{1, 2, 3}},
+	}
+
+	var buf bytes.Buffer
+	if err := writeParquet(&buf, columns, 3); err != nil {
+		t.Fatalf("writeParquet() error = %v", err)
+	}
+	file := buf.Bytes()
+
+	if string(file[:4]) != parquetMagic || string(file[len(file)-4:]) != parquetMagic {
+		t.Fatal("missing PAR1 magic")
+	}
+	footerLen := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	footerStart := len(file) - 8 - footerLen
+
+	meta, err := (&thriftReader{b: file, pos: footerStart}).readStruct()
+	if err != nil {
+		t.Fatalf("read footer: %v", err)
+	}
+	if meta[3] != int64(3) {
+		t.Errorf("num_rows = %v, want 3", meta[3])
+	}
+	schema := meta[2].([]interface{})
+	if len(schema) != 3 {
+		t.Fatalf("schema has %d elements, want 3", len(schema))
+	}
+	if ts := schema[2].(map[int16]interface{}); ts[4] != "block_time" || ts[6] != int64(parquetConvertedTimestampMillis) {
+		t.Errorf("schema[2] = %v", ts)
+	}
+
+	rowGroup := meta[4].([]interface{})[0].(map[int16]interface{})
+	chunks := rowGroup[1].([]interface{})
+	if len(chunks) != 2 {
+		t.Fatalf("row group has %d columns, want 2", len(chunks))
+	}
+
+	// Read back the first column's page.
+	columnMeta := chunks[0].(map[int16]interface{})[3].(map[int16]interface{})
+	page := &thriftReader{b: file, pos: int(columnMeta[9].(int64))}
+	header, err := page.readStruct()
+	if err != nil {
+		t.Fatalf("read page header: %v", err)
+	}
+	compressedSize := int(header[3].(int64))
+	zr, err := gzip.NewReader(bytes.NewReader(file[page.pos : page.pos+compressedSize]))
+	if err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	if !bytes.Equal(data, columns[0].plain()) {
+		t.Errorf("page data = %q, want %q", data, columns[0].plain())
+	}
+	if want := int64(page.pos+compressedSize) - columnMeta[9].(int64); columnMeta[7] != want {
+		t.Errorf("total_compressed_size = %v, want %d", columnMeta[7], want)
+	}
+}
+
+func TestWriteParquet_RowCountMismatch(t *testing.T) {
+	columns := []parquetColumn{{name: "slot", int64s: []int64{1}}}
+	if err := writeParquet(io.Discard, columns, 2); err == nil {
+		t.Error("writeParquet() error = nil, want row count error")
+	}
+}
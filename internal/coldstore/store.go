@@ -0,0 +1,118 @@
+package coldstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+// ObjectStore stores exported files under a key.
+type ObjectStore interface {
+	Put(ctx context.Context, key string, body []byte) error
+}
+
+type S3Options struct {
+	Bucket string
+	Region string
+	// Endpoint defaults to the regional AWS endpoint. Set it for S3
+	// compatible stores such as MinIO.
+	Endpoint    string
+	Credentials aws.Credentials
+}
+
+// S3Store uploads objects with the S3 REST API using path style URLs.
+type S3Store struct {
+	httpClient *http.Client
+	endpoint   string
+	bucket     string
+	region     string
+	creds      aws.Credentials
+	signer     *v4.Signer
+	now        func() time.Time
+}
+
+func NewS3Store(opts S3Options) (*S3Store, error) {
+	if opts.Bucket == "" {
+		return nil, fmt.Errorf("bucket is required")
+	}
+	endpoint := opts.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", opts.Region)
+	}
+	return &S3Store{
+		httpClient: &http.Client{Timeout: 5 * time.Minute},
+		endpoint:   strings.TrimRight(endpoint, "/"),
+		bucket:     opts.Bucket,
+		region:     opts.Region,
+		creds:      opts.Credentials,
+		// Object keys are escaped once by escapeObjectKey, as S3 expects.
+		signer: v4.NewSigner(func(o *v4.SignerOptions) { o.DisableURIPathEscaping = true }),
+		now:    time.Now,
+	}, nil
+}
+
+// NewGCSStore uploads to Google Cloud Storage through its S3 compatible XML
+// API, authenticated with an HMAC key of a service account.
+func NewGCSStore(bucket string, creds aws.Credentials) (*S3Store, error) {
+	return NewS3Store(S3Options{
+		Bucket:      bucket,
+		Region:      "auto",
+		Endpoint:    "https://storage.googleapis.com",
+		Credentials: creds,
+	})
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, body []byte) error {
+	url := s.endpoint + "/" + escapeObjectKey(s.bucket) + "/" + escapeObjectKey(key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	hash := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(hash[:])
+	req.Header.Set("Content-Type", "application/vnd.apache.parquet")
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if err := s.signer.SignHTTP(ctx, s.creds, req, payloadHash, "s3", s.region, s.now()); err != nil {
+		return fmt.Errorf("sign request: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("put %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("put %s failed with status %d: %s", key, resp.StatusCode, respBody)
+	}
+	return nil
+}
+
+// escapeObjectKey percent-encodes everything but unreserved characters and
+// slashes, as the SigV4 canonical URI requires. url.PathEscape would leave
+// characters such as '=' in partition names unescaped.
+func escapeObjectKey(key string) string {
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package coldstore
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Thrift compact protocol type ids.
+const (
+	compactI32    = 5
+	compactI64    = 6
+	compactBinary = 8
+	compactList   = 9
+	compactStruct = 12
+)
+
+// compactWriter encodes Thrift structs with the compact protocol, which
+// Parquet uses for page headers and the file footer. Only the types Parquet
+// metadata needs are supported.
+type compactWriter struct {
+	buf    bytes.Buffer
+	lastID []int16
+}
+
+// beginStruct starts a struct that is a list element or the top level value.
+func (w *compactWriter) beginStruct() {
+	w.lastID = append(w.lastID, 0)
+}
+
+func (w *compactWriter) endStruct() {
+	w.buf.WriteByte(0)
+	w.lastID = w.lastID[:len(w.lastID)-1]
+}
+
+func (w *compactWriter) structField(id int16) {
+	w.fieldHeader(id, compactStruct)
+	w.beginStruct()
+}
+
+func (w *compactWriter) i32Field(id int16, v int32) {
+	w.fieldHeader(id, compactI32)
+	w.i32(v)
+}
+
+func (w *compactWriter) i64Field(id int16, v int64) {
+	w.fieldHeader(id, compactI64)
+	w.uvarint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (w *compactWriter) stringField(id int16, v string) {
+	w.fieldHeader(id, compactBinary)
+	w.string(v)
+}
+
+func (w *compactWriter) listField(id int16, elemType byte, size int) {
+	w.fieldHeader(id, compactList)
+	if size < 15 {
+		w.buf.WriteByte(byte(size)<<4 | elemType)
+		return
+	}
+	w.buf.WriteByte(0xf0 | elemType)
+	w.uvarint(uint64(size))
+}
+
+func (w *compactWriter) i32(v int32) {
+	w.uvarint(uint64(uint32((v << 1) ^ (v >> 31))))
+}
+
+func (w *compactWriter) string(v string) {
+	w.uvarint(uint64(len(v)))
+	w.buf.WriteString(v)
+}
+
+func (w *compactWriter) fieldHeader(id int16, fieldType byte) {
+	top := len(w.lastID) - 1
+	if delta := id - w.lastID[top]; delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | fieldType)
+	} else {
+		w.buf.WriteByte(fieldType)
+		w.uvarint(uint64(uint16((id << 1) ^ (id >> 15))))
+	}
+	w.lastID[top] = id
+}
+
+func (w *compactWriter) uvarint(v uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	w.buf.Write(tmp[:n])
+}
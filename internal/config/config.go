@@ -3,9 +3,18 @@ package config
 import (
 	"fmt"
 	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/lugondev/go-indexer-solana-starter/internal/fieldcrypt"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+	pkgdecoder "github.com/lugondev/go-indexer-solana-starter/pkg/decoder"
 )
 
 type DatabaseType string
@@ -15,17 +24,79 @@ const (
 	DatabaseTypePostgres DatabaseType = "postgres"
 )
 
+type AWSSinkType string
+
+const (
+	AWSSinkTypeNone    AWSSinkType = ""
+	AWSSinkTypeKinesis AWSSinkType = "kinesis"
+	AWSSinkTypeSQS     AWSSinkType = "sqs"
+)
+
 type Config struct {
+	// Network tags the events, checkpoints and watermarks of this
+	// configuration, which reads the <NETWORK>_ variables of the network
+	// over the others; empty when a single network is indexed. Networks
+	// are the networks "indexer run" indexes at once.
+	Network  string
+	Networks []string
+
 	SolanaRPCURL string
 	SolanaWSURL  string
 
 	StarterProgramID string
 	CounterProgramID string
+	// CounterProgramIDL is the Anchor IDL of the counter program, from
+	// which the accounts of its instructions are resolved; empty uses the
+	// account orders of the reference counter program.
+	CounterProgramIDL string
+	// CounterDeploymentPrograms maps deployment labels to the program IDs
+	// of several counter program deployments; see CounterDeployments.
+	CounterDeploymentPrograms map[string]string
+	// ProgramTenants maps program IDs to the tenant owning them. Their
+	// events are stored with the tenant and API users of a tenant only see
+	// those events.
+	ProgramTenants map[string]string
+	// LogGrammarFile is a YAML file of log grammars, each indexing a
+	// program from its log lines; empty indexes none.
+	LogGrammarFile string
+	// DecoderPlugins are Go plugins each adding the decoder of a program,
+	// see pkg/decoder.LoadPlugin.
+	DecoderPlugins []string
+	// IDLPrograms are programs whose events are decoded with the Anchor
+	// IDL uploaded for them, fetched at start.
+	IDLPrograms []string
+	// SplTokenMints are mints whose SPL Token and Token-2022 instructions
+	// are indexed with the built-in token decoder.
+	SplTokenMints []string
+	// SolWatchlist are addresses whose native SOL transfers are indexed.
+	SolWatchlist []string
+	// WatchlistWebhookURL is notified when an address of the runtime
+	// watchlist appears in a transaction; WatchlistWebhookSecret signs the
+	// notifications.
+	WatchlistWebhookURL    string
+	WatchlistWebhookSecret string
+	// NotifyRulesFile is a YAML file of rules posting a message to Discord
+	// or Telegram when events of given types are indexed; empty posts none.
+	NotifyRulesFile string
+	// TelegramBotToken is the bot the Telegram chats of notification rules
+	// are posted to with.
+	TelegramBotToken string
 
+	// StartFrom is where indexing starts when a program has no checkpoint
+	// yet: "genesis", "latest", "slot" (StartSlot) or "signature"
+	// (StartSignature). Empty picks from the fields that are set; use
+	// StartStrategy for the effective value.
+	StartFrom      string
 	StartSlot      uint64
+	StartSignature string
 	PollInterval   time.Duration
 	BatchSize      int
 	MaxConcurrency int
+	// PipelineQueueSize bounds the transactions waiting between the fetch,
+	// decode and store stages of a program.
+	PipelineQueueSize int
+
+	ProgramDataMode string
 
 	DatabaseType DatabaseType
 	DatabaseURL  string
@@ -33,25 +104,399 @@ type Config struct {
 
 	ServerPort int
 	LogLevel   string
+
+	AWSSinkType          AWSSinkType
+	AWSRegion            string
+	AWSEndpoint          string
+	AWSAccessKeyID       string
+	AWSSecretAccessKey   string
+	AWSSessionToken      string
+	AWSSinkTarget        string
+	AWSSinkRoutes        map[string]string
+	AWSSinkBatchSize     int
+	AWSSinkFlushInterval time.Duration
+
+	CacheInvalidationMintURL       string
+	CacheInvalidationCollectionURL string
+	CacheInvalidationWalletURL     string
+	CacheInvalidationMethod        string
+	CacheInvalidationToken         string
+
+	RedisURL           string
+	RedisEventTTL      time.Duration
+	RedisLatestTTL     time.Duration
+	RedisChannelPrefix string
+	// RedisHotWindow is how much recent history is kept in Redis to serve
+	// latest-events queries; 0 disables the hot tier.
+	RedisHotWindow time.Duration
+
+	APIRateLimitPerMinute int
+
+	CounterMinFeeLamports uint64
+
+	ConfigMirrorEnabled bool
+
+	// EventRollupsEnabled keeps the per minute and per hour event counts
+	// of the rollup tables.
+	EventRollupsEnabled bool
+
+	MongoCollectionLayout string
+
+	IdentityProvider string
+	IdentityCacheTTL time.Duration
+
+	RetentionDays      int
+	RetentionOverrides map[string]string
+	// RetentionMode is RETENTION_MODE as set; ParseRetentionMode parses it.
+	RetentionMode     string
+	RetentionInterval time.Duration
+
+	ColdExportProvider  string
+	ColdExportBucket    string
+	ColdExportPrefix    string
+	ColdExportEndpoint  string
+	ColdExportAfterDays int
+	ColdExportInterval  time.Duration
+	ColdExportBatchSize int
+	GCSHMACAccessID     string
+	GCSHMACSecret       string
+
+	SinkLagInterval time.Duration
+
+	IdleAfter        time.Duration
+	IdlePollInterval time.Duration
+
+	ReportCheckInterval      time.Duration
+	ReportMaxRows            int
+	SMTPAddr                 string
+	SMTPUsername             string
+	SMTPPassword             string
+	SMTPFrom                 string
+	GoogleServiceAccountFile string
+
+	DatabaseAutoMigrate bool
+	// PostgresPartitionSlots partitions a new PostgreSQL events table by
+	// ranges of this many slots; zero leaves it unpartitioned.
+	PostgresPartitionSlots int
+	// PostgresCopyThreshold is the batch size from which events are
+	// written to PostgreSQL with COPY instead of INSERTs.
+	PostgresCopyThreshold int
+	// StoreRawData keeps the program data payload an event was decoded
+	// from in its raw_data, except for event types with EncryptedFields.
+	StoreRawData bool
+	// RawDataCompression compresses the raw data of stored events: "none",
+	// "gzip" or "zstd".
+	RawDataCompression string
+
+	EventAllowlist     []string
+	EventDenylist      []string
+	EventAccountFilter []string
+
+	// DedupFields maps event types to the "+" separated fields identifying
+	// duplicates of an event; see DedupFieldsByType.
+	DedupFields map[string]string
+	DedupWindow time.Duration
+
+	// EncryptedFields maps event types to the "+" separated fields stored
+	// encrypted with FieldEncryptionKey, a base64 AES key; see
+	// EncryptedFieldsByType.
+	EncryptedFields    map[string]string
+	FieldEncryptionKey string
+
+	MongoCollectionOverrides map[string]string
+	MongoExtraIndexes        map[string]string
+
+	StreamWindows       map[string]string
+	StreamWindowHistory int
+
+	NftMetadataEnabled     bool
+	NftMetadataInterval    time.Duration
+	NftMetadataBatchSize   int
+	NftMetadataTimeout     time.Duration
+	NftMetadataMaxAttempts int
+	NftMetadataIPFSGateway string
+
+	APIUsers map[string]string
+
+	RPCBreakerThreshold int
+	RPCBreakerCooldown  time.Duration
+	// RPCFallbackURLs are called in order once the budgets of
+	// SolanaRPCURL and the fallbacks before them are exhausted.
+	RPCFallbackURLs []string
+	// RPCBudgets maps endpoint names to request budgets; see RPCEndpoints.
+	RPCBudgets map[string]string
+	// RPCMaxInFlight caps the RPC calls in flight, shared by live
+	// polling, repair and backfill by RPCPriorityWeights; zero disables
+	// scheduling.
+	RPCMaxInFlight     int
+	RPCPriorityWeights map[string]string
+
+	SeenCacheSize              int
+	SeenBloomCapacity          int
+	SeenBloomFalsePositiveRate float64
+	SeenBloomPath              string
+	SeenBloomSaveInterval      time.Duration
+
+	// BlockTimeCacheSize is how many slot block times the RPC client keeps
+	// in memory; 0 disables the cache.
+	BlockTimeCacheSize int
+
+	// Sinks lists the outputs every event is written to besides the primary
+	// database: mongodb, postgres, kafka, webhook or stdout.
+	Sinks             []string
+	SinkBatchSize     int
+	SinkFlushInterval time.Duration
+	SinkMongoURL      string
+	SinkPostgresURL   string
+	SinkWebhookURL    string
+	SinkWebhookToken  string
+	SinkWebhookSecret string
+	KafkaRESTURL      string
+	KafkaTopic        string
+
+	// OutboxEnabled delivers the message bus sinks through a transactional
+	// outbox instead of publishing to them directly.
+	OutboxEnabled   bool
+	OutboxInterval  time.Duration
+	OutboxBatchSize int
+
+	// APIV1DeprecatedSince and APIV1Sunset are YYYY-MM-DD dates; the former
+	// marks /api/v1 deprecated.
+	APIV1DeprecatedSince string
+	APIV1Sunset          string
+
+	// AccountMonitorInterval is how often the size and rent of program
+	// accounts are sampled; zero disables sampling.
+	AccountMonitorInterval      time.Duration
+	AccountMonitorSizeWarnRatio float64
+
+	// UpgradeCheckInterval is how often the indexed programs are checked
+	// for upgrades; zero disables it. UpgradeRefetchIDL fetches the IDL of
+	// an IDL_PROGRAMS program again when it is upgraded.
+	UpgradeCheckInterval time.Duration
+	UpgradeRefetchIDL    bool
+
+	// WatchdogStallAfter raises an alert when no slot has been processed
+	// and the finalized watermark has not moved for this long; zero
+	// disables it. WatchdogMaxErrorsPerMinute raises one when pipeline
+	// errors average more over WatchdogErrorWindow; zero disables it.
+	// Alerts are POSTed to WatchdogWebhookURL when set.
+	WatchdogStallAfter         time.Duration
+	WatchdogMaxErrorsPerMinute float64
+	WatchdogErrorWindow        time.Duration
+	WatchdogWebhookURL         string
+	WatchdogWebhookSecret      string
+
+	// OfflineBufferDir enables buffering events on local disk while the
+	// database is unreachable; they are replayed every
+	// OfflineBufferRetryInterval. Indexing pauses once OfflineBufferMaxMB
+	// is buffered.
+	OfflineBufferDir           string
+	OfflineBufferMaxMB         int
+	OfflineBufferRetryInterval time.Duration
 }
 
-func Load() (*Config, error) {
-	_ = godotenv.Load()
+// SinkNames are the outputs SINKS accepts.
+var SinkNames = []string{string(DatabaseTypeMongo), string(DatabaseTypePostgres), "kafka", "webhook", "stdout"}
+
+// load reads the configuration of network, or of NETWORK when it is empty.
+func load(network string) (*Config, error) {
+	if values, err := godotenv.Read(); err == nil {
+		if err := setFileEnv(values); err != nil {
+			return nil, fmt.Errorf("load .env: %w", err)
+		}
+	}
+	if path := os.Getenv("CONFIG_MANIFEST"); path != "" {
+		if err := applyManifest(path); err != nil {
+			return nil, fmt.Errorf("apply CONFIG_MANIFEST: %w", err)
+		}
+	}
+
+	if network == "" {
+		network = strings.TrimSpace(os.Getenv("NETWORK"))
+	}
+	envPrefix = NetworkEnvPrefix(network)
+	defer func() { envPrefix = "" }()
 
 	cfg := &Config{
-		SolanaRPCURL:     getEnvOrDefault("SOLANA_RPC_URL", "https://api.devnet.solana.com"),
-		SolanaWSURL:      getEnvOrDefault("SOLANA_WS_URL", "wss://api.devnet.solana.com"),
-		StarterProgramID: getEnvOrDefault("STARTER_PROGRAM_ID", "gARh1g6reuvsAHB7DXqiuYzzyiJeoiJmtmCpV8Y5uWC"),
-		CounterProgramID: getEnvOrDefault("COUNTER_PROGRAM_ID", "CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc"),
-		StartSlot:        uint64(getEnvIntOrDefault("START_SLOT", 0)),
-		PollInterval:     time.Duration(getEnvIntOrDefault("POLL_INTERVAL_MS", 1000)) * time.Millisecond,
-		BatchSize:        getEnvIntOrDefault("BATCH_SIZE", 10),
-		MaxConcurrency:   getEnvIntOrDefault("MAX_CONCURRENCY", 5),
-		DatabaseType:     DatabaseType(getEnvOrDefault("DATABASE_TYPE", "mongodb")),
-		DatabaseURL:      getEnvOrDefault("DATABASE_URL", "mongodb://localhost:27017"),
-		DatabaseName:     getEnvOrDefault("DATABASE_NAME", "solana_indexer"),
-		ServerPort:       getEnvIntOrDefault("SERVER_PORT", 8080),
-		LogLevel:         getEnvOrDefault("LOG_LEVEL", "info"),
+		Network:  network,
+		Networks: getEnvListOrDefault("NETWORKS"),
+
+		SolanaRPCURL:      getEnvOrDefault("SOLANA_RPC_URL", "https://api.devnet.solana.com"),
+		SolanaWSURL:       getEnvOrDefault("SOLANA_WS_URL", "wss://api.devnet.solana.com"),
+		StarterProgramID:  getEnvOrDefault("STARTER_PROGRAM_ID", "gARh1g6reuvsAHB7DXqiuYzzyiJeoiJmtmCpV8Y5uWC"),
+		CounterProgramID:  getEnvOrDefault("COUNTER_PROGRAM_ID", "CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc"),
+		CounterProgramIDL: getEnvOrDefault("COUNTER_PROGRAM_IDL", ""),
+		ProgramTenants:    getEnvMapOrDefault("PROGRAM_TENANTS"),
+		StartFrom:         getEnvOrDefault("START_FROM", ""),
+		StartSlot:         uint64(getEnvIntOrDefault("START_SLOT", 0)),
+		StartSignature:    getEnvOrDefault("START_SIGNATURE", ""),
+		PollInterval:      time.Duration(getEnvIntOrDefault("POLL_INTERVAL_MS", 1000)) * time.Millisecond,
+		BatchSize:         getEnvIntOrDefault("BATCH_SIZE", 10),
+		MaxConcurrency:    getEnvIntOrDefault("MAX_CONCURRENCY", 5),
+		ProgramDataMode:   getEnvOrDefault("PROGRAM_DATA_MODE", "lenient"),
+		DatabaseType:      DatabaseType(getEnvOrDefault("DATABASE_TYPE", "mongodb")),
+		DatabaseURL:       getEnvOrDefault("DATABASE_URL", "mongodb://localhost:27017"),
+		DatabaseName:      getEnvOrDefault("DATABASE_NAME", "solana_indexer"),
+		ServerPort:        getEnvIntOrDefault("SERVER_PORT", 8080),
+		LogLevel:          getEnvOrDefault("LOG_LEVEL", "info"),
+
+		CounterDeploymentPrograms: getEnvMapOrDefault("COUNTER_DEPLOYMENTS"),
+		LogGrammarFile:            getEnvOrDefault("LOG_GRAMMAR_FILE", ""),
+		DecoderPlugins:            getEnvListOrDefault("DECODER_PLUGINS"),
+		IDLPrograms:               getEnvListOrDefault("IDL_PROGRAMS"),
+		SplTokenMints:             getEnvListOrDefault("SPL_TOKEN_MINTS"),
+		SolWatchlist:              getEnvListOrDefault("SOL_WATCHLIST"),
+		WatchlistWebhookURL:       getEnvOrDefault("WATCHLIST_WEBHOOK_URL", ""),
+		WatchlistWebhookSecret:    getEnvOrDefault("WATCHLIST_WEBHOOK_SECRET", ""),
+		NotifyRulesFile:           getEnvOrDefault("NOTIFY_RULES_FILE", ""),
+		TelegramBotToken:          getEnvOrDefault("TELEGRAM_BOT_TOKEN", ""),
+
+		PipelineQueueSize: getEnvIntOrDefault("PIPELINE_QUEUE_SIZE", 100),
+
+		AWSSinkType:          AWSSinkType(getEnvOrDefault("AWS_SINK_TYPE", "")),
+		AWSRegion:            getEnvOrDefault("AWS_REGION", "us-east-1"),
+		AWSEndpoint:          getEnvOrDefault("AWS_ENDPOINT_URL", ""),
+		AWSAccessKeyID:       getEnvOrDefault("AWS_ACCESS_KEY_ID", ""),
+		AWSSecretAccessKey:   getEnvOrDefault("AWS_SECRET_ACCESS_KEY", ""),
+		AWSSessionToken:      getEnvOrDefault("AWS_SESSION_TOKEN", ""),
+		AWSSinkTarget:        getEnvOrDefault("AWS_SINK_TARGET", ""),
+		AWSSinkRoutes:        getEnvMapOrDefault("AWS_SINK_ROUTES"),
+		AWSSinkBatchSize:     getEnvIntOrDefault("AWS_SINK_BATCH_SIZE", 0),
+		AWSSinkFlushInterval: time.Duration(getEnvIntOrDefault("AWS_SINK_FLUSH_INTERVAL_MS", 1000)) * time.Millisecond,
+
+		CacheInvalidationMintURL:       getEnvOrDefault("CACHE_INVALIDATION_MINT_URL", ""),
+		CacheInvalidationCollectionURL: getEnvOrDefault("CACHE_INVALIDATION_COLLECTION_URL", ""),
+		CacheInvalidationWalletURL:     getEnvOrDefault("CACHE_INVALIDATION_WALLET_URL", ""),
+		CacheInvalidationMethod:        getEnvOrDefault("CACHE_INVALIDATION_METHOD", "POST"),
+		CacheInvalidationToken:         getEnvOrDefault("CACHE_INVALIDATION_TOKEN", ""),
+
+		RedisURL:           getEnvOrDefault("REDIS_URL", ""),
+		RedisEventTTL:      time.Duration(getEnvIntOrDefault("REDIS_EVENT_TTL_SECONDS", 3600)) * time.Second,
+		RedisLatestTTL:     time.Duration(getEnvIntOrDefault("REDIS_LATEST_TTL_MS", 2000)) * time.Millisecond,
+		RedisChannelPrefix: getEnvOrDefault("REDIS_CHANNEL_PREFIX", "solana_indexer:events"),
+		RedisHotWindow:     time.Duration(getEnvIntOrDefault("REDIS_HOT_WINDOW_MINUTES", 0)) * time.Minute,
+
+		APIRateLimitPerMinute: getEnvIntOrDefault("API_RATE_LIMIT_PER_MINUTE", 100),
+
+		CounterMinFeeLamports: uint64(getEnvIntOrDefault("COUNTER_MIN_FEE_LAMPORTS", 0)),
+
+		ConfigMirrorEnabled: getEnvBoolOrDefault("CONFIG_MIRROR_ENABLED", true),
+
+		EventRollupsEnabled: getEnvBoolOrDefault("EVENT_ROLLUPS_ENABLED", true),
+
+		MongoCollectionLayout: getEnvOrDefault("MONGO_COLLECTION_LAYOUT", "single"),
+
+		IdentityProvider: getEnvOrDefault("IDENTITY_PROVIDER", ""),
+		IdentityCacheTTL: time.Duration(getEnvIntOrDefault("IDENTITY_CACHE_TTL_SECONDS", 3600)) * time.Second,
+
+		RetentionDays:      getEnvIntOrDefault("RETENTION_DAYS", 0),
+		RetentionOverrides: getEnvMapOrDefault("RETENTION_OVERRIDES"),
+		RetentionMode:      getEnvOrDefault("RETENTION_MODE", string(RetentionDelete)),
+		RetentionInterval:  time.Duration(getEnvIntOrDefault("RETENTION_INTERVAL_MINUTES", 60)) * time.Minute,
+
+		ColdExportProvider:  getEnvOrDefault("COLD_EXPORT_PROVIDER", ""),
+		ColdExportBucket:    getEnvOrDefault("COLD_EXPORT_BUCKET", ""),
+		ColdExportPrefix:    getEnvOrDefault("COLD_EXPORT_PREFIX", "events"),
+		ColdExportEndpoint:  getEnvOrDefault("COLD_EXPORT_ENDPOINT", ""),
+		ColdExportAfterDays: getEnvIntOrDefault("COLD_EXPORT_AFTER_DAYS", 30),
+		ColdExportInterval:  time.Duration(getEnvIntOrDefault("COLD_EXPORT_INTERVAL_MINUTES", 60)) * time.Minute,
+		ColdExportBatchSize: getEnvIntOrDefault("COLD_EXPORT_BATCH_SIZE", 5000),
+		GCSHMACAccessID:     getEnvOrDefault("GCS_HMAC_ACCESS_ID", ""),
+		GCSHMACSecret:       getEnvOrDefault("GCS_HMAC_SECRET", ""),
+
+		SinkLagInterval: time.Duration(getEnvIntOrDefault("SINK_LAG_INTERVAL_SECONDS", 30)) * time.Second,
+
+		IdleAfter:        time.Duration(getEnvIntOrDefault("IDLE_AFTER_SECONDS", 60)) * time.Second,
+		IdlePollInterval: time.Duration(getEnvIntOrDefault("IDLE_MAX_POLL_INTERVAL_MS", 30000)) * time.Millisecond,
+
+		ReportCheckInterval:      time.Duration(getEnvIntOrDefault("REPORT_CHECK_INTERVAL_SECONDS", 60)) * time.Second,
+		ReportMaxRows:            getEnvIntOrDefault("REPORT_MAX_ROWS", 50000),
+		SMTPAddr:                 getEnvOrDefault("SMTP_ADDR", ""),
+		SMTPUsername:             getEnvOrDefault("SMTP_USERNAME", ""),
+		SMTPPassword:             getEnvOrDefault("SMTP_PASSWORD", ""),
+		SMTPFrom:                 getEnvOrDefault("SMTP_FROM", ""),
+		GoogleServiceAccountFile: getEnvOrDefault("GOOGLE_SERVICE_ACCOUNT_FILE", ""),
+
+		DatabaseAutoMigrate:    getEnvBoolOrDefault("DATABASE_AUTO_MIGRATE", true),
+		PostgresPartitionSlots: getEnvIntOrDefault("POSTGRES_PARTITION_SLOTS", 0),
+		PostgresCopyThreshold:  getEnvIntOrDefault("POSTGRES_COPY_THRESHOLD", 100),
+		StoreRawData:           getEnvBoolOrDefault("STORE_RAW_DATA", false),
+		RawDataCompression:     getEnvOrDefault("RAW_DATA_COMPRESSION", "none"),
+
+		EventAllowlist:     getEnvListOrDefault("EVENT_ALLOWLIST"),
+		EventDenylist:      getEnvListOrDefault("EVENT_DENYLIST"),
+		EventAccountFilter: getEnvListOrDefault("EVENT_ACCOUNT_FILTER"),
+
+		DedupFields: getEnvMapOrDefault("DEDUP_FIELDS"),
+		DedupWindow: time.Duration(getEnvIntOrDefault("DEDUP_WINDOW_SECONDS", 600)) * time.Second,
+
+		EncryptedFields:    getEnvMapOrDefault("ENCRYPTED_FIELDS"),
+		FieldEncryptionKey: getEnvOrDefault("FIELD_ENCRYPTION_KEY", ""),
+
+		MongoCollectionOverrides: getEnvMapOrDefault("MONGO_COLLECTION_OVERRIDES"),
+		MongoExtraIndexes:        getEnvMapOrDefault("MONGO_EXTRA_INDEXES"),
+
+		StreamWindows:       getEnvMapOrDefault("STREAM_WINDOWS"),
+		StreamWindowHistory: getEnvIntOrDefault("STREAM_WINDOW_HISTORY", 12),
+
+		NftMetadataEnabled:     getEnvBoolOrDefault("NFT_METADATA_ENABLED", false),
+		NftMetadataInterval:    time.Duration(getEnvIntOrDefault("NFT_METADATA_INTERVAL_SECONDS", 30)) * time.Second,
+		NftMetadataBatchSize:   getEnvIntOrDefault("NFT_METADATA_BATCH_SIZE", 20),
+		NftMetadataTimeout:     time.Duration(getEnvIntOrDefault("NFT_METADATA_TIMEOUT_SECONDS", 10)) * time.Second,
+		NftMetadataMaxAttempts: getEnvIntOrDefault("NFT_METADATA_MAX_ATTEMPTS", 5),
+		NftMetadataIPFSGateway: getEnvOrDefault("NFT_METADATA_IPFS_GATEWAY", "https://ipfs.io/ipfs/"),
+
+		APIUsers: getEnvMapOrDefault("API_USERS"),
+
+		RPCBreakerThreshold: getEnvIntOrDefault("RPC_BREAKER_THRESHOLD", 5),
+		RPCBreakerCooldown:  time.Duration(getEnvIntOrDefault("RPC_BREAKER_COOLDOWN_SECONDS", 30)) * time.Second,
+		RPCFallbackURLs:     getEnvListOrDefault("RPC_FALLBACK_URLS"),
+		RPCBudgets:          getEnvMapOrDefault("RPC_BUDGETS"),
+		RPCMaxInFlight:      getEnvIntOrDefault("RPC_MAX_IN_FLIGHT", 16),
+		RPCPriorityWeights:  getEnvMapOrDefault("RPC_PRIORITY_WEIGHTS"),
+
+		SeenCacheSize:              getEnvIntOrDefault("SEEN_CACHE_SIZE", 100000),
+		SeenBloomCapacity:          getEnvIntOrDefault("SEEN_BLOOM_CAPACITY", 1000000),
+		SeenBloomFalsePositiveRate: getEnvFloatOrDefault("SEEN_BLOOM_FALSE_POSITIVE_RATE", 0.001),
+		SeenBloomPath:              getEnvOrDefault("SEEN_BLOOM_PATH", ""),
+		SeenBloomSaveInterval:      time.Duration(getEnvIntOrDefault("SEEN_BLOOM_SAVE_INTERVAL_SECONDS", 60)) * time.Second,
+
+		BlockTimeCacheSize: getEnvIntOrDefault("BLOCK_TIME_CACHE_SIZE", 10000),
+
+		Sinks:             getEnvListOrDefault("SINKS"),
+		SinkBatchSize:     getEnvIntOrDefault("SINK_BATCH_SIZE", 1),
+		SinkFlushInterval: time.Duration(getEnvIntOrDefault("SINK_FLUSH_INTERVAL_MS", 1000)) * time.Millisecond,
+		SinkMongoURL:      getEnvOrDefault("SINK_MONGO_URL", ""),
+		SinkPostgresURL:   getEnvOrDefault("SINK_POSTGRES_URL", ""),
+		SinkWebhookURL:    getEnvOrDefault("SINK_WEBHOOK_URL", ""),
+		SinkWebhookToken:  getEnvOrDefault("SINK_WEBHOOK_TOKEN", ""),
+		SinkWebhookSecret: getEnvOrDefault("SINK_WEBHOOK_SECRET", ""),
+		KafkaRESTURL:      getEnvOrDefault("KAFKA_REST_URL", ""),
+		KafkaTopic:        getEnvOrDefault("KAFKA_TOPIC", "solana_indexer.events"),
+
+		OutboxEnabled:   getEnvBoolOrDefault("OUTBOX_ENABLED", false),
+		OutboxInterval:  time.Duration(getEnvIntOrDefault("OUTBOX_POLL_INTERVAL_MS", 1000)) * time.Millisecond,
+		OutboxBatchSize: getEnvIntOrDefault("OUTBOX_BATCH_SIZE", 100),
+
+		APIV1DeprecatedSince: getEnvOrDefault("API_V1_DEPRECATED_SINCE", ""),
+		APIV1Sunset:          getEnvOrDefault("API_V1_SUNSET", ""),
+
+		AccountMonitorInterval:      time.Duration(getEnvIntOrDefault("ACCOUNT_MONITOR_INTERVAL_SECONDS", 0)) * time.Second,
+		AccountMonitorSizeWarnRatio: getEnvFloatOrDefault("ACCOUNT_MONITOR_SIZE_WARN_RATIO", 0.9),
+
+		UpgradeCheckInterval: time.Duration(getEnvIntOrDefault("PROGRAM_UPGRADE_INTERVAL_SECONDS", 0)) * time.Second,
+		UpgradeRefetchIDL:    getEnvBoolOrDefault("PROGRAM_UPGRADE_REFETCH_IDL", false),
+
+		WatchdogStallAfter:         time.Duration(getEnvIntOrDefault("WATCHDOG_STALL_SECONDS", 300)) * time.Second,
+		WatchdogMaxErrorsPerMinute: getEnvFloatOrDefault("WATCHDOG_MAX_ERRORS_PER_MINUTE", 0),
+		WatchdogErrorWindow:        time.Duration(getEnvIntOrDefault("WATCHDOG_ERROR_WINDOW_SECONDS", 300)) * time.Second,
+		WatchdogWebhookURL:         getEnvOrDefault("WATCHDOG_WEBHOOK_URL", ""),
+		WatchdogWebhookSecret:      getEnvOrDefault("WATCHDOG_WEBHOOK_SECRET", ""),
+
+		OfflineBufferDir:           getEnvOrDefault("OFFLINE_BUFFER_DIR", ""),
+		OfflineBufferMaxMB:         getEnvIntOrDefault("OFFLINE_BUFFER_MAX_MB", 512),
+		OfflineBufferRetryInterval: time.Duration(getEnvIntOrDefault("OFFLINE_BUFFER_RETRY_SECONDS", 10)) * time.Second,
 	}
 
 	if err := cfg.Validate(); err != nil {
@@ -74,30 +519,462 @@ func (c *Config) Validate() error {
 	if c.MaxConcurrency <= 0 {
 		return fmt.Errorf("MAX_CONCURRENCY must be positive")
 	}
+	if c.PipelineQueueSize <= 0 {
+		return fmt.Errorf("PIPELINE_QUEUE_SIZE must be positive")
+	}
+	programs := map[string]bool{c.StarterProgramID: true}
+	for label, program := range c.CounterDeploymentPrograms {
+		if !tenantName.MatchString(label) {
+			return fmt.Errorf("COUNTER_DEPLOYMENTS: label %q must be 1-64 lowercase letters, digits, '-' or '_'", label)
+		}
+		if programs[program] {
+			return fmt.Errorf("COUNTER_DEPLOYMENTS: program %s is indexed twice", program)
+		}
+		programs[program] = true
+	}
+	for _, d := range c.CounterDeployments() {
+		programs[d.ProgramID] = true
+	}
+	if c.Network != "" && !ValidTenantName(c.Network) {
+		return fmt.Errorf("NETWORK: %q must be 1-64 lowercase letters, digits, '-' or '_'", c.Network)
+	}
+	for n, network := range c.Networks {
+		if !ValidTenantName(network) {
+			return fmt.Errorf("NETWORKS: %q must be 1-64 lowercase letters, digits, '-' or '_'", network)
+		}
+		if slices.Contains(c.Networks[:n], network) {
+			return fmt.Errorf("NETWORKS: %s is listed twice", network)
+		}
+	}
+	for program, tenant := range c.ProgramTenants {
+		if !programs[program] {
+			return fmt.Errorf("PROGRAM_TENANTS: %s is not an indexed program", program)
+		}
+		if !ValidTenantName(tenant) {
+			return fmt.Errorf("PROGRAM_TENANTS: tenant %q must be 1-64 lowercase letters, digits, '-' or '_'", tenant)
+		}
+	}
+	if len(c.DecoderPlugins) > 0 && !pkgdecoder.PluginsSupported {
+		return fmt.Errorf("DECODER_PLUGINS needs a build with cgo on Linux, macOS or FreeBSD; this binary was built without (the Docker image uses CGO_ENABLED=0)")
+	}
+	switch c.StartStrategy() {
+	case StartFromGenesis, StartFromLatest:
+	case StartFromSlot:
+		if c.StartSlot == 0 {
+			return fmt.Errorf("START_FROM=slot requires START_SLOT")
+		}
+	case StartFromSignature:
+		if !signaturePattern.MatchString(c.StartSignature) {
+			return fmt.Errorf("START_FROM=signature requires START_SIGNATURE to be a base58 transaction signature")
+		}
+	default:
+		return fmt.Errorf("START_FROM must be 'genesis', 'latest', 'slot' or 'signature'")
+	}
+	if _, err := pkgdecoder.ParseProgramDataMode(c.ProgramDataMode); err != nil {
+		return fmt.Errorf("PROGRAM_DATA_MODE must be 'strict' or 'lenient'")
+	}
 	if c.ServerPort <= 0 || c.ServerPort > 65535 {
 		return fmt.Errorf("SERVER_PORT must be between 1 and 65535")
 	}
+	if c.APIRateLimitPerMinute < 0 {
+		return fmt.Errorf("API_RATE_LIMIT_PER_MINUTE must not be negative")
+	}
 	if c.DatabaseType != DatabaseTypeMongo && c.DatabaseType != DatabaseTypePostgres {
 		return fmt.Errorf("DATABASE_TYPE must be 'mongodb' or 'postgres'")
 	}
+	if _, err := repository.ParseMongoLayout(c.MongoCollectionLayout); err != nil {
+		return fmt.Errorf("MONGO_COLLECTION_LAYOUT must be 'single', 'per_type' or 'per_program'")
+	}
+	switch c.RawDataCompression {
+	case "", "none", "gzip", "zstd":
+	default:
+		return fmt.Errorf("RAW_DATA_COMPRESSION must be 'none', 'gzip' or 'zstd'")
+	}
+	if c.DatabaseType == DatabaseTypePostgres && (len(c.MongoCollectionOverrides) > 0 || len(c.MongoExtraIndexes) > 0) {
+		return fmt.Errorf("MONGO_COLLECTION_OVERRIDES and MONGO_EXTRA_INDEXES require DATABASE_TYPE=mongodb")
+	}
+	if c.PostgresPartitionSlots < 0 {
+		return fmt.Errorf("POSTGRES_PARTITION_SLOTS must not be negative")
+	}
+	if c.PostgresCopyThreshold < 0 {
+		return fmt.Errorf("POSTGRES_COPY_THRESHOLD must not be negative")
+	}
+	if c.PostgresPartitionSlots > 0 && c.DatabaseType != DatabaseTypePostgres && c.SinkPostgresURL == "" {
+		return fmt.Errorf("POSTGRES_PARTITION_SLOTS requires DATABASE_TYPE=postgres or SINK_POSTGRES_URL")
+	}
+	retentionMode, err := ParseRetentionMode(c.RetentionMode)
+	if err != nil {
+		return fmt.Errorf("RETENTION_MODE must be 'delete' or 'archive'")
+	}
+	if c.DatabaseType == DatabaseTypePostgres && (c.RetentionDays > 0 || len(c.RetentionOverrides) > 0) {
+		// Retention drops whole partitions of the events table.
+		if c.PostgresPartitionSlots == 0 {
+			return fmt.Errorf("RETENTION_DAYS with DATABASE_TYPE=postgres requires POSTGRES_PARTITION_SLOTS")
+		}
+		if len(c.RetentionOverrides) > 0 || retentionMode == RetentionArchive {
+			return fmt.Errorf("RETENTION_OVERRIDES and RETENTION_MODE=archive require DATABASE_TYPE=mongodb")
+		}
+	}
+	if c.StreamWindowHistory < 0 {
+		return fmt.Errorf("STREAM_WINDOW_HISTORY must not be negative")
+	}
+	if c.NftMetadataEnabled {
+		if c.DatabaseType != DatabaseTypeMongo {
+			return fmt.Errorf("NFT_METADATA_ENABLED requires DATABASE_TYPE=mongodb")
+		}
+		if c.NftMetadataInterval <= 0 || c.NftMetadataBatchSize <= 0 || c.NftMetadataTimeout <= 0 || c.NftMetadataMaxAttempts <= 0 {
+			return fmt.Errorf("NFT_METADATA_INTERVAL_SECONDS, NFT_METADATA_BATCH_SIZE, NFT_METADATA_TIMEOUT_SECONDS and NFT_METADATA_MAX_ATTEMPTS must be positive")
+		}
+	}
+	if c.RPCBreakerThreshold < 0 {
+		return fmt.Errorf("RPC_BREAKER_THRESHOLD must not be negative")
+	}
+	if c.RPCBreakerThreshold > 0 && c.RPCBreakerCooldown <= 0 {
+		return fmt.Errorf("RPC_BREAKER_COOLDOWN_SECONDS must be positive")
+	}
+	if _, err := c.RPCEndpoints(); err != nil {
+		return err
+	}
+	if c.RPCMaxInFlight < 0 {
+		return fmt.Errorf("RPC_MAX_IN_FLIGHT must not be negative")
+	}
+	if _, err := c.RPCWeights(); err != nil {
+		return err
+	}
+	if c.SeenCacheSize < 0 {
+		return fmt.Errorf("SEEN_CACHE_SIZE must not be negative")
+	}
+	if c.SeenCacheSize > 0 {
+		if c.SeenBloomCapacity <= 0 {
+			return fmt.Errorf("SEEN_BLOOM_CAPACITY must be positive")
+		}
+		if c.SeenBloomFalsePositiveRate <= 0 || c.SeenBloomFalsePositiveRate >= 1 {
+			return fmt.Errorf("SEEN_BLOOM_FALSE_POSITIVE_RATE must be between 0 and 1")
+		}
+	}
+	if c.RedisHotWindow < 0 {
+		return fmt.Errorf("REDIS_HOT_WINDOW_MINUTES must not be negative")
+	}
+	if c.RedisHotWindow > 0 && c.RedisURL == "" {
+		return fmt.Errorf("REDIS_HOT_WINDOW_MINUTES requires REDIS_URL")
+	}
+	if c.BlockTimeCacheSize < 0 {
+		return fmt.Errorf("BLOCK_TIME_CACHE_SIZE must not be negative")
+	}
+	if err := c.validateSinks(); err != nil {
+		return err
+	}
+	if c.AccountMonitorInterval < 0 {
+		return fmt.Errorf("ACCOUNT_MONITOR_INTERVAL_SECONDS must not be negative")
+	}
+	if c.UpgradeCheckInterval < 0 {
+		return fmt.Errorf("PROGRAM_UPGRADE_INTERVAL_SECONDS must not be negative")
+	}
+	if c.AccountMonitorInterval > 0 && (c.AccountMonitorSizeWarnRatio <= 0 || c.AccountMonitorSizeWarnRatio > 1) {
+		return fmt.Errorf("ACCOUNT_MONITOR_SIZE_WARN_RATIO must be greater than 0 and at most 1")
+	}
+	if c.WatchdogStallAfter < 0 || c.WatchdogMaxErrorsPerMinute < 0 {
+		return fmt.Errorf("WATCHDOG_STALL_SECONDS and WATCHDOG_MAX_ERRORS_PER_MINUTE must not be negative")
+	}
+	if c.WatchdogMaxErrorsPerMinute > 0 && c.WatchdogErrorWindow < time.Minute {
+		return fmt.Errorf("WATCHDOG_ERROR_WINDOW_SECONDS must be at least 60")
+	}
+	if c.OfflineBufferDir != "" && (c.OfflineBufferMaxMB <= 0 || c.OfflineBufferRetryInterval <= 0) {
+		return fmt.Errorf("OFFLINE_BUFFER_MAX_MB and OFFLINE_BUFFER_RETRY_SECONDS must be positive")
+	}
+	if c.IdentityProvider != "" && c.IdentityProvider != "sns" {
+		return fmt.Errorf("IDENTITY_PROVIDER must be empty or 'sns'")
+	}
+	if c.RetentionDays < 0 {
+		return fmt.Errorf("RETENTION_DAYS must not be negative")
+	}
+	for eventType, days := range c.RetentionOverrides {
+		if n, err := strconv.Atoi(days); err != nil || n < 0 {
+			return fmt.Errorf("RETENTION_OVERRIDES: %s must be a non-negative number of days", eventType)
+		}
+	}
+	if (c.RetentionDays > 0 || len(c.RetentionOverrides) > 0) && c.RetentionInterval <= 0 {
+		return fmt.Errorf("RETENTION_INTERVAL_MINUTES must be positive")
+	}
+	for eventType := range c.DedupFields {
+		if !models.EventType(eventType).Known() {
+			return fmt.Errorf("DEDUP_FIELDS: unknown event type %q", eventType)
+		}
+	}
+	if len(c.DedupFields) > 0 && c.DedupWindow <= 0 {
+		return fmt.Errorf("DEDUP_WINDOW_SECONDS must be positive")
+	}
+	// STORE_RAW_DATA may be combined with ENCRYPTED_FIELDS: the cipher
+	// drops the raw data of the event types it encrypts, which would hold
+	// their fields in plain text.
+	if _, err := c.FieldCipher(); err != nil {
+		return err
+	}
+	if c.IdleAfter < 0 {
+		return fmt.Errorf("IDLE_AFTER_SECONDS must not be negative")
+	}
+	switch c.ColdExportProvider {
+	case "":
+	case "s3", "gcs":
+		if c.ColdExportBucket == "" {
+			return fmt.Errorf("COLD_EXPORT_BUCKET is required when COLD_EXPORT_PROVIDER is set")
+		}
+		if c.ColdExportProvider == "gcs" && (c.GCSHMACAccessID == "" || c.GCSHMACSecret == "") {
+			return fmt.Errorf("GCS_HMAC_ACCESS_ID and GCS_HMAC_SECRET are required when COLD_EXPORT_PROVIDER is gcs")
+		}
+		if c.ColdExportAfterDays <= 0 || c.ColdExportInterval <= 0 || c.ColdExportBatchSize <= 0 {
+			return fmt.Errorf("COLD_EXPORT_AFTER_DAYS, COLD_EXPORT_INTERVAL_MINUTES and COLD_EXPORT_BATCH_SIZE must be positive")
+		}
+	default:
+		return fmt.Errorf("COLD_EXPORT_PROVIDER must be 's3' or 'gcs'")
+	}
+	if c.ReportMaxRows < 0 {
+		return fmt.Errorf("REPORT_MAX_ROWS must not be negative")
+	}
+	if c.SMTPAddr != "" && c.SMTPFrom == "" {
+		return fmt.Errorf("SMTP_FROM is required when SMTP_ADDR is set")
+	}
 	if c.DatabaseURL == "" {
 		return fmt.Errorf("DATABASE_URL is required")
 	}
 	if c.DatabaseName == "" {
 		return fmt.Errorf("DATABASE_NAME is required")
 	}
+	switch c.AWSSinkType {
+	case AWSSinkTypeNone:
+	case AWSSinkTypeKinesis, AWSSinkTypeSQS:
+		if c.AWSRegion == "" {
+			return fmt.Errorf("AWS_REGION is required when AWS_SINK_TYPE is set")
+		}
+		if c.AWSSinkTarget == "" && len(c.AWSSinkRoutes) == 0 {
+			return fmt.Errorf("AWS_SINK_TARGET or AWS_SINK_ROUTES is required when AWS_SINK_TYPE is set")
+		}
+	default:
+		return fmt.Errorf("AWS_SINK_TYPE must be 'kinesis' or 'sqs'")
+	}
+	return nil
+}
+
+func (c *Config) validateSinks() error {
+	for _, name := range c.Sinks {
+		switch name {
+		case string(DatabaseTypeMongo):
+			if c.DatabaseType != DatabaseTypeMongo && c.SinkMongoURL == "" {
+				return fmt.Errorf("SINKS: mongodb requires SINK_MONGO_URL unless DATABASE_TYPE=mongodb")
+			}
+		case string(DatabaseTypePostgres):
+			if c.DatabaseType != DatabaseTypePostgres && c.SinkPostgresURL == "" {
+				return fmt.Errorf("SINKS: postgres requires SINK_POSTGRES_URL unless DATABASE_TYPE=postgres")
+			}
+		case "kafka":
+			if c.KafkaRESTURL == "" || c.KafkaTopic == "" {
+				return fmt.Errorf("SINKS: kafka requires KAFKA_REST_URL and KAFKA_TOPIC")
+			}
+		case "webhook":
+			if c.SinkWebhookURL == "" {
+				return fmt.Errorf("SINKS: webhook requires SINK_WEBHOOK_URL")
+			}
+		case "stdout":
+		default:
+			return fmt.Errorf("SINKS: unknown sink %q, want one of %s", name, strings.Join(SinkNames, ", "))
+		}
+	}
+	if len(c.Sinks) > 0 && c.SinkBatchSize <= 0 {
+		return fmt.Errorf("SINK_BATCH_SIZE must be positive")
+	}
+	if c.OutboxEnabled {
+		if c.DatabaseType != DatabaseTypeMongo {
+			return fmt.Errorf("OUTBOX_ENABLED requires DATABASE_TYPE=mongodb")
+		}
+		if len(c.OutboxSinks()) == 0 {
+			return fmt.Errorf("OUTBOX_ENABLED requires kafka or webhook in SINKS")
+		}
+		if c.OutboxInterval <= 0 || c.OutboxBatchSize <= 0 {
+			return fmt.Errorf("OUTBOX_POLL_INTERVAL_MS and OUTBOX_BATCH_SIZE must be positive")
+		}
+	}
 	return nil
 }
 
+// OutboxSinks returns the message bus sinks that are delivered through the
+// outbox, or nil when it is disabled.
+func (c *Config) OutboxSinks() []string {
+	if !c.OutboxEnabled {
+		return nil
+	}
+	var names []string
+	for _, name := range c.Sinks {
+		if name == "kafka" || name == "webhook" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// CounterDeployment is one deployment of the counter program. Its label
+// is stored with its events so they can be told apart.
+type CounterDeployment struct {
+	Label     string
+	ProgramID string
+}
+
+// CounterDeployments returns the COUNTER_DEPLOYMENTS sorted by label or,
+// without them, COUNTER_PROGRAM_ID as a single unlabeled deployment.
+func (c *Config) CounterDeployments() []CounterDeployment {
+	if len(c.CounterDeploymentPrograms) == 0 {
+		return []CounterDeployment{{ProgramID: c.CounterProgramID}}
+	}
+	deployments := make([]CounterDeployment, 0, len(c.CounterDeploymentPrograms))
+	for label, program := range c.CounterDeploymentPrograms {
+		deployments = append(deployments, CounterDeployment{Label: label, ProgramID: program})
+	}
+	sort.Slice(deployments, func(a, b int) bool { return deployments[a].Label < deployments[b].Label })
+	return deployments
+}
+
+// RPCEndpoint is an RPC endpoint and its request budget; zero limits are
+// unlimited.
+type RPCEndpoint struct {
+	Name      string
+	URL       string
+	PerSecond float64
+	PerDay    int64
+}
+
+// RPCEndpoints returns SOLANA_RPC_URL, named primary, followed by
+// RPC_FALLBACK_URLS, named fallback1, fallback2 and so on, with the
+// budgets RPC_BUDGETS gives them, e.g.
+// "primary=10/s+100000/d,fallback1=25/s".
+func (c *Config) RPCEndpoints() ([]RPCEndpoint, error) {
+	endpoints := []RPCEndpoint{{Name: "primary", URL: c.SolanaRPCURL}}
+	for n, url := range c.RPCFallbackURLs {
+		endpoints = append(endpoints, RPCEndpoint{Name: fmt.Sprintf("fallback%d", n+1), URL: url})
+	}
+
+	for name, budget := range c.RPCBudgets {
+		i := slices.IndexFunc(endpoints, func(e RPCEndpoint) bool { return e.Name == name })
+		if i < 0 {
+			return nil, fmt.Errorf("RPC_BUDGETS: unknown endpoint %q", name)
+		}
+		for _, limit := range strings.Split(budget, "+") {
+			value, unit, _ := strings.Cut(strings.TrimSpace(limit), "/")
+			var err error
+			switch unit {
+			case "s":
+				endpoints[i].PerSecond, err = strconv.ParseFloat(value, 64)
+				if err == nil && endpoints[i].PerSecond <= 0 {
+					err = fmt.Errorf("must be positive")
+				}
+			case "d":
+				endpoints[i].PerDay, err = strconv.ParseInt(value, 10, 64)
+				if err == nil && endpoints[i].PerDay <= 0 {
+					err = fmt.Errorf("must be positive")
+				}
+			default:
+				err = fmt.Errorf("want <n>/s or <n>/d")
+			}
+			if err != nil {
+				return nil, fmt.Errorf("RPC_BUDGETS: %s budget %q: %w", name, limit, err)
+			}
+		}
+	}
+	return endpoints, nil
+}
+
+// RPCWeights returns the scheduling weight of the live, repair and
+// backfill priorities, defaulting to 8, 3 and 1, overridden by
+// RPC_PRIORITY_WEIGHTS, e.g. "live=8,repair=3,backfill=1".
+func (c *Config) RPCWeights() (map[string]int, error) {
+	weights := map[string]int{"live": 8, "repair": 3, "backfill": 1}
+	for name, value := range c.RPCPriorityWeights {
+		if _, ok := weights[name]; !ok {
+			return nil, fmt.Errorf("RPC_PRIORITY_WEIGHTS: unknown priority %q, want live, repair or backfill", name)
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || weight <= 0 {
+			return nil, fmt.Errorf("RPC_PRIORITY_WEIGHTS: %s weight %q must be a positive integer", name, value)
+		}
+		weights[name] = weight
+	}
+	return weights, nil
+}
+
+// DedupFieldsByType splits the fields of DEDUP_FIELDS, e.g.
+// "TokensMintedEvent=mint+recipient+amount".
+func (c *Config) DedupFieldsByType() map[models.EventType][]string {
+	return fieldsByType(c.DedupFields)
+}
+
+// EncryptedFieldsByType splits the fields of ENCRYPTED_FIELDS, e.g.
+// "NftMintedEvent=name+uri".
+func (c *Config) EncryptedFieldsByType() map[models.EventType][]string {
+	return fieldsByType(c.EncryptedFields)
+}
+
+// FieldCipher returns the cipher of the ENCRYPTED_FIELDS, nil when there
+// are none.
+func (c *Config) FieldCipher() (*fieldcrypt.Cipher, error) {
+	if len(c.EncryptedFields) == 0 {
+		return nil, nil
+	}
+	key, err := fieldcrypt.ParseKey(c.FieldEncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("FIELD_ENCRYPTION_KEY: %w", err)
+	}
+	cipher, err := fieldcrypt.New(key, c.EncryptedFieldsByType())
+	if err != nil {
+		return nil, fmt.Errorf("ENCRYPTED_FIELDS: %w", err)
+	}
+	return cipher, nil
+}
+
+func fieldsByType(m map[string]string) map[models.EventType][]string {
+	if len(m) == 0 {
+		return nil
+	}
+	fields := make(map[models.EventType][]string, len(m))
+	for eventType, names := range m {
+		for _, name := range strings.Split(names, "+") {
+			if name = strings.TrimSpace(name); name != "" {
+				fields[models.EventType(eventType)] = append(fields[models.EventType(eventType)], name)
+			}
+		}
+	}
+	return fields
+}
+
+// envPrefix is the prefix of the variables of the network load reads, whose
+// values win over the unprefixed ones. fileEnvMu guards it.
+var envPrefix string
+
+// NetworkEnvPrefix returns the prefix of the variables of network, e.g.
+// "MAINNET_BETA_" for "mainnet-beta", or "" for no network.
+func NetworkEnvPrefix(network string) string {
+	if network == "" {
+		return ""
+	}
+	return strings.ToUpper(strings.ReplaceAll(network, "-", "_")) + "_"
+}
+
+// getEnv returns the variable key of the network being loaded, or else key.
+func getEnv(key string) string {
+	if envPrefix != "" {
+		if value := os.Getenv(envPrefix + key); value != "" {
+			return value
+		}
+	}
+	return os.Getenv(key)
+}
+
 func getEnvOrDefault(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
+	if value := getEnv(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
 
 func getEnvIntOrDefault(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
+	if value := getEnv(key); value != "" {
 		var intVal int
 		if _, err := fmt.Sscanf(value, "%d", &intVal); err == nil {
 			return intVal
@@ -105,3 +982,112 @@ func getEnvIntOrDefault(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvFloatOrDefault(key string, defaultValue float64) float64 {
+	if value := getEnv(key); value != "" {
+		if floatVal, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatVal
+		}
+	}
+	return defaultValue
+}
+
+func getEnvBoolOrDefault(key string, defaultValue bool) bool {
+	if value := getEnv(key); value != "" {
+		if boolVal, err := strconv.ParseBool(value); err == nil {
+			return boolVal
+		}
+	}
+	return defaultValue
+}
+
+var tenantName = regexp.MustCompile(`^[a-z0-9_-]{1,64}$`)
+
+// ValidTenantName reports whether name can be used as a tenant, which keeps
+// tenant names safe to embed in cache keys and log lines.
+func ValidTenantName(name string) bool {
+	return tenantName.MatchString(name)
+}
+
+const (
+	StartFromGenesis   = "genesis"
+	StartFromLatest    = "latest"
+	StartFromSlot      = "slot"
+	StartFromSignature = "signature"
+)
+
+// RetentionMode is what retention does with expired events.
+type RetentionMode string
+
+const (
+	RetentionDelete  RetentionMode = "delete"
+	RetentionArchive RetentionMode = "archive"
+)
+
+// ParseRetentionMode parses RETENTION_MODE: delete, the default, or
+// archive.
+func ParseRetentionMode(s string) (RetentionMode, error) {
+	switch mode := RetentionMode(strings.ToLower(strings.TrimSpace(s))); mode {
+	case "":
+		return RetentionDelete, nil
+	case RetentionDelete, RetentionArchive:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown retention mode %q", s)
+	}
+}
+
+var signaturePattern = regexp.MustCompile(`^[1-9A-HJ-NP-Za-km-z]{64,88}$`)
+
+// StartStrategy returns StartFrom, or when it is empty "signature" if
+// StartSignature is set, "slot" if StartSlot is and "genesis" otherwise.
+func (c *Config) StartStrategy() string {
+	switch {
+	case c.StartFrom != "":
+		return c.StartFrom
+	case c.StartSignature != "":
+		return StartFromSignature
+	case c.StartSlot > 0:
+		return StartFromSlot
+	default:
+		return StartFromGenesis
+	}
+}
+
+// getEnvMapOrDefault parses a comma separated list of key=value pairs,
+// e.g. "CounterIncrementedEvent=counter-stream,TokensMintedEvent=token-stream".
+func getEnvMapOrDefault(key string) map[string]string {
+	value := getEnv(key)
+	if value == "" {
+		return nil
+	}
+
+	result := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if k != "" && v != "" {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// getEnvListOrDefault parses a comma separated list, skipping empty items.
+func getEnvListOrDefault(key string) []string {
+	value := getEnv(key)
+	if value == "" {
+		return nil
+	}
+
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
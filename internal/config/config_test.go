@@ -1,8 +1,11 @@
 package config
 
 import (
+	"reflect"
+	"strings"
 	"testing"
-	"time"
+
+	pkgdecoder "github.com/lugondev/go-indexer-solana-starter/pkg/decoder"
 )
 
 func TestLoad(t *testing.T) {
@@ -28,71 +31,297 @@ func TestLoad(t *testing.T) {
 	}
 }
 
+func TestLoadNetwork(t *testing.T) {
+	t.Setenv("SOLANA_RPC_URL", "https://api.devnet.solana.com")
+	t.Setenv("MAINNET_BETA_SOLANA_RPC_URL", "https://api.mainnet-beta.solana.com")
+	t.Setenv("BATCH_SIZE", "20")
+	t.Setenv("NETWORKS", "devnet,mainnet-beta")
+
+	tests := []struct {
+		network string
+		wantRPC string
+	}{
+		{"devnet", "https://api.devnet.solana.com"},
+		{"mainnet-beta", "https://api.mainnet-beta.solana.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.network, func(t *testing.T) {
+			cfg, err := LoadNetwork(tt.network)
+			if err != nil {
+				t.Fatalf("LoadNetwork() error = %v", err)
+			}
+			if cfg.Network != tt.network {
+				t.Errorf("Network = %v, want %v", cfg.Network, tt.network)
+			}
+			if cfg.SolanaRPCURL != tt.wantRPC {
+				t.Errorf("SolanaRPCURL = %v, want %v", cfg.SolanaRPCURL, tt.wantRPC)
+			}
+			if cfg.BatchSize != 20 {
+				t.Errorf("BatchSize = %v, want %v", cfg.BatchSize, 20)
+			}
+		})
+	}
+
+	t.Setenv("NETWORKS", "devnet,devnet")
+	if _, err := LoadNetwork("devnet"); err == nil {
+		t.Errorf("LoadNetwork() with a network listed twice error = nil, want an error")
+	}
+}
+
+// validConfig returns the default configuration, which is valid.
+func validConfig(t *testing.T) *Config {
+	t.Helper()
+	t.Setenv("SOLANA_RPC_URL", "https://api.mainnet-beta.solana.com")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	return cfg
+}
+
 func TestConfig_Validate(t *testing.T) {
+	const key = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
 	tests := []struct {
 		name    string
-		cfg     *Config
-		wantErr bool
+		modify  func(c *Config)
+		wantErr string
 	}{
+		{name: "valid config", modify: func(c *Config) {}},
+		{name: "empty RPC URL", modify: func(c *Config) { c.SolanaRPCURL = "" }, wantErr: "SOLANA_RPC_URL is required"},
+		{name: "empty starter program", modify: func(c *Config) { c.StarterProgramID = "" }, wantErr: "STARTER_PROGRAM_ID is required"},
+		{name: "invalid batch size", modify: func(c *Config) { c.BatchSize = 0 }, wantErr: "BATCH_SIZE must be positive"},
+		{name: "invalid concurrency", modify: func(c *Config) { c.MaxConcurrency = -1 }, wantErr: "MAX_CONCURRENCY must be positive"},
+		{name: "invalid port", modify: func(c *Config) { c.ServerPort = 70000 }, wantErr: "SERVER_PORT"},
+		{name: "unknown raw data compression", modify: func(c *Config) { c.RawDataCompression = "lz4" }, wantErr: "RAW_DATA_COMPRESSION"},
+		{name: "program data mode in another case", modify: func(c *Config) { c.ProgramDataMode = " Strict" }},
+		{name: "unknown program data mode", modify: func(c *Config) { c.ProgramDataMode = "loose" }, wantErr: "PROGRAM_DATA_MODE"},
+		{name: "mongo layout in another case", modify: func(c *Config) { c.MongoCollectionLayout = "Per_Type " }},
+		{name: "unknown mongo layout", modify: func(c *Config) { c.MongoCollectionLayout = "per-type" }, wantErr: "MONGO_COLLECTION_LAYOUT"},
+		{name: "archive retention", modify: func(c *Config) { c.RetentionMode = " Archive" }},
+		{name: "unknown retention mode", modify: func(c *Config) { c.RetentionMode = "achive" }, wantErr: "RETENTION_MODE"},
 		{
-			name: "valid config",
-			cfg: &Config{
-				SolanaRPCURL:   "https://api.mainnet-beta.solana.com",
-				StartSlot:      0,
-				PollInterval:   time.Second,
-				BatchSize:      10,
-				MaxConcurrency: 5,
-				ServerPort:     8080,
+			name: "encrypted fields",
+			modify: func(c *Config) {
+				c.EncryptedFields = map[string]string{"NftMintedEvent": "name+uri"}
+				c.FieldEncryptionKey = key
 			},
-			wantErr: false,
 		},
 		{
-			name: "empty RPC URL",
-			cfg: &Config{
-				SolanaRPCURL:   "",
-				BatchSize:      10,
-				MaxConcurrency: 5,
-				ServerPort:     8080,
+			name:    "encrypted fields without a key",
+			modify:  func(c *Config) { c.EncryptedFields = map[string]string{"NftMintedEvent": "name"} },
+			wantErr: "FIELD_ENCRYPTION_KEY",
+		},
+		{
+			name: "encryption key too short",
+			modify: func(c *Config) {
+				c.EncryptedFields = map[string]string{"NftMintedEvent": "name"}
+				c.FieldEncryptionKey = "MDEyMzQ1Njc4OQ=="
 			},
-			wantErr: true,
+			wantErr: "FIELD_ENCRYPTION_KEY",
 		},
 		{
-			name: "invalid batch size",
-			cfg: &Config{
-				SolanaRPCURL:   "https://api.mainnet-beta.solana.com",
-				BatchSize:      0,
-				MaxConcurrency: 5,
-				ServerPort:     8080,
+			name: "encrypted non-string field",
+			modify: func(c *Config) {
+				c.EncryptedFields = map[string]string{"NftMintedEvent": "owner"}
+				c.FieldEncryptionKey = key
 			},
-			wantErr: true,
+			wantErr: `ENCRYPTED_FIELDS: NftMintedEvent has no string field "owner"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			tt.modify(cfg)
+			err := cfg.Validate()
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("Validate() error = %v, want nil", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseRetentionMode(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    RetentionMode
+		wantErr bool
+	}{
+		{in: "", want: RetentionDelete},
+		{in: "delete", want: RetentionDelete},
+		{in: "ARCHIVE ", want: RetentionArchive},
+		{in: "achive", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseRetentionMode(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseRetentionMode(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseRetentionMode(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestConfig_ValidateDecoderPlugins(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.DecoderPlugins = []string{"vault.so"}
+	err := cfg.Validate()
+	if pkgdecoder.PluginsSupported && err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+	if !pkgdecoder.PluginsSupported && (err == nil || !strings.Contains(err.Error(), "DECODER_PLUGINS")) {
+		t.Errorf("Validate() error = %v, want DECODER_PLUGINS rejected without cgo", err)
+	}
+}
+
+func TestLoad_StartStrategy(t *testing.T) {
+	const signature = "5VERv8NMvzbJMEkV8xnrLkEaWRtSz9CosKDYjCJjBRnbJLgp8uirBgmQpjKhoR4tjF3ZpRzrFmBV6UjKdiSZkQUW"
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    string
+		wantErr bool
+	}{
+		{name: "default", env: map[string]string{}, want: StartFromGenesis},
+		{name: "start slot", env: map[string]string{"START_SLOT": "100"}, want: StartFromSlot},
+		{name: "start signature", env: map[string]string{"START_SLOT": "100", "START_SIGNATURE": signature}, want: StartFromSignature},
+		{name: "explicit latest", env: map[string]string{"START_FROM": "latest", "START_SLOT": "100"}, want: StartFromLatest},
+		{name: "slot without start slot", env: map[string]string{"START_FROM": "slot"}, wantErr: true},
+		{name: "bad signature", env: map[string]string{"START_FROM": "signature", "START_SIGNATURE": "abc"}, wantErr: true},
+		{name: "unknown", env: map[string]string{"START_FROM": "now"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"START_FROM", "START_SLOT", "START_SIGNATURE"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			cfg, err := Load()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && cfg.StartStrategy() != tt.want {
+				t.Errorf("StartStrategy() = %v, want %v", cfg.StartStrategy(), tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_CounterDeployments(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *Config
+		want []CounterDeployment
+	}{
+		{
+			name: "single program",
+			cfg:  &Config{CounterProgramID: "CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc"},
+			want: []CounterDeployment{{ProgramID: "CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc"}},
 		},
 		{
-			name: "invalid concurrency",
+			name: "labeled deployments",
 			cfg: &Config{
-				SolanaRPCURL:   "https://api.mainnet-beta.solana.com",
-				BatchSize:      10,
-				MaxConcurrency: -1,
-				ServerPort:     8080,
+				CounterProgramID: "CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc",
+				CounterDeploymentPrograms: map[string]string{
+					"mainnet": "gARh1g6reuvsAHB7DXqiuYzzyiJeoiJmtmCpV8Y5uWC",
+					"devnet":  "CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc",
+				},
+			},
+			want: []CounterDeployment{
+				{Label: "devnet", ProgramID: "CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc"},
+				{Label: "mainnet", ProgramID: "gARh1g6reuvsAHB7DXqiuYzzyiJeoiJmtmCpV8Y5uWC"},
 			},
-			wantErr: true,
 		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.CounterDeployments(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CounterDeployments() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_RPCEndpoints(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *Config
+		want    []RPCEndpoint
+		wantErr bool
+	}{
 		{
-			name: "invalid port",
+			name: "primary only",
+			cfg:  &Config{SolanaRPCURL: "https://a.test"},
+			want: []RPCEndpoint{{Name: "primary", URL: "https://a.test"}},
+		},
+		{
+			name: "fallbacks with budgets",
 			cfg: &Config{
-				SolanaRPCURL:   "https://api.mainnet-beta.solana.com",
-				BatchSize:      10,
-				MaxConcurrency: 5,
-				ServerPort:     70000,
+				SolanaRPCURL:    "https://a.test",
+				RPCFallbackURLs: []string{"https://b.test", "https://c.test"},
+				RPCBudgets:      map[string]string{"primary": "10/s+100000/d", "fallback2": "2.5/s"},
+			},
+			want: []RPCEndpoint{
+				{Name: "primary", URL: "https://a.test", PerSecond: 10, PerDay: 100000},
+				{Name: "fallback1", URL: "https://b.test"},
+				{Name: "fallback2", URL: "https://c.test", PerSecond: 2.5},
 			},
+		},
+		{
+			name:    "unknown endpoint",
+			cfg:     &Config{SolanaRPCURL: "https://a.test", RPCBudgets: map[string]string{"fallback1": "10/s"}},
+			wantErr: true,
+		},
+		{
+			name:    "bad unit",
+			cfg:     &Config{SolanaRPCURL: "https://a.test", RPCBudgets: map[string]string{"primary": "10/m"}},
+			wantErr: true,
+		},
+		{
+			name:    "zero limit",
+			cfg:     &Config{SolanaRPCURL: "https://a.test", RPCBudgets: map[string]string{"primary": "0/d"}},
 			wantErr: true,
 		},
 	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.cfg.RPCEndpoints()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RPCEndpoints() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RPCEndpoints() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
 
+func TestConfig_RPCWeights(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights map[string]string
+		want    map[string]int
+		wantErr bool
+	}{
+		{"defaults", nil, map[string]int{"live": 8, "repair": 3, "backfill": 1}, false},
+		{"override", map[string]string{"backfill": "2"}, map[string]int{"live": 8, "repair": 3, "backfill": 2}, false},
+		{"unknown priority", map[string]string{"urgent": "10"}, nil, true},
+		{"zero weight", map[string]string{"live": "0"}, nil, true},
+	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := tt.cfg.Validate()
+			got, err := (&Config{RPCPriorityWeights: tt.weights}).RPCWeights()
 			if (err != nil) != tt.wantErr {
-				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+				t.Fatalf("RPCWeights() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RPCWeights() = %v, want %v", got, tt.want)
 			}
 		})
 	}
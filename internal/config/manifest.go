@@ -0,0 +1,276 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// ManifestVersion is the manifest format written by ExportManifest.
+const ManifestVersion = 1
+
+// Manifest is the deployment specific configuration as a single versioned
+// document, for checking into infrastructure repositories. Every field
+// mirrors the environment variable in its env tag and uses the same units;
+// zero values mean the variable is left to its default. Connection strings
+// and secrets are deliberately absent and stay in the environment.
+type Manifest struct {
+	Version   int               `json:"version" yaml:"version"`
+	Programs  ManifestPrograms  `json:"programs" yaml:"programs"`
+	Filters   ManifestFilters   `json:"filters" yaml:"filters"`
+	Sinks     ManifestSinks     `json:"sinks" yaml:"sinks"`
+	Webhooks  ManifestWebhooks  `json:"webhooks" yaml:"webhooks"`
+	Retention ManifestRetention `json:"retention" yaml:"retention"`
+}
+
+type ManifestPrograms struct {
+	Networks            []string `json:"networks,omitempty" yaml:"networks,omitempty" env:"NETWORKS"`
+	StarterProgramID    string   `json:"starter_program_id,omitempty" yaml:"starter_program_id,omitempty" env:"STARTER_PROGRAM_ID"`
+	CounterProgramID    string   `json:"counter_program_id,omitempty" yaml:"counter_program_id,omitempty" env:"COUNTER_PROGRAM_ID"`
+	CounterProgramIDL   string   `json:"counter_program_idl,omitempty" yaml:"counter_program_idl,omitempty" env:"COUNTER_PROGRAM_IDL"`
+	StartFrom           string   `json:"start_from,omitempty" yaml:"start_from,omitempty" env:"START_FROM"`
+	StartSlot           int      `json:"start_slot,omitempty" yaml:"start_slot,omitempty" env:"START_SLOT"`
+	StartSignature      string   `json:"start_signature,omitempty" yaml:"start_signature,omitempty" env:"START_SIGNATURE"`
+	ProgramDataMode     string   `json:"program_data_mode,omitempty" yaml:"program_data_mode,omitempty" env:"PROGRAM_DATA_MODE"`
+	ConfigMirrorEnabled *bool    `json:"config_mirror_enabled,omitempty" yaml:"config_mirror_enabled,omitempty" env:"CONFIG_MIRROR_ENABLED"`
+	// Tenants maps program IDs to the tenant owning them.
+	Tenants map[string]string `json:"tenants,omitempty" yaml:"tenants,omitempty" env:"PROGRAM_TENANTS"`
+	// CounterDeployments maps deployment labels to counter program IDs.
+	CounterDeployments map[string]string `json:"counter_deployments,omitempty" yaml:"counter_deployments,omitempty" env:"COUNTER_DEPLOYMENTS"`
+	LogGrammarFile     string            `json:"log_grammar_file,omitempty" yaml:"log_grammar_file,omitempty" env:"LOG_GRAMMAR_FILE"`
+	DecoderPlugins     []string          `json:"decoder_plugins,omitempty" yaml:"decoder_plugins,omitempty" env:"DECODER_PLUGINS"`
+	IDLPrograms        []string          `json:"idl_programs,omitempty" yaml:"idl_programs,omitempty" env:"IDL_PROGRAMS"`
+	SplTokenMints      []string          `json:"spl_token_mints,omitempty" yaml:"spl_token_mints,omitempty" env:"SPL_TOKEN_MINTS"`
+	SolWatchlist       []string          `json:"sol_watchlist,omitempty" yaml:"sol_watchlist,omitempty" env:"SOL_WATCHLIST"`
+}
+
+type ManifestFilters struct {
+	EventAllowlist []string `json:"event_allowlist,omitempty" yaml:"event_allowlist,omitempty" env:"EVENT_ALLOWLIST"`
+	EventDenylist  []string `json:"event_denylist,omitempty" yaml:"event_denylist,omitempty" env:"EVENT_DENYLIST"`
+	Accounts       []string `json:"accounts,omitempty" yaml:"accounts,omitempty" env:"EVENT_ACCOUNT_FILTER"`
+	// DedupFields maps event types to "+" separated field names.
+	DedupFields        map[string]string `json:"dedup_fields,omitempty" yaml:"dedup_fields,omitempty" env:"DEDUP_FIELDS"`
+	DedupWindowSeconds int               `json:"dedup_window_seconds,omitempty" yaml:"dedup_window_seconds,omitempty" env:"DEDUP_WINDOW_SECONDS"`
+}
+
+type ManifestSinks struct {
+	AWSSinkType         string            `json:"aws_sink_type,omitempty" yaml:"aws_sink_type,omitempty" env:"AWS_SINK_TYPE"`
+	AWSRegion           string            `json:"aws_region,omitempty" yaml:"aws_region,omitempty" env:"AWS_REGION"`
+	AWSSinkTarget       string            `json:"aws_sink_target,omitempty" yaml:"aws_sink_target,omitempty" env:"AWS_SINK_TARGET"`
+	AWSSinkRoutes       map[string]string `json:"aws_sink_routes,omitempty" yaml:"aws_sink_routes,omitempty" env:"AWS_SINK_ROUTES"`
+	AWSSinkBatchSize    int               `json:"aws_sink_batch_size,omitempty" yaml:"aws_sink_batch_size,omitempty" env:"AWS_SINK_BATCH_SIZE"`
+	AWSSinkFlushMS      int               `json:"aws_sink_flush_interval_ms,omitempty" yaml:"aws_sink_flush_interval_ms,omitempty" env:"AWS_SINK_FLUSH_INTERVAL_MS"`
+	RedisChannelPrefix  string            `json:"redis_channel_prefix,omitempty" yaml:"redis_channel_prefix,omitempty" env:"REDIS_CHANNEL_PREFIX"`
+	LagIntervalSeconds  *int              `json:"lag_interval_seconds,omitempty" yaml:"lag_interval_seconds,omitempty" env:"SINK_LAG_INTERVAL_SECONDS"`
+	StreamWindows       map[string]string `json:"stream_windows,omitempty" yaml:"stream_windows,omitempty" env:"STREAM_WINDOWS"`
+	StreamWindowHistory int               `json:"stream_window_history,omitempty" yaml:"stream_window_history,omitempty" env:"STREAM_WINDOW_HISTORY"`
+	Outputs             []string          `json:"outputs,omitempty" yaml:"outputs,omitempty" env:"SINKS"`
+	BatchSize           int               `json:"batch_size,omitempty" yaml:"batch_size,omitempty" env:"SINK_BATCH_SIZE"`
+	FlushMS             int               `json:"flush_interval_ms,omitempty" yaml:"flush_interval_ms,omitempty" env:"SINK_FLUSH_INTERVAL_MS"`
+	WebhookURL          string            `json:"webhook_url,omitempty" yaml:"webhook_url,omitempty" env:"SINK_WEBHOOK_URL"`
+	KafkaRESTURL        string            `json:"kafka_rest_url,omitempty" yaml:"kafka_rest_url,omitempty" env:"KAFKA_REST_URL"`
+	KafkaTopic          string            `json:"kafka_topic,omitempty" yaml:"kafka_topic,omitempty" env:"KAFKA_TOPIC"`
+	OutboxEnabled       *bool             `json:"outbox_enabled,omitempty" yaml:"outbox_enabled,omitempty" env:"OUTBOX_ENABLED"`
+	OutboxIntervalMS    int               `json:"outbox_poll_interval_ms,omitempty" yaml:"outbox_poll_interval_ms,omitempty" env:"OUTBOX_POLL_INTERVAL_MS"`
+	OutboxBatchSize     int               `json:"outbox_batch_size,omitempty" yaml:"outbox_batch_size,omitempty" env:"OUTBOX_BATCH_SIZE"`
+}
+
+// ManifestWebhooks are the downstream cache purge endpoints.
+type ManifestWebhooks struct {
+	MintURL       string `json:"mint_url,omitempty" yaml:"mint_url,omitempty" env:"CACHE_INVALIDATION_MINT_URL"`
+	CollectionURL string `json:"collection_url,omitempty" yaml:"collection_url,omitempty" env:"CACHE_INVALIDATION_COLLECTION_URL"`
+	WalletURL     string `json:"wallet_url,omitempty" yaml:"wallet_url,omitempty" env:"CACHE_INVALIDATION_WALLET_URL"`
+	Method        string `json:"method,omitempty" yaml:"method,omitempty" env:"CACHE_INVALIDATION_METHOD"`
+	WatchlistURL  string `json:"watchlist_url,omitempty" yaml:"watchlist_url,omitempty" env:"WATCHLIST_WEBHOOK_URL"`
+	NotifyRules   string `json:"notify_rules_file,omitempty" yaml:"notify_rules_file,omitempty" env:"NOTIFY_RULES_FILE"`
+}
+
+type ManifestRetention struct {
+	Days                   int               `json:"days,omitempty" yaml:"days,omitempty" env:"RETENTION_DAYS"`
+	Overrides              map[string]string `json:"overrides,omitempty" yaml:"overrides,omitempty" env:"RETENTION_OVERRIDES"`
+	Mode                   string            `json:"mode,omitempty" yaml:"mode,omitempty" env:"RETENTION_MODE"`
+	IntervalMinutes        int               `json:"interval_minutes,omitempty" yaml:"interval_minutes,omitempty" env:"RETENTION_INTERVAL_MINUTES"`
+	ColdExportProvider     string            `json:"cold_export_provider,omitempty" yaml:"cold_export_provider,omitempty" env:"COLD_EXPORT_PROVIDER"`
+	ColdExportBucket       string            `json:"cold_export_bucket,omitempty" yaml:"cold_export_bucket,omitempty" env:"COLD_EXPORT_BUCKET"`
+	ColdExportPrefix       string            `json:"cold_export_prefix,omitempty" yaml:"cold_export_prefix,omitempty" env:"COLD_EXPORT_PREFIX"`
+	ColdExportEndpoint     string            `json:"cold_export_endpoint,omitempty" yaml:"cold_export_endpoint,omitempty" env:"COLD_EXPORT_ENDPOINT"`
+	ColdExportAfterDays    int               `json:"cold_export_after_days,omitempty" yaml:"cold_export_after_days,omitempty" env:"COLD_EXPORT_AFTER_DAYS"`
+	ColdExportIntervalMins int               `json:"cold_export_interval_minutes,omitempty" yaml:"cold_export_interval_minutes,omitempty" env:"COLD_EXPORT_INTERVAL_MINUTES"`
+	ColdExportBatchSize    int               `json:"cold_export_batch_size,omitempty" yaml:"cold_export_batch_size,omitempty" env:"COLD_EXPORT_BATCH_SIZE"`
+}
+
+// ExportManifest builds a manifest from the variables set in the
+// environment and .env file. Unset variables are left out, so defaults stay
+// defaults when the manifest is applied elsewhere.
+func ExportManifest() (*Manifest, error) {
+	_ = godotenv.Load()
+
+	m := &Manifest{Version: ManifestVersion}
+	var err error
+	eachManifestField(m, func(env string, field reflect.Value) {
+		if err != nil {
+			return
+		}
+		value := os.Getenv(env)
+		if value == "" {
+			return
+		}
+		if setErr := setManifestField(env, field, value); setErr != nil {
+			err = setErr
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ReadManifest decodes a manifest, rejecting unknown fields and versions.
+func ReadManifest(r io.Reader) (*Manifest, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	var m Manifest
+	if err := dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("decode manifest: %w", err)
+	}
+	if m.Version != ManifestVersion {
+		return nil, fmt.Errorf("unsupported manifest version %d, want %d", m.Version, ManifestVersion)
+	}
+	return &m, nil
+}
+
+// ReadManifestYAML decodes a manifest written in YAML, rejecting unknown
+// fields and versions. Fields take the same names as in JSON.
+func ReadManifestYAML(r io.Reader) (*Manifest, error) {
+	var m Manifest
+	if err := DecodeYAML(r, &m); err != nil {
+		return nil, fmt.Errorf("decode manifest: %w", err)
+	}
+	if m.Version != ManifestVersion {
+		return nil, fmt.Errorf("unsupported manifest version %d, want %d", m.Version, ManifestVersion)
+	}
+	return &m, nil
+}
+
+// ReadManifestFile reads a JSON manifest, or a YAML one if the file name
+// ends in .yaml or .yml.
+func ReadManifestFile(path string) (*Manifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open manifest: %w", err)
+	}
+	defer f.Close()
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return ReadManifestYAML(f)
+	}
+	return ReadManifest(f)
+}
+
+// Env returns the environment variables the manifest sets, in the formats
+// config.Load parses.
+func (m *Manifest) Env() map[string]string {
+	env := make(map[string]string)
+	eachManifestField(m, func(name string, field reflect.Value) {
+		if value, ok := formatManifestField(field); ok {
+			env[name] = value
+		}
+	})
+	return env
+}
+
+// applyManifest sets the manifest's variables that are not already set, so
+// the environment and .env file take precedence over the manifest.
+func applyManifest(path string) error {
+	m, err := ReadManifestFile(path)
+	if err != nil {
+		return err
+	}
+	return setFileEnv(m.Env())
+}
+
+func eachManifestField(m *Manifest, fn func(env string, field reflect.Value)) {
+	sections := reflect.ValueOf(m).Elem()
+	for i := 0; i < sections.NumField(); i++ {
+		section := sections.Field(i)
+		if section.Kind() != reflect.Struct {
+			continue
+		}
+		for j := 0; j < section.NumField(); j++ {
+			if env := section.Type().Field(j).Tag.Get("env"); env != "" {
+				fn(env, section.Field(j))
+			}
+		}
+	}
+}
+
+func formatManifestField(field reflect.Value) (string, bool) {
+	switch field.Kind() {
+	case reflect.String:
+		return field.String(), field.String() != ""
+	case reflect.Int:
+		return strconv.FormatInt(field.Int(), 10), field.Int() != 0
+	case reflect.Pointer:
+		if field.IsNil() {
+			return "", false
+		}
+		value, _ := formatManifestField(field.Elem())
+		return value, true
+	case reflect.Bool:
+		return strconv.FormatBool(field.Bool()), true
+	case reflect.Slice:
+		items := field.Interface().([]string)
+		return strings.Join(items, ","), len(items) > 0
+	case reflect.Map:
+		pairs := field.Interface().(map[string]string)
+		keys := make([]string, 0, len(pairs))
+		for k := range pairs {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for i, k := range keys {
+			keys[i] = k + "=" + pairs[k]
+		}
+		return strings.Join(keys, ","), len(keys) > 0
+	}
+	return "", false
+}
+
+func setManifestField(env string, field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s must be an integer", env)
+		}
+		field.SetInt(int64(n))
+	case reflect.Pointer:
+		elem := reflect.New(field.Type().Elem())
+		if err := setManifestField(env, elem.Elem(), value); err != nil {
+			return err
+		}
+		field.Set(elem)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s must be a boolean", env)
+		}
+		field.SetBool(b)
+	case reflect.Slice:
+		field.Set(reflect.ValueOf(getEnvListOrDefault(env)))
+	case reflect.Map:
+		field.Set(reflect.ValueOf(getEnvMapOrDefault(env)))
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestManifest_RoundTrip(t *testing.T) {
+	env := map[string]string{
+		"STARTER_PROGRAM_ID":    "gARh1g6reuvsAHB7DXqiuYzzyiJeoiJmtmCpV8Y5uWC",
+		"CONFIG_MIRROR_ENABLED": "false",
+		"EVENT_ALLOWLIST":       "CounterIncrementedEvent,NftMintedEvent",
+		"RETENTION_OVERRIDES":   "CounterIncrementedEvent=7,NftMintedEvent=365",
+		"RETENTION_DAYS":        "90",
+	}
+	for name, value := range env {
+		t.Setenv(name, value)
+	}
+
+	m, err := ExportManifest()
+	if err != nil {
+		t.Fatalf("ExportManifest() error = %v", err)
+	}
+	if m.Programs.ConfigMirrorEnabled == nil || *m.Programs.ConfigMirrorEnabled {
+		t.Errorf("ConfigMirrorEnabled = %v, want false", m.Programs.ConfigMirrorEnabled)
+	}
+	if m.Retention.Days != 90 {
+		t.Errorf("Retention.Days = %v, want %v", m.Retention.Days, 90)
+	}
+	if got := m.Env(); !reflect.DeepEqual(got, env) {
+		t.Errorf("Env() = %v, want %v", got, env)
+	}
+}
+
+func TestReadManifest(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"valid", `{"version": 1, "retention": {"days": 30}}`, false},
+		{"unsupported version", `{"version": 2}`, true},
+		{"missing version", `{"retention": {"days": 30}}`, true},
+		{"unknown field", `{"version": 1, "retention": {"dayz": 30}}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadManifest(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ReadManifest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApplyManifest_EnvironmentWins(t *testing.T) {
+	path := t.TempDir() + "/manifest.json"
+	manifest := `{"version": 1, "retention": {"days": 30, "mode": "archive"}}`
+	if err := os.WriteFile(path, []byte(manifest), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("RETENTION_DAYS", "7")
+	t.Setenv("RETENTION_MODE", "")
+	os.Unsetenv("RETENTION_MODE")
+
+	if err := applyManifest(path); err != nil {
+		t.Fatalf("applyManifest() error = %v", err)
+	}
+	if got := os.Getenv("RETENTION_DAYS"); got != "7" {
+		t.Errorf("RETENTION_DAYS = %q, want %q", got, "7")
+	}
+	if got := os.Getenv("RETENTION_MODE"); got != "archive" {
+		t.Errorf("RETENTION_MODE = %q, want %q", got, "archive")
+	}
+}
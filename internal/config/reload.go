@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+)
+
+// fileEnv are the variables Load set from .env and CONFIG_MANIFEST rather
+// than found in the environment. Reload unsets them to read the files again.
+var (
+	fileEnvMu sync.Mutex
+	fileEnv   = make(map[string]bool)
+)
+
+// Load reads the configuration from the environment, .env and
+// CONFIG_MANIFEST, in that order of precedence, for the network of NETWORK.
+func Load() (*Config, error) {
+	return LoadNetwork("")
+}
+
+// LoadNetwork reads the configuration of network like Load, with the
+// variables prefixed by NetworkEnvPrefix(network) taking precedence, e.g.
+// DEVNET_SOLANA_RPC_URL over SOLANA_RPC_URL.
+func LoadNetwork(network string) (*Config, error) {
+	fileEnvMu.Lock()
+	defer fileEnvMu.Unlock()
+	return load(network)
+}
+
+// Reload loads the configuration of network again with .env and
+// CONFIG_MANIFEST as they are now. Variables of the process environment
+// still win.
+func Reload(network string) (*Config, error) {
+	fileEnvMu.Lock()
+	defer fileEnvMu.Unlock()
+	for name := range fileEnv {
+		os.Unsetenv(name)
+		delete(fileEnv, name)
+	}
+	return load(network)
+}
+
+// setFileEnv sets the variables read from a file that the environment does
+// not already set.
+func setFileEnv(values map[string]string) error {
+	for name, value := range values {
+		if _, ok := os.LookupEnv(name); ok {
+			continue
+		}
+		if err := os.Setenv(name, value); err != nil {
+			return fmt.Errorf("set %s: %w", name, err)
+		}
+		fileEnv[name] = true
+	}
+	return nil
+}
+
+// Changed returns the names of the fields whose values differ in other.
+func (c *Config) Changed(other *Config) []string {
+	a, b := reflect.ValueOf(c).Elem(), reflect.ValueOf(other).Elem()
+	var names []string
+	for n := 0; n < a.NumField(); n++ {
+		if !reflect.DeepEqual(a.Field(n).Interface(), b.Field(n).Interface()) {
+			names = append(names, a.Type().Field(n).Name)
+		}
+	}
+	return names
+}
+
+// Merge returns a copy of c with the named fields taken from other.
+func (c *Config) Merge(other *Config, fields []string) *Config {
+	merged := *c
+	for _, name := range fields {
+		reflect.ValueOf(&merged).Elem().FieldByName(name).Set(reflect.ValueOf(other).Elem().FieldByName(name))
+	}
+	return &merged
+}
+
+// ReloadResult reports how a reloaded configuration was applied.
+type ReloadResult struct {
+	// Applied are the components recreated from the new configuration.
+	Applied []string `json:"applied"`
+	// RestartRequired are the changed settings that only take effect on a
+	// restart.
+	RestartRequired []string `json:"restart_required"`
+}
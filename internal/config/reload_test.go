@@ -0,0 +1,49 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestReload(t *testing.T) {
+	path := t.TempDir() + "/manifest.yaml"
+	write := func(manifest string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(manifest), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("CONFIG_MANIFEST", path)
+	t.Setenv("EVENT_DENYLIST", "NftSoldEvent")
+	t.Cleanup(func() {
+		for name := range fileEnv {
+			os.Unsetenv(name)
+			delete(fileEnv, name)
+		}
+	})
+
+	write("version: 1\nfilters:\n  event_allowlist: [TokensMintedEvent]\n  event_denylist: [NftMintedEvent]\n")
+	before, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	write("version: 1\nfilters:\n  event_allowlist: [TokensBurnedEvent]\n  event_denylist: [NftMintedEvent]\n")
+	after, err := Reload("")
+	if err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	if want := []string{"TokensBurnedEvent"}; !reflect.DeepEqual(after.EventAllowlist, want) {
+		t.Errorf("EventAllowlist = %v, want %v", after.EventAllowlist, want)
+	}
+	if want := []string{"NftSoldEvent"}; !reflect.DeepEqual(after.EventDenylist, want) {
+		t.Errorf("EventDenylist = %v, want the environment's %v", after.EventDenylist, want)
+	}
+	if got, want := before.Changed(after), []string{"EventAllowlist"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Changed() = %v, want %v", got, want)
+	}
+	if merged := before.Merge(after, []string{"EventAllowlist"}); len(merged.Changed(after)) != 0 {
+		t.Errorf("Merge() left %v changed", merged.Changed(after))
+	}
+}
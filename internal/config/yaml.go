@@ -0,0 +1,21 @@
+package config
+
+import (
+	"errors"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DecodeYAML decodes a YAML document into v, rejecting fields v does not
+// have. Fields take the names of their yaml tags, which mirror the json
+// ones, and scalars decoded into strings keep their text, so "days: 7" and
+// "CounterIncrementedEvent: 7" both work. An empty document leaves v be.
+func DecodeYAML(r io.Reader, v interface{}) error {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
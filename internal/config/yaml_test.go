@@ -0,0 +1,81 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeYAML(t *testing.T) {
+	type rule struct {
+		Name  string            `yaml:"name"`
+		Tags  map[string]string `yaml:"tags"`
+		Count *int              `yaml:"count"`
+	}
+	one := 1
+	tests := []struct {
+		name    string
+		input   string
+		want    rule
+		wantErr bool
+	}{
+		{
+			name:  "scalars into strings",
+			input: "name: 7 # comment\ntags:\n  a: true\n  b: 0.5\ncount: 1\n",
+			want:  rule{Name: "7", Tags: map[string]string{"a": "true", "b": "0.5"}, Count: &one},
+		},
+		{name: "empty document", input: "# nothing\n"},
+		{name: "unknown field", input: "nmae: x\n", wantErr: true},
+		{name: "duplicate key", input: "name: a\nname: b\n", wantErr: true},
+		{name: "tab", input: "tags:\n\ta: b\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got rule
+			err := DecodeYAML(strings.NewReader(tt.input), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeYAML() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeYAML() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadManifestYAML(t *testing.T) {
+	input := `
+version: 1
+programs:
+  config_mirror_enabled: false
+  tenants:
+    gARh1g6reuvsAHB7DXqiuYzzyiJeoiJmtmCpV8Y5uWC: team-a
+filters:
+  event_allowlist:
+    - CounterIncrementedEvent
+    - NftMintedEvent
+retention:
+  days: 90
+  overrides:
+    CounterIncrementedEvent: 7
+`
+	m, err := ReadManifestYAML(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadManifestYAML() error = %v", err)
+	}
+	want := map[string]string{
+		"CONFIG_MIRROR_ENABLED": "false",
+		"PROGRAM_TENANTS":       "gARh1g6reuvsAHB7DXqiuYzzyiJeoiJmtmCpV8Y5uWC=team-a",
+		"EVENT_ALLOWLIST":       "CounterIncrementedEvent,NftMintedEvent",
+		"RETENTION_DAYS":        "90",
+		"RETENTION_OVERRIDES":   "CounterIncrementedEvent=7",
+	}
+	if got := m.Env(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Env() = %v, want %v", got, want)
+	}
+
+	if _, err := ReadManifestYAML(strings.NewReader("version: 1\nretention:\n  dayz: 30\n")); err == nil {
+		t.Error("ReadManifestYAML() with an unknown field succeeded, want an error")
+	}
+}
@@ -0,0 +1,119 @@
+// Package coverage tracks how completely the "Program data:" payloads of
+// processed transactions are decoded, in total and per UTC day.
+package coverage
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+const dayLayout = "2006-01-02"
+
+// Tracker aggregates the coverage of each transaction. When the day
+// changes it logs the report of the day that ended.
+type Tracker struct {
+	store repository.CoverageStore
+	now   func() time.Time
+
+	mu      sync.Mutex
+	total   models.DecoderCoverage
+	day     string
+	today   models.DecoderCoverage
+	pending map[string]models.DecoderCoverage
+}
+
+// NewTracker returns a tracker that adds the daily coverage to store on
+// Flush. store may be nil, in which case only the totals are kept.
+func NewTracker(store repository.CoverageStore) *Tracker {
+	return &Tracker{
+		store:   store,
+		now:     time.Now,
+		pending: make(map[string]models.DecoderCoverage),
+	}
+}
+
+// Record adds the coverage of one transaction.
+func (t *Tracker) Record(c models.DecoderCoverage) {
+	day := t.now().UTC().Format(dayLayout)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if day != t.day {
+		if t.day != "" {
+			logReport(t.day, t.today)
+		}
+		t.day, t.today = day, models.DecoderCoverage{}
+	}
+	t.total.Add(c)
+	t.today.Add(c)
+	if t.store != nil {
+		p := t.pending[day]
+		p.Add(c)
+		t.pending[day] = p
+	}
+}
+
+// Totals returns the coverage recorded since the tracker was created.
+func (t *Tracker) Totals() models.DecoderCoverage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.total
+}
+
+// Flush adds the coverage recorded since the last Flush to the store. What
+// fails to be stored is kept for the next Flush.
+func (t *Tracker) Flush(ctx context.Context) error {
+	if t.store == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	pending := t.pending
+	t.pending = make(map[string]models.DecoderCoverage)
+	t.mu.Unlock()
+
+	var firstErr error
+	for day, c := range pending {
+		err := t.store.AddDecoderCoverage(ctx, day, c)
+		if err == nil {
+			continue
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+		t.mu.Lock()
+		p := t.pending[day]
+		p.Add(c)
+		t.pending[day] = p
+		t.mu.Unlock()
+	}
+	return firstErr
+}
+
+// Run flushes every interval until ctx is done.
+func (t *Tracker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := t.Flush(ctx); err != nil {
+			log.Printf("warning: failed to store decoder coverage: %v", err)
+		}
+	}
+}
+
+func logReport(day string, c models.DecoderCoverage) {
+	log.Printf("decoder coverage for %s: %d transactions, %d payloads found, %d decoded (%.1f%%), %d unknown, %d failed",
+		day, c.Transactions, c.Found, c.Decoded, 100*c.Ratio(), c.Unknown, c.Failed)
+}
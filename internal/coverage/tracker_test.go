@@ -0,0 +1,87 @@
+package coverage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+type memStore struct {
+	days map[string]models.DecoderCoverage
+	err  error
+}
+
+func (s *memStore) AddDecoderCoverage(ctx context.Context, day string, c models.DecoderCoverage) error {
+	if s.err != nil {
+		return s.err
+	}
+	d := s.days[day]
+	d.Add(c)
+	s.days[day] = d
+	return nil
+}
+
+func (s *memStore) GetDecoderCoverage(ctx context.Context, from, to string) ([]models.DailyDecoderCoverage, error) {
+	return nil, nil
+}
+
+func TestTracker(t *testing.T) {
+	store := &memStore{days: make(map[string]models.DecoderCoverage)}
+	tracker := NewTracker(store)
+	now := time.Date(2026, 10, 15, 23, 59, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+
+	tracker.Record(models.DecoderCoverage{Transactions: 1, Found: 3, Decoded: 2, Unknown: 1})
+	now = now.Add(2 * time.Minute)
+	tracker.Record(models.DecoderCoverage{Transactions: 1, Found: 2, Decoded: 1, Failed: 1})
+
+	want := models.DecoderCoverage{Transactions: 2, Found: 5, Decoded: 3, Unknown: 1, Failed: 1}
+	if got := tracker.Totals(); got != want {
+		t.Errorf("Totals() = %+v, want %+v", got, want)
+	}
+
+	store.err = errors.New("unreachable")
+	if err := tracker.Flush(context.Background()); err == nil {
+		t.Fatal("Flush() error = nil, want error")
+	}
+	store.err = nil
+	if err := tracker.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	days := map[string]models.DecoderCoverage{
+		"2026-10-15": {Transactions: 1, Found: 3, Decoded: 2, Unknown: 1},
+		"2026-10-16": {Transactions: 1, Found: 2, Decoded: 1, Failed: 1},
+	}
+	for day, want := range days {
+		if got := store.days[day]; got != want {
+			t.Errorf("stored %s = %+v, want %+v", day, got, want)
+		}
+	}
+
+	// Flushed coverage is not stored twice.
+	if err := tracker.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if got := store.days["2026-10-16"]; got != days["2026-10-16"] {
+		t.Errorf("stored 2026-10-16 after second Flush = %+v, want %+v", got, days["2026-10-16"])
+	}
+}
+
+func TestDecoderCoverage_Ratio(t *testing.T) {
+	tests := []struct {
+		c    models.DecoderCoverage
+		want float64
+	}{
+		{models.DecoderCoverage{}, 1},
+		{models.DecoderCoverage{Found: 4, Decoded: 3, Unknown: 1}, 0.75},
+	}
+	for _, tt := range tests {
+		if got := tt.c.Ratio(); got != tt.want {
+			t.Errorf("Ratio(%+v) = %g, want %g", tt.c, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package decoder
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// ProgramConfigSeed is the PDA seed of the starter program's config account.
+const ProgramConfigSeed = "program_config"
+
+func accountDiscriminator(name string) [8]byte {
+	hash := sha256.Sum256([]byte("account:" + name))
+	var d [8]byte
+	copy(d[:], hash[:8])
+	return d
+}
+
+var programConfigDiscriminator = accountDiscriminator("ProgramConfig")
+
+// ProgramConfigAddress derives the ProgramConfig PDA of programID.
+func ProgramConfigAddress(programID solana.PublicKey) (solana.PublicKey, error) {
+	addr, _, err := solana.FindProgramAddress([][]byte{[]byte(ProgramConfigSeed)}, programID)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("derive program config address: %w", err)
+	}
+	return addr, nil
+}
+
+// DecodeProgramConfig decodes the raw data of a ProgramConfig account.
+func DecodeProgramConfig(data []byte) (*models.ProgramConfig, error) {
+	if len(data) < 8 {
+		return nil, fmt.Errorf("data too short for discriminator")
+	}
+	if !bytes.Equal(data[:8], programConfigDiscriminator[:]) {
+		return nil, fmt.Errorf("not a ProgramConfig account")
+	}
+
+	decoder := bin.NewBinDecoder(data[8:])
+	config := &models.ProgramConfig{}
+	if err := decoder.Decode(&config.Admin); err != nil {
+		return nil, fmt.Errorf("decode admin: %w", err)
+	}
+	if err := decoder.Decode(&config.FeeDestination); err != nil {
+		return nil, fmt.Errorf("decode fee destination: %w", err)
+	}
+	if err := decoder.Decode(&config.FeeBasisPoints); err != nil {
+		return nil, fmt.Errorf("decode fee basis points: %w", err)
+	}
+	if err := decoder.Decode(&config.Paused); err != nil {
+		return nil, fmt.Errorf("decode paused: %w", err)
+	}
+	return config, nil
+}
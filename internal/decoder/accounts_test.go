@@ -0,0 +1,39 @@
+package decoder
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+func TestDecodeProgramConfig(t *testing.T) {
+	admin := solana.PublicKey{1}
+	feeDestination := solana.PublicKey{2}
+
+	// Discriminator taken from idl/starter_program.json.
+	data := []byte{196, 210, 90, 231, 144, 149, 140, 63}
+	data = append(data, admin[:]...)
+	data = append(data, feeDestination[:]...)
+	data = binary.LittleEndian.AppendUint64(data, 250)
+	data = append(data, 1, 254) // paused, bump
+
+	config, err := DecodeProgramConfig(data)
+	if err != nil {
+		t.Fatalf("DecodeProgramConfig() error = %v", err)
+	}
+	if !config.Admin.Equals(admin) || !config.FeeDestination.Equals(feeDestination) {
+		t.Errorf("config = %+v", config)
+	}
+	if config.FeeBasisPoints != 250 || !config.Paused {
+		t.Errorf("FeeBasisPoints = %d, Paused = %v; want 250, true", config.FeeBasisPoints, config.Paused)
+	}
+
+	data[0] ^= 0xff
+	if _, err := DecodeProgramConfig(data); err == nil {
+		t.Error("DecodeProgramConfig() expected error for wrong discriminator")
+	}
+	if _, err := DecodeProgramConfig(data[:4]); err == nil {
+		t.Error("DecodeProgramConfig() expected error for short data")
+	}
+}
@@ -8,8 +8,14 @@ import (
 	bin "github.com/gagliardetto/binary"
 	"github.com/gagliardetto/solana-go"
 	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	pkgdecoder "github.com/lugondev/go-indexer-solana-starter/pkg/decoder"
 )
 
+// ErrUnknownEvent is returned by DecodeEvent for payloads that are not an
+// event it can decode, as opposed to a known event that fails to decode. It
+// is the error registered decoders return too.
+var ErrUnknownEvent = pkgdecoder.ErrUnknownEvent
+
 type EventDecoder struct {
 	discriminators map[string]models.EventType
 }
@@ -53,13 +59,13 @@ func eventDiscriminator(name string) string {
 
 func (d *EventDecoder) DecodeEvent(data []byte) (models.EventType, interface{}, error) {
 	if len(data) < 8 {
-		return "", nil, fmt.Errorf("data too short for discriminator")
+		return "", nil, fmt.Errorf("%w: data too short for discriminator", ErrUnknownEvent)
 	}
 
 	discriminator := base64.StdEncoding.EncodeToString(data[:8])
 	eventType, ok := d.discriminators[discriminator]
 	if !ok {
-		return "", nil, fmt.Errorf("unknown discriminator: %s", discriminator)
+		return "", nil, fmt.Errorf("%w: unknown discriminator: %s", ErrUnknownEvent, discriminator)
 	}
 
 	eventData := data[8:]
@@ -84,173 +90,158 @@ func (d *EventDecoder) DecodeEvent(data []byte) (models.EventType, interface{},
 	case models.EventTypeConfigUpdated:
 		event, err := decodeConfigUpdated(decoder)
 		return eventType, event, err
+	case models.EventTypeProgramPaused:
+		event, err := decodeProgramPaused(decoder)
+		return eventType, event, err
 	case models.EventTypeNftMinted:
 		event, err := decodeNftMinted(decoder)
 		return eventType, event, err
+	case models.EventTypeNftSold:
+		event, err := decodeNftSold(decoder)
+		return eventType, event, err
 	default:
-		return eventType, nil, fmt.Errorf("decoder not implemented for %s", eventType)
+		return eventType, nil, fmt.Errorf("%w: decoder not implemented for %s", ErrUnknownEvent, eventType)
 	}
 }
 
 func decodeTokensMinted(decoder *bin.Decoder) (*models.TokensMintedEvent, error) {
-	event := &models.TokensMintedEvent{}
-	if err := decoder.Decode(&event.Mint); err != nil {
-		return nil, err
-	}
-	if err := decoder.Decode(&event.Recipient); err != nil {
-		return nil, err
-	}
-	if err := decoder.Decode(&event.Amount); err != nil {
+	var (
+		mint, recipient solana.PublicKey
+		amount          uint64
+		timestamp       int64
+	)
+	if err := decodeAll(decoder, &mint, &recipient, &amount, &timestamp); err != nil {
 		return nil, err
 	}
-	if err := decoder.Decode(&event.Timestamp); err != nil {
-		return nil, err
-	}
-	return event, nil
+	return models.NewTokensMintedEvent(mint, recipient, amount, timestamp)
 }
 
 func decodeTokensTransferred(decoder *bin.Decoder) (*models.TokensTransferredEvent, error) {
-	event := &models.TokensTransferredEvent{}
-	if err := decoder.Decode(&event.Mint); err != nil {
-		return nil, err
-	}
-	if err := decoder.Decode(&event.From); err != nil {
-		return nil, err
-	}
-	if err := decoder.Decode(&event.To); err != nil {
+	var (
+		mint, from, to solana.PublicKey
+		amount         uint64
+		timestamp      int64
+	)
+	if err := decodeAll(decoder, &mint, &from, &to, &amount, &timestamp); err != nil {
 		return nil, err
 	}
-	if err := decoder.Decode(&event.Amount); err != nil {
-		return nil, err
-	}
-	if err := decoder.Decode(&event.Timestamp); err != nil {
-		return nil, err
-	}
-	return event, nil
+	return models.NewTokensTransferredEvent(mint, from, to, amount, timestamp)
 }
 
 func decodeTokensBurned(decoder *bin.Decoder) (*models.TokensBurnedEvent, error) {
-	event := &models.TokensBurnedEvent{}
-	if err := decoder.Decode(&event.Mint); err != nil {
+	var (
+		mint, owner solana.PublicKey
+		amount      uint64
+		timestamp   int64
+	)
+	if err := decodeAll(decoder, &mint, &owner, &amount, &timestamp); err != nil {
 		return nil, err
 	}
-	if err := decoder.Decode(&event.Owner); err != nil {
-		return nil, err
-	}
-	if err := decoder.Decode(&event.Amount); err != nil {
-		return nil, err
-	}
-	if err := decoder.Decode(&event.Timestamp); err != nil {
-		return nil, err
-	}
-	return event, nil
+	return models.NewTokensBurnedEvent(mint, owner, amount, timestamp)
 }
 
 func decodeUserAccountCreated(decoder *bin.Decoder) (*models.UserAccountCreatedEvent, error) {
-	event := &models.UserAccountCreatedEvent{}
-	if err := decoder.Decode(&event.User); err != nil {
-		return nil, err
-	}
-	if err := decoder.Decode(&event.Authority); err != nil {
-		return nil, err
-	}
-	if err := decoder.Decode(&event.Timestamp); err != nil {
+	var (
+		user, authority solana.PublicKey
+		timestamp       int64
+	)
+	if err := decodeAll(decoder, &user, &authority, &timestamp); err != nil {
 		return nil, err
 	}
-	return event, nil
+	return models.NewUserAccountCreatedEvent(user, authority, timestamp)
 }
 
 func decodeUserAccountUpdated(decoder *bin.Decoder) (*models.UserAccountUpdatedEvent, error) {
-	event := &models.UserAccountUpdatedEvent{}
-	if err := decoder.Decode(&event.User); err != nil {
-		return nil, err
-	}
-	if err := decoder.Decode(&event.OldPoints); err != nil {
-		return nil, err
-	}
-	if err := decoder.Decode(&event.NewPoints); err != nil {
-		return nil, err
-	}
-	if err := decoder.Decode(&event.Timestamp); err != nil {
+	var (
+		user                 solana.PublicKey
+		oldPoints, newPoints uint64
+		timestamp            int64
+	)
+	if err := decodeAll(decoder, &user, &oldPoints, &newPoints, &timestamp); err != nil {
 		return nil, err
 	}
-	return event, nil
+	return models.NewUserAccountUpdatedEvent(user, oldPoints, newPoints, timestamp)
 }
 
 func decodeConfigUpdated(decoder *bin.Decoder) (*models.ConfigUpdatedEvent, error) {
-	event := &models.ConfigUpdatedEvent{}
-	if err := decoder.Decode(&event.Admin); err != nil {
+	var (
+		admin          solana.PublicKey
+		oldFee, newFee uint64
+		timestamp      int64
+	)
+	if err := decodeAll(decoder, &admin, &oldFee, &newFee, &timestamp); err != nil {
 		return nil, err
 	}
-	if err := decoder.Decode(&event.OldFee); err != nil {
-		return nil, err
-	}
-	if err := decoder.Decode(&event.NewFee); err != nil {
-		return nil, err
-	}
-	if err := decoder.Decode(&event.Timestamp); err != nil {
+	return models.NewConfigUpdatedEvent(admin, oldFee, newFee, timestamp)
+}
+
+func decodeProgramPaused(decoder *bin.Decoder) (*models.ProgramPausedEvent, error) {
+	var (
+		admin     solana.PublicKey
+		paused    bool
+		timestamp int64
+	)
+	if err := decodeAll(decoder, &admin, &paused, &timestamp); err != nil {
 		return nil, err
 	}
-	return event, nil
+	return models.NewProgramPausedEvent(admin, paused, timestamp)
 }
 
 func decodeNftMinted(decoder *bin.Decoder) (*models.NftMintedEvent, error) {
-	event := &models.NftMintedEvent{}
-	if err := decoder.Decode(&event.NftMint); err != nil {
-		return nil, err
-	}
-	if err := decoder.Decode(&event.Collection); err != nil {
-		return nil, err
-	}
-	if err := decoder.Decode(&event.Owner); err != nil {
+	var nftMint, collection, owner solana.PublicKey
+	if err := decodeAll(decoder, &nftMint, &collection, &owner); err != nil {
 		return nil, err
 	}
 
-	var nameLen uint32
-	if err := decoder.Decode(&nameLen); err != nil {
+	name, err := decodeString(decoder)
+	if err != nil {
 		return nil, err
 	}
-	nameBytes := make([]byte, nameLen)
-	if err := decoder.Decode(&nameBytes); err != nil {
+	uri, err := decodeString(decoder)
+	if err != nil {
 		return nil, err
 	}
-	event.Name = string(nameBytes)
 
-	var uriLen uint32
-	if err := decoder.Decode(&uriLen); err != nil {
+	var timestamp int64
+	if err := decoder.Decode(&timestamp); err != nil {
 		return nil, err
 	}
-	uriBytes := make([]byte, uriLen)
-	if err := decoder.Decode(&uriBytes); err != nil {
-		return nil, err
-	}
-	event.Uri = string(uriBytes)
+	return models.NewNftMintedEvent(nftMint, collection, owner, name, uri, timestamp)
+}
 
-	if err := decoder.Decode(&event.Timestamp); err != nil {
+func decodeNftSold(decoder *bin.Decoder) (*models.NftSoldEvent, error) {
+	var (
+		nftMint, seller, buyer solana.PublicKey
+		price                  uint64
+		timestamp              int64
+	)
+	if err := decodeAll(decoder, &nftMint, &seller, &buyer, &price, &timestamp); err != nil {
 		return nil, err
 	}
-	return event, nil
+	return models.NewNftSoldEvent(nftMint, seller, buyer, price, timestamp)
 }
 
-func ParseProgramData(logs []string) [][]byte {
-	var programData [][]byte
-
-	for _, log := range logs {
-		if len(log) < 14 {
-			continue
-		}
-
-		if log[:13] == "Program data:" {
-			dataStr := log[14:]
-			data, err := base64.StdEncoding.DecodeString(dataStr)
-			if err != nil {
-				continue
-			}
-			programData = append(programData, data)
+// decodeAll decodes the Borsh fields into values, in order.
+func decodeAll(decoder *bin.Decoder, values ...interface{}) error {
+	for _, v := range values {
+		if err := decoder.Decode(v); err != nil {
+			return err
 		}
 	}
+	return nil
+}
 
-	return programData
+// decodeString decodes a Borsh string: a u32 length and that many bytes.
+func decodeString(decoder *bin.Decoder) (string, error) {
+	var length uint32
+	if err := decoder.Decode(&length); err != nil {
+		return "", err
+	}
+	data := make([]byte, length)
+	if err := decoder.Decode(&data); err != nil {
+		return "", err
+	}
+	return string(data), nil
 }
 
 func FilterByProgramID(programID solana.PublicKey, data []byte) bool {
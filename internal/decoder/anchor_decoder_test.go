@@ -0,0 +1,39 @@
+package decoder
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestDecodeEvent_Unknown(t *testing.T) {
+	d := NewEventDecoder()
+	tests := []struct {
+		name        string
+		data        []byte
+		wantUnknown bool
+	}{
+		{"too short", []byte{1, 2, 3}, true},
+		{"unknown discriminator", []byte{1, 2, 3, 4, 5, 6, 7, 8, 9}, true},
+		{"truncated known event", mustDecodeBase64(t, eventDiscriminator("TokensMintedEvent")), false},
+	}
+	for _, tt := range tests {
+		_, _, err := d.DecodeEvent(tt.data)
+		if err == nil {
+			t.Errorf("%s: DecodeEvent() error = nil, want error", tt.name)
+			continue
+		}
+		if got := errors.Is(err, ErrUnknownEvent); got != tt.wantUnknown {
+			t.Errorf("%s: errors.Is(%v, ErrUnknownEvent) = %v, want %v", tt.name, err, got, tt.wantUnknown)
+		}
+	}
+}
+
+func mustDecodeBase64(t *testing.T, s string) []byte {
+	t.Helper()
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		t.Fatalf("decode base64: %v", err)
+	}
+	return data
+}
@@ -0,0 +1,137 @@
+package decoder
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/codegen"
+)
+
+// CounterInstruction is a counter program instruction with its account
+// indexes already resolved against the transaction's account keys.
+type CounterInstruction struct {
+	Accounts []solana.PublicKey
+	Data     []byte
+}
+
+// counterAccountLayout records where each role sits in an instruction's
+// account list. A negative index means the instruction has no such account.
+type counterAccountLayout struct {
+	Name         string
+	Counter      int
+	Authority    int
+	Payer        int
+	FeeCollector int
+}
+
+// defaultCounterLayouts are used without the counter program's IDL, see
+// NewCounterLogParserFromIDL. They are the account orders loadgen and the
+// test validator scripts build, not read from a published IDL, so indexing
+// a counter program whose accounts differ requires COUNTER_PROGRAM_IDL.
+var defaultCounterLayouts = makeCounterLayoutMap([]counterAccountLayout{
+	{Name: "initialize", Counter: 0, Authority: 1, Payer: -1, FeeCollector: -1},
+	{Name: "increment", Counter: 0, Authority: -1, Payer: -1, FeeCollector: -1},
+	{Name: "decrement", Counter: 0, Authority: -1, Payer: -1, FeeCollector: -1},
+	{Name: "add", Counter: 0, Authority: -1, Payer: -1, FeeCollector: -1},
+	{Name: "reset", Counter: 0, Authority: 1, Payer: -1, FeeCollector: -1},
+	{Name: "increment_with_payment", Counter: 0, Authority: -1, Payer: 1, FeeCollector: 2},
+})
+
+// defaultCounterLayout is used for instructions whose discriminator is not
+// known; Anchor places the primary account first by convention.
+var defaultCounterLayout = counterAccountLayout{Counter: 0, Authority: -1, Payer: -1, FeeCollector: -1}
+
+func makeCounterLayoutMap(layouts []counterAccountLayout) map[[8]byte]counterAccountLayout {
+	m := make(map[[8]byte]counterAccountLayout, len(layouts))
+	for _, layout := range layouts {
+		m[instructionDiscriminator(layout.Name)] = layout
+	}
+	return m
+}
+
+// counterLayoutsFromIDL reads the account layout of every instruction of
+// idl from its account names: counter, authority, payer and fee_collector.
+func counterLayoutsFromIDL(idl *codegen.IDL) (map[[8]byte]counterAccountLayout, error) {
+	layouts := make(map[[8]byte]counterAccountLayout, len(idl.Instructions))
+	for _, ix := range idl.Instructions {
+		layout := counterAccountLayout{Name: ix.Name, Counter: -1, Authority: -1, Payer: -1, FeeCollector: -1}
+		for n, account := range ix.Accounts {
+			switch account.Name {
+			case "counter":
+				layout.Counter = n
+			case "authority":
+				layout.Authority = n
+			case "payer":
+				layout.Payer = n
+			case "fee_collector":
+				layout.FeeCollector = n
+			}
+		}
+		if layout.Counter < 0 {
+			continue
+		}
+		layouts[ix.Discriminator] = layout
+	}
+	if len(layouts) == 0 {
+		return nil, fmt.Errorf("no instruction of IDL %s takes a counter account", idl.Metadata.Name)
+	}
+	return layouts, nil
+}
+
+func instructionDiscriminator(name string) [8]byte {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("global:%s", name)))
+	var d [8]byte
+	copy(d[:], hash[:8])
+	return d
+}
+
+// counterAccounts holds the accounts an action refers to.
+type counterAccounts struct {
+	Counter      solana.PublicKey
+	Authority    *solana.PublicKey
+	Payer        *solana.PublicKey
+	FeeCollector *solana.PublicKey
+}
+
+// resolveCounterAccounts picks the counter, authority, payer and fee
+// collector from the instruction that produced a log, looking its account
+// layout up in layouts. Without an instruction it falls back to positional
+// message accounts.
+func resolveCounterAccounts(layouts map[[8]byte]counterAccountLayout, ix *CounterInstruction, accounts []solana.PublicKey) counterAccounts {
+	if ix == nil {
+		var resolved counterAccounts
+		if len(accounts) > 0 {
+			resolved.Counter = accounts[0]
+		}
+		resolved.Payer = accountAt(accounts, 1)
+		resolved.FeeCollector = accountAt(accounts, 2)
+		return resolved
+	}
+
+	layout := defaultCounterLayout
+	if len(ix.Data) >= 8 {
+		var d [8]byte
+		copy(d[:], ix.Data[:8])
+		if known, ok := layouts[d]; ok {
+			layout = known
+		}
+	}
+
+	var resolved counterAccounts
+	if counter := accountAt(ix.Accounts, layout.Counter); counter != nil {
+		resolved.Counter = *counter
+	}
+	resolved.Authority = accountAt(ix.Accounts, layout.Authority)
+	resolved.Payer = accountAt(ix.Accounts, layout.Payer)
+	resolved.FeeCollector = accountAt(ix.Accounts, layout.FeeCollector)
+	return resolved
+}
+
+func accountAt(accounts []solana.PublicKey, index int) *solana.PublicKey {
+	if index < 0 || index >= len(accounts) {
+		return nil
+	}
+	key := accounts[index]
+	return &key
+}
@@ -2,41 +2,123 @@ package decoder
 
 import (
 	"fmt"
+	"math"
 	"regexp"
-	"strconv"
 	"strings"
 
 	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/codegen"
 	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	pkgdecoder "github.com/lugondev/go-indexer-solana-starter/pkg/decoder"
+)
+
+const programLogPrefix = "Program log: "
+
+var (
+	counterAddedPattern   = regexp.MustCompile(`^Added (\d+) to counter\. New value: (\d+)`)
+	counterPaymentPattern = regexp.MustCompile(`^Payment of (\d+) lamports received\. Counter incremented to: (\d+)`)
 )
 
 type CounterLogParser struct {
 	programID solana.PublicKey
+	layouts   map[[8]byte]counterAccountLayout
 }
 
 func NewCounterLogParser(programID solana.PublicKey) *CounterLogParser {
 	return &CounterLogParser{
 		programID: programID,
+		layouts:   defaultCounterLayouts,
+	}
+}
+
+// NewCounterLogParserFromIDL returns a parser resolving the accounts of
+// each instruction by their names in the counter program's IDL.
+func NewCounterLogParserFromIDL(programID solana.PublicKey, idl *codegen.IDL) (*CounterLogParser, error) {
+	layouts, err := counterLayoutsFromIDL(idl)
+	if err != nil {
+		return nil, err
 	}
+	return &CounterLogParser{programID: programID, layouts: layouts}, nil
 }
 
+// ParseLogs parses counter logs using positional message accounts. Prefer
+// ParseLogsWithInstructions, which attributes each log to the instruction
+// that emitted it.
 func (p *CounterLogParser) ParseLogs(logs []string, accounts []solana.PublicKey) ([]CounterAction, error) {
-	var actions []CounterAction
+	return p.ParseLogsWithInstructions(logs, nil, accounts)
+}
 
-	for _, log := range logs {
-		if !strings.Contains(log, "Program log:") {
-			continue
+// ParseLogsWithInstructions parses counter logs and resolves accounts from
+// the counter program instruction active when each log line was written.
+// instructions must be in execution order (top-level instructions with their
+// inner instructions interleaved). Logs outside any tracked invocation fall
+// back to the positional accounts.
+func (p *CounterLogParser) ParseLogsWithInstructions(logs []string, instructions []CounterInstruction, accounts []solana.PublicKey) ([]CounterAction, error) {
+	var actions []CounterAction
+	walkProgramLogs(logs, p.programID.String(), instructions, func(msg string, frame *logFrame) {
+		var ix *CounterInstruction
+		if frame != nil {
+			ix = frame.instruction
+			if ix == nil && len(instructions) > 0 {
+				// Log from another program's invocation.
+				return
+			}
 		}
 
-		action := p.parseLogMessage(log, accounts)
+		action := p.parseLogMessage(msg, resolveCounterAccounts(p.layouts, ix, accounts))
 		if action != nil {
 			actions = append(actions, *action)
 		}
-	}
+	})
 
 	return actions, nil
 }
 
+// logFrame is a program invocation in a transaction's logs. instruction is
+// the next of the instructions walkProgramLogs was given when the program
+// invoked is theirs.
+type logFrame struct {
+	program     string
+	instruction *CounterInstruction
+}
+
+// walkProgramLogs calls fn with the message of every "Program log: " line
+// and the innermost invocation active when it was written, nil outside any.
+// instructions are the instructions of program in execution order.
+func walkProgramLogs(logs []string, program string, instructions []CounterInstruction, fn func(msg string, frame *logFrame)) {
+	var (
+		stack []*logFrame
+		next  int
+	)
+	for _, log := range logs {
+		if invoked, ok := pkgdecoder.InvokedProgram(log); ok {
+			frame := &logFrame{program: invoked}
+			if invoked == program && next < len(instructions) {
+				frame.instruction = &instructions[next]
+				next++
+			}
+			stack = append(stack, frame)
+			continue
+		}
+		if pkgdecoder.IsProgramExit(log) {
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			continue
+		}
+
+		msg, ok := programLogMessage(log)
+		if !ok {
+			continue
+		}
+		var frame *logFrame
+		if len(stack) > 0 {
+			frame = stack[len(stack)-1]
+		}
+		fn(msg, frame)
+	}
+}
+
 type CounterAction struct {
 	Type         models.EventType
 	Counter      solana.PublicKey
@@ -49,59 +131,58 @@ type CounterAction struct {
 	Payment      *uint64
 }
 
-func (p *CounterLogParser) parseLogMessage(log string, accounts []solana.PublicKey) *CounterAction {
-	msgPrefix := "Program log: "
-	if !strings.Contains(log, msgPrefix) {
-		return nil
+// programLogMessage returns the trimmed text following the "Program log: "
+// prefix. The result is a substring of log, so no copy is made.
+func programLogMessage(log string) (string, bool) {
+	idx := strings.Index(log, programLogPrefix)
+	if idx < 0 {
+		return "", false
 	}
+	return strings.TrimSpace(log[idx+len(programLogPrefix):]), true
+}
 
-	msg := strings.TrimSpace(strings.Split(log, msgPrefix)[1])
-
-	var counter solana.PublicKey
-	if len(accounts) > 0 {
-		counter = accounts[0]
+func (p *CounterLogParser) parseLogMessage(msg string, accounts counterAccounts) *CounterAction {
+	if msg == "" {
+		return nil
 	}
 
+	counter := accounts.Counter
+
 	if msg == "Counter initialized" {
 		return &CounterAction{
-			Type:     models.EventTypeCounterInitialized,
-			Counter:  counter,
-			NewValue: uint64Ptr(0),
+			Type:      models.EventTypeCounterInitialized,
+			Counter:   counter,
+			Authority: accounts.Authority,
+			NewValue:  uint64Ptr(0),
 		}
 	}
 
 	if strings.HasPrefix(msg, "Counter incremented to: ") {
-		newValue := p.extractNumber(msg, "Counter incremented to: ")
-		if newValue != nil {
-			oldValue := *newValue - 1
+		if newValue, ok := parseUintBytes(msg[len("Counter incremented to: "):]); ok {
+			oldValue := newValue - 1
 			return &CounterAction{
 				Type:     models.EventTypeCounterIncremented,
 				Counter:  counter,
 				OldValue: &oldValue,
-				NewValue: newValue,
+				NewValue: &newValue,
 			}
 		}
 	}
 
 	if strings.HasPrefix(msg, "Counter decremented to: ") {
-		newValue := p.extractNumber(msg, "Counter decremented to: ")
-		if newValue != nil {
-			oldValue := *newValue + 1
+		if newValue, ok := parseUintBytes(msg[len("Counter decremented to: "):]); ok {
+			oldValue := newValue + 1
 			return &CounterAction{
 				Type:     models.EventTypeCounterDecremented,
 				Counter:  counter,
 				OldValue: &oldValue,
-				NewValue: newValue,
+				NewValue: &newValue,
 			}
 		}
 	}
 
-	if strings.HasPrefix(msg, "Added ") && strings.Contains(msg, "to counter") {
-		re := regexp.MustCompile(`Added (\d+) to counter\. New value: (\d+)`)
-		matches := re.FindStringSubmatch(msg)
-		if len(matches) == 3 {
-			added, _ := strconv.ParseUint(matches[1], 10, 64)
-			newVal, _ := strconv.ParseUint(matches[2], 10, 64)
+	if strings.HasPrefix(msg, "Added ") {
+		if added, newVal, ok := matchUintPair(counterAddedPattern, msg); ok {
 			oldVal := newVal - added
 			return &CounterAction{
 				Type:       models.EventTypeCounterAdded,
@@ -115,35 +196,23 @@ func (p *CounterLogParser) parseLogMessage(log string, accounts []solana.PublicK
 
 	if msg == "Counter reset" {
 		return &CounterAction{
-			Type:     models.EventTypeCounterReset,
-			Counter:  counter,
-			OldValue: nil,
-			NewValue: uint64Ptr(0),
+			Type:      models.EventTypeCounterReset,
+			Counter:   counter,
+			Authority: accounts.Authority,
+			OldValue:  nil,
+			NewValue:  uint64Ptr(0),
 		}
 	}
 
-	if strings.HasPrefix(msg, "Payment of ") && strings.Contains(msg, "lamports received") {
-		re := regexp.MustCompile(`Payment of (\d+) lamports received\. Counter incremented to: (\d+)`)
-		matches := re.FindStringSubmatch(msg)
-		if len(matches) == 3 {
-			payment, _ := strconv.ParseUint(matches[1], 10, 64)
-			newCount, _ := strconv.ParseUint(matches[2], 10, 64)
-
-			var payer, feeCollector *solana.PublicKey
-			if len(accounts) > 1 {
-				payer = &accounts[1]
-			}
-			if len(accounts) > 2 {
-				feeCollector = &accounts[2]
-			}
-
+	if strings.HasPrefix(msg, "Payment of ") {
+		if payment, newCount, ok := matchUintPair(counterPaymentPattern, msg); ok {
 			return &CounterAction{
 				Type:         models.EventTypeCounterPaymentReceived,
 				Counter:      counter,
 				Payment:      &payment,
 				NewValue:     &newCount,
-				Payer:        payer,
-				FeeCollector: feeCollector,
+				Payer:        accounts.Payer,
+				FeeCollector: accounts.FeeCollector,
 			}
 		}
 	}
@@ -151,16 +220,47 @@ func (p *CounterLogParser) parseLogMessage(log string, accounts []solana.PublicK
 	return nil
 }
 
-func (p *CounterLogParser) extractNumber(msg, prefix string) *uint64 {
-	numStr := strings.TrimPrefix(msg, prefix)
-	numStr = strings.TrimSpace(numStr)
+// matchUintPair runs a precompiled two-group pattern against msg and parses
+// both groups as uint64 directly from the matched byte ranges.
+func matchUintPair(re *regexp.Regexp, msg string) (uint64, uint64, bool) {
+	loc := re.FindStringSubmatchIndex(msg)
+	if len(loc) != 6 {
+		return 0, 0, false
+	}
 
-	num, err := strconv.ParseUint(numStr, 10, 64)
-	if err != nil {
-		return nil
+	first, ok := parseUintBytes(msg[loc[2]:loc[3]])
+	if !ok {
+		return 0, 0, false
+	}
+	second, ok := parseUintBytes(msg[loc[4]:loc[5]])
+	if !ok {
+		return 0, 0, false
+	}
+	return first, second, true
+}
+
+// parseUintBytes parses a base-10 uint64 by walking the bytes of s, avoiding
+// the intermediate allocations of strconv on error paths.
+func parseUintBytes(s string) (uint64, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, false
+	}
+
+	var num uint64
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		digit := uint64(c - '0')
+		if num > (math.MaxUint64-digit)/10 {
+			return 0, false
+		}
+		num = num*10 + digit
 	}
 
-	return &num
+	return num, true
 }
 
 func uint64Ptr(v uint64) *uint64 {
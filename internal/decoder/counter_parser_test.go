@@ -0,0 +1,329 @@
+package decoder
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/codegen"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+func TestCounterLogParser_ParseLogs(t *testing.T) {
+	counter := solana.PublicKey{1}
+	payer := solana.PublicKey{2}
+	feeCollector := solana.PublicKey{3}
+	accounts := []solana.PublicKey{counter, payer, feeCollector}
+
+	tests := []struct {
+		name     string
+		log      string
+		wantType models.EventType
+		wantOld  *uint64
+		wantNew  *uint64
+		wantNone bool
+	}{
+		{
+			name:     "initialized",
+			log:      "Program log: Counter initialized",
+			wantType: models.EventTypeCounterInitialized,
+			wantNew:  uint64Ptr(0),
+		},
+		{
+			name:     "incremented",
+			log:      "Program log: Counter incremented to: 42",
+			wantType: models.EventTypeCounterIncremented,
+			wantOld:  uint64Ptr(41),
+			wantNew:  uint64Ptr(42),
+		},
+		{
+			name:     "decremented with trailing whitespace",
+			log:      "Program log: Counter decremented to: 7  ",
+			wantType: models.EventTypeCounterDecremented,
+			wantOld:  uint64Ptr(8),
+			wantNew:  uint64Ptr(7),
+		},
+		{
+			name:     "added",
+			log:      "Program log: Added 5 to counter. New value: 47",
+			wantType: models.EventTypeCounterAdded,
+			wantOld:  uint64Ptr(42),
+			wantNew:  uint64Ptr(47),
+		},
+		{
+			name:     "reset",
+			log:      "Program log: Counter reset",
+			wantType: models.EventTypeCounterReset,
+			wantNew:  uint64Ptr(0),
+		},
+		{
+			name:     "payment",
+			log:      "Program log: Payment of 1000000 lamports received. Counter incremented to: 48",
+			wantType: models.EventTypeCounterPaymentReceived,
+			wantNew:  uint64Ptr(48),
+		},
+		{
+			name:     "non numeric value",
+			log:      "Program log: Counter incremented to: abc",
+			wantNone: true,
+		},
+		{
+			name:     "overflow",
+			log:      "Program log: Counter incremented to: 18446744073709551616",
+			wantNone: true,
+		},
+		{
+			name:     "not a program log",
+			log:      "Program CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc invoke [1]",
+			wantNone: true,
+		},
+	}
+
+	parser := NewCounterLogParser(solana.PublicKey{})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actions, err := parser.ParseLogs([]string{tt.log}, accounts)
+			if err != nil {
+				t.Fatalf("ParseLogs() error = %v", err)
+			}
+
+			if tt.wantNone {
+				if len(actions) != 0 {
+					t.Fatalf("ParseLogs() = %d actions, want 0", len(actions))
+				}
+				return
+			}
+
+			if len(actions) != 1 {
+				t.Fatalf("ParseLogs() = %d actions, want 1", len(actions))
+			}
+
+			action := actions[0]
+			if action.Type != tt.wantType {
+				t.Errorf("Type = %v, want %v", action.Type, tt.wantType)
+			}
+			if !action.Counter.Equals(counter) {
+				t.Errorf("Counter = %v, want %v", action.Counter, counter)
+			}
+			assertUint64Ptr(t, "OldValue", action.OldValue, tt.wantOld)
+			assertUint64Ptr(t, "NewValue", action.NewValue, tt.wantNew)
+		})
+	}
+}
+
+func TestCounterLogParser_PaymentAccounts(t *testing.T) {
+	accounts := []solana.PublicKey{{1}, {2}, {3}}
+	parser := NewCounterLogParser(solana.PublicKey{})
+
+	actions, err := parser.ParseLogs([]string{
+		"Program log: Payment of 250 lamports received. Counter incremented to: 3",
+	}, accounts)
+	if err != nil {
+		t.Fatalf("ParseLogs() error = %v", err)
+	}
+	if len(actions) != 1 {
+		t.Fatalf("ParseLogs() = %d actions, want 1", len(actions))
+	}
+
+	action := actions[0]
+	assertUint64Ptr(t, "Payment", action.Payment, uint64Ptr(250))
+	if action.Payer == nil || !action.Payer.Equals(accounts[1]) {
+		t.Errorf("Payer = %v, want %v", action.Payer, accounts[1])
+	}
+	if action.FeeCollector == nil || !action.FeeCollector.Equals(accounts[2]) {
+		t.Errorf("FeeCollector = %v, want %v", action.FeeCollector, accounts[2])
+	}
+}
+
+func assertUint64Ptr(t *testing.T, field string, got, want *uint64) {
+	t.Helper()
+	switch {
+	case want == nil && got != nil:
+		t.Errorf("%s = %d, want nil", field, *got)
+	case want != nil && got == nil:
+		t.Errorf("%s = nil, want %d", field, *want)
+	case want != nil && *got != *want:
+		t.Errorf("%s = %d, want %d", field, *got, *want)
+	}
+}
+
+// benchmarkLogs builds a transaction log of n lines resembling real
+// counter program output: mostly runtime noise with a few counter messages.
+func benchmarkLogs(n int) []string {
+	programID := "CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc"
+	logs := make([]string, 0, n)
+	for i := 0; len(logs) < n; i++ {
+		logs = append(logs,
+			fmt.Sprintf("Program %s invoke [1]", programID),
+			"Program log: Instruction: Increment",
+			fmt.Sprintf("Program log: Counter incremented to: %d", i+1),
+			fmt.Sprintf("Program log: Added %d to counter. New value: %d", i, i*2),
+			fmt.Sprintf("Program log: Payment of %d lamports received. Counter incremented to: %d", 1000+i, i+2),
+			fmt.Sprintf("Program %s consumed %d of 200000 compute units", programID, 1500+i),
+			fmt.Sprintf("Program %s success", programID),
+		)
+	}
+	return logs[:n]
+}
+
+func BenchmarkCounterLogParser_ParseLogs(b *testing.B) {
+	accounts := []solana.PublicKey{{1}, {2}, {3}}
+	parser := NewCounterLogParser(solana.PublicKey{})
+
+	for _, size := range []int{10, 100, 500} {
+		logs := benchmarkLogs(size)
+		b.Run(fmt.Sprintf("lines=%d", size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := parser.ParseLogs(logs, accounts); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkParseUintBytes(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, ok := parseUintBytes("18446744073709551615"); !ok {
+			b.Fatal("parse failed")
+		}
+	}
+}
+
+func TestCounterLogParser_ParseLogsWithInstructions(t *testing.T) {
+	programID := solana.PublicKey{0xc0}
+	feePayer := solana.PublicKey{1}
+	counterPDA := solana.PublicKey{2}
+	authority := solana.PublicKey{3}
+	payer := solana.PublicKey{4}
+	feeCollector := solana.PublicKey{5}
+	otherProgram := solana.PublicKey{0xee}
+
+	initIx := instructionDiscriminator("initialize")
+	paymentIx := instructionDiscriminator("increment_with_payment")
+	instructions := []CounterInstruction{
+		{Accounts: []solana.PublicKey{counterPDA, authority, solana.SystemProgramID}, Data: initIx[:]},
+		{Accounts: []solana.PublicKey{counterPDA, payer, feeCollector}, Data: append(paymentIx[:], 0x01)},
+	}
+	messageAccounts := []solana.PublicKey{feePayer, counterPDA, authority, programID}
+
+	logs := []string{
+		"Program " + programID.String() + " invoke [1]",
+		"Program log: Instruction: Initialize",
+		"Program 11111111111111111111111111111111 invoke [2]",
+		"Program log: Counter reset",
+		"Program 11111111111111111111111111111111 success",
+		"Program log: Counter initialized",
+		"Program " + programID.String() + " success",
+		"Program " + otherProgram.String() + " invoke [1]",
+		"Program log: Counter incremented to: 9",
+		"Program " + otherProgram.String() + " success",
+		"Program " + programID.String() + " invoke [1]",
+		"Program log: Payment of 500 lamports received. Counter incremented to: 1",
+		"Program " + programID.String() + " success",
+	}
+
+	parser := NewCounterLogParser(programID)
+	actions, err := parser.ParseLogsWithInstructions(logs, instructions, messageAccounts)
+	if err != nil {
+		t.Fatalf("ParseLogsWithInstructions() error = %v", err)
+	}
+	if len(actions) != 2 {
+		t.Fatalf("got %d actions, want 2 (logs from other programs must be ignored): %+v", len(actions), actions)
+	}
+
+	init := actions[0]
+	if init.Type != models.EventTypeCounterInitialized {
+		t.Errorf("actions[0].Type = %s", init.Type)
+	}
+	if !init.Counter.Equals(counterPDA) {
+		t.Errorf("Counter = %s, want counter PDA %s (not fee payer)", init.Counter, counterPDA)
+	}
+	if init.Authority == nil || !init.Authority.Equals(authority) {
+		t.Errorf("Authority = %v, want %s", init.Authority, authority)
+	}
+
+	payment := actions[1]
+	if !payment.Counter.Equals(counterPDA) {
+		t.Errorf("payment Counter = %s, want %s", payment.Counter, counterPDA)
+	}
+	if payment.Payer == nil || !payment.Payer.Equals(payer) {
+		t.Errorf("Payer = %v, want %s", payment.Payer, payer)
+	}
+	if payment.FeeCollector == nil || !payment.FeeCollector.Equals(feeCollector) {
+		t.Errorf("FeeCollector = %v, want %s", payment.FeeCollector, feeCollector)
+	}
+}
+
+func TestResolveCounterAccounts_UnknownInstruction(t *testing.T) {
+	counterPDA := solana.PublicKey{7}
+	ix := &CounterInstruction{Accounts: []solana.PublicKey{counterPDA, {8}}, Data: []byte{1, 2, 3, 4, 5, 6, 7, 8}}
+
+	resolved := resolveCounterAccounts(defaultCounterLayouts, ix, []solana.PublicKey{{1}, counterPDA})
+	if !resolved.Counter.Equals(counterPDA) {
+		t.Errorf("Counter = %s, want %s", resolved.Counter, counterPDA)
+	}
+	if resolved.Authority != nil || resolved.Payer != nil {
+		t.Errorf("unexpected roles resolved: %+v", resolved)
+	}
+}
+
+func TestNewCounterLogParserFromIDL(t *testing.T) {
+	programID := solana.PublicKey{0xc0}
+	idl, err := codegen.ReadIDL(strings.NewReader(`{
+		"metadata": {"name": "counter_program"},
+		"instructions": [
+			{
+				"name": "increment_with_payment",
+				"discriminator": [1, 2, 3, 4, 5, 6, 7, 8],
+				"accounts": [
+					{"name": "payer", "writable": true, "signer": true},
+					{"name": "fee_collector", "writable": true},
+					{"name": "counter", "writable": true},
+					{"name": "system_program"}
+				]
+			}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("ReadIDL() error = %v", err)
+	}
+	parser, err := NewCounterLogParserFromIDL(programID, idl)
+	if err != nil {
+		t.Fatalf("NewCounterLogParserFromIDL() error = %v", err)
+	}
+
+	payer, feeCollector, counter := solana.PublicKey{1}, solana.PublicKey{2}, solana.PublicKey{3}
+	actions, err := parser.ParseLogsWithInstructions([]string{
+		fmt.Sprintf("Program %s invoke [1]", programID),
+		"Program log: Payment of 250 lamports received. Counter incremented to: 3",
+		fmt.Sprintf("Program %s success", programID),
+	}, []CounterInstruction{{
+		Accounts: []solana.PublicKey{payer, feeCollector, counter, solana.SystemProgramID},
+		Data:     []byte{1, 2, 3, 4, 5, 6, 7, 8, 250, 0, 0, 0, 0, 0, 0, 0},
+	}}, nil)
+	if err != nil {
+		t.Fatalf("ParseLogsWithInstructions() error = %v", err)
+	}
+	if len(actions) != 1 {
+		t.Fatalf("ParseLogsWithInstructions() = %d actions, want 1", len(actions))
+	}
+	action := actions[0]
+	if !action.Counter.Equals(counter) {
+		t.Errorf("Counter = %s, want %s", action.Counter, counter)
+	}
+	if action.Payer == nil || !action.Payer.Equals(payer) {
+		t.Errorf("Payer = %v, want %s", action.Payer, payer)
+	}
+	if action.FeeCollector == nil || !action.FeeCollector.Equals(feeCollector) {
+		t.Errorf("FeeCollector = %v, want %s", action.FeeCollector, feeCollector)
+	}
+
+	if _, err := NewCounterLogParserFromIDL(programID, &codegen.IDL{}); err == nil {
+		t.Error("NewCounterLogParserFromIDL() expected error for an IDL without counter accounts")
+	}
+}
@@ -0,0 +1,74 @@
+package decoder
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	pkgdecoder "github.com/lugondev/go-indexer-solana-starter/pkg/decoder"
+	"github.com/lugondev/go-indexer-solana-starter/pkg/solana/mock"
+)
+
+// The fixtures in testdata were recorded with "indexer fixtures record"
+// from "indexer loadgen" traffic.
+func TestDecode_Fixtures(t *testing.T) {
+	fixtures, err := mock.LoadDir("testdata")
+	if err != nil {
+		t.Fatalf("LoadDir() error = %v", err)
+	}
+	counterProgram := solana.MustPublicKeyFromBase58("CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc")
+	counterParser := NewCounterLogParser(counterProgram)
+	events := NewEventDecoder()
+
+	got := make(map[models.EventType]int)
+	for _, f := range fixtures {
+		for _, info := range f.Signatures {
+			var tx struct {
+				Meta struct {
+					LogMessages []string `json:"logMessages"`
+				} `json:"meta"`
+			}
+			if err := json.Unmarshal(f.Transactions[info.Signature], &tx); err != nil {
+				t.Fatalf("decode transaction %s: %v", info.Signature, err)
+			}
+			logs := tx.Meta.LogMessages
+
+			if f.Address == counterProgram.String() {
+				actions, err := counterParser.ParseLogs(logs, nil)
+				if err != nil {
+					t.Errorf("ParseLogs(%s) error = %v", info.Signature, err)
+				}
+				for _, a := range actions {
+					got[a.Type]++
+				}
+				continue
+			}
+			for _, data := range pkgdecoder.ParseProgramData(logs) {
+				eventType, _, err := events.DecodeEvent(data)
+				if err != nil {
+					t.Errorf("DecodeEvent(%s) error = %v", info.Signature, err)
+					continue
+				}
+				got[eventType]++
+			}
+		}
+	}
+
+	want := map[models.EventType]int{
+		models.EventTypeCounterIncremented:     3,
+		models.EventTypeCounterAdded:           1,
+		models.EventTypeCounterReset:           1,
+		models.EventTypeCounterPaymentReceived: 1,
+		models.EventTypeTokensMinted:           2,
+		models.EventTypeTokensTransferred:      4,
+	}
+	if len(got) != len(want) {
+		t.Errorf("decoded %v, want %v", got, want)
+	}
+	for eventType, n := range want {
+		if got[eventType] != n {
+			t.Errorf("decoded %d %s, want %d", got[eventType], eventType, n)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package decoder
+
+import (
+	"fmt"
+	"reflect"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/codegen"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// IDLDecoder decodes the events of a program from its Anchor IDL at run
+// time, for programs without generated bindings. Events decode to a
+// models.LogEvent whose fields are named as in the IDL; integers stay
+// integers, public keys are base58 strings, byte strings []byte and enum
+// values the name of their variant.
+type IDLDecoder struct {
+	events map[[8]byte]string
+	types  map[string]*codegen.IDLTypeDef
+}
+
+// NewIDLDecoder checks that every event of idl can be decoded: its fields
+// have types the codegen package supports.
+func NewIDLDecoder(idl *codegen.IDL) (*IDLDecoder, error) {
+	d := &IDLDecoder{
+		events: make(map[[8]byte]string, len(idl.Events)),
+		types:  make(map[string]*codegen.IDLTypeDef, len(idl.Types)),
+	}
+	for n := range idl.Types {
+		d.types[idl.Types[n].Name] = &idl.Types[n]
+	}
+	for _, e := range idl.Events {
+		if err := d.check(&codegen.IDLType{Defined: e.Name}, map[string]bool{}); err != nil {
+			return nil, fmt.Errorf("event %s: %w", e.Name, err)
+		}
+		d.events[e.Discriminator] = e.Name
+	}
+	return d, nil
+}
+
+// check reports types decodeValue cannot decode. seen guards against
+// recursive types.
+func (d *IDLDecoder) check(t *codegen.IDLType, seen map[string]bool) error {
+	switch {
+	case t.Option != nil:
+		return d.check(t.Option, seen)
+	case t.Vec != nil:
+		return d.check(t.Vec, seen)
+	case t.Array != nil:
+		return d.check(t.Array, seen)
+	case t.Defined != "":
+		if seen[t.Defined] {
+			return nil
+		}
+		seen[t.Defined] = true
+		def, ok := d.types[t.Defined]
+		if !ok {
+			return fmt.Errorf("type %s is not defined", t.Defined)
+		}
+		switch def.Type.Kind {
+		case "struct":
+			for _, f := range def.Type.Fields {
+				if err := d.check(&f.Type, seen); err != nil {
+					return fmt.Errorf("%s.%s: %w", def.Name, f.Name, err)
+				}
+			}
+		case "enum":
+			for _, v := range def.Type.Variants {
+				if len(v.Fields) > 0 && string(v.Fields) != "null" {
+					return fmt.Errorf("enum %s: variant %s has fields, which are not supported", def.Name, v.Name)
+				}
+			}
+		default:
+			return fmt.Errorf("type %s is a %q, which is not supported", def.Name, def.Type.Kind)
+		}
+		return nil
+	}
+	switch t.Primitive {
+	case "string", "bytes", "pubkey", "publicKey":
+		return nil
+	}
+	if _, ok := primitiveTypes[t.Primitive]; ok {
+		return nil
+	}
+	return fmt.Errorf("type %q is not supported", t.Primitive)
+}
+
+// DecodeEvent implements Decoder.
+func (d *IDLDecoder) DecodeEvent(data []byte) (models.EventType, interface{}, error) {
+	if len(data) < 8 {
+		return "", nil, ErrUnknownEvent
+	}
+	name, ok := d.events[[8]byte(data[:8])]
+	if !ok {
+		return "", nil, ErrUnknownEvent
+	}
+	eventType := models.EventType(name)
+	value, err := d.decodeValue(bin.NewBorshDecoder(data[8:]), &codegen.IDLType{Defined: name})
+	if err != nil {
+		return eventType, nil, fmt.Errorf("decode %s: %w", name, err)
+	}
+	return eventType, models.LogEvent{Fields: value.(map[string]interface{})}, nil
+}
+
+func (d *IDLDecoder) decodeValue(decoder *bin.Decoder, t *codegen.IDLType) (interface{}, error) {
+	switch {
+	case t.Option != nil:
+		some, err := decoder.ReadOption()
+		if err != nil || !some {
+			return nil, err
+		}
+		return d.decodeValue(decoder, t.Option)
+	case t.Vec != nil:
+		n, err := decodeLength(decoder)
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeValues(decoder, t.Vec, n)
+	case t.Array != nil:
+		return d.decodeValues(decoder, t.Array, t.Len)
+	case t.Defined != "":
+		def := d.types[t.Defined]
+		if def.Type.Kind == "enum" {
+			variant, err := decoder.ReadUint8()
+			if err != nil {
+				return nil, err
+			}
+			if int(variant) >= len(def.Type.Variants) {
+				return nil, fmt.Errorf("invalid %s variant %d", def.Name, variant)
+			}
+			return def.Type.Variants[variant].Name, nil
+		}
+		fields := make(map[string]interface{}, len(def.Type.Fields))
+		for _, f := range def.Type.Fields {
+			value, err := d.decodeValue(decoder, &f.Type)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", f.Name, err)
+			}
+			fields[f.Name] = value
+		}
+		return fields, nil
+	}
+	return decodePrimitive(decoder, t.Primitive)
+}
+
+func (d *IDLDecoder) decodeValues(decoder *bin.Decoder, t *codegen.IDLType, n int) ([]interface{}, error) {
+	values := make([]interface{}, n)
+	for i := range values {
+		value, err := d.decodeValue(decoder, t)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// primitiveTypes are the Go types of the fixed size IDL primitives.
+var primitiveTypes = map[string]reflect.Type{
+	"bool": reflect.TypeFor[bool](),
+	"u8":   reflect.TypeFor[uint8](),
+	"i8":   reflect.TypeFor[int8](),
+	"u16":  reflect.TypeFor[uint16](),
+	"i16":  reflect.TypeFor[int16](),
+	"u32":  reflect.TypeFor[uint32](),
+	"i32":  reflect.TypeFor[int32](),
+	"u64":  reflect.TypeFor[uint64](),
+	"i64":  reflect.TypeFor[int64](),
+	"f32":  reflect.TypeFor[float32](),
+	"f64":  reflect.TypeFor[float64](),
+}
+
+func decodePrimitive(decoder *bin.Decoder, primitive string) (interface{}, error) {
+	switch primitive {
+	case "string":
+		return decodeString(decoder)
+	case "bytes":
+		n, err := decodeLength(decoder)
+		if err != nil {
+			return nil, err
+		}
+		return decoder.ReadNBytes(n)
+	case "pubkey", "publicKey":
+		var key solana.PublicKey
+		if err := decoder.Decode(&key); err != nil {
+			return nil, err
+		}
+		return key.String(), nil
+	}
+	t, ok := primitiveTypes[primitive]
+	if !ok {
+		return nil, fmt.Errorf("type %q is not supported", primitive)
+	}
+	v := reflect.New(t)
+	if err := decoder.Decode(v.Interface()); err != nil {
+		return nil, err
+	}
+	return v.Elem().Interface(), nil
+}
+
+// decodeLength decodes the u32 length of a Borsh vector.
+func decodeLength(decoder *bin.Decoder) (int, error) {
+	n, err := decoder.ReadUint32(bin.LE)
+	if err != nil {
+		return 0, err
+	}
+	if int(n) > decoder.Remaining() {
+		return 0, fmt.Errorf("length %d exceeds the remaining %d bytes", n, decoder.Remaining())
+	}
+	return int(n), nil
+}
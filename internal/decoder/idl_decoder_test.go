@@ -0,0 +1,106 @@
+package decoder
+
+import (
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/codegen"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+const vaultIDL = `{
+  "address": "CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc",
+  "metadata": {"name": "vault"},
+  "events": [{"name": "Deposited", "discriminator": [1, 2, 3, 4, 5, 6, 7, 8]}],
+  "types": [
+    {"name": "Deposited", "type": {"kind": "struct", "fields": [
+      {"name": "vault", "type": "pubkey"},
+      {"name": "amount", "type": "u64"},
+      {"name": "memo", "type": {"option": "string"}},
+      {"name": "kind", "type": {"defined": {"name": "Kind"}}},
+      {"name": "fees", "type": {"vec": "u16"}},
+      {"name": "tag", "type": {"array": ["u8", 2]}}
+    ]}},
+    {"name": "Kind", "type": {"kind": "enum", "variants": [{"name": "Cash"}, {"name": "Card"}]}}
+  ]
+}`
+
+func TestIDLDecoder_DecodeEvent(t *testing.T) {
+	idl, err := codegen.ReadIDL(strings.NewReader(vaultIDL))
+	if err != nil {
+		t.Fatalf("ReadIDL() error = %v", err)
+	}
+	d, err := NewIDLDecoder(idl)
+	if err != nil {
+		t.Fatalf("NewIDLDecoder() error = %v", err)
+	}
+
+	vault := solana.MustPublicKeyFromBase58("gARh1g6reuvsAHB7DXqiuYzzyiJeoiJmtmCpV8Y5uWC")
+	data := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	data = append(data, vault[:]...)
+	data = binary.LittleEndian.AppendUint64(data, 1<<63)
+	data = append(data, 0) // memo: none
+	data = append(data, 1) // kind: Card
+	data = append(data, 2, 0, 0, 0, 10, 0, 20, 0)
+	data = append(data, 7, 9) // tag
+
+	eventType, event, err := d.DecodeEvent(data)
+	if err != nil {
+		t.Fatalf("DecodeEvent() error = %v", err)
+	}
+	if eventType != "Deposited" {
+		t.Errorf("DecodeEvent() type = %s, want Deposited", eventType)
+	}
+	want := models.LogEvent{Fields: map[string]interface{}{
+		"vault":  vault.String(),
+		"amount": uint64(1 << 63),
+		"memo":   nil,
+		"kind":   "Card",
+		"fees":   []interface{}{uint16(10), uint16(20)},
+		"tag":    []interface{}{uint8(7), uint8(9)},
+	}}
+	if !reflect.DeepEqual(event, want) {
+		t.Errorf("DecodeEvent() = %#v, want %#v", event, want)
+	}
+
+	if _, _, err := d.DecodeEvent(data[:20]); err == nil || errors.Is(err, ErrUnknownEvent) {
+		t.Errorf("DecodeEvent() of a truncated event error = %v, want a decode error", err)
+	}
+	if _, _, err := d.DecodeEvent([]byte("unknown!")); !errors.Is(err, ErrUnknownEvent) {
+		t.Errorf("DecodeEvent() of an unknown event error = %v, want ErrUnknownEvent", err)
+	}
+}
+
+func TestNewIDLDecoder_Unsupported(t *testing.T) {
+	tests := []struct {
+		name  string
+		field string
+		types string
+	}{
+		{name: "undefined type", field: `{"defined": "Missing"}`},
+		{name: "unsupported primitive", field: `"u128"`},
+		{
+			name:  "enum with fields",
+			field: `{"defined": "Shape"}`,
+			types: `, {"name": "Shape", "type": {"kind": "enum", "variants": [{"name": "Circle", "fields": ["u8"]}]}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idl, err := codegen.ReadIDL(strings.NewReader(`{
+  "events": [{"name": "E", "discriminator": [1, 1, 1, 1, 1, 1, 1, 1]}],
+  "types": [{"name": "E", "type": {"kind": "struct", "fields": [{"name": "f", "type": ` + tt.field + `}]}}` + tt.types + `]
+}`))
+			if err != nil {
+				t.Fatalf("ReadIDL() error = %v", err)
+			}
+			if _, err := NewIDLDecoder(idl); err == nil {
+				t.Error("NewIDLDecoder() error = nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package decoder
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// LogGrammarFile is the YAML file of log grammars LOG_GRAMMAR_FILE points
+// to.
+type LogGrammarFile struct {
+	Programs []LogGrammarSpec `json:"programs" yaml:"programs"`
+}
+
+// LogGrammarSpec describes how to turn the log lines of one program into
+// events.
+type LogGrammarSpec struct {
+	// Name identifies the program in logs and checkpoints.
+	Name      string `json:"name" yaml:"name"`
+	ProgramID string `json:"program_id" yaml:"program_id"`
+	// Events are tried in order against every "Program log: " message the
+	// program writes; the first match becomes an event.
+	Events []LogEventSpec `json:"events" yaml:"events"`
+}
+
+type LogEventSpec struct {
+	Event string `json:"event" yaml:"event"`
+	// Pattern is a regular expression matched against the message.
+	Pattern string                  `json:"pattern" yaml:"pattern"`
+	Fields  map[string]LogFieldSpec `json:"fields" yaml:"fields"`
+}
+
+// LogFieldSpec takes a field from exactly one of a capture group of the
+// pattern, by name or number, an account of the instruction that wrote the
+// log, by index, or a constant value.
+type LogFieldSpec struct {
+	Group   string `json:"group,omitempty" yaml:"group,omitempty"`
+	Account *int   `json:"account,omitempty" yaml:"account,omitempty"`
+	Value   string `json:"value,omitempty" yaml:"value,omitempty"`
+	// Type is string (the default), u64, i64, bool or pubkey.
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+}
+
+var (
+	logGrammarName = regexp.MustCompile(`^[a-z0-9_-]{1,64}$`)
+	// Event and field names become collection and document field names.
+	logEventName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]{0,63}$`)
+	logFieldName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,63}$`)
+)
+
+// LogGrammar matches the log lines of one program against the patterns of
+// a LogGrammarSpec.
+type LogGrammar struct {
+	Name    string
+	Program solana.PublicKey
+	rules   []logRule
+}
+
+type logRule struct {
+	event   models.EventType
+	pattern *regexp.Regexp
+	fields  []logField
+}
+
+type logField struct {
+	name    string
+	group   int
+	account int
+	value   string
+	typ     string
+}
+
+// LogMatch is an event found by a LogGrammar.
+type LogMatch struct {
+	Event  models.EventType
+	Fields map[string]interface{}
+}
+
+// NewLogGrammar compiles spec, checking every pattern and field.
+func NewLogGrammar(spec LogGrammarSpec) (*LogGrammar, error) {
+	if !logGrammarName.MatchString(spec.Name) {
+		return nil, fmt.Errorf("name %q must be 1-64 lowercase letters, digits, '-' or '_'", spec.Name)
+	}
+	program, err := solana.PublicKeyFromBase58(spec.ProgramID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: program_id: %w", spec.Name, err)
+	}
+	if len(spec.Events) == 0 {
+		return nil, fmt.Errorf("%s: no events", spec.Name)
+	}
+
+	g := &LogGrammar{Name: spec.Name, Program: program}
+	for n, event := range spec.Events {
+		rule, err := newLogRule(event)
+		if err != nil {
+			return nil, fmt.Errorf("%s: event %d: %w", spec.Name, n+1, err)
+		}
+		g.rules = append(g.rules, rule)
+	}
+	return g, nil
+}
+
+func newLogRule(spec LogEventSpec) (logRule, error) {
+	if !logEventName.MatchString(spec.Event) {
+		return logRule{}, fmt.Errorf("event %q must be a letter followed by up to 63 letters, digits or '_'", spec.Event)
+	}
+	if models.EventType(spec.Event).Known() {
+		return logRule{}, fmt.Errorf("%s is a built-in event type", spec.Event)
+	}
+	if spec.Pattern == "" {
+		return logRule{}, fmt.Errorf("%s: pattern is required", spec.Event)
+	}
+	pattern, err := regexp.Compile(spec.Pattern)
+	if err != nil {
+		return logRule{}, fmt.Errorf("%s: pattern: %w", spec.Event, err)
+	}
+
+	rule := logRule{event: models.EventType(spec.Event), pattern: pattern}
+	for name, field := range spec.Fields {
+		f, err := newLogField(name, field, pattern)
+		if err != nil {
+			return logRule{}, fmt.Errorf("%s: field %s: %w", spec.Event, name, err)
+		}
+		rule.fields = append(rule.fields, f)
+	}
+	return rule, nil
+}
+
+func newLogField(name string, spec LogFieldSpec, pattern *regexp.Regexp) (logField, error) {
+	f := logField{name: name, group: -1, account: -1, value: spec.Value, typ: spec.Type}
+	if !logFieldName.MatchString(name) {
+		return f, fmt.Errorf("name must be letters, digits or '_', not starting with a digit")
+	}
+	if f.typ == "" {
+		f.typ = "string"
+	}
+	switch f.typ {
+	case "string", "u64", "i64", "bool", "pubkey":
+	default:
+		return f, fmt.Errorf("type %q must be string, u64, i64, bool or pubkey", spec.Type)
+	}
+
+	sources := 0
+	if spec.Group != "" {
+		sources++
+		f.group = pattern.SubexpIndex(spec.Group)
+		if n, err := strconv.Atoi(spec.Group); err == nil && n >= 0 && n <= pattern.NumSubexp() {
+			f.group = n
+		}
+		if f.group < 0 {
+			return f, fmt.Errorf("pattern has no group %q", spec.Group)
+		}
+	}
+	if spec.Account != nil {
+		sources++
+		if *spec.Account < 0 {
+			return f, fmt.Errorf("account index must not be negative")
+		}
+		f.account = *spec.Account
+	}
+	if spec.Value != "" {
+		sources++
+		if _, err := f.convert(spec.Value); err != nil {
+			return f, err
+		}
+	}
+	if sources != 1 {
+		return f, fmt.Errorf("set exactly one of group, account or value")
+	}
+	return f, nil
+}
+
+// convert parses a captured or constant value as the field's type.
+func (f logField) convert(s string) (interface{}, error) {
+	switch f.typ {
+	case "u64":
+		return strconv.ParseUint(s, 10, 64)
+	case "i64":
+		return strconv.ParseInt(s, 10, 64)
+	case "bool":
+		return strconv.ParseBool(s)
+	case "pubkey":
+		key, err := solana.PublicKeyFromBase58(s)
+		if err != nil {
+			return nil, err
+		}
+		return key.String(), nil
+	}
+	return s, nil
+}
+
+// ParseLogs returns the events the program wrote to logs. instructions
+// are the program's instructions in execution order, as for
+// CounterLogParser.ParseLogsWithInstructions; account fields of a log
+// written outside them are left out. Messages matching a pattern whose
+// fields do not convert are skipped and returned as errors.
+func (g *LogGrammar) ParseLogs(logs []string, instructions []CounterInstruction) ([]LogMatch, []error) {
+	var (
+		matches []LogMatch
+		errs    []error
+	)
+	program := g.Program.String()
+	walkProgramLogs(logs, program, instructions, func(msg string, frame *logFrame) {
+		if frame == nil || frame.program != program {
+			return
+		}
+		var accounts []solana.PublicKey
+		if frame.instruction != nil {
+			accounts = frame.instruction.Accounts
+		}
+		for _, rule := range g.rules {
+			groups := rule.pattern.FindStringSubmatch(msg)
+			if groups == nil {
+				continue
+			}
+			match, err := rule.match(groups, accounts)
+			if err != nil {
+				errs = append(errs, err)
+			} else {
+				matches = append(matches, *match)
+			}
+			return
+		}
+	})
+	return matches, errs
+}
+
+func (r logRule) match(groups []string, accounts []solana.PublicKey) (*LogMatch, error) {
+	match := &LogMatch{Event: r.event, Fields: make(map[string]interface{}, len(r.fields))}
+	for _, f := range r.fields {
+		var raw string
+		switch {
+		case f.group >= 0:
+			raw = groups[f.group]
+		case f.account >= 0:
+			if f.account >= len(accounts) {
+				continue
+			}
+			match.Fields[f.name] = accounts[f.account].String()
+			continue
+		default:
+			raw = f.value
+		}
+		value, err := f.convert(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s field %s: %w", r.event, f.name, err)
+		}
+		match.Fields[f.name] = value
+	}
+	return match, nil
+}
+
+// ReadLogGrammars decodes a YAML log grammar file and compiles its
+// grammars.
+func ReadLogGrammars(r io.Reader) ([]*LogGrammar, error) {
+	var file LogGrammarFile
+	if err := config.DecodeYAML(r, &file); err != nil {
+		return nil, fmt.Errorf("decode log grammars: %w", err)
+	}
+
+	var grammars []*LogGrammar
+	seen := make(map[solana.PublicKey]bool)
+	for _, spec := range file.Programs {
+		g, err := NewLogGrammar(spec)
+		if err != nil {
+			return nil, fmt.Errorf("log grammar: %w", err)
+		}
+		if seen[g.Program] {
+			return nil, fmt.Errorf("log grammar: program %s has two grammars", g.Program)
+		}
+		seen[g.Program] = true
+		grammars = append(grammars, g)
+	}
+	return grammars, nil
+}
+
+// ReadLogGrammarFile reads the grammars of the file at path.
+func ReadLogGrammarFile(path string) ([]*LogGrammar, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open log grammars: %w", err)
+	}
+	defer f.Close()
+	return ReadLogGrammars(f)
+}
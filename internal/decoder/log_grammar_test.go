@@ -0,0 +1,141 @@
+package decoder
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+const testVault = "4Nd1mBQtrMJVYVfKf2PJy9NZUZdTAsp7D4xWLs4gDB4T"
+
+const testGrammars = `
+programs:
+  - name: vault
+    program_id: ` + testVault + `
+    events:
+      - event: VaultDeposit
+        pattern: '^Deposited (?P<amount>\d+) into (\w+)$'
+        fields:
+          amount:
+            group: amount
+            type: u64
+          vault:
+            group: 2
+          depositor:
+            account: 1
+          kind:
+            value: deposit
+      - event: VaultPaused
+        pattern: '^Paused: (true|false)$'
+        fields:
+          paused:
+            group: 1
+            type: bool
+`
+
+func TestLogGrammar_ParseLogs(t *testing.T) {
+	grammars, err := ReadLogGrammars(strings.NewReader(testGrammars))
+	if err != nil {
+		t.Fatalf("ReadLogGrammars() error = %v", err)
+	}
+	if len(grammars) != 1 || grammars[0].Name != "vault" {
+		t.Fatalf("ReadLogGrammars() = %d grammars, want vault", len(grammars))
+	}
+	g := grammars[0]
+
+	depositor := solana.PublicKey{2}
+	instructions := []CounterInstruction{{Accounts: []solana.PublicKey{{1}, depositor}}}
+	logs := []string{
+		"Program " + testVault + " invoke [1]",
+		"Program log: Deposited 250 into main",
+		"Program log: Something else",
+		"Program Other111111111111111111111111111111111111 invoke [2]",
+		"Program log: Paused: true",
+		"Program Other111111111111111111111111111111111111 success",
+		"Program log: Paused: true",
+		"Program " + testVault + " success",
+		"Program log: Paused: false",
+	}
+
+	matches, errs := g.ParseLogs(logs, instructions)
+	if len(errs) != 0 {
+		t.Fatalf("ParseLogs() errors = %v", errs)
+	}
+	want := []LogMatch{
+		{Event: "VaultDeposit", Fields: map[string]interface{}{
+			"amount": uint64(250), "vault": "main", "depositor": depositor.String(), "kind": "deposit",
+		}},
+		{Event: "VaultPaused", Fields: map[string]interface{}{"paused": true}},
+	}
+	if !reflect.DeepEqual(matches, want) {
+		t.Errorf("ParseLogs() = %v, want %v", matches, want)
+	}
+
+	// Outside a tracked instruction account fields are left out, and a
+	// capture that does not convert is an error.
+	matches, errs = g.ParseLogs([]string{
+		"Program " + testVault + " invoke [1]",
+		"Program log: Deposited 99999999999999999999 into main",
+		"Program log: Deposited 5 into main",
+		"Program " + testVault + " success",
+	}, nil)
+	if len(errs) != 1 || len(matches) != 1 {
+		t.Fatalf("ParseLogs() = %v, %v, want 1 match and 1 error", matches, errs)
+	}
+	if _, ok := matches[0].Fields["depositor"]; ok {
+		t.Errorf("ParseLogs() without instructions set depositor")
+	}
+}
+
+func TestReadLogGrammars_Invalid(t *testing.T) {
+	grammar := func(event string) string {
+		return "programs:\n  - name: vault\n    program_id: " + testVault + "\n    events:\n" + event
+	}
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{name: "bad name", yaml: "programs:\n  - name: Vault\n    program_id: " + testVault, wantErr: "name"},
+		{name: "bad program", yaml: "programs:\n  - name: vault\n    program_id: nope", wantErr: "program_id"},
+		{name: "unknown key", yaml: "programs:\n  - name: vault\n    program: x", wantErr: "field program not found"},
+		{name: "no events", yaml: grammar(""), wantErr: "no events"},
+		{name: "built-in event", yaml: grammar("      - event: " + string(models.EventTypeCounterReset) + "\n        pattern: x\n"), wantErr: "built-in"},
+		{name: "bad pattern", yaml: grammar("      - event: E\n        pattern: '('\n"), wantErr: "pattern"},
+		{name: "missing group", yaml: grammar("      - event: E\n        pattern: x\n        fields:\n          f:\n            group: v\n"), wantErr: "no group"},
+		{name: "two sources", yaml: grammar("      - event: E\n        pattern: (x)\n        fields:\n          f:\n            group: 1\n            account: 0\n"), wantErr: "exactly one"},
+		{name: "bad type", yaml: grammar("      - event: E\n        pattern: x\n        fields:\n          f:\n            value: 1\n            type: u128\n"), wantErr: "type"},
+		{name: "bad constant", yaml: grammar("      - event: E\n        pattern: x\n        fields:\n          f:\n            value: x\n            type: u64\n"), wantErr: "invalid syntax"},
+		{name: "bad field name", yaml: grammar("      - event: E\n        pattern: x\n        fields:\n          $f:\n            value: x\n"), wantErr: "field $f"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadLogGrammars(strings.NewReader(tt.yaml))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ReadLogGrammars() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadLogGrammarFile_Example(t *testing.T) {
+	grammars, err := ReadLogGrammarFile("../../idl/grammars/counter.yaml")
+	if err != nil {
+		t.Fatalf("ReadLogGrammarFile() error = %v", err)
+	}
+	counter := solana.PublicKey{9}
+	matches, errs := grammars[0].ParseLogs([]string{
+		"Program CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc invoke [1]",
+		"Program log: Added 5 to counter. New value: 12",
+		"Program CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc success",
+	}, []CounterInstruction{{Accounts: []solana.PublicKey{counter}}})
+	want := []LogMatch{{Event: "CounterLogAdded", Fields: map[string]interface{}{
+		"counter": counter.String(), "added_value": uint64(5), "new_value": uint64(12),
+	}}}
+	if len(errs) != 0 || !reflect.DeepEqual(matches, want) {
+		t.Errorf("ParseLogs() = %v, %v, want %v", matches, errs, want)
+	}
+}
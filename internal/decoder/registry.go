@@ -0,0 +1,42 @@
+package decoder
+
+import (
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	pkgdecoder "github.com/lugondev/go-indexer-solana-starter/pkg/decoder"
+)
+
+// Decoder decodes the events a program emits in its "Program data:" logs
+// or with emit_cpi!, as EventDecoder does for the starter program. It
+// returns ErrUnknownEvent for data it does not know.
+type Decoder interface {
+	DecodeEvent(data []byte) (models.EventType, interface{}, error)
+}
+
+// DecoderFunc adapts a function to a Decoder.
+type DecoderFunc func(data []byte) (models.EventType, interface{}, error)
+
+func (f DecoderFunc) DecodeEvent(data []byte) (models.EventType, interface{}, error) {
+	return f(data)
+}
+
+// Registration is the decoder of a program other than the built-in ones.
+type Registration struct {
+	Program solana.PublicKey
+	Decoder Decoder
+}
+
+// Registered returns the decoders registered with pkg/decoder, ordered by
+// program.
+func Registered() []Registration {
+	registered := pkgdecoder.Registered()
+	registrations := make([]Registration, len(registered))
+	for n, r := range registered {
+		d := r.Decoder
+		registrations[n] = Registration{Program: r.Program, Decoder: DecoderFunc(func(data []byte) (models.EventType, interface{}, error) {
+			eventType, event, err := d.DecodeEvent(data)
+			return models.EventType(eventType), event, err
+		})}
+	}
+	return registrations
+}
@@ -0,0 +1,151 @@
+package decoder
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// Instruction tags shared by the SPL Token and Token-2022 programs.
+const (
+	tokenInitializeAccount  = 1
+	tokenTransfer           = 3
+	tokenMintTo             = 7
+	tokenBurn               = 8
+	tokenTransferChecked    = 12
+	tokenMintToChecked      = 14
+	tokenBurnChecked        = 15
+	tokenInitializeAccount2 = 16
+	tokenInitializeAccount3 = 18
+)
+
+// TokenAccount is what a transaction's token balances tell about a token
+// account.
+type TokenAccount struct {
+	Mint     solana.PublicKey
+	Owner    solana.PublicKey
+	Decimals uint8
+}
+
+// TokenEvent is an event decoded from an SPL Token instruction. Event is
+// the event model by value, as the processor expects it.
+type TokenEvent struct {
+	Type  models.EventType
+	Mint  solana.PublicKey
+	Event interface{}
+}
+
+// DecodeTokenInstruction decodes the Transfer, MintTo, Burn and
+// InitializeAccount instructions of the SPL Token and Token-2022 programs,
+// checked variants included, and returns nil for any other instruction.
+// accounts fills in what the instruction leaves out: the mint of a plain
+// Transfer, the owners of token accounts and the decimals of unchecked
+// amounts.
+func DecodeTokenInstruction(ix CounterInstruction, accounts map[solana.PublicKey]TokenAccount) (*TokenEvent, error) {
+	if len(ix.Data) == 0 {
+		return nil, nil
+	}
+	keys := ix.Accounts
+	switch tag := ix.Data[0]; tag {
+	case tokenTransfer, tokenTransferChecked:
+		amount, decimals, err := tokenAmount(ix.Data, tag == tokenTransferChecked)
+		if err != nil {
+			return nil, fmt.Errorf("transfer: %w", err)
+		}
+		source, mint, destination, authority := tokenKey(keys, 0), tokenKey(keys, 1), tokenKey(keys, 2), tokenKey(keys, 3)
+		if tag == tokenTransfer {
+			mint, destination, authority = accounts[source].Mint, tokenKey(keys, 1), tokenKey(keys, 2)
+			if mint.IsZero() {
+				mint = accounts[destination].Mint
+			}
+		}
+		event, err := models.NewSplTokenTransferEvent(mint, source, destination, authority, amount)
+		if err != nil {
+			return nil, err
+		}
+		event.SourceOwner, event.DestinationOwner = accounts[source].Owner, accounts[destination].Owner
+		event.Decimals = tokenDecimals(decimals, accounts[source])
+		return &TokenEvent{Type: models.EventTypeSplTokenTransfer, Mint: mint, Event: *event}, nil
+
+	case tokenMintTo, tokenMintToChecked:
+		amount, decimals, err := tokenAmount(ix.Data, tag == tokenMintToChecked)
+		if err != nil {
+			return nil, fmt.Errorf("mint to: %w", err)
+		}
+		mint, account := tokenKey(keys, 0), tokenKey(keys, 1)
+		event, err := models.NewSplTokenMintToEvent(mint, account, tokenKey(keys, 2), amount)
+		if err != nil {
+			return nil, err
+		}
+		event.Owner = accounts[account].Owner
+		event.Decimals = tokenDecimals(decimals, accounts[account])
+		return &TokenEvent{Type: models.EventTypeSplTokenMintTo, Mint: mint, Event: *event}, nil
+
+	case tokenBurn, tokenBurnChecked:
+		amount, decimals, err := tokenAmount(ix.Data, tag == tokenBurnChecked)
+		if err != nil {
+			return nil, fmt.Errorf("burn: %w", err)
+		}
+		account, mint := tokenKey(keys, 0), tokenKey(keys, 1)
+		event, err := models.NewSplTokenBurnEvent(mint, account, tokenKey(keys, 2), amount)
+		if err != nil {
+			return nil, err
+		}
+		event.Owner = accounts[account].Owner
+		event.Decimals = tokenDecimals(decimals, accounts[account])
+		return &TokenEvent{Type: models.EventTypeSplTokenBurn, Mint: mint, Event: *event}, nil
+
+	case tokenInitializeAccount, tokenInitializeAccount2, tokenInitializeAccount3:
+		account, mint := tokenKey(keys, 0), tokenKey(keys, 1)
+		// InitializeAccount passes the owner as an account, the later
+		// variants in the instruction data.
+		owner := tokenKey(keys, 2)
+		if tag != tokenInitializeAccount {
+			if len(ix.Data) < 1+solana.PublicKeyLength {
+				return nil, fmt.Errorf("initialize account: data is %d bytes, want %d", len(ix.Data), 1+solana.PublicKeyLength)
+			}
+			owner = solana.PublicKeyFromBytes(ix.Data[1 : 1+solana.PublicKeyLength])
+		}
+		event, err := models.NewSplTokenAccountInitializedEvent(mint, account, owner)
+		if err != nil {
+			return nil, err
+		}
+		return &TokenEvent{Type: models.EventTypeSplTokenAccountInitialized, Mint: mint, Event: *event}, nil
+	}
+	return nil, nil
+}
+
+// tokenAmount reads the u64 amount following the tag and, for checked
+// instructions, the decimals after it.
+func tokenAmount(data []byte, checked bool) (uint64, *uint8, error) {
+	want := 9
+	if checked {
+		want = 10
+	}
+	if len(data) < want {
+		return 0, nil, fmt.Errorf("data is %d bytes, want %d", len(data), want)
+	}
+	amount := binary.LittleEndian.Uint64(data[1:9])
+	if !checked {
+		return amount, nil, nil
+	}
+	return amount, &data[9], nil
+}
+
+func tokenDecimals(checked *uint8, account TokenAccount) uint8 {
+	if checked != nil {
+		return *checked
+	}
+	return account.Decimals
+}
+
+// tokenKey returns the account at n, or the zero key the event
+// constructors reject when the instruction has fewer accounts.
+func tokenKey(keys []solana.PublicKey, n int) solana.PublicKey {
+	if n < len(keys) {
+		return keys[n]
+	}
+	return solana.PublicKey{}
+}
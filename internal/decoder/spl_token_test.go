@@ -0,0 +1,114 @@
+package decoder
+
+import (
+	"encoding/binary"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+func tokenData(tag byte, amount uint64, decimals ...byte) []byte {
+	data := binary.LittleEndian.AppendUint64([]byte{tag}, amount)
+	return append(data, decimals...)
+}
+
+func TestDecodeTokenInstruction(t *testing.T) {
+	mint, authority := solana.PublicKey{1}, solana.PublicKey{2}
+	source, destination := solana.PublicKey{3}, solana.PublicKey{4}
+	alice, bob := solana.PublicKey{5}, solana.PublicKey{6}
+	accounts := map[solana.PublicKey]TokenAccount{
+		source:      {Mint: mint, Owner: alice, Decimals: 6},
+		destination: {Mint: mint, Owner: bob, Decimals: 6},
+	}
+
+	tests := []struct {
+		name string
+		ix   CounterInstruction
+		want *TokenEvent
+	}{
+		{
+			name: "transfer",
+			ix:   CounterInstruction{Accounts: []solana.PublicKey{source, destination, authority}, Data: tokenData(3, 250)},
+			want: &TokenEvent{Type: models.EventTypeSplTokenTransfer, Mint: mint, Event: models.SplTokenTransferEvent{
+				Mint: mint, Source: source, Destination: destination, Authority: authority,
+				SourceOwner: alice, DestinationOwner: bob, Amount: 250, Decimals: 6,
+			}},
+		},
+		{
+			name: "transfer checked",
+			ix:   CounterInstruction{Accounts: []solana.PublicKey{source, mint, destination, authority}, Data: tokenData(12, 250, 9)},
+			want: &TokenEvent{Type: models.EventTypeSplTokenTransfer, Mint: mint, Event: models.SplTokenTransferEvent{
+				Mint: mint, Source: source, Destination: destination, Authority: authority,
+				SourceOwner: alice, DestinationOwner: bob, Amount: 250, Decimals: 9,
+			}},
+		},
+		{
+			name: "mint to",
+			ix:   CounterInstruction{Accounts: []solana.PublicKey{mint, destination, authority}, Data: tokenData(7, 1000)},
+			want: &TokenEvent{Type: models.EventTypeSplTokenMintTo, Mint: mint, Event: models.SplTokenMintToEvent{
+				Mint: mint, Account: destination, Authority: authority, Owner: bob, Amount: 1000, Decimals: 6,
+			}},
+		},
+		{
+			name: "burn checked",
+			ix:   CounterInstruction{Accounts: []solana.PublicKey{source, mint, alice}, Data: tokenData(15, 10, 6)},
+			want: &TokenEvent{Type: models.EventTypeSplTokenBurn, Mint: mint, Event: models.SplTokenBurnEvent{
+				Mint: mint, Account: source, Authority: alice, Owner: alice, Amount: 10, Decimals: 6,
+			}},
+		},
+		{
+			name: "initialize account",
+			ix:   CounterInstruction{Accounts: []solana.PublicKey{destination, mint, bob, {9}}, Data: []byte{1}},
+			want: &TokenEvent{Type: models.EventTypeSplTokenAccountInitialized, Mint: mint, Event: models.SplTokenAccountInitializedEvent{
+				Mint: mint, Account: destination, Owner: bob,
+			}},
+		},
+		{
+			name: "initialize account 3",
+			ix:   CounterInstruction{Accounts: []solana.PublicKey{destination, mint}, Data: append([]byte{18}, bob[:]...)},
+			want: &TokenEvent{Type: models.EventTypeSplTokenAccountInitialized, Mint: mint, Event: models.SplTokenAccountInitializedEvent{
+				Mint: mint, Account: destination, Owner: bob,
+			}},
+		},
+		{
+			name: "other instruction",
+			ix:   CounterInstruction{Accounts: []solana.PublicKey{source, authority}, Data: tokenData(4, 1)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeTokenInstruction(tt.ix, accounts)
+			if err != nil {
+				t.Fatalf("DecodeTokenInstruction() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeTokenInstruction() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeTokenInstruction_Invalid(t *testing.T) {
+	source, destination, authority := solana.PublicKey{3}, solana.PublicKey{4}, solana.PublicKey{2}
+	tests := []struct {
+		name    string
+		ix      CounterInstruction
+		wantErr string
+	}{
+		{name: "short amount", ix: CounterInstruction{Data: []byte{7, 1, 2}}, wantErr: "data is 3 bytes"},
+		{name: "missing decimals", ix: CounterInstruction{Data: tokenData(12, 1)}, wantErr: "want 10"},
+		{name: "unknown mint", ix: CounterInstruction{Accounts: []solana.PublicKey{source, destination, authority}, Data: tokenData(3, 1)}, wantErr: "mint"},
+		{name: "missing owner", ix: CounterInstruction{Accounts: []solana.PublicKey{source, {1}}, Data: []byte{16, 1}}, wantErr: "data is 2 bytes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeTokenInstruction(tt.ix, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("DecodeTokenInstruction() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package decoder
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// System Program instruction tags, as little-endian u32.
+const (
+	systemTransfer         = 2
+	systemTransferWithSeed = 11
+)
+
+// SystemTransfer is a lamport transfer by the System Program.
+type SystemTransfer struct {
+	From     solana.PublicKey
+	To       solana.PublicKey
+	Lamports uint64
+}
+
+// DecodeSystemTransfer decodes a System Program Transfer or
+// TransferWithSeed instruction and returns nil for any other instruction.
+func DecodeSystemTransfer(ix CounterInstruction) (*SystemTransfer, error) {
+	if len(ix.Data) < 4 {
+		return nil, nil
+	}
+	// TransferWithSeed takes the funding account's base as its second
+	// account.
+	to := 1
+	switch binary.LittleEndian.Uint32(ix.Data) {
+	case systemTransfer:
+	case systemTransferWithSeed:
+		to = 2
+	default:
+		return nil, nil
+	}
+	if len(ix.Data) < 12 {
+		return nil, fmt.Errorf("system transfer: data is %d bytes, want 12", len(ix.Data))
+	}
+	if len(ix.Accounts) <= to {
+		return nil, fmt.Errorf("system transfer: %d accounts, want %d", len(ix.Accounts), to+1)
+	}
+	return &SystemTransfer{
+		From:     ix.Accounts[0],
+		To:       ix.Accounts[to],
+		Lamports: binary.LittleEndian.Uint64(ix.Data[4:12]),
+	}, nil
+}
@@ -0,0 +1,125 @@
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+type Format string
+
+const (
+	FormatCSV   Format = "csv"
+	FormatJSONL Format = "jsonl"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case FormatCSV, FormatJSONL:
+		return Format(s), nil
+	default:
+		return "", fmt.Errorf("unknown export format %q, want csv or jsonl", s)
+	}
+}
+
+// Writer encodes events one at a time.
+type Writer interface {
+	Write(event repository.ExportedEvent) error
+	// Flush writes any buffered data to the underlying writer.
+	Flush() error
+}
+
+func NewWriter(w io.Writer, format Format) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatJSONL:
+		return &jsonlWriter{w: w}, nil
+	default:
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+// Events streams the events matching filter into w and returns how many were
+// written. Memory use does not depend on the number of events.
+func Events(ctx context.Context, source repository.EventStreamer, filter repository.EventFilter, w Writer) (int, error) {
+	var n int
+	err := source.StreamEvents(ctx, filter, func(event repository.ExportedEvent) error {
+		if err := w.Write(event); err != nil {
+			return fmt.Errorf("write event %s: %w", event.Signature, err)
+		}
+		n++
+		return nil
+	})
+	if err != nil {
+		return n, err
+	}
+	return n, w.Flush()
+}
+
+// CSVHeader names the columns of CSVRow.
+var CSVHeader = []string{"signature", "event_type", "slot", "block_time", "program_id", "indexer_version", "document"}
+
+// CSVRow flattens an event into the CSVHeader columns.
+func CSVRow(event repository.ExportedEvent) []string {
+	return []string{
+		event.Signature,
+		string(event.EventType),
+		strconv.FormatUint(event.Slot, 10),
+		event.BlockTime.UTC().Format(time.RFC3339),
+		event.ProgramID.String(),
+		event.IndexerVersion,
+		string(event.Document),
+	}
+}
+
+// csvWriter writes the common event fields as columns and the full stored
+// document as JSON in the last column, since event types differ in fields.
+type csvWriter struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) Write(event repository.ExportedEvent) error {
+	if !c.wroteHeader {
+		if err := c.w.Write(CSVHeader); err != nil {
+			return err
+		}
+		c.wroteHeader = true
+	}
+	return c.w.Write(CSVRow(event))
+}
+
+func (c *csvWriter) Flush() error {
+	if !c.wroteHeader {
+		if err := c.w.Write(CSVHeader); err != nil {
+			return err
+		}
+		c.wroteHeader = true
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type jsonlWriter struct {
+	w io.Writer
+}
+
+func (j *jsonlWriter) Write(event repository.ExportedEvent) error {
+	line := make([]byte, 0, len(event.Document)+1)
+	line = append(line, event.Document...)
+	line = append(line, '\n')
+	_, err := j.w.Write(line)
+	return err
+}
+
+func (j *jsonlWriter) Flush() error { return nil }
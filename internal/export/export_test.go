@@ -0,0 +1,125 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+type fakeStreamer struct {
+	events []repository.ExportedEvent
+	filter repository.EventFilter
+}
+
+func (s *fakeStreamer) StreamEvents(ctx context.Context, filter repository.EventFilter, fn func(event repository.ExportedEvent) error) error {
+	s.filter = filter
+	for _, event := range s.events {
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func testEvents() []repository.ExportedEvent {
+	blockTime := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	return []repository.ExportedEvent{
+		{
+			BaseEvent: models.BaseEvent{Signature: "sig1", EventType: models.EventTypeCounterIncremented, Slot: 7, BlockTime: blockTime},
+			Document:  []byte(`{"signature":"sig1","new_value":2}`),
+		},
+		{
+			BaseEvent: models.BaseEvent{Signature: "sig2", EventType: models.EventTypeCounterReset, Slot: 8, BlockTime: blockTime},
+			Document:  []byte(`{"signature":"sig2","note":"a, \"quoted\" value"}`),
+		},
+	}
+}
+
+func TestEvents(t *testing.T) {
+	tests := []struct {
+		format Format
+		check  func(t *testing.T, out string)
+	}{
+		{
+			format: FormatJSONL,
+			check: func(t *testing.T, out string) {
+				want := `{"signature":"sig1","new_value":2}` + "\n" + `{"signature":"sig2","note":"a, \"quoted\" value"}` + "\n"
+				if out != want {
+					t.Errorf("output = %q, want %q", out, want)
+				}
+			},
+		},
+		{
+			format: FormatCSV,
+			check: func(t *testing.T, out string) {
+				records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+				if err != nil {
+					t.Fatalf("read csv: %v", err)
+				}
+				if len(records) != 3 || records[0][0] != "signature" {
+					t.Fatalf("records = %v", records)
+				}
+				if got := records[1]; got[1] != "CounterIncrementedEvent" || got[2] != "7" || got[3] != "2026-01-02T03:04:05Z" {
+					t.Errorf("row 1 = %v", got)
+				}
+				if got := records[2][6]; got != `{"signature":"sig2","note":"a, \"quoted\" value"}` {
+					t.Errorf("document = %s", got)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewWriter(&buf, tt.format)
+			if err != nil {
+				t.Fatalf("NewWriter() error = %v", err)
+			}
+			source := &fakeStreamer{events: testEvents()}
+			filter := repository.EventFilter{EventTypes: []models.EventType{models.EventTypeCounterReset}}
+
+			n, err := Events(context.Background(), source, filter, w)
+			if err != nil {
+				t.Fatalf("Events() error = %v", err)
+			}
+			if n != 2 {
+				t.Errorf("Events() = %d, want 2", n)
+			}
+			if len(source.filter.EventTypes) != 1 {
+				t.Errorf("filter not passed through: %+v", source.filter)
+			}
+			tt.check(t, buf.String())
+		})
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestEvents_WriteError(t *testing.T) {
+	w, _ := NewWriter(failingWriter{}, FormatJSONL)
+	n, err := Events(context.Background(), &fakeStreamer{events: testEvents()}, repository.EventFilter{}, w)
+	if err == nil || n != 0 {
+		t.Errorf("Events() = %d, %v, want write error", n, err)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, s := range []string{"csv", "jsonl"} {
+		if _, err := ParseFormat(s); err != nil {
+			t.Errorf("ParseFormat(%q) error = %v", s, err)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("ParseFormat(xml) error = nil")
+	}
+}
@@ -0,0 +1,138 @@
+// Package failure classifies the errors of the indexing pipeline by where
+// they come from, so storage failures can be alerted on separately from
+// decode noise.
+package failure
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+type Kind string
+
+const (
+	KindRPC     Kind = "rpc"
+	KindDecode  Kind = "decode"
+	KindStorage Kind = "storage"
+	KindReorg   Kind = "reorg"
+	KindSink    Kind = "sink"
+	KindOther   Kind = "other"
+)
+
+// Kinds lists every kind, in the order they are reported.
+var Kinds = []Kind{KindRPC, KindDecode, KindStorage, KindReorg, KindSink, KindOther}
+
+// RPCError is a failed call to the Solana RPC endpoint.
+type RPCError struct {
+	Method string
+	Err    error
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("rpc %s: %v", e.Method, e.Err)
+}
+
+func (e *RPCError) Unwrap() error { return e.Err }
+
+// DecodeError is program data or logs that could not be turned into an
+// event. EventType is empty when the event was not identified.
+type DecodeError struct {
+	EventType models.EventType
+	Err       error
+}
+
+func (e *DecodeError) Error() string {
+	if e.EventType == "" {
+		return fmt.Sprintf("decode: %v", e.Err)
+	}
+	return fmt.Sprintf("decode %s: %v", e.EventType, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error { return e.Err }
+
+// StorageError is a failure to store or buffer an event.
+type StorageError struct {
+	Op  string
+	Err error
+}
+
+func (e *StorageError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Op, e.Err)
+}
+
+func (e *StorageError) Unwrap() error { return e.Err }
+
+// ReorgError is a transaction that was listed for a program but could not
+// be fetched, because the fork it was confirmed on was dropped.
+type ReorgError struct {
+	Signature string
+}
+
+func (e *ReorgError) Error() string {
+	return fmt.Sprintf("transaction %s is no longer available, its fork was likely dropped", e.Signature)
+}
+
+// SinkError is a stored event a sink gave up delivering.
+type SinkError struct {
+	Sink string
+	Err  error
+}
+
+func (e *SinkError) Error() string {
+	return fmt.Sprintf("%s sink: %v", e.Sink, e.Err)
+}
+
+func (e *SinkError) Unwrap() error { return e.Err }
+
+// KindOf returns the kind of the outermost typed error in err's chain.
+func KindOf(err error) Kind {
+	for err != nil {
+		switch err.(type) {
+		case *RPCError:
+			return KindRPC
+		case *DecodeError:
+			return KindDecode
+		case *StorageError:
+			return KindStorage
+		case *ReorgError:
+			return KindReorg
+		case *SinkError:
+			return KindSink
+		}
+		err = errors.Unwrap(err)
+	}
+	return KindOther
+}
+
+// Counter counts errors by kind. It is safe for concurrent use.
+type Counter struct {
+	mu     sync.Mutex
+	counts map[Kind]uint64
+}
+
+func NewCounter() *Counter {
+	return &Counter{counts: make(map[Kind]uint64)}
+}
+
+// Record counts err and returns its kind.
+func (c *Counter) Record(err error) Kind {
+	kind := KindOf(err)
+	c.mu.Lock()
+	c.counts[kind]++
+	c.mu.Unlock()
+	return kind
+}
+
+// Counts returns the count of every kind, including those never recorded.
+func (c *Counter) Counts() map[Kind]uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	counts := make(map[Kind]uint64, len(Kinds))
+	for _, kind := range Kinds {
+		counts[kind] = c.counts[kind]
+	}
+	return counts
+}
@@ -0,0 +1,50 @@
+package failure
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestKindOf(t *testing.T) {
+	base := errors.New("boom")
+	tests := []struct {
+		name string
+		err  error
+		want Kind
+	}{
+		{"rpc", &RPCError{Method: "getTransaction", Err: base}, KindRPC},
+		{"wrapped decode", fmt.Errorf("starter: %w", &DecodeError{Err: base}), KindDecode},
+		{"storage around rpc", &StorageError{Op: "save event", Err: &RPCError{Method: "x", Err: base}}, KindStorage},
+		{"reorg", &ReorgError{Signature: "sig"}, KindReorg},
+		{"sink", &SinkError{Sink: "kinesis", Err: base}, KindSink},
+		{"plain", base, KindOther},
+		{"nil", nil, KindOther},
+	}
+	for _, tt := range tests {
+		if got := KindOf(tt.err); got != tt.want {
+			t.Errorf("KindOf(%s) = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	if err := (&StorageError{Op: "save event", Err: base}); !errors.Is(err, base) {
+		t.Errorf("errors.Is(%v, base) = false, want true", err)
+	}
+}
+
+func TestCounter(t *testing.T) {
+	c := NewCounter()
+	c.Record(&DecodeError{Err: errors.New("short")})
+	c.Record(&DecodeError{Err: errors.New("short")})
+	if kind := c.Record(&StorageError{Op: "save event", Err: errors.New("down")}); kind != KindStorage {
+		t.Errorf("Record() = %s, want %s", kind, KindStorage)
+	}
+
+	counts := c.Counts()
+	want := map[Kind]uint64{KindRPC: 0, KindDecode: 2, KindStorage: 1, KindReorg: 0, KindSink: 0, KindOther: 0}
+	for kind, n := range want {
+		if counts[kind] != n {
+			t.Errorf("Counts()[%s] = %d, want %d", kind, counts[kind], n)
+		}
+	}
+}
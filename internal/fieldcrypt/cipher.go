@@ -0,0 +1,267 @@
+// Package fieldcrypt encrypts designated fields of events before they are
+// stored, with AES-GCM, and decrypts them again when they are read.
+//
+// An encrypted field holds Prefix followed by the base64 nonce and sealed
+// JSON value. The event type and field name are authenticated with it, so
+// a value cannot be moved to another field unnoticed.
+package fieldcrypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"strings"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// Prefix marks an encrypted value.
+const Prefix = "enc:v1:"
+
+// Cipher encrypts the fields of each event type it was given.
+type Cipher struct {
+	aead   cipher.AEAD
+	fields map[models.EventType][]string
+}
+
+// New returns a Cipher with an AES-128, 192 or 256 key. The fields of the
+// event types with a model must be string fields of the model, named as
+// stored; those of other types, such as log grammar events, are keys of
+// their fields.
+func New(key []byte, fields map[models.EventType][]string) (*Cipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+	for eventType, names := range fields {
+		model, ok := models.NewEventModel(eventType)
+		if !ok {
+			continue
+		}
+		for _, name := range names {
+			if _, ok := stringField(reflect.ValueOf(model), name); !ok {
+				return nil, fmt.Errorf("%s has no string field %q", eventType, name)
+			}
+		}
+	}
+	return &Cipher{aead: aead, fields: fields}, nil
+}
+
+// ParseKey decodes a base64 key, as FIELD_ENCRYPTION_KEY holds it.
+func ParseKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("key must be base64: %w", err)
+	}
+	switch len(key) {
+	case 16, 24, 32:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("key must be 16, 24 or 32 bytes, not %d", len(key))
+	}
+}
+
+// Encrypt returns a copy of event with its fields encrypted, or event
+// itself when its type has none. Empty and already encrypted values are
+// left as they are. The copy has no raw data: the payload the event was
+// decoded from holds the encrypted values in plain text.
+func (c *Cipher) Encrypt(event models.Event) (models.Event, error) {
+	eventType := event.Base().EventType
+	names := c.fields[eventType]
+	if len(names) == 0 {
+		return event, nil
+	}
+
+	if e, ok := event.(*models.LogEvent); ok {
+		encrypted := *e
+		encrypted.Fields = maps.Clone(e.Fields)
+		for _, name := range names {
+			value, ok := encrypted.Fields[name]
+			if !ok || value == nil || isEncrypted(value) {
+				continue
+			}
+			sealed, err := c.seal(eventType, name, value)
+			if err != nil {
+				return nil, err
+			}
+			encrypted.Fields[name] = sealed
+		}
+		dropRawData(&encrypted)
+		return &encrypted, nil
+	}
+
+	v := reflect.ValueOf(event)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s: cannot encrypt a %T", eventType, event)
+	}
+	encrypted := reflect.New(v.Elem().Type())
+	encrypted.Elem().Set(v.Elem())
+	for _, name := range names {
+		field, ok := stringField(encrypted, name)
+		if !ok {
+			return nil, fmt.Errorf("%s has no string field %q", eventType, name)
+		}
+		if field.String() == "" || isEncrypted(field.String()) {
+			continue
+		}
+		sealed, err := c.seal(eventType, name, field.String())
+		if err != nil {
+			return nil, err
+		}
+		field.SetString(sealed)
+	}
+	e := encrypted.Interface().(models.Event)
+	dropRawData(e)
+	return e, nil
+}
+
+func dropRawData(event models.Event) {
+	base := event.Base()
+	base.RawData, base.RawDataEncoding = nil, ""
+}
+
+// Decrypt decrypts the encrypted fields of an event read back in place:
+// a model, a *models.LogEvent or a document of another type. Every
+// encrypted value is decrypted, whether or not its field is still
+// configured.
+func (c *Cipher) Decrypt(event interface{}) error {
+	if e, ok := event.(*models.LogEvent); ok {
+		return c.openMap(e.EventType, e.Fields)
+	}
+	v := reflect.ValueOf(event)
+	if doc, ok := asMap(v); ok {
+		eventType, _ := doc["event_type"].(string)
+		if err := c.openMap(models.EventType(eventType), doc); err != nil {
+			return err
+		}
+		if fields, ok := asMap(reflect.ValueOf(doc["fields"])); ok {
+			return c.openMap(models.EventType(eventType), fields)
+		}
+		return nil
+	}
+	e, ok := event.(models.Event)
+	if !ok || v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	eventType := e.Base().EventType
+	s := v.Elem()
+	for i := 0; i < s.NumField(); i++ {
+		field := s.Field(i)
+		if s.Type().Field(i).Anonymous || field.Kind() != reflect.String || !isEncrypted(field.String()) {
+			continue
+		}
+		var plain string
+		if err := c.open(eventType, bsonName(s.Type().Field(i)), field.String(), &plain); err != nil {
+			return err
+		}
+		field.SetString(plain)
+	}
+	return nil
+}
+
+func (c *Cipher) openMap(eventType models.EventType, doc map[string]interface{}) error {
+	for name, value := range doc {
+		sealed, ok := value.(string)
+		if !ok || !isEncrypted(sealed) {
+			continue
+		}
+		var plain interface{}
+		if err := c.open(eventType, name, sealed, &plain); err != nil {
+			return err
+		}
+		doc[name] = plain
+	}
+	return nil
+}
+
+func (c *Cipher) seal(eventType models.EventType, name string, value interface{}) (string, error) {
+	plain, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("encode %s.%s: %w", eventType, name, err)
+	}
+	nonce := make([]byte, c.aead.NonceSize(), c.aead.NonceSize()+len(plain)+c.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generate nonce: %w", err)
+	}
+	sealed := c.aead.Seal(nonce, nonce, plain, additionalData(eventType, name))
+	return Prefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+func (c *Cipher) open(eventType models.EventType, name, value string, out interface{}) error {
+	sealed, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(value, Prefix))
+	if err != nil || len(sealed) < c.aead.NonceSize() {
+		return fmt.Errorf("decrypt %s.%s: malformed value", eventType, name)
+	}
+	nonce, sealed := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plain, err := c.aead.Open(nil, nonce, sealed, additionalData(eventType, name))
+	if err != nil {
+		return fmt.Errorf("decrypt %s.%s: %w", eventType, name, err)
+	}
+	// Numbers stay json.Numbers, so large integers keep their precision.
+	dec := json.NewDecoder(bytes.NewReader(plain))
+	dec.UseNumber()
+	if err := dec.Decode(out); err != nil {
+		return fmt.Errorf("decode %s.%s: %w", eventType, name, err)
+	}
+	return nil
+}
+
+func additionalData(eventType models.EventType, name string) []byte {
+	return []byte(string(eventType) + "." + name)
+}
+
+func isEncrypted(value interface{}) bool {
+	s, ok := value.(string)
+	return ok && strings.HasPrefix(s, Prefix)
+}
+
+// stringField finds a string field of an event model by its bson name.
+// Fields of the embedded BaseEvent are not eligible.
+func stringField(v reflect.Value, name string) (reflect.Value, bool) {
+	for v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.Anonymous || bsonName(field) != name {
+			continue
+		}
+		if field.Type.Kind() != reflect.String {
+			return reflect.Value{}, false
+		}
+		return v.Field(i), true
+	}
+	return reflect.Value{}, false
+}
+
+func bsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("bson"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+var mapType = reflect.TypeOf(map[string]interface{}{})
+
+// asMap returns v as a map[string]interface{} sharing its entries, for
+// documents such as a bson.M.
+func asMap(v reflect.Value) (map[string]interface{}, bool) {
+	if !v.IsValid() || v.Kind() != reflect.Map || !v.Type().ConvertibleTo(mapType) || v.IsNil() {
+		return nil, false
+	}
+	return v.Convert(mapType).Interface().(map[string]interface{}), true
+}
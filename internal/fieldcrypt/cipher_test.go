@@ -0,0 +1,136 @@
+package fieldcrypt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+func newTestCipher(t *testing.T) *Cipher {
+	t.Helper()
+	c, err := New(testKey, map[models.EventType][]string{
+		models.EventTypeNftMinted: {"name", "uri"},
+		"MemoEvent":               {"memo", "amount"},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return c
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     []byte
+		fields  map[models.EventType][]string
+		wantErr bool
+	}{
+		{"string field", testKey, map[models.EventType][]string{models.EventTypeNftMinted: {"name"}}, false},
+		{"log event field", testKey, map[models.EventType][]string{"MemoEvent": {"anything"}}, false},
+		{"non-string field", testKey, map[models.EventType][]string{models.EventTypeNftMinted: {"owner"}}, true},
+		{"base event field", testKey, map[models.EventType][]string{models.EventTypeNftMinted: {"signature"}}, true},
+		{"short key", testKey[:10], nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.key, tt.fields)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	if _, err := ParseKey(base64.StdEncoding.EncodeToString(testKey)); err != nil {
+		t.Errorf("ParseKey() error = %v", err)
+	}
+	if _, err := ParseKey(base64.StdEncoding.EncodeToString(testKey[:20])); err == nil {
+		t.Errorf("ParseKey() of a 20 byte key error = nil, want an error")
+	}
+	if _, err := ParseKey("not base64!"); err == nil {
+		t.Errorf("ParseKey() of invalid base64 error = nil, want an error")
+	}
+}
+
+func TestCipher_Model(t *testing.T) {
+	c := newTestCipher(t)
+	event := &models.NftMintedEvent{
+		BaseEvent: models.BaseEvent{EventType: models.EventTypeNftMinted, Signature: "sig"},
+		Name:      "Alice's NFT",
+	}
+
+	stored, err := c.Encrypt(event)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if event.Name != "Alice's NFT" {
+		t.Errorf("Encrypt() changed the event's name to %q", event.Name)
+	}
+	got := stored.(*models.NftMintedEvent)
+	if !strings.HasPrefix(got.Name, Prefix) {
+		t.Errorf("Encrypt() name = %q, want it encrypted", got.Name)
+	}
+	if got.Uri != "" {
+		t.Errorf("Encrypt() uri = %q, want the empty value kept", got.Uri)
+	}
+	again, err := c.Encrypt(got)
+	if err != nil || again.(*models.NftMintedEvent).Name != got.Name {
+		t.Errorf("Encrypt() of an encrypted event changed it")
+	}
+
+	if err := c.Decrypt(got); err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if got.Name != "Alice's NFT" {
+		t.Errorf("Decrypt() name = %q, want %q", got.Name, "Alice's NFT")
+	}
+}
+
+func TestCipher_LogEventAndDocument(t *testing.T) {
+	c := newTestCipher(t)
+	event := &models.LogEvent{
+		BaseEvent: models.BaseEvent{EventType: "MemoEvent"},
+		Fields:    map[string]interface{}{"memo": "for rent", "amount": json.Number("18446744073709551615"), "payer": "x"},
+	}
+
+	stored, err := c.Encrypt(event)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	fields := stored.(*models.LogEvent).Fields
+	if !strings.HasPrefix(fields["memo"].(string), Prefix) || fields["payer"] != "x" {
+		t.Errorf("Encrypt() fields = %v, want only memo and amount encrypted", fields)
+	}
+	if event.Fields["memo"] != "for rent" {
+		t.Errorf("Encrypt() changed the event's fields")
+	}
+
+	// Events of types without a model are read back as documents.
+	doc := bson.M{"event_type": "MemoEvent", "fields": bson.M(fields)}
+	if err := c.Decrypt(doc); err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	got := doc["fields"].(bson.M)
+	if got["memo"] != "for rent" || got["amount"] != json.Number("18446744073709551615") {
+		t.Errorf("Decrypt() fields = %v, want the original values", got)
+	}
+}
+
+func TestCipher_DecryptWrongField(t *testing.T) {
+	c := newTestCipher(t)
+	stored, err := c.Encrypt(&models.NftMintedEvent{BaseEvent: models.BaseEvent{EventType: models.EventTypeNftMinted}, Name: "a"})
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	moved := &models.NftMintedEvent{BaseEvent: models.BaseEvent{EventType: models.EventTypeNftMinted}, Uri: stored.(*models.NftMintedEvent).Name}
+	if err := c.Decrypt(moved); err == nil {
+		t.Errorf("Decrypt() of a value moved to another field error = nil, want an error")
+	}
+}
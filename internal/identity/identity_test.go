@@ -0,0 +1,122 @@
+package identity
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	solanaClient "github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+type fakeAccounts map[solana.PublicKey][]byte
+
+func (f fakeAccounts) GetAccountData(ctx context.Context, account solana.PublicKey) ([]byte, uint64, error) {
+	data, ok := f[account]
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: %s", solanaClient.ErrAccountNotFound, account)
+	}
+	return data, 1, nil
+}
+
+func registryData(owner solana.PublicKey, payload []byte) []byte {
+	data := make([]byte, snsHeaderSize)
+	copy(data[32:64], owner[:])
+	return append(data, payload...)
+}
+
+func snsAccounts(t *testing.T, wallet, registryOwner solana.PublicKey, domain string) fakeAccounts {
+	t.Helper()
+	nameAccount := solana.PublicKey{0xaa}
+
+	favourite, _, err := solana.FindProgramAddress([][]byte{[]byte("favourite_domain"), wallet[:]}, snsNameOffersID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reverse, err := nameAccountKey(nameAccount.String(), snsReverseLookupClass)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name := binary.LittleEndian.AppendUint32(nil, uint32(len(domain)))
+	return fakeAccounts{
+		favourite:   append([]byte{1}, nameAccount[:]...),
+		nameAccount: registryData(registryOwner, nil),
+		reverse:     registryData(solana.PublicKey{}, append(name, domain...)),
+	}
+}
+
+func TestSNSResolver_Resolve(t *testing.T) {
+	wallet := solana.PublicKey{1}
+
+	tests := []struct {
+		name     string
+		accounts fakeAccounts
+		want     string
+	}{
+		{name: "favourite domain", accounts: snsAccounts(t, wallet, wallet, "bonfida"), want: "bonfida.sol"},
+		{name: "domain transferred away", accounts: snsAccounts(t, wallet, solana.PublicKey{2}, "bonfida")},
+		{name: "no favourite domain", accounts: fakeAccounts{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSNSResolver(tt.accounts).Resolve(context.Background(), wallet)
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Resolve() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+type countingResolver struct {
+	calls int
+	err   error
+}
+
+func (r *countingResolver) Resolve(ctx context.Context, wallet solana.PublicKey) (string, error) {
+	r.calls++
+	if r.err != nil {
+		return "", r.err
+	}
+	return "", nil
+}
+
+func TestCachedResolver(t *testing.T) {
+	next := &countingResolver{}
+	resolver := NewCachedResolver(next, time.Minute)
+	now := time.Unix(0, 0)
+	resolver.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	wallet := solana.PublicKey{1}
+
+	resolver.Resolve(ctx, wallet)
+	resolver.Resolve(ctx, wallet)
+	if next.calls != 1 {
+		t.Errorf("calls within TTL = %d, want 1", next.calls)
+	}
+
+	now = now.Add(time.Minute)
+	resolver.Resolve(ctx, wallet)
+	if next.calls != 2 {
+		t.Errorf("calls after TTL = %d, want 2", next.calls)
+	}
+
+	next.err = errors.New("rpc down")
+	other := solana.PublicKey{2}
+	for i := 0; i < 2; i++ {
+		if _, err := resolver.Resolve(ctx, other); err == nil {
+			t.Error("Resolve() expected error")
+		}
+	}
+	if next.calls != 4 {
+		t.Errorf("calls with errors = %d, want 4 (errors must not be cached)", next.calls)
+	}
+}
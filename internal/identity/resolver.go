@@ -0,0 +1,107 @@
+package identity
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Resolver maps a wallet address to a human readable name.
+type Resolver interface {
+	// Resolve returns the name of wallet, or "" when it has none.
+	Resolve(ctx context.Context, wallet solana.PublicKey) (string, error)
+}
+
+// ResolveAll resolves wallets and returns the names found, keyed by base58
+// address. Lookup failures are logged and skipped so enrichment never blocks
+// indexing.
+func ResolveAll(ctx context.Context, resolver Resolver, wallets []solana.PublicKey) map[string]string {
+	var names map[string]string
+	for _, wallet := range wallets {
+		name, err := resolver.Resolve(ctx, wallet)
+		if err != nil {
+			log.Printf("warning: failed to resolve identity of %s: %v", wallet, err)
+			continue
+		}
+		if name == "" {
+			continue
+		}
+		if names == nil {
+			names = make(map[string]string)
+		}
+		names[wallet.String()] = name
+	}
+	return names
+}
+
+const defaultCacheEntries = 10000
+
+// CachedResolver caches the results of another resolver, including wallets
+// without a name, for a fixed TTL. Errors are not cached.
+type CachedResolver struct {
+	next       Resolver
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[solana.PublicKey]cacheEntry
+}
+
+type cacheEntry struct {
+	name    string
+	expires time.Time
+}
+
+func NewCachedResolver(next Resolver, ttl time.Duration) *CachedResolver {
+	if ttl <= 0 {
+		ttl = time.Hour
+	}
+	return &CachedResolver{
+		next:       next,
+		ttl:        ttl,
+		maxEntries: defaultCacheEntries,
+		now:        time.Now,
+		entries:    make(map[solana.PublicKey]cacheEntry),
+	}
+}
+
+func (r *CachedResolver) Resolve(ctx context.Context, wallet solana.PublicKey) (string, error) {
+	now := r.now()
+
+	r.mu.Lock()
+	entry, ok := r.entries[wallet]
+	r.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.name, nil
+	}
+
+	name, err := r.next.Resolve(ctx, wallet)
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.entries) >= r.maxEntries {
+		r.evict(now)
+	}
+	r.entries[wallet] = cacheEntry{name: name, expires: now.Add(r.ttl)}
+	return name, nil
+}
+
+// evict drops expired entries, or every entry when none has expired yet.
+// The caller must hold r.mu.
+func (r *CachedResolver) evict(now time.Time) {
+	for wallet, entry := range r.entries {
+		if !now.Before(entry.expires) {
+			delete(r.entries, wallet)
+		}
+	}
+	if len(r.entries) >= r.maxEntries {
+		r.entries = make(map[solana.PublicKey]cacheEntry)
+	}
+}
@@ -0,0 +1,118 @@
+package identity
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	solanaClient "github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+var (
+	snsNameProgramID      = solana.MustPublicKeyFromBase58("namesLPneVptA9Z5rqUDD9tMTWEJwofgaYwp8cawRkX")
+	snsNameOffersID       = solana.MustPublicKeyFromBase58("85iDfUvr3HJyLM2LcetcB5pieh6RTjymaD5NqxfoFwM4")
+	snsReverseLookupClass = solana.MustPublicKeyFromBase58("33m47vH6Eav6jr5Ry86XjhRft2jRBLDnDgPSHoquXi2Z")
+)
+
+const (
+	snsHashPrefix = "SPL Name Service"
+	// snsHeaderSize is the NameRegistry header: parent, owner and class.
+	snsHeaderSize = 96
+)
+
+// AccountReader reads raw account data.
+type AccountReader interface {
+	GetAccountData(ctx context.Context, account solana.PublicKey) ([]byte, uint64, error)
+}
+
+// SNSResolver resolves wallets to their favourite (primary) Solana Name
+// Service domain, e.g. "bonfida.sol".
+type SNSResolver struct {
+	accounts AccountReader
+}
+
+func NewSNSResolver(accounts AccountReader) *SNSResolver {
+	return &SNSResolver{accounts: accounts}
+}
+
+func (r *SNSResolver) Resolve(ctx context.Context, wallet solana.PublicKey) (string, error) {
+	favourite, _, err := solana.FindProgramAddress([][]byte{[]byte("favourite_domain"), wallet[:]}, snsNameOffersID)
+	if err != nil {
+		return "", fmt.Errorf("derive favourite domain address: %w", err)
+	}
+
+	data, ok, err := r.read(ctx, favourite)
+	if err != nil || !ok {
+		return "", err
+	}
+	// Tag byte followed by the domain's name account.
+	if len(data) < 33 {
+		return "", fmt.Errorf("favourite domain account too short")
+	}
+	nameAccount := solana.PublicKeyFromBytes(data[1:33])
+
+	// A favourite that was transferred away no longer names this wallet.
+	registry, ok, err := r.read(ctx, nameAccount)
+	if err != nil || !ok {
+		return "", err
+	}
+	if len(registry) < snsHeaderSize || !solana.PublicKeyFromBytes(registry[32:64]).Equals(wallet) {
+		return "", nil
+	}
+
+	reverse, err := nameAccountKey(nameAccount.String(), snsReverseLookupClass)
+	if err != nil {
+		return "", err
+	}
+	data, ok, err = r.read(ctx, reverse)
+	if err != nil || !ok {
+		return "", err
+	}
+
+	name, err := decodeReverseLookup(data)
+	if err != nil {
+		return "", fmt.Errorf("decode reverse lookup of %s: %w", nameAccount, err)
+	}
+	return name + ".sol", nil
+}
+
+func (r *SNSResolver) read(ctx context.Context, account solana.PublicKey) ([]byte, bool, error) {
+	data, _, err := r.accounts.GetAccountData(ctx, account)
+	if errors.Is(err, solanaClient.ErrAccountNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// nameAccountKey derives the name service account of name in class with no
+// parent.
+func nameAccountKey(name string, class solana.PublicKey) (solana.PublicKey, error) {
+	hashed := sha256.Sum256([]byte(snsHashPrefix + name))
+	seeds := [][]byte{hashed[:], class[:], make([]byte, 32)}
+
+	key, _, err := solana.FindProgramAddress(seeds, snsNameProgramID)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("derive name account: %w", err)
+	}
+	return key, nil
+}
+
+// decodeReverseLookup reads the borsh string stored after the registry
+// header of a reverse lookup account.
+func decodeReverseLookup(data []byte) (string, error) {
+	if len(data) < snsHeaderSize+4 {
+		return "", fmt.Errorf("account too short")
+	}
+	data = data[snsHeaderSize:]
+	n := binary.LittleEndian.Uint32(data)
+	if uint64(n) > uint64(len(data)-4) {
+		return "", fmt.Errorf("name length %d exceeds account data", n)
+	}
+	return string(data[4 : 4+n]), nil
+}
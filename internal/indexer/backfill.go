@@ -0,0 +1,229 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+	solanaClient "github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// backfillPageSize is the largest page getSignaturesForAddress allows.
+const backfillPageSize = 1000
+
+// BackfillOptions bounds a backfill by slot, both ends inclusive. A zero
+// ToSlot starts at the newest transaction; a zero FromSlot walks back to the
+// first transaction of each program.
+type BackfillOptions struct {
+	FromSlot uint64
+	ToSlot   uint64
+	// Reprocess processes transactions the seen signature cache knows, e.g.
+	// after their events were deleted.
+	Reprocess bool
+}
+
+// Backfill indexes the historical transactions of every program within the
+// slot range and returns how many were processed. Transactions in the seen
+// signature cache are skipped unless opts.Reprocess is set, so callers
+// re-indexing a range should delete its events first and set it. It cannot
+// run alongside Start, use StartBackfill instead; call Shutdown afterwards
+// to flush sinks.
+func (i *Indexer) Backfill(ctx context.Context, opts BackfillOptions) (int, error) {
+	if opts.ToSlot > 0 && opts.FromSlot > opts.ToSlot {
+		return 0, fmt.Errorf("from slot %d is after to slot %d", opts.FromSlot, opts.ToSlot)
+	}
+
+	i.mu.Lock()
+	if i.isRunning {
+		i.mu.Unlock()
+		return 0, fmt.Errorf("indexer is already running")
+	}
+	i.isRunning = true
+	i.mu.Unlock()
+
+	return i.backfill(ctx, opts)
+}
+
+// StartBackfill backfills the slot range in the background of the running
+// indexer. Its RPC calls are scheduled at backfill priority, so they give
+// way to live polling when the RPC endpoint is under pressure. Only one
+// backfill runs at a time.
+func (i *Indexer) StartBackfill(fromSlot, toSlot uint64) (*models.BackfillStatus, error) {
+	if toSlot > 0 && fromSlot > toSlot {
+		return nil, fmt.Errorf("from slot %d is after to slot %d", fromSlot, toSlot)
+	}
+	return i.startBackfill(BackfillOptions{FromSlot: fromSlot, ToSlot: toSlot}, nil)
+}
+
+// StartReindex deletes the stored events of the slot range and backfills
+// it again in the background of the running indexer, e.g. after a decoder
+// fix. It runs as a backfill, so not alongside another one.
+func (i *Indexer) StartReindex(fromSlot, toSlot uint64) (*models.BackfillStatus, error) {
+	if fromSlot == 0 || toSlot == 0 || fromSlot > toSlot {
+		return nil, fmt.Errorf("a reindex needs a slot range, not %d to %d", fromSlot, toSlot)
+	}
+	deleter, ok := repository.Unwrap(i.repo).(repository.EventDeleter)
+	if !ok {
+		return nil, fmt.Errorf("%T does not support deleting events", repository.Unwrap(i.repo))
+	}
+	opts := BackfillOptions{FromSlot: fromSlot, ToSlot: toSlot, Reprocess: true}
+	return i.startBackfill(opts, func(ctx context.Context) error {
+		deleted, err := deleter.DeleteEvents(ctx, repository.EventFilter{FromSlot: fromSlot, ToSlot: toSlot, Network: i.Network()})
+		if err != nil {
+			return fmt.Errorf("delete events: %w", err)
+		}
+		log.Printf("deleted %d events in slots %d-%d", deleted, fromSlot, toSlot)
+		return nil
+	})
+}
+
+// startBackfill runs prepare, if any, and then the backfill in the
+// background.
+func (i *Indexer) startBackfill(opts BackfillOptions, prepare func(ctx context.Context) error) (*models.BackfillStatus, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.runCtx == nil {
+		return nil, fmt.Errorf("indexer is not running")
+	}
+	if i.backfillRun != nil && i.backfillRun.Running {
+		return nil, fmt.Errorf("a backfill of slots %d to %d is already running", i.backfillRun.FromSlot, i.backfillRun.ToSlot)
+	}
+	run := &models.BackfillStatus{
+		FromSlot:  opts.FromSlot,
+		ToSlot:    opts.ToSlot,
+		Reindex:   prepare != nil,
+		Running:   true,
+		StartedAt: time.Now().UTC(),
+	}
+	i.backfillRun = run
+	ctx := i.runCtx
+
+	go func() {
+		var (
+			n   int
+			err error
+		)
+		if prepare != nil {
+			err = prepare(ctx)
+		}
+		if err == nil {
+			log.Printf("backfilling slots %d to %d", opts.FromSlot, opts.ToSlot)
+			n, err = i.backfill(ctx, opts)
+		}
+		if err != nil {
+			log.Printf("warning: backfill of slots %d to %d stopped after %d transactions: %v", opts.FromSlot, opts.ToSlot, n, err)
+		} else {
+			log.Printf("backfilled %d transactions of slots %d to %d", n, opts.FromSlot, opts.ToSlot)
+		}
+
+		finished := time.Now().UTC()
+		i.mu.Lock()
+		defer i.mu.Unlock()
+		run.Running, run.Processed, run.FinishedAt = false, n, &finished
+		if err != nil {
+			run.Error = err.Error()
+		}
+	}()
+
+	status := *run
+	return &status, nil
+}
+
+// BackfillStatus returns the last backfill StartBackfill started, or nil.
+func (i *Indexer) BackfillStatus() *models.BackfillStatus {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	if i.backfillRun == nil {
+		return nil
+	}
+	status := *i.backfillRun
+	return &status
+}
+
+func (i *Indexer) backfill(ctx context.Context, opts BackfillOptions) (int, error) {
+	ctx = solanaClient.WithPriority(ctx, solanaClient.PriorityBackfill)
+	starter, err := i.backfillProgram(ctx, i.starterProgramID, i.processStarterTransaction, opts)
+	if err != nil {
+		return starter, fmt.Errorf("backfill starter program: %w", err)
+	}
+	processed := starter
+	deployments, _ := i.counterDeployments()
+	for _, d := range deployments {
+		counter, err := i.backfillProgram(ctx, d.program, i.counterTransactionProcessor(d), opts)
+		processed += counter
+		if err != nil {
+			return processed, fmt.Errorf("backfill %s program: %w", d.cursorName(), err)
+		}
+	}
+	for _, lp := range i.logPrograms {
+		n, err := i.backfillProgram(ctx, lp.grammar.Program, i.process(i.logDecoder(lp)), opts)
+		processed += n
+		if err != nil {
+			return processed, fmt.Errorf("backfill %s program: %w", lp.grammar.Name, err)
+		}
+	}
+	for _, cp := range i.customPrograms {
+		n, err := i.backfillProgram(ctx, cp.program, i.process(i.customDecoder(cp)), opts)
+		processed += n
+		if err != nil {
+			return processed, fmt.Errorf("backfill program %s: %w", cp.program, err)
+		}
+	}
+	for _, m := range i.tokenMints {
+		n, err := i.backfillProgram(ctx, m.mint, i.process(i.tokenDecoder(m)), opts)
+		processed += n
+		if err != nil {
+			return processed, fmt.Errorf("backfill spl-token mint %s: %w", m.mint, err)
+		}
+	}
+	for _, address := range i.solWatchlist.keys() {
+		n, err := i.backfillProgram(ctx, address, i.process(i.solDecoder(address)), opts)
+		processed += n
+		if err != nil {
+			return processed, fmt.Errorf("backfill sol transfers of %s: %w", address, err)
+		}
+	}
+	return processed, nil
+}
+
+func (i *Indexer) backfillProgram(ctx context.Context, programID solana.PublicKey, process func(context.Context, solana.Signature) error, opts BackfillOptions) (int, error) {
+	var before *solana.Signature
+	var processed int
+	for {
+		sigs, err := i.client.GetSignaturesForAddress(ctx, programID, backfillPageSize, before, nil)
+		if err != nil {
+			return processed, fmt.Errorf("get signatures: %w", err)
+		}
+		if len(sigs) == 0 {
+			return processed, nil
+		}
+
+		for _, sig := range sigs {
+			if sig.Slot < opts.FromSlot {
+				return processed, nil
+			}
+			if opts.ToSlot > 0 && sig.Slot > opts.ToSlot {
+				continue
+			}
+			ok, err := i.processNew(ctx, sig.Signature, opts.Reprocess, process)
+			if err != nil {
+				kind := i.recordFailure(ctx, programID, sig.Signature, sig.Slot, err)
+				log.Printf("error backfilling transaction %s (%s): %v", sig.Signature, kind, err)
+				continue
+			}
+			if ok {
+				processed++
+			}
+		}
+		log.Printf("backfilled %s down to slot %d (%d transactions)", programID, sigs[len(sigs)-1].Slot, processed)
+
+		if err := ctx.Err(); err != nil {
+			return processed, err
+		}
+		before = &sigs[len(sigs)-1].Signature
+	}
+}
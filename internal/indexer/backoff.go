@@ -0,0 +1,43 @@
+package indexer
+
+import "time"
+
+// pollBackoff stretches the poll interval while the indexed programs are
+// idle, doubling it up to max once no signatures have been seen for
+// idleAfter, and drops straight back to the base interval on activity.
+type pollBackoff struct {
+	base         time.Duration
+	max          time.Duration
+	idleAfter    time.Duration
+	current      time.Duration
+	lastActivity time.Time
+}
+
+func newPollBackoff(base, max, idleAfter time.Duration, now time.Time) *pollBackoff {
+	return &pollBackoff{
+		base:         base,
+		max:          max,
+		idleAfter:    idleAfter,
+		current:      base,
+		lastActivity: now,
+	}
+}
+
+// next returns the delay before the following poll, given whether the last
+// poll found new signatures.
+func (b *pollBackoff) next(active bool, now time.Time) time.Duration {
+	if active || b.idleAfter <= 0 || b.max <= b.base {
+		b.lastActivity = now
+		b.current = b.base
+		return b.current
+	}
+	if now.Sub(b.lastActivity) < b.idleAfter {
+		return b.current
+	}
+	b.current = min(b.current*2, b.max)
+	return b.current
+}
+
+func (b *pollBackoff) idle() bool {
+	return b.current > b.base
+}
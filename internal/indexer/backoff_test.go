@@ -0,0 +1,39 @@
+package indexer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPollBackoff(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newPollBackoff(time.Second, 8*time.Second, time.Minute, start)
+
+	steps := []struct {
+		name   string
+		at     time.Duration
+		active bool
+		want   time.Duration
+	}{
+		{name: "recently active", at: 30 * time.Second, want: time.Second},
+		{name: "idle threshold reached", at: time.Minute, want: 2 * time.Second},
+		{name: "keeps doubling", at: 62 * time.Second, want: 4 * time.Second},
+		{name: "doubles again", at: 66 * time.Second, want: 8 * time.Second},
+		{name: "capped at max", at: 74 * time.Second, want: 8 * time.Second},
+		{name: "activity resets", at: 82 * time.Second, active: true, want: time.Second},
+		{name: "fast again until idle", at: 83 * time.Second, want: time.Second},
+	}
+	for _, step := range steps {
+		if got := b.next(step.active, start.Add(step.at)); got != step.want {
+			t.Errorf("%s: next() = %v, want %v", step.name, got, step.want)
+		}
+	}
+}
+
+func TestPollBackoff_Disabled(t *testing.T) {
+	start := time.Now()
+	b := newPollBackoff(time.Second, 30*time.Second, 0, start)
+	if got := b.next(false, start.Add(time.Hour)); got != time.Second {
+		t.Errorf("next() = %v, want base interval when idle detection is off", got)
+	}
+}
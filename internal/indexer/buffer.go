@@ -0,0 +1,35 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+// replayBuffer saves and publishes the events buffered while the database
+// was unreachable, retrying every OfflineBufferRetryInterval. New events
+// keep going to the buffer until it is empty, so they are stored in order.
+func (i *Indexer) replayBuffer(ctx context.Context) {
+	ticker := time.NewTicker(i.cfg.OfflineBufferRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if i.buffer.Len() == 0 {
+			continue
+		}
+
+		n, err := i.buffer.Drain(ctx, i.starterProcessor.Replay)
+		if n > 0 {
+			log.Printf("replayed %d buffered events, %d left", n, i.buffer.Len())
+		}
+		if err != nil && !errors.Is(err, context.Canceled) {
+			log.Printf("warning: database still unreachable, %d events buffered: %v", i.buffer.Len(), err)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+	solanaClient "github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// programCursor is the live indexing position of one program. Transactions
+// up to and including head have been processed; until there is a head,
+// transactions below floor are skipped.
+type programCursor struct {
+	name    string
+	program solana.PublicKey
+	// id is the ID of the program's checkpoint.
+	id     string
+	decode transactionDecoder
+	store  repository.CheckpointStore
+	queues *pipelineQueues
+
+	head  *solana.Signature
+	slot  uint64
+	floor uint64
+	start string
+}
+
+// openCursor resumes from the program's checkpoint or, on the first run,
+// applies the configured start strategy. The start point is recorded with
+// the checkpoint, so later changes to START_FROM do not move a program that
+// is already being indexed.
+func (i *Indexer) openCursor(ctx context.Context, name string, program solana.PublicKey, decode transactionDecoder) (*programCursor, error) {
+	c := &programCursor{
+		name:    name,
+		program: program,
+		id:      models.CheckpointID(i.cfg.Network, program.String()),
+		decode:  decode,
+		queues:  i.programQueues(program),
+	}
+	c.store, _ = repository.Unwrap(i.repo).(repository.CheckpointStore)
+
+	if c.store != nil {
+		checkpoint, err := c.store.GetCheckpoint(ctx, c.id)
+		if err != nil {
+			return nil, fmt.Errorf("load %s checkpoint: %w", name, err)
+		}
+		if checkpoint != nil {
+			head, err := solana.SignatureFromBase58(checkpoint.Signature)
+			if err != nil {
+				return nil, fmt.Errorf("%s checkpoint signature: %w", name, err)
+			}
+			c.head, c.slot, c.start = &head, checkpoint.Slot, checkpoint.Start
+			log.Printf("resuming %s program %s after slot %d (started from %s)", name, program, c.slot, c.start)
+			return c, nil
+		}
+	} else {
+		log.Printf("warning: %T cannot store checkpoints, %s program restarts from %s", repository.Unwrap(i.repo), name, i.cfg.StartStrategy())
+	}
+
+	switch i.cfg.StartStrategy() {
+	case config.StartFromLatest:
+		sigs, err := i.client.GetSignaturesForAddress(ctx, program, 1, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("find latest %s signature: %w", name, err)
+		}
+		if len(sigs) > 0 {
+			c.head, c.slot = &sigs[0].Signature, sigs[0].Slot
+		}
+		c.start = config.StartFromLatest
+	case config.StartFromSignature:
+		head, err := solana.SignatureFromBase58(i.cfg.StartSignature)
+		if err != nil {
+			return nil, fmt.Errorf("START_SIGNATURE: %w", err)
+		}
+		c.head = &head
+		c.start = "signature " + head.String()
+	case config.StartFromSlot:
+		c.floor = i.cfg.StartSlot
+		c.start = fmt.Sprintf("slot %d", i.cfg.StartSlot)
+	default:
+		c.start = config.StartFromGenesis
+	}
+	log.Printf("starting %s program %s from %s", name, program, c.start)
+
+	c.save(ctx)
+	return c, nil
+}
+
+// save records the cursor as the program's checkpoint. A cursor without a
+// head has nothing to record yet.
+func (c *programCursor) save(ctx context.Context) {
+	if c.store == nil || c.head == nil {
+		return
+	}
+	checkpoint := &models.Checkpoint{
+		Program:   c.id,
+		Signature: c.head.String(),
+		Slot:      c.slot,
+		Start:     c.start,
+		UpdatedAt: time.Now().UTC(),
+	}
+	if err := c.store.SaveCheckpoint(ctx, checkpoint); err != nil {
+		log.Printf("warning: failed to save %s checkpoint: %v", c.name, err)
+	}
+}
+
+// poll processes the transactions after the cursor's head, oldest first,
+// and returns how many there were.
+func (i *Indexer) poll(ctx context.Context, c *programCursor) (int, error) {
+	finalized := i.clusterFinalizedSlot()
+	sigs, err := pendingSignatures(i.cfg.BatchSize, c.floor, func(before *solana.Signature) ([]*rpc.TransactionSignature, error) {
+		return i.client.GetSignaturesForAddress(ctx, c.program, i.cfg.BatchSize, before, c.head)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("get signatures: %w", err)
+	}
+	if len(sigs) == 0 {
+		i.markComplete(c.program, finalized)
+		return 0, nil
+	}
+
+	log.Printf("processing %d %s program signatures", len(sigs), c.name)
+
+	if len(sigs) > i.cfg.BatchSize {
+		// More than a page behind, e.g. after downtime: catching up must
+		// not starve the programs that are current.
+		ctx = solanaClient.WithPriority(ctx, solanaClient.PriorityRepair)
+	}
+	n, err := i.runPipeline(ctx, c, sigs)
+	if err != nil {
+		return n, err
+	}
+	if n == len(sigs) {
+		i.markComplete(c.program, finalized)
+	}
+
+	i.mu.Lock()
+	if c.slot > i.currentSlot {
+		i.currentSlot = c.slot
+	}
+	i.mu.Unlock()
+
+	return n, nil
+}
+
+// pendingSignatures pages back through the signatures list returns, newest
+// first, until a page comes back short or a signature is below floor.
+func pendingSignatures(pageSize int, floor uint64, list func(before *solana.Signature) ([]*rpc.TransactionSignature, error)) ([]*rpc.TransactionSignature, error) {
+	var out []*rpc.TransactionSignature
+	var before *solana.Signature
+	for {
+		page, err := list(before)
+		if err != nil {
+			return nil, err
+		}
+		for _, sig := range page {
+			if sig.Slot < floor {
+				return out, nil
+			}
+			out = append(out, sig)
+		}
+		if len(page) < pageSize {
+			return out, nil
+		}
+		before = &page[len(page)-1].Signature
+	}
+}
@@ -0,0 +1,72 @@
+package indexer
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestPendingSignatures(t *testing.T) {
+	// history holds slots 10 down to 1, newest first, as an RPC node
+	// returns them.
+	var history []*rpc.TransactionSignature
+	for slot := uint64(10); slot >= 1; slot-- {
+		var sig solana.Signature
+		sig[0] = byte(slot)
+		history = append(history, &rpc.TransactionSignature{Signature: sig, Slot: slot})
+	}
+	list := func(pageSize int, until uint64) func(before *solana.Signature) ([]*rpc.TransactionSignature, error) {
+		return func(before *solana.Signature) ([]*rpc.TransactionSignature, error) {
+			var page []*rpc.TransactionSignature
+			started := before == nil
+			for _, sig := range history {
+				if !started {
+					started = sig.Signature == *before
+					continue
+				}
+				if sig.Slot == until || len(page) == pageSize {
+					break
+				}
+				page = append(page, sig)
+			}
+			return page, nil
+		}
+	}
+
+	tests := []struct {
+		name     string
+		pageSize int
+		floor    uint64
+		until    uint64
+		want     []uint64
+	}{
+		{name: "genesis", pageSize: 3, want: []uint64{10, 9, 8, 7, 6, 5, 4, 3, 2, 1}},
+		{name: "until head", pageSize: 3, until: 4, want: []uint64{10, 9, 8, 7, 6, 5}},
+		{name: "until head on page boundary", pageSize: 3, until: 7, want: []uint64{10, 9, 8}},
+		{name: "from slot", pageSize: 4, floor: 6, want: []uint64{10, 9, 8, 7, 6}},
+		{name: "nothing new", pageSize: 3, until: 10, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sigs, err := pendingSignatures(tt.pageSize, tt.floor, list(tt.pageSize, tt.until))
+			if err != nil {
+				t.Fatalf("pendingSignatures() error = %v", err)
+			}
+			var got []uint64
+			for _, sig := range sigs {
+				got = append(got, sig.Slot)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("pendingSignatures() slots = %v, want %v", got, tt.want)
+			}
+			for n := range got {
+				if got[n] != tt.want[n] {
+					t.Errorf("pendingSignatures() slots = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
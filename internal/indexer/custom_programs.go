@@ -0,0 +1,177 @@
+package indexer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/codegen"
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/decoder"
+	"github.com/lugondev/go-indexer-solana-starter/internal/failure"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/processor"
+	pkgdecoder "github.com/lugondev/go-indexer-solana-starter/pkg/decoder"
+	solanaClient "github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// idlFetchTimeout bounds fetching the IDL of an IDL_PROGRAMS program.
+const idlFetchTimeout = 30 * time.Second
+
+// customProgram is a program decoded by a decoder registered with
+// pkg/decoder.Register or loaded from DECODER_PLUGINS, or from the IDL of an
+// IDL_PROGRAMS program.
+type customProgram struct {
+	program   solana.PublicKey
+	processor *processor.EventProcessor
+	// fromIDL is set for IDL_PROGRAMS programs, whose decoder is replaced
+	// when the program is upgraded with PROGRAM_UPGRADE_REFETCH_IDL.
+	fromIDL bool
+
+	mu      sync.RWMutex
+	decoder decoder.Decoder
+}
+
+// newCustomPrograms loads DECODER_PLUGINS and the on-chain IDLs of
+// IDL_PROGRAMS, and returns their programs and those of all registered
+// decoders, which must not be indexed otherwise. Their processors share the
+// settings of starter.
+func newCustomPrograms(cfg *config.Config, client *solanaClient.Client, starter *processor.EventProcessor, indexed []solana.PublicKey) ([]*customProgram, error) {
+	for _, path := range cfg.DecoderPlugins {
+		program, err := pkgdecoder.LoadPlugin(path)
+		if err != nil {
+			return nil, fmt.Errorf("DECODER_PLUGINS: %w", err)
+		}
+		log.Printf("loaded decoder plugin %s for program %s", path, program)
+	}
+
+	registered := decoder.Registered()
+	fromIDL := make(map[solana.PublicKey]bool, len(cfg.IDLPrograms))
+	for _, id := range cfg.IDLPrograms {
+		program, err := solana.PublicKeyFromBase58(id)
+		if err != nil {
+			return nil, fmt.Errorf("IDL_PROGRAMS: %q: %w", id, err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), idlFetchTimeout)
+		d, err := fetchIDLDecoder(ctx, client, program)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("IDL_PROGRAMS: %w", err)
+		}
+		registered = append(registered, decoder.Registration{Program: program, Decoder: d})
+		fromIDL[program] = true
+	}
+
+	programs := make([]*customProgram, 0, len(registered))
+	for _, r := range registered {
+		if slices.Contains(indexed, r.Program) {
+			return nil, fmt.Errorf("program %s has a decoder but is already indexed", r.Program)
+		}
+		indexed = append(indexed, r.Program)
+		programs = append(programs, &customProgram{
+			program:   r.Program,
+			processor: starter.WithProgram(r.Program),
+			fromIDL:   fromIDL[r.Program],
+			decoder:   r.Decoder,
+		})
+	}
+	return programs, nil
+}
+
+// fetchIDLDecoder returns a decoder of the IDL uploaded for program.
+func fetchIDLDecoder(ctx context.Context, client *solanaClient.Client, program solana.PublicKey) (*decoder.IDLDecoder, error) {
+	raw, err := codegen.FetchIDL(ctx, client, program)
+	if err != nil {
+		return nil, err
+	}
+	idl, err := codegen.ReadIDL(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("IDL of %s: %w", program, err)
+	}
+	d, err := decoder.NewIDLDecoder(idl)
+	if err != nil {
+		return nil, fmt.Errorf("IDL of %s: %w", program, err)
+	}
+	log.Printf("decoding %d events of program %s from its on-chain IDL %s", len(idl.Events), program, idl.Metadata.Name)
+	return d, nil
+}
+
+func (i *Indexer) customDecoder(cp *customProgram) transactionDecoder {
+	return func(ctx context.Context, tx *fetchedTransaction) (*decodedTransaction, error) {
+		return i.decodeCustomTransaction(ctx, cp, tx)
+	}
+}
+
+func (i *Indexer) decodeCustomTransaction(ctx context.Context, cp *customProgram, fetched *fetchedTransaction) (*decodedTransaction, error) {
+	signature, tx := fetched.signature, fetched.tx
+	decoded := &decodedTransaction{
+		signature: signature,
+		slot:      tx.Slot,
+		blockTime: fetched.blockTime,
+		program:   cp.program,
+		processor: cp.processor,
+		kind:      "custom",
+	}
+	for _, data := range pkgdecoder.EventData(cp.program, tx, i.programDataMode) {
+		eventType, event, err := cp.decodeEvent(data)
+		if errors.Is(err, decoder.ErrUnknownEvent) {
+			continue
+		}
+		if err != nil {
+			i.recordFailure(ctx, cp.program, signature, tx.Slot, &failure.DecodeError{EventType: eventType, Err: err})
+			log.Printf("failed to decode event of %s: %v", cp.program, err)
+			continue
+		}
+		decoded.events = append(decoded.events, decodedEvent{eventType: eventType, data: event, raw: i.rawData(data)})
+	}
+	return decoded, nil
+}
+
+// decodeEvent decodes data with the program's decoder. Events of types
+// without a model of their own are stored as a models.LogEvent with the
+// JSON fields of the decoded value.
+func (cp *customProgram) decodeEvent(data []byte) (models.EventType, interface{}, error) {
+	cp.mu.RLock()
+	d := cp.decoder
+	cp.mu.RUnlock()
+	eventType, event, err := d.DecodeEvent(data)
+	if err != nil {
+		return eventType, nil, err
+	}
+	if _, ok := models.NewEventModel(eventType); ok {
+		return eventType, event, nil
+	}
+	if e, ok := event.(models.LogEvent); ok {
+		return eventType, e, nil
+	}
+	encoded, err := json.Marshal(event)
+	if err != nil {
+		return eventType, nil, fmt.Errorf("encode %s: %w", eventType, err)
+	}
+	var e models.LogEvent
+	if err := json.Unmarshal(fmt.Appendf(nil, `{"fields":%s}`, encoded), &e); err != nil {
+		return eventType, nil, fmt.Errorf("%s must encode to a JSON object: %w", eventType, err)
+	}
+	return eventType, e, nil
+}
+
+func (cp *customProgram) setDecoder(d decoder.Decoder) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	cp.decoder = d
+}
+
+func customProgramIDs(programs []*customProgram) []solana.PublicKey {
+	keys := make([]solana.PublicKey, len(programs))
+	for n, cp := range programs {
+		keys[n] = cp.program
+	}
+	return keys
+}
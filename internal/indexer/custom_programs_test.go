@@ -0,0 +1,62 @@
+package indexer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/decoder"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+func TestCustomProgram_DecodeEvent(t *testing.T) {
+	type vaultDeposit struct {
+		Vault  string `json:"vault"`
+		Amount uint64 `json:"amount"`
+	}
+	minted := models.TokensMintedEvent{Amount: 5}
+
+	tests := []struct {
+		name      string
+		eventType models.EventType
+		event     interface{}
+		want      interface{}
+		wantErr   bool
+	}{
+		{
+			name:      "custom event becomes a log event",
+			eventType: "VaultDeposit",
+			event:     vaultDeposit{Vault: "main", Amount: 1 << 60},
+			want:      models.LogEvent{Fields: map[string]interface{}{"vault": "main", "amount": int64(1 << 60)}},
+		},
+		{
+			name:      "event with a model is kept",
+			eventType: models.EventTypeTokensMinted,
+			event:     &minted,
+			want:      &minted,
+		},
+		{
+			name:      "event that is not an object",
+			eventType: "Ping",
+			event:     "pong",
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cp := &customProgram{decoder: decoder.DecoderFunc(func(data []byte) (models.EventType, interface{}, error) {
+				return tt.eventType, tt.event, nil
+			})}
+			eventType, got, err := cp.decodeEvent(nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeEvent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if eventType != tt.eventType {
+				t.Errorf("decodeEvent() type = %s, want %s", eventType, tt.eventType)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeEvent() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
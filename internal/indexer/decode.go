@@ -0,0 +1,190 @@
+package indexer
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	pkgdecoder "github.com/lugondev/go-indexer-solana-starter/pkg/decoder"
+)
+
+// DecodeEventData decodes one starter program event payload: the event
+// discriminator and its Borsh data, optionally behind the emit_cpi! tag.
+func (i *Indexer) DecodeEventData(data []byte) models.DecodedEvent {
+	if payload, ok := pkgdecoder.ParseEventCPI(data); ok {
+		data = payload
+	}
+	return i.decodeStarterEvent(data, models.BaseEvent{})
+}
+
+// DecodeTransaction decodes the events of a getTransaction result with the
+// decoders the indexer is running, without storing or publishing them.
+func (i *Indexer) DecodeTransaction(tx *rpc.GetTransactionResult) ([]models.DecodedEvent, error) {
+	if tx == nil || tx.Meta == nil {
+		return nil, fmt.Errorf("transaction has no meta")
+	}
+	base := models.BaseEvent{Slot: tx.Slot}
+	if tx.BlockTime != nil {
+		base.BlockTime = time.Unix(int64(*tx.BlockTime), 0).UTC()
+	}
+	if tx.Transaction != nil {
+		if txObj, err := tx.Transaction.GetTransaction(); err == nil && len(txObj.Signatures) > 0 {
+			base.Signature = txObj.Signatures[0].String()
+		}
+	}
+
+	events := []models.DecodedEvent{}
+	payloads, found := i.starterEventData(tx)
+	for _, data := range payloads {
+		events = append(events, i.decodeStarterEvent(data, base))
+	}
+	if n := found - len(payloads); n > 0 {
+		events = append(events, models.DecodedEvent{
+			ProgramID: i.starterProgramID.String(),
+			Error:     fmt.Sprintf("%d program data logs are not valid base64", n),
+		})
+	}
+
+	deployments, _ := i.counterDeployments()
+	for _, d := range deployments {
+		if !invokes(tx.Meta.LogMessages, d.program) {
+			continue
+		}
+		actions, err := counterActions(d, tx)
+		if err != nil {
+			events = append(events, models.DecodedEvent{ProgramID: d.program.String(), Deployment: d.label, Error: err.Error()})
+			continue
+		}
+		for _, action := range actions {
+			decoded := models.DecodedEvent{ProgramID: d.program.String(), Deployment: d.label, EventType: action.Type}
+			eventData, err := i.convertCounterActionToEvent(action)
+			if err != nil {
+				decoded.Error = err.Error()
+			} else {
+				eventBase := base
+				eventBase.ProgramID = d.program
+				eventBase.Deployment = d.label
+				decoded.Event = withBase(eventData, action.Type, eventBase)
+			}
+			events = append(events, decoded)
+		}
+	}
+
+	for _, lp := range i.logPrograms {
+		program := lp.grammar.Program
+		if !invokes(tx.Meta.LogMessages, program) {
+			continue
+		}
+		matches, errs := logMatches(lp.grammar, tx)
+		for _, err := range errs {
+			events = append(events, models.DecodedEvent{ProgramID: program.String(), Error: err.Error()})
+		}
+		for _, m := range matches {
+			eventBase := base
+			eventBase.EventType = m.Event
+			eventBase.ProgramID = program
+			events = append(events, models.DecodedEvent{
+				ProgramID: program.String(),
+				EventType: m.Event,
+				Event:     &models.LogEvent{BaseEvent: eventBase, Fields: m.Fields},
+			})
+		}
+	}
+
+	for _, cp := range i.customPrograms {
+		for _, data := range pkgdecoder.EventData(cp.program, tx, i.programDataMode) {
+			eventType, event, err := cp.decodeEvent(data)
+			decoded := models.DecodedEvent{ProgramID: cp.program.String(), EventType: eventType}
+			if err != nil {
+				decoded.Error = err.Error()
+			} else {
+				eventBase := base
+				eventBase.ProgramID = cp.program
+				decoded.Event = withBase(event, eventType, eventBase)
+			}
+			events = append(events, decoded)
+		}
+	}
+
+	if tx.Meta.Err == nil && tx.Transaction != nil {
+		if txObj, err := tx.Transaction.GetTransaction(); err == nil {
+			for _, address := range i.solWatchlist.keys() {
+				transfers, errs := solTransfers(tx, txObj, address, i.solWatchlist.watched)
+				for _, err := range errs {
+					events = append(events, models.DecodedEvent{ProgramID: solana.SystemProgramID.String(), Error: err.Error()})
+				}
+				for _, t := range transfers {
+					eventBase := base
+					eventBase.ProgramID = solana.SystemProgramID
+					events = append(events, models.DecodedEvent{
+						ProgramID: solana.SystemProgramID.String(),
+						EventType: models.EventTypeSolTransfer,
+						Event:     withBase(t, models.EventTypeSolTransfer, eventBase),
+					})
+				}
+			}
+			for _, m := range i.tokenMints {
+				tokenEvents, errs := tokenEvents(tx, txObj, m.mint)
+				for _, err := range errs {
+					events = append(events, models.DecodedEvent{ProgramID: m.mint.String(), Error: err.Error()})
+				}
+				for _, e := range tokenEvents {
+					eventBase := base
+					eventBase.ProgramID = e.program
+					events = append(events, models.DecodedEvent{
+						ProgramID: e.program.String(),
+						EventType: e.Type,
+						Event:     withBase(e.Event, e.Type, eventBase),
+					})
+				}
+			}
+		}
+	}
+	return events, nil
+}
+
+func (i *Indexer) decodeStarterEvent(data []byte, base models.BaseEvent) models.DecodedEvent {
+	decoded := models.DecodedEvent{ProgramID: i.starterProgramID.String()}
+	eventType, eventData, err := i.eventDecoder.DecodeEvent(data)
+	decoded.EventType = eventType
+	if err != nil {
+		decoded.Error = err.Error()
+		return decoded
+	}
+	base.ProgramID = i.starterProgramID
+	decoded.Event = withBase(eventData, eventType, base)
+	return decoded
+}
+
+// withBase returns a copy of event, an event model or a pointer to one as
+// the decoders build it, with its BaseEvent set.
+func withBase(event interface{}, eventType models.EventType, base models.BaseEvent) interface{} {
+	v := reflect.ValueOf(event)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	c := reflect.New(v.Type())
+	c.Elem().Set(v)
+	e, ok := c.Interface().(models.Event)
+	if !ok {
+		return event
+	}
+	base.EventType = eventType
+	*e.Base() = base
+	return e
+}
+
+// invokes reports whether logs show program being invoked.
+func invokes(logs []string, program solana.PublicKey) bool {
+	prefix := "Program " + program.String() + " invoke ["
+	for _, log := range logs {
+		if strings.HasPrefix(log, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/codegen"
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/decoder"
+	"github.com/lugondev/go-indexer-solana-starter/internal/processor"
+)
+
+// counterDeployment is one deployment of the counter program. Its events
+// carry the deployment label.
+type counterDeployment struct {
+	label     string
+	program   solana.PublicKey
+	tenant    string
+	processor *processor.EventProcessor
+	logParser *decoder.CounterLogParser
+}
+
+func newCounterDeployments(cfg *config.Config) ([]*counterDeployment, error) {
+	var idl *codegen.IDL
+	if cfg.CounterProgramIDL != "" {
+		f, err := os.Open(cfg.CounterProgramIDL)
+		if err != nil {
+			return nil, fmt.Errorf("COUNTER_PROGRAM_IDL: %w", err)
+		}
+		idl, err = codegen.ReadIDL(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("COUNTER_PROGRAM_IDL: %w", err)
+		}
+	}
+
+	var deployments []*counterDeployment
+	for _, d := range cfg.CounterDeployments() {
+		program, err := solana.PublicKeyFromBase58(d.ProgramID)
+		if err != nil {
+			return nil, fmt.Errorf("parse counter program ID %q: %w", d.ProgramID, err)
+		}
+		logParser := decoder.NewCounterLogParser(program)
+		if idl != nil {
+			if logParser, err = decoder.NewCounterLogParserFromIDL(program, idl); err != nil {
+				return nil, fmt.Errorf("COUNTER_PROGRAM_IDL: %w", err)
+			}
+		}
+		deployments = append(deployments, &counterDeployment{
+			label:     d.Label,
+			program:   program,
+			tenant:    cfg.ProgramTenants[d.ProgramID],
+			logParser: logParser,
+		})
+	}
+	return deployments, nil
+}
+
+// attach gives the deployment a processor sharing the settings of starter.
+func (d *counterDeployment) attach(starter *processor.EventProcessor) {
+	d.processor = starter.WithProgram(d.program)
+	d.processor.SetTenant(d.tenant)
+	d.processor.SetDeployment(d.label)
+}
+
+// cursorName names the deployment in logs and errors.
+func (d *counterDeployment) cursorName() string {
+	if d.label == "" {
+		return "counter"
+	}
+	return "counter " + d.label
+}
+
+func (d *counterDeployment) String() string {
+	if d.label == "" {
+		return d.program.String()
+	}
+	return fmt.Sprintf("%s (%s)", d.program, d.label)
+}
+
+func (i *Indexer) counterDecoder(d *counterDeployment) transactionDecoder {
+	return func(ctx context.Context, tx *fetchedTransaction) (*decodedTransaction, error) {
+		return i.decodeCounterTransaction(ctx, d, tx)
+	}
+}
+
+func (i *Indexer) counterTransactionProcessor(d *counterDeployment) func(context.Context, solana.Signature) error {
+	return i.process(i.counterDecoder(d))
+}
+
+func counterPrograms(deployments []*counterDeployment) []solana.PublicKey {
+	programs := make([]solana.PublicKey, len(deployments))
+	for n, d := range deployments {
+		programs[n] = d.program
+	}
+	return programs
+}
+
+// counterDeployments returns the deployments currently indexed, which a
+// reload may change, and their generation.
+func (i *Indexer) counterDeployments() ([]*counterDeployment, uint64) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.counters, i.counterGen
+}
+
+// DeploymentLabels returns the labels of the indexed counter deployments.
+func (i *Indexer) DeploymentLabels() []string {
+	deployments, _ := i.counterDeployments()
+	var labels []string
+	for _, d := range deployments {
+		if d.label != "" {
+			labels = append(labels, d.label)
+		}
+	}
+	return labels
+}
+
+// syncCounterCursors matches the cursors to the deployments after a
+// reload: cursors of kept programs go on where they are, added programs
+// resume from their checkpoint and removed ones are dropped. It reports
+// false if a cursor could not be opened, to be retried.
+func (i *Indexer) syncCounterCursors(ctx context.Context, cursors []*programCursor, deployments []*counterDeployment) ([]*programCursor, bool) {
+	byProgram := make(map[solana.PublicKey]*programCursor, len(cursors))
+	for _, c := range cursors {
+		byProgram[c.program] = c
+	}
+
+	synced := make([]*programCursor, 0, len(deployments))
+	ok := true
+	for _, d := range deployments {
+		c, found := byProgram[d.program]
+		if !found {
+			var err error
+			c, err = i.openCursor(ctx, d.cursorName(), d.program, i.counterDecoder(d))
+			if err != nil {
+				log.Printf("error opening %s cursor: %v", d.cursorName(), err)
+				ok = false
+				continue
+			}
+			log.Printf("starting indexer for Counter Program %s", d)
+		}
+		c.name = d.cursorName()
+		c.decode = i.counterDecoder(d)
+		synced = append(synced, c)
+	}
+	for program, c := range byProgram {
+		if !slices.ContainsFunc(deployments, func(d *counterDeployment) bool { return d.program == program }) {
+			log.Printf("stopped indexing %s %s", c.name, program)
+		}
+	}
+	return synced, ok
+}
@@ -0,0 +1,57 @@
+package indexer
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/failure"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+// recordFailure counts err by kind and, when the database can keep them,
+// records the transaction as a dead letter. It returns the kind for the
+// caller's log line.
+func (i *Indexer) recordFailure(ctx context.Context, program solana.PublicKey, signature solana.Signature, slot uint64, err error) failure.Kind {
+	return recordDeadLetter(ctx, i.repo, i.failures, program.String(), signature.String(), slot, err)
+}
+
+func recordDeadLetter(ctx context.Context, repo repository.Repository, failures *failure.Counter, program, signature string, slot uint64, err error) failure.Kind {
+	kind := failures.Record(err)
+
+	store, ok := repository.Unwrap(repo).(repository.DeadLetterStore)
+	if !ok || repository.IsUnavailable(err) {
+		// A database that cannot store the event cannot store its failure.
+		return kind
+	}
+	letter := &models.DeadLetter{
+		Signature:    signature,
+		Program:      program,
+		Slot:         slot,
+		Kind:         string(kind),
+		Error:        err.Error(),
+		LastFailedAt: time.Now().UTC(),
+	}
+	if err := store.SaveDeadLetter(ctx, letter); err != nil {
+		log.Printf("warning: failed to record dead letter %s: %v", signature, err)
+	}
+	return kind
+}
+
+// sinkFailureRecorder returns the AWSOptions.OnFailure of sink: the event
+// is already stored, so it is recorded as a dead letter of kind sink to be
+// sent again with resink.
+func sinkFailureRecorder(repo repository.Repository, failures *failure.Counter, sink string) func(models.BaseEvent, error) {
+	return func(event models.BaseEvent, err error) {
+		err = &failure.SinkError{Sink: sink, Err: err}
+		log.Printf("warning: %s %s: %v", event.EventType, event.Signature, err)
+		recordDeadLetter(context.Background(), repo, failures, event.ProgramID.String(), event.Signature, event.Slot, err)
+	}
+}
+
+// PipelineErrors returns the number of pipeline errors since start, by kind.
+func (i *Indexer) PipelineErrors() map[failure.Kind]uint64 {
+	return i.failures.Counts()
+}
@@ -2,87 +2,532 @@ package indexer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/lugondev/go-indexer-solana-starter/internal/accountmon"
+	"github.com/lugondev/go-indexer-solana-starter/internal/aggregate"
+	"github.com/lugondev/go-indexer-solana-starter/internal/analytics"
+	"github.com/lugondev/go-indexer-solana-starter/internal/cache"
+	"github.com/lugondev/go-indexer-solana-starter/internal/coldstore"
 	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/coverage"
 	"github.com/lugondev/go-indexer-solana-starter/internal/decoder"
+	"github.com/lugondev/go-indexer-solana-starter/internal/failure"
+	"github.com/lugondev/go-indexer-solana-starter/internal/identity"
+	"github.com/lugondev/go-indexer-solana-starter/internal/mirror"
 	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/nftmeta"
+	"github.com/lugondev/go-indexer-solana-starter/internal/outbox"
 	"github.com/lugondev/go-indexer-solana-starter/internal/processor"
+	"github.com/lugondev/go-indexer-solana-starter/internal/report"
 	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+	"github.com/lugondev/go-indexer-solana-starter/internal/rollup"
+	"github.com/lugondev/go-indexer-solana-starter/internal/seen"
+	"github.com/lugondev/go-indexer-solana-starter/internal/sink"
+	"github.com/lugondev/go-indexer-solana-starter/internal/spool"
+	"github.com/lugondev/go-indexer-solana-starter/internal/upgrade"
+	"github.com/lugondev/go-indexer-solana-starter/internal/watchdog"
+	pkgdecoder "github.com/lugondev/go-indexer-solana-starter/pkg/decoder"
 	solanaClient "github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+	"golang.org/x/sync/errgroup"
 )
 
 type Indexer struct {
 	cfg              *config.Config
 	client           *solanaClient.Client
 	repo             repository.Repository
+	sharedRepo       bool // repo belongs to the caller, who closes it
+	redis            *cache.RedisClient
+	sinks            []sink.Sink
+	webhooks         *sink.Switch
+	lagTracker       *sink.LagTracker
+	windows          *aggregate.Engine
+	nftEnricher      *nftmeta.Enricher
+	seen             *seen.Cache
+	buffer           *spool.Spool
+	coverage         *coverage.Tracker
+	rollups          *rollup.Tracker
+	failures         *failure.Counter
+	dispatchers      []*outbox.Dispatcher
+	accountMonitor   *accountmon.Monitor
+	upgradeWatcher   *upgrade.Watcher
+	watchdog         *watchdog.Watchdog
 	starterProcessor *processor.EventProcessor
-	counterProcessor *processor.EventProcessor
 	eventDecoder     *decoder.EventDecoder
-	counterLogParser *decoder.CounterLogParser
+	counters         []*counterDeployment
+	counterGen       uint64
+	logPrograms      []*logProgram
+	customPrograms   []*customProgram
+	tokenMints       []*tokenMint
+	solWatchlist     *solWatchlist
+	watchProcessor   *processor.EventProcessor
+	watchers         map[solana.PublicKey]*watcher
+	watchSync        chan struct{}
+	pipelines        map[solana.PublicKey]*pipelineQueues
+	applied          *config.Config
+	reloadMu         sync.Mutex
+	configMirror     *mirror.ConfigMirror
+	retention        *repository.RetentionPolicy
+	coldExporter     *coldstore.Exporter
+	reports          *report.Scheduler
+	programDataMode  pkgdecoder.ProgramDataMode
 	starterProgramID solana.PublicKey
-	counterProgramID solana.PublicKey
 	currentSlot      uint64
-	lastStarterSig   *solana.Signature
-	lastCounterSig   *solana.Signature
+	clusterConfirmed uint64
+	clusterFinalized uint64
+	complete         map[solana.PublicKey]uint64
 	mu               sync.RWMutex
 	isRunning        bool
-	shutdownOnce     sync.Once
+	paused           atomic.Bool
+	// runCtx is the context of Start, which StartBackfill runs in.
+	runCtx       context.Context
+	backfillRun  *models.BackfillStatus
+	shutdownOnce sync.Once
 }
 
 func New(cfg *config.Config) (*Indexer, error) {
+	return newIndexer(cfg, nil)
+}
+
+// NewWithRepository returns an indexer storing its events in repo, opened
+// with NewRepository and shared with other indexers, such as those of the
+// other networks of NETWORKS. Sharing it lets them number events without
+// handing out a sequence twice. repo is not closed on Shutdown.
+func NewWithRepository(cfg *config.Config, repo repository.Repository) (*Indexer, error) {
+	return newIndexer(cfg, repo)
+}
+
+func newIndexer(cfg *config.Config, shared repository.Repository) (*Indexer, error) {
 	if cfg == nil {
 		return nil, fmt.Errorf("config cannot be nil")
 	}
 
-	client, err := solanaClient.NewClient(cfg.SolanaRPCURL, cfg.SolanaWSURL)
+	endpoints, err := cfg.RPCEndpoints()
+	if err != nil {
+		return nil, err
+	}
+	var rpcEndpoints []solanaClient.Endpoint
+	for _, e := range endpoints {
+		rpcEndpoints = append(rpcEndpoints, solanaClient.Endpoint{
+			Name:   e.Name,
+			URL:    e.URL,
+			Budget: solanaClient.Budget{PerSecond: e.PerSecond, PerDay: e.PerDay},
+		})
+	}
+	client, err := solanaClient.NewClientWithEndpoints(rpcEndpoints, cfg.SolanaWSURL)
 	if err != nil {
 		return nil, fmt.Errorf("create solana client: %w", err)
 	}
+	if cfg.RPCBreakerThreshold > 0 {
+		client.SetCircuitBreaker(solanaClient.NewCircuitBreaker(cfg.RPCBreakerThreshold, cfg.RPCBreakerCooldown))
+	}
+	if cfg.RPCMaxInFlight > 0 {
+		weights, err := cfg.RPCWeights()
+		if err != nil {
+			return nil, err
+		}
+		priorities := make(map[solanaClient.Priority]int, len(weights))
+		for name, weight := range weights {
+			if p, ok := solanaClient.ParsePriority(name); ok {
+				priorities[p] = weight
+			}
+		}
+		client.SetScheduler(solanaClient.NewScheduler(cfg.RPCMaxInFlight, priorities))
+	}
 
 	starterProgramID, err := solana.PublicKeyFromBase58(cfg.StarterProgramID)
 	if err != nil {
 		return nil, fmt.Errorf("parse starter program ID: %w", err)
 	}
 
-	counterProgramID, err := solana.PublicKeyFromBase58(cfg.CounterProgramID)
+	counters, err := newCounterDeployments(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("parse counter program ID: %w", err)
+		return nil, err
 	}
 
-	var repo repository.Repository
-	switch cfg.DatabaseType {
-	case config.DatabaseTypeMongo:
-		repo, err = repository.NewMongoRepository(cfg.DatabaseURL, cfg.DatabaseName)
+	programDataMode, err := pkgdecoder.ParseProgramDataMode(cfg.ProgramDataMode)
+	if err != nil {
+		return nil, fmt.Errorf("parse program data mode: %w", err)
+	}
+
+	repo := shared
+	if repo == nil {
+		repo, err = NewRepository(cfg)
 		if err != nil {
-			return nil, fmt.Errorf("create mongo repository: %w", err)
+			return nil, err
+		}
+	}
+	if cfg.BlockTimeCacheSize > 0 {
+		// Block times are persisted when the database can store them.
+		store, _ := repository.Unwrap(repo).(solanaClient.BlockTimeStore)
+		client.SetBlockTimeCache(solanaClient.NewBlockTimeCache(cfg.BlockTimeCacheSize, store))
+	}
+	if names := cfg.OutboxSinks(); len(names) > 0 {
+		repo, err = repository.NewOutboxRepository(repo, names)
+		if err != nil {
+			return nil, fmt.Errorf("create outbox: %w", err)
+		}
+	}
+	dispatchers, err := newOutboxDispatchers(cfg, repo)
+	if err != nil {
+		return nil, fmt.Errorf("create outbox dispatchers: %w", err)
+	}
+
+	coldExporter, err := newColdExporter(cfg, repo)
+	if err != nil {
+		return nil, fmt.Errorf("create cold storage exporter: %w", err)
+	}
+
+	reports, err := newReportScheduler(cfg, repo)
+	if err != nil {
+		return nil, fmt.Errorf("create report scheduler: %w", err)
+	}
+
+	var redisClient *cache.RedisClient
+	if cfg.RedisURL != "" {
+		redisClient, err = cache.NewRedisClient(cfg.RedisURL)
+		if err != nil {
+			return nil, fmt.Errorf("create redis client: %w", err)
+		}
+		repo = repository.NewCachedRepository(repo, redisClient, repository.CacheOptions{
+			EventTTL:  cfg.RedisEventTTL,
+			LatestTTL: cfg.RedisLatestTTL,
+		})
+		if cfg.RedisHotWindow > 0 {
+			repo = repository.NewHotRepository(repo, redisClient, repository.HotOptions{Window: cfg.RedisHotWindow})
 		}
-	default:
-		return nil, fmt.Errorf("unsupported database type: %s", cfg.DatabaseType)
 	}
 
-	starterProcessor := processor.NewEventProcessor(repo, starterProgramID)
-	counterProcessor := processor.NewEventProcessor(repo, counterProgramID)
+	failures := failure.NewCounter()
+	sinks, err := newSinks(cfg, repo, failures)
+	if err != nil {
+		return nil, fmt.Errorf("create sinks: %w", err)
+	}
+	webhookSinks, err := newWebhookSinks(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("create sinks: %w", err)
+	}
+	webhooks := sink.NewSwitch(webhookSinks...)
+	sinks = append(sinks, webhooks)
+	if redisClient != nil {
+		sinks = append(sinks, sink.NewRedisPubSubSink(redisClient, cfg.RedisChannelPrefix))
+	}
+
+	var windows *aggregate.Engine
+	if len(cfg.StreamWindows) > 0 {
+		windows, err = newWindowEngine(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("create stream windows: %w", err)
+		}
+		sinks = append(sinks, windows)
+	}
+
+	if store, ok := repository.Unwrap(repo).(repository.NftSalesStore); ok {
+		sinks = append(sinks, analytics.NewSalesTracker(store))
+	}
+	if store, ok := repository.Unwrap(repo).(repository.TokenHolderStore); ok {
+		sinks = append(sinks, analytics.NewTokenTracker(store))
+	}
+	if store, ok := repository.Unwrap(repo).(repository.TokenAccountStore); ok {
+		sinks = append(sinks, analytics.NewTokenAccountTracker(store))
+	}
+
+	nftEnricher := newNftEnricher(cfg, repo)
+	if nftEnricher != nil {
+		sinks = append(sinks, nftEnricher)
+	}
+
+	seenCache, err := newSeenCache(cfg, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var lagTracker *sink.LagTracker
+	if cfg.SinkLagInterval > 0 {
+		lagTracker = sink.NewLagTracker(cfg.SinkLagInterval, sinks...)
+	}
+
+	starterProcessor := processor.NewEventProcessor(repo, starterProgramID, sinks...)
+	starterProcessor.SetTenant(cfg.ProgramTenants[cfg.StarterProgramID])
+	starterProcessor.SetNetwork(cfg.Network)
+	if cfg.IdentityProvider == "sns" {
+		resolver := identity.NewCachedResolver(identity.NewSNSResolver(client), cfg.IdentityCacheTTL)
+		starterProcessor.SetIdentityResolver(resolver)
+	}
+	filter, err := newEventFilter(cfg)
+	if err != nil {
+		return nil, err
+	}
+	starterProcessor.SetFilter(filter)
+	if fields := cfg.DedupFieldsByType(); len(fields) > 0 {
+		store, ok := repository.Unwrap(repo).(repository.DedupStore)
+		if !ok {
+			return nil, fmt.Errorf("DEDUP_FIELDS is not supported by the %s repository", cfg.DatabaseType)
+		}
+		strategy, err := processor.NewFieldHash(fields)
+		if err != nil {
+			return nil, fmt.Errorf("DEDUP_FIELDS: %w", err)
+		}
+		starterProcessor.SetDedup(strategy, store, cfg.DedupWindow)
+	}
+	fieldCipher, err := cfg.FieldCipher()
+	if err != nil {
+		return nil, err
+	}
+	starterProcessor.SetCipher(fieldCipher)
+	var rollups *rollup.Tracker
+	if store, ok := repository.Unwrap(repo).(repository.RollupStore); ok && cfg.EventRollupsEnabled {
+		rollups = rollup.NewTracker(store)
+		starterProcessor.SetRollups(rollups)
+	}
+	if t, ok := repository.Unwrap(repo).(repository.Transactor); ok && t.SupportsTransactions() {
+		starterProcessor.SetTransactor(t)
+	}
+	var buffer *spool.Spool
+	if cfg.OfflineBufferDir != "" {
+		buffer, err = spool.Open(cfg.OfflineBufferDir, int64(cfg.OfflineBufferMaxMB)<<20)
+		if err != nil {
+			return nil, fmt.Errorf("open offline buffer: %w", err)
+		}
+		starterProcessor.SetSpool(buffer)
+	}
+	// Counter processors share the starter processor's settings.
+	for _, d := range counters {
+		d.attach(starterProcessor)
+	}
+	logPrograms, err := newLogPrograms(cfg, starterProcessor)
+	if err != nil {
+		return nil, err
+	}
+	tokenMints, err := newTokenMints(cfg, starterProcessor)
+	if err != nil {
+		return nil, err
+	}
+	indexed := append([]solana.PublicKey{starterProgramID}, counterPrograms(counters)...)
+	indexed = append(indexed, logProgramIDs(logPrograms)...)
+	customPrograms, err := newCustomPrograms(cfg, client, starterProcessor, append(indexed, tokenMintKeys(tokenMints)...))
+	if err != nil {
+		return nil, err
+	}
+	indexed = append(indexed, customProgramIDs(customPrograms)...)
+	solWatchlist, err := newSolWatchlist(cfg, starterProcessor, append(indexed, tokenMintKeys(tokenMints)...))
+	if err != nil {
+		return nil, err
+	}
+	coverageStore, _ := repository.Unwrap(repo).(repository.CoverageStore)
 	eventDecoder := decoder.NewEventDecoder()
-	counterLogParser := decoder.NewCounterLogParser(counterProgramID)
 
-	return &Indexer{
+	var configMirror *mirror.ConfigMirror
+	if cfg.ConfigMirrorEnabled {
+		configMirror, err = mirror.NewConfigMirror(repo, client, starterProgramID)
+		if err != nil {
+			return nil, fmt.Errorf("create config mirror: %w", err)
+		}
+	}
+
+	idx := &Indexer{
 		cfg:              cfg,
+		applied:          cfg,
+		retention:        retentionPolicy(cfg),
+		coldExporter:     coldExporter,
+		reports:          reports,
 		client:           client,
 		repo:             repo,
+		sharedRepo:       shared != nil,
+		redis:            redisClient,
+		sinks:            sinks,
+		webhooks:         webhooks,
+		lagTracker:       lagTracker,
+		windows:          windows,
+		nftEnricher:      nftEnricher,
+		seen:             seenCache,
+		buffer:           buffer,
+		coverage:         coverage.NewTracker(coverageStore),
+		rollups:          rollups,
+		failures:         failures,
+		dispatchers:      dispatchers,
+		accountMonitor:   newAccountMonitor(cfg, client, repo, indexed...),
 		starterProcessor: starterProcessor,
-		counterProcessor: counterProcessor,
 		eventDecoder:     eventDecoder,
-		counterLogParser: counterLogParser,
+		counters:         counters,
+		logPrograms:      logPrograms,
+		customPrograms:   customPrograms,
+		tokenMints:       tokenMints,
+		solWatchlist:     solWatchlist,
+		watchSync:        make(chan struct{}, 1),
+		pipelines:        make(map[solana.PublicKey]*pipelineQueues),
+		complete:         make(map[solana.PublicKey]uint64),
+		configMirror:     configMirror,
+		programDataMode:  programDataMode,
 		starterProgramID: starterProgramID,
-		counterProgramID: counterProgramID,
 		currentSlot:      cfg.StartSlot,
 		isRunning:        false,
-	}, nil
+	}
+	// Watched transactions are stored under the System Program and also
+	// sent to the watchlist webhooks.
+	idx.watchProcessor = starterProcessor.WithProgram(solana.SystemProgramID)
+	idx.watchProcessor.AddSink(sink.NewWatchlistSink(sink.WatchlistOptions{
+		URL:     cfg.WatchlistWebhookURL,
+		Secret:  cfg.WatchlistWebhookSecret,
+		Webhook: idx.watchedWebhook,
+	}))
+	idx.watchdog = newWatchdog(cfg, idx)
+	idx.upgradeWatcher = newUpgradeWatcher(cfg, client, idx.handleUpgrade, indexed...)
+	return idx, nil
+}
+
+// coverageFlushInterval is how often the daily decoder coverage is stored.
+const coverageFlushInterval = time.Minute
+
+// rollupFlushInterval is how often the event rollup counts are stored.
+const rollupFlushInterval = 10 * time.Second
+
+// NewRepository connects to the configured event store.
+func NewRepository(cfg *config.Config) (repository.Repository, error) {
+	return openRepository(cfg, cfg.DatabaseType, cfg.DatabaseURL)
+}
+
+// openRepository opens a database of the given type with the layout and
+// schema options of cfg, for the primary database and store sinks alike.
+func openRepository(cfg *config.Config, dbType config.DatabaseType, url string) (repository.Repository, error) {
+	rawData, err := repository.ParseRawDataCompression(cfg.RawDataCompression)
+	if err != nil {
+		return nil, fmt.Errorf("RAW_DATA_COMPRESSION: %w", err)
+	}
+	switch dbType {
+	case config.DatabaseTypeMongo:
+		layout, err := repository.ParseMongoLayout(cfg.MongoCollectionLayout)
+		if err != nil {
+			return nil, fmt.Errorf("parse mongo collection layout: %w", err)
+		}
+		opts := repository.MongoOptions{Layout: layout, RawDataCompression: rawData}
+		if err := applyMongoSchema(cfg, &opts); err != nil {
+			return nil, err
+		}
+		repo, err := repository.NewMongoRepository(url, cfg.DatabaseName, opts)
+		if err != nil {
+			return nil, fmt.Errorf("create mongo repository: %w", err)
+		}
+		return repo, nil
+	case config.DatabaseTypePostgres:
+		repo, err := repository.NewPostgresRepository(url, repository.PostgresOptions{
+			PartitionSlots:     uint64(cfg.PostgresPartitionSlots),
+			CopyThreshold:      cfg.PostgresCopyThreshold,
+			RawDataCompression: rawData,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("create postgres repository: %w", err)
+		}
+		return repo, nil
+	default:
+		return nil, fmt.Errorf("unsupported database type: %s", dbType)
+	}
+}
+
+// newSeenCache returns nil when SEEN_CACHE_SIZE is 0. Bloom filter hits are
+// confirmed by looking the signature up in the event store.
+func newSeenCache(cfg *config.Config, repo repository.Repository) (*seen.Cache, error) {
+	if cfg.SeenCacheSize == 0 {
+		return nil, nil
+	}
+	confirm := func(ctx context.Context, signature solana.Signature) (bool, error) {
+		event, err := repo.GetEventBySignature(ctx, signature.String())
+		return event != nil, err
+	}
+	cache, err := seen.New(seen.Options{
+		Size:                   cfg.SeenCacheSize,
+		BloomCapacity:          cfg.SeenBloomCapacity,
+		BloomFalsePositiveRate: cfg.SeenBloomFalsePositiveRate,
+		Path:                   cfg.SeenBloomPath,
+		SaveInterval:           cfg.SeenBloomSaveInterval,
+	}, confirm)
+	if err != nil {
+		return nil, fmt.Errorf("create seen signature cache: %w", err)
+	}
+	return cache, nil
+}
+
+// processNew processes a transaction unless the seen cache knows it was
+// processed before and reprocess is false, and reports whether it did. A
+// failing cache lookup is logged and the transaction processed anyway.
+func (i *Indexer) processNew(ctx context.Context, signature solana.Signature, reprocess bool, process func(context.Context, solana.Signature) error) (bool, error) {
+	if i.seen != nil && !reprocess {
+		done, err := i.seen.Seen(ctx, signature)
+		if err != nil {
+			log.Printf("warning: %v", err)
+		}
+		if done {
+			return false, nil
+		}
+	}
+	if err := process(ctx, signature); err != nil {
+		return false, err
+	}
+	if i.seen != nil {
+		i.seen.Add(signature)
+	}
+	return true, nil
+}
+
+func newWindowEngine(cfg *config.Config) (*aggregate.Engine, error) {
+	names := make([]string, 0, len(cfg.StreamWindows))
+	for name := range cfg.StreamWindows {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	specs := make([]aggregate.Spec, 0, len(names))
+	for _, name := range names {
+		spec, err := aggregate.ParseSpec(name, cfg.StreamWindows[name])
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+	return aggregate.NewEngine(specs, cfg.StreamWindowHistory), nil
+}
+
+// applyMongoSchema adds the per event type collection overrides and extra
+// indexes from cfg to opts.
+func applyMongoSchema(cfg *config.Config, opts *repository.MongoOptions) error {
+	for name, collection := range cfg.MongoCollectionOverrides {
+		eventType := models.EventType(name)
+		if !eventType.Known() {
+			return fmt.Errorf("MONGO_COLLECTION_OVERRIDES: unknown event type %q", name)
+		}
+		if opts.Collections == nil {
+			opts.Collections = make(map[models.EventType]string)
+		}
+		opts.Collections[eventType] = collection
+	}
+
+	for name, raw := range cfg.MongoExtraIndexes {
+		eventType := models.EventType(name)
+		if !eventType.Known() {
+			return fmt.Errorf("MONGO_EXTRA_INDEXES: unknown event type %q", name)
+		}
+		specs, err := repository.ParseIndexSpecs(raw)
+		if err != nil {
+			return fmt.Errorf("MONGO_EXTRA_INDEXES: %s: %w", name, err)
+		}
+		if opts.Indexes == nil {
+			opts.Indexes = make(map[models.EventType][]repository.IndexSpec)
+		}
+		opts.Indexes[eventType] = specs
+	}
+	return nil
 }
 
 func (i *Indexer) Start(ctx context.Context) error {
@@ -94,238 +539,799 @@ func (i *Indexer) Start(ctx context.Context) error {
 	i.isRunning = true
 	i.mu.Unlock()
 
-	log.Printf("starting indexer for Starter Program %s from slot %d", i.starterProgramID.String(), i.currentSlot)
-	log.Printf("starting indexer for Counter Program %s from slot %d", i.counterProgramID.String(), i.currentSlot)
-
-	if mongoRepo, ok := i.repo.(*repository.MongoRepository); ok {
-		if err := mongoRepo.CreateIndexes(ctx); err != nil {
-			log.Printf("warning: failed to create indexes: %v", err)
-		}
+	if i.cfg.Network != "" {
+		log.Printf("indexing network %s", i.cfg.Network)
+	}
+	log.Printf("starting indexer for Starter Program %s", i.starterProgramID.String())
+	deployments, counterGen := i.counterDeployments()
+	for _, d := range deployments {
+		log.Printf("starting indexer for Counter Program %s", d)
+	}
+	for _, lp := range i.logPrograms {
+		log.Printf("starting indexer for %s program %s from its logs", lp.grammar.Name, lp.grammar.Program)
+	}
+	for _, cp := range i.customPrograms {
+		log.Printf("starting indexer for program %s with its registered decoder", cp.program)
+	}
+	for _, m := range i.tokenMints {
+		log.Printf("starting indexer for SPL token mint %s", m.mint)
+	}
+	for _, address := range i.solWatchlist.keys() {
+		log.Printf("starting indexer for SOL transfers of %s", address)
 	}
 
-	ticker := time.NewTicker(i.cfg.PollInterval)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			log.Println("indexer context cancelled")
-			return ctx.Err()
-		case <-ticker.C:
-			if err := i.processStarterSignatures(ctx); err != nil {
-				log.Printf("error processing starter signatures: %v", err)
+	if i.cfg.DatabaseAutoMigrate {
+		switch repo := repository.Unwrap(i.repo).(type) {
+		case *repository.MongoRepository:
+			if err := repo.CreateIndexes(ctx); err != nil {
+				log.Printf("warning: failed to create indexes: %v", err)
+			}
+		case *repository.PostgresRepository:
+			applied, err := repo.Migrate(ctx)
+			if err != nil {
+				i.mu.Lock()
+				i.isRunning = false
+				i.mu.Unlock()
+				return fmt.Errorf("migrate database: %w", err)
 			}
-			if err := i.processCounterSignatures(ctx); err != nil {
-				log.Printf("error processing counter signatures: %v", err)
+			for _, m := range applied {
+				log.Printf("applied migration %04d_%s", m.Version, m.Name)
 			}
 		}
 	}
-}
-
-func (i *Indexer) processStarterSignatures(ctx context.Context) error {
-	i.mu.RLock()
-	programID := i.starterProgramID
-	lastSig := i.lastStarterSig
-	i.mu.RUnlock()
 
-	sigs, err := i.client.GetSignaturesForAddress(ctx, programID, i.cfg.BatchSize, lastSig, nil)
+	starter, err := i.openCursor(ctx, "starter", i.starterProgramID, i.decodeStarterTransaction)
+	var counters []*programCursor
+	for _, d := range deployments {
+		if err != nil {
+			break
+		}
+		var counter *programCursor
+		counter, err = i.openCursor(ctx, d.cursorName(), d.program, i.counterDecoder(d))
+		counters = append(counters, counter)
+	}
+	var cursors []*programCursor
+	for _, lp := range i.logPrograms {
+		if err != nil {
+			break
+		}
+		var c *programCursor
+		c, err = i.openCursor(ctx, lp.grammar.Name, lp.grammar.Program, i.logDecoder(lp))
+		cursors = append(cursors, c)
+	}
+	for _, cp := range i.customPrograms {
+		if err != nil {
+			break
+		}
+		var c *programCursor
+		c, err = i.openCursor(ctx, "custom", cp.program, i.customDecoder(cp))
+		cursors = append(cursors, c)
+	}
+	for _, m := range i.tokenMints {
+		if err != nil {
+			break
+		}
+		var c *programCursor
+		c, err = i.openCursor(ctx, "spl-token", m.mint, i.tokenDecoder(m))
+		cursors = append(cursors, c)
+	}
+	for _, address := range i.solWatchlist.keys() {
+		if err != nil {
+			break
+		}
+		var c *programCursor
+		c, err = i.openCursor(ctx, "sol", address, i.solDecoder(address))
+		cursors = append(cursors, c)
+	}
 	if err != nil {
-		return fmt.Errorf("get signatures: %w", err)
+		i.mu.Lock()
+		i.isRunning = false
+		i.mu.Unlock()
+		return err
 	}
 
-	if len(sigs) == 0 {
-		return nil
+	programs := []solana.PublicKey{i.starterProgramID}
+	for _, d := range deployments {
+		programs = append(programs, d.program)
 	}
+	programs = append(programs, logProgramIDs(i.logPrograms)...)
+	programs = append(programs, customProgramIDs(i.customPrograms)...)
+	programs = append(programs, tokenMintKeys(i.tokenMints)...)
+	programs = append(programs, i.solWatchlist.keys()...)
+	i.loadWatermarks(ctx, programs)
+	go i.runWatermarks(ctx)
 
-	log.Printf("processing %d starter program signatures", len(sigs))
+	i.mu.Lock()
+	i.runCtx = ctx
+	i.mu.Unlock()
 
-	for _, sig := range sigs {
-		if err := i.processStarterTransaction(ctx, sig.Signature); err != nil {
-			log.Printf("error processing starter transaction %s: %v", sig.Signature, err)
-			continue
-		}
+	if i.configMirror != nil {
+		go func() {
+			if err := i.configMirror.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				log.Printf("config mirror stopped: %v", err)
+			}
+		}()
 	}
 
-	i.mu.Lock()
-	i.lastStarterSig = &sigs[len(sigs)-1].Signature
-	i.mu.Unlock()
+	if repo, ok := repository.Unwrap(i.repo).(*repository.PostgresRepository); ok && i.cfg.PostgresPartitionSlots > 0 {
+		go i.maintainPartitions(ctx, repo)
+	}
 
-	return nil
-}
+	if i.retention != nil {
+		if pruner, ok := repository.Unwrap(i.repo).(repository.Pruner); ok {
+			go i.runRetention(ctx, pruner)
+		} else {
+			log.Printf("warning: retention is configured but %T does not support pruning", repository.Unwrap(i.repo))
+		}
+	}
 
-func (i *Indexer) processCounterSignatures(ctx context.Context) error {
-	i.mu.RLock()
-	programID := i.counterProgramID
-	lastSig := i.lastCounterSig
-	i.mu.RUnlock()
+	if i.coldExporter != nil {
+		go i.coldExporter.Run(ctx)
+	}
 
-	sigs, err := i.client.GetSignaturesForAddress(ctx, programID, i.cfg.BatchSize, lastSig, nil)
-	if err != nil {
-		return fmt.Errorf("get signatures: %w", err)
+	if i.lagTracker != nil {
+		go i.lagTracker.Run(ctx)
 	}
 
-	if len(sigs) == 0 {
-		return nil
+	if i.reports != nil {
+		go i.reports.Run(ctx)
 	}
 
-	log.Printf("processing %d counter program signatures", len(sigs))
+	if i.nftEnricher != nil {
+		go i.nftEnricher.Run(ctx)
+	}
 
-	for _, sig := range sigs {
-		if err := i.processCounterTransaction(ctx, sig.Signature); err != nil {
-			log.Printf("error processing counter transaction %s: %v", sig.Signature, err)
-			continue
-		}
+	if i.seen != nil {
+		go i.seen.Run(ctx)
 	}
 
-	i.mu.Lock()
-	i.lastCounterSig = &sigs[len(sigs)-1].Signature
-	i.mu.Unlock()
+	if i.buffer != nil {
+		go i.replayBuffer(ctx)
+	}
 
-	return nil
-}
+	go i.coverage.Run(ctx, coverageFlushInterval)
+	if i.rollups != nil {
+		go i.rollups.Run(ctx, rollupFlushInterval)
+	}
 
-func (i *Indexer) processStarterTransaction(ctx context.Context, signature solana.Signature) error {
-	tx, err := i.client.GetTransaction(ctx, signature)
-	if err != nil {
-		return fmt.Errorf("get transaction: %w", err)
+	for _, d := range i.dispatchers {
+		go d.Run(ctx)
 	}
 
-	if tx == nil || tx.Meta == nil {
-		return nil
+	if i.accountMonitor != nil {
+		go i.accountMonitor.Run(ctx)
 	}
+	if i.upgradeWatcher != nil {
+		i.seedUpgrades(ctx)
+		go i.upgradeWatcher.Run(ctx)
+	}
+	if i.watchdog != nil {
+		go i.watchdog.Run(ctx)
+	}
+
+	// Every program is polled in its own goroutine, so a slow one does not
+	// delay the others.
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error { return i.runProgram(gctx, starter) })
+	g.Go(func() error { return i.runCounters(gctx, counters, counterGen) })
+	for _, c := range cursors {
+		g.Go(func() error { return i.runProgram(gctx, c) })
+	}
+	g.Go(func() error { return i.runWatchlist(gctx) })
+	err = g.Wait()
+	log.Println("indexer context cancelled")
+	return err
+}
+
+func (i *Indexer) processStarterTransaction(ctx context.Context, signature solana.Signature) error {
+	return i.process(i.decodeStarterTransaction)(ctx, signature)
+}
 
-	blockTime := time.Unix(int64(tx.BlockTime.Time().Unix()), 0)
+func (i *Indexer) decodeStarterTransaction(ctx context.Context, fetched *fetchedTransaction) (*decodedTransaction, error) {
+	signature, tx := fetched.signature, fetched.tx
 	slot := tx.Slot
 
-	logs := tx.Meta.LogMessages
-	if len(logs) == 0 {
-		return nil
-	}
+	programDataList, found := i.starterEventData(tx)
 
-	programDataList := decoder.ParseProgramData(logs)
+	// Payloads found but not parsed had invalid base64.
+	cov := models.DecoderCoverage{
+		Transactions: 1,
+		Found:        uint64(found),
+		Failed:       uint64(max(found-len(programDataList), 0)),
+	}
+	decoded := &decodedTransaction{
+		signature: signature,
+		slot:      slot,
+		blockTime: fetched.blockTime,
+		program:   i.starterProgramID,
+		processor: i.starterProcessor,
+		kind:      "starter",
+		mirror:    true,
+		coverage:  &cov,
+	}
 
 	for _, data := range programDataList {
 		eventType, eventData, err := i.eventDecoder.DecodeEvent(data)
 		if err != nil {
+			if errors.Is(err, decoder.ErrUnknownEvent) {
+				cov.Unknown++
+			} else {
+				cov.Failed++
+			}
+			i.recordFailure(ctx, i.starterProgramID, signature, slot, &failure.DecodeError{EventType: eventType, Err: err})
 			log.Printf("failed to decode event: %v", err)
 			continue
 		}
-
-		if err := i.starterProcessor.ProcessEvent(ctx, signature.String(), slot, blockTime, eventType, eventData); err != nil {
-			log.Printf("failed to process event: %v", err)
-			continue
-		}
-
-		log.Printf("processed starter event %s at slot %d", eventType, slot)
+		cov.Decoded++
+		decoded.events = append(decoded.events, decodedEvent{eventType: eventType, data: eventData, raw: i.rawData(data)})
 	}
+	return decoded, nil
+}
 
-	return nil
+// rawData returns the payload to keep with its event, nil unless
+// STORE_RAW_DATA is set.
+func (i *Indexer) rawData(data []byte) []byte {
+	if !i.cfg.StoreRawData {
+		return nil
+	}
+	return data
 }
 
-func (i *Indexer) processCounterTransaction(ctx context.Context, signature solana.Signature) error {
-	tx, err := i.client.GetTransaction(ctx, signature)
+// blockTime returns the block time of tx, looking it up by slot when the
+// transaction came without one.
+func (i *Indexer) blockTime(ctx context.Context, tx *rpc.GetTransactionResult) time.Time {
+	if tx.BlockTime != nil {
+		return time.Unix(int64(*tx.BlockTime), 0)
+	}
+	blockTime, err := i.client.GetBlockTime(ctx, tx.Slot)
 	if err != nil {
-		return fmt.Errorf("get transaction: %w", err)
+		log.Printf("warning: no block time for slot %d: %v", tx.Slot, err)
+		return time.Time{}
 	}
+	return time.Unix(blockTime, 0)
+}
 
-	if tx == nil || tx.Meta == nil {
-		return nil
+func (i *Indexer) decodeCounterTransaction(ctx context.Context, d *counterDeployment, fetched *fetchedTransaction) (*decodedTransaction, error) {
+	signature, tx := fetched.signature, fetched.tx
+	if len(tx.Meta.LogMessages) == 0 {
+		return nil, nil
 	}
 
-	blockTime := time.Unix(int64(tx.BlockTime.Time().Unix()), 0)
-	slot := tx.Slot
+	actions, err := counterActions(d, tx)
+	if err != nil {
+		return nil, &failure.DecodeError{Err: err}
+	}
 
+	decoded := &decodedTransaction{
+		signature: signature,
+		slot:      tx.Slot,
+		blockTime: fetched.blockTime,
+		program:   d.program,
+		processor: d.processor,
+		kind:      "counter",
+	}
+	for _, action := range actions {
+		eventData, err := i.convertCounterActionToEvent(action)
+		if err != nil {
+			i.recordFailure(ctx, d.program, signature, tx.Slot, &failure.DecodeError{EventType: action.Type, Err: err})
+			log.Printf("failed to build counter event: %v", err)
+			continue
+		}
+		decoded.events = append(decoded.events, decodedEvent{eventType: action.Type, data: eventData})
+	}
+	return decoded, nil
+}
+
+// starterEventData returns the starter program's event payloads in tx,
+// from its logs and emit_cpi! instructions, and how many were found,
+// including those that could not be read.
+func (i *Indexer) starterEventData(tx *rpc.GetTransactionResult) ([][]byte, int) {
 	logs := tx.Meta.LogMessages
-	if len(logs) == 0 {
-		return nil
+	programDataList := pkgdecoder.ParseProgramDataWithMode(logs, i.programDataMode)
+	found := pkgdecoder.CountProgramData(logs, i.programDataMode)
+
+	// Programs using emit_cpi! carry events in self-invoked instructions
+	// instead of logs.
+	if tx.Transaction != nil {
+		if txObj, err := tx.Transaction.GetTransaction(); err == nil {
+			accounts := solanaClient.ResolveAccountKeys(txObj.Message, tx.Meta.LoadedAddresses)
+			cpiData := pkgdecoder.EventCPIData(i.starterProgramID, tx.Meta, accounts)
+			programDataList = append(programDataList, cpiData...)
+			found += len(cpiData)
+		}
 	}
+	return programDataList, found
+}
 
-	var accounts []solana.PublicKey
+// counterActions parses the actions of deployment d in tx.
+func counterActions(d *counterDeployment, tx *rpc.GetTransactionResult) ([]decoder.CounterAction, error) {
+	var (
+		accounts     []solana.PublicKey
+		instructions []decoder.CounterInstruction
+	)
 	if tx.Transaction != nil {
 		txObj, err := tx.Transaction.GetTransaction()
 		if err == nil {
-			accounts = txObj.Message.AccountKeys
+			accounts = solanaClient.ResolveAccountKeys(txObj.Message, tx.Meta.LoadedAddresses)
+			instructions = counterInstructions(d.program, txObj, tx.Meta, accounts)
 		}
 	}
 
-	actions, err := i.counterLogParser.ParseLogs(logs, accounts)
+	actions, err := d.logParser.ParseLogsWithInstructions(tx.Meta.LogMessages, instructions, accounts)
 	if err != nil {
-		return fmt.Errorf("parse counter logs: %w", err)
+		return nil, fmt.Errorf("parse counter logs: %w", err)
 	}
+	return actions, nil
+}
 
-	for _, action := range actions {
-		eventData := i.convertCounterActionToEvent(action)
-		if err := i.counterProcessor.ProcessEvent(ctx, signature.String(), slot, blockTime, action.Type, eventData); err != nil {
-			log.Printf("failed to process counter event: %v", err)
-			continue
+// counterInstructions returns the instructions of program, the counter
+// program or one indexed through a log grammar, in a transaction in
+// execution order, with inner (CPI) instructions following the top-level
+// instruction that invoked them.
+func counterInstructions(program solana.PublicKey, txObj *solana.Transaction, meta *rpc.TransactionMeta, accounts []solana.PublicKey) []decoder.CounterInstruction {
+	inner := make(map[uint16][]solana.CompiledInstruction, len(meta.InnerInstructions))
+	for _, set := range meta.InnerInstructions {
+		inner[set.Index] = set.Instructions
+	}
+
+	var instructions []decoder.CounterInstruction
+	add := func(ix solana.CompiledInstruction) {
+		if int(ix.ProgramIDIndex) >= len(accounts) || !accounts[ix.ProgramIDIndex].Equals(program) {
+			return
 		}
+		keys := make([]solana.PublicKey, len(ix.Accounts))
+		for n, idx := range ix.Accounts {
+			if int(idx) < len(accounts) {
+				keys[n] = accounts[idx]
+			}
+		}
+		instructions = append(instructions, decoder.CounterInstruction{Accounts: keys, Data: ix.Data})
+	}
 
-		log.Printf("processed counter event %s at slot %d", action.Type, slot)
+	for idx, ix := range txObj.Message.Instructions {
+		add(ix)
+		for _, innerIx := range inner[uint16(idx)] {
+			add(innerIx)
+		}
 	}
 
-	return nil
+	return instructions
 }
 
-func (i *Indexer) convertCounterActionToEvent(action decoder.CounterAction) interface{} {
+// convertCounterActionToEvent builds the event model of a parsed counter
+// action, failing when the action's values are inconsistent.
+func (i *Indexer) convertCounterActionToEvent(action decoder.CounterAction) (interface{}, error) {
+	var authority, payer, feeCollector solana.PublicKey
+	if action.Authority != nil {
+		authority = *action.Authority
+	}
+	if action.Payer != nil {
+		payer = *action.Payer
+	}
+	if action.FeeCollector != nil {
+		feeCollector = *action.FeeCollector
+	}
+	oldValue := valueOrDefault(action.OldValue, 0)
+	newValue := valueOrDefault(action.NewValue, 0)
+
 	switch action.Type {
 	case models.EventTypeCounterInitialized:
-		authority := solana.PublicKey{}
-		if action.Authority != nil {
-			authority = *action.Authority
+		event, err := models.NewCounterInitializedEvent(action.Counter, authority, newValue)
+		return deref(event, err)
+	case models.EventTypeCounterIncremented:
+		event, err := models.NewCounterIncrementedEvent(action.Counter, oldValue, newValue)
+		return deref(event, err)
+	case models.EventTypeCounterDecremented:
+		event, err := models.NewCounterDecrementedEvent(action.Counter, oldValue, newValue)
+		return deref(event, err)
+	case models.EventTypeCounterAdded:
+		event, err := models.NewCounterAddedEvent(action.Counter, oldValue, valueOrDefault(action.AddedValue, 0), newValue)
+		return deref(event, err)
+	case models.EventTypeCounterReset:
+		event, err := models.NewCounterResetEvent(action.Counter, authority, oldValue)
+		return deref(event, err)
+	case models.EventTypeCounterPaymentReceived:
+		event, err := models.NewCounterPaymentReceivedEvent(action.Counter, payer, feeCollector, valueOrDefault(action.Payment, 0), newValue)
+		return deref(event, err)
+	default:
+		return nil, fmt.Errorf("unsupported counter action %s", action.Type)
+	}
+}
+
+// deref returns the event a constructor built by value, as the processor
+// expects counter events.
+func deref[T any](event *T, err error) (interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	return *event, nil
+}
+
+func newSinks(cfg *config.Config, repo repository.Repository, failures *failure.Counter) ([]sink.Sink, error) {
+	var sinks []sink.Sink
+
+	if cfg.AWSSinkType != config.AWSSinkTypeNone {
+		s, err := newAWSSink(cfg, sinkFailureRecorder(repo, failures, string(cfg.AWSSinkType)))
+		if err != nil {
+			return nil, err
 		}
-		return models.CounterInitializedEvent{
-			Counter:      action.Counter,
-			Authority:    authority,
-			InitialCount: valueOrDefault(action.NewValue, 0),
+		sinks = append(sinks, s)
+	}
+
+	outboxSinks := cfg.OutboxSinks()
+	for _, name := range cfg.Sinks {
+		if name == "webhook" || slices.Contains(outboxSinks, name) {
+			continue
 		}
-	case models.EventTypeCounterIncremented:
-		return models.CounterIncrementedEvent{
-			Counter:  action.Counter,
-			OldValue: valueOrDefault(action.OldValue, 0),
-			NewValue: valueOrDefault(action.NewValue, 0),
+		w, err := newSinkWriter(cfg, name)
+		if err != nil {
+			return nil, fmt.Errorf("create %s sink: %w", name, err)
 		}
-	case models.EventTypeCounterDecremented:
-		return models.CounterDecrementedEvent{
-			Counter:  action.Counter,
-			OldValue: valueOrDefault(action.OldValue, 0),
-			NewValue: valueOrDefault(action.NewValue, 0),
+		if w == nil {
+			continue
 		}
-	case models.EventTypeCounterAdded:
-		return models.CounterAddedEvent{
-			Counter:    action.Counter,
-			OldValue:   valueOrDefault(action.OldValue, 0),
-			AddedValue: valueOrDefault(action.AddedValue, 0),
-			NewValue:   valueOrDefault(action.NewValue, 0),
+		sinks = append(sinks, sink.NewWriterSink(name, w, cfg.SinkBatchSize, cfg.SinkFlushInterval))
+	}
+
+	return sinks, nil
+}
+
+// newWebhookSinks returns the cache invalidation and webhook sinks, which
+// a configuration reload recreates.
+func newWebhookSinks(cfg *config.Config) ([]sink.Sink, error) {
+	var sinks []sink.Sink
+
+	templates := make(map[sink.InvalidationKey]string)
+	if cfg.CacheInvalidationMintURL != "" {
+		templates[sink.InvalidationKeyMint] = cfg.CacheInvalidationMintURL
+	}
+	if cfg.CacheInvalidationCollectionURL != "" {
+		templates[sink.InvalidationKeyCollection] = cfg.CacheInvalidationCollectionURL
+	}
+	if cfg.CacheInvalidationWalletURL != "" {
+		templates[sink.InvalidationKeyWallet] = cfg.CacheInvalidationWalletURL
+	}
+	if len(templates) > 0 {
+		s, err := sink.NewInvalidationSink(sink.InvalidationOptions{
+			Templates:   templates,
+			Method:      cfg.CacheInvalidationMethod,
+			BearerToken: cfg.CacheInvalidationToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("create cache invalidation sink: %w", err)
 		}
-	case models.EventTypeCounterReset:
-		authority := solana.PublicKey{}
-		if action.Authority != nil {
-			authority = *action.Authority
+		sinks = append(sinks, s)
+	}
+
+	if slices.Contains(cfg.Sinks, "webhook") && !slices.Contains(cfg.OutboxSinks(), "webhook") {
+		w, err := newSinkWriter(cfg, "webhook")
+		if err != nil {
+			return nil, fmt.Errorf("create webhook sink: %w", err)
 		}
-		return models.CounterResetEvent{
-			Counter:   action.Counter,
-			Authority: authority,
-			OldValue:  valueOrDefault(action.OldValue, 0),
+		sinks = append(sinks, sink.NewWriterSink("webhook", w, cfg.SinkBatchSize, cfg.SinkFlushInterval))
+	}
+
+	if cfg.NotifyRulesFile != "" {
+		rules, err := sink.ReadNotifyRulesFile(cfg.NotifyRulesFile)
+		if err != nil {
+			return nil, fmt.Errorf("NOTIFY_RULES_FILE: %w", err)
 		}
-	case models.EventTypeCounterPaymentReceived:
-		payer := solana.PublicKey{}
-		feeCollector := solana.PublicKey{}
-		if action.Payer != nil {
-			payer = *action.Payer
+		s, err := sink.NewNotifySink(sink.NotifyOptions{Rules: rules, TelegramToken: cfg.TelegramBotToken})
+		if err != nil {
+			return nil, fmt.Errorf("NOTIFY_RULES_FILE: %w", err)
 		}
-		if action.FeeCollector != nil {
-			feeCollector = *action.FeeCollector
+		sinks = append(sinks, s)
+	}
+	return sinks, nil
+}
+
+// newOutboxDispatchers returns a dispatcher for every sink delivered
+// through the outbox.
+func newOutboxDispatchers(cfg *config.Config, repo repository.Repository) ([]*outbox.Dispatcher, error) {
+	names := cfg.OutboxSinks()
+	if len(names) == 0 {
+		return nil, nil
+	}
+	store, ok := repository.Unwrap(repo).(repository.OutboxStore)
+	if !ok {
+		return nil, fmt.Errorf("%T does not support an outbox", repository.Unwrap(repo))
+	}
+
+	opts := outbox.Options{Interval: cfg.OutboxInterval, BatchSize: cfg.OutboxBatchSize}
+	dispatchers := make([]*outbox.Dispatcher, 0, len(names))
+	for _, name := range names {
+		w, err := newSinkWriter(cfg, name)
+		if err != nil {
+			return nil, fmt.Errorf("create %s sink: %w", name, err)
+		}
+		dispatchers = append(dispatchers, outbox.NewDispatcher(store, name, w, opts))
+	}
+	return dispatchers, nil
+}
+
+// newSinkWriter returns nil for the primary database, which the event
+// processors already write to.
+func newSinkWriter(cfg *config.Config, name string) (sink.Writer, error) {
+	switch name {
+	case string(config.DatabaseTypeMongo), string(config.DatabaseTypePostgres):
+		dbType := config.DatabaseType(name)
+		if dbType == cfg.DatabaseType {
+			return nil, nil
 		}
-		return models.CounterPaymentReceivedEvent{
-			Counter:      action.Counter,
-			Payer:        payer,
-			FeeCollector: feeCollector,
-			Payment:      valueOrDefault(action.Payment, 0),
-			NewCount:     valueOrDefault(action.NewValue, 0),
+		url := cfg.SinkMongoURL
+		if dbType == config.DatabaseTypePostgres {
+			url = cfg.SinkPostgresURL
 		}
+		fieldCipher, err := cfg.FieldCipher()
+		if err != nil {
+			return nil, err
+		}
+		repo, err := openRepository(cfg, dbType, url)
+		if err != nil {
+			return nil, err
+		}
+		w := sink.NewRepositoryWriter(repo)
+		w.SetCipher(fieldCipher)
+		return w, nil
+	case "kafka":
+		return sink.NewKafkaWriter(sink.KafkaOptions{RESTURL: cfg.KafkaRESTURL, Topic: cfg.KafkaTopic})
+	case "webhook":
+		return sink.NewWebhookWriter(sink.WebhookOptions{
+			URL:         cfg.SinkWebhookURL,
+			BearerToken: cfg.SinkWebhookToken,
+			Secret:      cfg.SinkWebhookSecret,
+		})
+	case "stdout":
+		return sink.NewStdoutWriter(os.Stdout), nil
 	default:
+		return nil, fmt.Errorf("unknown sink")
+	}
+}
+
+func newAWSSink(cfg *config.Config, onFailure func(models.BaseEvent, error)) (sink.Sink, error) {
+	routes := make(map[models.EventType]string, len(cfg.AWSSinkRoutes))
+	for eventType, target := range cfg.AWSSinkRoutes {
+		routes[models.EventType(eventType)] = target
+	}
+
+	opts := sink.AWSOptions{
+		Region:   cfg.AWSRegion,
+		Endpoint: cfg.AWSEndpoint,
+		Credentials: sink.AWSCredentials{
+			AccessKeyID:     cfg.AWSAccessKeyID,
+			SecretAccessKey: cfg.AWSSecretAccessKey,
+			SessionToken:    cfg.AWSSessionToken,
+		},
+		DefaultTarget: cfg.AWSSinkTarget,
+		Routes:        routes,
+		BatchSize:     cfg.AWSSinkBatchSize,
+		FlushInterval: cfg.AWSSinkFlushInterval,
+		OnFailure:     onFailure,
+	}
+
+	switch cfg.AWSSinkType {
+	case config.AWSSinkTypeKinesis:
+		s, err := sink.NewKinesisSink(opts)
+		if err != nil {
+			return nil, fmt.Errorf("create kinesis sink: %w", err)
+		}
+		return s, nil
+	case config.AWSSinkTypeSQS:
+		s, err := sink.NewSQSSink(opts)
+		if err != nil {
+			return nil, fmt.Errorf("create sqs sink: %w", err)
+		}
+		return s, nil
+	default:
+		return nil, fmt.Errorf("unsupported aws sink type: %s", cfg.AWSSinkType)
+	}
+}
+
+// retentionPolicy builds the configured retention policy, or nil when
+// events are kept forever.
+func retentionPolicy(cfg *config.Config) *repository.RetentionPolicy {
+	if cfg.RetentionDays <= 0 && len(cfg.RetentionOverrides) == 0 {
 		return nil
 	}
+
+	day := 24 * time.Hour
+	mode, _ := config.ParseRetentionMode(cfg.RetentionMode) // validated by config.Validate
+	policy := &repository.RetentionPolicy{
+		MaxAge:  time.Duration(cfg.RetentionDays) * day,
+		PerType: make(map[models.EventType]time.Duration, len(cfg.RetentionOverrides)),
+		Archive: mode == config.RetentionArchive,
+	}
+	for eventType, raw := range cfg.RetentionOverrides {
+		days, _ := strconv.Atoi(raw) // validated by config.Validate
+		policy.PerType[models.EventType(eventType)] = time.Duration(days) * day
+	}
+	return policy
+}
+
+// newColdExporter builds the configured cold storage exporter, or nil when
+// events stay in the hot store.
+func newColdExporter(cfg *config.Config, repo repository.Repository) (*coldstore.Exporter, error) {
+	if cfg.ColdExportProvider == "" {
+		return nil, nil
+	}
+
+	source, ok := repository.Unwrap(repo).(repository.ColdSource)
+	if !ok {
+		return nil, fmt.Errorf("%T does not support cold storage export", repository.Unwrap(repo))
+	}
+
+	var store coldstore.ObjectStore
+	var err error
+	switch cfg.ColdExportProvider {
+	case "s3":
+		store, err = coldstore.NewS3Store(coldstore.S3Options{
+			Bucket:   cfg.ColdExportBucket,
+			Region:   cfg.AWSRegion,
+			Endpoint: cfg.ColdExportEndpoint,
+			Credentials: aws.Credentials{
+				AccessKeyID:     cfg.AWSAccessKeyID,
+				SecretAccessKey: cfg.AWSSecretAccessKey,
+				SessionToken:    cfg.AWSSessionToken,
+			},
+		})
+	case "gcs":
+		store, err = coldstore.NewGCSStore(cfg.ColdExportBucket, aws.Credentials{
+			AccessKeyID:     cfg.GCSHMACAccessID,
+			SecretAccessKey: cfg.GCSHMACSecret,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported cold export provider: %s", cfg.ColdExportProvider)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return coldstore.NewExporter(source, store, coldstore.Options{
+		After:     time.Duration(cfg.ColdExportAfterDays) * 24 * time.Hour,
+		Interval:  cfg.ColdExportInterval,
+		BatchSize: cfg.ColdExportBatchSize,
+		Prefix:    cfg.ColdExportPrefix,
+	}), nil
+}
+
+// newReportScheduler builds the scheduler for reports saved through the API,
+// or nil when reports are disabled or the database cannot store them.
+func newReportScheduler(cfg *config.Config, repo repository.Repository) (*report.Scheduler, error) {
+	if cfg.ReportCheckInterval <= 0 {
+		return nil, nil
+	}
+	store, ok := repository.Unwrap(repo).(repository.ReportStore)
+	if !ok {
+		return nil, nil
+	}
+	events, ok := repository.Unwrap(repo).(repository.EventStreamer)
+	if !ok {
+		return nil, nil
+	}
+
+	opts := report.Options{
+		CheckInterval: cfg.ReportCheckInterval,
+		MaxRows:       cfg.ReportMaxRows,
+	}
+	if queries, ok := repository.Unwrap(repo).(repository.SavedQueryStore); ok {
+		opts.Queries = queries
+	}
+	if cfg.SMTPAddr != "" {
+		mailer, err := report.NewSMTPMailer(report.SMTPOptions{
+			Addr:     cfg.SMTPAddr,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+		})
+		if err != nil {
+			return nil, err
+		}
+		opts.Mailer = mailer
+	}
+	if cfg.GoogleServiceAccountFile != "" {
+		sheets, err := report.NewSheetsClient(cfg.GoogleServiceAccountFile)
+		if err != nil {
+			return nil, err
+		}
+		opts.Sheets = sheets
+	}
+
+	return report.NewScheduler(store, events, opts), nil
+}
+
+// newNftEnricher builds the NFT metadata worker, or nil when it is disabled
+// or the database cannot store metadata.
+func newNftEnricher(cfg *config.Config, repo repository.Repository) *nftmeta.Enricher {
+	if !cfg.NftMetadataEnabled {
+		return nil
+	}
+	store, ok := repository.Unwrap(repo).(repository.NftMetadataStore)
+	if !ok {
+		log.Printf("warning: NFT metadata is enabled but %T cannot store it", repository.Unwrap(repo))
+		return nil
+	}
+
+	fetcher := nftmeta.NewFetcher(nftmeta.FetcherOptions{
+		Timeout:     cfg.NftMetadataTimeout,
+		IPFSGateway: cfg.NftMetadataIPFSGateway,
+	})
+	return nftmeta.NewEnricher(store, fetcher, nftmeta.Options{
+		Interval:    cfg.NftMetadataInterval,
+		BatchSize:   cfg.NftMetadataBatchSize,
+		MaxAttempts: cfg.NftMetadataMaxAttempts,
+	})
+}
+
+func newAccountMonitor(cfg *config.Config, client *solanaClient.Client, repo repository.Repository, programs ...solana.PublicKey) *accountmon.Monitor {
+	if cfg.AccountMonitorInterval == 0 {
+		return nil
+	}
+	store, ok := repository.Unwrap(repo).(repository.AccountSampleStore)
+	if !ok {
+		log.Printf("warning: account monitoring is enabled but %T cannot store samples; only alerts are kept", repository.Unwrap(repo))
+	}
+	return accountmon.NewMonitor(client, store, programs, accountmon.Options{
+		Interval:      cfg.AccountMonitorInterval,
+		SizeWarnRatio: cfg.AccountMonitorSizeWarnRatio,
+	})
+}
+
+func newWatchdog(cfg *config.Config, source watchdog.Source) *watchdog.Watchdog {
+	if cfg.WatchdogStallAfter == 0 && cfg.WatchdogMaxErrorsPerMinute == 0 {
+		return nil
+	}
+	return watchdog.New(source, watchdog.Options{
+		StallAfter:         cfg.WatchdogStallAfter,
+		MaxErrorsPerMinute: cfg.WatchdogMaxErrorsPerMinute,
+		ErrorWindow:        cfg.WatchdogErrorWindow,
+		WebhookURL:         cfg.WatchdogWebhookURL,
+		WebhookSecret:      cfg.WatchdogWebhookSecret,
+	})
+}
+
+// partitionCheckInterval is how often the events table partitions of the
+// current slot are checked.
+const partitionCheckInterval = time.Minute
+
+// maintainPartitions keeps the events table partitions of the current slot
+// and the next range created.
+func (i *Indexer) maintainPartitions(ctx context.Context, repo *repository.PostgresRepository) {
+	ticker := time.NewTicker(partitionCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if slot := i.GetCurrentSlot(); slot > 0 {
+			if err := repo.EnsurePartitions(ctx, slot); err != nil && ctx.Err() == nil {
+				log.Printf("warning: failed to create events partitions: %v", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (i *Indexer) runRetention(ctx context.Context, pruner repository.Pruner) {
+	ticker := time.NewTicker(i.cfg.RetentionInterval)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := pruner.PruneEvents(ctx, *i.retention, time.Now())
+		if err != nil {
+			log.Printf("error pruning expired events: %v", err)
+		} else if deleted > 0 {
+			log.Printf("pruned %d expired events", deleted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
 }
 
 func valueOrDefault(ptr *uint64, defaultValue uint64) uint64 {
@@ -348,8 +1354,49 @@ func (i *Indexer) Shutdown(ctx context.Context) error {
 		log.Println("shutting down indexer...")
 		i.isRunning = false
 
-		if err := i.repo.Close(ctx); err != nil {
-			shutdownErr = fmt.Errorf("close repository: %w", err)
+		for _, s := range i.sinks {
+			if err := s.Close(ctx); err != nil {
+				log.Printf("error closing sink: %v", err)
+			}
+		}
+		for _, d := range i.dispatchers {
+			if err := d.Close(ctx); err != nil {
+				log.Printf("error closing outbox dispatcher: %v", err)
+			}
+		}
+
+		if i.seen != nil {
+			if err := i.seen.Save(); err != nil {
+				log.Printf("error saving seen signatures: %v", err)
+			}
+		}
+
+		if i.buffer != nil {
+			if err := i.buffer.Close(); err != nil {
+				log.Printf("error closing offline buffer: %v", err)
+			}
+		}
+
+		if err := i.coverage.Flush(ctx); err != nil {
+			log.Printf("error storing decoder coverage: %v", err)
+		}
+
+		if i.rollups != nil {
+			if err := i.rollups.Flush(ctx); err != nil {
+				log.Printf("error storing event rollups: %v", err)
+			}
+		}
+
+		if !i.sharedRepo {
+			if err := i.repo.Close(ctx); err != nil {
+				shutdownErr = fmt.Errorf("close repository: %w", err)
+			}
+		}
+
+		if i.redis != nil {
+			if err := i.redis.Close(); err != nil {
+				log.Printf("error closing redis client: %v", err)
+			}
 		}
 	})
 	return shutdownErr
@@ -361,6 +1408,82 @@ func (i *Indexer) GetCurrentSlot() uint64 {
 	return i.currentSlot
 }
 
+// Network returns the network indexed, empty unless NETWORKS is set.
+func (i *Indexer) Network() string {
+	return i.cfg.Network
+}
+
+func (i *Indexer) Repository() repository.Repository {
+	return i.repo
+}
+
+// ConsumerLag returns the last known lag of downstream sink consumers, or
+// nil when no sink reports lag.
+func (i *Indexer) ConsumerLag() []sink.ConsumerLag {
+	if i.lagTracker == nil {
+		return nil
+	}
+	return i.lagTracker.Snapshot()
+}
+
+// SeenStats returns the seen signature cache stats, or nil when the cache is
+// disabled.
+func (i *Indexer) SeenStats() *seen.Stats {
+	if i.seen == nil {
+		return nil
+	}
+	stats := i.seen.Stats()
+	return &stats
+}
+
+// BufferStats returns the state of the offline buffer, or nil when it is
+// disabled.
+func (i *Indexer) BufferStats() *spool.Stats {
+	if i.buffer == nil {
+		return nil
+	}
+	stats := i.buffer.Stats()
+	return &stats
+}
+
+// DecoderCoverage returns the decoder coverage of the starter program
+// transactions processed since start.
+func (i *Indexer) DecoderCoverage() models.DecoderCoverage {
+	return i.coverage.Totals()
+}
+
+// AccountAlerts returns the alerts of the last program account sampling, or
+// nil when account monitoring is disabled.
+func (i *Indexer) AccountAlerts() []accountmon.Alert {
+	if i.accountMonitor == nil {
+		return nil
+	}
+	return i.accountMonitor.Alerts()
+}
+
+// WatchdogAlerts returns the raised watchdog alerts, or nil when the
+// watchdog is disabled.
+func (i *Indexer) WatchdogAlerts() []watchdog.Alert {
+	if i.watchdog == nil {
+		return nil
+	}
+	return i.watchdog.Alerts()
+}
+
+// RPCStatus returns the per method RPC stats and circuit breaker state.
+func (i *Indexer) RPCStatus() solanaClient.RPCStatus {
+	return i.client.Status()
+}
+
+// StreamWindows returns the current windows of the streaming metrics, or
+// nil when none are configured.
+func (i *Indexer) StreamWindows() []aggregate.Series {
+	if i.windows == nil {
+		return nil
+	}
+	return i.windows.Snapshot()
+}
+
 func (i *Indexer) IsRunning() bool {
 	i.mu.RLock()
 	defer i.mu.RUnlock()
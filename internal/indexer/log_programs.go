@@ -0,0 +1,101 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/decoder"
+	"github.com/lugondev/go-indexer-solana-starter/internal/failure"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/processor"
+	solanaClient "github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// logProgram is a program indexed through a log grammar of
+// LOG_GRAMMAR_FILE rather than a decoder written in Go.
+type logProgram struct {
+	grammar   *decoder.LogGrammar
+	processor *processor.EventProcessor
+}
+
+// newLogPrograms reads LOG_GRAMMAR_FILE. Its processors share the settings
+// of starter.
+func newLogPrograms(cfg *config.Config, starter *processor.EventProcessor) ([]*logProgram, error) {
+	if cfg.LogGrammarFile == "" {
+		return nil, nil
+	}
+	grammars, err := decoder.ReadLogGrammarFile(cfg.LogGrammarFile)
+	if err != nil {
+		return nil, fmt.Errorf("LOG_GRAMMAR_FILE: %w", err)
+	}
+
+	indexed := map[string]bool{cfg.StarterProgramID: true}
+	for _, d := range cfg.CounterDeployments() {
+		indexed[d.ProgramID] = true
+	}
+	programs := make([]*logProgram, 0, len(grammars))
+	for _, g := range grammars {
+		if indexed[g.Program.String()] {
+			return nil, fmt.Errorf("LOG_GRAMMAR_FILE: %s program %s is already indexed", g.Name, g.Program)
+		}
+		programs = append(programs, &logProgram{grammar: g, processor: starter.WithProgram(g.Program)})
+	}
+	return programs, nil
+}
+
+func (i *Indexer) logDecoder(lp *logProgram) transactionDecoder {
+	return func(ctx context.Context, tx *fetchedTransaction) (*decodedTransaction, error) {
+		return i.decodeLogTransaction(ctx, lp, tx)
+	}
+}
+
+func (i *Indexer) decodeLogTransaction(ctx context.Context, lp *logProgram, fetched *fetchedTransaction) (*decodedTransaction, error) {
+	signature, tx := fetched.signature, fetched.tx
+	if len(tx.Meta.LogMessages) == 0 {
+		return nil, nil
+	}
+
+	matches, errs := logMatches(lp.grammar, tx)
+	for _, err := range errs {
+		i.recordFailure(ctx, lp.grammar.Program, signature, tx.Slot, &failure.DecodeError{Err: err})
+		log.Printf("failed to decode %s log: %v", lp.grammar.Name, err)
+	}
+
+	decoded := &decodedTransaction{
+		signature: signature,
+		slot:      tx.Slot,
+		blockTime: fetched.blockTime,
+		program:   lp.grammar.Program,
+		processor: lp.processor,
+		kind:      lp.grammar.Name,
+	}
+	for _, m := range matches {
+		decoded.events = append(decoded.events, decodedEvent{eventType: m.Event, data: models.LogEvent{Fields: m.Fields}})
+	}
+	return decoded, nil
+}
+
+// logMatches runs grammar over the logs of tx, resolving account fields
+// from the program's instructions.
+func logMatches(grammar *decoder.LogGrammar, tx *rpc.GetTransactionResult) ([]decoder.LogMatch, []error) {
+	var instructions []decoder.CounterInstruction
+	if tx.Transaction != nil {
+		if txObj, err := tx.Transaction.GetTransaction(); err == nil {
+			accounts := solanaClient.ResolveAccountKeys(txObj.Message, tx.Meta.LoadedAddresses)
+			instructions = counterInstructions(grammar.Program, txObj, tx.Meta, accounts)
+		}
+	}
+	return grammar.ParseLogs(tx.Meta.LogMessages, instructions)
+}
+
+func logProgramIDs(programs []*logProgram) []solana.PublicKey {
+	keys := make([]solana.PublicKey, len(programs))
+	for n, lp := range programs {
+		keys[n] = lp.grammar.Program
+	}
+	return keys
+}
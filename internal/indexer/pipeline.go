@@ -0,0 +1,263 @@
+package indexer
+
+import (
+	"context"
+	"log"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/lugondev/go-indexer-solana-starter/internal/failure"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/processor"
+	"golang.org/x/sync/errgroup"
+)
+
+// A transaction is indexed in three stages: fetch from the RPC endpoint,
+// decode into events and store. While a program catches up, the stages run
+// concurrently, connected by queues of PIPELINE_QUEUE_SIZE transactions,
+// so a slow database holds fetching back rather than filling memory.
+
+// fetchedTransaction is a transaction with its block time.
+type fetchedTransaction struct {
+	signature solana.Signature
+	tx        *rpc.GetTransactionResult
+	blockTime time.Time
+}
+
+// decodedTransaction holds the events of a transaction, ready to be stored.
+type decodedTransaction struct {
+	signature solana.Signature
+	slot      uint64
+	blockTime time.Time
+	program   solana.PublicKey
+	processor *processor.EventProcessor
+	// kind names the program in logs: starter or counter.
+	kind string
+	// mirror applies the events to the config mirror too.
+	mirror   bool
+	events   []decodedEvent
+	coverage *models.DecoderCoverage
+}
+
+type decodedEvent struct {
+	eventType models.EventType
+	data      interface{}
+	// raw is the payload the event was decoded from, when STORE_RAW_DATA
+	// keeps it.
+	raw []byte
+}
+
+// transactionDecoder decodes the events of one program in a transaction.
+// It returns nil when there are none.
+type transactionDecoder func(ctx context.Context, tx *fetchedTransaction) (*decodedTransaction, error)
+
+// pipelineItem is a transaction moving through the stages of poll.
+type pipelineItem struct {
+	sig *rpc.TransactionSignature
+	// seen is set for transactions processed before; only the cursor
+	// moves past them.
+	seen    bool
+	fetched *fetchedTransaction
+	decoded *decodedTransaction
+	err     error
+}
+
+// pipelineQueues are the queues of a program's pipeline while it polls.
+type pipelineQueues struct {
+	mu     sync.Mutex
+	decode chan *pipelineItem
+	store  chan *pipelineItem
+}
+
+func (q *pipelineQueues) set(decode, store chan *pipelineItem) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.decode, q.store = decode, store
+}
+
+func (q *pipelineQueues) depths() (decode, store int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.decode), len(q.store)
+}
+
+// fetchTransaction returns nil for a transaction without metadata, which
+// has nothing to decode.
+func (i *Indexer) fetchTransaction(ctx context.Context, signature solana.Signature) (*fetchedTransaction, error) {
+	tx, err := i.client.GetTransaction(ctx, signature)
+	if err != nil {
+		return nil, &failure.RPCError{Method: "getTransaction", Err: err}
+	}
+	if tx == nil {
+		return nil, &failure.ReorgError{Signature: signature.String()}
+	}
+	if tx.Meta == nil {
+		return nil, nil
+	}
+	return &fetchedTransaction{signature: signature, tx: tx, blockTime: i.blockTime(ctx, tx)}, nil
+}
+
+// storeTransaction processes the decoded events. Events that fail are
+// recorded and logged; the others are stored regardless.
+func (i *Indexer) storeTransaction(ctx context.Context, d *decodedTransaction) {
+	for _, e := range d.events {
+		if d.mirror && i.configMirror != nil {
+			if err := i.configMirror.ApplyEvent(ctx, d.signature.String(), d.slot, d.blockTime, e.data); err != nil {
+				log.Printf("failed to mirror config event: %v", err)
+			}
+		}
+		if err := d.processor.ProcessRawEvent(ctx, d.signature.String(), d.slot, d.blockTime, e.eventType, e.data, e.raw); err != nil {
+			kind := i.recordFailure(ctx, d.program, d.signature, d.slot, err)
+			log.Printf("failed to process %s event (%s): %v", d.kind, kind, err)
+			continue
+		}
+		log.Printf("processed %s event %s at slot %d", d.kind, e.eventType, d.slot)
+	}
+	if d.coverage != nil {
+		i.coverage.Record(*d.coverage)
+	}
+}
+
+// process returns a function running the stages one after the other for a
+// single transaction, as backfill does.
+func (i *Indexer) process(decode transactionDecoder) func(context.Context, solana.Signature) error {
+	return func(ctx context.Context, signature solana.Signature) error {
+		fetched, err := i.fetchTransaction(ctx, signature)
+		if err != nil || fetched == nil {
+			return err
+		}
+		decoded, err := decode(ctx, fetched)
+		if err != nil || decoded == nil {
+			return err
+		}
+		i.storeTransaction(ctx, decoded)
+		return nil
+	}
+}
+
+// runPipeline fetches, decodes and stores sigs, listed newest first, from
+// the oldest on with the stages running concurrently. It returns how many transactions the cursor
+// moved past. The checkpoint is saved every BatchSize transactions, so an
+// interrupted catch-up resumes where it stopped.
+func (i *Indexer) runPipeline(ctx context.Context, c *programCursor, sigs []*rpc.TransactionSignature) (int, error) {
+	stagesCtx, cancel := context.WithCancel(ctx)
+	g, gctx := errgroup.WithContext(stagesCtx)
+	defer func() {
+		cancel()
+		g.Wait()
+	}()
+
+	decodeQueue := make(chan *pipelineItem, i.cfg.PipelineQueueSize)
+	storeQueue := make(chan *pipelineItem, i.cfg.PipelineQueueSize)
+	c.queues.set(decodeQueue, storeQueue)
+	defer c.queues.set(nil, nil)
+
+	g.Go(func() error {
+		defer close(decodeQueue)
+		for n := len(sigs) - 1; n >= 0; n-- {
+			item := &pipelineItem{sig: sigs[n]}
+			if i.seen != nil {
+				done, err := i.seen.Seen(gctx, item.sig.Signature)
+				if err != nil {
+					log.Printf("warning: %v", err)
+				}
+				item.seen = done
+			}
+			if !item.seen {
+				item.fetched, item.err = i.fetchTransaction(gctx, item.sig.Signature)
+			}
+			select {
+			case decodeQueue <- item:
+			case <-gctx.Done():
+				return gctx.Err()
+			}
+		}
+		return nil
+	})
+	g.Go(func() error {
+		defer close(storeQueue)
+		for item := range decodeQueue {
+			if item.fetched != nil {
+				item.decoded, item.err = c.decode(gctx, item.fetched)
+			}
+			select {
+			case storeQueue <- item:
+			case <-gctx.Done():
+				return gctx.Err()
+			}
+		}
+		return nil
+	})
+
+	processed := 0
+	for item := range storeQueue {
+		if i.buffer != nil && i.buffer.Full() {
+			log.Printf("warning: offline buffer is full, pausing %s program after slot %d until it is replayed", c.name, c.slot)
+			c.save(ctx)
+			return processed, nil
+		}
+		if item.decoded != nil {
+			i.storeTransaction(ctx, item.decoded)
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			// The transaction may not have been stored: stop before it.
+			c.save(context.WithoutCancel(ctx))
+			return processed, ctxErr
+		}
+		if item.err != nil {
+			kind := i.recordFailure(ctx, c.program, item.sig.Signature, item.sig.Slot, item.err)
+			log.Printf("error processing %s transaction %s (%s): %v", c.name, item.sig.Signature, kind, item.err)
+		} else if !item.seen && i.seen != nil {
+			i.seen.Add(item.sig.Signature)
+		}
+		c.head, c.slot = &item.sig.Signature, item.sig.Slot
+		processed++
+		if processed%i.cfg.BatchSize == 0 {
+			c.save(ctx)
+		}
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		c.save(context.WithoutCancel(ctx))
+		return processed, ctxErr
+	}
+	c.save(ctx)
+	return processed, nil
+}
+
+// programQueues returns the pipeline queues of a program, creating them on
+// first use.
+func (i *Indexer) programQueues(program solana.PublicKey) *pipelineQueues {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	q, ok := i.pipelines[program]
+	if !ok {
+		q = &pipelineQueues{}
+		i.pipelines[program] = q
+	}
+	return q
+}
+
+// PipelineQueues returns the depth of the queues in front of the decode
+// and store stages of every indexed program.
+func (i *Indexer) PipelineQueues() []models.PipelineQueue {
+	i.mu.RLock()
+	programs := make(map[string]*pipelineQueues, len(i.pipelines))
+	for program, q := range i.pipelines {
+		programs[program.String()] = q
+	}
+	i.mu.RUnlock()
+
+	var queues []models.PipelineQueue
+	for _, program := range slices.Sorted(maps.Keys(programs)) {
+		decode, store := programs[program].depths()
+		queues = append(queues,
+			models.PipelineQueue{Program: program, Stage: "decode", Depth: decode, Capacity: i.cfg.PipelineQueueSize},
+			models.PipelineQueue{Program: program, Stage: "store", Depth: store, Capacity: i.cfg.PipelineQueueSize},
+		)
+	}
+	return queues
+}
@@ -0,0 +1,108 @@
+package indexer
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// runProgram polls one program on its own timer until ctx is done, slowing
+// down while the program is idle. Errors are logged and the poll retried.
+func (i *Indexer) runProgram(ctx context.Context, c *programCursor) error {
+	backoff := newPollBackoff(i.cfg.PollInterval, i.cfg.IdlePollInterval, i.cfg.IdleAfter, time.Now())
+	timer := time.NewTimer(i.cfg.PollInterval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+		if i.Paused() {
+			timer.Reset(i.cfg.PollInterval)
+			continue
+		}
+
+		n, err := i.poll(ctx, c)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			log.Printf("error processing %s signatures: %v", c.name, err)
+		}
+
+		wasIdle := backoff.idle()
+		delay := backoff.next(n > 0, time.Now())
+		switch {
+		case backoff.idle() && !wasIdle:
+			log.Printf("no new %s signatures for %v, slowing polling", c.name, i.cfg.IdleAfter)
+		case !backoff.idle() && wasIdle:
+			log.Printf("%s activity resumed, polling every %v", c.name, delay)
+		}
+		timer.Reset(delay)
+	}
+}
+
+// Pause stops polling the programs until Resume. Transactions already
+// fetched are still processed, and backfills go on.
+func (i *Indexer) Pause() {
+	if !i.paused.Swap(true) {
+		log.Printf("indexing paused")
+	}
+}
+
+func (i *Indexer) Resume() {
+	if i.paused.Swap(false) {
+		log.Printf("indexing resumed")
+	}
+}
+
+func (i *Indexer) Paused() bool {
+	return i.paused.Load()
+}
+
+// runCounters runs a program goroutine for every counter deployment. When
+// a reload changes the deployments, the goroutines are stopped, the
+// cursors synced and the goroutines started again.
+func (i *Indexer) runCounters(ctx context.Context, counters []*programCursor, gen uint64) error {
+	ticker := time.NewTicker(i.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		runCtx, stop := context.WithCancel(ctx)
+		g, gctx := errgroup.WithContext(runCtx)
+		for _, c := range counters {
+			g.Go(func() error { return i.runProgram(gctx, c) })
+		}
+
+		deployments, changed := i.awaitCounterChange(ctx, ticker, gen)
+		stop()
+		g.Wait()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		var synced bool
+		if counters, synced = i.syncCounterCursors(ctx, counters, deployments); synced {
+			gen = changed
+		}
+	}
+}
+
+// awaitCounterChange waits until the counter deployments differ from
+// generation gen, or ctx is done.
+func (i *Indexer) awaitCounterChange(ctx context.Context, ticker *time.Ticker, gen uint64) ([]*counterDeployment, uint64) {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, gen
+		case <-ticker.C:
+			if deployments, current := i.counterDeployments(); current != gen {
+				return deployments, current
+			}
+		}
+	}
+}
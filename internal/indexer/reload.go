@@ -0,0 +1,157 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/processor"
+	"github.com/lugondev/go-indexer-solana-starter/internal/sink"
+)
+
+// Components a reload recreates, and the settings each is built from.
+var (
+	filterSettings  = []string{"EventAllowlist", "EventDenylist", "EventAccountFilter"}
+	webhookSettings = []string{
+		"CacheInvalidationMintURL", "CacheInvalidationCollectionURL", "CacheInvalidationWalletURL",
+		"CacheInvalidationMethod", "CacheInvalidationToken",
+		"SinkWebhookURL", "SinkWebhookToken", "SinkWebhookSecret",
+		"NotifyRulesFile", "TelegramBotToken",
+	}
+	counterSettings = []string{"CounterProgramID", "CounterDeploymentPrograms", "ProgramTenants"}
+)
+
+// ReloadConfig reads the configuration again and applies it.
+func (i *Indexer) ReloadConfig(ctx context.Context) (*config.ReloadResult, error) {
+	cfg, err := config.Reload(i.cfg.Network)
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+	return i.Reload(ctx, cfg)
+}
+
+// Reload applies the event filters, webhooks and counter deployments of
+// cfg without a restart, recreating only the components whose settings
+// changed. Other changed settings are reported, not applied. Nothing is
+// applied if a component cannot be created.
+func (i *Indexer) Reload(ctx context.Context, cfg *config.Config) (*config.ReloadResult, error) {
+	i.reloadMu.Lock()
+	defer i.reloadMu.Unlock()
+
+	result := &config.ReloadResult{Applied: []string{}, RestartRequired: []string{}}
+	changed := i.applied.Changed(cfg)
+	if len(changed) == 0 {
+		return result, nil
+	}
+	reload := func(settings []string) bool {
+		return slices.ContainsFunc(changed, func(name string) bool { return slices.Contains(settings, name) })
+	}
+
+	var filter *processor.Filter
+	var err error
+	if reload(filterSettings) {
+		if filter, err = newEventFilter(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	var counters []*counterDeployment
+	reloadCounters := reload(counterSettings)
+	if reloadCounters {
+		if cfg.ProgramTenants[cfg.StarterProgramID] != i.applied.ProgramTenants[cfg.StarterProgramID] {
+			return nil, fmt.Errorf("PROGRAM_TENANTS: the tenant of the starter program changes only on a restart")
+		}
+		if counters, err = i.reloadCounters(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	// Webhook sinks are created last, so an error above leaves none open.
+	var webhooks []sink.Sink
+	if reload(webhookSettings) {
+		if webhooks, err = newWebhookSinks(cfg); err != nil {
+			return nil, fmt.Errorf("create webhook sinks: %w", err)
+		}
+	}
+
+	// Every component is built; apply them.
+	var applied []string
+	if reload(filterSettings) {
+		i.starterProcessor.SetFilter(filter)
+		current, _ := i.counterDeployments()
+		for _, d := range current {
+			d.processor.SetFilter(filter)
+		}
+		for _, d := range counters {
+			d.processor.SetFilter(filter)
+		}
+		applied = append(applied, filterSettings...)
+		result.Applied = append(result.Applied, "event filters")
+	}
+	if reload(webhookSettings) {
+		if err := sink.CloseAll(ctx, i.webhooks.Replace(webhooks...)); err != nil {
+			log.Printf("error closing replaced webhook sinks: %v", err)
+		}
+		settings := webhookSettings
+		if slices.Contains(i.applied.OutboxSinks(), "webhook") {
+			// The outbox dispatcher keeps the webhook sink's settings.
+			settings = slices.DeleteFunc(slices.Clone(settings), func(name string) bool {
+				return strings.HasPrefix(name, "SinkWebhook")
+			})
+		}
+		applied = append(applied, settings...)
+		result.Applied = append(result.Applied, "webhooks")
+	}
+	if reloadCounters {
+		i.mu.Lock()
+		i.counters = counters
+		i.counterGen++
+		i.mu.Unlock()
+		applied = append(applied, counterSettings...)
+		result.Applied = append(result.Applied, "counter deployments")
+	}
+
+	i.applied = i.applied.Merge(cfg, applied)
+	for _, name := range changed {
+		if !slices.Contains(applied, name) {
+			result.RestartRequired = append(result.RestartRequired, name)
+		}
+	}
+	log.Printf("configuration reloaded: applied %v, restart required for %v", result.Applied, result.RestartRequired)
+	return result, nil
+}
+
+// reloadCounters returns the deployments of cfg, keeping those whose
+// program, label and tenant are unchanged. Added deployments get a
+// processor with the current settings.
+func (i *Indexer) reloadCounters(cfg *config.Config) ([]*counterDeployment, error) {
+	counters, err := newCounterDeployments(cfg)
+	if err != nil {
+		return nil, err
+	}
+	current, _ := i.counterDeployments()
+	for n, d := range counters {
+		if k := slices.IndexFunc(current, func(c *counterDeployment) bool {
+			return c.program == d.program && c.label == d.label && c.tenant == d.tenant
+		}); k >= 0 {
+			counters[n] = current[k]
+			continue
+		}
+		d.attach(i.starterProcessor)
+	}
+	return counters, nil
+}
+
+func newEventFilter(cfg *config.Config) (*processor.Filter, error) {
+	if len(cfg.EventAllowlist) == 0 && len(cfg.EventDenylist) == 0 && len(cfg.EventAccountFilter) == 0 {
+		return nil, nil
+	}
+	filter, err := processor.NewFilter(cfg.EventAllowlist, cfg.EventDenylist, cfg.EventAccountFilter)
+	if err != nil {
+		return nil, fmt.Errorf("create event filter: %w", err)
+	}
+	return filter, nil
+}
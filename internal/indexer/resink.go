@@ -0,0 +1,95 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+	"github.com/lugondev/go-indexer-solana-starter/internal/sink"
+)
+
+// ResinkOptions selects the stored events Resink publishes again.
+type ResinkOptions struct {
+	// Sink is a name of SINKS, e.g. kafka.
+	Sink string
+	// Writer, when set, receives the events instead of a new writer of
+	// Sink; it is not closed.
+	Writer   sink.Writer
+	FromSlot uint64
+	ToSlot   uint64
+}
+
+// Resink publishes the stored events of a slot range to one sink, oldest
+// first, in batches of SINK_BATCH_SIZE, so a sink added later gets the
+// history without fetching it from RPC again. It returns the number of
+// events written.
+func Resink(ctx context.Context, cfg *config.Config, repo repository.Repository, opts ResinkOptions) (int, error) {
+	replayer, ok := repository.Unwrap(repo).(repository.EventReplayer)
+	if !ok {
+		return 0, fmt.Errorf("%T does not support replaying events", repository.Unwrap(repo))
+	}
+	fieldCipher, err := cfg.FieldCipher()
+	if err != nil {
+		return 0, err
+	}
+	w := opts.Writer
+	if w == nil {
+		if w, err = newSinkWriter(cfg, opts.Sink); err != nil {
+			return 0, fmt.Errorf("create %s sink: %w", opts.Sink, err)
+		}
+		if w == nil {
+			return 0, fmt.Errorf("%s is the primary database", opts.Sink)
+		}
+		defer w.Close(context.Background())
+	}
+
+	batchSize := max(cfg.SinkBatchSize, 1)
+	batch := make([]models.Envelope, 0, batchSize)
+	var written, skipped int
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := w.Write(ctx, batch); err != nil {
+			return fmt.Errorf("write to %s sink: %w", opts.Sink, err)
+		}
+		written += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	filter := repository.EventFilter{FromSlot: opts.FromSlot, ToSlot: opts.ToSlot, Network: cfg.Network}
+	err = replayer.ReplayEvents(ctx, filter, func(stored interface{}) error {
+		event, ok := stored.(models.Event)
+		if !ok {
+			// A document of a type without a model, which the sinks never
+			// received either.
+			skipped++
+			return nil
+		}
+		if fieldCipher != nil {
+			if err := fieldCipher.Decrypt(event); err != nil {
+				return err
+			}
+		}
+		envelope, err := models.NewEnvelope(*event.Base(), event)
+		if err != nil {
+			return err
+		}
+		batch = append(batch, envelope)
+		if len(batch) < batchSize {
+			return nil
+		}
+		return flush()
+	})
+	if err == nil {
+		err = flush()
+	}
+	if skipped > 0 {
+		log.Printf("warning: skipped %d stored events without a model", skipped)
+	}
+	return written, err
+}
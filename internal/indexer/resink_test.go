@@ -0,0 +1,93 @@
+package indexer
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type replayRepo struct {
+	repository.Repository
+	events []interface{}
+	filter repository.EventFilter
+}
+
+func (r *replayRepo) ReplayEvents(ctx context.Context, filter repository.EventFilter, fn func(event interface{}) error) error {
+	r.filter = filter
+	for _, e := range r.events {
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordingWriter keeps the batches written to it.
+type recordingWriter struct {
+	batches [][]models.Envelope
+}
+
+func (w *recordingWriter) Write(ctx context.Context, events []models.Envelope) error {
+	w.batches = append(w.batches, slices.Clone(events))
+	return nil
+}
+
+func (w *recordingWriter) Close(ctx context.Context) error { return nil }
+
+func TestResink(t *testing.T) {
+	reset := func(signature string, slot, oldValue uint64) *models.CounterResetEvent {
+		return &models.CounterResetEvent{
+			BaseEvent: models.BaseEvent{EventType: models.EventTypeCounterReset, Signature: signature, Slot: slot},
+			OldValue:  oldValue,
+		}
+	}
+	repo := &replayRepo{events: []interface{}{
+		reset("a", 10, 1),
+		bson.M{"event_type": "UnknownEvent"},
+		reset("b", 11, 2),
+		reset("c", 12, 3),
+	}}
+	cfg := &config.Config{DatabaseType: config.DatabaseTypeMongo, SinkBatchSize: 2, Network: "devnet"}
+	w := &recordingWriter{}
+
+	n, err := Resink(context.Background(), cfg, repo, ResinkOptions{Sink: "kafka", Writer: w, FromSlot: 10})
+	if err != nil {
+		t.Fatalf("Resink() error = %v", err)
+	}
+	if n != 3 {
+		t.Errorf("Resink() = %d, want 3", n)
+	}
+	if repo.filter.FromSlot != 10 || repo.filter.Network != "devnet" {
+		t.Errorf("filter = %+v, want from slot 10 of devnet", repo.filter)
+	}
+
+	if len(w.batches) != 2 || len(w.batches[0]) != 2 || len(w.batches[1]) != 1 {
+		t.Fatalf("batches = %v, want batches of 2 and 1 events", w.batches)
+	}
+	want := []struct {
+		signature string
+		oldValue  uint64
+	}{{"a", 1}, {"b", 2}, {"c", 3}}
+	for i, envelope := range slices.Concat(w.batches...) {
+		var payload models.CounterResetEvent
+		if err := json.Unmarshal(envelope.Payload, &payload); err != nil {
+			t.Fatalf("events[%d]: decode payload: %v", i, err)
+		}
+		if envelope.Type != models.EventTypeCounterReset || envelope.Base.Signature != want[i].signature {
+			t.Errorf("events[%d] = %s %s, want %s %s", i, envelope.Type, envelope.Base.Signature, models.EventTypeCounterReset, want[i].signature)
+		}
+		if payload.Signature != want[i].signature || payload.OldValue != want[i].oldValue {
+			t.Errorf("events[%d] payload = %s with old value %d, want %s with %d", i, payload.Signature, payload.OldValue, want[i].signature, want[i].oldValue)
+		}
+	}
+
+	if _, err := Resink(context.Background(), cfg, repo, ResinkOptions{Sink: "mongodb"}); err == nil {
+		t.Errorf("Resink() to the primary database error = nil, want an error")
+	}
+}
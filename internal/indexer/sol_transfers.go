@@ -0,0 +1,162 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/decoder"
+	"github.com/lugondev/go-indexer-solana-starter/internal/failure"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/processor"
+	solanaClient "github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// solWatchlist holds the addresses of SOL_WATCHLIST. Each is polled like a
+// program, from the transactions naming it, and its native SOL transfers
+// are stored under the System Program.
+type solWatchlist struct {
+	addresses []solana.PublicKey
+	watched   map[solana.PublicKey]bool
+	processor *processor.EventProcessor
+}
+
+// newSolWatchlist parses SOL_WATCHLIST; its processor shares the settings
+// of starter. indexed are the addresses already polled, which cannot be
+// watched too as they would share a checkpoint.
+func newSolWatchlist(cfg *config.Config, starter *processor.EventProcessor, indexed []solana.PublicKey) (*solWatchlist, error) {
+	if len(cfg.SolWatchlist) == 0 {
+		return nil, nil
+	}
+	w := &solWatchlist{
+		watched:   make(map[solana.PublicKey]bool),
+		processor: starter.WithProgram(solana.SystemProgramID),
+	}
+	for _, s := range cfg.SolWatchlist {
+		address, err := solana.PublicKeyFromBase58(s)
+		if err != nil {
+			return nil, fmt.Errorf("SOL_WATCHLIST: %s: %w", s, err)
+		}
+		if slices.Contains(indexed, address) {
+			return nil, fmt.Errorf("SOL_WATCHLIST: %s is already indexed", address)
+		}
+		if w.watched[address] {
+			continue
+		}
+		w.watched[address] = true
+		w.addresses = append(w.addresses, address)
+	}
+	return w, nil
+}
+
+func (w *solWatchlist) keys() []solana.PublicKey {
+	if w == nil {
+		return nil
+	}
+	return w.addresses
+}
+
+func (i *Indexer) solDecoder(address solana.PublicKey) transactionDecoder {
+	return func(ctx context.Context, tx *fetchedTransaction) (*decodedTransaction, error) {
+		return i.decodeSolTransaction(ctx, address, tx)
+	}
+}
+
+func (i *Indexer) decodeSolTransaction(ctx context.Context, address solana.PublicKey, fetched *fetchedTransaction) (*decodedTransaction, error) {
+	signature, tx := fetched.signature, fetched.tx
+	// A failed transaction only paid its fee.
+	if tx.Meta.Err != nil || tx.Transaction == nil {
+		return nil, nil
+	}
+	txObj, err := tx.Transaction.GetTransaction()
+	if err != nil {
+		return nil, &failure.DecodeError{Err: fmt.Errorf("decode transaction: %w", err)}
+	}
+
+	transfers, errs := solTransfers(tx, txObj, address, i.solWatchlist.watched)
+	for _, err := range errs {
+		i.recordFailure(ctx, address, signature, tx.Slot, &failure.DecodeError{EventType: models.EventTypeSolTransfer, Err: err})
+		log.Printf("failed to decode sol transfer: %v", err)
+	}
+	decoded := &decodedTransaction{
+		signature: signature,
+		slot:      tx.Slot,
+		blockTime: fetched.blockTime,
+		program:   address,
+		processor: i.solWatchlist.processor,
+		kind:      "sol",
+	}
+	for _, t := range transfers {
+		decoded.events = append(decoded.events, decodedEvent{eventType: models.EventTypeSolTransfer, data: t})
+	}
+	return decoded, nil
+}
+
+// solTransfers returns the System Program transfers of tx to or from
+// address, in execution order, followed by the change of address's balance
+// they do not explain, net of the fee when address paid it. A transfer
+// between two watched addresses is left to the sender's cursor, so it is
+// stored once.
+func solTransfers(tx *rpc.GetTransactionResult, txObj *solana.Transaction, address solana.PublicKey, watched map[solana.PublicKey]bool) ([]models.SolTransferEvent, []error) {
+	keys := solanaClient.ResolveAccountKeys(txObj.Message, tx.Meta.LoadedAddresses)
+
+	var (
+		transfers []models.SolTransferEvent
+		errs      []error
+		explained int64
+	)
+	for _, ix := range counterInstructions(solana.SystemProgramID, txObj, tx.Meta, keys) {
+		t, err := decoder.DecodeSystemTransfer(ix)
+		if err != nil {
+			if slices.Contains(ix.Accounts, address) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if t == nil || (!t.From.Equals(address) && !t.To.Equals(address)) {
+			continue
+		}
+		if t.From.Equals(address) {
+			explained -= int64(t.Lamports)
+		}
+		if t.To.Equals(address) {
+			explained += int64(t.Lamports)
+			if !t.From.Equals(address) && watched[t.From] {
+				continue
+			}
+		}
+		event, err := models.NewSolTransferEvent(t.From, t.To, t.Lamports, models.SolTransferInstruction)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		transfers = append(transfers, *event)
+	}
+
+	n := slices.Index(keys, address)
+	if n < 0 || n >= len(tx.Meta.PreBalances) || n >= len(tx.Meta.PostBalances) {
+		return transfers, errs
+	}
+	change := int64(tx.Meta.PostBalances[n]) - int64(tx.Meta.PreBalances[n])
+	if n == 0 {
+		change += int64(tx.Meta.Fee)
+	}
+	var event *models.SolTransferEvent
+	var err error
+	switch residual := change - explained; {
+	case residual > 0:
+		event, err = models.NewSolTransferEvent(solana.PublicKey{}, address, uint64(residual), models.SolTransferBalance)
+	case residual < 0:
+		event, err = models.NewSolTransferEvent(address, solana.PublicKey{}, uint64(-residual), models.SolTransferBalance)
+	}
+	if err != nil {
+		errs = append(errs, err)
+	} else if event != nil {
+		transfers = append(transfers, *event)
+	}
+	return transfers, errs
+}
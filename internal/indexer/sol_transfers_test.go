@@ -0,0 +1,90 @@
+package indexer
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+func systemTransferData(lamports uint64) []byte {
+	data := binary.LittleEndian.AppendUint32(nil, 2)
+	return binary.LittleEndian.AppendUint64(data, lamports)
+}
+
+func TestSolTransfers(t *testing.T) {
+	payer, treasury, vault, program := solana.PublicKey{1}, solana.PublicKey{2}, solana.PublicKey{3}, solana.PublicKey{4}
+	// payer sends 500 to treasury, which sends 200 to vault; another
+	// program then moves 50 lamports out of treasury.
+	txObj := &solana.Transaction{Message: solana.Message{
+		AccountKeys: []solana.PublicKey{payer, treasury, vault, solana.SystemProgramID, program},
+		Instructions: []solana.CompiledInstruction{
+			{ProgramIDIndex: 3, Accounts: []uint16{0, 1}, Data: systemTransferData(500)},
+			{ProgramIDIndex: 3, Accounts: []uint16{1, 2}, Data: systemTransferData(200)},
+			{ProgramIDIndex: 4, Accounts: []uint16{1}, Data: []byte{1}},
+		},
+	}}
+	tx := &rpc.GetTransactionResult{Meta: &rpc.TransactionMeta{
+		Fee:          5000,
+		PreBalances:  []uint64{10000, 1000, 0, 1, 1},
+		PostBalances: []uint64{4500, 1250, 200, 1, 1},
+	}}
+	transfer := func(from, to solana.PublicKey, lamports uint64, source string) models.SolTransferEvent {
+		event, err := models.NewSolTransferEvent(from, to, lamports, source)
+		if err != nil {
+			t.Fatalf("NewSolTransferEvent() error = %v", err)
+		}
+		return *event
+	}
+
+	tests := []struct {
+		name    string
+		address solana.PublicKey
+		watched []solana.PublicKey
+		want    []models.SolTransferEvent
+	}{
+		{
+			name:    "fee payer",
+			address: payer,
+			watched: []solana.PublicKey{payer},
+			want:    []models.SolTransferEvent{transfer(payer, treasury, 500, models.SolTransferInstruction)},
+		},
+		{
+			name:    "unexplained balance change",
+			address: treasury,
+			watched: []solana.PublicKey{treasury},
+			want: []models.SolTransferEvent{
+				transfer(payer, treasury, 500, models.SolTransferInstruction),
+				transfer(treasury, vault, 200, models.SolTransferInstruction),
+				transfer(treasury, solana.PublicKey{}, 50, models.SolTransferBalance),
+			},
+		},
+		{
+			name:    "sender is watched too",
+			address: treasury,
+			watched: []solana.PublicKey{payer, treasury},
+			want: []models.SolTransferEvent{
+				transfer(treasury, vault, 200, models.SolTransferInstruction),
+				transfer(treasury, solana.PublicKey{}, 50, models.SolTransferBalance),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			watched := make(map[solana.PublicKey]bool)
+			for _, address := range tt.watched {
+				watched[address] = true
+			}
+			got, errs := solTransfers(tx, txObj, tt.address, watched)
+			if len(errs) != 0 {
+				t.Fatalf("solTransfers() errors = %v", errs)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("solTransfers() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/decoder"
+	"github.com/lugondev/go-indexer-solana-starter/internal/failure"
+	"github.com/lugondev/go-indexer-solana-starter/internal/processor"
+	solanaClient "github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// tokenPrograms are the programs whose instructions the SPL Token decoder
+// reads; Token-2022 shares the instruction layouts of SPL Token.
+var tokenPrograms = []solana.PublicKey{solanaClient.TokenProgramID, solanaClient.Token2022ProgramID}
+
+// tokenMint is a mint of SPL_TOKEN_MINTS. Its transactions are listed by
+// the mint's address and decoded with the built-in SPL Token decoder. A
+// plain Transfer only names token accounts, so it is found when another
+// instruction of its transaction names the mint.
+type tokenMint struct {
+	mint solana.PublicKey
+	// processors store the events under the token program that owns the
+	// mint, SPL Token or Token-2022.
+	processors map[solana.PublicKey]*processor.EventProcessor
+}
+
+// newTokenMints parses SPL_TOKEN_MINTS. Their processors share the
+// settings of starter.
+func newTokenMints(cfg *config.Config, starter *processor.EventProcessor) ([]*tokenMint, error) {
+	if len(cfg.SplTokenMints) == 0 {
+		return nil, nil
+	}
+	processors := make(map[solana.PublicKey]*processor.EventProcessor, len(tokenPrograms))
+	for _, program := range tokenPrograms {
+		processors[program] = starter.WithProgram(program)
+	}
+
+	mints := make([]*tokenMint, 0, len(cfg.SplTokenMints))
+	seen := make(map[solana.PublicKey]bool)
+	for _, s := range cfg.SplTokenMints {
+		mint, err := solana.PublicKeyFromBase58(s)
+		if err != nil {
+			return nil, fmt.Errorf("SPL_TOKEN_MINTS: %s: %w", s, err)
+		}
+		if seen[mint] {
+			continue
+		}
+		seen[mint] = true
+		mints = append(mints, &tokenMint{mint: mint, processors: processors})
+	}
+	return mints, nil
+}
+
+func (i *Indexer) tokenDecoder(m *tokenMint) transactionDecoder {
+	return func(ctx context.Context, tx *fetchedTransaction) (*decodedTransaction, error) {
+		return i.decodeTokenTransaction(ctx, m, tx)
+	}
+}
+
+func (i *Indexer) decodeTokenTransaction(ctx context.Context, m *tokenMint, fetched *fetchedTransaction) (*decodedTransaction, error) {
+	signature, tx := fetched.signature, fetched.tx
+	// A failed transaction moved no tokens.
+	if tx.Meta.Err != nil || tx.Transaction == nil {
+		return nil, nil
+	}
+	txObj, err := tx.Transaction.GetTransaction()
+	if err != nil {
+		return nil, &failure.DecodeError{Err: fmt.Errorf("decode transaction: %w", err)}
+	}
+
+	decoded := &decodedTransaction{
+		signature: signature,
+		slot:      tx.Slot,
+		blockTime: fetched.blockTime,
+		program:   m.mint,
+		kind:      "spl-token",
+	}
+	events, errs := tokenEvents(tx, txObj, m.mint)
+	for _, err := range errs {
+		i.recordFailure(ctx, m.mint, signature, tx.Slot, &failure.DecodeError{Err: err})
+		log.Printf("failed to decode spl-token instruction: %v", err)
+	}
+	for _, e := range events {
+		decoded.processor = m.processors[e.program]
+		decoded.events = append(decoded.events, decodedEvent{eventType: e.Type, data: e.Event})
+	}
+	if decoded.processor == nil {
+		return nil, nil
+	}
+	return decoded, nil
+}
+
+// programTokenEvent is a token event with the token program that emitted
+// it.
+type programTokenEvent struct {
+	decoder.TokenEvent
+	program solana.PublicKey
+}
+
+// tokenEvents decodes the token instructions of tx that concern mint, in
+// execution order. Instructions naming mint that do not decode are
+// returned as errors.
+func tokenEvents(tx *rpc.GetTransactionResult, txObj *solana.Transaction, mint solana.PublicKey) ([]programTokenEvent, []error) {
+	keys := solanaClient.ResolveAccountKeys(txObj.Message, tx.Meta.LoadedAddresses)
+	accounts := tokenAccounts(keys, tx.Meta)
+
+	var (
+		events []programTokenEvent
+		errs   []error
+	)
+	for _, program := range tokenPrograms {
+		for _, ix := range counterInstructions(program, txObj, tx.Meta, keys) {
+			event, err := decoder.DecodeTokenInstruction(ix, accounts)
+			if err != nil {
+				if slices.Contains(ix.Accounts, mint) {
+					errs = append(errs, err)
+				}
+				continue
+			}
+			if event != nil && event.Mint.Equals(mint) {
+				events = append(events, programTokenEvent{TokenEvent: *event, program: program})
+			}
+		}
+	}
+	return events, errs
+}
+
+// tokenAccounts returns the mint, owner and decimals of every token account
+// in the transaction's token balances.
+func tokenAccounts(keys []solana.PublicKey, meta *rpc.TransactionMeta) map[solana.PublicKey]decoder.TokenAccount {
+	accounts := make(map[solana.PublicKey]decoder.TokenAccount)
+	for _, balances := range [][]rpc.TokenBalance{meta.PreTokenBalances, meta.PostTokenBalances} {
+		for _, b := range balances {
+			if int(b.AccountIndex) >= len(keys) {
+				continue
+			}
+			account := decoder.TokenAccount{Mint: b.Mint}
+			if b.Owner != nil {
+				account.Owner = *b.Owner
+			}
+			if b.UiTokenAmount != nil {
+				account.Decimals = b.UiTokenAmount.Decimals
+			}
+			accounts[keys[b.AccountIndex]] = account
+		}
+	}
+	return accounts
+}
+
+func tokenMintKeys(mints []*tokenMint) []solana.PublicKey {
+	keys := make([]solana.PublicKey, len(mints))
+	for n, m := range mints {
+		keys[n] = m.mint
+	}
+	return keys
+}
@@ -0,0 +1,107 @@
+package indexer
+
+import (
+	"context"
+	"log"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/processor"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+	"github.com/lugondev/go-indexer-solana-starter/internal/upgrade"
+	solanaClient "github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// upgradeSeedEvents is the number of the latest stored upgrades the
+// deployments of the programs are seeded from.
+const upgradeSeedEvents = 100
+
+func newUpgradeWatcher(cfg *config.Config, client *solanaClient.Client, onUpgrade func(ctx context.Context, u upgrade.Upgrade) error, programs ...solana.PublicKey) *upgrade.Watcher {
+	if cfg.UpgradeCheckInterval == 0 {
+		return nil
+	}
+	return upgrade.NewWatcher(client, programs, onUpgrade, upgrade.Options{Interval: cfg.UpgradeCheckInterval})
+}
+
+// seedUpgrades starts the upgrade watcher from the last stored upgrade of
+// every program, so upgrades made while the indexer was stopped are
+// recorded too.
+func (i *Indexer) seedUpgrades(ctx context.Context) {
+	page, err := repository.GetTypedEvents[*models.ProgramUpgradedEvent](ctx, i.repo, models.EventTypeProgramUpgraded, repository.PageOptions{Limit: upgradeSeedEvents})
+	if err != nil {
+		log.Printf("warning: failed to load stored program upgrades: %v", err)
+		return
+	}
+	seeded := make(map[solana.PublicKey]bool)
+	for _, e := range page.Events {
+		if seeded[e.Program] {
+			continue
+		}
+		seeded[e.Program] = true
+		i.upgradeWatcher.Seed(e.Program, upgrade.Deployment{Slot: e.Slot, Hash: e.Hash})
+	}
+}
+
+// handleUpgrade stores a ProgramUpgradedEvent for u under the upgraded
+// program and, with PROGRAM_UPGRADE_REFETCH_IDL, fetches the IDL of an
+// IDL_PROGRAMS program again.
+func (i *Indexer) handleUpgrade(ctx context.Context, u upgrade.Upgrade) error {
+	log.Printf("warning: program %s was upgraded at slot %d, hash %s (was %s); its events may no longer decode with the current IDL or decoder", u.Program, u.Slot, u.Hash, u.Previous.Hash)
+
+	if u.Signature.IsZero() {
+		log.Printf("warning: the upgrade transaction of %s at slot %d was not found; the upgrade is not stored", u.Program, u.Slot)
+	} else if p := i.processorFor(u.Program); p != nil {
+		event := models.ProgramUpgradedEvent{
+			Program:      u.Program,
+			ProgramData:  u.ProgramData,
+			Hash:         u.Hash,
+			PreviousSlot: u.Previous.Slot,
+			PreviousHash: u.Previous.Hash,
+		}
+		if err := p.ProcessEvent(ctx, u.Signature.String(), u.Slot, u.BlockTime, models.EventTypeProgramUpgraded, event); err != nil {
+			return err
+		}
+	}
+
+	if !i.cfg.UpgradeRefetchIDL {
+		return nil
+	}
+	for _, cp := range i.customPrograms {
+		if cp.program != u.Program || !cp.fromIDL {
+			continue
+		}
+		d, err := fetchIDLDecoder(ctx, i.client, cp.program)
+		if err != nil {
+			log.Printf("warning: failed to fetch the IDL of upgraded program %s, keeping the previous one: %v", cp.program, err)
+			return nil
+		}
+		cp.setDecoder(d)
+	}
+	return nil
+}
+
+// processorFor returns the processor storing the events of an indexed
+// program, nil for other programs.
+func (i *Indexer) processorFor(program solana.PublicKey) *processor.EventProcessor {
+	if program == i.starterProgramID {
+		return i.starterProcessor
+	}
+	deployments, _ := i.counterDeployments()
+	for _, d := range deployments {
+		if d.program == program {
+			return d.processor
+		}
+	}
+	for _, lp := range i.logPrograms {
+		if lp.grammar.Program == program {
+			return lp.processor
+		}
+	}
+	for _, cp := range i.customPrograms {
+		if cp.program == program {
+			return cp.processor
+		}
+	}
+	return nil
+}
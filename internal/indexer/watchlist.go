@@ -0,0 +1,228 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/lugondev/go-indexer-solana-starter/internal/failure"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+	solanaClient "github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// The watchlist is kept in the database and changed through the admin API
+// while the indexer runs. Every address on it gets a cursor and a goroutine
+// of its own, started and stopped as the list changes; a removed address
+// keeps its checkpoint, so adding it back resumes where it stopped.
+
+// watchlistRefreshInterval is how often the stored watchlist is read again
+// besides after every change made through the API.
+const watchlistRefreshInterval = time.Minute
+
+// watcher indexes the transactions of one watched address.
+type watcher struct {
+	address solana.PublicKey
+	entry   atomic.Pointer[models.WatchedAddress]
+	stop    context.CancelFunc
+	done    chan struct{}
+}
+
+// SyncWatchlist makes the running indexer apply the stored watchlist
+// without waiting for the next refresh.
+func (i *Indexer) SyncWatchlist() {
+	select {
+	case i.watchSync <- struct{}{}:
+	default:
+	}
+}
+
+// IndexedAddress reports whether address is already polled as a program,
+// SPL token mint or SOL_WATCHLIST address, which cannot be watched too as
+// they would share a checkpoint.
+func (i *Indexer) IndexedAddress(address solana.PublicKey) bool {
+	indexed := []solana.PublicKey{i.starterProgramID}
+	deployments, _ := i.counterDeployments()
+	indexed = append(indexed, counterPrograms(deployments)...)
+	indexed = append(indexed, logProgramIDs(i.logPrograms)...)
+	indexed = append(indexed, customProgramIDs(i.customPrograms)...)
+	indexed = append(indexed, tokenMintKeys(i.tokenMints)...)
+	indexed = append(indexed, i.solWatchlist.keys()...)
+	return slices.Contains(indexed, address)
+}
+
+// runWatchlist keeps a watcher running for every stored watched address
+// until ctx is done.
+func (i *Indexer) runWatchlist(ctx context.Context) error {
+	store, ok := repository.Unwrap(i.repo).(repository.WatchlistStore)
+	if !ok {
+		return nil
+	}
+	ticker := time.NewTicker(watchlistRefreshInterval)
+	defer ticker.Stop()
+
+	watchers := make(map[solana.PublicKey]*watcher)
+	defer func() {
+		for _, w := range watchers {
+			w.stop()
+			<-w.done
+		}
+	}()
+	for {
+		i.syncWatchers(ctx, store, watchers)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		case <-i.watchSync:
+		}
+	}
+}
+
+// syncWatchers starts watchers for new addresses, stops those of removed
+// ones and updates the entries of the others. A watcher that fails to
+// start is tried again on the next sync.
+func (i *Indexer) syncWatchers(ctx context.Context, store repository.WatchlistStore, watchers map[solana.PublicKey]*watcher) {
+	entries, err := store.GetWatchedAddresses(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("warning: failed to read watchlist: %v", err)
+		}
+		return
+	}
+
+	wanted := make(map[solana.PublicKey]bool, len(entries))
+	for _, entry := range entries {
+		address, err := solana.PublicKeyFromBase58(entry.Address)
+		if err != nil || i.IndexedAddress(address) {
+			continue
+		}
+		wanted[address] = true
+		if w, ok := watchers[address]; ok {
+			w.entry.Store(&entry)
+			continue
+		}
+		w, err := i.startWatcher(ctx, address, &entry)
+		if err != nil {
+			log.Printf("warning: failed to watch %s: %v", address, err)
+			continue
+		}
+		watchers[address] = w
+		log.Printf("watching transactions of %s", address)
+	}
+
+	for address, w := range watchers {
+		if wanted[address] {
+			continue
+		}
+		w.stop()
+		<-w.done
+		delete(watchers, address)
+		log.Printf("stopped watching transactions of %s", address)
+	}
+
+	i.mu.Lock()
+	i.watchers = make(map[solana.PublicKey]*watcher, len(watchers))
+	for address, w := range watchers {
+		i.watchers[address] = w
+	}
+	i.mu.Unlock()
+}
+
+func (i *Indexer) startWatcher(ctx context.Context, address solana.PublicKey, entry *models.WatchedAddress) (*watcher, error) {
+	w := &watcher{address: address, done: make(chan struct{})}
+	w.entry.Store(entry)
+	c, err := i.openCursor(ctx, "watch", address, i.watchDecoder(w))
+	if err != nil {
+		return nil, err
+	}
+
+	watchCtx, stop := context.WithCancel(ctx)
+	w.stop = stop
+	go func() {
+		defer close(w.done)
+		i.runProgram(watchCtx, c)
+	}()
+	return w, nil
+}
+
+// watchedWebhook returns the webhook of a watched address, or "".
+func (i *Indexer) watchedWebhook(address string) string {
+	key, err := solana.PublicKeyFromBase58(address)
+	if err != nil {
+		return ""
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	if w, ok := i.watchers[key]; ok {
+		return w.entry.Load().Webhook
+	}
+	return ""
+}
+
+func (i *Indexer) watchDecoder(w *watcher) transactionDecoder {
+	return func(ctx context.Context, fetched *fetchedTransaction) (*decodedTransaction, error) {
+		tx := fetched.tx
+		if tx.Transaction == nil {
+			return nil, nil
+		}
+		txObj, err := tx.Transaction.GetTransaction()
+		if err != nil {
+			return nil, &failure.DecodeError{EventType: models.EventTypeWatchedTransaction, Err: fmt.Errorf("decode transaction: %w", err)}
+		}
+		event := watchedTransaction(tx, txObj, w.address)
+		event.Label = w.entry.Load().Label
+		return &decodedTransaction{
+			signature: fetched.signature,
+			slot:      tx.Slot,
+			blockTime: fetched.blockTime,
+			program:   w.address,
+			processor: i.watchProcessor,
+			kind:      "watch",
+			events:    []decodedEvent{{eventType: models.EventTypeWatchedTransaction, data: event}},
+		}, nil
+	}
+}
+
+// watchedTransaction summarizes tx for the watched address.
+func watchedTransaction(tx *rpc.GetTransactionResult, txObj *solana.Transaction, address solana.PublicKey) models.WatchedTransactionEvent {
+	keys := solanaClient.ResolveAccountKeys(txObj.Message, tx.Meta.LoadedAddresses)
+	event := models.WatchedTransactionEvent{
+		Address:  address,
+		Programs: []string{},
+		Success:  tx.Meta.Err == nil,
+		Fee:      tx.Meta.Fee,
+	}
+	if len(keys) > 0 {
+		event.FeePayer = keys[0]
+	}
+
+	inner := make(map[uint16][]solana.CompiledInstruction, len(tx.Meta.InnerInstructions))
+	for _, set := range tx.Meta.InnerInstructions {
+		inner[set.Index] = set.Instructions
+	}
+	addProgram := func(ix solana.CompiledInstruction) {
+		if int(ix.ProgramIDIndex) >= len(keys) {
+			return
+		}
+		if program := keys[ix.ProgramIDIndex].String(); !slices.Contains(event.Programs, program) {
+			event.Programs = append(event.Programs, program)
+		}
+	}
+	for idx, ix := range txObj.Message.Instructions {
+		addProgram(ix)
+		for _, innerIx := range inner[uint16(idx)] {
+			addProgram(innerIx)
+		}
+	}
+
+	if n := slices.Index(keys, address); n >= 0 && n < len(tx.Meta.PreBalances) && n < len(tx.Meta.PostBalances) {
+		event.BalanceChange = int64(tx.Meta.PostBalances[n]) - int64(tx.Meta.PreBalances[n])
+	}
+	return event
+}
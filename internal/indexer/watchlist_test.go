@@ -0,0 +1,43 @@
+package indexer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+func TestWatchedTransaction(t *testing.T) {
+	payer, wallet, programA, programB := solana.PublicKey{1}, solana.PublicKey{2}, solana.PublicKey{3}, solana.PublicKey{4}
+	txObj := &solana.Transaction{Message: solana.Message{
+		AccountKeys: []solana.PublicKey{payer, wallet, programA, programB, solana.SystemProgramID},
+		Instructions: []solana.CompiledInstruction{
+			{ProgramIDIndex: 3, Accounts: []uint16{1}},
+			{ProgramIDIndex: 2, Accounts: []uint16{0, 1}},
+		},
+	}}
+	tx := &rpc.GetTransactionResult{Meta: &rpc.TransactionMeta{
+		Err:          map[string]interface{}{"InstructionError": []interface{}{1, "Custom"}},
+		Fee:          5000,
+		PreBalances:  []uint64{10000, 700, 1, 1, 1},
+		PostBalances: []uint64{5000, 200, 1, 1, 1},
+		InnerInstructions: []rpc.InnerInstruction{
+			{Index: 0, Instructions: []solana.CompiledInstruction{{ProgramIDIndex: 4}, {ProgramIDIndex: 2}}},
+		},
+	}}
+
+	got := watchedTransaction(tx, txObj, wallet)
+	want := models.WatchedTransactionEvent{
+		Address:       wallet,
+		FeePayer:      payer,
+		Programs:      []string{programB.String(), solana.SystemProgramID.String(), programA.String()},
+		Success:       false,
+		Fee:           5000,
+		BalanceChange: -500,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("watchedTransaction() = %+v, want %+v", got, want)
+	}
+}
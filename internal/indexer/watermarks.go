@@ -0,0 +1,131 @@
+package indexer
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+// Signatures are listed at the cluster's default finalized commitment, so
+// once a poll has processed every pending signature of a program, its data
+// is complete and final up to the finalized slot read before the listing.
+// The finalized watermark is the lowest such slot of the indexed programs.
+
+// loadWatermarks restores the saved watermarks, so the programs count as
+// complete up to the saved finalized slot until their first poll.
+func (i *Indexer) loadWatermarks(ctx context.Context, programs []solana.PublicKey) {
+	store, ok := repository.Unwrap(i.repo).(repository.WatermarkStore)
+	if !ok {
+		return
+	}
+	saved, err := store.GetWatermarks(ctx, i.cfg.Network)
+	if err != nil {
+		log.Printf("warning: failed to load watermarks: %v", err)
+		return
+	}
+	if saved == nil {
+		return
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.clusterConfirmed = saved.Confirmed
+	for _, program := range programs {
+		i.complete[program] = saved.Finalized
+	}
+}
+
+// runWatermarks reads the cluster's confirmed and finalized slots every
+// poll interval and saves the watermarks when they move.
+func (i *Indexer) runWatermarks(ctx context.Context) {
+	store, _ := repository.Unwrap(i.repo).(repository.WatermarkStore)
+	ticker := time.NewTicker(i.cfg.PollInterval)
+	defer ticker.Stop()
+
+	var saved models.Watermarks
+	for {
+		confirmed, err := i.client.GetSlot(ctx, rpc.CommitmentConfirmed)
+		if err == nil {
+			var finalized uint64
+			finalized, err = i.client.GetSlot(ctx, rpc.CommitmentFinalized)
+			if err == nil {
+				i.mu.Lock()
+				i.clusterConfirmed, i.clusterFinalized = confirmed, finalized
+				i.mu.Unlock()
+			}
+		}
+		if err != nil && ctx.Err() == nil {
+			log.Printf("warning: failed to read cluster slots: %v", err)
+		}
+
+		if watermarks := i.Watermarks(); store != nil && watermarks.Finalized > 0 && !sameWatermarks(watermarks, saved) {
+			if err := store.SaveWatermarks(ctx, i.cfg.Network, &watermarks); err != nil {
+				log.Printf("warning: failed to save watermarks: %v", err)
+			} else {
+				saved = watermarks
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func sameWatermarks(a, b models.Watermarks) bool {
+	return a.Processed == b.Processed && a.Confirmed == b.Confirmed && a.Finalized == b.Finalized
+}
+
+// clusterFinalizedSlot returns the cluster's finalized slot when last read,
+// or 0 before the first read.
+func (i *Indexer) clusterFinalizedSlot() uint64 {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.clusterFinalized
+}
+
+// markComplete records that every transaction of program up to slot has
+// been processed.
+func (i *Indexer) markComplete(program solana.PublicKey, slot uint64) {
+	if slot == 0 {
+		return
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.complete[program] = max(i.complete[program], slot)
+}
+
+// Watermarks returns how far the indexed data goes. A program that has not
+// completed a poll yet holds the finalized watermark at 0.
+func (i *Indexer) Watermarks() models.Watermarks {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	finalized := i.complete[i.starterProgramID]
+	for _, d := range i.counters {
+		finalized = min(finalized, i.complete[d.program])
+	}
+	for _, lp := range i.logPrograms {
+		finalized = min(finalized, i.complete[lp.grammar.Program])
+	}
+	for _, cp := range i.customPrograms {
+		finalized = min(finalized, i.complete[cp.program])
+	}
+	for _, m := range i.tokenMints {
+		finalized = min(finalized, i.complete[m.mint])
+	}
+	for _, address := range i.solWatchlist.keys() {
+		finalized = min(finalized, i.complete[address])
+	}
+	return models.Watermarks{
+		Processed: i.currentSlot,
+		Confirmed: i.clusterConfirmed,
+		Finalized: finalized,
+		UpdatedAt: time.Now().UTC(),
+	}
+}
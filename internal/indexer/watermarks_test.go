@@ -0,0 +1,40 @@
+package indexer
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+func TestIndexer_Watermarks(t *testing.T) {
+	starter, counter := solana.PublicKey{1}, solana.PublicKey{2}
+	i := &Indexer{
+		starterProgramID: starter,
+		counters:         []*counterDeployment{{label: "main", program: counter}},
+		complete:         make(map[solana.PublicKey]uint64),
+		currentSlot:      90,
+		clusterConfirmed: 150,
+	}
+
+	tests := []struct {
+		name          string
+		program       solana.PublicKey
+		slot          uint64
+		wantFinalized uint64
+	}{
+		{name: "counter not complete yet", program: starter, slot: 120, wantFinalized: 0},
+		{name: "lowest program", program: counter, slot: 110, wantFinalized: 110},
+		{name: "unknown slot ignored", program: counter, slot: 0, wantFinalized: 110},
+		{name: "never moves back", program: counter, slot: 100, wantFinalized: 110},
+		{name: "capped by starter", program: counter, slot: 130, wantFinalized: 120},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i.markComplete(tt.program, tt.slot)
+			got := i.Watermarks()
+			if got.Finalized != tt.wantFinalized || got.Processed != 90 || got.Confirmed != 150 {
+				t.Errorf("Watermarks() = %+v, want finalized %d, processed 90, confirmed 150", got, tt.wantFinalized)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+// Package jsonschema derives JSON Schemas (draft 2020-12) from Go types,
+// following the rules encoding/json marshals them by: field names and
+// omitempty from the json tags, embedded structs inlined and nil slices,
+// maps and pointers written as null.
+package jsonschema
+
+import (
+	"encoding"
+	"encoding/json"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Draft is the JSON Schema dialect of the generated schemas.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// base58Pattern matches the base58 strings solana.PublicKey marshals to.
+const base58Pattern = "^[1-9A-HJ-NP-Za-km-z]{32,44}$"
+
+// Types is the "type" keyword: one JSON type, or several when a value may
+// also be null.
+type Types []string
+
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*t = Types{one}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+// Schema is the subset of JSON Schema the generator writes. The zero
+// Schema accepts any value.
+type Schema struct {
+	Schema          string             `json:"$schema,omitempty"`
+	ID              string             `json:"$id,omitempty"`
+	Title           string             `json:"title,omitempty"`
+	Description     string             `json:"description,omitempty"`
+	Type            Types              `json:"type,omitempty"`
+	Format          string             `json:"format,omitempty"`
+	Pattern         string             `json:"pattern,omitempty"`
+	ContentEncoding string             `json:"contentEncoding,omitempty"`
+	Minimum         *int64             `json:"minimum,omitempty"`
+	Maximum         *uint64            `json:"maximum,omitempty"`
+	Properties      map[string]*Schema `json:"properties,omitempty"`
+	Required        []string           `json:"required,omitempty"`
+	// AdditionalProperties is the schema of the values of a map.
+	AdditionalProperties *Schema `json:"additionalProperties,omitempty"`
+	Items                *Schema `json:"items,omitempty"`
+	MinItems             *int    `json:"minItems,omitempty"`
+	MaxItems             *int    `json:"maxItems,omitempty"`
+}
+
+var (
+	publicKeyType     = reflect.TypeOf(solana.PublicKey{})
+	timeType          = reflect.TypeOf(time.Time{})
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// For returns the schema of the JSON encoding of values of type t.
+//
+// Structs are described by their fields even when they implement
+// json.Marshaler, as the event models only derive fields when marshaling;
+// other json.Marshaler types and interfaces accept any value.
+func For(t reflect.Type) *Schema {
+	return schemaOf(t, make(map[reflect.Type]bool))
+}
+
+// schemaOf describes t; visiting holds the structs being described, so a
+// recursive type accepts any value where it refers to itself.
+func schemaOf(t reflect.Type, visiting map[reflect.Type]bool) *Schema {
+	switch t {
+	case publicKeyType:
+		return &Schema{Type: Types{"string"}, Pattern: base58Pattern}
+	case timeType:
+		return &Schema{Type: Types{"string"}, Format: "date-time"}
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(schemaOf(t.Elem(), visiting))
+	case reflect.Bool:
+		return &Schema{Type: Types{"boolean"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: Types{"integer"}}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: Types{"integer"}, Minimum: new(int64), Maximum: ptr(uint64(math.MaxUint64) >> (64 - t.Bits()))}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: Types{"number"}}
+	case reflect.String:
+		return &Schema{Type: Types{"string"}}
+	case reflect.Interface:
+		return &Schema{}
+	}
+
+	if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return &Schema{Type: Types{"string"}}
+	}
+
+	switch t.Kind() {
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: Types{"string", "null"}, ContentEncoding: "base64"}
+		}
+		return &Schema{Type: Types{"array", "null"}, Items: schemaOf(t.Elem(), visiting)}
+	case reflect.Array:
+		return &Schema{Type: Types{"array"}, Items: schemaOf(t.Elem(), visiting), MinItems: ptr(t.Len()), MaxItems: ptr(t.Len())}
+	case reflect.Map:
+		return &Schema{Type: Types{"object", "null"}, AdditionalProperties: schemaOf(t.Elem(), visiting)}
+	case reflect.Struct:
+		if visiting[t] {
+			return &Schema{}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+		s := &Schema{Type: Types{"object"}, Properties: make(map[string]*Schema)}
+		addFields(s, t, visiting)
+		return s
+	}
+	// Channels and functions do not marshal.
+	return &Schema{}
+}
+
+// addFields adds the fields of struct t to s. Fields of embedded structs
+// come after those of t, so they do not replace them, as encoding/json
+// prefers the shallower field.
+func addFields(s *Schema, t reflect.Type, visiting map[reflect.Type]bool) {
+	var embedded []reflect.Type
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				embedded = append(embedded, ft)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if _, ok := s.Properties[name]; ok {
+			continue
+		}
+
+		field := schemaOf(f.Type, visiting)
+		if hasOption(opts, "string") {
+			switch f.Type.Kind() {
+			case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+				field = &Schema{Type: Types{"string"}}
+			}
+		}
+		s.Properties[name] = field
+		if !hasOption(opts, "omitempty") && !hasOption(opts, "omitzero") {
+			s.Required = append(s.Required, name)
+		}
+	}
+	for _, et := range embedded {
+		if visiting[et] {
+			continue
+		}
+		visiting[et] = true
+		addFields(s, et, visiting)
+		delete(visiting, et)
+	}
+}
+
+func hasOption(opts, option string) bool {
+	for opts != "" {
+		var o string
+		o, opts, _ = strings.Cut(opts, ",")
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// nullable lets s also be null; a schema accepting anything already does.
+func nullable(s *Schema) *Schema {
+	if len(s.Type) > 0 && !slices.Contains(s.Type, "null") {
+		s.Type = append(s.Type, "null")
+	}
+	return s
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
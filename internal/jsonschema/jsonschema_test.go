@@ -0,0 +1,84 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+type inner struct {
+	Name  string `json:"name"`
+	Shade string `json:"shade"`
+}
+
+type sample struct {
+	inner
+	Shade    int               `json:"shade"`
+	Key      solana.PublicKey  `json:"key"`
+	At       time.Time         `json:"at"`
+	Raw      []byte            `json:"raw,omitempty"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Small    uint8             `json:"small"`
+	Count    uint64            `json:"count,string"`
+	Next     *sample           `json:"next"`
+	Skipped  string            `json:"-"`
+	untagged string
+	Default  bool
+}
+
+func TestFor(t *testing.T) {
+	s := For(reflect.TypeOf(sample{}))
+
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{"name", `{"type":"string"}`},
+		{"shade", `{"type":"integer"}`},
+		{"key", `{"type":"string","pattern":"^[1-9A-HJ-NP-Za-km-z]{32,44}$"}`},
+		{"at", `{"type":"string","format":"date-time"}`},
+		{"raw", `{"type":["string","null"],"contentEncoding":"base64"}`},
+		{"tags", `{"type":["array","null"],"items":{"type":"string"}}`},
+		{"labels", `{"type":["object","null"],"additionalProperties":{"type":"string"}}`},
+		{"small", `{"type":"integer","minimum":0,"maximum":255}`},
+		{"count", `{"type":"string"}`},
+		{"next", `{}`},
+		{"Default", `{"type":"boolean"}`},
+	}
+	for _, tt := range tests {
+		got, err := json.Marshal(s.Properties[tt.field])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("For() %s = %s, want %s", tt.field, got, tt.want)
+		}
+	}
+	if len(s.Properties) != len(tests) {
+		t.Errorf("For() has %d properties, want %d", len(s.Properties), len(tests))
+	}
+
+	slices.Sort(s.Required)
+	want := []string{"Default", "at", "count", "key", "name", "next", "shade", "small", "tags"}
+	if !slices.Equal(s.Required, want) {
+		t.Errorf("For() required = %v, want %v", s.Required, want)
+	}
+}
+
+func TestTypes_JSON(t *testing.T) {
+	for _, raw := range []string{`"string"`, `["string","null"]`} {
+		var types Types
+		if err := json.Unmarshal([]byte(raw), &types); err != nil {
+			t.Fatalf("Unmarshal(%s) error = %v", raw, err)
+		}
+		got, _ := json.Marshal(types)
+		if string(got) != raw {
+			t.Errorf("Marshal(Unmarshal(%s)) = %s", raw, got)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package loadgen
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Tx is a synthetic transaction together with what an RPC node would report
+// about it.
+type Tx struct {
+	Signature solana.Signature
+	Slot      uint64
+	BlockTime time.Time
+	Program   solana.PublicKey
+	// Raw is the signed transaction in wire format.
+	Raw  []byte
+	Logs []string
+}
+
+type counterState struct {
+	key       solana.PublicKey
+	authority solana.PrivateKey
+	value     uint64
+}
+
+// Generator builds transactions whose logs and instructions match what the
+// counter and starter programs emit, so the indexer decodes them like real
+// traffic. It is not safe for concurrent use.
+type Generator struct {
+	counterProgram solana.PublicKey
+	starterProgram solana.PublicKey
+	rng            *rand.Rand
+	payers         []solana.PrivateKey
+	counters       []*counterState
+	feeCollector   solana.PublicKey
+	mints          []solana.PublicKey
+	holders        []solana.PublicKey
+	seq            uint64
+}
+
+// NewGenerator creates accounts for the synthetic traffic. accounts sizes
+// the pools of counters, payers, mints and holders.
+func NewGenerator(counterProgram, starterProgram solana.PublicKey, accounts int, seed int64) (*Generator, error) {
+	if accounts <= 0 {
+		accounts = 16
+	}
+	g := &Generator{
+		counterProgram: counterProgram,
+		starterProgram: starterProgram,
+		rng:            rand.New(rand.NewSource(seed)),
+	}
+
+	for i := 0; i < accounts; i++ {
+		payer, err := solana.NewRandomPrivateKey()
+		if err != nil {
+			return nil, fmt.Errorf("generate payer: %w", err)
+		}
+		g.payers = append(g.payers, payer)
+		g.counters = append(g.counters, &counterState{key: g.randomKey(), authority: payer})
+		g.mints = append(g.mints, g.randomKey())
+		g.holders = append(g.holders, g.randomKey())
+	}
+	g.feeCollector = g.randomKey()
+	return g, nil
+}
+
+func (g *Generator) randomKey() solana.PublicKey {
+	var key solana.PublicKey
+	g.rng.Read(key[:])
+	return key
+}
+
+// Counter returns a counter program transaction: mostly increments, with
+// adds, paid increments, decrements and the occasional reset.
+func (g *Generator) Counter(slot uint64, blockTime time.Time) (*Tx, error) {
+	counter := g.counters[g.rng.Intn(len(g.counters))]
+	payer := g.payers[g.rng.Intn(len(g.payers))]
+
+	var (
+		name     string
+		data     []byte
+		accounts solana.AccountMetaSlice
+		message  string
+		signer   = payer
+	)
+	switch roll := g.rng.Intn(100); {
+	case roll < 50 || (roll < 60 && counter.value == 0):
+		counter.value++
+		name = "increment"
+		accounts = solana.AccountMetaSlice{solana.Meta(counter.key).WRITE()}
+		message = fmt.Sprintf("Counter incremented to: %d", counter.value)
+	case roll < 60:
+		counter.value--
+		name = "decrement"
+		accounts = solana.AccountMetaSlice{solana.Meta(counter.key).WRITE()}
+		message = fmt.Sprintf("Counter decremented to: %d", counter.value)
+	case roll < 80:
+		added := uint64(g.rng.Intn(100) + 1)
+		counter.value += added
+		name = "add"
+		data = binary.LittleEndian.AppendUint64(nil, added)
+		accounts = solana.AccountMetaSlice{solana.Meta(counter.key).WRITE()}
+		message = fmt.Sprintf("Added %d to counter. New value: %d", added, counter.value)
+	case roll < 95:
+		payment := uint64(g.rng.Intn(9_000_000) + 1_000_000)
+		counter.value++
+		name = "increment_with_payment"
+		data = binary.LittleEndian.AppendUint64(nil, payment)
+		accounts = solana.AccountMetaSlice{
+			solana.Meta(counter.key).WRITE(),
+			solana.Meta(payer.PublicKey()).WRITE().SIGNER(),
+			solana.Meta(g.feeCollector).WRITE(),
+			solana.Meta(solana.SystemProgramID),
+		}
+		message = fmt.Sprintf("Payment of %d lamports received. Counter incremented to: %d", payment, counter.value)
+	default:
+		counter.value = 0
+		signer = counter.authority
+		name = "reset"
+		accounts = solana.AccountMetaSlice{
+			solana.Meta(counter.key).WRITE(),
+			solana.Meta(counter.authority.PublicKey()).SIGNER(),
+		}
+		message = "Counter reset"
+	}
+
+	ix := solana.NewInstruction(g.counterProgram, accounts, append(instructionDiscriminator(name), data...))
+	return g.build(g.counterProgram, signer, ix, slot, blockTime, "Program log: "+message)
+}
+
+// Starter returns a starter program transaction that emits a token mint,
+// transfer or burn event.
+func (g *Generator) Starter(slot uint64, blockTime time.Time) (*Tx, error) {
+	payer := g.payers[g.rng.Intn(len(g.payers))]
+	mint := g.mints[g.rng.Intn(len(g.mints))]
+	holder := g.holders[g.rng.Intn(len(g.holders))]
+	amount := uint64(g.rng.Int63n(1_000_000_000) + 1)
+
+	var name, event string
+	var fields []byte
+	switch roll := g.rng.Intn(100); {
+	case roll < 40:
+		name, event = "mint_tokens", "TokensMintedEvent"
+		fields = append(append(fields, mint[:]...), holder[:]...)
+	case roll < 85:
+		to := g.holders[g.rng.Intn(len(g.holders))]
+		name, event = "transfer_tokens", "TokensTransferredEvent"
+		fields = append(append(append(fields, mint[:]...), holder[:]...), to[:]...)
+	default:
+		name, event = "burn_tokens", "TokensBurnedEvent"
+		fields = append(append(fields, mint[:]...), holder[:]...)
+	}
+	fields = binary.LittleEndian.AppendUint64(fields, amount)
+	fields = binary.LittleEndian.AppendUint64(fields, uint64(blockTime.Unix()))
+
+	payload := append(eventDiscriminator(event), fields...)
+	ix := solana.NewInstruction(g.starterProgram, solana.AccountMetaSlice{
+		solana.Meta(mint).WRITE(),
+		solana.Meta(holder).WRITE(),
+		solana.Meta(payer.PublicKey()).WRITE().SIGNER(),
+	}, binary.LittleEndian.AppendUint64(instructionDiscriminator(name), amount))
+	return g.build(g.starterProgram, payer, ix, slot, blockTime, "Program data: "+base64.StdEncoding.EncodeToString(payload))
+}
+
+func (g *Generator) build(program solana.PublicKey, signer solana.PrivateKey, ix solana.Instruction, slot uint64, blockTime time.Time, logLine string) (*Tx, error) {
+	// A distinct blockhash per transaction keeps signatures unique when the
+	// same instruction repeats.
+	g.seq++
+	blockhash := solana.Hash(sha256.Sum256(binary.LittleEndian.AppendUint64(nil, g.seq)))
+
+	tx, err := solana.NewTransaction([]solana.Instruction{ix}, blockhash, solana.TransactionPayer(signer.PublicKey()))
+	if err != nil {
+		return nil, fmt.Errorf("build transaction: %w", err)
+	}
+	if _, err := tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+		if key.Equals(signer.PublicKey()) {
+			return &signer
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("sign transaction: %w", err)
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("encode transaction: %w", err)
+	}
+
+	return &Tx{
+		Signature: tx.Signatures[0],
+		Slot:      slot,
+		BlockTime: blockTime,
+		Program:   program,
+		Raw:       raw,
+		Logs: []string{
+			fmt.Sprintf("Program %s invoke [1]", program),
+			logLine,
+			fmt.Sprintf("Program %s consumed %d of 200000 compute units", program, 2000+g.rng.Intn(8000)),
+			fmt.Sprintf("Program %s success", program),
+		},
+	}, nil
+}
+
+func instructionDiscriminator(name string) []byte {
+	hash := sha256.Sum256([]byte("global:" + name))
+	return hash[:8]
+}
+
+func eventDiscriminator(name string) []byte {
+	hash := sha256.Sum256([]byte("event:" + name))
+	return hash[:8]
+}
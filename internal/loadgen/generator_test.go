@@ -0,0 +1,62 @@
+package loadgen
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/decoder"
+	pkgdecoder "github.com/lugondev/go-indexer-solana-starter/pkg/decoder"
+)
+
+var (
+	testCounterProgram = solana.MustPublicKeyFromBase58("CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc")
+	testStarterProgram = solana.MustPublicKeyFromBase58("gARh1g6reuvsAHB7DXqiuYzzyiJeoiJmtmCpV8Y5uWC")
+)
+
+func TestGenerator_CounterDecodes(t *testing.T) {
+	g, err := NewGenerator(testCounterProgram, testStarterProgram, 4, 1)
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+	parser := decoder.NewCounterLogParser(testCounterProgram)
+
+	seen := make(map[solana.Signature]bool)
+	for i := 0; i < 200; i++ {
+		tx, err := g.Counter(uint64(i), time.Unix(1700000000, 0))
+		if err != nil {
+			t.Fatalf("Counter() error = %v", err)
+		}
+		if seen[tx.Signature] {
+			t.Fatalf("duplicate signature %s", tx.Signature)
+		}
+		seen[tx.Signature] = true
+
+		actions, err := parser.ParseLogs(tx.Logs, nil)
+		if err != nil || len(actions) != 1 {
+			t.Fatalf("ParseLogs(%q) = %d actions, %v; want 1", tx.Logs, len(actions), err)
+		}
+	}
+}
+
+func TestGenerator_StarterDecodes(t *testing.T) {
+	g, err := NewGenerator(testCounterProgram, testStarterProgram, 4, 1)
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+	events := decoder.NewEventDecoder()
+
+	for i := 0; i < 50; i++ {
+		tx, err := g.Starter(uint64(i), time.Unix(1700000000, 0))
+		if err != nil {
+			t.Fatalf("Starter() error = %v", err)
+		}
+		data := pkgdecoder.ParseProgramData(tx.Logs)
+		if len(data) != 1 {
+			t.Fatalf("ParseProgramData(%q) returned %d payloads, want 1", tx.Logs, len(data))
+		}
+		if _, _, err := events.DecodeEvent(data[0]); err != nil {
+			t.Errorf("DecodeEvent() error = %v", err)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package loadgen
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+const (
+	// slotDuration is the target slot time of mainnet.
+	slotDuration = 400 * time.Millisecond
+
+	maxSignaturesLimit = 1000
+)
+
+// Clock maps wall time to slots the way a validator would, starting at
+// StartSlot at Start.
+type Clock struct {
+	Start     time.Time
+	StartSlot uint64
+}
+
+func (c Clock) Slot(t time.Time) uint64 {
+	if t.Before(c.Start) {
+		return c.StartSlot
+	}
+	return c.StartSlot + uint64(t.Sub(c.Start)/slotDuration)
+}
+
+func (c Clock) Time(slot uint64) time.Time {
+	if slot < c.StartSlot {
+		return c.Start
+	}
+	return c.Start.Add(time.Duration(slot-c.StartSlot) * slotDuration)
+}
+
+// MockRPC serves the Solana JSON-RPC methods the indexer polls from an
+// in-memory ledger of generated transactions. It keeps the latest retain
+// transactions and forgets older ones.
+type MockRPC struct {
+	clock  Clock
+	now    func() time.Time
+	retain int
+
+	mu        sync.RWMutex
+	all       []*Tx
+	byProgram map[solana.PublicKey][]*Tx
+	bySig     map[solana.Signature]*Tx
+}
+
+func NewMockRPC(clock Clock, retain int) *MockRPC {
+	if retain <= 0 {
+		retain = 100000
+	}
+	return &MockRPC{
+		clock:     clock,
+		now:       time.Now,
+		retain:    retain,
+		byProgram: make(map[solana.PublicKey][]*Tx),
+		bySig:     make(map[solana.Signature]*Tx),
+	}
+}
+
+// Add appends tx to the ledger. Transactions must be added in slot order.
+func (m *MockRPC) Add(tx *Tx) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.all = append(m.all, tx)
+	m.byProgram[tx.Program] = append(m.byProgram[tx.Program], tx)
+	m.bySig[tx.Signature] = tx
+
+	for len(m.all) > m.retain {
+		oldest := m.all[0]
+		m.all = m.all[1:]
+		delete(m.bySig, oldest.Signature)
+		m.byProgram[oldest.Program] = m.byProgram[oldest.Program][1:]
+	}
+}
+
+type rpcRequest struct {
+	JSONRPC string            `json:"jsonrpc"`
+	ID      json.RawMessage   `json:"id"`
+	Method  string            `json:"method"`
+	Params  []json.RawMessage `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (m *MockRPC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "JSON-RPC requests must be POSTed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req rpcRequest
+	resp := map[string]interface{}{"jsonrpc": "2.0"}
+	if err := json.Unmarshal(body, &req); err != nil {
+		resp["id"] = nil
+		resp["error"] = rpcError{Code: -32700, Message: "parse error"}
+	} else {
+		resp["id"] = req.ID
+		result, rpcErr := m.call(req.Method, req.Params)
+		if rpcErr != nil {
+			resp["error"] = rpcErr
+		} else {
+			resp["result"] = result
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (m *MockRPC) call(method string, params []json.RawMessage) (interface{}, *rpcError) {
+	switch method {
+	case "getHealth":
+		return "ok", nil
+	case "getVersion":
+		return map[string]interface{}{"solana-core": "loadgen", "feature-set": 0}, nil
+	case "getSlot":
+		return m.clock.Slot(m.now()), nil
+	case "getBlockTime":
+		var slot uint64
+		if len(params) < 1 || json.Unmarshal(params[0], &slot) != nil {
+			return nil, invalidParams("expected a slot")
+		}
+		return m.clock.Time(slot).Unix(), nil
+	case "getSignaturesForAddress":
+		return m.getSignaturesForAddress(params)
+	case "getTransaction":
+		return m.getTransaction(params)
+	default:
+		return nil, &rpcError{Code: -32601, Message: "method not found: " + method}
+	}
+}
+
+func invalidParams(msg string) *rpcError {
+	return &rpcError{Code: -32602, Message: "invalid params: " + msg}
+}
+
+// getSignaturesForAddress returns the signatures of one program newest
+// first, honouring limit, before and until like a validator.
+func (m *MockRPC) getSignaturesForAddress(params []json.RawMessage) (interface{}, *rpcError) {
+	var address solana.PublicKey
+	if len(params) < 1 || json.Unmarshal(params[0], &address) != nil {
+		return nil, invalidParams("expected a base58 address")
+	}
+	var opts struct {
+		Limit  int    `json:"limit"`
+		Before string `json:"before"`
+		Until  string `json:"until"`
+	}
+	if len(params) > 1 && json.Unmarshal(params[1], &opts) != nil {
+		return nil, invalidParams("malformed config object")
+	}
+	if opts.Limit <= 0 || opts.Limit > maxSignaturesLimit {
+		opts.Limit = maxSignaturesLimit
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	txs := m.byProgram[address]
+	end := len(txs)
+	if opts.Before != "" {
+		end = indexOf(txs, opts.Before)
+		if end < 0 {
+			return []interface{}{}, nil
+		}
+	}
+
+	out := make([]map[string]interface{}, 0, min(end, opts.Limit))
+	for i := end - 1; i >= 0 && len(out) < opts.Limit; i-- {
+		tx := txs[i]
+		if opts.Until != "" && tx.Signature.String() == opts.Until {
+			break
+		}
+		out = append(out, map[string]interface{}{
+			"signature":          tx.Signature.String(),
+			"slot":               tx.Slot,
+			"err":                nil,
+			"memo":               nil,
+			"blockTime":          tx.BlockTime.Unix(),
+			"confirmationStatus": "confirmed",
+		})
+	}
+	return out, nil
+}
+
+func indexOf(txs []*Tx, signature string) int {
+	for i := len(txs) - 1; i >= 0; i-- {
+		if txs[i].Signature.String() == signature {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *MockRPC) getTransaction(params []json.RawMessage) (interface{}, *rpcError) {
+	var signature string
+	if len(params) < 1 || json.Unmarshal(params[0], &signature) != nil {
+		return nil, invalidParams("expected a base58 signature")
+	}
+	sig, err := solana.SignatureFromBase58(signature)
+	if err != nil {
+		return nil, invalidParams("expected a base58 signature")
+	}
+	var opts struct {
+		Encoding string `json:"encoding"`
+	}
+	if len(params) > 1 && json.Unmarshal(params[1], &opts) != nil {
+		return nil, invalidParams("malformed config object")
+	}
+	if opts.Encoding != "base64" {
+		return nil, invalidParams("only base64 encoding is supported")
+	}
+
+	m.mu.RLock()
+	tx, ok := m.bySig[sig]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, nil
+	}
+
+	return map[string]interface{}{
+		"slot":        tx.Slot,
+		"blockTime":   tx.BlockTime.Unix(),
+		"version":     "legacy",
+		"transaction": []string{base64.StdEncoding.EncodeToString(tx.Raw), "base64"},
+		"meta": map[string]interface{}{
+			"err":               nil,
+			"fee":               5000,
+			"preBalances":       []uint64{},
+			"postBalances":      []uint64{},
+			"innerInstructions": []interface{}{},
+			"preTokenBalances":  []interface{}{},
+			"postTokenBalances": []interface{}{},
+			"logMessages":       tx.Logs,
+			"loadedAddresses":   map[string]interface{}{"writable": []string{}, "readonly": []string{}},
+			"status":            map[string]interface{}{"Ok": nil},
+		},
+	}, nil
+}
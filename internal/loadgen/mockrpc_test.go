@@ -0,0 +1,89 @@
+package loadgen
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func rpcCall(t *testing.T, handler http.Handler, method string, params ...interface{}) json.RawMessage {
+	t.Helper()
+	body, _ := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": method, "params": params})
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body)))
+
+	var resp struct {
+		Result json.RawMessage `json:"result"`
+		Error  *rpcError       `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode %s response: %v", method, err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("%s error = %s", method, resp.Error.Message)
+	}
+	return resp.Result
+}
+
+func TestMockRPC(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	mock := NewMockRPC(Clock{Start: start, StartSlot: 1000}, 4)
+	mock.now = func() time.Time { return start.Add(2 * time.Second) }
+
+	g, err := NewGenerator(testCounterProgram, testStarterProgram, 2, 1)
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+	target := NewMockTarget(g, mock)
+	for i := 0; i < 6; i++ {
+		if err := target.Send(context.Background(), ProgramCounter); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+	if err := target.Send(context.Background(), ProgramStarter); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if got := string(rpcCall(t, mock, "getSlot")); got != "1005" {
+		t.Errorf("getSlot = %s, want 1005", got)
+	}
+
+	// Retaining 4 transactions leaves the last 3 counter transactions.
+	var sigs []struct {
+		Signature string `json:"signature"`
+	}
+	raw := rpcCall(t, mock, "getSignaturesForAddress", testCounterProgram.String(), map[string]interface{}{"limit": 2})
+	if err := json.Unmarshal(raw, &sigs); err != nil || len(sigs) != 2 {
+		t.Fatalf("getSignaturesForAddress = %s, want 2 signatures", raw)
+	}
+	counterTxs := mock.byProgram[testCounterProgram]
+	if want := counterTxs[len(counterTxs)-1].Signature.String(); sigs[0].Signature != want {
+		t.Errorf("first signature = %s, want newest %s", sigs[0].Signature, want)
+	}
+
+	raw = rpcCall(t, mock, "getSignaturesForAddress", testCounterProgram.String(), map[string]interface{}{"before": sigs[1].Signature})
+	if err := json.Unmarshal(raw, &sigs); err != nil || len(sigs) != 1 {
+		t.Errorf("getSignaturesForAddress before = %s, want 1 older signature", raw)
+	}
+
+	var tx struct {
+		Slot        uint64   `json:"slot"`
+		Transaction []string `json:"transaction"`
+		Meta        struct {
+			LogMessages []string `json:"logMessages"`
+		} `json:"meta"`
+	}
+	raw = rpcCall(t, mock, "getTransaction", sigs[0].Signature, map[string]interface{}{"encoding": "base64"})
+	if err := json.Unmarshal(raw, &tx); err != nil {
+		t.Fatalf("decode getTransaction: %v", err)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(tx.Transaction[0])
+	if err != nil || !bytes.Equal(decoded, counterTxs[0].Raw) || len(tx.Meta.LogMessages) == 0 {
+		t.Errorf("getTransaction = %s", raw)
+	}
+}
@@ -0,0 +1,141 @@
+package loadgen
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Program selects which program a transaction is generated for.
+type Program string
+
+const (
+	ProgramCounter Program = "counter"
+	ProgramStarter Program = "starter"
+)
+
+// Target receives generated load.
+type Target interface {
+	Send(ctx context.Context, program Program) error
+}
+
+type Options struct {
+	// CounterRate and StarterRate are transactions per second; zero
+	// disables a program.
+	CounterRate float64
+	StarterRate float64
+	// Duration stops the run after it elapses; zero runs until ctx is done.
+	Duration time.Duration
+	// Workers bounds concurrent sends.
+	Workers        int
+	ReportInterval time.Duration
+}
+
+type Stats struct {
+	Sent    map[Program]int64
+	Failed  map[Program]int64
+	Skipped map[Program]int64
+	Elapsed time.Duration
+}
+
+type counters struct {
+	sent, failed, skipped atomic.Int64
+}
+
+// Run sends transactions to target at the configured rates. When the
+// workers fall behind, due transactions are skipped rather than queued, so
+// Stats shows the rate the target actually sustained.
+func Run(ctx context.Context, target Target, opts Options) Stats {
+	if opts.Workers <= 0 {
+		opts.Workers = 8
+	}
+	if opts.ReportInterval <= 0 {
+		opts.ReportInterval = 10 * time.Second
+	}
+	if opts.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Duration)
+		defer cancel()
+	}
+
+	rates := map[Program]float64{ProgramCounter: opts.CounterRate, ProgramStarter: opts.StarterRate}
+	stats := map[Program]*counters{ProgramCounter: {}, ProgramStarter: {}}
+
+	jobs := make(chan Program, opts.Workers)
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for program := range jobs {
+				if err := target.Send(ctx, program); err != nil {
+					if ctx.Err() == nil {
+						log.Printf("warning: loadgen %s send failed: %v", program, err)
+					}
+					stats[program].failed.Add(1)
+					continue
+				}
+				stats[program].sent.Add(1)
+			}
+		}()
+	}
+
+	start := time.Now()
+	due := map[Program]int64{}
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	report := time.NewTicker(opts.ReportInterval)
+	defer report.Stop()
+
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+		case <-report.C:
+			logProgress(stats, time.Since(start))
+		case now := <-ticker.C:
+			elapsed := now.Sub(start).Seconds()
+			for _, program := range []Program{ProgramCounter, ProgramStarter} {
+				want := int64(elapsed * rates[program])
+				for ; due[program] < want; due[program]++ {
+					select {
+					case jobs <- program:
+					default:
+						stats[program].skipped.Add(1)
+					}
+				}
+			}
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	result := Stats{
+		Sent:    map[Program]int64{},
+		Failed:  map[Program]int64{},
+		Skipped: map[Program]int64{},
+		Elapsed: time.Since(start),
+	}
+	for program, c := range stats {
+		result.Sent[program] = c.sent.Load()
+		result.Failed[program] = c.failed.Load()
+		result.Skipped[program] = c.skipped.Load()
+	}
+	return result
+}
+
+func logProgress(stats map[Program]*counters, elapsed time.Duration) {
+	for _, program := range []Program{ProgramCounter, ProgramStarter} {
+		c := stats[program]
+		sent := c.sent.Load()
+		if sent == 0 && c.failed.Load() == 0 && c.skipped.Load() == 0 {
+			continue
+		}
+		log.Printf("loadgen: %s %.1f tx/s (sent %d, failed %d, skipped %d)",
+			program, float64(sent)/elapsed.Seconds(), sent, c.failed.Load(), c.skipped.Load())
+	}
+}
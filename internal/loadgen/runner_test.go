@@ -0,0 +1,37 @@
+package loadgen
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingTarget struct {
+	counter, starter atomic.Int64
+}
+
+func (t *countingTarget) Send(ctx context.Context, program Program) error {
+	if program == ProgramCounter {
+		t.counter.Add(1)
+	} else {
+		t.starter.Add(1)
+	}
+	return nil
+}
+
+func TestRun_Rates(t *testing.T) {
+	target := &countingTarget{}
+	stats := Run(context.Background(), target, Options{CounterRate: 200, StarterRate: 0, Duration: 500 * time.Millisecond})
+
+	// 100 transactions are due; allow for timer granularity.
+	if got := target.counter.Load(); got < 80 || got > 100 {
+		t.Errorf("counter transactions = %d, want about 100", got)
+	}
+	if got := target.starter.Load(); got != 0 {
+		t.Errorf("starter transactions = %d, want 0", got)
+	}
+	if stats.Sent[ProgramCounter] != target.counter.Load() {
+		t.Errorf("Stats.Sent = %d, want %d", stats.Sent[ProgramCounter], target.counter.Load())
+	}
+}
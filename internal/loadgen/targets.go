@@ -0,0 +1,131 @@
+package loadgen
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// MockTarget generates transactions into a MockRPC ledger, stamped with the
+// mock's clock.
+type MockTarget struct {
+	mu        sync.Mutex
+	generator *Generator
+	rpc       *MockRPC
+}
+
+func NewMockTarget(generator *Generator, mock *MockRPC) *MockTarget {
+	return &MockTarget{generator: generator, rpc: mock}
+}
+
+func (t *MockTarget) Send(ctx context.Context, program Program) error {
+	// Generation and Add share the lock so the ledger stays in slot order.
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.rpc.now()
+	slot := t.rpc.clock.Slot(now)
+	blockTime := t.rpc.clock.Time(slot)
+
+	var tx *Tx
+	var err error
+	switch program {
+	case ProgramCounter:
+		tx, err = t.generator.Counter(slot, blockTime)
+	case ProgramStarter:
+		tx, err = t.generator.Starter(slot, blockTime)
+	default:
+		err = fmt.Errorf("unknown program %q", program)
+	}
+	if err != nil {
+		return err
+	}
+	t.rpc.Add(tx)
+	return nil
+}
+
+// blockhashMaxAge is how long a fetched blockhash is reused. Blockhashes
+// expire after about 60 seconds.
+const blockhashMaxAge = 20 * time.Second
+
+// ValidatorTarget sends real counter increments to a validator, usually
+// solana-test-validator with the counter program deployed. The counter
+// account must already be initialized. Starter instructions need program
+// specific accounts (mints, token accounts), so only counter load is
+// supported.
+type ValidatorTarget struct {
+	client         *rpc.Client
+	payer          solana.PrivateKey
+	counterProgram solana.PublicKey
+	counter        solana.PublicKey
+
+	mu          sync.Mutex
+	blockhash   solana.Hash
+	blockhashAt time.Time
+	seq         uint64
+}
+
+func NewValidatorTarget(rpcURL string, payer solana.PrivateKey, counterProgram, counter solana.PublicKey) *ValidatorTarget {
+	return &ValidatorTarget{
+		client:         rpc.New(rpcURL),
+		payer:          payer,
+		counterProgram: counterProgram,
+		counter:        counter,
+	}
+}
+
+func (t *ValidatorTarget) Send(ctx context.Context, program Program) error {
+	if program != ProgramCounter {
+		return fmt.Errorf("%s load is not supported against a validator", program)
+	}
+
+	blockhash, seq, err := t.nextBlockhash(ctx)
+	if err != nil {
+		return err
+	}
+
+	// A memo with a sequence number keeps increments sent with the same
+	// blockhash from being rejected as duplicates.
+	increment := solana.NewInstruction(t.counterProgram,
+		solana.AccountMetaSlice{solana.Meta(t.counter).WRITE()},
+		instructionDiscriminator("increment"))
+	memo := solana.NewInstruction(solana.MemoProgramID, nil,
+		[]byte(fmt.Sprintf("loadgen %d", seq)))
+
+	tx, err := solana.NewTransaction([]solana.Instruction{increment, memo}, blockhash, solana.TransactionPayer(t.payer.PublicKey()))
+	if err != nil {
+		return fmt.Errorf("build transaction: %w", err)
+	}
+	if _, err := tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+		if key.Equals(t.payer.PublicKey()) {
+			return &t.payer
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("sign transaction: %w", err)
+	}
+
+	if _, err := t.client.SendTransactionWithOpts(ctx, tx, rpc.TransactionOpts{SkipPreflight: true}); err != nil {
+		return fmt.Errorf("send transaction: %w", err)
+	}
+	return nil
+}
+
+func (t *ValidatorTarget) nextBlockhash(ctx context.Context) (solana.Hash, uint64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if time.Since(t.blockhashAt) > blockhashMaxAge {
+		out, err := t.client.GetLatestBlockhash(ctx, rpc.CommitmentFinalized)
+		if err != nil {
+			return solana.Hash{}, 0, fmt.Errorf("get latest blockhash: %w", err)
+		}
+		t.blockhash, t.blockhashAt = out.Value.Blockhash, time.Now()
+	}
+	t.seq++
+	return t.blockhash, t.seq, nil
+}
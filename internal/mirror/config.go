@@ -0,0 +1,205 @@
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/decoder"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+const maxResubscribeBackoff = 30 * time.Second
+
+// ConfigStore persists the ProgramConfig change history.
+type ConfigStore interface {
+	SaveConfigChange(ctx context.Context, change *models.ConfigChange) error
+	GetConfigHistory(ctx context.Context, limit int) ([]models.ConfigChange, error)
+}
+
+// AccountSource reads an account and follows its updates.
+type AccountSource interface {
+	GetAccountData(ctx context.Context, account solana.PublicKey) ([]byte, uint64, error)
+	SubscribeAccount(ctx context.Context, account solana.PublicKey, handler func(slot uint64, data []byte)) error
+}
+
+// ConfigMirror keeps the starter program's ProgramConfig account mirrored in
+// the store. ConfigUpdated and ProgramPaused events are recorded as they are
+// indexed, attributed to their transaction; the account subscription fills
+// in changes that no event explains, such as a new fee destination.
+type ConfigMirror struct {
+	store     ConfigStore
+	source    AccountSource
+	programID solana.PublicKey
+	address   solana.PublicKey
+
+	mu      sync.Mutex
+	loaded  bool
+	current *models.ConfigChange
+}
+
+func NewConfigMirror(store ConfigStore, source AccountSource, programID solana.PublicKey) (*ConfigMirror, error) {
+	address, err := decoder.ProgramConfigAddress(programID)
+	if err != nil {
+		return nil, err
+	}
+	return &ConfigMirror{
+		store:     store,
+		source:    source,
+		programID: programID,
+		address:   address,
+	}, nil
+}
+
+// Run reads the config account once and then follows it until ctx is
+// cancelled, resubscribing with backoff when the subscription drops.
+func (m *ConfigMirror) Run(ctx context.Context) error {
+	data, slot, err := m.source.GetAccountData(ctx, m.address)
+	if err != nil {
+		log.Printf("warning: failed to read program config %s: %v", m.address, err)
+	} else {
+		m.handleAccount(ctx, slot, data)
+	}
+
+	backoff := time.Second
+	for {
+		err := m.source.SubscribeAccount(ctx, m.address, func(slot uint64, data []byte) {
+			m.handleAccount(ctx, slot, data)
+		})
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		log.Printf("warning: program config subscription ended: %v, retrying in %s", err, backoff)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxResubscribeBackoff)
+	}
+}
+
+// ApplyEvent records a decoded ConfigUpdated or ProgramPaused event. Other
+// events are ignored.
+func (m *ConfigMirror) ApplyEvent(ctx context.Context, signature string, slot uint64, blockTime time.Time, event interface{}) error {
+	change := models.ConfigChange{
+		Signature: signature,
+		Slot:      slot,
+		BlockTime: blockTime,
+	}
+
+	var update func(*models.ProgramConfig)
+	switch e := event.(type) {
+	case *models.ConfigUpdatedEvent:
+		change.Source = models.ConfigChangeSourceConfigUpdated
+		if e.OldFee != e.NewFee {
+			change.ChangedFields = []string{"fee_basis_points"}
+		}
+		update = func(c *models.ProgramConfig) { c.FeeBasisPoints = e.NewFee }
+	case *models.ProgramPausedEvent:
+		change.Source = models.ConfigChangeSourceProgramPaused
+		change.ChangedFields = []string{"paused"}
+		update = func(c *models.ProgramConfig) { c.Paused = e.Paused }
+	default:
+		return nil
+	}
+
+	return m.apply(ctx, change, update)
+}
+
+func (m *ConfigMirror) handleAccount(ctx context.Context, slot uint64, data []byte) {
+	config, err := decoder.DecodeProgramConfig(data)
+	if err != nil {
+		log.Printf("warning: failed to decode program config at slot %d: %v", slot, err)
+		return
+	}
+
+	change := models.ConfigChange{
+		Source: models.ConfigChangeSourceAccount,
+		Slot:   slot,
+	}
+	if err := m.apply(ctx, change, func(c *models.ProgramConfig) { *c = *config }); err != nil {
+		log.Printf("warning: failed to mirror program config at slot %d: %v", slot, err)
+	}
+}
+
+// apply records change with the config produced by update. Account
+// snapshots are only recorded when they are newer than and differ from the
+// mirrored state; events are always recorded so every on-chain config action
+// keeps its signature in the history.
+func (m *ConfigMirror) apply(ctx context.Context, change models.ConfigChange, update func(*models.ProgramConfig)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.load(ctx); err != nil {
+		return err
+	}
+
+	var prev models.ProgramConfig
+	if m.current != nil {
+		prev = m.current.Config
+	}
+	next := prev
+	update(&next)
+
+	if change.Source == models.ConfigChangeSourceAccount {
+		change.ChangedFields = diffConfig(prev, next)
+		if m.current != nil && (change.Slot < m.current.Slot || len(change.ChangedFields) == 0) {
+			return nil
+		}
+	}
+	if change.ChangedFields == nil {
+		change.ChangedFields = []string{}
+	}
+
+	change.ProgramID = m.programID
+	change.Config = next
+	change.RecordedAt = time.Now()
+	if err := m.store.SaveConfigChange(ctx, &change); err != nil {
+		return fmt.Errorf("save config change: %w", err)
+	}
+
+	if m.current == nil || change.Slot >= m.current.Slot {
+		m.current = &change
+	}
+	return nil
+}
+
+// load seeds the mirrored state from the latest stored change. The caller
+// must hold m.mu.
+func (m *ConfigMirror) load(ctx context.Context) error {
+	if m.loaded {
+		return nil
+	}
+
+	history, err := m.store.GetConfigHistory(ctx, 1)
+	if err != nil {
+		return fmt.Errorf("load config history: %w", err)
+	}
+	if len(history) > 0 {
+		m.current = &history[0]
+	}
+	m.loaded = true
+	return nil
+}
+
+func diffConfig(prev, next models.ProgramConfig) []string {
+	var fields []string
+	if !prev.Admin.Equals(next.Admin) {
+		fields = append(fields, "admin")
+	}
+	if !prev.FeeDestination.Equals(next.FeeDestination) {
+		fields = append(fields, "fee_destination")
+	}
+	if prev.FeeBasisPoints != next.FeeBasisPoints {
+		fields = append(fields, "fee_basis_points")
+	}
+	if prev.Paused != next.Paused {
+		fields = append(fields, "paused")
+	}
+	return fields
+}
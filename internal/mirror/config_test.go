@@ -0,0 +1,116 @@
+package mirror
+
+import (
+	"context"
+	"encoding/binary"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+type memoryStore struct {
+	changes []models.ConfigChange
+}
+
+func (s *memoryStore) SaveConfigChange(ctx context.Context, change *models.ConfigChange) error {
+	s.changes = append(s.changes, *change)
+	return nil
+}
+
+func (s *memoryStore) GetConfigHistory(ctx context.Context, limit int) ([]models.ConfigChange, error) {
+	var out []models.ConfigChange
+	for i := len(s.changes) - 1; i >= 0 && len(out) < limit; i-- {
+		out = append(out, s.changes[i])
+	}
+	return out, nil
+}
+
+func programConfigData(config models.ProgramConfig) []byte {
+	data := []byte{196, 210, 90, 231, 144, 149, 140, 63}
+	data = append(data, config.Admin[:]...)
+	data = append(data, config.FeeDestination[:]...)
+	data = binary.LittleEndian.AppendUint64(data, config.FeeBasisPoints)
+	if config.Paused {
+		data = append(data, 1)
+	} else {
+		data = append(data, 0)
+	}
+	return append(data, 255)
+}
+
+func TestConfigMirror_History(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryStore{}
+	m, err := NewConfigMirror(store, nil, solana.PublicKey{9})
+	if err != nil {
+		t.Fatalf("NewConfigMirror() error = %v", err)
+	}
+
+	initial := models.ProgramConfig{Admin: solana.PublicKey{1}, FeeDestination: solana.PublicKey{2}, FeeBasisPoints: 100}
+	m.handleAccount(ctx, 10, programConfigData(initial))
+	m.handleAccount(ctx, 11, programConfigData(initial))
+
+	if err := m.ApplyEvent(ctx, "sig-fee", 12, time.Time{}, &models.ConfigUpdatedEvent{OldFee: 100, NewFee: 200}); err != nil {
+		t.Fatalf("ApplyEvent() error = %v", err)
+	}
+
+	// The account catches up with the event, then a stale snapshot arrives.
+	updated := initial
+	updated.FeeBasisPoints = 200
+	m.handleAccount(ctx, 12, programConfigData(updated))
+	m.handleAccount(ctx, 9, programConfigData(models.ProgramConfig{FeeBasisPoints: 50}))
+
+	if err := m.ApplyEvent(ctx, "sig-pause", 13, time.Time{}, &models.ProgramPausedEvent{Paused: true}); err != nil {
+		t.Fatalf("ApplyEvent() error = %v", err)
+	}
+	if err := m.ApplyEvent(ctx, "sig-nft", 14, time.Time{}, &models.NftMintedEvent{}); err != nil {
+		t.Fatalf("ApplyEvent() error = %v", err)
+	}
+
+	moved := updated
+	moved.Paused = true
+	moved.FeeDestination = solana.PublicKey{3}
+	m.handleAccount(ctx, 15, programConfigData(moved))
+
+	want := []struct {
+		source  models.ConfigChangeSource
+		slot    uint64
+		changed []string
+	}{
+		{models.ConfigChangeSourceAccount, 10, []string{"admin", "fee_destination", "fee_basis_points"}},
+		{models.ConfigChangeSourceConfigUpdated, 12, []string{"fee_basis_points"}},
+		{models.ConfigChangeSourceProgramPaused, 13, []string{"paused"}},
+		{models.ConfigChangeSourceAccount, 15, []string{"fee_destination"}},
+	}
+	if len(store.changes) != len(want) {
+		t.Fatalf("got %d changes, want %d: %+v", len(store.changes), len(want), store.changes)
+	}
+	for i, w := range want {
+		got := store.changes[i]
+		if got.Source != w.source || got.Slot != w.slot || !reflect.DeepEqual(got.ChangedFields, w.changed) {
+			t.Errorf("changes[%d] = %s@%d %v, want %s@%d %v", i, got.Source, got.Slot, got.ChangedFields, w.source, w.slot, w.changed)
+		}
+	}
+	if last := store.changes[len(store.changes)-1].Config; last != moved {
+		t.Errorf("mirrored config = %+v, want %+v", last, moved)
+	}
+}
+
+func TestConfigMirror_SeedsFromStore(t *testing.T) {
+	ctx := context.Background()
+	config := models.ProgramConfig{Admin: solana.PublicKey{1}, FeeBasisPoints: 100}
+	store := &memoryStore{changes: []models.ConfigChange{{Source: models.ConfigChangeSourceAccount, Slot: 50, Config: config}}}
+
+	m, err := NewConfigMirror(store, nil, solana.PublicKey{9})
+	if err != nil {
+		t.Fatalf("NewConfigMirror() error = %v", err)
+	}
+
+	m.handleAccount(ctx, 60, programConfigData(config))
+	if len(store.changes) != 1 {
+		t.Errorf("unchanged snapshot after restart was recorded: %+v", store.changes[1:])
+	}
+}
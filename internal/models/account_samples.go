@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// AccountSample is the size and balance of a program account at one time.
+type AccountSample struct {
+	Address  string `bson:"address" json:"address"`
+	Program  string `bson:"program" json:"program"`
+	Size     uint64 `bson:"size" json:"size"`
+	Lamports uint64 `bson:"lamports" json:"lamports"`
+	// RentExemptMinimum is the balance an account of Size bytes needs to be
+	// rent exempt.
+	RentExemptMinimum uint64    `bson:"rent_exempt_minimum" json:"rent_exempt_minimum"`
+	SampledAt         time.Time `bson:"sampled_at" json:"sampled_at"`
+}
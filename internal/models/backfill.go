@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// BackfillStatus is the progress of a backfill started on the running
+// indexer.
+type BackfillStatus struct {
+	FromSlot uint64 `json:"from_slot"`
+	ToSlot   uint64 `json:"to_slot"`
+	// Reindex is set when the events of the range were deleted first.
+	Reindex bool `json:"reindex,omitempty"`
+	Running bool `json:"running"`
+	// Processed is the number of transactions indexed once it finished.
+	Processed  int        `json:"processed"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
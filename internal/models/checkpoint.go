@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Checkpoint is how far the indexer has processed the transactions of a
+// program. Signature and Slot are the newest transaction processed; every
+// older transaction since the start point has been processed as well.
+type Checkpoint struct {
+	// Program is CheckpointID of the program.
+	Program   string `bson:"_id" json:"program"`
+	Signature string `bson:"signature,omitempty" json:"signature,omitempty"`
+	Slot      uint64 `bson:"slot" json:"slot"`
+	// Start is the start strategy the checkpoint was created with, e.g.
+	// "genesis" or "slot 250000000". It is kept when the checkpoint moves.
+	Start     string    `bson:"start" json:"start"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// CheckpointID returns the ID of the checkpoint of program: its address,
+// prefixed with the network and a colon when one is set, so the same
+// program can be indexed on several networks.
+func CheckpointID(network, program string) string {
+	if network == "" {
+		return program
+	}
+	return network + ":" + program
+}
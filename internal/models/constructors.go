@@ -0,0 +1,321 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// The New*Event constructors build event models from decoded program data
+// and reject values no program could have emitted, so a bad decode fails
+// loudly instead of being stored. They leave BaseEvent to the processor.
+
+// MinEventTimestamp is the Solana mainnet-beta genesis time. Earlier event
+// timestamps are decoding errors.
+var MinEventTimestamp = time.Date(2020, 3, 16, 0, 0, 0, 0, time.UTC).Unix()
+
+// MaxClockSkew is how far in the future of the local clock an event
+// timestamp may be.
+const MaxClockSkew = 24 * time.Hour
+
+const (
+	// MaxNftNameLength and MaxNftURILength are the Metaplex metadata limits.
+	MaxNftNameLength = 32
+	MaxNftURILength  = 200
+)
+
+// ValidationError reports an event field with an invalid value.
+type ValidationError struct {
+	Event  EventType
+	Field  string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s %s", e.Event, e.Field, e.Reason)
+}
+
+// eventValidator collects the first invalid field of an event.
+type eventValidator struct {
+	event EventType
+	err   *ValidationError
+}
+
+func (v *eventValidator) fail(field, reason string) {
+	if v.err == nil {
+		v.err = &ValidationError{Event: v.event, Field: field, Reason: reason}
+	}
+}
+
+// address requires key to be set: the zero key is what an account that was
+// never decoded looks like.
+func (v *eventValidator) address(field string, key solana.PublicKey) {
+	if key.IsZero() {
+		v.fail(field, "must be a non-zero address")
+	}
+}
+
+func (v *eventValidator) timestamp(field string, ts int64) {
+	if ts < MinEventTimestamp {
+		v.fail(field, fmt.Sprintf("%d is before the Solana genesis", ts))
+	} else if ts > time.Now().Add(MaxClockSkew).Unix() {
+		v.fail(field, fmt.Sprintf("%d is in the future", ts))
+	}
+}
+
+// text trims s and requires it to be valid UTF-8 of at most limit bytes.
+// Metaplex pads names and URIs with NUL bytes, which are trimmed too.
+func (v *eventValidator) text(field, s string, limit int) string {
+	s = strings.TrimSpace(strings.TrimRight(s, "\x00"))
+	if !utf8.ValidString(s) {
+		v.fail(field, "must be valid UTF-8")
+	} else if len(s) > limit {
+		v.fail(field, fmt.Sprintf("must be at most %d bytes", limit))
+	}
+	return s
+}
+
+func (v *eventValidator) check(field string, ok bool, reason string) {
+	if !ok {
+		v.fail(field, reason)
+	}
+}
+
+func (v *eventValidator) result() error {
+	if v.err == nil {
+		return nil
+	}
+	return v.err
+}
+
+func NewTokensMintedEvent(mint, recipient solana.PublicKey, amount uint64, timestamp int64) (*TokensMintedEvent, error) {
+	v := eventValidator{event: EventTypeTokensMinted}
+	v.address("mint", mint)
+	v.address("recipient", recipient)
+	v.timestamp("timestamp", timestamp)
+	if err := v.result(); err != nil {
+		return nil, err
+	}
+	return &TokensMintedEvent{Mint: mint, Recipient: recipient, Amount: amount, Timestamp: timestamp}, nil
+}
+
+func NewTokensTransferredEvent(mint, from, to solana.PublicKey, amount uint64, timestamp int64) (*TokensTransferredEvent, error) {
+	v := eventValidator{event: EventTypeTokensTransferred}
+	v.address("mint", mint)
+	v.address("from", from)
+	v.address("to", to)
+	v.timestamp("timestamp", timestamp)
+	if err := v.result(); err != nil {
+		return nil, err
+	}
+	return &TokensTransferredEvent{Mint: mint, From: from, To: to, Amount: amount, Timestamp: timestamp}, nil
+}
+
+func NewTokensBurnedEvent(mint, owner solana.PublicKey, amount uint64, timestamp int64) (*TokensBurnedEvent, error) {
+	v := eventValidator{event: EventTypeTokensBurned}
+	v.address("mint", mint)
+	v.address("owner", owner)
+	v.timestamp("timestamp", timestamp)
+	if err := v.result(); err != nil {
+		return nil, err
+	}
+	return &TokensBurnedEvent{Mint: mint, Owner: owner, Amount: amount, Timestamp: timestamp}, nil
+}
+
+func NewUserAccountCreatedEvent(user, authority solana.PublicKey, timestamp int64) (*UserAccountCreatedEvent, error) {
+	v := eventValidator{event: EventTypeUserAccountCreated}
+	v.address("user", user)
+	v.address("authority", authority)
+	v.timestamp("timestamp", timestamp)
+	if err := v.result(); err != nil {
+		return nil, err
+	}
+	return &UserAccountCreatedEvent{User: user, Authority: authority, Timestamp: timestamp}, nil
+}
+
+func NewUserAccountUpdatedEvent(user solana.PublicKey, oldPoints, newPoints uint64, timestamp int64) (*UserAccountUpdatedEvent, error) {
+	v := eventValidator{event: EventTypeUserAccountUpdated}
+	v.address("user", user)
+	v.timestamp("timestamp", timestamp)
+	if err := v.result(); err != nil {
+		return nil, err
+	}
+	return &UserAccountUpdatedEvent{User: user, OldPoints: oldPoints, NewPoints: newPoints, Timestamp: timestamp}, nil
+}
+
+func NewConfigUpdatedEvent(admin solana.PublicKey, oldFee, newFee uint64, timestamp int64) (*ConfigUpdatedEvent, error) {
+	v := eventValidator{event: EventTypeConfigUpdated}
+	v.address("admin", admin)
+	v.timestamp("timestamp", timestamp)
+	if err := v.result(); err != nil {
+		return nil, err
+	}
+	return &ConfigUpdatedEvent{Admin: admin, OldFee: oldFee, NewFee: newFee, Timestamp: timestamp}, nil
+}
+
+func NewProgramPausedEvent(admin solana.PublicKey, paused bool, timestamp int64) (*ProgramPausedEvent, error) {
+	v := eventValidator{event: EventTypeProgramPaused}
+	v.address("admin", admin)
+	v.timestamp("timestamp", timestamp)
+	if err := v.result(); err != nil {
+		return nil, err
+	}
+	return &ProgramPausedEvent{Admin: admin, Paused: paused, Timestamp: timestamp}, nil
+}
+
+// NewNftMintedEvent trims name and uri. collection is the zero key for an
+// NFT outside any collection.
+func NewNftMintedEvent(nftMint, collection, owner solana.PublicKey, name, uri string, timestamp int64) (*NftMintedEvent, error) {
+	v := eventValidator{event: EventTypeNftMinted}
+	v.address("nft_mint", nftMint)
+	v.address("owner", owner)
+	name = v.text("name", name, MaxNftNameLength)
+	uri = v.text("uri", uri, MaxNftURILength)
+	v.timestamp("timestamp", timestamp)
+	if err := v.result(); err != nil {
+		return nil, err
+	}
+	return &NftMintedEvent{NftMint: nftMint, Collection: collection, Owner: owner, Name: name, Uri: uri, Timestamp: timestamp}, nil
+}
+
+func NewNftSoldEvent(nftMint, seller, buyer solana.PublicKey, price uint64, timestamp int64) (*NftSoldEvent, error) {
+	v := eventValidator{event: EventTypeNftSold}
+	v.address("nft_mint", nftMint)
+	v.address("seller", seller)
+	v.address("buyer", buyer)
+	v.timestamp("timestamp", timestamp)
+	if err := v.result(); err != nil {
+		return nil, err
+	}
+	return &NftSoldEvent{NftMint: nftMint, Seller: seller, Buyer: buyer, Price: price, PriceSol: FormatSol(price), Timestamp: timestamp}, nil
+}
+
+// NewCounterInitializedEvent accepts a zero authority: the counter program
+// logs do not always identify it.
+func NewCounterInitializedEvent(counter, authority solana.PublicKey, initialCount uint64) (*CounterInitializedEvent, error) {
+	v := eventValidator{event: EventTypeCounterInitialized}
+	v.address("counter", counter)
+	if err := v.result(); err != nil {
+		return nil, err
+	}
+	return &CounterInitializedEvent{Counter: counter, Authority: authority, InitialCount: initialCount}, nil
+}
+
+func NewCounterIncrementedEvent(counter solana.PublicKey, oldValue, newValue uint64) (*CounterIncrementedEvent, error) {
+	v := eventValidator{event: EventTypeCounterIncremented}
+	v.address("counter", counter)
+	v.check("new_value", newValue > oldValue && newValue-oldValue == 1, fmt.Sprintf("%d does not follow old_value %d", newValue, oldValue))
+	if err := v.result(); err != nil {
+		return nil, err
+	}
+	return &CounterIncrementedEvent{Counter: counter, OldValue: oldValue, NewValue: newValue}, nil
+}
+
+func NewCounterDecrementedEvent(counter solana.PublicKey, oldValue, newValue uint64) (*CounterDecrementedEvent, error) {
+	v := eventValidator{event: EventTypeCounterDecremented}
+	v.address("counter", counter)
+	v.check("new_value", oldValue > newValue && oldValue-newValue == 1, fmt.Sprintf("%d does not precede old_value %d", newValue, oldValue))
+	if err := v.result(); err != nil {
+		return nil, err
+	}
+	return &CounterDecrementedEvent{Counter: counter, OldValue: oldValue, NewValue: newValue}, nil
+}
+
+func NewCounterAddedEvent(counter solana.PublicKey, oldValue, addedValue, newValue uint64) (*CounterAddedEvent, error) {
+	v := eventValidator{event: EventTypeCounterAdded}
+	v.address("counter", counter)
+	v.check("new_value", newValue >= addedValue && newValue-addedValue == oldValue, fmt.Sprintf("%d is not old_value %d plus added_value %d", newValue, oldValue, addedValue))
+	if err := v.result(); err != nil {
+		return nil, err
+	}
+	return &CounterAddedEvent{Counter: counter, OldValue: oldValue, AddedValue: addedValue, NewValue: newValue}, nil
+}
+
+// NewCounterResetEvent accepts a zero authority, like
+// NewCounterInitializedEvent.
+func NewCounterResetEvent(counter, authority solana.PublicKey, oldValue uint64) (*CounterResetEvent, error) {
+	v := eventValidator{event: EventTypeCounterReset}
+	v.address("counter", counter)
+	if err := v.result(); err != nil {
+		return nil, err
+	}
+	return &CounterResetEvent{Counter: counter, Authority: authority, OldValue: oldValue}, nil
+}
+
+// NewCounterPaymentReceivedEvent accepts a zero payer and fee collector when
+// the transaction's accounts could not be attributed.
+func NewCounterPaymentReceivedEvent(counter, payer, feeCollector solana.PublicKey, payment, newCount uint64) (*CounterPaymentReceivedEvent, error) {
+	v := eventValidator{event: EventTypeCounterPaymentReceived}
+	v.address("counter", counter)
+	if err := v.result(); err != nil {
+		return nil, err
+	}
+	return &CounterPaymentReceivedEvent{
+		Counter:      counter,
+		Payer:        payer,
+		FeeCollector: feeCollector,
+		Payment:      payment,
+		PaymentSol:   FormatSol(payment),
+		NewCount:     newCount,
+	}, nil
+}
+
+func NewSplTokenTransferEvent(mint, source, destination, authority solana.PublicKey, amount uint64) (*SplTokenTransferEvent, error) {
+	v := eventValidator{event: EventTypeSplTokenTransfer}
+	v.address("mint", mint)
+	v.address("source", source)
+	v.address("destination", destination)
+	v.address("authority", authority)
+	if err := v.result(); err != nil {
+		return nil, err
+	}
+	return &SplTokenTransferEvent{Mint: mint, Source: source, Destination: destination, Authority: authority, Amount: amount}, nil
+}
+
+func NewSplTokenMintToEvent(mint, account, authority solana.PublicKey, amount uint64) (*SplTokenMintToEvent, error) {
+	v := eventValidator{event: EventTypeSplTokenMintTo}
+	v.address("mint", mint)
+	v.address("account", account)
+	v.address("authority", authority)
+	if err := v.result(); err != nil {
+		return nil, err
+	}
+	return &SplTokenMintToEvent{Mint: mint, Account: account, Authority: authority, Amount: amount}, nil
+}
+
+func NewSplTokenBurnEvent(mint, account, authority solana.PublicKey, amount uint64) (*SplTokenBurnEvent, error) {
+	v := eventValidator{event: EventTypeSplTokenBurn}
+	v.address("mint", mint)
+	v.address("account", account)
+	v.address("authority", authority)
+	if err := v.result(); err != nil {
+		return nil, err
+	}
+	return &SplTokenBurnEvent{Mint: mint, Account: account, Authority: authority, Amount: amount}, nil
+}
+
+func NewSplTokenAccountInitializedEvent(mint, account, owner solana.PublicKey) (*SplTokenAccountInitializedEvent, error) {
+	v := eventValidator{event: EventTypeSplTokenAccountInitialized}
+	v.address("mint", mint)
+	v.address("account", account)
+	v.address("owner", owner)
+	if err := v.result(); err != nil {
+		return nil, err
+	}
+	return &SplTokenAccountInitializedEvent{Mint: mint, Account: account, Owner: owner}, nil
+}
+
+// NewSolTransferEvent requires at least one side of the transfer; a balance
+// change leaves the other one zero.
+func NewSolTransferEvent(from, to solana.PublicKey, lamports uint64, source string) (*SolTransferEvent, error) {
+	v := eventValidator{event: EventTypeSolTransfer}
+	v.check("from", !from.IsZero() || !to.IsZero(), "and to must not both be zero")
+	v.check("source", source == SolTransferInstruction || source == SolTransferBalance, fmt.Sprintf("%q is unknown", source))
+	if err := v.result(); err != nil {
+		return nil, err
+	}
+	return &SolTransferEvent{From: from, To: to, Lamports: lamports, LamportsSol: FormatSol(lamports), Source: source}, nil
+}
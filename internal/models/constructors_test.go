@@ -0,0 +1,153 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+var (
+	testMint   = solana.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112")
+	testWallet = solana.MustPublicKeyFromBase58("9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM")
+)
+
+func TestNewEvent_Validation(t *testing.T) {
+	now := time.Now().Unix()
+	tests := []struct {
+		name      string
+		build     func() error
+		wantField string
+	}{
+		{
+			name: "valid mint",
+			build: func() error {
+				_, err := NewTokensMintedEvent(testMint, testWallet, 100, now)
+				return err
+			},
+		},
+		{
+			name: "zero recipient",
+			build: func() error {
+				_, err := NewTokensMintedEvent(testMint, solana.PublicKey{}, 100, now)
+				return err
+			},
+			wantField: "recipient",
+		},
+		{
+			name: "timestamp before genesis",
+			build: func() error {
+				_, err := NewTokensBurnedEvent(testMint, testWallet, 1, 1000)
+				return err
+			},
+			wantField: "timestamp",
+		},
+		{
+			name: "timestamp in the future",
+			build: func() error {
+				_, err := NewUserAccountCreatedEvent(testWallet, testWallet, now+int64(2*MaxClockSkew/time.Second))
+				return err
+			},
+			wantField: "timestamp",
+		},
+		{
+			name: "nft name too long",
+			build: func() error {
+				_, err := NewNftMintedEvent(testMint, solana.PublicKey{}, testWallet, strings.Repeat("x", MaxNftNameLength+1), "https://example.com", now)
+				return err
+			},
+			wantField: "name",
+		},
+		{
+			name: "nft uri not utf-8",
+			build: func() error {
+				_, err := NewNftMintedEvent(testMint, solana.PublicKey{}, testWallet, "Token", "\xff", now)
+				return err
+			},
+			wantField: "uri",
+		},
+		{
+			name: "increment skipping a value",
+			build: func() error {
+				_, err := NewCounterIncrementedEvent(testMint, 1, 3)
+				return err
+			},
+			wantField: "new_value",
+		},
+		{
+			name: "decrement below zero",
+			build: func() error {
+				_, err := NewCounterDecrementedEvent(testMint, 0, 18446744073709551615)
+				return err
+			},
+			wantField: "new_value",
+		},
+		{
+			name: "add overflowing",
+			build: func() error {
+				_, err := NewCounterAddedEvent(testMint, 18446744073709551615, 2, 1)
+				return err
+			},
+			wantField: "new_value",
+		},
+		{
+			name: "reset without authority",
+			build: func() error {
+				_, err := NewCounterResetEvent(testMint, solana.PublicKey{}, 5)
+				return err
+			},
+		},
+		{
+			name: "payment without counter",
+			build: func() error {
+				_, err := NewCounterPaymentReceivedEvent(solana.PublicKey{}, testWallet, testWallet, 10, 1)
+				return err
+			},
+			wantField: "counter",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.build()
+			if tt.wantField == "" {
+				if err != nil {
+					t.Errorf("error = %v, want nil", err)
+				}
+				return
+			}
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("error = %v, want a ValidationError", err)
+			}
+			if verr.Field != tt.wantField {
+				t.Errorf("Field = %q, want %q", verr.Field, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestNewNftMintedEvent_Normalizes(t *testing.T) {
+	event, err := NewNftMintedEvent(testMint, solana.PublicKey{}, testWallet, "Starter #1\x00\x00\x00", " https://example.com/1.json ", time.Now().Unix())
+	if err != nil {
+		t.Fatalf("NewNftMintedEvent() error = %v", err)
+	}
+	if event.Name != "Starter #1" {
+		t.Errorf("Name = %q, want %q", event.Name, "Starter #1")
+	}
+	if event.Uri != "https://example.com/1.json" {
+		t.Errorf("Uri = %q, want %q", event.Uri, "https://example.com/1.json")
+	}
+}
+
+func TestNewNftSoldEvent_SetsPriceSol(t *testing.T) {
+	event, err := NewNftSoldEvent(testMint, testWallet, solana.MustPublicKeyFromBase58("4Nd1mBQtrMJVYVfKf2PJy9NZUZdTAsp7D4xWLs4gDB4T"), 1_500_000_000, time.Now().Unix())
+	if err != nil {
+		t.Fatalf("NewNftSoldEvent() error = %v", err)
+	}
+	if event.PriceSol != "1.500000000" {
+		t.Errorf("PriceSol = %q, want %q", event.PriceSol, "1.500000000")
+	}
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// DecoderCoverage counts the "Program data:" payloads found in processed
+// transactions by how they decoded. Found is the sum of Decoded, Unknown
+// (not an event the decoder knows) and Failed (a known event or a payload
+// that could not be decoded).
+type DecoderCoverage struct {
+	Transactions uint64 `bson:"transactions" json:"transactions"`
+	Found        uint64 `bson:"found" json:"found"`
+	Decoded      uint64 `bson:"decoded" json:"decoded"`
+	Unknown      uint64 `bson:"unknown" json:"unknown"`
+	Failed       uint64 `bson:"failed" json:"failed"`
+}
+
+func (c *DecoderCoverage) Add(other DecoderCoverage) {
+	c.Transactions += other.Transactions
+	c.Found += other.Found
+	c.Decoded += other.Decoded
+	c.Unknown += other.Unknown
+	c.Failed += other.Failed
+}
+
+// Ratio is the share of the payloads found that decoded, 1 when none were
+// found.
+func (c DecoderCoverage) Ratio() float64 {
+	if c.Found == 0 {
+		return 1
+	}
+	return float64(c.Decoded) / float64(c.Found)
+}
+
+// DailyDecoderCoverage is the decoder coverage of one UTC day, formatted
+// as 2006-01-02.
+type DailyDecoderCoverage struct {
+	Day             string `bson:"_id" json:"day"`
+	DecoderCoverage `bson:",inline"`
+	UpdatedAt       time.Time `bson:"updated_at" json:"updated_at"`
+}
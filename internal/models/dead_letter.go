@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// DeadLetter records a transaction the pipeline failed on, with the kind
+// and message of the last error. Repeated failures of the same transaction
+// update one record.
+type DeadLetter struct {
+	Signature     string    `bson:"_id" json:"signature"`
+	Program       string    `bson:"program" json:"program"`
+	Slot          uint64    `bson:"slot" json:"slot"`
+	Kind          string    `bson:"kind" json:"kind"`
+	Error         string    `bson:"error" json:"error"`
+	Attempts      int       `bson:"attempts" json:"attempts"`
+	FirstFailedAt time.Time `bson:"first_failed_at" json:"first_failed_at"`
+	LastFailedAt  time.Time `bson:"last_failed_at" json:"last_failed_at"`
+}
@@ -0,0 +1,12 @@
+package models
+
+// DecodedEvent is an event decoded on request rather than indexed. Event
+// is the event model as it would be stored; Error says why a payload could
+// not be decoded.
+type DecodedEvent struct {
+	ProgramID  string      `json:"program_id"`
+	Deployment string      `json:"deployment,omitempty"`
+	EventType  EventType   `json:"event_type,omitempty"`
+	Event      interface{} `json:"event,omitempty"`
+	Error      string      `json:"error,omitempty"`
+}
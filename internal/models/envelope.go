@@ -0,0 +1,46 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Envelope is an indexed event as the processor hands it to the sinks and
+// the outbox: its base fields for routing, its type and Payload, the JSON
+// of its model. Every output sends Payload as it is, so an event is
+// serialized once and the same way everywhere.
+type Envelope struct {
+	Base    BaseEvent       `json:"base"`
+	Type    EventType       `json:"type"`
+	Payload json.RawMessage `json:"payload"`
+
+	// event is the model Payload was marshaled from; nil once the envelope
+	// was read back, e.g. from the outbox.
+	event Event
+}
+
+// NewEnvelope marshals event, whose base is base.
+func NewEnvelope(base BaseEvent, event Event) (Envelope, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return Envelope{}, fmt.Errorf("marshal %s: %w", base.EventType, err)
+	}
+	return Envelope{Base: base, Type: base.EventType, Payload: payload, event: event}, nil
+}
+
+// Event returns the model of the event, decoding Payload if the envelope
+// was not made by NewEnvelope. Types without a model of their own are the
+// events of log grammars and decode into a *LogEvent.
+func (e Envelope) Event() (Event, error) {
+	if e.event != nil {
+		return e.event, nil
+	}
+	event, ok := NewEventModel(e.Type)
+	if !ok {
+		event = &LogEvent{}
+	}
+	if err := json.Unmarshal(e.Payload, event); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", e.Type, err)
+	}
+	return event, nil
+}
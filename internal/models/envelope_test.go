@@ -0,0 +1,47 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEnvelope_Event(t *testing.T) {
+	event := &TokensMintedEvent{BaseEvent: BaseEvent{EventType: EventTypeTokensMinted, Signature: "sig"}, Amount: 7}
+	e, err := NewEnvelope(event.BaseEvent, event)
+	if err != nil {
+		t.Fatalf("NewEnvelope() error = %v", err)
+	}
+	if got, _ := e.Event(); got != Event(event) {
+		t.Errorf("Event() = %v, want the model the envelope was made from", got)
+	}
+
+	// Read back, e.g. from the outbox, the payload is decoded.
+	read := Envelope{Base: e.Base, Type: e.Type, Payload: e.Payload}
+	got, err := read.Event()
+	if err != nil {
+		t.Fatalf("Event() error = %v", err)
+	}
+	minted, ok := got.(*TokensMintedEvent)
+	if !ok || minted.Amount != 7 || minted.Signature != "sig" {
+		t.Errorf("Event() = %+v, want the decoded TokensMintedEvent", got)
+	}
+}
+
+func TestEnvelope_EventLogGrammar(t *testing.T) {
+	e := Envelope{Type: "swap_executed", Payload: json.RawMessage(`{"event_type":"swap_executed","fields":{"amount":9007199254740993}}`)}
+	got, err := e.Event()
+	if err != nil {
+		t.Fatalf("Event() error = %v", err)
+	}
+	logEvent, ok := got.(*LogEvent)
+	if !ok {
+		t.Fatalf("Event() = %T, want *LogEvent", got)
+	}
+	if amount := logEvent.Fields["amount"]; amount != int64(9007199254740993) {
+		t.Errorf("Fields[amount] = %v (%T), want 9007199254740993", amount, amount)
+	}
+
+	if _, err := (Envelope{Type: EventTypeTokensMinted, Payload: json.RawMessage(`{`)}).Event(); err == nil {
+		t.Error("Event() error = nil, want the decode error of a truncated payload")
+	}
+}
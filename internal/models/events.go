@@ -1,6 +1,8 @@
 package models
 
 import (
+	"maps"
+	"slices"
 	"time"
 
 	"github.com/gagliardetto/solana-go"
@@ -36,8 +38,118 @@ const (
 	EventTypeCounterAdded           EventType = "CounterAddedEvent"
 	EventTypeCounterReset           EventType = "CounterResetEvent"
 	EventTypeCounterPaymentReceived EventType = "CounterPaymentReceivedEvent"
+
+	EventTypeSplTokenTransfer           EventType = "SplTokenTransferEvent"
+	EventTypeSplTokenMintTo             EventType = "SplTokenMintToEvent"
+	EventTypeSplTokenBurn               EventType = "SplTokenBurnEvent"
+	EventTypeSplTokenAccountInitialized EventType = "SplTokenAccountInitializedEvent"
+
+	EventTypeSolTransfer        EventType = "SolTransferEvent"
+	EventTypeWatchedTransaction EventType = "WatchedTransactionEvent"
+
+	EventTypeProgramUpgraded EventType = "ProgramUpgradedEvent"
 )
 
+var knownEventTypes = map[EventType]bool{
+	EventTypeTokensMinted:           true,
+	EventTypeTokensTransferred:      true,
+	EventTypeTokensBurned:           true,
+	EventTypeDelegateApproved:       true,
+	EventTypeDelegateRevoked:        true,
+	EventTypeTokenAccountClosed:     true,
+	EventTypeTokenAccountFrozen:     true,
+	EventTypeTokenAccountThawed:     true,
+	EventTypeUserAccountCreated:     true,
+	EventTypeUserAccountUpdated:     true,
+	EventTypeUserAccountClosed:      true,
+	EventTypeConfigUpdated:          true,
+	EventTypeProgramPaused:          true,
+	EventTypeNftCollectionCreated:   true,
+	EventTypeNftMinted:              true,
+	EventTypeNftListed:              true,
+	EventTypeNftSold:                true,
+	EventTypeNftListingCancelled:    true,
+	EventTypeNftOfferCreated:        true,
+	EventTypeNftOfferAccepted:       true,
+	EventTypeCounterInitialized:     true,
+	EventTypeCounterIncremented:     true,
+	EventTypeCounterDecremented:     true,
+	EventTypeCounterAdded:           true,
+	EventTypeCounterReset:           true,
+	EventTypeCounterPaymentReceived: true,
+
+	EventTypeSplTokenTransfer:           true,
+	EventTypeSplTokenMintTo:             true,
+	EventTypeSplTokenBurn:               true,
+	EventTypeSplTokenAccountInitialized: true,
+
+	EventTypeSolTransfer:        true,
+	EventTypeWatchedTransaction: true,
+
+	EventTypeProgramUpgraded: true,
+}
+
+// Known reports whether t is one of the event types above.
+func (t EventType) Known() bool {
+	return knownEventTypes[t]
+}
+
+// EventTypes returns the known event types, sorted.
+func EventTypes() []EventType {
+	return slices.Sorted(maps.Keys(knownEventTypes))
+}
+
+// NewEventModel returns an empty model of an event type the processor
+// saves, to decode a stored or buffered event into.
+func NewEventModel(eventType EventType) (Event, bool) {
+	switch eventType {
+	case EventTypeTokensMinted:
+		return &TokensMintedEvent{}, true
+	case EventTypeTokensTransferred:
+		return &TokensTransferredEvent{}, true
+	case EventTypeTokensBurned:
+		return &TokensBurnedEvent{}, true
+	case EventTypeUserAccountCreated:
+		return &UserAccountCreatedEvent{}, true
+	case EventTypeUserAccountUpdated:
+		return &UserAccountUpdatedEvent{}, true
+	case EventTypeConfigUpdated:
+		return &ConfigUpdatedEvent{}, true
+	case EventTypeNftMinted:
+		return &NftMintedEvent{}, true
+	case EventTypeNftSold:
+		return &NftSoldEvent{}, true
+	case EventTypeCounterInitialized:
+		return &CounterInitializedEvent{}, true
+	case EventTypeCounterIncremented:
+		return &CounterIncrementedEvent{}, true
+	case EventTypeCounterDecremented:
+		return &CounterDecrementedEvent{}, true
+	case EventTypeCounterAdded:
+		return &CounterAddedEvent{}, true
+	case EventTypeCounterReset:
+		return &CounterResetEvent{}, true
+	case EventTypeCounterPaymentReceived:
+		return &CounterPaymentReceivedEvent{}, true
+	case EventTypeSplTokenTransfer:
+		return &SplTokenTransferEvent{}, true
+	case EventTypeSplTokenMintTo:
+		return &SplTokenMintToEvent{}, true
+	case EventTypeSplTokenBurn:
+		return &SplTokenBurnEvent{}, true
+	case EventTypeSplTokenAccountInitialized:
+		return &SplTokenAccountInitializedEvent{}, true
+	case EventTypeSolTransfer:
+		return &SolTransferEvent{}, true
+	case EventTypeWatchedTransaction:
+		return &WatchedTransactionEvent{}, true
+	case EventTypeProgramUpgraded:
+		return &ProgramUpgradedEvent{}, true
+	default:
+		return nil, false
+	}
+}
+
 type BaseEvent struct {
 	ID        string           `bson:"_id,omitempty" json:"id,omitempty"`
 	EventType EventType        `bson:"event_type" json:"event_type"`
@@ -47,6 +159,40 @@ type BaseEvent struct {
 	ProgramID solana.PublicKey `bson:"program_id" json:"program_id"`
 	CreatedAt time.Time        `bson:"created_at" json:"created_at"`
 	RawData   []byte           `bson:"raw_data,omitempty" json:"raw_data,omitempty"`
+	// RawDataEncoding is the compression of the stored RawData, e.g.
+	// "zstd"; empty when it is stored as it is.
+	RawDataEncoding string `bson:"raw_data_encoding,omitempty" json:"raw_data_encoding,omitempty"`
+	// IndexerVersion identifies the indexer build that decoded the event.
+	IndexerVersion string `bson:"indexer_version,omitempty" json:"indexer_version,omitempty"`
+	// Identities maps wallet addresses in the event to resolved domain
+	// names such as "bonfida.sol".
+	Identities map[string]string `bson:"identities,omitempty" json:"identities,omitempty"`
+	// Tenant is the team owning the program that emitted the event; empty
+	// when tenants are not configured.
+	Tenant string `bson:"tenant,omitempty" json:"tenant,omitempty"`
+	// Deployment labels the counter program deployment that emitted the
+	// event when several are indexed.
+	Deployment string `bson:"deployment,omitempty" json:"deployment,omitempty"`
+	// Network is the network the event was indexed from when several are,
+	// e.g. "devnet" or "mainnet".
+	Network string `bson:"network,omitempty" json:"network,omitempty"`
+	// ContentHash identifies the event's content when duplicates are
+	// collapsed; DuplicateSignatures are the later transactions that
+	// emitted the same content.
+	ContentHash         string   `bson:"content_hash,omitempty" json:"content_hash,omitempty"`
+	DuplicateSignatures []string `bson:"duplicate_signatures,omitempty" json:"duplicate_signatures,omitempty"`
+	// Sequence numbers the events of a program on a network from 1 in the
+	// order they were stored, for change consumers to resume from.
+	Sequence uint64 `bson:"sequence,omitempty" json:"sequence,omitempty"`
+}
+
+// Event is implemented by every event model through its embedded BaseEvent.
+type Event interface {
+	Base() *BaseEvent
+}
+
+func (e *BaseEvent) Base() *BaseEvent {
+	return e
 }
 
 type TokensMintedEvent struct {
@@ -97,6 +243,13 @@ type ConfigUpdatedEvent struct {
 	Timestamp int64            `bson:"timestamp" json:"timestamp"`
 }
 
+type ProgramPausedEvent struct {
+	BaseEvent `bson:",inline"`
+	Admin     solana.PublicKey `bson:"admin" json:"admin"`
+	Paused    bool             `bson:"paused" json:"paused"`
+	Timestamp int64            `bson:"timestamp" json:"timestamp"`
+}
+
 type NftMintedEvent struct {
 	BaseEvent  `bson:",inline"`
 	NftMint    solana.PublicKey `bson:"nft_mint" json:"nft_mint"`
@@ -107,6 +260,16 @@ type NftMintedEvent struct {
 	Timestamp  int64            `bson:"timestamp" json:"timestamp"`
 }
 
+type NftSoldEvent struct {
+	BaseEvent `bson:",inline"`
+	NftMint   solana.PublicKey `bson:"nft_mint" json:"nft_mint"`
+	Seller    solana.PublicKey `bson:"seller" json:"seller"`
+	Buyer     solana.PublicKey `bson:"buyer" json:"buyer"`
+	Price     uint64           `bson:"price" json:"price"`
+	PriceSol  string           `bson:"price_sol,omitempty" json:"price_sol"`
+	Timestamp int64            `bson:"timestamp" json:"timestamp"`
+}
+
 type CounterInitializedEvent struct {
 	BaseEvent    `bson:",inline"`
 	Counter      solana.PublicKey `bson:"counter" json:"counter"`
@@ -149,5 +312,100 @@ type CounterPaymentReceivedEvent struct {
 	Payer        solana.PublicKey `bson:"payer" json:"payer"`
 	FeeCollector solana.PublicKey `bson:"fee_collector" json:"fee_collector"`
 	Payment      uint64           `bson:"payment" json:"payment"`
+	PaymentSol   string           `bson:"payment_sol,omitempty" json:"payment_sol"`
 	NewCount     uint64           `bson:"new_count" json:"new_count"`
 }
+
+// SplTokenTransferEvent and the other SplToken events come from SPL Token
+// and Token-2022 instructions. Source, Destination and Account are token
+// accounts; their owners are taken from the transaction's token balances
+// and are zero when it has none for the account.
+type SplTokenTransferEvent struct {
+	BaseEvent        `bson:",inline"`
+	Mint             solana.PublicKey `bson:"mint" json:"mint"`
+	Source           solana.PublicKey `bson:"source" json:"source"`
+	Destination      solana.PublicKey `bson:"destination" json:"destination"`
+	Authority        solana.PublicKey `bson:"authority" json:"authority"`
+	SourceOwner      solana.PublicKey `bson:"source_owner" json:"source_owner"`
+	DestinationOwner solana.PublicKey `bson:"destination_owner" json:"destination_owner"`
+	Amount           uint64           `bson:"amount" json:"amount"`
+	Decimals         uint8            `bson:"decimals" json:"decimals"`
+}
+
+type SplTokenMintToEvent struct {
+	BaseEvent `bson:",inline"`
+	Mint      solana.PublicKey `bson:"mint" json:"mint"`
+	Account   solana.PublicKey `bson:"account" json:"account"`
+	Authority solana.PublicKey `bson:"authority" json:"authority"`
+	Owner     solana.PublicKey `bson:"owner" json:"owner"`
+	Amount    uint64           `bson:"amount" json:"amount"`
+	Decimals  uint8            `bson:"decimals" json:"decimals"`
+}
+
+type SplTokenBurnEvent struct {
+	BaseEvent `bson:",inline"`
+	Mint      solana.PublicKey `bson:"mint" json:"mint"`
+	Account   solana.PublicKey `bson:"account" json:"account"`
+	Authority solana.PublicKey `bson:"authority" json:"authority"`
+	Owner     solana.PublicKey `bson:"owner" json:"owner"`
+	Amount    uint64           `bson:"amount" json:"amount"`
+	Decimals  uint8            `bson:"decimals" json:"decimals"`
+}
+
+type SplTokenAccountInitializedEvent struct {
+	BaseEvent `bson:",inline"`
+	Mint      solana.PublicKey `bson:"mint" json:"mint"`
+	Account   solana.PublicKey `bson:"account" json:"account"`
+	Owner     solana.PublicKey `bson:"owner" json:"owner"`
+}
+
+// SolTransfer sources: a System Program transfer instruction, or the change
+// of a watched address's balance that no such instruction explains, such as
+// lamports moved by another program or an account closed into it.
+const (
+	SolTransferInstruction = "instruction"
+	SolTransferBalance     = "balance"
+)
+
+// SolTransferEvent is a native SOL transfer involving a watched address.
+// For a balance change the other side is unknown and left zero.
+type SolTransferEvent struct {
+	BaseEvent   `bson:",inline"`
+	From        solana.PublicKey `bson:"from" json:"from"`
+	To          solana.PublicKey `bson:"to" json:"to"`
+	Lamports    uint64           `bson:"lamports" json:"lamports"`
+	LamportsSol string           `bson:"lamports_sol,omitempty" json:"lamports_sol"`
+	Source      string           `bson:"source" json:"source"`
+}
+
+// WatchedTransactionEvent is a transaction naming an address of the
+// watchlist, whatever the programs it calls. Failed transactions are
+// included.
+type WatchedTransactionEvent struct {
+	BaseEvent `bson:",inline"`
+	Address   solana.PublicKey `bson:"address" json:"address"`
+	Label     string           `bson:"label,omitempty" json:"label,omitempty"`
+	FeePayer  solana.PublicKey `bson:"fee_payer" json:"fee_payer"`
+	// Programs are the programs invoked, top-level or by other programs,
+	// in order of first call.
+	Programs []string `bson:"programs" json:"programs"`
+	Success  bool     `bson:"success" json:"success"`
+	Fee      uint64   `bson:"fee" json:"fee"`
+	// BalanceChange is the change of Address's lamports, fee included.
+	BalanceChange int64 `bson:"balance_change" json:"balance_change"`
+}
+
+// ProgramUpgradedEvent is an upgrade of an indexed program, seen as a new
+// deployment slot of its program data account. Its signature is that of the
+// upgrade transaction and its slot the deployment slot.
+type ProgramUpgradedEvent struct {
+	BaseEvent   `bson:",inline"`
+	Program     solana.PublicKey `bson:"program" json:"program"`
+	ProgramData solana.PublicKey `bson:"program_data" json:"program_data"`
+	// Hash is the hash of the new executable, as solana-verify reports it.
+	Hash string `bson:"hash" json:"hash"`
+	// PreviousSlot and PreviousHash are those of the deployment replaced;
+	// the hash is empty when the deployment was not seen.
+	PreviousSlot uint64 `bson:"previous_slot" json:"previous_slot"`
+	PreviousHash string `bson:"previous_hash,omitempty" json:"previous_hash,omitempty"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const LamportsPerSol = 1_000_000_000
+
+// FormatSol formats lamports as SOL with all nine decimals, e.g.
+// "1.500000000". Strings keep the exact value, which float64 cannot above
+// about 9 million SOL.
+func FormatSol(lamports uint64) string {
+	return fmt.Sprintf("%d.%09d", lamports/LamportsPerSol, lamports%LamportsPerSol)
+}
+
+// The *_sol fields are also derived when serializing, so events stored before
+// they were added get them too.
+
+func (e CounterPaymentReceivedEvent) MarshalJSON() ([]byte, error) {
+	type plain CounterPaymentReceivedEvent
+	e.PaymentSol = FormatSol(e.Payment)
+	return json.Marshal(plain(e))
+}
+
+func (e SolTransferEvent) MarshalJSON() ([]byte, error) {
+	type plain SolTransferEvent
+	e.LamportsSol = FormatSol(e.Lamports)
+	return json.Marshal(plain(e))
+}
+
+func (e NftSoldEvent) MarshalJSON() ([]byte, error) {
+	type plain NftSoldEvent
+	e.PriceSol = FormatSol(e.Price)
+	return json.Marshal(plain(e))
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFormatSol(t *testing.T) {
+	tests := []struct {
+		lamports uint64
+		want     string
+	}{
+		{0, "0.000000000"},
+		{1, "0.000000001"},
+		{1_500_000_000, "1.500000000"},
+		{18_446_744_073_709_551_615, "18446744073.709551615"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatSol(tt.lamports); got != tt.want {
+			t.Errorf("FormatSol(%d) = %q, want %q", tt.lamports, got, tt.want)
+		}
+	}
+}
+
+func TestMarshalJSON_DerivesSol(t *testing.T) {
+	// Stored before payment_sol existed, so the field is empty.
+	event := &CounterPaymentReceivedEvent{BaseEvent: BaseEvent{Signature: "sig"}, Payment: 2_000_000}
+	body, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	for _, want := range []string{`"payment_sol":"0.002000000"`, `"signature":"sig"`} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("Marshal() = %s, missing %s", body, want)
+		}
+	}
+
+	body, err = json.Marshal(NftSoldEvent{Price: 3 * LamportsPerSol})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(body), `"price_sol":"3.000000000"`) {
+		t.Errorf("Marshal() = %s, missing price_sol", body)
+	}
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+// LogEvent is an event of a program indexed through a log grammar. Its
+// event type and fields are the ones the grammar gives it; field values
+// are strings, integers or booleans, and accounts base58 strings.
+type LogEvent struct {
+	BaseEvent `bson:",inline"`
+	Fields    map[string]interface{} `bson:"fields" json:"fields"`
+}
+
+// UnmarshalJSON keeps integer fields integers, as encoding/json would
+// otherwise decode them to float64 and lose precision above 2^53.
+func (e *LogEvent) UnmarshalJSON(data []byte) error {
+	type plain LogEvent
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode((*plain)(e)); err != nil {
+		return err
+	}
+	for name, value := range e.Fields {
+		n, ok := value.(json.Number)
+		if !ok {
+			continue
+		}
+		if v, err := n.Int64(); err == nil {
+			e.Fields[name] = v
+		} else if v, err := strconv.ParseUint(n.String(), 10, 64); err == nil {
+			e.Fields[name] = v
+		} else {
+			e.Fields[name] = n.String()
+		}
+	}
+	return nil
+}
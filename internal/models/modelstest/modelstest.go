@@ -0,0 +1,19 @@
+// Package modelstest provides helpers for tests that work with models.
+package modelstest
+
+import (
+	"testing"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// Envelope wraps event for Publish, failing the test if it does not
+// marshal.
+func Envelope(t testing.TB, base models.BaseEvent, event models.Event) models.Envelope {
+	t.Helper()
+	e, err := models.NewEnvelope(base, event)
+	if err != nil {
+		t.Fatalf("NewEnvelope() error = %v", err)
+	}
+	return e
+}
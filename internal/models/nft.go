@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+type NftMetadataStatus string
+
+const (
+	NftMetadataPending NftMetadataStatus = "pending"
+	NftMetadataFetched NftMetadataStatus = "fetched"
+	// NftMetadataFailed is final: the URI could not be fetched after the
+	// configured number of attempts.
+	NftMetadataFailed NftMetadataStatus = "failed"
+)
+
+// NftMetadata is the off-chain JSON behind an NftMintedEvent URI, in the
+// Metaplex token metadata standard.
+type NftMetadata struct {
+	Mint       string `bson:"_id" json:"mint"`
+	Collection string `bson:"collection" json:"collection"`
+	Owner      string `bson:"owner" json:"owner"`
+	// Name and Uri are the on-chain values from the mint event.
+	Name      string `bson:"name" json:"name"`
+	Uri       string `bson:"uri" json:"uri"`
+	Slot      uint64 `bson:"slot" json:"slot"`
+	Signature string `bson:"signature" json:"signature"`
+
+	Symbol      string         `bson:"symbol,omitempty" json:"symbol,omitempty"`
+	Description string         `bson:"description,omitempty" json:"description,omitempty"`
+	Image       string         `bson:"image,omitempty" json:"image,omitempty"`
+	ExternalURL string         `bson:"external_url,omitempty" json:"external_url,omitempty"`
+	Attributes  []NftAttribute `bson:"attributes,omitempty" json:"attributes,omitempty"`
+
+	Status        NftMetadataStatus `bson:"status" json:"status"`
+	Attempts      int               `bson:"attempts" json:"attempts"`
+	NextAttemptAt time.Time         `bson:"next_attempt_at" json:"-"`
+	FetchedAt     *time.Time        `bson:"fetched_at,omitempty" json:"fetched_at,omitempty"`
+	LastError     string            `bson:"last_error,omitempty" json:"last_error,omitempty"`
+}
+
+// NftAttribute is one trait. Numeric and boolean values are stored in their
+// JSON text form so attributes can be searched uniformly.
+type NftAttribute struct {
+	TraitType string `bson:"trait_type" json:"trait_type"`
+	Value     string `bson:"value" json:"value"`
+}
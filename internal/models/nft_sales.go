@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+type SalesPeriod string
+
+const (
+	SalesPeriodDay SalesPeriod = "day"
+	// SalesPeriodWeek weeks start on Monday, UTC.
+	SalesPeriodWeek SalesPeriod = "week"
+)
+
+// Start returns the start of the period containing t, in UTC.
+func (p SalesPeriod) Start(t time.Time) time.Time {
+	day := t.UTC().Truncate(24 * time.Hour)
+	if p == SalesPeriodWeek {
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	}
+	return day
+}
+
+// Next returns the start of the period after the one starting at start.
+func (p SalesPeriod) Next(start time.Time) time.Time {
+	if p == SalesPeriodWeek {
+		return start.AddDate(0, 0, 7)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// CollectionSalesStats summarizes the NftSoldEvent sales of one collection
+// in one period. Prices are in lamports; Floor and High are the lowest and
+// highest sale prices.
+type CollectionSalesStats struct {
+	Collection string      `bson:"collection" json:"-"`
+	Period     SalesPeriod `bson:"period" json:"-"`
+	Start      time.Time   `bson:"start" json:"start"`
+	Sales      int64       `bson:"sales" json:"sales"`
+	Volume     uint64      `bson:"volume" json:"volume"`
+	Floor      uint64      `bson:"floor" json:"floor"`
+	High       uint64      `bson:"high" json:"high"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// OutboxEntry is an event waiting to be published to one destination. It is
+// stored together with the event, so an event is never stored without its
+// entries.
+type OutboxEntry struct {
+	ID          string    `bson:"_id" json:"id"`
+	Destination string    `bson:"destination" json:"destination"`
+	EventType   EventType `bson:"event_type" json:"event_type"`
+	Signature   string    `bson:"signature" json:"signature"`
+	// Payload is the event as JSON.
+	Payload   []byte     `bson:"payload" json:"payload"`
+	CreatedAt time.Time  `bson:"created_at" json:"created_at"`
+	Attempts  int        `bson:"attempts" json:"attempts"`
+	LastError string     `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	SentAt    *time.Time `bson:"sent_at,omitempty" json:"sent_at,omitempty"`
+}
+
+// Envelope returns the event of the entry for publishing. Only the event
+// type and signature of its base are stored with the entry.
+func (e OutboxEntry) Envelope() Envelope {
+	return Envelope{
+		Base:    BaseEvent{EventType: e.EventType, Signature: e.Signature},
+		Type:    e.EventType,
+		Payload: e.Payload,
+	}
+}
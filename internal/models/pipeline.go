@@ -0,0 +1,10 @@
+package models
+
+// PipelineQueue is the queue in front of one stage of a program's indexing
+// pipeline: "decode" holds fetched transactions, "store" decoded ones.
+type PipelineQueue struct {
+	Program  string `json:"program"`
+	Stage    string `json:"stage"`
+	Depth    int    `json:"depth"`
+	Capacity int    `json:"capacity"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// ProgramConfig mirrors the starter program's ProgramConfig account.
+type ProgramConfig struct {
+	Admin          solana.PublicKey `bson:"admin" json:"admin"`
+	FeeDestination solana.PublicKey `bson:"fee_destination" json:"fee_destination"`
+	FeeBasisPoints uint64           `bson:"fee_basis_points" json:"fee_basis_points"`
+	Paused         bool             `bson:"paused" json:"paused"`
+}
+
+type ConfigChangeSource string
+
+const (
+	ConfigChangeSourceConfigUpdated ConfigChangeSource = ConfigChangeSource(EventTypeConfigUpdated)
+	ConfigChangeSourceProgramPaused ConfigChangeSource = ConfigChangeSource(EventTypeProgramPaused)
+	// ConfigChangeSourceAccount marks changes observed on the account itself
+	// that no indexed event accounted for.
+	ConfigChangeSourceAccount ConfigChangeSource = "account"
+)
+
+// ConfigChange is one entry of the ProgramConfig history: the full config
+// after the change and the fields that differ from the previous entry.
+type ConfigChange struct {
+	ProgramID     solana.PublicKey   `bson:"program_id" json:"program_id"`
+	Source        ConfigChangeSource `bson:"source" json:"source"`
+	Signature     string             `bson:"signature,omitempty" json:"signature,omitempty"`
+	Slot          uint64             `bson:"slot" json:"slot"`
+	BlockTime     time.Time          `bson:"block_time" json:"block_time"`
+	ChangedFields []string           `bson:"changed_fields" json:"changed_fields"`
+	Config        ProgramConfig      `bson:"config" json:"config"`
+	RecordedAt    time.Time          `bson:"recorded_at" json:"recorded_at"`
+}
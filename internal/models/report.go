@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Report is a saved query that runs on a schedule and delivers its result
+// as CSV by email and/or to a Google Sheet.
+type Report struct {
+	Name  string      `bson:"_id" json:"name"`
+	Query ReportQuery `bson:"query" json:"query"`
+	// Every is how often the report runs, as a Go duration such as "24h".
+	Every    string         `bson:"every" json:"every"`
+	Delivery ReportDelivery `bson:"delivery" json:"delivery"`
+
+	CreatedAt time.Time  `bson:"created_at" json:"created_at"`
+	LastRunAt *time.Time `bson:"last_run_at,omitempty" json:"last_run_at,omitempty"`
+	LastError string     `bson:"last_error,omitempty" json:"last_error,omitempty"`
+}
+
+type ReportQuery struct {
+	EventTypes []EventType `bson:"event_types,omitempty" json:"event_types,omitempty"`
+	Account    string      `bson:"account,omitempty" json:"account,omitempty"`
+	// Window is how far back from the run time the query looks, e.g. "24h".
+	Window string `bson:"window" json:"window"`
+	// SavedQuery runs the saved query of this name with Params instead of
+	// EventTypes and Account. Window applies unless the saved query sets
+	// its own time range.
+	SavedQuery string            `bson:"saved_query,omitempty" json:"saved_query,omitempty"`
+	Params     map[string]string `bson:"params,omitempty" json:"params,omitempty"`
+}
+
+type ReportDelivery struct {
+	Email []string `bson:"email,omitempty" json:"email,omitempty"`
+	// SheetID is a Google spreadsheet the service account can edit. Each run
+	// replaces the contents of SheetRange, which defaults to "Sheet1".
+	SheetID    string `bson:"sheet_id,omitempty" json:"sheet_id,omitempty"`
+	SheetRange string `bson:"sheet_range,omitempty" json:"sheet_range,omitempty"`
+}
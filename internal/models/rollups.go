@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// RollupResolution is the bucket size of an event rollup table.
+type RollupResolution string
+
+const (
+	RollupMinute RollupResolution = "minute"
+	RollupHour   RollupResolution = "hour"
+)
+
+// RollupResolutions are the resolutions rollups are kept at.
+var RollupResolutions = []RollupResolution{RollupMinute, RollupHour}
+
+func (r RollupResolution) Duration() time.Duration {
+	if r == RollupHour {
+		return time.Hour
+	}
+	return time.Minute
+}
+
+// EventRollup counts the events of one type and program stored with a
+// block time in [Bucket, Bucket+resolution).
+type EventRollup struct {
+	Bucket    time.Time `bson:"bucket" json:"bucket"`
+	EventType EventType `bson:"event_type" json:"event_type"`
+	ProgramID string    `bson:"program_id" json:"program_id"`
+	Count     int64     `bson:"count" json:"count"`
+}
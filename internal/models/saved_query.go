@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// SavedQuery is a named event query kept server side, so dashboards and
+// reports reference a stable definition instead of repeating its filter.
+// Filter values of the form "{{name}}" are filled in from Params when the
+// query runs.
+type SavedQuery struct {
+	Name        string           `bson:"_id" json:"name"`
+	Description string           `bson:"description,omitempty" json:"description,omitempty"`
+	Filter      SavedQueryFilter `bson:"filter" json:"filter"`
+	// Order is "asc" or "desc" by slot; empty means "desc".
+	Order string `bson:"order,omitempty" json:"order,omitempty"`
+	// Fields keeps only these event fields in the results; empty keeps all.
+	Fields []string `bson:"fields,omitempty" json:"fields,omitempty"`
+	// Limit is the default page size.
+	Limit  int               `bson:"limit,omitempty" json:"limit,omitempty"`
+	Params []SavedQueryParam `bson:"params,omitempty" json:"params,omitempty"`
+
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// SavedQueryFilter holds every value as a string so any of them can be a
+// parameter placeholder.
+type SavedQueryFilter struct {
+	// EventTypes may hold a placeholder expanding to a comma separated list.
+	EventTypes []string `bson:"event_types,omitempty" json:"event_types,omitempty"`
+	Account    string   `bson:"account,omitempty" json:"account,omitempty"`
+	// From (inclusive) and To (exclusive) are RFC 3339 or YYYY-MM-DD.
+	From string `bson:"from,omitempty" json:"from,omitempty"`
+	To   string `bson:"to,omitempty" json:"to,omitempty"`
+	// Window looks back from the run time, e.g. "24h"; it excludes From.
+	Window   string `bson:"window,omitempty" json:"window,omitempty"`
+	FromSlot string `bson:"from_slot,omitempty" json:"from_slot,omitempty"`
+	ToSlot   string `bson:"to_slot,omitempty" json:"to_slot,omitempty"`
+}
+
+// SavedQueryParam is a parameter of a saved query. Parameters without a
+// default are required.
+type SavedQueryParam struct {
+	Name        string `bson:"name" json:"name"`
+	Description string `bson:"description,omitempty" json:"description,omitempty"`
+	Default     string `bson:"default,omitempty" json:"default,omitempty"`
+}
@@ -0,0 +1,41 @@
+package models
+
+// TokenMovement is a change of token balances: a mint has no From, a burn
+// no To and a transfer both.
+type TokenMovement struct {
+	// ID identifies the movement, so it is applied once however often its
+	// event is indexed.
+	ID     string
+	Mint   string
+	From   string
+	To     string
+	Amount uint64
+	Slot   uint64
+}
+
+// TokenSupply is the running supply of a mint from its indexed mint and burn
+// events.
+type TokenSupply struct {
+	Mint    string `bson:"_id" json:"mint"`
+	Minted  uint64 `bson:"minted" json:"minted"`
+	Burned  uint64 `bson:"burned" json:"burned"`
+	Supply  uint64 `bson:"-" json:"supply"`
+	Holders int64  `bson:"-" json:"holders"`
+	Slot    uint64 `bson:"slot" json:"slot"`
+}
+
+type TokenHolder struct {
+	Mint    string `bson:"mint" json:"-"`
+	Owner   string `bson:"owner" json:"owner"`
+	Balance uint64 `bson:"balance" json:"balance"`
+	Slot    uint64 `bson:"slot" json:"slot"`
+}
+
+// TokenAccount maps an associated token account to its owner and mint.
+type TokenAccount struct {
+	Address      string `bson:"_id" json:"address"`
+	Owner        string `bson:"owner" json:"owner"`
+	Mint         string `bson:"mint" json:"mint"`
+	TokenProgram string `bson:"token_program" json:"token_program"`
+	Slot         uint64 `bson:"slot" json:"slot"`
+}
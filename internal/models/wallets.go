@@ -0,0 +1,110 @@
+package models
+
+import "github.com/gagliardetto/solana-go"
+
+// WalletAddresses returns the wallet (user-owned) addresses referenced by an
+// event, skipping zero keys and duplicates. Mints, collections and program
+// accounts are not included.
+func WalletAddresses(event interface{}) []solana.PublicKey {
+	var keys []solana.PublicKey
+	switch e := event.(type) {
+	case *TokensMintedEvent:
+		keys = []solana.PublicKey{e.Recipient}
+	case *TokensTransferredEvent:
+		keys = []solana.PublicKey{e.From, e.To}
+	case *TokensBurnedEvent:
+		keys = []solana.PublicKey{e.Owner}
+	case *UserAccountCreatedEvent:
+		keys = []solana.PublicKey{e.User, e.Authority}
+	case *UserAccountUpdatedEvent:
+		keys = []solana.PublicKey{e.User}
+	case *ConfigUpdatedEvent:
+		keys = []solana.PublicKey{e.Admin}
+	case *ProgramPausedEvent:
+		keys = []solana.PublicKey{e.Admin}
+	case *NftMintedEvent:
+		keys = []solana.PublicKey{e.Owner}
+	case *NftSoldEvent:
+		keys = []solana.PublicKey{e.Seller, e.Buyer}
+	case *CounterInitializedEvent:
+		keys = []solana.PublicKey{e.Authority}
+	case *CounterResetEvent:
+		keys = []solana.PublicKey{e.Authority}
+	case *CounterPaymentReceivedEvent:
+		keys = []solana.PublicKey{e.Payer, e.FeeCollector}
+	case *SplTokenTransferEvent:
+		keys = []solana.PublicKey{e.SourceOwner, e.DestinationOwner, e.Authority}
+	case *SplTokenMintToEvent:
+		keys = []solana.PublicKey{e.Owner}
+	case *SplTokenBurnEvent:
+		keys = []solana.PublicKey{e.Owner, e.Authority}
+	case *SplTokenAccountInitializedEvent:
+		keys = []solana.PublicKey{e.Owner}
+	case *SolTransferEvent:
+		keys = []solana.PublicKey{e.From, e.To}
+	case *WatchedTransactionEvent:
+		keys = []solana.PublicKey{e.Address, e.FeePayer}
+	}
+
+	return uniqueKeys(keys)
+}
+
+// AccountAddresses returns every address referenced by an event: its
+// wallets plus mints, collections and counters.
+func AccountAddresses(event interface{}) []solana.PublicKey {
+	keys := WalletAddresses(event)
+	switch e := event.(type) {
+	case *TokensMintedEvent:
+		keys = append(keys, e.Mint)
+	case *TokensTransferredEvent:
+		keys = append(keys, e.Mint)
+	case *TokensBurnedEvent:
+		keys = append(keys, e.Mint)
+	case *NftMintedEvent:
+		keys = append(keys, e.NftMint, e.Collection)
+	case *NftSoldEvent:
+		keys = append(keys, e.NftMint)
+	case *CounterInitializedEvent:
+		keys = append(keys, e.Counter)
+	case *CounterIncrementedEvent:
+		keys = append(keys, e.Counter)
+	case *CounterDecrementedEvent:
+		keys = append(keys, e.Counter)
+	case *CounterAddedEvent:
+		keys = append(keys, e.Counter)
+	case *CounterResetEvent:
+		keys = append(keys, e.Counter)
+	case *CounterPaymentReceivedEvent:
+		keys = append(keys, e.Counter)
+	case *SplTokenTransferEvent:
+		keys = append(keys, e.Mint, e.Source, e.Destination)
+	case *SplTokenMintToEvent:
+		keys = append(keys, e.Mint, e.Account, e.Authority)
+	case *SplTokenBurnEvent:
+		keys = append(keys, e.Mint, e.Account)
+	case *SplTokenAccountInitializedEvent:
+		keys = append(keys, e.Mint, e.Account)
+	}
+	return uniqueKeys(keys)
+}
+
+// uniqueKeys drops zero keys and duplicates in place.
+func uniqueKeys(keys []solana.PublicKey) []solana.PublicKey {
+	out := keys[:0]
+	for _, key := range keys {
+		if key.IsZero() || containsKey(out, key) {
+			continue
+		}
+		out = append(out, key)
+	}
+	return out
+}
+
+func containsKey(keys []solana.PublicKey, key solana.PublicKey) bool {
+	for _, k := range keys {
+		if k.Equals(key) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// WatchedAddress is an address of the runtime watchlist: every transaction
+// naming it is indexed as a WatchedTransactionEvent, whatever the programs.
+type WatchedAddress struct {
+	Address string `bson:"_id" json:"address"`
+	Label   string `bson:"label,omitempty" json:"label,omitempty"`
+	// Webhook is notified of the address's transactions instead of
+	// WATCHLIST_WEBHOOK_URL.
+	Webhook   string    `bson:"webhook,omitempty" json:"webhook,omitempty"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
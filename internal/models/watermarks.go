@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Watermarks tell downstream consumers how far the indexed data goes.
+type Watermarks struct {
+	// Processed is the newest slot a transaction was indexed from.
+	Processed uint64 `bson:"processed" json:"processed"`
+	// Confirmed is the slot the cluster had confirmed when last asked.
+	Confirmed uint64 `bson:"confirmed" json:"confirmed"`
+	// Finalized is the slot up to which the data of every indexed program
+	// is complete and final: every transaction up to it has been indexed
+	// and none can be rolled back.
+	Finalized uint64    `bson:"finalized" json:"finalized"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,158 @@
+// Package nftmeta fetches the off-chain metadata JSON of minted NFTs so it
+// can be searched by name and attribute.
+package nftmeta
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+// maxRetryDelay caps the backoff between attempts for one NFT.
+const maxRetryDelay = 6 * time.Hour
+
+type Options struct {
+	// Interval is how often pending NFTs are fetched.
+	Interval time.Duration
+	// BatchSize caps the NFTs fetched per interval.
+	BatchSize int
+	// MaxAttempts marks an NFT failed after this many fetch errors.
+	MaxAttempts int
+}
+
+// Enricher queues every NftMintedEvent it is published and fetches the
+// metadata of queued NFTs in the background. The queue lives in the store,
+// so pending NFTs survive restarts.
+type Enricher struct {
+	store   repository.NftMetadataStore
+	fetcher *Fetcher
+	opts    Options
+	now     func() time.Time
+}
+
+func NewEnricher(store repository.NftMetadataStore, fetcher *Fetcher, opts Options) *Enricher {
+	if opts.Interval <= 0 {
+		opts.Interval = 30 * time.Second
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 20
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 5
+	}
+	return &Enricher{
+		store:   store,
+		fetcher: fetcher,
+		opts:    opts,
+		now:     time.Now,
+	}
+}
+
+// Publish queues minted NFTs. Queueing failures are logged rather than
+// returned so enrichment never blocks indexing.
+func (e *Enricher) Publish(ctx context.Context, envelope models.Envelope) error {
+	if envelope.Type != models.EventTypeNftMinted {
+		return nil
+	}
+	event, err := envelope.Event()
+	if err != nil {
+		return err
+	}
+	mint, ok := event.(*models.NftMintedEvent)
+	if !ok {
+		return nil
+	}
+	base := envelope.Base
+
+	nft := &models.NftMetadata{
+		Mint:          mint.NftMint.String(),
+		Collection:    mint.Collection.String(),
+		Owner:         mint.Owner.String(),
+		Name:          mint.Name,
+		Uri:           mint.Uri,
+		Slot:          base.Slot,
+		Signature:     base.Signature,
+		Status:        models.NftMetadataPending,
+		NextAttemptAt: e.now(),
+	}
+	if err := e.store.QueueNftMetadata(ctx, nft); err != nil {
+		log.Printf("warning: failed to queue metadata of nft %s: %v", nft.Mint, err)
+	}
+	return nil
+}
+
+func (e *Enricher) Close(ctx context.Context) error {
+	return nil
+}
+
+func (e *Enricher) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := e.RunOnce(ctx); err != nil {
+			log.Printf("error fetching nft metadata: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce fetches one batch of due NFTs and returns how many were fetched
+// successfully. A failed fetch is recorded on the NFT and retried later.
+func (e *Enricher) RunOnce(ctx context.Context) (int, error) {
+	pending, err := e.store.PendingNftMetadata(ctx, e.now(), e.opts.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	fetched := 0
+	for i := range pending {
+		nft := &pending[i]
+		if e.fetch(ctx, nft) {
+			fetched++
+		}
+		if err := e.store.SaveNftMetadata(ctx, nft); err != nil {
+			return fetched, err
+		}
+	}
+	return fetched, nil
+}
+
+func (e *Enricher) fetch(ctx context.Context, nft *models.NftMetadata) bool {
+	nft.Attempts++
+	doc, err := e.fetcher.Fetch(ctx, nft.Uri)
+	now := e.now()
+	if err != nil {
+		nft.LastError = err.Error()
+		if nft.Attempts >= e.opts.MaxAttempts {
+			nft.Status = models.NftMetadataFailed
+			log.Printf("warning: giving up on metadata of nft %s: %v", nft.Mint, err)
+		} else {
+			nft.NextAttemptAt = now.Add(retryDelay(e.opts.Interval, nft.Attempts))
+		}
+		return false
+	}
+
+	doc.Apply(nft)
+	nft.Status = models.NftMetadataFetched
+	nft.FetchedAt = &now
+	nft.LastError = ""
+	return true
+}
+
+// retryDelay doubles the interval with every failed attempt.
+func retryDelay(interval time.Duration, attempts int) time.Duration {
+	delay := interval
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
@@ -0,0 +1,131 @@
+package nftmeta
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models/modelstest"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+type fakeStore struct {
+	nfts map[string]models.NftMetadata
+}
+
+func (s *fakeStore) QueueNftMetadata(ctx context.Context, nft *models.NftMetadata) error {
+	if _, ok := s.nfts[nft.Mint]; !ok {
+		s.nfts[nft.Mint] = *nft
+	}
+	return nil
+}
+
+func (s *fakeStore) PendingNftMetadata(ctx context.Context, now time.Time, limit int) ([]models.NftMetadata, error) {
+	var pending []models.NftMetadata
+	for _, nft := range s.nfts {
+		if nft.Status == models.NftMetadataPending && !nft.NextAttemptAt.After(now) && len(pending) < limit {
+			pending = append(pending, nft)
+		}
+	}
+	return pending, nil
+}
+
+func (s *fakeStore) SaveNftMetadata(ctx context.Context, nft *models.NftMetadata) error {
+	s.nfts[nft.Mint] = *nft
+	return nil
+}
+
+func (s *fakeStore) GetNftMetadata(ctx context.Context, mint string) (*models.NftMetadata, error) {
+	nft, ok := s.nfts[mint]
+	if !ok {
+		return nil, nil
+	}
+	return &nft, nil
+}
+
+func (s *fakeStore) SearchNfts(ctx context.Context, search repository.NftSearch) ([]models.NftMetadata, error) {
+	return nil, nil
+}
+
+func TestEnricher_RunOnce(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken.json" {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"name": "Ape #1", "attributes": [{"trait_type": "Background", "value": "Blue"}]}`))
+	}))
+	defer srv.Close()
+
+	store := &fakeStore{nfts: make(map[string]models.NftMetadata)}
+	e := NewEnricher(store, NewFetcher(FetcherOptions{AllowPrivateNetworks: true}), Options{Interval: time.Minute, MaxAttempts: 2})
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	e.now = func() time.Time { return now }
+
+	good := solana.MustPublicKeyFromBase58("7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU")
+	bad := solana.MustPublicKeyFromBase58("9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM")
+	for _, mint := range []*models.NftMintedEvent{
+		{NftMint: good, Uri: srv.URL + "/1.json"},
+		{NftMint: bad, Uri: srv.URL + "/broken.json"},
+		{NftMint: good, Uri: srv.URL + "/duplicate.json"},
+	} {
+		if err := e.Publish(context.Background(), modelstest.Envelope(t, models.BaseEvent{EventType: models.EventTypeNftMinted, Slot: 10}, mint)); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	if len(store.nfts) != 2 {
+		t.Fatalf("queued %d nfts, want 2", len(store.nfts))
+	}
+
+	fetched, err := e.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+	if fetched != 1 {
+		t.Errorf("RunOnce() = %d, want 1", fetched)
+	}
+
+	ok := store.nfts[good.String()]
+	if ok.Status != models.NftMetadataFetched || ok.Name != "Ape #1" || len(ok.Attributes) != 1 || ok.FetchedAt == nil {
+		t.Errorf("fetched nft = %+v", ok)
+	}
+	failed := store.nfts[bad.String()]
+	if failed.Status != models.NftMetadataPending || failed.Attempts != 1 || !failed.NextAttemptAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("failed nft = %+v", failed)
+	}
+
+	// Not due yet.
+	if fetched, _ := e.RunOnce(context.Background()); fetched != 0 || store.nfts[bad.String()].Attempts != 1 {
+		t.Errorf("retried before the backoff elapsed")
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := e.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+	if got := store.nfts[bad.String()].Status; got != models.NftMetadataFailed {
+		t.Errorf("status after max attempts = %s, want %s", got, models.NftMetadataFailed)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{4, 8 * time.Minute},
+		{20, maxRetryDelay},
+	}
+
+	for _, tt := range tests {
+		if got := retryDelay(time.Minute, tt.attempts); got != tt.want {
+			t.Errorf("retryDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
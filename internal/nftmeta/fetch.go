@@ -0,0 +1,191 @@
+package nftmeta
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+const (
+	defaultIPFSGateway    = "https://ipfs.io/ipfs/"
+	defaultArweaveGateway = "https://arweave.net/"
+	maxMetadataBytes      = 1 << 20
+)
+
+var errPrivateAddress = errors.New("refusing to fetch from a private network address")
+
+// Document is the subset of the Metaplex JSON metadata standard that is
+// stored and searchable.
+type Document struct {
+	Name        string         `json:"name"`
+	Symbol      string         `json:"symbol"`
+	Description string         `json:"description"`
+	Image       string         `json:"image"`
+	ExternalURL string         `json:"external_url"`
+	Attributes  []rawAttribute `json:"attributes"`
+}
+
+type rawAttribute struct {
+	TraitType string          `json:"trait_type"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// Fetcher downloads metadata JSON over HTTP(S). ipfs:// and ar:// URIs are
+// rewritten to public gateways. URIs come from the chain and anyone can mint,
+// so by default the fetcher refuses to connect to loopback, private and
+// link-local addresses.
+type Fetcher struct {
+	client      *http.Client
+	ipfsGateway string
+}
+
+type FetcherOptions struct {
+	Timeout time.Duration
+	// IPFSGateway replaces "ipfs://"; defaults to https://ipfs.io/ipfs/.
+	IPFSGateway string
+	// AllowPrivateNetworks disables the private address check, for tests
+	// and self-hosted gateways.
+	AllowPrivateNetworks bool
+}
+
+func NewFetcher(opts FetcherOptions) *Fetcher {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.IPFSGateway == "" {
+		opts.IPFSGateway = defaultIPFSGateway
+	}
+	if !strings.HasSuffix(opts.IPFSGateway, "/") {
+		opts.IPFSGateway += "/"
+	}
+
+	dialer := &net.Dialer{Timeout: opts.Timeout}
+	if !opts.AllowPrivateNetworks {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isPrivate(ip) {
+				return fmt.Errorf("%w: %s", errPrivateAddress, host)
+			}
+			return nil
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	transport.Proxy = nil
+
+	return &Fetcher{
+		client:      &http.Client{Timeout: opts.Timeout, Transport: transport},
+		ipfsGateway: opts.IPFSGateway,
+	}
+}
+
+func isPrivate(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsUnspecified() || ip.IsMulticast()
+}
+
+// ResolveURI maps a metadata URI to the HTTP(S) URL it is fetched from.
+func (f *Fetcher) ResolveURI(uri string) (string, error) {
+	uri = strings.TrimSpace(uri)
+	switch {
+	case strings.HasPrefix(uri, "ipfs://"):
+		path := strings.TrimPrefix(strings.TrimPrefix(uri, "ipfs://"), "ipfs/")
+		return f.ipfsGateway + path, nil
+	case strings.HasPrefix(uri, "ar://"):
+		return defaultArweaveGateway + strings.TrimPrefix(uri, "ar://"), nil
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("parse uri: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("unsupported uri %q", uri)
+	}
+	return u.String(), nil
+}
+
+func (f *Fetcher) Fetch(ctx context.Context, uri string) (*Document, error) {
+	target, err := f.ResolveURI(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s: %w", target, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch %s: status %d", target, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxMetadataBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", target, err)
+	}
+	if len(body) > maxMetadataBytes {
+		return nil, fmt.Errorf("metadata at %s exceeds %d bytes", target, maxMetadataBytes)
+	}
+
+	var doc Document
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("decode metadata: %w", err)
+	}
+	return &doc, nil
+}
+
+// Apply copies the fetched fields onto nft. The on-chain name is kept when
+// set; the JSON name only fills it in.
+func (d *Document) Apply(nft *models.NftMetadata) {
+	if nft.Name == "" {
+		nft.Name = d.Name
+	}
+	nft.Symbol = d.Symbol
+	nft.Description = d.Description
+	nft.Image = d.Image
+	nft.ExternalURL = d.ExternalURL
+	nft.Attributes = nil
+	for _, attr := range d.Attributes {
+		if attr.TraitType == "" {
+			continue
+		}
+		nft.Attributes = append(nft.Attributes, models.NftAttribute{
+			TraitType: attr.TraitType,
+			Value:     attributeValue(attr.Value),
+		})
+	}
+}
+
+// attributeValue unquotes string values and keeps numbers and booleans in
+// their JSON form.
+func attributeValue(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	if string(raw) == "null" {
+		return ""
+	}
+	return string(raw)
+}
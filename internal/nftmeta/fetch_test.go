@@ -0,0 +1,101 @@
+package nftmeta
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+func TestFetcher_ResolveURI(t *testing.T) {
+	f := NewFetcher(FetcherOptions{IPFSGateway: "https://gateway.example/ipfs"})
+
+	tests := []struct {
+		uri     string
+		want    string
+		wantErr bool
+	}{
+		{"https://example.com/1.json", "https://example.com/1.json", false},
+		{" http://example.com/1.json ", "http://example.com/1.json", false},
+		{"ipfs://bafy123/1.json", "https://gateway.example/ipfs/bafy123/1.json", false},
+		{"ipfs://ipfs/bafy123", "https://gateway.example/ipfs/bafy123", false},
+		{"ar://abc", "https://arweave.net/abc", false},
+		{"file:///etc/passwd", "", true},
+		{"https://", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			got, err := f.ResolveURI(tt.uri)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveURI() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveURI() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetcher_Fetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{
+			"name": "Ape #1",
+			"symbol": "APE",
+			"description": "A test ape",
+			"image": "https://example.com/1.png",
+			"attributes": [
+				{"trait_type": "Background", "value": "Blue"},
+				{"trait_type": "Level", "value": 3},
+				{"trait_type": "Rare", "value": true},
+				{"value": "no trait"}
+			]
+		}`))
+	}))
+	defer srv.Close()
+
+	f := NewFetcher(FetcherOptions{AllowPrivateNetworks: true})
+	doc, err := f.Fetch(context.Background(), srv.URL+"/1.json")
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	nft := &models.NftMetadata{Name: "On-chain name"}
+	doc.Apply(nft)
+	if nft.Name != "On-chain name" || nft.Symbol != "APE" || nft.Image != "https://example.com/1.png" {
+		t.Errorf("Apply() = %+v", nft)
+	}
+	wantAttrs := []models.NftAttribute{
+		{TraitType: "Background", Value: "Blue"},
+		{TraitType: "Level", Value: "3"},
+		{TraitType: "Rare", Value: "true"},
+	}
+	if !reflect.DeepEqual(nft.Attributes, wantAttrs) {
+		t.Errorf("Attributes = %+v, want %+v", nft.Attributes, wantAttrs)
+	}
+
+	if _, err := f.Fetch(context.Background(), srv.URL+"/missing.json"); err == nil {
+		t.Error("Fetch() of a 404 succeeded")
+	}
+}
+
+func TestFetcher_RefusesPrivateAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	_, err := NewFetcher(FetcherOptions{}).Fetch(context.Background(), srv.URL)
+	if !errors.Is(err, errPrivateAddress) {
+		t.Errorf("Fetch() error = %v, want %v", err, errPrivateAddress)
+	}
+}
@@ -0,0 +1,108 @@
+// Package outbox publishes the outbox entries stored with every event, so
+// events reach message buses at least once even across crashes.
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+	"github.com/lugondev/go-indexer-solana-starter/internal/sink"
+)
+
+type Options struct {
+	Interval  time.Duration
+	BatchSize int
+}
+
+// Dispatcher publishes the pending entries of one destination in order. A
+// batch is marked sent only after the writer accepted it, so a crash in
+// between publishes the batch again.
+type Dispatcher struct {
+	store       repository.OutboxStore
+	destination string
+	writer      sink.Writer
+	opts        Options
+	now         func() time.Time
+}
+
+func NewDispatcher(store repository.OutboxStore, destination string, writer sink.Writer, opts Options) *Dispatcher {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	return &Dispatcher{
+		store:       store,
+		destination: destination,
+		writer:      writer,
+		opts:        opts,
+		now:         time.Now,
+	}
+}
+
+// Run dispatches until ctx is done, draining the backlog before waiting for
+// the next interval.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			n, err := d.DispatchOnce(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("warning: outbox dispatch to %s failed: %v", d.destination, err)
+				}
+				break
+			}
+			if n < d.opts.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchOnce publishes one batch and returns how many entries it held.
+func (d *Dispatcher) DispatchOnce(ctx context.Context) (int, error) {
+	entries, err := d.store.PendingOutbox(ctx, d.destination, d.opts.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]string, len(entries))
+	events := make([]models.Envelope, len(entries))
+	for i, e := range entries {
+		ids[i] = e.ID
+		events[i] = e.Envelope()
+	}
+
+	if err := d.writer.Write(ctx, events); err != nil {
+		if markErr := d.store.MarkOutboxFailed(ctx, ids, err.Error()); markErr != nil {
+			log.Printf("warning: %v", markErr)
+		}
+		return 0, fmt.Errorf("write %d entries: %w", len(entries), err)
+	}
+	if err := d.store.MarkOutboxSent(ctx, ids, d.now()); err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+// Close closes the writer.
+func (d *Dispatcher) Close(ctx context.Context) error {
+	return d.writer.Close(ctx)
+}
@@ -0,0 +1,104 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+type fakeStore struct {
+	entries []models.OutboxEntry
+	failed  map[string]string
+}
+
+func (s *fakeStore) SaveEventWithOutbox(ctx context.Context, event models.Event, destinations []string) error {
+	return nil
+}
+
+func (s *fakeStore) PendingOutbox(ctx context.Context, destination string, limit int) ([]models.OutboxEntry, error) {
+	var pending []models.OutboxEntry
+	for _, e := range s.entries {
+		if e.Destination == destination && e.SentAt == nil && len(pending) < limit {
+			pending = append(pending, e)
+		}
+	}
+	return pending, nil
+}
+
+func (s *fakeStore) MarkOutboxSent(ctx context.Context, ids []string, at time.Time) error {
+	for _, id := range ids {
+		for i := range s.entries {
+			if s.entries[i].ID == id {
+				s.entries[i].SentAt = &at
+			}
+		}
+	}
+	return nil
+}
+
+func (s *fakeStore) MarkOutboxFailed(ctx context.Context, ids []string, reason string) error {
+	for _, id := range ids {
+		s.failed[id] = reason
+	}
+	return nil
+}
+
+type fakeWriter struct {
+	written []models.Envelope
+	err     error
+}
+
+func (w *fakeWriter) Write(ctx context.Context, events []models.Envelope) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.written = append(w.written, events...)
+	return nil
+}
+
+func (w *fakeWriter) Close(ctx context.Context) error {
+	return nil
+}
+
+func TestDispatcher_DispatchOnce(t *testing.T) {
+	store := &fakeStore{
+		entries: []models.OutboxEntry{
+			{ID: "1", Destination: "kafka", Signature: "a", Payload: []byte(`{"n":1}`)},
+			{ID: "2", Destination: "webhook", Signature: "a", Payload: []byte(`{"n":1}`)},
+			{ID: "3", Destination: "kafka", Signature: "b", Payload: []byte(`{"n":2}`)},
+		},
+		failed: make(map[string]string),
+	}
+	ctx := context.Background()
+
+	w := &fakeWriter{err: errors.New("broker down")}
+	d := NewDispatcher(store, "kafka", w, Options{BatchSize: 10})
+	if _, err := d.DispatchOnce(ctx); err == nil {
+		t.Fatalf("DispatchOnce() error = nil, want the write error")
+	}
+	if store.failed["1"] != "broker down" || store.failed["3"] != "broker down" {
+		t.Errorf("failed = %v, want both kafka entries marked failed", store.failed)
+	}
+
+	w.err = nil
+	n, err := d.DispatchOnce(ctx)
+	if err != nil {
+		t.Fatalf("DispatchOnce() error = %v", err)
+	}
+	if n != 2 || len(w.written) != 2 || w.written[1].Base.Signature != "b" {
+		t.Fatalf("written = %+v, want both kafka entries in order", w.written)
+	}
+	if payload := w.written[0].Payload; string(payload) != `{"n":1}` {
+		t.Errorf("payload = %s, want the stored JSON", payload)
+	}
+	if store.entries[1].SentAt != nil {
+		t.Errorf("webhook entry was marked sent by the kafka dispatcher")
+	}
+
+	if n, _ := d.DispatchOnce(ctx); n != 0 {
+		t.Errorf("DispatchOnce() after sending = %d, want 0", n)
+	}
+}
@@ -0,0 +1,223 @@
+// Package output renders query results for the terminal as a table, JSON
+// or YAML.
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+type Format string
+
+const (
+	FormatTable Format = "table"
+	FormatJSON  Format = "json"
+	FormatYAML  Format = "yaml"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case FormatTable, FormatJSON, FormatYAML:
+		return Format(s), nil
+	default:
+		return "", fmt.Errorf("unknown output format %q, want table, json or yaml", s)
+	}
+}
+
+// Record is one result as its JSON fields, in the order they were encoded.
+type Record struct {
+	Keys   []string
+	Values map[string]interface{}
+}
+
+// Records converts a slice of results to records through their JSON
+// encoding, so fields are named as in the API.
+func Records(items interface{}) ([]Record, error) {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, fmt.Errorf("encode results: %w", err)
+	}
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("results are not a list: %w", err)
+	}
+
+	records := make([]Record, 0, len(raw))
+	for _, item := range raw {
+		r, err := decodeRecord(item)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+	return records, nil
+}
+
+func decodeRecord(data json.RawMessage) (Record, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return Record{}, fmt.Errorf("result is not an object: %s", truncate(string(data), 40))
+	}
+
+	r := Record{Values: make(map[string]interface{})}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return Record{}, fmt.Errorf("decode result: %w", err)
+		}
+		key := tok.(string)
+		var value interface{}
+		if err := dec.Decode(&value); err != nil {
+			return Record{}, fmt.Errorf("decode result field %s: %w", key, err)
+		}
+		if _, dup := r.Values[key]; !dup {
+			r.Keys = append(r.Keys, key)
+		}
+		r.Values[key] = value
+	}
+	return r, nil
+}
+
+// Write renders records. With columns set only those fields are written,
+// in that order; otherwise tables show the fields of all records in order
+// of appearance and JSON and YAML the whole records.
+func Write(w io.Writer, format Format, records []Record, columns []string) error {
+	if len(columns) > 0 {
+		if err := checkColumns(records, columns); err != nil {
+			return err
+		}
+	}
+	switch format {
+	case FormatTable:
+		if len(columns) == 0 {
+			columns = allKeys(records)
+		}
+		return writeTable(w, records, columns)
+	case FormatJSON:
+		return writeJSON(w, records, columns)
+	case FormatYAML:
+		return writeYAML(w, records, columns)
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// checkColumns rejects columns no record has, which are most likely typos.
+func checkColumns(records []Record, columns []string) error {
+	if len(records) == 0 {
+		return nil
+	}
+	known := make(map[string]bool)
+	for _, k := range allKeys(records) {
+		known[k] = true
+	}
+	for _, c := range columns {
+		if !known[c] {
+			return fmt.Errorf("unknown column %q, want one of %s", c, strings.Join(allKeys(records), ", "))
+		}
+	}
+	return nil
+}
+
+func allKeys(records []Record) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, r := range records {
+		for _, k := range r.Keys {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	return keys
+}
+
+// fields returns the keys of r to write.
+func (r Record) fields(columns []string) []string {
+	if len(columns) > 0 {
+		return columns
+	}
+	return r.Keys
+}
+
+func writeTable(w io.Writer, records []Record, columns []string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = strings.ToUpper(c)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	cells := make([]string, len(columns))
+	for _, r := range records {
+		for i, c := range columns {
+			cells[i] = tableCell(r.Values[c])
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// tableCell renders scalars as is and nested values as compact JSON.
+func tableCell(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.NewReplacer("\t", " ", "\n", " ").Replace(v)
+	case json.Number:
+		return v.String()
+	case bool:
+		return fmt.Sprint(v)
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+func writeJSON(w io.Writer, records []Record, columns []string) error {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, r := range records {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('{')
+		for j, k := range r.fields(columns) {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(k)
+			value, err := json.Marshal(r.Values[k])
+			if err != nil {
+				return fmt.Errorf("encode field %s: %w", k, err)
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			buf.Write(value)
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(']')
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+		return fmt.Errorf("indent json: %w", err)
+	}
+	out.WriteByte('\n')
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,129 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+type testEvent struct {
+	EventType string            `json:"event_type"`
+	Slot      uint64            `json:"slot"`
+	Signature string            `json:"signature"`
+	BlockTime time.Time         `json:"block_time"`
+	Memo      *string           `json:"memo"`
+	Accounts  []string          `json:"accounts,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+func testRecords(t *testing.T) []Record {
+	t.Helper()
+	blockTime := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	records, err := Records([]testEvent{
+		{EventType: "CounterIncrementedEvent", Slot: 18446744073709551615, Signature: "5abc", BlockTime: blockTime},
+		{EventType: "TokensMintedEvent", Slot: 7, Signature: "true", BlockTime: blockTime,
+			Accounts: []string{"mint", "owner"}, Labels: map[string]string{"z": "last", "a": "key: value"}},
+	})
+	if err != nil {
+		t.Fatalf("Records() error = %v", err)
+	}
+	return records
+}
+
+func TestRecords(t *testing.T) {
+	records := testRecords(t)
+	want := []string{"event_type", "slot", "signature", "block_time", "memo"}
+	if got := records[0].Keys; len(got) != len(want) {
+		t.Fatalf("Keys = %v, want %v", got, want)
+	}
+	for i, k := range want {
+		if records[0].Keys[i] != k {
+			t.Errorf("Keys = %v, want %v", records[0].Keys, want)
+			break
+		}
+	}
+	if got := records[0].Values["slot"]; got != json.Number("18446744073709551615") {
+		t.Errorf("slot = %v, want the exact uint64", got)
+	}
+
+	if _, err := Records([]int{1}); err == nil {
+		t.Error("Records() of non-objects error = nil")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  Format
+		columns []string
+		want    string
+	}{
+		{
+			name:    "table with columns",
+			format:  FormatTable,
+			columns: []string{"slot", "event_type", "accounts"},
+			want: "SLOT                  EVENT_TYPE               ACCOUNTS\n" +
+				"18446744073709551615  CounterIncrementedEvent  \n" +
+				"7                     TokensMintedEvent        [\"mint\",\"owner\"]\n",
+		},
+		{
+			name:    "json with columns",
+			format:  FormatJSON,
+			columns: []string{"signature", "slot"},
+			want: "[\n  {\n    \"signature\": \"5abc\",\n    \"slot\": 18446744073709551615\n  },\n" +
+				"  {\n    \"signature\": \"true\",\n    \"slot\": 7\n  }\n]\n",
+		},
+		{
+			name:   "yaml",
+			format: FormatYAML,
+			want: `- event_type: CounterIncrementedEvent
+  slot: 18446744073709551615
+  signature: 5abc
+  block_time: "2026-01-02T03:04:05Z"
+  memo: null
+- event_type: TokensMintedEvent
+  slot: 7
+  signature: "true"
+  block_time: "2026-01-02T03:04:05Z"
+  memo: null
+  accounts:
+    - mint
+    - owner
+  labels:
+    a: 'key: value'
+    z: last
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, tt.format, testRecords(t), tt.columns); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Write() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrite_UnknownColumn(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatTable, testRecords(t), []string{"slto"}); err == nil {
+		t.Error("Write() with an unknown column error = nil")
+	}
+}
+
+func TestWrite_Empty(t *testing.T) {
+	for format, want := range map[Format]string{FormatJSON: "[]\n", FormatYAML: "[]\n"} {
+		var buf bytes.Buffer
+		if err := Write(&buf, format, nil, nil); err != nil {
+			t.Fatalf("Write(%s) error = %v", format, err)
+		}
+		if buf.String() != want {
+			t.Errorf("Write(%s) = %q, want %q", format, buf.String(), want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// writeYAML writes records as a YAML sequence of mappings with the fields
+// in column order.
+func writeYAML(w io.Writer, records []Record, columns []string) error {
+	doc := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, r := range records {
+		record := &yaml.Node{Kind: yaml.MappingNode}
+		for _, k := range r.fields(columns) {
+			value, err := yamlNode(r.Values[k])
+			if err != nil {
+				return err
+			}
+			record.Content = append(record.Content, yamlKey(k), value)
+		}
+		doc.Content = append(doc.Content, record)
+	}
+	if len(records) == 0 {
+		doc.Style = yaml.FlowStyle
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func yamlKey(k string) *yaml.Node {
+	var n yaml.Node
+	n.SetString(k)
+	return &n
+}
+
+// yamlNode converts a value JSON decodes to. Mapping keys are sorted, and
+// numbers keep their digits, which float64 would not above 2^53.
+func yamlNode(v interface{}) (*yaml.Node, error) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		n := &yaml.Node{Kind: yaml.MappingNode}
+		if len(v) == 0 {
+			n.Style = yaml.FlowStyle
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			value, err := yamlNode(v[k])
+			if err != nil {
+				return nil, err
+			}
+			n.Content = append(n.Content, yamlKey(k), value)
+		}
+		return n, nil
+	case []interface{}:
+		n := &yaml.Node{Kind: yaml.SequenceNode}
+		if len(v) == 0 {
+			n.Style = yaml.FlowStyle
+		}
+		for _, item := range v {
+			value, err := yamlNode(item)
+			if err != nil {
+				return nil, err
+			}
+			n.Content = append(n.Content, value)
+		}
+		return n, nil
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(v.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: v.String()}, nil
+	default:
+		var n yaml.Node
+		if err := n.Encode(v); err != nil {
+			return nil, err
+		}
+		return &n, nil
+	}
+}
@@ -0,0 +1,85 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/failure"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+	"github.com/lugondev/go-indexer-solana-starter/internal/spool"
+)
+
+// bufferedEvent is an event as it is written to the spool.
+type bufferedEvent struct {
+	EventType models.EventType `json:"event_type"`
+	Event     json.RawMessage  `json:"event"`
+	// Log is set for a models.LogEvent, whose type a log grammar defines.
+	Log bool `json:"log,omitempty"`
+}
+
+// SetSpool buffers events in s while the database is unreachable instead of
+// failing them. Replay saves and publishes them once it is back.
+func (p *EventProcessor) SetSpool(s *spool.Spool) {
+	p.spool = s
+}
+
+func (p *EventProcessor) buffer(base models.BaseEvent, event interface{}) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encode %s for buffering: %w", base.EventType, err)
+	}
+	_, isLog := event.(*models.LogEvent)
+	record, err := json.Marshal(bufferedEvent{EventType: base.EventType, Event: data, Log: isLog})
+	if err != nil {
+		return fmt.Errorf("encode %s for buffering: %w", base.EventType, err)
+	}
+	if err := p.spool.Append(record); err != nil {
+		return &failure.StorageError{Op: "buffer " + string(base.EventType), Err: err}
+	}
+	return nil
+}
+
+// Replay saves and publishes one buffered event, failing only while the
+// database is still unreachable. The filter and identity resolution were
+// applied before it was buffered. Records that cannot be decoded, e.g. one
+// cut short by a crash, or that the database rejects are logged and
+// dropped so they do not block the rest.
+func (p *EventProcessor) Replay(ctx context.Context, record []byte) error {
+	var buffered bufferedEvent
+	if err := json.Unmarshal(record, &buffered); err != nil {
+		log.Printf("warning: dropping unreadable buffered event: %v", err)
+		return nil
+	}
+	event, ok := models.NewEventModel(buffered.EventType)
+	if buffered.Log {
+		event, ok = &models.LogEvent{}, true
+	}
+	if !ok {
+		log.Printf("warning: dropping buffered event of unknown type %s", buffered.EventType)
+		return nil
+	}
+	if err := json.Unmarshal(buffered.Event, event); err != nil {
+		log.Printf("warning: dropping unreadable buffered %s: %v", buffered.EventType, err)
+		return nil
+	}
+
+	base := *event.Base()
+	if err := p.store(ctx, base, event); err != nil {
+		if repository.IsUnavailable(err) {
+			return err
+		}
+		log.Printf("warning: dropping buffered %s %s: %v", base.EventType, base.Signature, err)
+		return nil
+	}
+	base.Sequence = event.Base().Sequence
+	p.recordRollup(base)
+	// The event is stored: retrying it for a failing sink would store it
+	// twice.
+	if err := p.publish(ctx, base, event); err != nil {
+		log.Printf("warning: buffered %s %s: %v", base.EventType, base.Signature, err)
+	}
+	return nil
+}
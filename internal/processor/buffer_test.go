@@ -0,0 +1,107 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+	"github.com/lugondev/go-indexer-solana-starter/internal/spool"
+)
+
+type flakyRepo struct {
+	repository.Repository
+	down  bool
+	saved []interface{}
+}
+
+func (r *flakyRepo) SaveEvent(ctx context.Context, event models.Event) error {
+	if r.down {
+		return &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	}
+	r.saved = append(r.saved, event)
+	return nil
+}
+
+func TestEventProcessor_BufferAndReplay(t *testing.T) {
+	ctx := context.Background()
+	counter := solana.MustPublicKeyFromBase58("4Nd1mBQtrMJVYVfKf2PJy9NZUZdTAsp7D4xWLs4gDB4T")
+	program := solana.MustPublicKeyFromBase58("CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc")
+
+	buffer, err := spool.Open(t.TempDir(), 1<<20)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	repo := &flakyRepo{down: true}
+	p := NewEventProcessor(repo, program)
+	p.SetSpool(buffer)
+
+	process := func(slot, value uint64) {
+		t.Helper()
+		event := models.CounterIncrementedEvent{Counter: counter, OldValue: value - 1, NewValue: value}
+		if err := p.ProcessEvent(ctx, "sig", slot, time.Unix(1700000000, 0), models.EventTypeCounterIncremented, event); err != nil {
+			t.Fatalf("ProcessEvent() error = %v", err)
+		}
+	}
+
+	process(10, 1)
+	process(11, 2)
+	repo.down = false
+	// Buffered events are replayed first, so later ones queue behind them.
+	process(12, 3)
+	if len(repo.saved) != 0 || buffer.Len() != 3 {
+		t.Fatalf("saved, buffered = %d, %d, want 0, 3", len(repo.saved), buffer.Len())
+	}
+
+	if n, err := buffer.Drain(ctx, p.Replay); err != nil || n != 3 {
+		t.Fatalf("Drain() = %d, %v, want 3, nil", n, err)
+	}
+	for n, saved := range repo.saved {
+		event, ok := saved.(*models.CounterIncrementedEvent)
+		if !ok {
+			t.Fatalf("saved[%d] is %T, want *models.CounterIncrementedEvent", n, saved)
+		}
+		if event.Slot != uint64(10+n) || event.NewValue != uint64(1+n) || !event.Counter.Equals(counter) || !event.ProgramID.Equals(program) {
+			t.Errorf("saved[%d] = slot %d, new value %d, counter %s, program %s", n, event.Slot, event.NewValue, event.Counter, event.ProgramID)
+		}
+	}
+
+	process(13, 4)
+	if len(repo.saved) != 4 || buffer.Len() != 0 {
+		t.Errorf("saved, buffered after replay = %d, %d, want 4, 0", len(repo.saved), buffer.Len())
+	}
+}
+
+func TestEventProcessor_ReplayLogEvent(t *testing.T) {
+	ctx := context.Background()
+	program := solana.MustPublicKeyFromBase58("CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc")
+
+	buffer, err := spool.Open(t.TempDir(), 1<<20)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	repo := &flakyRepo{down: true}
+	p := NewEventProcessor(repo, program)
+	p.SetSpool(buffer)
+
+	event := models.LogEvent{Fields: map[string]interface{}{"amount": uint64(1) << 60, "vault": "main"}}
+	if err := p.ProcessEvent(ctx, "sig", 10, time.Unix(1700000000, 0), "VaultDepositEvent", event); err != nil {
+		t.Fatalf("ProcessEvent() error = %v", err)
+	}
+	repo.down = false
+	if n, err := buffer.Drain(ctx, p.Replay); err != nil || n != 1 {
+		t.Fatalf("Drain() = %d, %v, want 1, nil", n, err)
+	}
+
+	saved, ok := repo.saved[0].(*models.LogEvent)
+	if !ok {
+		t.Fatalf("saved is %T, want *models.LogEvent", repo.saved[0])
+	}
+	if saved.EventType != "VaultDepositEvent" || saved.Fields["amount"] != int64(1)<<60 || saved.Fields["vault"] != "main" {
+		t.Errorf("saved = %s %v, want VaultDepositEvent with amount 2^60 and vault main", saved.EventType, saved.Fields)
+	}
+}
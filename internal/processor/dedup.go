@@ -0,0 +1,137 @@
+package processor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/failure"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+// DedupStrategy decides which events count as the same. Events with the
+// same content key within the dedup window are stored once.
+type DedupStrategy interface {
+	// ContentKey returns the key of event, or "" if events of its type are
+	// never collapsed.
+	ContentKey(base models.BaseEvent, event interface{}) (string, error)
+}
+
+// FieldHash is a DedupStrategy hashing chosen fields of each event type,
+// along with the type and program.
+type FieldHash struct {
+	fields map[models.EventType][]string
+}
+
+// NewFieldHash takes the bson field names to hash per event type, e.g.
+// "mint" and "amount" for TokensMintedEvent. Types without fields are not
+// deduplicated.
+func NewFieldHash(fields map[models.EventType][]string) (*FieldHash, error) {
+	h := &FieldHash{fields: make(map[models.EventType][]string, len(fields))}
+	for eventType, names := range fields {
+		model, ok := models.NewEventModel(eventType)
+		if !ok {
+			return nil, fmt.Errorf("unknown event type %q", eventType)
+		}
+		if len(names) == 0 {
+			return nil, fmt.Errorf("%s: no fields", eventType)
+		}
+		for _, name := range names {
+			if _, ok := eventField(reflect.ValueOf(model), name); !ok {
+				return nil, fmt.Errorf("%s has no field %q", eventType, name)
+			}
+		}
+		sorted := append([]string(nil), names...)
+		sort.Strings(sorted)
+		h.fields[eventType] = sorted
+	}
+	return h, nil
+}
+
+func (h *FieldHash) ContentKey(base models.BaseEvent, event interface{}) (string, error) {
+	names, ok := h.fields[base.EventType]
+	if !ok {
+		return "", nil
+	}
+
+	sum := sha256.New()
+	fmt.Fprintf(sum, "%s\x00%s", base.EventType, base.ProgramID)
+	for _, name := range names {
+		value, ok := eventField(reflect.ValueOf(event), name)
+		if !ok {
+			return "", fmt.Errorf("%s has no field %q", base.EventType, name)
+		}
+		encoded, err := json.Marshal(value.Interface())
+		if err != nil {
+			return "", fmt.Errorf("encode %s: %w", name, err)
+		}
+		fmt.Fprintf(sum, "\x00%s=%s", name, encoded)
+	}
+	return hex.EncodeToString(sum.Sum(nil)), nil
+}
+
+// eventField finds the field of an event model by its bson name, looking
+// into the embedded BaseEvent too.
+func eventField(v reflect.Value, name string) (reflect.Value, bool) {
+	for v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.Anonymous {
+			if found, ok := eventField(v.Field(i), name); ok {
+				return found, true
+			}
+			continue
+		}
+		if tag, _, _ := strings.Cut(field.Tag.Get("bson"), ","); tag == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// SetDedup collapses an event into an earlier one of the same content key,
+// stored at most window before it, instead of saving and publishing it.
+// The earlier event records the duplicate's signature.
+func (p *EventProcessor) SetDedup(strategy DedupStrategy, store repository.DedupStore, window time.Duration) {
+	p.dedup = strategy
+	p.dedupStore = store
+	p.dedupWindow = window
+}
+
+// collapse reports whether event duplicates one already stored. Otherwise
+// it sets the event's content hash so later duplicates can find it.
+func (p *EventProcessor) collapse(ctx context.Context, base *models.BaseEvent, event models.Event) (bool, error) {
+	key, err := p.dedup.ContentKey(*base, event)
+	if err != nil {
+		return false, &failure.DecodeError{EventType: base.EventType, Err: err}
+	}
+	if key == "" {
+		return false, nil
+	}
+
+	found, err := p.dedupStore.AddDuplicateSignature(ctx, base.EventType, key, base.BlockTime.Add(-p.dedupWindow), base.Signature)
+	if err != nil {
+		return false, &failure.StorageError{Op: "dedup " + string(base.EventType), Err: err}
+	}
+	if found {
+		log.Printf("collapsed %s %s into an earlier event with the same content", base.EventType, base.Signature)
+		return true, nil
+	}
+
+	base.ContentHash = key
+	event.Base().ContentHash = key
+	return false, nil
+}
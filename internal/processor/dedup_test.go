@@ -0,0 +1,93 @@
+package processor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// hashStore is an in-memory DedupStore.
+type hashStore struct {
+	events     map[string]*models.BaseEvent
+	duplicates map[string][]string
+}
+
+func (s *hashStore) AddDuplicateSignature(ctx context.Context, eventType models.EventType, contentHash string, since time.Time, signature string) (bool, error) {
+	first, ok := s.events[contentHash]
+	if !ok || first.BlockTime.Before(since) || first.Signature == signature {
+		return false, nil
+	}
+	s.duplicates[contentHash] = append(s.duplicates[contentHash], signature)
+	return true, nil
+}
+
+func TestNewFieldHash(t *testing.T) {
+	tests := []struct {
+		name    string
+		fields  map[models.EventType][]string
+		wantErr bool
+	}{
+		{"event fields", map[models.EventType][]string{models.EventTypeTokensMinted: {"mint", "recipient", "amount"}}, false},
+		{"base field", map[models.EventType][]string{models.EventTypeNftSold: {"nft_mint", "program_id"}}, false},
+		{"unknown field", map[models.EventType][]string{models.EventTypeTokensMinted: {"owner"}}, true},
+		{"unknown type", map[models.EventType][]string{"SwapEvent": {"mint"}}, true},
+		{"no fields", map[models.EventType][]string{models.EventTypeTokensMinted: nil}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewFieldHash(tt.fields); (err != nil) != tt.wantErr {
+				t.Errorf("NewFieldHash() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEventProcessor_Dedup(t *testing.T) {
+	ctx := context.Background()
+	program := solana.MustPublicKeyFromBase58("CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc")
+	mint := solana.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112")
+	wallet := solana.MustPublicKeyFromBase58("9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM")
+
+	strategy, err := NewFieldHash(map[models.EventType][]string{models.EventTypeTokensMinted: {"mint", "recipient", "amount"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &flakyRepo{}
+	store := &hashStore{events: make(map[string]*models.BaseEvent), duplicates: make(map[string][]string)}
+	p := NewEventProcessor(repo, program)
+	p.SetDedup(strategy, store, time.Minute)
+
+	start := time.Unix(1700000000, 0)
+	process := func(signature string, amount uint64, blockTime time.Time) {
+		t.Helper()
+		event := models.TokensMintedEvent{Mint: mint, Recipient: wallet, Amount: amount, Timestamp: blockTime.Unix()}
+		if err := p.ProcessEvent(ctx, signature, 1, blockTime, models.EventTypeTokensMinted, event); err != nil {
+			t.Fatalf("ProcessEvent() error = %v", err)
+		}
+		if n := len(repo.saved); n > 0 {
+			base := repo.saved[n-1].(models.Event).Base()
+			if _, ok := store.events[base.ContentHash]; !ok {
+				store.events[base.ContentHash] = base
+			}
+		}
+	}
+
+	process("first", 100, start)
+	process("retry", 100, start.Add(10*time.Second))
+	process("other amount", 200, start.Add(20*time.Second))
+	process("late retry", 100, start.Add(2*time.Minute))
+
+	if len(repo.saved) != 3 {
+		t.Fatalf("saved %d events, want 3", len(repo.saved))
+	}
+	first := repo.saved[0].(models.Event).Base()
+	if first.ContentHash == "" {
+		t.Fatal("ContentHash is empty")
+	}
+	if got := store.duplicates[first.ContentHash]; len(got) != 1 || got[0] != "retry" {
+		t.Errorf("duplicates = %v, want [retry]", got)
+	}
+}
@@ -4,26 +4,131 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"slices"
+	"sync/atomic"
 	"time"
 
 	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/failure"
+	"github.com/lugondev/go-indexer-solana-starter/internal/fieldcrypt"
+	"github.com/lugondev/go-indexer-solana-starter/internal/identity"
 	"github.com/lugondev/go-indexer-solana-starter/internal/models"
 	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+	"github.com/lugondev/go-indexer-solana-starter/internal/rollup"
+	"github.com/lugondev/go-indexer-solana-starter/internal/sink"
+	"github.com/lugondev/go-indexer-solana-starter/internal/spool"
+	"github.com/lugondev/go-indexer-solana-starter/internal/version"
 )
 
+var indexerVersion = version.Get().String()
+
 type EventProcessor struct {
-	repo      repository.Repository
-	programID solana.PublicKey
+	repo       repository.Repository
+	programID  solana.PublicKey
+	sinks      []sink.Sink
+	identities identity.Resolver
+	filter     atomic.Pointer[Filter]
+	tenant     string
+	deployment string
+	network    string
+	spool      *spool.Spool
+	rollups    *rollup.Tracker
+	transactor repository.Transactor
+	cipher     *fieldcrypt.Cipher
+
+	dedup       DedupStrategy
+	dedupStore  repository.DedupStore
+	dedupWindow time.Duration
 }
 
-func NewEventProcessor(repo repository.Repository, programID solana.PublicKey) *EventProcessor {
+func NewEventProcessor(repo repository.Repository, programID solana.PublicKey, sinks ...sink.Sink) *EventProcessor {
 	return &EventProcessor{
 		repo:      repo,
 		programID: programID,
+		sinks:     sinks,
+	}
+}
+
+// WithProgram returns a processor for another program that shares the
+// repository, sinks and settings of p, except for tenant and deployment.
+func (p *EventProcessor) WithProgram(programID solana.PublicKey) *EventProcessor {
+	c := &EventProcessor{
+		repo:        p.repo,
+		programID:   programID,
+		sinks:       p.sinks,
+		identities:  p.identities,
+		spool:       p.spool,
+		rollups:     p.rollups,
+		transactor:  p.transactor,
+		dedup:       p.dedup,
+		dedupStore:  p.dedupStore,
+		dedupWindow: p.dedupWindow,
+		network:     p.network,
+		cipher:      p.cipher,
 	}
+	c.filter.Store(p.filter.Load())
+	return c
+}
+
+// AddSink publishes the events of p, not those of the processors it was
+// derived from, to s as well.
+func (p *EventProcessor) AddSink(s sink.Sink) {
+	p.sinks = append(slices.Clip(p.sinks), s)
+}
+
+// SetRollups counts every stored event in the rollup tables.
+func (p *EventProcessor) SetRollups(t *rollup.Tracker) {
+	p.rollups = t
+}
+
+// SetTransactor saves every event in a transaction together with the
+// updates of the projections among the sinks, so they never drift from the
+// event log.
+func (p *EventProcessor) SetTransactor(t repository.Transactor) {
+	p.transactor = t
+}
+
+// SetIdentityResolver enables resolving the wallets of every event to
+// domain names before it is saved and published.
+func (p *EventProcessor) SetIdentityResolver(resolver identity.Resolver) {
+	p.identities = resolver
+}
+
+// SetFilter drops events the filter rejects instead of saving them. It may
+// be called while events are processed.
+func (p *EventProcessor) SetFilter(filter *Filter) {
+	p.filter.Store(filter)
+}
+
+// SetCipher stores the fields the cipher encrypts encrypted. Sinks still
+// receive them in plain text.
+func (p *EventProcessor) SetCipher(c *fieldcrypt.Cipher) {
+	p.cipher = c
+}
+
+// SetTenant stores every event with the tenant owning the program.
+func (p *EventProcessor) SetTenant(tenant string) {
+	p.tenant = tenant
+}
+
+// SetNetwork stores every event with the network it was indexed from.
+func (p *EventProcessor) SetNetwork(network string) {
+	p.network = network
+}
+
+// SetDeployment stores every event with the label of the program
+// deployment it comes from.
+func (p *EventProcessor) SetDeployment(label string) {
+	p.deployment = label
 }
 
 func (p *EventProcessor) ProcessEvent(ctx context.Context, signature string, slot uint64, blockTime time.Time, eventType models.EventType, eventData interface{}) error {
+	return p.ProcessRawEvent(ctx, signature, slot, blockTime, eventType, eventData, nil)
+}
+
+// ProcessRawEvent processes an event like ProcessEvent, keeping raw, the
+// payload it was decoded from, in its raw data.
+func (p *EventProcessor) ProcessRawEvent(ctx context.Context, signature string, slot uint64, blockTime time.Time, eventType models.EventType, eventData interface{}, raw []byte) error {
 	baseEvent := models.BaseEvent{
 		EventType: eventType,
 		Signature: signature,
@@ -31,6 +136,18 @@ func (p *EventProcessor) ProcessEvent(ctx context.Context, signature string, slo
 		BlockTime: blockTime,
 		ProgramID: p.programID,
 		CreatedAt: time.Now(),
+		RawData:   raw,
+
+		IndexerVersion: indexerVersion,
+		Tenant:         p.tenant,
+		Deployment:     p.deployment,
+		Network:        p.network,
+	}
+
+	if event, ok := eventData.(models.LogEvent); ok {
+		// Log grammars define their own event types.
+		event.BaseEvent = baseEvent
+		return p.save(ctx, baseEvent, &event)
 	}
 
 	switch eventType {
@@ -48,6 +165,8 @@ func (p *EventProcessor) ProcessEvent(ctx context.Context, signature string, slo
 		return p.processConfigUpdated(ctx, baseEvent, eventData)
 	case models.EventTypeNftMinted:
 		return p.processNftMinted(ctx, baseEvent, eventData)
+	case models.EventTypeNftSold:
+		return p.processNftSold(ctx, baseEvent, eventData)
 	case models.EventTypeCounterInitialized:
 		return p.processCounterInitialized(ctx, baseEvent, eventData)
 	case models.EventTypeCounterIncremented:
@@ -60,6 +179,20 @@ func (p *EventProcessor) ProcessEvent(ctx context.Context, signature string, slo
 		return p.processCounterReset(ctx, baseEvent, eventData)
 	case models.EventTypeCounterPaymentReceived:
 		return p.processCounterPaymentReceived(ctx, baseEvent, eventData)
+	case models.EventTypeSplTokenTransfer:
+		return p.processSplTokenTransfer(ctx, baseEvent, eventData)
+	case models.EventTypeSplTokenMintTo:
+		return p.processSplTokenMintTo(ctx, baseEvent, eventData)
+	case models.EventTypeSplTokenBurn:
+		return p.processSplTokenBurn(ctx, baseEvent, eventData)
+	case models.EventTypeSplTokenAccountInitialized:
+		return p.processSplTokenAccountInitialized(ctx, baseEvent, eventData)
+	case models.EventTypeSolTransfer:
+		return p.processSolTransfer(ctx, baseEvent, eventData)
+	case models.EventTypeWatchedTransaction:
+		return p.processWatchedTransaction(ctx, baseEvent, eventData)
+	case models.EventTypeProgramUpgraded:
+		return p.processProgramUpgraded(ctx, baseEvent, eventData)
 	default:
 		log.Printf("Unknown event type: %s", eventType)
 		return nil
@@ -69,91 +202,238 @@ func (p *EventProcessor) ProcessEvent(ctx context.Context, signature string, slo
 func (p *EventProcessor) processTokensMinted(ctx context.Context, base models.BaseEvent, data interface{}) error {
 	event := data.(models.TokensMintedEvent)
 	event.BaseEvent = base
-	return p.repo.SaveEvent(ctx, &event)
+	return p.save(ctx, base, &event)
 }
 
 func (p *EventProcessor) processTokensTransferred(ctx context.Context, base models.BaseEvent, data interface{}) error {
 	event := data.(models.TokensTransferredEvent)
 	event.BaseEvent = base
-	return p.repo.SaveEvent(ctx, &event)
+	return p.save(ctx, base, &event)
 }
 
 func (p *EventProcessor) processTokensBurned(ctx context.Context, base models.BaseEvent, data interface{}) error {
 	event := data.(models.TokensBurnedEvent)
 	event.BaseEvent = base
-	return p.repo.SaveEvent(ctx, &event)
+	return p.save(ctx, base, &event)
 }
 
 func (p *EventProcessor) processUserAccountCreated(ctx context.Context, base models.BaseEvent, data interface{}) error {
 	event := data.(models.UserAccountCreatedEvent)
 	event.BaseEvent = base
-	return p.repo.SaveEvent(ctx, &event)
+	return p.save(ctx, base, &event)
 }
 
 func (p *EventProcessor) processUserAccountUpdated(ctx context.Context, base models.BaseEvent, data interface{}) error {
 	event := data.(models.UserAccountUpdatedEvent)
 	event.BaseEvent = base
-	return p.repo.SaveEvent(ctx, &event)
+	return p.save(ctx, base, &event)
 }
 
 func (p *EventProcessor) processConfigUpdated(ctx context.Context, base models.BaseEvent, data interface{}) error {
 	event := data.(models.ConfigUpdatedEvent)
 	event.BaseEvent = base
-	return p.repo.SaveEvent(ctx, &event)
+	return p.save(ctx, base, &event)
 }
 
 func (p *EventProcessor) processNftMinted(ctx context.Context, base models.BaseEvent, data interface{}) error {
 	event := data.(models.NftMintedEvent)
 	event.BaseEvent = base
-	return p.repo.SaveEvent(ctx, &event)
+	return p.save(ctx, base, &event)
+}
+
+func (p *EventProcessor) processNftSold(ctx context.Context, base models.BaseEvent, data interface{}) error {
+	event := data.(models.NftSoldEvent)
+	event.BaseEvent = base
+	event.PriceSol = models.FormatSol(event.Price)
+	return p.save(ctx, base, &event)
 }
 
 func (p *EventProcessor) processCounterInitialized(ctx context.Context, base models.BaseEvent, data interface{}) error {
 	event := data.(models.CounterInitializedEvent)
 	event.BaseEvent = base
-	return p.repo.SaveEvent(ctx, &event)
+	return p.save(ctx, base, &event)
 }
 
 func (p *EventProcessor) processCounterIncremented(ctx context.Context, base models.BaseEvent, data interface{}) error {
 	event := data.(models.CounterIncrementedEvent)
 	event.BaseEvent = base
-	return p.repo.SaveEvent(ctx, &event)
+	return p.save(ctx, base, &event)
 }
 
 func (p *EventProcessor) processCounterDecremented(ctx context.Context, base models.BaseEvent, data interface{}) error {
 	event := data.(models.CounterDecrementedEvent)
 	event.BaseEvent = base
-	return p.repo.SaveEvent(ctx, &event)
+	return p.save(ctx, base, &event)
 }
 
 func (p *EventProcessor) processCounterAdded(ctx context.Context, base models.BaseEvent, data interface{}) error {
 	event := data.(models.CounterAddedEvent)
 	event.BaseEvent = base
-	return p.repo.SaveEvent(ctx, &event)
+	return p.save(ctx, base, &event)
 }
 
 func (p *EventProcessor) processCounterReset(ctx context.Context, base models.BaseEvent, data interface{}) error {
 	event := data.(models.CounterResetEvent)
 	event.BaseEvent = base
-	return p.repo.SaveEvent(ctx, &event)
+	return p.save(ctx, base, &event)
 }
 
 func (p *EventProcessor) processCounterPaymentReceived(ctx context.Context, base models.BaseEvent, data interface{}) error {
 	event := data.(models.CounterPaymentReceivedEvent)
 	event.BaseEvent = base
-	return p.repo.SaveEvent(ctx, &event)
+	event.PaymentSol = models.FormatSol(event.Payment)
+	return p.save(ctx, base, &event)
+}
+
+func (p *EventProcessor) processSplTokenTransfer(ctx context.Context, base models.BaseEvent, data interface{}) error {
+	event := data.(models.SplTokenTransferEvent)
+	event.BaseEvent = base
+	return p.save(ctx, base, &event)
+}
+
+func (p *EventProcessor) processSplTokenMintTo(ctx context.Context, base models.BaseEvent, data interface{}) error {
+	event := data.(models.SplTokenMintToEvent)
+	event.BaseEvent = base
+	return p.save(ctx, base, &event)
+}
+
+func (p *EventProcessor) processSplTokenBurn(ctx context.Context, base models.BaseEvent, data interface{}) error {
+	event := data.(models.SplTokenBurnEvent)
+	event.BaseEvent = base
+	return p.save(ctx, base, &event)
 }
 
-func (p *EventProcessor) GetEventStats(ctx context.Context, from, to time.Time) (map[models.EventType]int64, error) {
-	events, err := p.repo.GetEventsByTimeRange(ctx, from, to)
+func (p *EventProcessor) processSplTokenAccountInitialized(ctx context.Context, base models.BaseEvent, data interface{}) error {
+	event := data.(models.SplTokenAccountInitializedEvent)
+	event.BaseEvent = base
+	return p.save(ctx, base, &event)
+}
+
+func (p *EventProcessor) processSolTransfer(ctx context.Context, base models.BaseEvent, data interface{}) error {
+	event := data.(models.SolTransferEvent)
+	event.BaseEvent = base
+	event.LamportsSol = models.FormatSol(event.Lamports)
+	return p.save(ctx, base, &event)
+}
+
+func (p *EventProcessor) processWatchedTransaction(ctx context.Context, base models.BaseEvent, data interface{}) error {
+	event := data.(models.WatchedTransactionEvent)
+	event.BaseEvent = base
+	return p.save(ctx, base, &event)
+}
+
+func (p *EventProcessor) processProgramUpgraded(ctx context.Context, base models.BaseEvent, data interface{}) error {
+	event := data.(models.ProgramUpgradedEvent)
+	event.BaseEvent = base
+	return p.save(ctx, base, &event)
+}
+
+func (p *EventProcessor) save(ctx context.Context, base models.BaseEvent, event models.Event) error {
+	if !p.filter.Load().Keep(base.EventType, event) {
+		return nil
+	}
+
+	if p.identities != nil {
+		event.Base().Identities = identity.ResolveAll(ctx, p.identities, models.WalletAddresses(event))
+		base.Identities = event.Base().Identities
+	}
+
+	if p.spool != nil && p.spool.Len() > 0 {
+		// Keep buffering until the backlog is replayed, so events are
+		// stored in order and each one does not wait for a timeout.
+		return p.buffer(base, event)
+	}
+	if p.dedup != nil {
+		duplicate, err := p.collapse(ctx, &base, event)
+		if err != nil {
+			if p.spool != nil && ctx.Err() == nil && repository.IsUnavailable(err) {
+				log.Printf("warning: database unreachable, buffering events in %s: %v", p.spool.Dir(), err)
+				return p.buffer(base, event)
+			}
+			return err
+		}
+		if duplicate {
+			return nil
+		}
+	}
+	if err := p.store(ctx, base, event); err != nil {
+		if p.spool == nil || ctx.Err() != nil || !repository.IsUnavailable(err) {
+			return &failure.StorageError{Op: "save " + string(base.EventType), Err: err}
+		}
+		log.Printf("warning: database unreachable, buffering events in %s: %v", p.spool.Dir(), err)
+		return p.buffer(base, event)
+	}
+	// Numbered by the repository.
+	base.Sequence = event.Base().Sequence
+	p.recordRollup(base)
+
+	return p.publish(ctx, base, event)
+}
+
+// store saves event, and updates the projections in the same transaction
+// when a transactor is set.
+func (p *EventProcessor) store(ctx context.Context, base models.BaseEvent, event models.Event) error {
+	if p.transactor == nil {
+		return p.saveEvent(ctx, event)
+	}
+	envelope, err := models.NewEnvelope(base, event)
 	if err != nil {
-		return nil, fmt.Errorf("get events by time range: %w", err)
+		return err
 	}
+	return p.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := p.saveEvent(ctx, event); err != nil {
+			return err
+		}
+		for _, s := range p.sinks {
+			if projection, ok := s.(sink.Projection); ok {
+				if err := projection.Project(ctx, envelope); err != nil {
+					return fmt.Errorf("update projection: %w", err)
+				}
+			}
+		}
+		return nil
+	})
+}
 
-	stats := make(map[models.EventType]int64)
-	for _, event := range events {
-		stats[event.EventType]++
+// saveEvent saves event with its fields encrypted when a cipher is set.
+func (p *EventProcessor) saveEvent(ctx context.Context, event models.Event) error {
+	if p.cipher == nil {
+		return p.repo.SaveEvent(ctx, event)
+	}
+	stored, err := p.cipher.Encrypt(event)
+	if err != nil {
+		return err
+	}
+	if err := p.repo.SaveEvent(ctx, stored); err != nil {
+		return err
+	}
+	event.Base().Sequence = stored.Base().Sequence
+	return nil
+}
+
+func (p *EventProcessor) recordRollup(base models.BaseEvent) {
+	if p.rollups != nil {
+		p.rollups.Record(base)
+	}
+}
+
+func (p *EventProcessor) publish(ctx context.Context, base models.BaseEvent, event models.Event) error {
+	if len(p.sinks) == 0 {
+		return nil
+	}
+	envelope, err := models.NewEnvelope(base, event)
+	if err != nil {
+		return err
+	}
+	for _, s := range p.sinks {
+		if _, ok := s.(sink.Projection); ok && p.transactor != nil {
+			// Updated when the event was saved.
+			continue
+		}
+		if err := s.Publish(ctx, envelope); err != nil {
+			return fmt.Errorf("publish event to sink: %w", err)
+		}
 	}
 
-	return stats, nil
+	return nil
 }
@@ -0,0 +1,170 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/fieldcrypt"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type txKey struct{}
+
+// fakeTransactor marks the context of its transactions and discards the
+// events saved in failed ones.
+type fakeTransactor struct {
+	repo      *flakyRepo
+	committed int
+}
+
+func (t *fakeTransactor) SupportsTransactions() bool { return true }
+
+func (t *fakeTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	saved := len(t.repo.saved)
+	if err := fn(context.WithValue(ctx, txKey{}, true)); err != nil {
+		t.repo.saved = t.repo.saved[:saved]
+		return err
+	}
+	t.committed++
+	return nil
+}
+
+type fakeProjection struct {
+	fail                 bool
+	projected, published int
+	inTx                 bool
+}
+
+func (p *fakeProjection) Project(ctx context.Context, event models.Envelope) error {
+	p.inTx = ctx.Value(txKey{}) != nil
+	if p.fail {
+		return errors.New("write conflict")
+	}
+	p.projected++
+	return nil
+}
+
+func (p *fakeProjection) Publish(ctx context.Context, event models.Envelope) error {
+	p.published++
+	return nil
+}
+
+func (p *fakeProjection) Close(ctx context.Context) error { return nil }
+
+func TestEventProcessor_SaveWithProjections(t *testing.T) {
+	ctx := context.Background()
+	repo := &flakyRepo{}
+	tx := &fakeTransactor{repo: repo}
+	projection := &fakeProjection{}
+	p := NewEventProcessor(repo, solana.PublicKey{}, projection)
+	p.SetTransactor(tx)
+
+	event := models.CounterResetEvent{}
+	if err := p.ProcessEvent(ctx, "sig1", 1, time.Unix(1700000000, 0), models.EventTypeCounterReset, event); err != nil {
+		t.Fatalf("ProcessEvent() error = %v", err)
+	}
+	if len(repo.saved) != 1 || tx.committed != 1 || projection.projected != 1 || !projection.inTx {
+		t.Errorf("saved, committed, projected, in transaction = %d, %d, %d, %v, want 1, 1, 1, true", len(repo.saved), tx.committed, projection.projected, projection.inTx)
+	}
+	if projection.published != 0 {
+		t.Errorf("published = %d, want 0", projection.published)
+	}
+
+	projection.fail = true
+	if err := p.ProcessEvent(ctx, "sig2", 2, time.Unix(1700000000, 0), models.EventTypeCounterReset, event); err == nil {
+		t.Fatal("ProcessEvent() error = nil, want the projection error")
+	}
+	if len(repo.saved) != 1 || tx.committed != 1 {
+		t.Errorf("saved, committed after failed projection = %d, %d, want 1, 1", len(repo.saved), tx.committed)
+	}
+}
+
+type recordingSink struct {
+	published []models.Envelope
+}
+
+func (s *recordingSink) Publish(ctx context.Context, event models.Envelope) error {
+	s.published = append(s.published, event)
+	return nil
+}
+
+func (s *recordingSink) Close(ctx context.Context) error { return nil }
+
+func TestEventProcessor_EncryptedFields(t *testing.T) {
+	ctx := context.Background()
+	c, err := fieldcrypt.New([]byte("0123456789abcdef"), map[models.EventType][]string{models.EventTypeNftMinted: {"name"}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	repo := &flakyRepo{}
+	out := &recordingSink{}
+	p := NewEventProcessor(repo, solana.PublicKey{}, out)
+	p.SetCipher(c)
+
+	event := models.NftMintedEvent{Name: "alice"}
+	if err := p.ProcessEvent(ctx, "sig", 1, time.Unix(1700000000, 0), models.EventTypeNftMinted, event); err != nil {
+		t.Fatalf("ProcessEvent() error = %v", err)
+	}
+	if len(repo.saved) != 1 || !strings.HasPrefix(repo.saved[0].(*models.NftMintedEvent).Name, fieldcrypt.Prefix) {
+		t.Fatalf("saved = %+v, want the name encrypted", repo.saved)
+	}
+	if len(out.published) != 1 || !strings.Contains(string(out.published[0].Payload), `"name":"alice"`) {
+		t.Errorf("published = %s, want the name in plain text", out.published[0].Payload)
+	}
+}
+
+func TestEventProcessor_EncryptedFieldsWithRawData(t *testing.T) {
+	ctx := context.Background()
+	fields := map[models.EventType][]string{models.EventTypeNftMinted: {"name", "uri"}}
+	c, err := fieldcrypt.New([]byte("0123456789abcdef"), fields)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	repo := &flakyRepo{}
+	p := NewEventProcessor(repo, solana.PublicKey{}, &recordingSink{})
+	p.SetCipher(c)
+
+	// The Borsh payload of the event holds its strings length prefixed.
+	raw := append([]byte{5, 0, 0, 0}, "alice"...)
+	raw = append(raw, append([]byte{21, 0, 0, 0}, "https://alice.example"...)...)
+	event := models.NftMintedEvent{Name: "alice", Uri: "https://alice.example"}
+	if err := p.ProcessRawEvent(ctx, "sig", 1, time.Unix(1700000000, 0), models.EventTypeNftMinted, event, raw); err != nil {
+		t.Fatalf("ProcessRawEvent() error = %v", err)
+	}
+	if len(repo.saved) != 1 {
+		t.Fatalf("saved %d events, want 1", len(repo.saved))
+	}
+	doc, err := bson.Marshal(repo.saved[0])
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if bytes.Contains(doc, []byte("alice")) {
+		t.Errorf("stored document = %v, want no plain text", bson.Raw(doc))
+	}
+}
+
+func TestEventProcessor_ProcessRawEvent(t *testing.T) {
+	ctx := context.Background()
+	repo := &flakyRepo{}
+	out := &recordingSink{}
+	p := NewEventProcessor(repo, solana.PublicKey{}, out)
+
+	raw := []byte{0xf3, 0x2a, 1, 2, 3}
+	event := models.CounterResetEvent{OldValue: 3}
+	if err := p.ProcessRawEvent(ctx, "sig", 1, time.Unix(1700000000, 0), models.EventTypeCounterReset, event, raw); err != nil {
+		t.Fatalf("ProcessRawEvent() error = %v", err)
+	}
+	if len(repo.saved) != 1 || !reflect.DeepEqual(repo.saved[0].(*models.CounterResetEvent).RawData, raw) {
+		t.Fatalf("saved = %+v, want the raw data kept", repo.saved)
+	}
+	if len(out.published) != 1 || !strings.Contains(string(out.published[0].Payload), `"raw_data":"8yoBAgM="`) {
+		t.Errorf("published = %s, want the raw data", out.published[0].Payload)
+	}
+}
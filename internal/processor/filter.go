@@ -0,0 +1,83 @@
+package processor
+
+import (
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// Filter decides which decoded events are stored and published. Events it
+// rejects are dropped before they reach the repository or any sink.
+type Filter struct {
+	allow    map[models.EventType]bool
+	deny     map[models.EventType]bool
+	accounts map[solana.PublicKey]bool
+}
+
+// NewFilter builds a filter from event type names and base58 account
+// addresses. An empty allowlist allows every type; the denylist wins over
+// the allowlist. When accounts are given, only events that reference one of
+// them as a wallet, mint, collection or counter are kept.
+func NewFilter(allow, deny, accounts []string) (*Filter, error) {
+	f := &Filter{}
+
+	var err error
+	if f.allow, err = eventTypeSet(allow); err != nil {
+		return nil, fmt.Errorf("allowlist: %w", err)
+	}
+	if f.deny, err = eventTypeSet(deny); err != nil {
+		return nil, fmt.Errorf("denylist: %w", err)
+	}
+
+	if len(accounts) > 0 {
+		f.accounts = make(map[solana.PublicKey]bool, len(accounts))
+		for _, account := range accounts {
+			key, err := solana.PublicKeyFromBase58(account)
+			if err != nil {
+				return nil, fmt.Errorf("account %q: %w", account, err)
+			}
+			f.accounts[key] = true
+		}
+	}
+
+	return f, nil
+}
+
+func eventTypeSet(names []string) (map[models.EventType]bool, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	set := make(map[models.EventType]bool, len(names))
+	for _, name := range names {
+		eventType := models.EventType(name)
+		if !eventType.Known() {
+			return nil, fmt.Errorf("unknown event type %q", name)
+		}
+		set[eventType] = true
+	}
+	return set, nil
+}
+
+// Keep reports whether an event should be stored. A nil filter keeps
+// everything.
+func (f *Filter) Keep(eventType models.EventType, event interface{}) bool {
+	if f == nil {
+		return true
+	}
+	if f.deny[eventType] {
+		return false
+	}
+	if f.allow != nil && !f.allow[eventType] {
+		return false
+	}
+	if f.accounts == nil {
+		return true
+	}
+	for _, key := range models.AccountAddresses(event) {
+		if f.accounts[key] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+func TestFilter_Keep(t *testing.T) {
+	mint := solana.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112")
+	other := solana.MustPublicKeyFromBase58("11111111111111111111111111111111")
+	transfer := &models.TokensTransferredEvent{Mint: mint, From: other, To: other}
+
+	tests := []struct {
+		name      string
+		allow     []string
+		deny      []string
+		accounts  []string
+		eventType models.EventType
+		event     interface{}
+		want      bool
+	}{
+		{"no rules", nil, nil, nil, models.EventTypeTokensTransferred, transfer, true},
+		{"allowed", []string{"TokensTransferredEvent"}, nil, nil, models.EventTypeTokensTransferred, transfer, true},
+		{"not allowed", []string{"NftMintedEvent"}, nil, nil, models.EventTypeTokensTransferred, transfer, false},
+		{"denied", nil, []string{"TokensTransferredEvent"}, nil, models.EventTypeTokensTransferred, transfer, false},
+		{"deny wins", []string{"TokensTransferredEvent"}, []string{"TokensTransferredEvent"}, nil, models.EventTypeTokensTransferred, transfer, false},
+		{"matching mint", nil, nil, []string{mint.String()}, models.EventTypeTokensTransferred, transfer, true},
+		{"no matching account", nil, nil, []string{"TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA"}, models.EventTypeTokensTransferred, transfer, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewFilter(tt.allow, tt.deny, tt.accounts)
+			if err != nil {
+				t.Fatalf("NewFilter() error = %v", err)
+			}
+			if got := f.Keep(tt.eventType, tt.event); got != tt.want {
+				t.Errorf("Keep() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewFilter_Invalid(t *testing.T) {
+	if _, err := NewFilter([]string{"TokensMinted"}, nil, nil); err == nil {
+		t.Error("NewFilter() error = nil, want unknown event type error")
+	}
+	if _, err := NewFilter(nil, nil, []string{"not-a-key"}); err == nil {
+		t.Error("NewFilter() error = nil, want invalid account error")
+	}
+}
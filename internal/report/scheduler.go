@@ -0,0 +1,246 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/export"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+	"github.com/lugondev/go-indexer-solana-starter/internal/savedquery"
+)
+
+// errTooManyRows stops streaming once a report reaches Options.MaxRows.
+var errTooManyRows = errors.New("too many rows")
+
+// Mailer sends a message with a single attachment.
+type Mailer interface {
+	Send(ctx context.Context, to []string, subject, body, filename string, attachment []byte) error
+}
+
+// SheetWriter replaces the contents of a spreadsheet range.
+type SheetWriter interface {
+	ReplaceValues(ctx context.Context, spreadsheetID, sheetRange string, rows [][]string) error
+}
+
+type Options struct {
+	// CheckInterval is how often due reports are looked for.
+	CheckInterval time.Duration
+	// MaxRows truncates report results; the delivery notes the truncation.
+	MaxRows int
+	// Mailer and Sheets are optional. Reports that need a missing one fail
+	// with an error recorded on the report.
+	Mailer Mailer
+	Sheets SheetWriter
+	// Queries resolves reports that run a saved query; optional.
+	Queries repository.SavedQueryStore
+}
+
+// Scheduler runs saved reports when they are due and delivers the results.
+type Scheduler struct {
+	store  repository.ReportStore
+	events repository.EventStreamer
+	opts   Options
+	now    func() time.Time
+}
+
+func NewScheduler(store repository.ReportStore, events repository.EventStreamer, opts Options) *Scheduler {
+	if opts.CheckInterval <= 0 {
+		opts.CheckInterval = time.Minute
+	}
+	if opts.MaxRows <= 0 {
+		opts.MaxRows = 50000
+	}
+	return &Scheduler{
+		store:  store,
+		events: events,
+		opts:   opts,
+		now:    time.Now,
+	}
+}
+
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.opts.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.RunDue(ctx); err != nil {
+			log.Printf("error running reports: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue runs every report whose schedule has elapsed. A failing report is
+// recorded on the report and does not stop the others.
+func (s *Scheduler) RunDue(ctx context.Context) error {
+	reports, err := s.store.GetReports(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range reports {
+		report := &reports[i]
+		now := s.now()
+		if !Due(report, now) {
+			continue
+		}
+
+		var runErr string
+		if err := s.RunReport(ctx, report, now); err != nil {
+			log.Printf("warning: report %s failed: %v", report.Name, err)
+			runErr = err.Error()
+		} else {
+			log.Printf("delivered report %s", report.Name)
+		}
+		if err := s.store.RecordReportRun(ctx, report.Name, now, runErr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Due reports whether report should run at now. Reports with an invalid
+// schedule never run.
+func Due(report *models.Report, now time.Time) bool {
+	every, err := time.ParseDuration(report.Every)
+	if err != nil || every <= 0 {
+		return false
+	}
+	return report.LastRunAt == nil || !now.Before(report.LastRunAt.Add(every))
+}
+
+// RunReport queries the events in the report window ending at now and
+// delivers them to every configured destination.
+func (s *Scheduler) RunReport(ctx context.Context, report *models.Report, now time.Time) error {
+	filter, err := s.filter(ctx, report.Query, now)
+	if err != nil {
+		return err
+	}
+
+	rows := [][]string{export.CSVHeader}
+	err = s.events.StreamEvents(ctx, filter, func(event repository.ExportedEvent) error {
+		if len(rows) > s.opts.MaxRows {
+			return errTooManyRows
+		}
+		rows = append(rows, export.CSVRow(event))
+		return nil
+	})
+	truncated := errors.Is(err, errTooManyRows)
+	if err != nil && !truncated {
+		return fmt.Errorf("query events: %w", err)
+	}
+
+	var errs []error
+	if len(report.Delivery.Email) > 0 {
+		errs = append(errs, s.email(ctx, report, filter, rows, truncated))
+	}
+	if report.Delivery.SheetID != "" {
+		errs = append(errs, s.sheet(ctx, report, rows))
+	}
+	return errors.Join(errs...)
+}
+
+// Filter turns a report query into an event filter for the window ending at
+// now.
+func Filter(query models.ReportQuery, now time.Time) (repository.EventFilter, error) {
+	window, err := time.ParseDuration(query.Window)
+	if err != nil || window <= 0 {
+		return repository.EventFilter{}, fmt.Errorf("invalid window %q", query.Window)
+	}
+
+	filter := repository.EventFilter{
+		EventTypes: query.EventTypes,
+		From:       now.Add(-window),
+		To:         now,
+	}
+	if query.Account != "" {
+		account, err := solana.PublicKeyFromBase58(query.Account)
+		if err != nil {
+			return repository.EventFilter{}, fmt.Errorf("invalid account: %w", err)
+		}
+		filter.Account = &account
+	}
+	return filter, nil
+}
+
+// filter resolves the saved query a report runs, if any.
+func (s *Scheduler) filter(ctx context.Context, query models.ReportQuery, now time.Time) (repository.EventFilter, error) {
+	if query.SavedQuery == "" {
+		return Filter(query, now)
+	}
+	window, err := time.ParseDuration(query.Window)
+	if err != nil || window <= 0 {
+		return repository.EventFilter{}, fmt.Errorf("invalid window %q", query.Window)
+	}
+	if s.opts.Queries == nil {
+		return repository.EventFilter{}, errors.New("saved queries are not supported by the configured database")
+	}
+
+	saved, err := s.opts.Queries.GetQuery(ctx, query.SavedQuery)
+	if err != nil {
+		return repository.EventFilter{}, err
+	}
+	if saved == nil {
+		return repository.EventFilter{}, fmt.Errorf("saved query %s does not exist", query.SavedQuery)
+	}
+	filter, err := savedquery.Resolve(saved, query.Params, now)
+	if err != nil {
+		return repository.EventFilter{}, fmt.Errorf("saved query %s: %w", query.SavedQuery, err)
+	}
+	if filter.From.IsZero() && filter.To.IsZero() {
+		filter.From, filter.To = now.Add(-window), now
+	}
+	return filter, nil
+}
+
+func (s *Scheduler) email(ctx context.Context, report *models.Report, filter repository.EventFilter, rows [][]string, truncated bool) error {
+	if s.opts.Mailer == nil {
+		return errors.New("email delivery is not configured (SMTP_ADDR)")
+	}
+
+	var attachment bytes.Buffer
+	w := csv.NewWriter(&attachment)
+	if err := w.WriteAll(rows); err != nil {
+		return fmt.Errorf("encode csv: %w", err)
+	}
+
+	body := fmt.Sprintf("%d events between %s and %s.\n",
+		len(rows)-1, filter.From.UTC().Format(time.RFC3339), filter.To.UTC().Format(time.RFC3339))
+	if truncated {
+		body += fmt.Sprintf("The result was truncated to the first %d events.\n", s.opts.MaxRows)
+	}
+	subject := "Report " + report.Name
+	filename := fmt.Sprintf("%s-%s.csv", report.Name, filter.To.UTC().Format("20060102T150405Z"))
+
+	if err := s.opts.Mailer.Send(ctx, report.Delivery.Email, subject, body, filename, attachment.Bytes()); err != nil {
+		return fmt.Errorf("send email: %w", err)
+	}
+	return nil
+}
+
+func (s *Scheduler) sheet(ctx context.Context, report *models.Report, rows [][]string) error {
+	if s.opts.Sheets == nil {
+		return errors.New("google sheets delivery is not configured (GOOGLE_SERVICE_ACCOUNT_FILE)")
+	}
+
+	sheetRange := report.Delivery.SheetRange
+	if sheetRange == "" {
+		sheetRange = "Sheet1"
+	}
+	if err := s.opts.Sheets.ReplaceValues(ctx, report.Delivery.SheetID, sheetRange, rows); err != nil {
+		return fmt.Errorf("update sheet: %w", err)
+	}
+	return nil
+}
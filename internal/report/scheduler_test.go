@@ -0,0 +1,211 @@
+package report
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+type fakeStore struct {
+	reports []models.Report
+	runs    map[string]string
+}
+
+func (s *fakeStore) SaveReport(ctx context.Context, report *models.Report) error { return nil }
+func (s *fakeStore) GetReports(ctx context.Context) ([]models.Report, error)     { return s.reports, nil }
+func (s *fakeStore) GetReport(ctx context.Context, name string) (*models.Report, error) {
+	return nil, nil
+}
+func (s *fakeStore) DeleteReport(ctx context.Context, name string) (bool, error) { return false, nil }
+
+func (s *fakeStore) RecordReportRun(ctx context.Context, name string, at time.Time, runErr string) error {
+	s.runs[name] = runErr
+	return nil
+}
+
+type fakeEvents struct {
+	events []repository.ExportedEvent
+	filter repository.EventFilter
+}
+
+func (e *fakeEvents) StreamEvents(ctx context.Context, filter repository.EventFilter, fn func(event repository.ExportedEvent) error) error {
+	e.filter = filter
+	for _, event := range e.events {
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type fakeMailer struct {
+	to         []string
+	body       string
+	attachment string
+}
+
+func (m *fakeMailer) Send(ctx context.Context, to []string, subject, body, filename string, attachment []byte) error {
+	m.to, m.body, m.attachment = to, body, string(attachment)
+	return nil
+}
+
+type fakeSheets struct {
+	sheetRange string
+	rows       [][]string
+}
+
+func (s *fakeSheets) ReplaceValues(ctx context.Context, spreadsheetID, sheetRange string, rows [][]string) error {
+	s.sheetRange, s.rows = sheetRange, rows
+	return nil
+}
+
+func TestDue(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(-d)
+		return &t
+	}
+
+	tests := []struct {
+		name   string
+		report models.Report
+		want   bool
+	}{
+		{name: "never run", report: models.Report{Every: "24h"}, want: true},
+		{name: "interval elapsed", report: models.Report{Every: "24h", LastRunAt: at(24 * time.Hour)}, want: true},
+		{name: "interval not elapsed", report: models.Report{Every: "24h", LastRunAt: at(time.Hour)}, want: false},
+		{name: "invalid schedule", report: models.Report{Every: "daily"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Due(&tt.report, now); got != tt.want {
+				t.Errorf("Due() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduler_RunDue(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	store := &fakeStore{
+		reports: []models.Report{
+			{
+				Name:     "daily-resets",
+				Every:    "24h",
+				Query:    models.ReportQuery{EventTypes: []models.EventType{models.EventTypeCounterReset}, Window: "24h"},
+				Delivery: models.ReportDelivery{Email: []string{"ops@example.com"}, SheetID: "sheet"},
+			},
+			{
+				Name:     "sheet-only",
+				Every:    "1h",
+				Query:    models.ReportQuery{Window: "1h"},
+				Delivery: models.ReportDelivery{SheetID: "other"},
+			},
+		},
+		runs: make(map[string]string),
+	}
+	events := &fakeEvents{events: []repository.ExportedEvent{
+		{BaseEvent: models.BaseEvent{Signature: "sig-1", EventType: models.EventTypeCounterReset, Slot: 7, BlockTime: now}},
+		{BaseEvent: models.BaseEvent{Signature: "sig-2", EventType: models.EventTypeCounterReset, Slot: 8, BlockTime: now}},
+	}}
+	mailer := &fakeMailer{}
+	sheets := &fakeSheets{}
+
+	s := NewScheduler(store, events, Options{MaxRows: 1, Mailer: mailer, Sheets: sheets})
+	s.now = func() time.Time { return now }
+	if err := s.RunDue(context.Background()); err != nil {
+		t.Fatalf("RunDue() error = %v", err)
+	}
+
+	if want := now.Add(-time.Hour); !events.filter.From.Equal(want) {
+		t.Errorf("From = %v, want %v", events.filter.From, want)
+	}
+	if len(mailer.to) != 1 || !strings.Contains(mailer.body, "truncated") {
+		t.Errorf("mail to %v with body %q", mailer.to, mailer.body)
+	}
+	if lines := strings.Count(mailer.attachment, "\n"); lines != 2 {
+		t.Errorf("attachment has %d lines, want header and 1 row", lines)
+	}
+	if sheets.sheetRange != "Sheet1" || len(sheets.rows) != 2 {
+		t.Errorf("sheet range = %q with %d rows", sheets.sheetRange, len(sheets.rows))
+	}
+	for _, name := range []string{"daily-resets", "sheet-only"} {
+		if runErr, ok := store.runs[name]; !ok || runErr != "" {
+			t.Errorf("run of %s recorded = %v, error %q", name, ok, runErr)
+		}
+	}
+}
+
+func TestScheduler_MissingMailer(t *testing.T) {
+	store := &fakeStore{
+		reports: []models.Report{{
+			Name:     "weekly",
+			Every:    "168h",
+			Query:    models.ReportQuery{Window: "168h"},
+			Delivery: models.ReportDelivery{Email: []string{"ops@example.com"}},
+		}},
+		runs: make(map[string]string),
+	}
+
+	s := NewScheduler(store, &fakeEvents{}, Options{})
+	if err := s.RunDue(context.Background()); err != nil {
+		t.Fatalf("RunDue() error = %v", err)
+	}
+	if !strings.Contains(store.runs["weekly"], "SMTP_ADDR") {
+		t.Errorf("recorded error = %q, want missing SMTP configuration", store.runs["weekly"])
+	}
+}
+
+type fakeQueries struct {
+	queries map[string]models.SavedQuery
+}
+
+func (q *fakeQueries) SaveQuery(ctx context.Context, query *models.SavedQuery) error { return nil }
+func (q *fakeQueries) GetQueries(ctx context.Context) ([]models.SavedQuery, error)   { return nil, nil }
+func (q *fakeQueries) DeleteQuery(ctx context.Context, name string) (bool, error)    { return false, nil }
+
+func (q *fakeQueries) GetQuery(ctx context.Context, name string) (*models.SavedQuery, error) {
+	query, ok := q.queries[name]
+	if !ok {
+		return nil, nil
+	}
+	return &query, nil
+}
+
+func TestScheduler_SavedQuery(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	queries := &fakeQueries{queries: map[string]models.SavedQuery{
+		"by-type": {
+			Name:   "by-type",
+			Filter: models.SavedQueryFilter{EventTypes: []string{"{{type}}"}},
+			Params: []models.SavedQueryParam{{Name: "type"}},
+		},
+	}}
+	events := &fakeEvents{}
+	s := NewScheduler(&fakeStore{runs: make(map[string]string)}, events, Options{Sheets: &fakeSheets{}, Queries: queries})
+
+	report := &models.Report{
+		Name:     "resets",
+		Query:    models.ReportQuery{SavedQuery: "by-type", Params: map[string]string{"type": "CounterResetEvent"}, Window: "24h"},
+		Delivery: models.ReportDelivery{SheetID: "sheet"},
+	}
+	if err := s.RunReport(context.Background(), report, now); err != nil {
+		t.Fatalf("RunReport() error = %v", err)
+	}
+	if len(events.filter.EventTypes) != 1 || events.filter.EventTypes[0] != models.EventTypeCounterReset {
+		t.Errorf("EventTypes = %v, want [CounterResetEvent]", events.filter.EventTypes)
+	}
+	if want := now.Add(-24 * time.Hour); !events.filter.From.Equal(want) || !events.filter.To.Equal(now) {
+		t.Errorf("range = %v to %v, want the report window", events.filter.From, events.filter.To)
+	}
+
+	report.Query.SavedQuery = "missing"
+	if err := s.RunReport(context.Background(), report, now); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("RunReport() error = %v, want a missing saved query", err)
+	}
+}
@@ -0,0 +1,117 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	sheetsEndpoint = "https://sheets.googleapis.com"
+	sheetsScope    = "https://www.googleapis.com/auth/spreadsheets"
+
+	// maxCellLength is the most characters Google Sheets accepts in a cell.
+	maxCellLength = 50000
+)
+
+// SheetsClient writes values with the Google Sheets REST API, authenticated
+// as a service account. Spreadsheets must be shared with the service
+// account's email.
+type SheetsClient struct {
+	httpClient *http.Client
+	endpoint   string
+}
+
+// NewSheetsClient loads a service account key file downloaded from the
+// Google Cloud console.
+func NewSheetsClient(keyFile string) (*SheetsClient, error) {
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("read service account file: %w", err)
+	}
+	jwtConfig, err := google.JWTConfigFromJSON(data, sheetsScope)
+	if err != nil {
+		return nil, fmt.Errorf("parse service account file: %w", err)
+	}
+
+	httpClient := jwtConfig.Client(context.Background())
+	httpClient.Timeout = time.Minute
+	return &SheetsClient{
+		httpClient: httpClient,
+		endpoint:   sheetsEndpoint,
+	}, nil
+}
+
+// ReplaceValues clears sheetRange and writes rows from its top left cell.
+func (c *SheetsClient) ReplaceValues(ctx context.Context, spreadsheetID, sheetRange string, rows [][]string) error {
+	base := c.endpoint + "/v4/spreadsheets/" + url.PathEscape(spreadsheetID) + "/values/" + url.PathEscape(sheetRange)
+
+	if err := c.do(ctx, http.MethodPost, base+":clear", struct{}{}); err != nil {
+		return fmt.Errorf("clear %s: %w", sheetRange, err)
+	}
+
+	values := make([][]string, len(rows))
+	for i, row := range rows {
+		values[i] = make([]string, len(row))
+		for j, cell := range row {
+			values[i][j] = truncateCell(cell)
+		}
+	}
+	body := map[string]interface{}{
+		"range":          sheetRange,
+		"majorDimension": "ROWS",
+		"values":         values,
+	}
+	if err := c.do(ctx, http.MethodPut, base+"?valueInputOption=RAW", body); err != nil {
+		return fmt.Errorf("update %s: %w", sheetRange, err)
+	}
+	return nil
+}
+
+func (c *SheetsClient) do(ctx context.Context, method, target string, body interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, respBody)
+	}
+	return nil
+}
+
+// truncateCell cuts cell to maxCellLength characters, never inside one.
+func truncateCell(cell string) string {
+	if len(cell) <= maxCellLength {
+		return cell
+	}
+	n := 0
+	for i := range cell {
+		if n == maxCellLength {
+			return cell[:i]
+		}
+		n++
+	}
+	return cell
+}
@@ -0,0 +1,108 @@
+package report
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSheetsClient_ReplaceValues(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tokenRequests int
+	var calls []string
+	var update struct {
+		Values [][]string `json:"values"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			tokenRequests++
+			if r.FormValue("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" || strings.Count(r.FormValue("assertion"), ".") != 2 {
+				t.Errorf("token request form = %v", r.Form)
+			}
+			_, _ = w.Write([]byte(`{"access_token":"tok","expires_in":3600}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		calls = append(calls, r.Method+" "+r.URL.EscapedPath()+"?"+r.URL.RawQuery)
+		if r.Method == http.MethodPut {
+			_ = json.NewDecoder(r.Body).Decode(&update)
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	keyFile := filepath.Join(t.TempDir(), "sa.json")
+	account, _ := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "reports@project.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":    srv.URL + "/token",
+	})
+	if err := os.WriteFile(keyFile, account, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := NewSheetsClient(keyFile)
+	if err != nil {
+		t.Fatalf("NewSheetsClient() error = %v", err)
+	}
+	client.endpoint = srv.URL
+
+	rows := [][]string{{"signature"}, {strings.Repeat("x", maxCellLength+1)}}
+	if err := client.ReplaceValues(context.Background(), "abc", "Sheet1", rows); err != nil {
+		t.Fatalf("ReplaceValues() error = %v", err)
+	}
+
+	want := []string{
+		"POST /v4/spreadsheets/abc/values/Sheet1:clear?",
+		"PUT /v4/spreadsheets/abc/values/Sheet1?valueInputOption=RAW",
+	}
+	if strings.Join(calls, ",") != strings.Join(want, ",") {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+	if tokenRequests != 1 {
+		t.Errorf("token requests = %d, want 1", tokenRequests)
+	}
+	if len(update.Values) != 2 || len(update.Values[1][0]) != maxCellLength {
+		t.Errorf("update did not truncate the long cell")
+	}
+}
+
+func TestTruncateCell(t *testing.T) {
+	tests := []struct {
+		name string
+		cell string
+		want string
+	}{
+		{name: "short", cell: "abc", want: "abc"},
+		{name: "exactly the limit", cell: strings.Repeat("é", maxCellLength), want: strings.Repeat("é", maxCellLength)},
+		{name: "ascii", cell: strings.Repeat("x", maxCellLength+1), want: strings.Repeat("x", maxCellLength)},
+		{name: "multibyte", cell: "a" + strings.Repeat("é", maxCellLength), want: "a" + strings.Repeat("é", maxCellLength-1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncateCell(tt.cell); got != tt.want {
+				t.Errorf("truncateCell() = %d bytes, want %d bytes", len(got), len(tt.want))
+			}
+		})
+	}
+}
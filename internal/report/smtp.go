@@ -0,0 +1,111 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+type SMTPOptions struct {
+	// Addr is the host:port of the submission server.
+	Addr     string
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPMailer sends mail through an SMTP server, using STARTTLS when the
+// server offers it and PLAIN authentication when a username is set.
+type SMTPMailer struct {
+	opts SMTPOptions
+	now  func() time.Time
+}
+
+func NewSMTPMailer(opts SMTPOptions) (*SMTPMailer, error) {
+	if _, _, err := net.SplitHostPort(opts.Addr); err != nil {
+		return nil, fmt.Errorf("invalid smtp address %q: %w", opts.Addr, err)
+	}
+	if opts.From == "" {
+		return nil, fmt.Errorf("from address is required")
+	}
+	return &SMTPMailer{opts: opts, now: time.Now}, nil
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, to []string, subject, body, filename string, attachment []byte) error {
+	msg, err := buildMessage(m.opts.From, to, subject, body, filename, attachment, m.now())
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.opts.Username != "" {
+		host, _, _ := net.SplitHostPort(m.opts.Addr)
+		auth = smtp.PlainAuth("", m.opts.Username, m.opts.Password, host)
+	}
+
+	// net/smtp takes no context; run it aside so cancellation is not
+	// blocked by a slow server.
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(m.opts.Addr, auth, m.opts.From, to, msg)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// buildMessage encodes a multipart/mixed message with a text body and a CSV
+// attachment.
+func buildMessage(from string, to []string, subject, body, filename string, attachment []byte, date time.Time) ([]byte, error) {
+	var msg bytes.Buffer
+	mw := multipart.NewWriter(&msg)
+
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", mw.Boundary())
+
+	text, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"8bit"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := text.Write([]byte(strings.ReplaceAll(body, "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+
+	part, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {mime.FormatMediaType("text/csv", map[string]string{"name": filename})},
+		"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": filename})},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	encoded := base64.StdEncoding.EncodeToString(attachment)
+	for len(encoded) > 76 {
+		fmt.Fprintf(part, "%s\r\n", encoded[:76])
+		encoded = encoded[76:]
+	}
+	fmt.Fprintf(part, "%s\r\n", encoded)
+
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return msg.Bytes(), nil
+}
@@ -0,0 +1,54 @@
+package report
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"testing"
+	"time"
+)
+
+func TestBuildMessage(t *testing.T) {
+	attachment := bytes.Repeat([]byte("signature,slot\n"), 20)
+	raw, err := buildMessage("indexer@example.com", []string{"a@example.com", "b@example.com"},
+		"Report daily", "2 events\n", "daily.csv", attachment, time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("buildMessage() error = %v", err)
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("ReadMessage() error = %v", err)
+	}
+	to, err := msg.Header.AddressList("To")
+	if err != nil || len(to) != 2 {
+		t.Errorf("To = %v, %v", to, err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		t.Fatalf("Content-Type = %q, %v", mediaType, err)
+	}
+
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	if _, err := mr.NextPart(); err != nil {
+		t.Fatalf("text part: %v", err)
+	}
+	part, err := mr.NextPart()
+	if err != nil {
+		t.Fatalf("attachment part: %v", err)
+	}
+	if part.FileName() != "daily.csv" {
+		t.Errorf("FileName() = %q, want daily.csv", part.FileName())
+	}
+	// multipart.Reader only decodes quoted-printable parts itself.
+	decoded, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, part))
+	if err != nil {
+		t.Fatalf("decode attachment: %v", err)
+	}
+	if !bytes.Equal(decoded, attachment) {
+		t.Errorf("attachment = %q, want %q", decoded, attachment)
+	}
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// accountSampleTTL is how long account samples are kept.
+const accountSampleTTL = 90 * 24 * time.Hour
+
+// AccountSampleStore is implemented by repositories that keep the size and
+// balance history of program accounts.
+type AccountSampleStore interface {
+	SaveAccountSamples(ctx context.Context, samples []models.AccountSample) error
+	// GetAccountSamples returns the samples of address taken in [from, to),
+	// oldest first. Past limit, the newest samples are returned.
+	GetAccountSamples(ctx context.Context, address string, from, to time.Time, limit int) ([]models.AccountSample, error)
+}
+
+func (r *MongoRepository) SaveAccountSamples(ctx context.Context, samples []models.AccountSample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+	docs := make([]interface{}, len(samples))
+	for i := range samples {
+		docs[i] = samples[i]
+	}
+	if _, err := r.accountSamples.InsertMany(ctx, docs); err != nil {
+		return fmt.Errorf("save account samples: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoRepository) GetAccountSamples(ctx context.Context, address string, from, to time.Time, limit int) ([]models.AccountSample, error) {
+	filter := bson.M{
+		"address":    address,
+		"sampled_at": bson.M{"$gte": from, "$lt": to},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "sampled_at", Value: -1}}).
+		SetLimit(int64(limit))
+	cursor, err := r.accountSamples.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find account samples: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var samples []models.AccountSample
+	if err := cursor.All(ctx, &samples); err != nil {
+		return nil, fmt.Errorf("decode account samples: %w", err)
+	}
+	slices.Reverse(samples)
+	return samples, nil
+}
+
+func (r *MongoRepository) createAccountSampleIndexes(ctx context.Context) error {
+	_, err := r.accountSamples.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "address", Value: 1}, {Key: "sampled_at", Value: -1}}},
+		{
+			Keys:    bson.D{{Key: "sampled_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(accountSampleTTL.Seconds())),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("create account sample indexes: %w", err)
+	}
+	return nil
+}
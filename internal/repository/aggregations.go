@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// aggregateFieldName is the form of the event fields that can be summed,
+// as stored by both backends.
+var aggregateFieldName = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// WindowSum is the sum of a field over the events of [Start, Start+window).
+type WindowSum struct {
+	Start time.Time `bson:"_id" json:"start"`
+	// Count is the number of events with a numeric value of the field.
+	Count int64 `bson:"count" json:"count"`
+	Sum   int64 `bson:"sum" json:"sum"`
+}
+
+// WindowCount is the number of events of one type in [Start, Start+window).
+type WindowCount struct {
+	Start     time.Time        `json:"start"`
+	EventType models.EventType `json:"event_type"`
+	Count     int64            `json:"count"`
+}
+
+// EventAggregator is implemented by repositories that can aggregate events
+// in the database rather than in memory.
+type EventAggregator interface {
+	CountEventsByType(ctx context.Context, filter EventFilter) (map[models.EventType]int64, error)
+	// CountEventsByWindow counts the events matching filter by type over
+	// consecutive windows of block time, aligned to the Unix epoch, in time
+	// then type order. A window of 24h counts by UTC day.
+	CountEventsByWindow(ctx context.Context, filter EventFilter, window time.Duration) ([]WindowCount, error)
+	// SumFieldByWindow sums a numeric field of the events matching filter
+	// over consecutive windows of block time, aligned to the Unix epoch, in
+	// time order. Windows without events are left out.
+	SumFieldByWindow(ctx context.Context, filter EventFilter, field string, window time.Duration) ([]WindowSum, error)
+}
+
+func checkWindow(window time.Duration) error {
+	if window < time.Second || window%time.Second != 0 {
+		return fmt.Errorf("window must be a whole number of seconds, got %v", window)
+	}
+	return nil
+}
+
+func checkSumField(field string, window time.Duration) error {
+	if !aggregateFieldName.MatchString(field) {
+		return fmt.Errorf("invalid field %q", field)
+	}
+	return checkWindow(window)
+}
+
+// mongoWindowStart truncates the block time to the window in epoch
+// milliseconds, which unlike $dateTrunc works before MongoDB 5.0.
+func mongoWindowStart(window time.Duration) bson.M {
+	millis := bson.M{"$toLong": "$block_time"}
+	return bson.M{"$toDate": bson.M{"$subtract": bson.A{millis, bson.M{"$mod": bson.A{millis, window.Milliseconds()}}}}}
+}
+
+// sqlWindowStart truncates block_time to the window.
+func sqlWindowStart(window time.Duration) string {
+	seconds := strconv.FormatInt(int64(window/time.Second), 10)
+	return "to_timestamp(floor(extract(epoch FROM block_time) / " + seconds + ") * " + seconds + ")"
+}
+
+func (r *MongoRepository) CountEventsByType(ctx context.Context, filter EventFilter) (map[models.EventType]int64, error) {
+	names, err := r.filterCollections(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[models.EventType]int64)
+	if len(names) == 0 {
+		return counts, nil
+	}
+	cursor, err := r.aggregateEvents(ctx, names, filter.mongoFilter(), mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": "$event_type", "count": bson.M{"$sum": 1}}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("count events by type: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var row struct {
+			EventType models.EventType `bson:"_id"`
+			Count     int64            `bson:"count"`
+		}
+		if err := cursor.Decode(&row); err != nil {
+			return nil, fmt.Errorf("decode event count: %w", err)
+		}
+		counts[row.EventType] = row.Count
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("count events by type: %w", err)
+	}
+	return counts, nil
+}
+
+func (r *MongoRepository) CountEventsByWindow(ctx context.Context, filter EventFilter, window time.Duration) ([]WindowCount, error) {
+	if err := checkWindow(window); err != nil {
+		return nil, err
+	}
+	names, err := r.filterCollections(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := []WindowCount{}
+	if len(names) == 0 {
+		return counts, nil
+	}
+	cursor, err := r.aggregateEvents(ctx, names, filter.mongoFilter(), mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"start": mongoWindowStart(window), "event_type": "$event_type"},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id.start", Value: 1}, {Key: "_id.event_type", Value: 1}}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("count events by window: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var row struct {
+			ID struct {
+				Start     time.Time        `bson:"start"`
+				EventType models.EventType `bson:"event_type"`
+			} `bson:"_id"`
+			Count int64 `bson:"count"`
+		}
+		if err := cursor.Decode(&row); err != nil {
+			return nil, fmt.Errorf("decode event count: %w", err)
+		}
+		counts = append(counts, WindowCount{Start: row.ID.Start.UTC(), EventType: row.ID.EventType, Count: row.Count})
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("count events by window: %w", err)
+	}
+	return counts, nil
+}
+
+func (r *MongoRepository) SumFieldByWindow(ctx context.Context, filter EventFilter, field string, window time.Duration) ([]WindowSum, error) {
+	if err := checkSumField(field, window); err != nil {
+		return nil, err
+	}
+	names, err := r.filterCollections(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	sums := []WindowSum{}
+	if len(names) == 0 {
+		return sums, nil
+	}
+	match := bson.M{"$and": bson.A{filter.mongoFilter(), bson.M{field: bson.M{"$type": "number"}}}}
+	cursor, err := r.aggregateEvents(ctx, names, match, mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": mongoWindowStart(window), "count": bson.M{"$sum": 1}, "sum": bson.M{"$sum": "$" + field}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sum %s by window: %w", field, err)
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &sums); err != nil {
+		return nil, fmt.Errorf("decode %s sums: %w", field, err)
+	}
+	return sums, nil
+}
+
+func (r *PostgresRepository) CountEventsByType(ctx context.Context, filter EventFilter) (map[models.EventType]int64, error) {
+	var q sqlQuery
+	filter.sqlFilter(&q)
+	q.tenant(ctx)
+
+	rows, err := r.pool.Query(ctx, "SELECT event_type, COUNT(*) FROM events"+q.where()+" GROUP BY event_type", q.args...)
+	if err != nil {
+		return nil, fmt.Errorf("count events by type: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[models.EventType]int64)
+	for rows.Next() {
+		var (
+			eventType string
+			count     int64
+		)
+		if err := rows.Scan(&eventType, &count); err != nil {
+			return nil, fmt.Errorf("scan event count: %w", err)
+		}
+		counts[models.EventType(eventType)] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("count events by type: %w", err)
+	}
+	return counts, nil
+}
+
+func (r *PostgresRepository) CountEventsByWindow(ctx context.Context, filter EventFilter, window time.Duration) ([]WindowCount, error) {
+	if err := checkWindow(window); err != nil {
+		return nil, err
+	}
+	var q sqlQuery
+	filter.sqlFilter(&q)
+	q.tenant(ctx)
+
+	rows, err := r.pool.Query(ctx, "SELECT "+sqlWindowStart(window)+" AS start, event_type, COUNT(*) FROM events"+q.where()+
+		" GROUP BY start, event_type ORDER BY start, event_type", q.args...)
+	if err != nil {
+		return nil, fmt.Errorf("count events by window: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []WindowCount{}
+	for rows.Next() {
+		var (
+			c         WindowCount
+			eventType string
+		)
+		if err := rows.Scan(&c.Start, &eventType, &c.Count); err != nil {
+			return nil, fmt.Errorf("scan event count: %w", err)
+		}
+		c.Start, c.EventType = c.Start.UTC(), models.EventType(eventType)
+		counts = append(counts, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("count events by window: %w", err)
+	}
+	return counts, nil
+}
+
+func (r *PostgresRepository) SumFieldByWindow(ctx context.Context, filter EventFilter, field string, window time.Duration) ([]WindowSum, error) {
+	if err := checkSumField(field, window); err != nil {
+		return nil, err
+	}
+	var q sqlQuery
+	filter.sqlFilter(&q)
+	q.tenant(ctx)
+	q.conds = append(q.conds, "jsonb_typeof(event_data->'"+field+"') = 'number'")
+
+	rows, err := r.pool.Query(ctx, "SELECT "+sqlWindowStart(window)+" AS start, "+
+		"COUNT(*), SUM((event_data->>'"+field+"')::numeric)::bigint FROM events"+q.where()+" GROUP BY start ORDER BY start", q.args...)
+	if err != nil {
+		return nil, fmt.Errorf("sum %s by window: %w", field, err)
+	}
+	defer rows.Close()
+
+	sums := []WindowSum{}
+	for rows.Next() {
+		var s WindowSum
+		if err := rows.Scan(&s.Start, &s.Count, &s.Sum); err != nil {
+			return nil, fmt.Errorf("scan %s sum: %w", field, err)
+		}
+		s.Start = s.Start.UTC()
+		sums = append(sums, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("sum %s by window: %w", field, err)
+	}
+	return sums, nil
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// The block time methods make MongoRepository the persistent store of the
+// RPC client's slot to block time cache.
+
+type blockTimeDocument struct {
+	Slot      int64 `bson:"_id"`
+	BlockTime int64 `bson:"block_time"`
+}
+
+func (r *MongoRepository) GetBlockTime(ctx context.Context, slot uint64) (int64, bool, error) {
+	var doc blockTimeDocument
+	err := r.blockTimes.FindOne(ctx, bson.M{"_id": int64(slot)}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("find block time: %w", err)
+	}
+	return doc.BlockTime, true, nil
+}
+
+func (r *MongoRepository) SaveBlockTime(ctx context.Context, slot uint64, blockTime int64) error {
+	doc := blockTimeDocument{Slot: int64(slot), BlockTime: blockTime}
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.blockTimes.ReplaceOne(ctx, bson.M{"_id": doc.Slot}, doc, opts); err != nil {
+		return fmt.Errorf("save block time: %w", err)
+	}
+	return nil
+}
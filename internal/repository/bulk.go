@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// BatchSaver is implemented by repositories that save many events at once
+// faster than one by one, e.g. the batches of a backfill.
+type BatchSaver interface {
+	SaveEvents(ctx context.Context, events []models.Event) error
+}
+
+// defaultCopyThreshold is the batch size from which SaveEvents uses COPY
+// when PostgresOptions.CopyThreshold is not set.
+const defaultCopyThreshold = 100
+
+// eventColumns are the columns of the events table written for an event.
+var eventColumns = []string{"event_type", "signature", "slot", "block_time", "program_id", "raw_data", "event_data", "sequence"}
+
+// insertEventSQL inserts the values of eventColumns but the sequence, which
+// it takes from the counter of the program on network $8 in the same
+// statement.
+const insertEventSQL = `
+WITH seq AS (
+	INSERT INTO event_sequences (network, program_id, value) VALUES ($8, $5, 1)
+	ON CONFLICT (network, program_id) DO UPDATE SET value = event_sequences.value + 1
+	RETURNING value
+)
+INSERT INTO events (event_type, signature, slot, block_time, program_id, raw_data, event_data, sequence)
+SELECT $1, $2, $3::bigint, $4::timestamp, $5, $6::jsonb, $7::jsonb, value FROM seq
+RETURNING sequence`
+
+// reserveSequencesSQL reserves $3 sequence numbers of program $2 on network
+// $1 and returns the last.
+const reserveSequencesSQL = `
+INSERT INTO event_sequences (network, program_id, value) VALUES ($1, $2, $3)
+ON CONFLICT (network, program_id) DO UPDATE SET value = event_sequences.value + EXCLUDED.value
+RETURNING value`
+
+// eventRow returns the values of eventColumns for event, with its raw data
+// compressed with c.
+func eventRow(event models.Event, c RawDataCompression) ([]interface{}, error) {
+	restore, err := compressRawData(event, c)
+	if err != nil {
+		return nil, err
+	}
+	defer restore()
+
+	base := event.Base()
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("encode %s: %w", base.EventType, err)
+	}
+	var raw interface{}
+	if len(base.RawData) > 0 {
+		encoded, err := json.Marshal(base.RawData)
+		if err != nil {
+			return nil, fmt.Errorf("encode %s raw data: %w", base.EventType, err)
+		}
+		raw = json.RawMessage(encoded)
+	}
+	return []interface{}{
+		string(base.EventType),
+		base.Signature,
+		int64(base.Slot),
+		base.BlockTime.UTC(),
+		base.ProgramID.String(),
+		raw,
+		json.RawMessage(data),
+		int64(base.Sequence),
+	}, nil
+}
+
+// SaveEvents inserts events one by one, or with a single COPY once there
+// are at least CopyThreshold of them. A COPY is all or nothing: one event
+// the table rejects, such as a duplicate, fails the whole batch, and the
+// sequence numbers reserved for it with it.
+func (r *PostgresRepository) SaveEvents(ctx context.Context, events []models.Event) error {
+	threshold := r.opts.CopyThreshold
+	if threshold <= 0 {
+		threshold = defaultCopyThreshold
+	}
+	if len(events) < threshold {
+		for _, event := range events {
+			if err := r.SaveEvent(ctx, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	counts := make(map[sequenceCounter]uint64)
+	for _, event := range events {
+		if err := r.EnsurePartitions(ctx, event.Base().Slot); err != nil {
+			return err
+		}
+		counts[counterOf(event)]++
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Counters are locked in a fixed order, so concurrent batches cannot
+	// deadlock.
+	byID := func(a, b sequenceCounter) int { return strings.Compare(a.id(), b.id()) }
+	next := make(map[sequenceCounter]uint64, len(counts))
+	for _, counter := range slices.SortedFunc(maps.Keys(counts), byID) {
+		var last int64
+		if err := tx.QueryRow(ctx, reserveSequencesSQL, counter.network, counter.program.String(), int64(counts[counter])).Scan(&last); err != nil {
+			return fmt.Errorf("reserve sequences of %s: %w", counter, err)
+		}
+		next[counter] = uint64(last) - counts[counter] + 1
+	}
+
+	rows := make([][]interface{}, 0, len(events))
+	for _, event := range events {
+		counter := counterOf(event)
+		event.Base().Sequence = next[counter]
+		next[counter]++
+		row, err := eventRow(event, r.opts.RawDataCompression)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, row)
+	}
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"events"}, eventColumns, pgx.CopyFromRows(rows)); err != nil {
+		return fmt.Errorf("copy %d events: %w", len(rows), err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit %d events: %w", len(rows), err)
+	}
+	return nil
+}
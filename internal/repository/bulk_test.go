@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+func TestEventRow(t *testing.T) {
+	blockTime := time.Date(2026, 1, 2, 3, 4, 5, 0, time.FixedZone("", 3600))
+	event := &models.CounterIncrementedEvent{
+		BaseEvent: models.BaseEvent{
+			EventType: models.EventTypeCounterIncremented,
+			Signature: "sig1",
+			Slot:      42,
+			BlockTime: blockTime,
+		},
+		NewValue: 7,
+	}
+
+	row, err := eventRow(event, RawDataUncompressed)
+	if err != nil {
+		t.Fatalf("eventRow() error = %v", err)
+	}
+	if len(row) != len(eventColumns) {
+		t.Fatalf("eventRow() has %d values, want %d", len(row), len(eventColumns))
+	}
+	if got := row[2]; got != int64(42) {
+		t.Errorf("eventRow() slot = %v, want 42", got)
+	}
+	if got := row[3].(time.Time); got.Location() != time.UTC || !got.Equal(blockTime) {
+		t.Errorf("eventRow() block_time = %v, want %v in UTC", got, blockTime)
+	}
+	if row[5] != nil {
+		t.Errorf("eventRow() raw_data = %v, want nil", row[5])
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(row[6].(json.RawMessage), &data); err != nil {
+		t.Fatalf("eventRow() event_data is not JSON: %v", err)
+	}
+	if data["signature"] != "sig1" || data["new_value"] != float64(7) {
+		t.Errorf("eventRow() event_data = %v", data)
+	}
+
+	event.RawData = []byte{1, 2}
+	row, err = eventRow(event, RawDataUncompressed)
+	if err != nil {
+		t.Fatalf("eventRow() error = %v", err)
+	}
+	if got := string(row[5].(json.RawMessage)); got != `"AQI="` {
+		t.Errorf("eventRow() raw_data = %s, want \"AQI=\"", got)
+	}
+
+	row, err = eventRow(event, RawDataZstd)
+	if err != nil {
+		t.Fatalf("eventRow() error = %v", err)
+	}
+	if err := json.Unmarshal(row[6].(json.RawMessage), &data); err != nil {
+		t.Fatalf("eventRow() event_data is not JSON: %v", err)
+	}
+	if data["raw_data_encoding"] != "zstd" {
+		t.Errorf("eventRow() event_data raw_data_encoding = %v, want zstd", data["raw_data_encoding"])
+	}
+	if err := decompressRawData(data); err != nil || data["raw_data"] != "AQI=" {
+		t.Errorf("decompressRawData() raw_data = %v, %v, want AQI=", data["raw_data"], err)
+	}
+	if string(event.RawData) != "\x01\x02" || event.RawDataEncoding != "" {
+		t.Errorf("eventRow() left the event's raw data compressed")
+	}
+}
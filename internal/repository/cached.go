@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Cache is the key/value store used by CachedRepository.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Incr(ctx context.Context, key string) (int64, error)
+}
+
+type CacheOptions struct {
+	KeyPrefix string
+	// EventTTL applies to single events looked up by signature. Events are
+	// immutable once indexed, so this can be long.
+	EventTTL time.Duration
+	// LatestTTL bounds how long a latest-events list may be served after a
+	// generation bump was missed.
+	LatestTTL time.Duration
+}
+
+// CachedRepository serves hot read queries from a cache and falls back to
+// the wrapped repository. Latest-events lists are keyed by a per-type
+// generation counter that is bumped on every write, so new events are
+// visible immediately across all indexer instances sharing the cache.
+type CachedRepository struct {
+	Repository
+	cache Cache
+	opts  CacheOptions
+}
+
+func NewCachedRepository(repo Repository, cache Cache, opts CacheOptions) *CachedRepository {
+	if opts.KeyPrefix == "" {
+		opts.KeyPrefix = "solana_indexer"
+	}
+	if opts.EventTTL <= 0 {
+		opts.EventTTL = time.Hour
+	}
+	if opts.LatestTTL <= 0 {
+		opts.LatestTTL = 2 * time.Second
+	}
+	return &CachedRepository{
+		Repository: repo,
+		cache:      cache,
+		opts:       opts,
+	}
+}
+
+func (r *CachedRepository) Unwrap() Repository {
+	return r.Repository
+}
+
+func (r *CachedRepository) SaveEvent(ctx context.Context, event models.Event) error {
+	if err := r.Repository.SaveEvent(ctx, event); err != nil {
+		return err
+	}
+
+	if _, err := r.cache.Incr(ctx, r.generationKey(event.Base().EventType)); err != nil {
+		log.Printf("warning: failed to bump cache generation: %v", err)
+	}
+	return nil
+}
+
+func (r *CachedRepository) GetEventBySignature(ctx context.Context, signature string) (interface{}, error) {
+	key := fmt.Sprintf("%s:event:%s", r.opts.KeyPrefix, signature) + tenantKeySuffix(ctx)
+
+	var cached struct {
+		Event bson.Raw `bson:"event"`
+	}
+	if r.load(ctx, key, &cached) {
+		if event, err := DecodeEvent(cached.Event); err == nil {
+			return event, nil
+		}
+	}
+
+	event, err := r.Repository.GetEventBySignature(ctx, signature)
+	if err != nil || event == nil {
+		return event, err
+	}
+
+	r.store(ctx, key, bson.M{"event": event}, r.opts.EventTTL)
+	return event, nil
+}
+
+// GetEventsByType caches the first page of the newest events; later pages,
+// ascending and deployment reads go to the database.
+func (r *CachedRepository) GetEventsByType(ctx context.Context, eventType models.EventType, page PageOptions) (*EventPage, error) {
+	if page.After != nil || page.Ascending || page.Deployment != "" || page.Network != "" {
+		return r.Repository.GetEventsByType(ctx, eventType, page)
+	}
+
+	generation, _, err := r.cache.Get(ctx, r.generationKey(eventType))
+	if err != nil {
+		log.Printf("warning: cache read failed: %v", err)
+		return r.Repository.GetEventsByType(ctx, eventType, page)
+	}
+	key := fmt.Sprintf("%s:latest:%s:%s:%d", r.opts.KeyPrefix, eventType, generation, page.Limit) + tenantKeySuffix(ctx)
+
+	var cached struct {
+		Events []bson.Raw `bson:"events"`
+		Next   *Cursor    `bson:"next"`
+	}
+	if r.load(ctx, key, &cached) {
+		if events, err := decodeEvents(cached.Events); err == nil {
+			return &EventPage{Events: events, Next: cached.Next}, nil
+		}
+	}
+
+	result, err := r.Repository.GetEventsByType(ctx, eventType, page)
+	if err != nil {
+		return nil, err
+	}
+
+	r.store(ctx, key, bson.M{"events": result.Events, "next": result.Next}, r.opts.LatestTTL)
+	return result, nil
+}
+
+func decodeEvents(docs []bson.Raw) ([]interface{}, error) {
+	events := make([]interface{}, 0, len(docs))
+	for _, doc := range docs {
+		event, err := DecodeEvent(doc)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// tenantKeySuffix keeps the cached results of tenant scoped reads apart
+// from unscoped ones and from other tenants'.
+func tenantKeySuffix(ctx context.Context) string {
+	if tenant := TenantFromContext(ctx); tenant != "" {
+		return ":tenant:" + tenant
+	}
+	return ""
+}
+
+func (r *CachedRepository) generationKey(eventType models.EventType) string {
+	return fmt.Sprintf("%s:latest:%s:gen", r.opts.KeyPrefix, eventType)
+}
+
+// load decodes a cached BSON document into out. Cache failures are logged
+// and treated as misses so the database remains the source of truth.
+func (r *CachedRepository) load(ctx context.Context, key string, out interface{}) bool {
+	data, ok, err := r.cache.Get(ctx, key)
+	if err != nil {
+		log.Printf("warning: cache read failed: %v", err)
+		return false
+	}
+	if !ok {
+		return false
+	}
+	if err := bson.Unmarshal(data, out); err != nil {
+		log.Printf("warning: cache decode failed for %s: %v", key, err)
+		return false
+	}
+	return true
+}
+
+func (r *CachedRepository) store(ctx context.Context, key string, doc interface{}, ttl time.Duration) {
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		log.Printf("warning: cache encode failed for %s: %v", key, err)
+		return
+	}
+	if err := r.cache.Set(ctx, key, data, ttl); err != nil {
+		log.Printf("warning: cache write failed: %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CheckpointStore is implemented by repositories that can persist how far
+// each program has been indexed, so a restart resumes instead of starting
+// over.
+type CheckpointStore interface {
+	// GetCheckpoint returns nil when the program has no checkpoint.
+	GetCheckpoint(ctx context.Context, program string) (*models.Checkpoint, error)
+	SaveCheckpoint(ctx context.Context, checkpoint *models.Checkpoint) error
+}
+
+func (r *MongoRepository) GetCheckpoint(ctx context.Context, program string) (*models.Checkpoint, error) {
+	var checkpoint models.Checkpoint
+	err := r.checkpoints.FindOne(ctx, bson.M{"_id": program}).Decode(&checkpoint)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find checkpoint: %w", err)
+	}
+	return &checkpoint, nil
+}
+
+func (r *MongoRepository) SaveCheckpoint(ctx context.Context, checkpoint *models.Checkpoint) error {
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.checkpoints.ReplaceOne(ctx, bson.M{"_id": checkpoint.Program}, checkpoint, opts); err != nil {
+		return fmt.Errorf("save checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// CounterPaymentStore is implemented by repositories that list the
+// payments received by the counter program, e.g. for payment analytics.
+type CounterPaymentStore interface {
+	// GetCounterPayments returns the payments with a block time in
+	// [from, to], oldest first.
+	GetCounterPayments(ctx context.Context, from, to time.Time) ([]models.CounterPaymentReceivedEvent, error)
+}
+
+func (r *MongoRepository) GetCounterPayments(ctx context.Context, from, to time.Time) ([]models.CounterPaymentReceivedEvent, error) {
+	filter := bson.M{
+		"event_type": models.EventTypeCounterPaymentReceived,
+		"block_time": bson.M{
+			"$gte": from,
+			"$lte": to,
+		},
+	}
+	sortBy := bson.D{{Key: "block_time", Value: 1}}
+
+	names, err := r.eventCollections(ctx, models.EventTypeCounterPaymentReceived)
+	if err != nil {
+		return nil, err
+	}
+
+	var payments []models.CounterPaymentReceivedEvent
+	if err := r.findEvents(ctx, names, filter, sortBy, 0, &payments); err != nil {
+		return nil, fmt.Errorf("find counter payments: %w", err)
+	}
+
+	return payments, nil
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CoverageStore is implemented by repositories that can keep the daily
+// decoder coverage.
+type CoverageStore interface {
+	// AddDecoderCoverage adds coverage to the totals of day.
+	AddDecoderCoverage(ctx context.Context, day string, coverage models.DecoderCoverage) error
+	// GetDecoderCoverage returns the days from from to to, inclusive, oldest
+	// first.
+	GetDecoderCoverage(ctx context.Context, from, to string) ([]models.DailyDecoderCoverage, error)
+}
+
+func (r *MongoRepository) AddDecoderCoverage(ctx context.Context, day string, coverage models.DecoderCoverage) error {
+	update := bson.M{
+		"$inc": bson.M{
+			"transactions": int64(coverage.Transactions),
+			"found":        int64(coverage.Found),
+			"decoded":      int64(coverage.Decoded),
+			"unknown":      int64(coverage.Unknown),
+			"failed":       int64(coverage.Failed),
+		},
+		"$set": bson.M{"updated_at": time.Now().UTC()},
+	}
+	opts := options.Update().SetUpsert(true)
+	if _, err := r.decoderCoverage.UpdateOne(ctx, bson.M{"_id": day}, update, opts); err != nil {
+		return fmt.Errorf("add decoder coverage: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoRepository) GetDecoderCoverage(ctx context.Context, from, to string) ([]models.DailyDecoderCoverage, error) {
+	filter := bson.M{"_id": bson.M{"$gte": from, "$lte": to}}
+	cursor, err := r.decoderCoverage.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("find decoder coverage: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var days []models.DailyDecoderCoverage
+	if err := cursor.All(ctx, &days); err != nil {
+		return nil, fmt.Errorf("decode decoder coverage: %w", err)
+	}
+	return days, nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DeadLetterStore is implemented by repositories that can keep the
+// transactions the pipeline failed on.
+type DeadLetterStore interface {
+	// SaveDeadLetter records a failure, counting it as another attempt
+	// when the transaction already failed before.
+	SaveDeadLetter(ctx context.Context, letter *models.DeadLetter) error
+	// GetDeadLetters returns the most recently failed transactions first,
+	// of one kind unless kind is empty.
+	GetDeadLetters(ctx context.Context, kind string, limit int) ([]models.DeadLetter, error)
+}
+
+func (r *MongoRepository) SaveDeadLetter(ctx context.Context, letter *models.DeadLetter) error {
+	update := bson.M{
+		"$set": bson.M{
+			"program":        letter.Program,
+			"slot":           letter.Slot,
+			"kind":           letter.Kind,
+			"error":          letter.Error,
+			"last_failed_at": letter.LastFailedAt,
+		},
+		"$setOnInsert": bson.M{"first_failed_at": letter.LastFailedAt},
+		"$inc":         bson.M{"attempts": 1},
+	}
+	opts := options.Update().SetUpsert(true)
+	if _, err := r.deadLetters.UpdateOne(ctx, bson.M{"_id": letter.Signature}, update, opts); err != nil {
+		return fmt.Errorf("save dead letter: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoRepository) GetDeadLetters(ctx context.Context, kind string, limit int) ([]models.DeadLetter, error) {
+	filter := bson.M{}
+	if kind != "" {
+		filter["kind"] = kind
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "last_failed_at", Value: -1}}).
+		SetLimit(int64(limit))
+	cursor, err := r.deadLetters.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find dead letters: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var letters []models.DeadLetter
+	if err := cursor.All(ctx, &letters); err != nil {
+		return nil, fmt.Errorf("decode dead letters: %w", err)
+	}
+	return letters, nil
+}
+
+func (r *MongoRepository) createDeadLetterIndexes(ctx context.Context) error {
+	_, err := r.deadLetters.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "kind", Value: 1}, {Key: "last_failed_at", Value: -1}},
+	})
+	if err != nil {
+		return fmt.Errorf("create dead letter indexes: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// DedupStore is implemented by repositories that can collapse events with
+// the same content into the first one stored.
+type DedupStore interface {
+	// AddDuplicateSignature records signature on the event of eventType
+	// with contentHash from another transaction, with a block time at or
+	// after since. It returns false if there is no such event.
+	AddDuplicateSignature(ctx context.Context, eventType models.EventType, contentHash string, since time.Time, signature string) (bool, error)
+}
+
+func (r *MongoRepository) AddDuplicateSignature(ctx context.Context, eventType models.EventType, contentHash string, since time.Time, signature string) (bool, error) {
+	names, err := r.eventCollections(ctx, eventType)
+	if err != nil {
+		return false, err
+	}
+
+	filter := tenantFilter(ctx, bson.M{
+		"event_type":   eventType,
+		"content_hash": contentHash,
+		"block_time":   bson.M{"$gte": since},
+		"signature":    bson.M{"$ne": signature},
+	})
+	update := bson.M{"$addToSet": bson.M{"duplicate_signatures": signature}}
+	for _, name := range names {
+		result, err := r.database.Collection(name).UpdateOne(ctx, filter, update)
+		if err != nil {
+			return false, fmt.Errorf("add duplicate signature: %w", err)
+		}
+		if result.MatchedCount > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// ExportedEvent is an event read for export, together with the full stored
+// document encoded as JSON.
+type ExportedEvent struct {
+	models.BaseEvent
+	Document []byte
+}
+
+// ColdSource is implemented by repositories that can move events out to cold
+// storage. ExportEvents hands events with a block time before the cutoff to
+// export in batches of up to batchSize, oldest first, and deletes each batch
+// only after export returns nil. It returns the number of events moved.
+type ColdSource interface {
+	ExportEvents(ctx context.Context, before time.Time, batchSize int, export func(ctx context.Context, events []ExportedEvent) error) (int64, error)
+}
@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// HotStore is the Redis subset used by HotRepository.
+type HotStore interface {
+	Append(ctx context.Context, key string, value []byte) error
+	PExpire(ctx context.Context, key string, ttl time.Duration) error
+	MGet(ctx context.Context, keys ...string) ([][]byte, error)
+	ZAdd(ctx context.Context, key string, score float64, member string) error
+	ZRevRangeByLex(ctx context.Context, key, max, min string, count int) ([]string, error)
+	ZRemRangeByLex(ctx context.Context, key, min, max string) error
+}
+
+// hotSlotDuration is the nominal slot time, used to estimate how many
+// slots the window spans when trimming the index.
+const hotSlotDuration = 400 * time.Millisecond
+
+// hotTrimInterval is how often the index of an event type is trimmed.
+const hotTrimInterval = 10 * time.Second
+
+type HotOptions struct {
+	KeyPrefix string
+	// Window is how recent an event must be, by block time, to be kept in
+	// the hot tier.
+	Window time.Duration
+}
+
+// HotRepository keeps the events of the last Window in Redis and serves
+// the newest-first pages of GetEventsByType from there, falling back to the
+// wrapped repository for older ranges.
+//
+// Each event type has a sorted set of positions, "<slot>:<signature>" with
+// the slot zero padded so lexical order is slot order, and each position a
+// key holding the BSON documents of its events, expiring when the position
+// leaves the window. A page is served from Redis only if it is full and
+// the position after it is still in the window, so a page never ends where
+// the hot tier does.
+type HotRepository struct {
+	Repository
+	store HotStore
+	opts  HotOptions
+
+	mu       sync.Mutex
+	trimmed  map[models.EventType]time.Time
+	maxSlots map[models.EventType]uint64
+}
+
+func NewHotRepository(repo Repository, store HotStore, opts HotOptions) *HotRepository {
+	if opts.KeyPrefix == "" {
+		opts.KeyPrefix = "solana_indexer"
+	}
+	return &HotRepository{
+		Repository: repo,
+		store:      store,
+		opts:       opts,
+		trimmed:    make(map[models.EventType]time.Time),
+		maxSlots:   make(map[models.EventType]uint64),
+	}
+}
+
+func (r *HotRepository) Unwrap() Repository {
+	return r.Repository
+}
+
+// SaveEvent saves the event and, if its block time is within the window,
+// adds it to the hot tier. Hot tier failures are logged: the database
+// remains the source of truth.
+func (r *HotRepository) SaveEvent(ctx context.Context, event models.Event) error {
+	if err := r.Repository.SaveEvent(ctx, event); err != nil {
+		return err
+	}
+
+	base := event.Base()
+	ttl := r.opts.Window - time.Since(base.BlockTime)
+	if base.Tenant != "" || ttl <= 0 {
+		return nil
+	}
+	if err := r.add(ctx, base, event, ttl); err != nil {
+		log.Printf("warning: failed to add event %s to the hot tier: %v", base.Signature, err)
+	}
+	return nil
+}
+
+func (r *HotRepository) add(ctx context.Context, base *models.BaseEvent, event interface{}, ttl time.Duration) error {
+	data, err := bson.Marshal(bson.M{"event": event})
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+
+	position := hotPosition(Cursor{Slot: base.Slot, Signature: base.Signature})
+	key := r.eventsKey(base.EventType, position)
+	if err := r.store.Append(ctx, key, data); err != nil {
+		return err
+	}
+	if err := r.store.PExpire(ctx, key, ttl); err != nil {
+		return err
+	}
+	if err := r.store.ZAdd(ctx, r.indexKey(base.EventType), 0, position); err != nil {
+		return err
+	}
+
+	if minSlot, ok := r.trimSlot(base.EventType, base.Slot); ok {
+		if err := r.store.ZRemRangeByLex(ctx, r.indexKey(base.EventType), "-", "("+hotPosition(Cursor{Slot: minSlot})); err != nil {
+			return fmt.Errorf("trim index: %w", err)
+		}
+	}
+	return nil
+}
+
+// trimSlot returns the slot below which positions are dropped from the
+// index of eventType, at most once per hotTrimInterval. It allows for
+// twice the window in slots; reads stop at expired positions regardless.
+func (r *HotRepository) trimSlot(eventType models.EventType, slot uint64) (uint64, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if slot > r.maxSlots[eventType] {
+		r.maxSlots[eventType] = slot
+	}
+	if time.Since(r.trimmed[eventType]) < hotTrimInterval {
+		return 0, false
+	}
+	r.trimmed[eventType] = time.Now()
+
+	span := uint64(2 * r.opts.Window / hotSlotDuration)
+	if r.maxSlots[eventType] <= span {
+		return 0, false
+	}
+	return r.maxSlots[eventType] - span, true
+}
+
+// GetEventsByType serves newest-first pages from the hot tier when it
+// holds the whole page.
+func (r *HotRepository) GetEventsByType(ctx context.Context, eventType models.EventType, page PageOptions) (*EventPage, error) {
+	if page.Ascending || page.Limit <= 0 || page.Deployment != "" || page.Network != "" || TenantFromContext(ctx) != "" {
+		return r.Repository.GetEventsByType(ctx, eventType, page)
+	}
+
+	result, ok, err := r.hotPage(ctx, eventType, page)
+	if err != nil {
+		log.Printf("warning: hot tier read failed: %v", err)
+	}
+	if !ok {
+		return r.Repository.GetEventsByType(ctx, eventType, page)
+	}
+	return result, nil
+}
+
+func (r *HotRepository) hotPage(ctx context.Context, eventType models.EventType, page PageOptions) (*EventPage, bool, error) {
+	upper := "+"
+	if page.After != nil {
+		upper = "(" + hotPosition(*page.After)
+	}
+	// Every position holds at least one event, so Limit+1 positions are
+	// enough to fill the page and tell whether there is a next one.
+	positions, err := r.store.ZRevRangeByLex(ctx, r.indexKey(eventType), upper, "-", page.Limit+1)
+	if err != nil || len(positions) == 0 {
+		return nil, false, err
+	}
+
+	keys := make([]string, len(positions))
+	for n, position := range positions {
+		keys[n] = r.eventsKey(eventType, position)
+	}
+	docs, err := r.store.MGet(ctx, keys...)
+	if err != nil {
+		return nil, false, err
+	}
+
+	result := &EventPage{Events: []interface{}{}}
+	var last Cursor
+	for n, position := range positions {
+		if docs[n] == nil {
+			// The position left the window.
+			return nil, false, nil
+		}
+		cursor, err := parseHotPosition(position)
+		if err != nil {
+			return nil, false, err
+		}
+		events, err := decodeHotEvents(docs[n])
+		if err != nil {
+			return nil, false, fmt.Errorf("decode %s: %w", keys[n], err)
+		}
+		for _, event := range events {
+			if len(result.Events) == page.Limit {
+				result.Next = &last
+				return result, true, nil
+			}
+			result.Events = append(result.Events, event)
+			last = cursor
+		}
+	}
+	return nil, false, nil
+}
+
+func (r *HotRepository) indexKey(eventType models.EventType) string {
+	return fmt.Sprintf("%s:hot:%s", r.opts.KeyPrefix, eventType)
+}
+
+func (r *HotRepository) eventsKey(eventType models.EventType, position string) string {
+	return fmt.Sprintf("%s:hot:%s:%s", r.opts.KeyPrefix, eventType, position)
+}
+
+func hotPosition(c Cursor) string {
+	return fmt.Sprintf("%020d:%s", c.Slot, c.Signature)
+}
+
+func parseHotPosition(position string) (Cursor, error) {
+	var c Cursor
+	if len(position) < 21 || position[20] != ':' {
+		return c, fmt.Errorf("invalid hot tier position %q", position)
+	}
+	if _, err := fmt.Sscanf(position[:20], "%d", &c.Slot); err != nil {
+		return c, fmt.Errorf("invalid hot tier position %q", position)
+	}
+	c.Signature = position[21:]
+	return c, nil
+}
+
+// decodeHotEvents splits the BSON documents appended to a position key.
+// Each document starts with its length.
+func decodeHotEvents(data []byte) ([]interface{}, error) {
+	var events []interface{}
+	for len(data) > 0 {
+		if len(data) < 4 {
+			return nil, fmt.Errorf("truncated document")
+		}
+		size := int(binary.LittleEndian.Uint32(data))
+		if size < 5 || size > len(data) {
+			return nil, fmt.Errorf("truncated document")
+		}
+		var doc struct {
+			Event bson.Raw `bson:"event"`
+		}
+		if err := bson.Unmarshal(data[:size], &doc); err != nil {
+			return nil, err
+		}
+		event, err := DecodeEvent(doc.Event)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+		data = data[size:]
+	}
+	return events, nil
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// memHotStore keeps every sorted set member at score 0, like HotRepository.
+type memHotStore struct {
+	values map[string][]byte
+	sets   map[string][]string
+}
+
+func newMemHotStore() *memHotStore {
+	return &memHotStore{values: make(map[string][]byte), sets: make(map[string][]string)}
+}
+
+func (m *memHotStore) Append(ctx context.Context, key string, value []byte) error {
+	m.values[key] = append(m.values[key], value...)
+	return nil
+}
+
+func (m *memHotStore) PExpire(ctx context.Context, key string, ttl time.Duration) error {
+	return nil
+}
+
+func (m *memHotStore) MGet(ctx context.Context, keys ...string) ([][]byte, error) {
+	values := make([][]byte, len(keys))
+	for n, key := range keys {
+		values[n] = m.values[key]
+	}
+	return values, nil
+}
+
+func (m *memHotStore) ZAdd(ctx context.Context, key string, score float64, member string) error {
+	for _, existing := range m.sets[key] {
+		if existing == member {
+			return nil
+		}
+	}
+	m.sets[key] = append(m.sets[key], member)
+	sort.Strings(m.sets[key])
+	return nil
+}
+
+func (m *memHotStore) ZRevRangeByLex(ctx context.Context, key, max, min string, count int) ([]string, error) {
+	var members []string
+	set := m.sets[key]
+	for n := len(set) - 1; n >= 0 && len(members) < count; n-- {
+		if max == "+" || set[n] < strings.TrimPrefix(max, "(") {
+			members = append(members, set[n])
+		}
+	}
+	return members, nil
+}
+
+func (m *memHotStore) ZRemRangeByLex(ctx context.Context, key, min, max string) error {
+	var kept []string
+	for _, member := range m.sets[key] {
+		if member >= strings.TrimPrefix(max, "(") {
+			kept = append(kept, member)
+		}
+	}
+	m.sets[key] = kept
+	return nil
+}
+
+// pageRepository counts the reads that reach the database.
+type pageRepository struct {
+	Repository
+	reads int
+}
+
+func (r *pageRepository) SaveEvent(ctx context.Context, event models.Event) error {
+	return nil
+}
+
+func (r *pageRepository) GetEventsByType(ctx context.Context, eventType models.EventType, page PageOptions) (*EventPage, error) {
+	r.reads++
+	return &EventPage{}, nil
+}
+
+func TestHotRepository_GetEventsByType(t *testing.T) {
+	ctx := context.Background()
+	db := &pageRepository{}
+	store := newMemHotStore()
+	hot := NewHotRepository(db, store, HotOptions{Window: 10 * time.Minute})
+
+	save := func(slot uint64, signature string, blockTime time.Time) {
+		event := &models.CounterResetEvent{BaseEvent: models.BaseEvent{
+			EventType: models.EventTypeCounterReset,
+			Signature: signature,
+			Slot:      slot,
+			BlockTime: blockTime,
+		}}
+		if err := hot.SaveEvent(ctx, event); err != nil {
+			t.Fatalf("SaveEvent() error = %v", err)
+		}
+	}
+	save(100, "old", time.Now().Add(-time.Hour)) // outside the window
+	save(101, "a", time.Now())
+	save(102, "b", time.Now())
+	save(102, "b", time.Now()) // a second event of the same transaction
+	save(103, "c", time.Now())
+
+	tests := []struct {
+		name       string
+		page       PageOptions
+		wantEvents int
+		wantNext   *Cursor
+		wantReads  int
+	}{
+		{"first page", PageOptions{Limit: 2}, 2, &Cursor{Slot: 102, Signature: "b"}, 0},
+		{"after cursor", PageOptions{Limit: 1, After: &Cursor{Slot: 102, Signature: "b"}}, 0, nil, 1},
+		{"page reaching the end of the window", PageOptions{Limit: 4}, 0, nil, 1},
+		{"ascending", PageOptions{Limit: 1, Ascending: true}, 0, nil, 1},
+		{"deployment", PageOptions{Limit: 1, Deployment: "devnet"}, 0, nil, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db.reads = 0
+			got, err := hot.GetEventsByType(ctx, models.EventTypeCounterReset, tt.page)
+			if err != nil {
+				t.Fatalf("GetEventsByType() error = %v", err)
+			}
+			if db.reads != tt.wantReads {
+				t.Errorf("database reads = %d, want %d", db.reads, tt.wantReads)
+			}
+			if len(got.Events) != tt.wantEvents {
+				t.Errorf("len(Events) = %d, want %d", len(got.Events), tt.wantEvents)
+			}
+			if (got.Next == nil) != (tt.wantNext == nil) || (got.Next != nil && *got.Next != *tt.wantNext) {
+				t.Errorf("Next = %v, want %v", got.Next, tt.wantNext)
+			}
+		})
+	}
+}
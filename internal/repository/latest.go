@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// LatestReader is implemented by repositories that list the newest events
+// of every type, e.g. for a recent activity feed.
+type LatestReader interface {
+	// GetLatestEvents returns one page of the events of every type ordered
+	// by slot, sequence and signature; page.BySequence is implied.
+	GetLatestEvents(ctx context.Context, page PageOptions) (*EventPage, error)
+}
+
+func (r *MongoRepository) GetLatestEvents(ctx context.Context, page PageOptions) (*EventPage, error) {
+	names, err := r.eventCollections(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	page.BySequence = true
+	result, err := r.findPage(ctx, names, bson.M{}, page, DecodeEvent)
+	if err != nil {
+		return nil, fmt.Errorf("find latest events: %w", err)
+	}
+	return result, nil
+}
+
+func (r *PostgresRepository) GetLatestEvents(ctx context.Context, page PageOptions) (*EventPage, error) {
+	page.BySequence = true
+	result, err := r.QueryEvents(ctx, EventQuery{Page: page})
+	if err != nil {
+		return nil, fmt.Errorf("find latest events: %w", err)
+	}
+	return result, nil
+}
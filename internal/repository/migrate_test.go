@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "ordered by version",
+			files: fstest.MapFS{
+				"m/0002_add_index.sql":    {Data: []byte("CREATE INDEX")},
+				"m/0001_create_table.sql": {Data: []byte("CREATE TABLE")},
+				"m/README.md":             {Data: []byte("ignored")},
+			},
+			want: []string{"create_table", "add_index"},
+		},
+		{
+			name: "gap in versions",
+			files: fstest.MapFS{
+				"m/0001_create_table.sql": {Data: []byte("")},
+				"m/0003_add_index.sql":    {Data: []byte("")},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate version",
+			files:   fstest.MapFS{"m/0001_a.sql": {}, "m/0001_b.sql": {}},
+			wantErr: true,
+		},
+		{
+			name:    "missing version prefix",
+			files:   fstest.MapFS{"m/create_table.sql": {}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := loadMigrations(tt.files, "m")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadMigrations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(migrations) != len(tt.want) {
+				t.Fatalf("loadMigrations() returned %d migrations, want %d", len(migrations), len(tt.want))
+			}
+			for i, m := range migrations {
+				if m.Version != i+1 || m.Name != tt.want[i] {
+					t.Errorf("migration %d = %d_%s, want %d_%s", i, m.Version, m.Name, i+1, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPostgresMigrationsLoad(t *testing.T) {
+	migrations, err := loadMigrations(postgresMigrations, "migrations/postgres")
+	if err != nil {
+		t.Fatalf("embedded migrations: %v", err)
+	}
+	if len(migrations) == 0 || migrations[0].Name != "create_events" {
+		t.Errorf("first migration = %+v, want create_events", migrations)
+	}
+}
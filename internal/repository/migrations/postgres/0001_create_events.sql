@@ -0,0 +1,18 @@
+-- Matches the schema databases created before versioned migrations already
+-- have, so they adopt the migration history without changes.
+CREATE TABLE IF NOT EXISTS events (
+	id SERIAL PRIMARY KEY,
+	event_type VARCHAR(100) NOT NULL,
+	signature VARCHAR(255) UNIQUE NOT NULL,
+	slot BIGINT NOT NULL,
+	block_time TIMESTAMP NOT NULL,
+	program_id VARCHAR(44) NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	raw_data JSONB,
+	event_data JSONB NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_events_event_type ON events(event_type);
+CREATE INDEX IF NOT EXISTS idx_events_block_time ON events(block_time DESC);
+CREATE INDEX IF NOT EXISTS idx_events_slot ON events(slot DESC);
+CREATE INDEX IF NOT EXISTS idx_events_program_id ON events(program_id);
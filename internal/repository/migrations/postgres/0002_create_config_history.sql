@@ -0,0 +1,14 @@
+-- Program config projection, one row per observed change.
+CREATE TABLE config_history (
+	id BIGSERIAL PRIMARY KEY,
+	program_id VARCHAR(44) NOT NULL,
+	source VARCHAR(100) NOT NULL,
+	signature VARCHAR(255),
+	slot BIGINT NOT NULL,
+	block_time TIMESTAMPTZ NOT NULL,
+	changed_fields TEXT[] NOT NULL DEFAULT '{}',
+	config JSONB NOT NULL,
+	recorded_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_config_history_program_slot ON config_history(program_id, slot DESC);
@@ -0,0 +1,8 @@
+-- Last processed signature per program, so a restart resumes where the
+-- previous run stopped.
+CREATE TABLE checkpoints (
+	program_id VARCHAR(44) PRIMARY KEY,
+	last_signature VARCHAR(255) NOT NULL,
+	last_slot BIGINT NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
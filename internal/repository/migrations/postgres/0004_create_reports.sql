@@ -0,0 +1,10 @@
+-- Scheduled reports saved through the API.
+CREATE TABLE reports (
+	name VARCHAR(64) PRIMARY KEY,
+	query JSONB NOT NULL,
+	every VARCHAR(32) NOT NULL,
+	delivery JSONB NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	last_run_at TIMESTAMPTZ,
+	last_error TEXT
+);
@@ -0,0 +1,2 @@
+-- Event queries page in (slot, signature) order.
+CREATE INDEX idx_events_slot_signature ON events(slot DESC, signature DESC);
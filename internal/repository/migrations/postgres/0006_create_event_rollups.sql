@@ -0,0 +1,19 @@
+-- Events stored per minute and per hour of block time by type and program,
+-- kept by the processor for dashboards.
+CREATE TABLE event_rollups_minute (
+	bucket TIMESTAMPTZ NOT NULL,
+	event_type VARCHAR(100) NOT NULL,
+	program_id VARCHAR(44) NOT NULL,
+	count BIGINT NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	PRIMARY KEY (bucket, event_type, program_id)
+);
+
+CREATE TABLE event_rollups_hour (
+	bucket TIMESTAMPTZ NOT NULL,
+	event_type VARCHAR(100) NOT NULL,
+	program_id VARCHAR(44) NOT NULL,
+	count BIGINT NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	PRIMARY KEY (bucket, event_type, program_id)
+);
@@ -0,0 +1,10 @@
+-- Events are numbered per program as they are stored, so change consumers
+-- can resume from the last sequence they read.
+ALTER TABLE events ADD COLUMN sequence BIGINT;
+
+CREATE INDEX idx_events_program_sequence ON events(program_id, sequence) WHERE sequence IS NOT NULL;
+
+CREATE TABLE event_sequences (
+	program_id VARCHAR(44) PRIMARY KEY,
+	value BIGINT NOT NULL
+);
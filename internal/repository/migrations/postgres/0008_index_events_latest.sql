@@ -0,0 +1,2 @@
+-- The latest events page in (slot, sequence, signature) order.
+CREATE INDEX idx_events_latest ON events(slot DESC, COALESCE(sequence, 0) DESC, signature DESC);
@@ -0,0 +1,6 @@
+-- Networks indexed into the same database number the events of a program
+-- separately.
+ALTER TABLE event_sequences ADD COLUMN network VARCHAR(64) NOT NULL DEFAULT '';
+
+ALTER TABLE event_sequences DROP CONSTRAINT event_sequences_pkey;
+ALTER TABLE event_sequences ADD PRIMARY KEY (network, program_id);
@@ -2,22 +2,75 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"slices"
+	"sync"
 	"time"
 
+	"github.com/gagliardetto/solana-go"
 	"github.com/lugondev/go-indexer-solana-starter/internal/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+type MongoOptions struct {
+	// Layout defaults to MongoLayoutSingle.
+	Layout MongoLayout
+	// Collections routes event types to collections of their own,
+	// overriding Layout.
+	Collections map[models.EventType]string
+	// Indexes are created for event types on top of the standard indexes.
+	Indexes map[models.EventType][]IndexSpec
+	// RawDataCompression compresses the raw data of the events saved.
+	RawDataCompression RawDataCompression
+}
+
 type MongoRepository struct {
-	client     *mongo.Client
-	database   *mongo.Database
-	collection *mongo.Collection
+	client          *mongo.Client
+	database        *mongo.Database
+	configHistory   *mongo.Collection
+	reports         *mongo.Collection
+	savedQueries    *mongo.Collection
+	checkpoints     *mongo.Collection
+	watermarks      *mongo.Collection
+	watchlist       *mongo.Collection
+	blockTimes      *mongo.Collection
+	deadLetters     *mongo.Collection
+	decoderCoverage *mongo.Collection
+	nfts            *mongo.Collection
+	nftMints        *mongo.Collection
+	nftSales        *mongo.Collection
+	nftSalesStats   *mongo.Collection
+	tokenMovements  *mongo.Collection
+	tokenHolders    *mongo.Collection
+	tokenSupplies   *mongo.Collection
+	tokenAccounts   *mongo.Collection
+	outbox          *mongo.Collection
+	accountSamples  *mongo.Collection
+	sequences       *mongo.Collection
+	watermark       *sequenceWatermark
+	layout          MongoLayout
+	collections     map[models.EventType]string
+	indexes         map[models.EventType][]IndexSpec
+	indexed         sync.Map
+	// transactions is set on replica sets and sharded clusters.
+	transactions bool
+	rawData      RawDataCompression
 }
 
-func NewMongoRepository(uri, dbName string) (*MongoRepository, error) {
+func NewMongoRepository(uri, dbName string, opts MongoOptions) (*MongoRepository, error) {
+	if opts.Layout == "" {
+		opts.Layout = MongoLayoutSingle
+	}
+	for eventType, name := range opts.Collections {
+		if err := ValidateCollectionName(name); err != nil {
+			return nil, fmt.Errorf("collection for %s: %w", eventType, err)
+		}
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -30,24 +83,82 @@ func NewMongoRepository(uri, dbName string) (*MongoRepository, error) {
 		return nil, fmt.Errorf("ping mongodb: %w", err)
 	}
 
+	transactions, err := detectTransactions(ctx, client)
+	if err != nil {
+		log.Printf("warning: assuming mongodb does not support transactions: %v", err)
+	}
+
 	database := client.Database(dbName)
-	collection := database.Collection("events")
 
 	return &MongoRepository{
-		client:     client,
-		database:   database,
-		collection: collection,
+		client:          client,
+		database:        database,
+		configHistory:   database.Collection("config_history"),
+		reports:         database.Collection("reports"),
+		savedQueries:    database.Collection("saved_queries"),
+		checkpoints:     database.Collection("checkpoints"),
+		watermarks:      database.Collection("watermarks"),
+		watchlist:       database.Collection("watchlist"),
+		blockTimes:      database.Collection("block_times"),
+		deadLetters:     database.Collection("dead_letters"),
+		decoderCoverage: database.Collection("decoder_coverage"),
+		nfts:            database.Collection("nft_metadata"),
+		nftMints:        database.Collection("nft_mints"),
+		nftSales:        database.Collection("nft_sales"),
+		nftSalesStats:   database.Collection("nft_sales_stats"),
+		tokenMovements:  database.Collection("token_movements"),
+		tokenHolders:    database.Collection("token_holders"),
+		tokenSupplies:   database.Collection("token_supplies"),
+		tokenAccounts:   database.Collection("token_accounts"),
+		outbox:          database.Collection("outbox"),
+		accountSamples:  database.Collection("account_samples"),
+		sequences:       database.Collection("event_sequences"),
+		watermark:       newSequenceWatermark(),
+		layout:          opts.Layout,
+		collections:     opts.Collections,
+		indexes:         opts.Indexes,
+		transactions:    transactions,
+		rawData:         opts.RawDataCompression,
 	}, nil
 }
 
-func (r *MongoRepository) SaveEvent(ctx context.Context, event interface{}) error {
-	_, err := r.collection.InsertOne(ctx, event)
+func (r *MongoRepository) SaveEvent(ctx context.Context, event models.Event) error {
+	name, err := r.eventCollectionFor(ctx, event)
+	if err != nil {
+		return err
+	}
+
+	done, err := r.assignSequence(ctx, event)
+	if err != nil {
+		return err
+	}
+	defer done()
+	restore, err := compressRawData(event, r.rawData)
+	if err != nil {
+		return err
+	}
+	defer restore()
+	_, err = r.database.Collection(name).InsertOne(ctx, event)
 	if err != nil {
 		return fmt.Errorf("insert event: %w", err)
 	}
 	return nil
 }
 
+// eventCollectionFor returns the collection event is stored in, creating
+// its indexes on first use.
+func (r *MongoRepository) eventCollectionFor(ctx context.Context, event models.Event) (string, error) {
+	if r.layout == MongoLayoutSingle && len(r.collections) == 0 && len(r.indexes) == 0 {
+		return eventsCollection, nil
+	}
+	name := r.collectionName(event.Base().EventType, event.Base().ProgramID)
+	// Indexes cannot be built on existing collections in a transaction.
+	if err := r.ensureIndexes(sessionless{ctx}, name); err != nil {
+		log.Printf("warning: %v", err)
+	}
+	return name, nil
+}
+
 func (r *MongoRepository) GetEventsByTimeRange(ctx context.Context, from, to time.Time) ([]models.BaseEvent, error) {
 	filter := bson.M{
 		"block_time": bson.M{
@@ -56,50 +167,361 @@ func (r *MongoRepository) GetEventsByTimeRange(ctx context.Context, from, to tim
 		},
 	}
 
-	cursor, err := r.collection.Find(ctx, filter)
+	names, err := r.eventCollections(ctx, "")
 	if err != nil {
-		return nil, fmt.Errorf("find events: %w", err)
+		return nil, err
 	}
-	defer cursor.Close(ctx)
 
 	var events []models.BaseEvent
-	if err := cursor.All(ctx, &events); err != nil {
-		return nil, fmt.Errorf("decode events: %w", err)
+	if err := r.findEvents(ctx, names, filter, nil, 0, &events); err != nil {
+		return nil, fmt.Errorf("find events: %w", err)
 	}
 
 	return events, nil
 }
 
-func (r *MongoRepository) GetEventsByType(ctx context.Context, eventType models.EventType, limit int) ([]interface{}, error) {
-	filter := bson.M{"event_type": eventType}
-	opts := options.Find().SetLimit(int64(limit)).SetSort(bson.D{{Key: "block_time", Value: -1}})
+func (r *MongoRepository) GetEventsByType(ctx context.Context, eventType models.EventType, page PageOptions) (*EventPage, error) {
+	names, err := r.eventCollections(ctx, eventType)
+	if err != nil {
+		return nil, err
+	}
 
-	cursor, err := r.collection.Find(ctx, filter, opts)
+	result, err := r.findPage(ctx, names, bson.M{"event_type": eventType}, page, DecodeEvent)
 	if err != nil {
 		return nil, fmt.Errorf("find events by type: %w", err)
 	}
+	return result, nil
+}
+
+func (r *MongoRepository) GetEventBySignature(ctx context.Context, signature string) (interface{}, error) {
+	filter := bson.M{"signature": signature}
+
+	names, err := r.eventCollections(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var events []bson.Raw
+	if err := r.findEvents(ctx, names, filter, nil, 1, &events); err != nil {
+		return nil, fmt.Errorf("find event by signature: %w", err)
+	}
+	if len(events) == 0 {
+		return nil, nil
+	}
+
+	return DecodeEvent(events[0])
+}
+
+func (r *MongoRepository) GetEventsByAccount(ctx context.Context, account solana.PublicKey, opts AccountEventsOptions) (*EventPage, error) {
+	eventFilter := EventFilter{EventTypes: opts.EventTypes, Account: &account}
+	names, err := r.filterCollections(ctx, eventFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.findPage(ctx, names, eventFilter.mongoFilter(), opts.PageOptions, DecodeEvent)
+	if err != nil {
+		return nil, fmt.Errorf("find events by account: %w", err)
+	}
+	return result, nil
+}
+
+// SaveConfigChange records a config change. Saving the same change twice,
+// e.g. when a transaction is re-indexed, is a no-op.
+func (r *MongoRepository) SaveConfigChange(ctx context.Context, change *models.ConfigChange) error {
+	filter := bson.M{
+		"program_id": change.ProgramID,
+		"source":     change.Source,
+		"signature":  change.Signature,
+		"slot":       change.Slot,
+	}
+	update := bson.M{"$setOnInsert": change}
+	opts := options.Update().SetUpsert(true)
+
+	if _, err := r.configHistory.UpdateOne(ctx, filter, update, opts); err != nil {
+		return fmt.Errorf("save config change: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoRepository) GetConfigHistory(ctx context.Context, limit int) ([]models.ConfigChange, error) {
+	opts := options.Find().
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "slot", Value: -1}, {Key: "recorded_at", Value: -1}})
+
+	cursor, err := r.configHistory.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find config history: %w", err)
+	}
 	defer cursor.Close(ctx)
 
-	var events []interface{}
-	if err := cursor.All(ctx, &events); err != nil {
-		return nil, fmt.Errorf("decode events: %w", err)
+	var changes []models.ConfigChange
+	if err := cursor.All(ctx, &changes); err != nil {
+		return nil, fmt.Errorf("decode config history: %w", err)
 	}
 
-	return events, nil
+	return changes, nil
 }
 
-func (r *MongoRepository) GetEventBySignature(ctx context.Context, signature string) (interface{}, error) {
-	filter := bson.M{"signature": signature}
+func (r *MongoRepository) SaveReport(ctx context.Context, report *models.Report) error {
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.reports.ReplaceOne(ctx, bson.M{"_id": report.Name}, report, opts); err != nil {
+		return fmt.Errorf("save report: %w", err)
+	}
+	return nil
+}
 
-	var event interface{}
-	if err := r.collection.FindOne(ctx, filter).Decode(&event); err != nil {
-		if err == mongo.ErrNoDocuments {
-			return nil, nil
+func (r *MongoRepository) GetReports(ctx context.Context) ([]models.Report, error) {
+	cursor, err := r.reports.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("find reports: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var reports []models.Report
+	if err := cursor.All(ctx, &reports); err != nil {
+		return nil, fmt.Errorf("decode reports: %w", err)
+	}
+	return reports, nil
+}
+
+func (r *MongoRepository) GetReport(ctx context.Context, name string) (*models.Report, error) {
+	var report models.Report
+	err := r.reports.FindOne(ctx, bson.M{"_id": name}).Decode(&report)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find report: %w", err)
+	}
+	return &report, nil
+}
+
+func (r *MongoRepository) DeleteReport(ctx context.Context, name string) (bool, error) {
+	result, err := r.reports.DeleteOne(ctx, bson.M{"_id": name})
+	if err != nil {
+		return false, fmt.Errorf("delete report: %w", err)
+	}
+	return result.DeletedCount > 0, nil
+}
+
+func (r *MongoRepository) RecordReportRun(ctx context.Context, name string, at time.Time, runErr string) error {
+	update := bson.M{"$set": bson.M{"last_run_at": at, "last_error": runErr}}
+	if _, err := r.reports.UpdateOne(ctx, bson.M{"_id": name}, update); err != nil {
+		return fmt.Errorf("record report run: %w", err)
+	}
+	return nil
+}
+
+// PruneEvents deletes events that have outlived policy, copying them into
+// "archive_<collection>" first when policy.Archive is set.
+func (r *MongoRepository) PruneEvents(ctx context.Context, policy RetentionPolicy, now time.Time) (int64, error) {
+	rules := policy.rules(now)
+	if len(rules) == 0 {
+		return 0, nil
+	}
+
+	names, err := r.eventCollections(ctx, "")
+	if err != nil {
+		return 0, err
+	}
+
+	var deleted int64
+	for _, name := range names {
+		collection := r.database.Collection(name)
+		for _, rule := range rules {
+			filter := bson.M{"block_time": bson.M{"$lt": rule.Before}}
+			if rule.EventType != "" {
+				filter["event_type"] = rule.EventType
+			} else if len(rule.Exclude) > 0 {
+				filter["event_type"] = bson.M{"$nin": rule.Exclude}
+			}
+
+			if policy.Archive {
+				pipeline := mongo.Pipeline{
+					{{Key: "$match", Value: filter}},
+					{{Key: "$merge", Value: bson.M{"into": "archive_" + name, "whenMatched": "keepExisting"}}},
+				}
+				cursor, err := collection.Aggregate(ctx, pipeline)
+				if err != nil {
+					return deleted, fmt.Errorf("archive expired events from %s: %w", name, err)
+				}
+				cursor.Close(ctx)
+			}
+
+			result, err := collection.DeleteMany(ctx, filter)
+			if err != nil {
+				return deleted, fmt.Errorf("delete expired events from %s: %w", name, err)
+			}
+			deleted += result.DeletedCount
 		}
-		return nil, fmt.Errorf("find event by signature: %w", err)
 	}
 
-	return event, nil
+	return deleted, nil
+}
+
+func (r *MongoRepository) ExportEvents(ctx context.Context, before time.Time, batchSize int, export func(ctx context.Context, events []ExportedEvent) error) (int64, error) {
+	names, err := r.eventCollections(ctx, "")
+	if err != nil {
+		return 0, err
+	}
+
+	filter := bson.M{"block_time": bson.M{"$lt": before}}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "block_time", Value: 1}}).
+		SetLimit(int64(batchSize))
+
+	var exported int64
+	for _, name := range names {
+		collection := r.database.Collection(name)
+		for {
+			cursor, err := collection.Find(ctx, filter, opts)
+			if err != nil {
+				return exported, fmt.Errorf("find events to export from %s: %w", name, err)
+			}
+			var docs []bson.Raw
+			err = cursor.All(ctx, &docs)
+			cursor.Close(ctx)
+			if err != nil {
+				return exported, fmt.Errorf("decode events to export from %s: %w", name, err)
+			}
+			if len(docs) == 0 {
+				break
+			}
+
+			events := make([]ExportedEvent, len(docs))
+			ids := make([]interface{}, len(docs))
+			for j, doc := range docs {
+				if err := bson.Unmarshal(doc, &events[j].BaseEvent); err != nil {
+					return exported, fmt.Errorf("decode event to export: %w", err)
+				}
+				if events[j].Document, err = bson.MarshalExtJSON(doc, false, false); err != nil {
+					return exported, fmt.Errorf("encode event to export: %w", err)
+				}
+				ids[j] = doc.Lookup("_id")
+			}
+
+			if err := export(ctx, events); err != nil {
+				return exported, fmt.Errorf("export events from %s: %w", name, err)
+			}
+
+			result, err := collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+			if err != nil {
+				return exported, fmt.Errorf("delete exported events from %s: %w", name, err)
+			}
+			exported += result.DeletedCount
+			if len(docs) < batchSize || result.DeletedCount == 0 {
+				break
+			}
+		}
+	}
+
+	return exported, nil
+}
+
+func (r *MongoRepository) StreamEvents(ctx context.Context, filter EventFilter, fn func(event ExportedEvent) error) error {
+	names, err := r.filterCollections(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	sortBy := bson.D{{Key: "block_time", Value: 1}, {Key: "slot", Value: 1}}
+	cursor, err := r.openEvents(ctx, names, filter.mongoFilter(), sortBy, 0)
+	if err != nil {
+		return fmt.Errorf("stream events: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		doc := bson.Raw(cursor.Current)
+		var event ExportedEvent
+		if err := bson.Unmarshal(doc, &event.BaseEvent); err != nil {
+			return fmt.Errorf("decode event: %w", err)
+		}
+		if event.Document, err = bson.MarshalExtJSON(doc, false, false); err != nil {
+			return fmt.Errorf("encode event: %w", err)
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("stream events: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoRepository) ReplayEvents(ctx context.Context, filter EventFilter, fn func(event interface{}) error) error {
+	names, err := r.filterCollections(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	sortBy := bson.D{{Key: "slot", Value: 1}, {Key: "sequence", Value: 1}, {Key: "signature", Value: 1}}
+	cursor, err := r.openEvents(ctx, names, filter.mongoFilter(), sortBy, 0)
+	if err != nil {
+		return fmt.Errorf("replay events: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		event, err := DecodeEvent(bson.Raw(cursor.Current))
+		if err != nil {
+			return err
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("replay events: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoRepository) DeleteEvents(ctx context.Context, filter EventFilter) (int64, error) {
+	names, err := r.filterCollections(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	var deleted int64
+	for _, name := range names {
+		result, err := r.database.Collection(name).DeleteMany(ctx, filter.mongoFilter())
+		if err != nil {
+			return deleted, fmt.Errorf("delete events from %s: %w", name, err)
+		}
+		deleted += result.DeletedCount
+	}
+	return deleted, nil
+}
+
+// filterCollections returns the collections that may hold events matching
+// filter.
+func (r *MongoRepository) filterCollections(ctx context.Context, filter EventFilter) ([]string, error) {
+	if len(filter.EventTypes) == 0 {
+		return r.eventCollections(ctx, "")
+	}
+
+	var names []string
+	for _, eventType := range filter.EventTypes {
+		typeNames, err := r.eventCollections(ctx, eventType)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range typeNames {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	return names, nil
 }
 
 func (r *MongoRepository) Close(ctx context.Context) error {
@@ -107,25 +529,47 @@ func (r *MongoRepository) Close(ctx context.Context) error {
 }
 
 func (r *MongoRepository) CreateIndexes(ctx context.Context) error {
-	indexes := []mongo.IndexModel{
-		{
-			Keys:    bson.D{{Key: "signature", Value: 1}},
-			Options: options.Index().SetUnique(true),
-		},
-		{
-			Keys: bson.D{{Key: "event_type", Value: 1}},
-		},
-		{
-			Keys: bson.D{{Key: "block_time", Value: -1}},
-		},
-		{
-			Keys: bson.D{{Key: "slot", Value: -1}},
-		},
+	names, err := r.eventCollections(ctx, "")
+	if err != nil {
+		return fmt.Errorf("create indexes: %w", err)
+	}
+	for _, name := range names {
+		r.indexed.Delete(name)
+		if err := r.ensureIndexes(ctx, name); err != nil {
+			return err
+		}
 	}
 
-	_, err := r.collection.Indexes().CreateMany(ctx, indexes)
+	_, err = r.configHistory.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "slot", Value: -1}, {Key: "recorded_at", Value: -1}},
+	})
 	if err != nil {
-		return fmt.Errorf("create indexes: %w", err)
+		return fmt.Errorf("create config history indexes: %w", err)
+	}
+
+	if err := r.createNftIndexes(ctx); err != nil {
+		return err
+	}
+	if err := r.createNftSalesIndexes(ctx); err != nil {
+		return err
+	}
+	if err := r.createTokenHolderIndexes(ctx); err != nil {
+		return err
+	}
+	if err := r.createTokenAccountIndexes(ctx); err != nil {
+		return err
+	}
+	if err := r.createOutboxIndexes(ctx); err != nil {
+		return err
+	}
+	if err := r.createAccountSampleIndexes(ctx); err != nil {
+		return err
+	}
+	if err := r.createDeadLetterIndexes(ctx); err != nil {
+		return err
+	}
+	if err := r.createRollupIndexes(ctx); err != nil {
+		return err
 	}
 
 	return nil
@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoLayout selects how events are spread across collections.
+type MongoLayout string
+
+const (
+	// MongoLayoutSingle stores every event in the "events" collection.
+	MongoLayoutSingle MongoLayout = "single"
+	// MongoLayoutPerType stores each event type in its own collection,
+	// e.g. "events_counter_incremented".
+	MongoLayoutPerType MongoLayout = "per_type"
+	// MongoLayoutPerProgram stores the events of each program in its own
+	// collection, e.g. "events_<program id>".
+	MongoLayoutPerProgram MongoLayout = "per_program"
+)
+
+const (
+	eventsCollection       = "events"
+	eventsCollectionPrefix = "events_"
+)
+
+func ParseMongoLayout(s string) (MongoLayout, error) {
+	switch layout := MongoLayout(strings.ToLower(strings.TrimSpace(s))); layout {
+	case "":
+		return MongoLayoutSingle, nil
+	case MongoLayoutSingle, MongoLayoutPerType, MongoLayoutPerProgram:
+		return layout, nil
+	default:
+		return "", fmt.Errorf("unknown mongo collection layout %q", s)
+	}
+}
+
+// collectionName returns the collection an event of eventType emitted by
+// programID is stored in.
+func (l MongoLayout) collectionName(eventType models.EventType, programID solana.PublicKey) string {
+	switch l {
+	case MongoLayoutPerType:
+		return eventsCollectionPrefix + snakeCase(strings.TrimSuffix(string(eventType), "Event"))
+	case MongoLayoutPerProgram:
+		return eventsCollectionPrefix + programID.String()
+	default:
+		return eventsCollection
+	}
+}
+
+func snakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// eventCollections returns the collections that may hold events of
+// eventType; an empty eventType means events of any type.
+func (r *MongoRepository) eventCollections(ctx context.Context, eventType models.EventType) ([]string, error) {
+	if name, ok := r.collections[eventType]; ok {
+		return []string{name}, nil
+	}
+
+	names, err := r.layoutCollections(ctx, eventType)
+	if err != nil {
+		return nil, err
+	}
+	if eventType == "" {
+		for _, name := range r.collections {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+	}
+	return names, nil
+}
+
+func (r *MongoRepository) layoutCollections(ctx context.Context, eventType models.EventType) ([]string, error) {
+	switch r.layout {
+	case MongoLayoutPerType:
+		if eventType != "" {
+			return []string{r.layout.collectionName(eventType, solana.PublicKey{})}, nil
+		}
+	case MongoLayoutPerProgram:
+	default:
+		return []string{eventsCollection}, nil
+	}
+
+	names, err := r.database.ListCollectionNames(ctx, bson.M{"name": bson.M{"$regex": "^" + eventsCollectionPrefix}})
+	if err != nil {
+		return nil, fmt.Errorf("list event collections: %w", err)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// findEvents runs filter against each named collection and decodes the
+// combined results into results.
+func (r *MongoRepository) findEvents(ctx context.Context, names []string, filter bson.M, sortBy bson.D, limit int64, results interface{}) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	cursor, err := r.openEvents(ctx, names, filter, sortBy, limit)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	return cursor.All(ctx, results)
+}
+
+// findPage runs filter against each named collection and returns one page
+// of events in slot order, each decoded by decode. One event beyond the
+// limit is read to tell whether another page follows.
+func (r *MongoRepository) findPage(ctx context.Context, names []string, filter bson.M, page PageOptions, decode func(bson.Raw) (interface{}, error)) (*EventPage, error) {
+	result := &EventPage{Events: []interface{}{}}
+	if len(names) == 0 {
+		return result, nil
+	}
+
+	var limit int64
+	if page.Limit > 0 {
+		limit = int64(page.Limit) + 1
+	}
+	cursor, err := r.openEvents(ctx, names, page.mongoFilter(filter), page.mongoSort(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var last Cursor
+	for cursor.Next(ctx) {
+		if page.Limit > 0 && len(result.Events) == page.Limit {
+			result.Next = &last
+			break
+		}
+		doc := bson.Raw(cursor.Current)
+		event, err := decode(doc)
+		if err != nil {
+			return nil, err
+		}
+		last = Cursor{Slot: uint64(doc.Lookup("slot").AsInt64()), Signature: doc.Lookup("signature").StringValue()}
+		if page.BySequence {
+			if sequence, ok := doc.Lookup("sequence").AsInt64OK(); ok {
+				last.Sequence = uint64(sequence)
+			}
+		}
+		result.Events = append(result.Events, event)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// openEvents runs filter against each named collection. Multiple collections
+// are merged server side with $unionWith so sort and limit apply across all
+// of them. Reads scoped to a tenant only see that tenant's events.
+func (r *MongoRepository) openEvents(ctx context.Context, names []string, filter bson.M, sortBy bson.D, limit int64) (*mongo.Cursor, error) {
+	if len(names) == 1 {
+		opts := options.Find()
+		if sortBy != nil {
+			opts.SetSort(sortBy)
+		}
+		if limit > 0 {
+			opts.SetLimit(limit)
+		}
+		return r.database.Collection(names[0]).Find(ctx, tenantFilter(ctx, filter), opts)
+	}
+
+	var stages mongo.Pipeline
+	if sortBy != nil {
+		stages = append(stages, bson.D{{Key: "$sort", Value: sortBy}})
+	}
+	if limit > 0 {
+		stages = append(stages, bson.D{{Key: "$limit", Value: limit}})
+	}
+	return r.aggregateEvents(ctx, names, filter, stages)
+}
+
+// aggregateEvents runs stages over the events of the named collections
+// matching filter, merged with $unionWith. Reads scoped to a tenant only
+// see that tenant's events.
+func (r *MongoRepository) aggregateEvents(ctx context.Context, names []string, filter bson.M, stages mongo.Pipeline) (*mongo.Cursor, error) {
+	filter = tenantFilter(ctx, filter)
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: filter}}}
+	for _, name := range names[1:] {
+		pipeline = append(pipeline, bson.D{{Key: "$unionWith", Value: bson.M{
+			"coll":     name,
+			"pipeline": bson.A{bson.M{"$match": filter}},
+		}}})
+	}
+	pipeline = append(pipeline, stages...)
+	return r.database.Collection(names[0]).Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+}
+
+// ensureIndexes creates the event indexes of a collection once per process.
+func (r *MongoRepository) ensureIndexes(ctx context.Context, name string) error {
+	if _, done := r.indexed.LoadOrStore(name, struct{}{}); done {
+		return nil
+	}
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "signature", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "block_time", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "slot", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "event_type", Value: 1}, {Key: "slot", Value: -1}, {Key: "signature", Value: -1}},
+		},
+		{
+			// The latest events of every type.
+			Keys: bson.D{{Key: "slot", Value: -1}, {Key: "sequence", Value: -1}, {Key: "signature", Value: -1}},
+		},
+	}
+	indexes = append(indexes, mongo.IndexModel{
+		Keys:    bson.D{{Key: "tenant", Value: 1}, {Key: "slot", Value: -1}, {Key: "signature", Value: -1}},
+		Options: options.Index().SetPartialFilterExpression(bson.M{"tenant": bson.M{"$exists": true}}),
+	})
+	indexes = append(indexes, mongo.IndexModel{
+		Keys:    bson.D{{Key: "content_hash", Value: 1}, {Key: "block_time", Value: -1}},
+		Options: options.Index().SetPartialFilterExpression(bson.M{"content_hash": bson.M{"$exists": true}}),
+	})
+	indexes = append(indexes, mongo.IndexModel{
+		Keys:    bson.D{{Key: "program_id", Value: 1}, {Key: "sequence", Value: 1}},
+		Options: options.Index().SetPartialFilterExpression(bson.M{"sequence": bson.M{"$exists": true}}),
+	})
+	// Account timelines query every account field; partial indexes keep
+	// each one to the documents that have the field.
+	for _, field := range accountFields {
+		indexes = append(indexes, mongo.IndexModel{
+			Keys:    bson.D{{Key: field, Value: 1}, {Key: "slot", Value: -1}, {Key: "signature", Value: -1}},
+			Options: options.Index().SetPartialFilterExpression(bson.M{field: bson.M{"$exists": true}}),
+		})
+	}
+	// A per-type collection holds a single event type.
+	if r.layout != MongoLayoutPerType || r.isOverride(name) {
+		indexes = append(indexes, mongo.IndexModel{Keys: bson.D{{Key: "event_type", Value: 1}}})
+	}
+	indexes = append(indexes, r.extraIndexes(name)...)
+
+	if _, err := r.database.Collection(name).Indexes().CreateMany(ctx, indexes); err != nil {
+		return fmt.Errorf("create indexes on %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+func TestMongoLayout_CollectionName(t *testing.T) {
+	programID := solana.PublicKey{1}
+
+	tests := []struct {
+		layout    MongoLayout
+		eventType models.EventType
+		want      string
+	}{
+		{MongoLayoutSingle, models.EventTypeCounterIncremented, "events"},
+		{MongoLayoutPerType, models.EventTypeCounterIncremented, "events_counter_incremented"},
+		{MongoLayoutPerType, models.EventTypeNftListingCancelled, "events_nft_listing_cancelled"},
+		{MongoLayoutPerProgram, models.EventTypeTokensMinted, "events_" + programID.String()},
+	}
+
+	for _, tt := range tests {
+		if got := tt.layout.collectionName(tt.eventType, programID); got != tt.want {
+			t.Errorf("%s.collectionName(%s) = %q, want %q", tt.layout, tt.eventType, got, tt.want)
+		}
+	}
+}
+
+func TestParseMongoLayout(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    MongoLayout
+		wantErr bool
+	}{
+		{in: "", want: MongoLayoutSingle},
+		{in: "per_type", want: MongoLayoutPerType},
+		{in: " PER_PROGRAM ", want: MongoLayoutPerProgram},
+		{in: "sharded", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMongoLayout(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseMongoLayout(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMongoLayout(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// IndexSpec lists the fields of an extra index in key order. A "-" prefix
+// sorts the field descending.
+type IndexSpec []string
+
+// ParseIndexSpecs parses "|" separated indexes whose fields are joined by
+// "+", e.g. "mint+from|mint+to+-block_time".
+func ParseIndexSpecs(s string) ([]IndexSpec, error) {
+	var specs []IndexSpec
+	for _, raw := range strings.Split(s, "|") {
+		var spec IndexSpec
+		for _, field := range strings.Split(raw, "+") {
+			field = strings.TrimSpace(field)
+			name := strings.TrimPrefix(field, "-")
+			if name == "" || strings.HasPrefix(name, "$") {
+				return nil, fmt.Errorf("invalid index field %q in %q", field, raw)
+			}
+			spec = append(spec, field)
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+func (s IndexSpec) keys() bson.D {
+	keys := make(bson.D, len(s))
+	for i, field := range s {
+		if name, ok := strings.CutPrefix(field, "-"); ok {
+			keys[i] = bson.E{Key: name, Value: -1}
+		} else {
+			keys[i] = bson.E{Key: field, Value: 1}
+		}
+	}
+	return keys
+}
+
+// model builds the index for eventType. It is partial on the event type, so
+// collections shared by several types only index the documents it applies
+// to, and named after the type so two types may index the same fields.
+func (s IndexSpec) model(eventType models.EventType) mongo.IndexModel {
+	name := string(eventType)
+	for _, e := range s.keys() {
+		name += fmt.Sprintf("_%s_%v", e.Key, e.Value)
+	}
+	return mongo.IndexModel{
+		Keys: s.keys(),
+		Options: options.Index().
+			SetName(name).
+			SetPartialFilterExpression(bson.M{"event_type": eventType}),
+	}
+}
+
+// ValidateCollectionName rejects names MongoDB would refuse or that clash
+// with the collections the repository manages itself.
+func ValidateCollectionName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("collection name is empty")
+	case strings.ContainsAny(name, "$\x00"):
+		return fmt.Errorf("collection name %q contains '$' or a null byte", name)
+	case strings.HasPrefix(name, "system."):
+		return fmt.Errorf("collection name %q is reserved", name)
+	case strings.HasPrefix(name, "archive_"):
+		return fmt.Errorf("collection name %q clashes with retention archives", name)
+	case name == "config_history" || name == "reports":
+		return fmt.Errorf("collection name %q is reserved", name)
+	}
+	return nil
+}
+
+// collectionName returns the collection an event is stored in, honouring
+// per-type overrides before the layout.
+func (r *MongoRepository) collectionName(eventType models.EventType, programID solana.PublicKey) string {
+	if name, ok := r.collections[eventType]; ok {
+		return name
+	}
+	return r.layout.collectionName(eventType, programID)
+}
+
+// isOverride reports whether name is a collection events are routed to by
+// an override.
+func (r *MongoRepository) isOverride(name string) bool {
+	for _, c := range r.collections {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+// extraIndexes returns the configured indexes of every event type that may
+// be stored in the named collection.
+func (r *MongoRepository) extraIndexes(name string) []mongo.IndexModel {
+	eventTypes := make([]models.EventType, 0, len(r.indexes))
+	for eventType := range r.indexes {
+		eventTypes = append(eventTypes, eventType)
+	}
+	sort.Slice(eventTypes, func(i, j int) bool { return eventTypes[i] < eventTypes[j] })
+
+	var out []mongo.IndexModel
+	for _, eventType := range eventTypes {
+		if !r.mayHold(name, eventType) {
+			continue
+		}
+		for _, spec := range r.indexes[eventType] {
+			out = append(out, spec.model(eventType))
+		}
+	}
+	return out
+}
+
+func (r *MongoRepository) mayHold(name string, eventType models.EventType) bool {
+	if override, ok := r.collections[eventType]; ok {
+		return override == name
+	}
+	if r.layout == MongoLayoutPerProgram {
+		return !r.isOverride(name)
+	}
+	return r.layout.collectionName(eventType, solana.PublicKey{}) == name
+}
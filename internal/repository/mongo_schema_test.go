@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+func TestParseIndexSpecs(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []IndexSpec
+		wantErr bool
+	}{
+		{in: "mint+from", want: []IndexSpec{{"mint", "from"}}},
+		{in: "mint+from|mint + to + -block_time", want: []IndexSpec{{"mint", "from"}, {"mint", "to", "-block_time"}}},
+		{in: "mint++to", wantErr: true},
+		{in: "$where", wantErr: true},
+		{in: "-", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseIndexSpecs(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseIndexSpecs(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseIndexSpecs(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMongoRepository_CollectionOverrides(t *testing.T) {
+	r := &MongoRepository{
+		layout:      MongoLayoutPerType,
+		collections: map[models.EventType]string{models.EventTypeTokensTransferred: "transfers"},
+		indexes: map[models.EventType][]IndexSpec{
+			models.EventTypeTokensTransferred: {{"mint", "from"}},
+			models.EventTypeTokensMinted:      {{"mint", "-block_time"}},
+		},
+	}
+
+	if got := r.collectionName(models.EventTypeTokensTransferred, solana.PublicKey{}); got != "transfers" {
+		t.Errorf("collectionName(TokensTransferred) = %q, want transfers", got)
+	}
+	if got := r.collectionName(models.EventTypeTokensMinted, solana.PublicKey{}); got != "events_tokens_minted" {
+		t.Errorf("collectionName(TokensMinted) = %q, want events_tokens_minted", got)
+	}
+
+	tests := []struct {
+		collection string
+		wantNames  []string
+	}{
+		{"transfers", []string{"TokensTransferredEvent_mint_1_from_1"}},
+		{"events_tokens_minted", []string{"TokensMintedEvent_mint_1_block_time_-1"}},
+		{"events_tokens_burned", nil},
+	}
+	for _, tt := range tests {
+		var names []string
+		for _, model := range r.extraIndexes(tt.collection) {
+			names = append(names, *model.Options.Name)
+		}
+		if !reflect.DeepEqual(names, tt.wantNames) {
+			t.Errorf("extraIndexes(%s) = %v, want %v", tt.collection, names, tt.wantNames)
+		}
+	}
+}
+
+func TestValidateCollectionName(t *testing.T) {
+	for _, name := range []string{"", "system.users", "archive_events", "reports", "a$b"} {
+		if err := ValidateCollectionName(name); err == nil {
+			t.Errorf("ValidateCollectionName(%q) error = nil, want error", name)
+		}
+	}
+	if err := ValidateCollectionName("transfers"); err != nil {
+		t.Errorf("ValidateCollectionName(transfers) error = %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// salesPeriods are the periods every sale is added to.
+var salesPeriods = []models.SalesPeriod{models.SalesPeriodDay, models.SalesPeriodWeek}
+
+// NftSalesStore is implemented by repositories that maintain per collection
+// sales stats as sales are indexed.
+type NftSalesStore interface {
+	// RecordNftCollection remembers the collection of a minted NFT, so its
+	// later sales can be attributed.
+	RecordNftCollection(ctx context.Context, mint, collection string) error
+	// RecordNftSale adds a sale to the stats of its collection. Each sale is
+	// counted once however often it is recorded; sales of NFTs whose mint was
+	// never indexed are kept but not counted.
+	RecordNftSale(ctx context.Context, sale *models.NftSoldEvent) error
+	// GetCollectionStats returns the periods starting in [from, to), oldest
+	// first. Periods without sales are omitted.
+	GetCollectionStats(ctx context.Context, collection string, period models.SalesPeriod, from, to time.Time) ([]models.CollectionSalesStats, error)
+}
+
+func (r *MongoRepository) RecordNftCollection(ctx context.Context, mint, collection string) error {
+	opts := options.Update().SetUpsert(true)
+	update := bson.M{"$set": bson.M{"collection": collection}}
+	if _, err := r.nftMints.UpdateOne(ctx, bson.M{"_id": mint}, update, opts); err != nil {
+		return fmt.Errorf("record nft collection: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoRepository) RecordNftSale(ctx context.Context, sale *models.NftSoldEvent) error {
+	mint := sale.NftMint.String()
+
+	var owner struct {
+		Collection string `bson:"collection"`
+	}
+	err := r.nftMints.FindOne(ctx, bson.M{"_id": mint}).Decode(&owner)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return fmt.Errorf("find nft collection: %w", err)
+	}
+
+	// The sale document makes recording idempotent: only the write that
+	// creates it updates the stats. It is an upsert rather than an insert
+	// because a duplicate key error would abort an enclosing transaction.
+	// A failure between the two leaves the sale uncounted.
+	res, err := r.nftSales.UpdateOne(ctx, bson.M{"_id": sale.Signature + ":" + mint}, bson.M{
+		"$setOnInsert": bson.M{
+			"nft_mint":   mint,
+			"collection": owner.Collection,
+			"price":      sale.Price,
+			"block_time": sale.BlockTime,
+		},
+	}, options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("upsert nft sale: %w", err)
+	}
+	if res.UpsertedCount == 0 {
+		return nil
+	}
+	if owner.Collection == "" {
+		return nil
+	}
+
+	opts := options.Update().SetUpsert(true)
+	for _, period := range salesPeriods {
+		start := period.Start(sale.BlockTime)
+		filter := bson.M{"_id": salesStatsID(owner.Collection, period, start)}
+		if _, err := r.nftSalesStats.UpdateOne(ctx, filter, salesStatsUpdate(owner.Collection, period, start, sale.Price), opts); err != nil {
+			return fmt.Errorf("update %s sales stats: %w", period, err)
+		}
+	}
+	return nil
+}
+
+func salesStatsID(collection string, period models.SalesPeriod, start time.Time) string {
+	return fmt.Sprintf("%s:%s:%s", collection, period, start.Format(time.DateOnly))
+}
+
+func salesStatsUpdate(collection string, period models.SalesPeriod, start time.Time, price uint64) bson.M {
+	return bson.M{
+		"$setOnInsert": bson.M{"collection": collection, "period": period, "start": start},
+		"$inc":         bson.M{"sales": 1, "volume": price},
+		"$min":         bson.M{"floor": price},
+		"$max":         bson.M{"high": price},
+	}
+}
+
+func (r *MongoRepository) GetCollectionStats(ctx context.Context, collection string, period models.SalesPeriod, from, to time.Time) ([]models.CollectionSalesStats, error) {
+	filter := bson.M{
+		"collection": collection,
+		"period":     period,
+		"start":      bson.M{"$gte": from, "$lt": to},
+	}
+	cursor, err := r.nftSalesStats.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "start", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("find sales stats: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var stats []models.CollectionSalesStats
+	if err := cursor.All(ctx, &stats); err != nil {
+		return nil, fmt.Errorf("decode sales stats: %w", err)
+	}
+	return stats, nil
+}
+
+func (r *MongoRepository) createNftSalesIndexes(ctx context.Context) error {
+	_, err := r.nftSalesStats.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "collection", Value: 1}, {Key: "period", Value: 1}, {Key: "start", Value: 1}},
+	})
+	if err != nil {
+		return fmt.Errorf("create nft sales stats indexes: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// NftMetadataStore is implemented by repositories that can hold the
+// off-chain metadata of minted NFTs.
+type NftMetadataStore interface {
+	// QueueNftMetadata records a minted NFT for fetching unless it is
+	// already known.
+	QueueNftMetadata(ctx context.Context, nft *models.NftMetadata) error
+	// PendingNftMetadata returns pending NFTs whose next attempt is due at now.
+	PendingNftMetadata(ctx context.Context, now time.Time, limit int) ([]models.NftMetadata, error)
+	SaveNftMetadata(ctx context.Context, nft *models.NftMetadata) error
+	// GetNftMetadata returns nil when the mint is unknown.
+	GetNftMetadata(ctx context.Context, mint string) (*models.NftMetadata, error)
+	SearchNfts(ctx context.Context, search NftSearch) ([]models.NftMetadata, error)
+}
+
+// NftSearch matches fetched NFTs against every set field.
+type NftSearch struct {
+	// Text is matched against the name, symbol, description and attribute
+	// values; results are ordered by relevance.
+	Text       string
+	Attributes []models.NftAttribute
+	Collection string
+	Limit      int
+}
+
+func (r *MongoRepository) QueueNftMetadata(ctx context.Context, nft *models.NftMetadata) error {
+	opts := options.Update().SetUpsert(true)
+	update := bson.M{"$setOnInsert": nft}
+	if _, err := r.nfts.UpdateOne(ctx, bson.M{"_id": nft.Mint}, update, opts); err != nil {
+		return fmt.Errorf("queue nft metadata: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoRepository) PendingNftMetadata(ctx context.Context, now time.Time, limit int) ([]models.NftMetadata, error) {
+	filter := bson.M{
+		"status":          models.NftMetadataPending,
+		"next_attempt_at": bson.M{"$lte": now},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetLimit(int64(limit))
+	return r.findNfts(ctx, filter, opts)
+}
+
+func (r *MongoRepository) SaveNftMetadata(ctx context.Context, nft *models.NftMetadata) error {
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.nfts.ReplaceOne(ctx, bson.M{"_id": nft.Mint}, nft, opts); err != nil {
+		return fmt.Errorf("save nft metadata: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoRepository) GetNftMetadata(ctx context.Context, mint string) (*models.NftMetadata, error) {
+	var nft models.NftMetadata
+	err := r.nfts.FindOne(ctx, bson.M{"_id": mint}).Decode(&nft)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find nft metadata: %w", err)
+	}
+	return &nft, nil
+}
+
+func (r *MongoRepository) SearchNfts(ctx context.Context, search NftSearch) ([]models.NftMetadata, error) {
+	filter, opts := nftSearchQuery(search)
+	return r.findNfts(ctx, filter, opts)
+}
+
+func nftSearchQuery(search NftSearch) (bson.M, *options.FindOptions) {
+	filter := bson.M{"status": models.NftMetadataFetched}
+	if search.Collection != "" {
+		filter["collection"] = search.Collection
+	}
+	if len(search.Attributes) > 0 {
+		all := make(bson.A, 0, len(search.Attributes))
+		for _, attr := range search.Attributes {
+			all = append(all, bson.M{"$elemMatch": bson.M{"trait_type": attr.TraitType, "value": attr.Value}})
+		}
+		filter["attributes"] = bson.M{"$all": all}
+	}
+
+	opts := options.Find().SetLimit(int64(search.Limit))
+	if search.Text != "" {
+		filter["$text"] = bson.M{"$search": search.Text}
+		score := bson.M{"$meta": "textScore"}
+		opts.SetProjection(bson.M{"score": score}).
+			SetSort(bson.D{{Key: "score", Value: score}, {Key: "slot", Value: -1}})
+	} else {
+		opts.SetSort(bson.D{{Key: "slot", Value: -1}})
+	}
+	return filter, opts
+}
+
+func (r *MongoRepository) findNfts(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]models.NftMetadata, error) {
+	cursor, err := r.nfts.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find nft metadata: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var nfts []models.NftMetadata
+	if err := cursor.All(ctx, &nfts); err != nil {
+		return nil, fmt.Errorf("decode nft metadata: %w", err)
+	}
+	return nfts, nil
+}
+
+func (r *MongoRepository) createNftIndexes(ctx context.Context) error {
+	_, err := r.nfts.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+		{Keys: bson.D{{Key: "collection", Value: 1}, {Key: "slot", Value: -1}}},
+		{Keys: bson.D{{Key: "attributes.trait_type", Value: 1}, {Key: "attributes.value", Value: 1}}},
+		{
+			Keys: bson.D{
+				{Key: "name", Value: "text"},
+				{Key: "symbol", Value: "text"},
+				{Key: "description", Value: "text"},
+				{Key: "attributes.value", Value: "text"},
+			},
+			Options: options.Index().SetName("nft_metadata_text"),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("create nft metadata indexes: %w", err)
+	}
+	return nil
+}
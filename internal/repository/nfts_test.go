@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestNftSearchQuery(t *testing.T) {
+	tests := []struct {
+		name   string
+		search NftSearch
+		want   bson.M
+	}{
+		{
+			name:   "collection",
+			search: NftSearch{Collection: "col"},
+			want:   bson.M{"status": models.NftMetadataFetched, "collection": "col"},
+		},
+		{
+			name:   "text",
+			search: NftSearch{Text: "blue ape"},
+			want:   bson.M{"status": models.NftMetadataFetched, "$text": bson.M{"$search": "blue ape"}},
+		},
+		{
+			name: "attributes",
+			search: NftSearch{Attributes: []models.NftAttribute{
+				{TraitType: "Background", Value: "Blue"},
+				{TraitType: "Eyes", Value: "Laser"},
+			}},
+			want: bson.M{
+				"status": models.NftMetadataFetched,
+				"attributes": bson.M{"$all": bson.A{
+					bson.M{"$elemMatch": bson.M{"trait_type": "Background", "value": "Blue"}},
+					bson.M{"$elemMatch": bson.M{"trait_type": "Eyes", "value": "Laser"}},
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := nftSearchQuery(tt.search)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("nftSearchQuery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
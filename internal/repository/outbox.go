@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// outboxSentTTL is how long published entries are kept for inspection.
+const outboxSentTTL = 24 * time.Hour
+
+// OutboxStore is implemented by repositories that can store an event and
+// its outbox entries atomically.
+type OutboxStore interface {
+	// SaveEventWithOutbox saves event and a pending entry for every
+	// destination in one transaction.
+	SaveEventWithOutbox(ctx context.Context, event models.Event, destinations []string) error
+	// PendingOutbox returns up to limit unsent entries of a destination,
+	// oldest first.
+	PendingOutbox(ctx context.Context, destination string, limit int) ([]models.OutboxEntry, error)
+	MarkOutboxSent(ctx context.Context, ids []string, at time.Time) error
+	// MarkOutboxFailed counts a failed attempt; the entries stay pending.
+	MarkOutboxFailed(ctx context.Context, ids []string, reason string) error
+}
+
+// OutboxRepository saves events through an OutboxStore, so every event is
+// published to the destinations by an outbox dispatcher even if the
+// indexer crashes right after storing it.
+type OutboxRepository struct {
+	Repository
+	store        OutboxStore
+	destinations []string
+}
+
+func NewOutboxRepository(repo Repository, destinations []string) (*OutboxRepository, error) {
+	store, ok := Unwrap(repo).(OutboxStore)
+	if !ok {
+		return nil, fmt.Errorf("%T does not support an outbox", Unwrap(repo))
+	}
+	return &OutboxRepository{Repository: repo, store: store, destinations: destinations}, nil
+}
+
+func (r *OutboxRepository) SaveEvent(ctx context.Context, event models.Event) error {
+	return r.store.SaveEventWithOutbox(ctx, event, r.destinations)
+}
+
+// Unwrap returns the decorated repository.
+func (r *OutboxRepository) Unwrap() Repository {
+	return r.Repository
+}
+
+// SaveEventWithOutbox needs a replica set or sharded cluster, as MongoDB
+// only supports transactions there. It joins the transaction of ctx, if
+// any.
+func (r *MongoRepository) SaveEventWithOutbox(ctx context.Context, event models.Event, destinations []string) error {
+	name, err := r.eventCollectionFor(ctx, event)
+	if err != nil {
+		return err
+	}
+
+	return r.WithTransaction(ctx, func(ctx context.Context) error {
+		// Numbered first, so the published payload has the sequence.
+		done, err := r.assignSequence(ctx, event)
+		if err != nil {
+			return err
+		}
+		defer done()
+		entries, err := newOutboxEntries(event, destinations, time.Now())
+		if err != nil {
+			return err
+		}
+		restore, err := compressRawData(event, r.rawData)
+		if err != nil {
+			return err
+		}
+		defer restore()
+		if _, err := r.database.Collection(name).InsertOne(ctx, event); err != nil {
+			return fmt.Errorf("insert event: %w", err)
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		docs := make([]interface{}, len(entries))
+		for i := range entries {
+			docs[i] = entries[i]
+		}
+		if _, err := r.outbox.InsertMany(ctx, docs); err != nil {
+			return fmt.Errorf("insert outbox entries: %w", err)
+		}
+		return nil
+	})
+}
+
+func newOutboxEntries(event models.Event, destinations []string, now time.Time) ([]models.OutboxEntry, error) {
+	if len(destinations) == 0 {
+		return nil, nil
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("marshal event: %w", err)
+	}
+	base := event.Base()
+
+	entries := make([]models.OutboxEntry, len(destinations))
+	for i, destination := range destinations {
+		id, err := newOutboxID()
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = models.OutboxEntry{
+			ID:          id,
+			Destination: destination,
+			EventType:   base.EventType,
+			Signature:   base.Signature,
+			Payload:     payload,
+			CreatedAt:   now,
+		}
+	}
+	return entries, nil
+}
+
+func newOutboxID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate outbox id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (r *MongoRepository) PendingOutbox(ctx context.Context, destination string, limit int) ([]models.OutboxEntry, error) {
+	filter := bson.M{"destination": destination, "sent_at": bson.M{"$exists": false}}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.outbox.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find outbox entries: %w", err)
+	}
+	var entries []models.OutboxEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("decode outbox entries: %w", err)
+	}
+	return entries, nil
+}
+
+func (r *MongoRepository) MarkOutboxSent(ctx context.Context, ids []string, at time.Time) error {
+	_, err := r.outbox.UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$in": ids}},
+		bson.M{"$set": bson.M{"sent_at": at}, "$inc": bson.M{"attempts": 1}, "$unset": bson.M{"last_error": ""}},
+	)
+	if err != nil {
+		return fmt.Errorf("mark outbox entries sent: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoRepository) MarkOutboxFailed(ctx context.Context, ids []string, reason string) error {
+	_, err := r.outbox.UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$in": ids}},
+		bson.M{"$set": bson.M{"last_error": reason}, "$inc": bson.M{"attempts": 1}},
+	)
+	if err != nil {
+		return fmt.Errorf("mark outbox entries failed: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoRepository) createOutboxIndexes(ctx context.Context) error {
+	_, err := r.outbox.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "destination", Value: 1}, {Key: "sent_at", Value: 1}, {Key: "created_at", Value: 1}}},
+		// Documents without sent_at are never expired.
+		{
+			Keys:    bson.D{{Key: "sent_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(outboxSentTTL.Seconds())),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("create outbox indexes: %w", err)
+	}
+	return nil
+}
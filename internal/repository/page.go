@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Cursor is the position of an event in slot order. The signature breaks
+// ties between events of the same slot, after the sequence in pages ordered
+// BySequence; zero is an event without one.
+type Cursor struct {
+	Slot      uint64
+	Sequence  uint64
+	Signature string
+}
+
+// String encodes the cursor as an opaque URL safe token.
+func (c Cursor) String() string {
+	s := strconv.FormatUint(c.Slot, 10) + ":"
+	if c.Sequence > 0 {
+		s += strconv.FormatUint(c.Sequence, 10) + ":"
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(s + c.Signature))
+}
+
+// ParseCursor decodes a token produced by Cursor.String.
+func ParseCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	parts := strings.Split(string(raw), ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[len(parts)-1] == "" {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	c := &Cursor{Signature: parts[len(parts)-1]}
+	if c.Slot, err = strconv.ParseUint(parts[0], 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	if len(parts) == 3 {
+		if c.Sequence, err = strconv.ParseUint(parts[1], 10, 64); err != nil || c.Sequence == 0 {
+			return nil, fmt.Errorf("invalid cursor")
+		}
+	}
+	return c, nil
+}
+
+// PageOptions selects one page of a query's events, ordered by slot.
+type PageOptions struct {
+	Limit int
+	// After continues from the last event of a previous page.
+	After *Cursor
+	// Ascending returns the oldest events first; the default is newest
+	// first.
+	Ascending bool
+	// Deployment restricts the page to the events of one labeled counter
+	// deployment.
+	Deployment string
+	// Network restricts the page to the events of one network of NETWORKS.
+	Network string
+	// BySequence orders the events of a slot by their sequence before the
+	// signature, so events of several programs or collections in a slot
+	// page in the order they were stored.
+	BySequence bool
+}
+
+// EventPage is one page of events. Next is nil on the last page.
+type EventPage struct {
+	Events []interface{}
+	Next   *Cursor
+}
+
+func (p PageOptions) direction() int {
+	if p.Ascending {
+		return 1
+	}
+	return -1
+}
+
+func (p PageOptions) mongoSort() bson.D {
+	if p.BySequence {
+		return bson.D{{Key: "slot", Value: p.direction()}, {Key: "sequence", Value: p.direction()}, {Key: "signature", Value: p.direction()}}
+	}
+	return bson.D{{Key: "slot", Value: p.direction()}, {Key: "signature", Value: p.direction()}}
+}
+
+// mongoFilter restricts filter to the deployment, the network and the
+// events after p.After.
+func (p PageOptions) mongoFilter(filter bson.M) bson.M {
+	if p.Deployment != "" {
+		filter = bson.M{"$and": bson.A{filter, bson.M{"deployment": p.Deployment}}}
+	}
+	if p.Network != "" {
+		filter = bson.M{"$and": bson.A{filter, bson.M{"network": p.Network}}}
+	}
+	if p.After == nil {
+		return filter
+	}
+	op := "$lt"
+	if p.Ascending {
+		op = "$gt"
+	}
+	after := bson.M{"$or": bson.A{
+		bson.M{"slot": bson.M{op: p.After.Slot}},
+		bson.M{"slot": p.After.Slot, "signature": bson.M{op: p.After.Signature}},
+	}}
+	if p.BySequence {
+		after = p.afterSequence(op)
+	}
+	if len(filter) == 0 {
+		return after
+	}
+	return bson.M{"$and": bson.A{filter, after}}
+}
+
+// afterSequence matches the events after p.After in BySequence order.
+// Events without a sequence sort before those with one, as MongoDB sorts
+// missing fields.
+func (p PageOptions) afterSequence(op string) bson.M {
+	slot, sequence, signature := p.After.Slot, p.After.Sequence, p.After.Signature
+	or := bson.A{bson.M{"slot": bson.M{op: slot}}}
+	switch {
+	case sequence > 0 && p.Ascending:
+		or = append(or, bson.M{"slot": slot, "sequence": bson.M{"$gt": sequence}})
+	case sequence > 0:
+		or = append(or, bson.M{"slot": slot, "sequence": bson.M{"$not": bson.M{"$gte": sequence}}})
+	case p.Ascending:
+		or = append(or, bson.M{"slot": slot, "sequence": bson.M{"$ne": nil}})
+	}
+	var same interface{} = sequence
+	if sequence == 0 {
+		same = nil
+	}
+	return bson.M{"$or": append(or, bson.M{"slot": slot, "sequence": same, "signature": bson.M{op: signature}})}
+}
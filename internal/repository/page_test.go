@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	want := Cursor{Slot: 123456789, Signature: "5VERv8NMvzbJMEkV8xnrLkEaWRtSz9CosKDYjCJjBRnbJLgp8uirBgmQpjKhoR4tjF3ZpRzrFmBV6UjKdiSZkQUW"}
+
+	got, err := ParseCursor(want.String())
+	if err != nil {
+		t.Fatalf("ParseCursor() error = %v", err)
+	}
+	if *got != want {
+		t.Errorf("ParseCursor() = %+v, want %+v", *got, want)
+	}
+
+	want.Sequence = 42
+	if got, err := ParseCursor(want.String()); err != nil || *got != want {
+		t.Errorf("ParseCursor() with a sequence = %+v, %v, want %+v", got, err, want)
+	}
+}
+
+func TestParseCursor_Invalid(t *testing.T) {
+	for _, s := range []string{"", "!!", Cursor{Slot: 1}.String(), "eDpzaWc"} {
+		if _, err := ParseCursor(s); err == nil {
+			t.Errorf("ParseCursor(%q) error = nil, want error", s)
+		}
+	}
+}
+
+func TestPageOptions_MongoFilter(t *testing.T) {
+	filter := bson.M{"event_type": "CounterResetEvent"}
+	tests := []struct {
+		name string
+		page PageOptions
+		want bson.M
+	}{
+		{"no restriction", PageOptions{}, filter},
+		{"deployment", PageOptions{Deployment: "devnet"}, bson.M{"$and": bson.A{filter, bson.M{"deployment": "devnet"}}}},
+		{"network", PageOptions{Network: "mainnet"}, bson.M{"$and": bson.A{filter, bson.M{"network": "mainnet"}}}},
+		{"after cursor", PageOptions{After: &Cursor{Slot: 5, Signature: "s"}}, bson.M{"$and": bson.A{filter, bson.M{"$or": bson.A{
+			bson.M{"slot": bson.M{"$lt": uint64(5)}},
+			bson.M{"slot": uint64(5), "signature": bson.M{"$lt": "s"}},
+		}}}}},
+		{"after sequence", PageOptions{BySequence: true, After: &Cursor{Slot: 5, Sequence: 7, Signature: "s"}}, bson.M{"$and": bson.A{filter, bson.M{"$or": bson.A{
+			bson.M{"slot": bson.M{"$lt": uint64(5)}},
+			bson.M{"slot": uint64(5), "sequence": bson.M{"$not": bson.M{"$gte": uint64(7)}}},
+			bson.M{"slot": uint64(5), "sequence": uint64(7), "signature": bson.M{"$lt": "s"}},
+		}}}}},
+		{"ascending after no sequence", PageOptions{BySequence: true, Ascending: true, After: &Cursor{Slot: 5, Signature: "s"}}, bson.M{"$and": bson.A{filter, bson.M{"$or": bson.A{
+			bson.M{"slot": bson.M{"$gt": uint64(5)}},
+			bson.M{"slot": uint64(5), "sequence": bson.M{"$ne": nil}},
+			bson.M{"slot": uint64(5), "sequence": nil, "signature": bson.M{"$gt": "s"}},
+		}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.page.mongoFilter(filter); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mongoFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
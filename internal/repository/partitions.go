@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// partitionedEventsDDL creates the events table of migration 0001
+// partitioned by slot range. Unique constraints of a partitioned table must
+// hold the partition key, so signatures are unique with their slot, which
+// a transaction only has one of.
+const partitionedEventsDDL = `
+CREATE TABLE IF NOT EXISTS events (
+	id BIGSERIAL,
+	event_type VARCHAR(100) NOT NULL,
+	signature VARCHAR(255) NOT NULL,
+	slot BIGINT NOT NULL,
+	block_time TIMESTAMP NOT NULL,
+	program_id VARCHAR(44) NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	raw_data JSONB,
+	event_data JSONB NOT NULL,
+	PRIMARY KEY (slot, id),
+	UNIQUE (signature, slot)
+) PARTITION BY RANGE (slot)`
+
+// partitionBound matches the bound pg_get_expr gives a slot range
+// partition, e.g. FOR VALUES FROM ('0') TO ('432000').
+var partitionBound = regexp.MustCompile(`FROM \('?(\d+)'?\) TO \('?(\d+)'?\)`)
+
+// slotRange is a partition of the events table holding [start, end).
+type slotRange struct {
+	name       string
+	start, end uint64
+}
+
+func (p slotRange) contains(slot uint64) bool {
+	return slot >= p.start && slot < p.end
+}
+
+// prepareEventsTable creates the events table partitioned before the
+// migrations run, which then leave it be and add their indexes to every
+// partition. An existing table is never converted.
+func (r *PostgresRepository) prepareEventsTable(ctx context.Context) error {
+	if r.opts.PartitionSlots == 0 {
+		return nil
+	}
+	kind, err := r.eventsTableKind(ctx)
+	switch {
+	case err != nil:
+		return err
+	case kind == "":
+		if _, err := r.pool.Exec(ctx, partitionedEventsDDL); err != nil {
+			return fmt.Errorf("create partitioned events table: %w", err)
+		}
+	case kind != "p":
+		log.Printf("warning: the events table was created unpartitioned; partitioning only applies to new databases")
+	}
+	return nil
+}
+
+// eventsTableKind returns the pg_class relkind of the events table, "p"
+// when partitioned, or "" when it does not exist.
+func (r *PostgresRepository) eventsTableKind(ctx context.Context) (string, error) {
+	var kind string
+	err := r.pool.QueryRow(ctx, `SELECT relkind::text FROM pg_class WHERE oid = to_regclass('events')`).Scan(&kind)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return "", fmt.Errorf("inspect events table: %w", err)
+	}
+	return kind, nil
+}
+
+// loadPartitions reads the slot range partitions of the events table,
+// ordered by slot. It returns none when the table is not partitioned.
+func (r *PostgresRepository) loadPartitions(ctx context.Context) ([]slotRange, error) {
+	rows, err := r.pool.Query(ctx, `
+	SELECT c.relname, pg_get_expr(c.relpartbound, c.oid)
+	FROM pg_inherits i JOIN pg_class c ON c.oid = i.inhrelid
+	WHERE i.inhparent = to_regclass('events')`)
+	if err != nil {
+		return nil, fmt.Errorf("query events partitions: %w", err)
+	}
+	defer rows.Close()
+
+	partitions := []slotRange{}
+	for rows.Next() {
+		var name, bound string
+		if err := rows.Scan(&name, &bound); err != nil {
+			return nil, fmt.Errorf("scan events partition: %w", err)
+		}
+		if p, ok := parsePartitionBound(name, bound); ok {
+			partitions = append(partitions, p)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query events partitions: %w", err)
+	}
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i].start < partitions[j].start })
+	return partitions, nil
+}
+
+// parsePartitionBound reads a slot range bound; ok is false for other
+// partitions, such as a default one.
+func parsePartitionBound(name, bound string) (slotRange, bool) {
+	m := partitionBound.FindStringSubmatch(bound)
+	if m == nil {
+		return slotRange{}, false
+	}
+	start, err1 := strconv.ParseUint(m[1], 10, 64)
+	end, err2 := strconv.ParseUint(m[2], 10, 64)
+	if err1 != nil || err2 != nil {
+		return slotRange{}, false
+	}
+	return slotRange{name: name, start: start, end: end}, true
+}
+
+// planPartition returns the partition to create for slot: its range of
+// size slots, shrunk so it does not overlap the existing partitions, which
+// may have been created with another size.
+func planPartition(existing []slotRange, slot, size uint64) slotRange {
+	p := slotRange{start: slot / size * size}
+	p.end = p.start + size
+	for _, e := range existing {
+		if e.end <= slot && e.end > p.start {
+			p.start = e.end
+		}
+		if e.start > slot && e.start < p.end {
+			p.end = e.start
+		}
+	}
+	p.name = "events_s" + strconv.FormatUint(p.start, 10)
+	return p
+}
+
+// EnsurePartitions creates the partitions of the events table holding slot
+// and the range after it, so writes never find their partition missing.
+// SaveEvent and SaveEvents call it with the slot of every event, which is
+// cheap once the partition is known. It does nothing unless the table is
+// partitioned.
+func (r *PostgresRepository) EnsurePartitions(ctx context.Context, slot uint64) error {
+	if r.opts.PartitionSlots == 0 {
+		return nil
+	}
+	r.partitionsMu.Lock()
+	defer r.partitionsMu.Unlock()
+
+	if r.partitions == nil {
+		partitions, err := r.loadPartitions(ctx)
+		if err != nil {
+			return err
+		}
+		if len(partitions) == 0 {
+			kind, err := r.eventsTableKind(ctx)
+			if err != nil {
+				return err
+			}
+			if kind != "p" {
+				// Not partitioned, or not migrated yet.
+				return nil
+			}
+		}
+		r.partitions = partitions
+	}
+
+	for _, s := range []uint64{slot, slot + r.opts.PartitionSlots} {
+		if r.partitionOf(s) >= 0 {
+			continue
+		}
+		p := planPartition(r.partitions, s, r.opts.PartitionSlots)
+		_, err := r.pool.Exec(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s PARTITION OF events FOR VALUES FROM (%d) TO (%d)`, quoteIdent(p.name), p.start, p.end))
+		if err != nil {
+			// Another indexer may have created an overlapping partition.
+			r.partitions = nil
+			return fmt.Errorf("create events partition %s: %w", p.name, err)
+		}
+		r.partitions = append(r.partitions, p)
+		sort.Slice(r.partitions, func(i, j int) bool { return r.partitions[i].start < r.partitions[j].start })
+	}
+	return nil
+}
+
+// quoteIdent quotes a table name read from the catalog.
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// partitionOf returns the index of the known partition holding slot, or -1.
+func (r *PostgresRepository) partitionOf(slot uint64) int {
+	i := sort.Search(len(r.partitions), func(i int) bool { return r.partitions[i].end > slot })
+	if i < len(r.partitions) && r.partitions[i].contains(slot) {
+		return i
+	}
+	return -1
+}
+
+// PruneEvents drops the oldest partitions of the events table while all
+// their events have outlived policy.MaxAge. Only whole partitions are
+// dropped, which needs no vacuum, so per type retention and archiving are
+// not supported.
+func (r *PostgresRepository) PruneEvents(ctx context.Context, policy RetentionPolicy, now time.Time) (int64, error) {
+	if r.opts.PartitionSlots == 0 {
+		return 0, fmt.Errorf("postgres retention requires a partitioned events table")
+	}
+	if len(policy.PerType) > 0 || policy.Archive {
+		return 0, fmt.Errorf("postgres retention drops whole partitions and supports neither per type retention nor archiving")
+	}
+	if policy.MaxAge <= 0 {
+		return 0, nil
+	}
+	before := now.Add(-policy.MaxAge)
+
+	r.partitionsMu.Lock()
+	defer r.partitionsMu.Unlock()
+	partitions, err := r.loadPartitions(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// Partitions are dropped oldest first until one holds recent events;
+	// empty ones, most likely created ahead of the indexed slots, are kept.
+	r.partitions = nil
+	var deleted int64
+	kept := make([]slotRange, 0, len(partitions))
+	for i, p := range partitions {
+		var newest *time.Time
+		var count int64
+		if err := r.pool.QueryRow(ctx, "SELECT max(block_time), count(*) FROM "+quoteIdent(p.name)).Scan(&newest, &count); err != nil {
+			return deleted, fmt.Errorf("inspect events partition %s: %w", p.name, err)
+		}
+		if newest == nil {
+			kept = append(kept, p)
+			continue
+		}
+		if !newest.Before(before) {
+			kept = append(kept, partitions[i:]...)
+			break
+		}
+		if _, err := r.pool.Exec(ctx, "DROP TABLE "+quoteIdent(p.name)); err != nil {
+			return deleted, fmt.Errorf("drop events partition %s: %w", p.name, err)
+		}
+		deleted += count
+	}
+	r.partitions = kept
+	return deleted, nil
+}
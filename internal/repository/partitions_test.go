@@ -0,0 +1,53 @@
+package repository
+
+import "testing"
+
+func TestParsePartitionBound(t *testing.T) {
+	tests := []struct {
+		bound  string
+		want   slotRange
+		wantOk bool
+	}{
+		{"FOR VALUES FROM ('0') TO ('432000')", slotRange{name: "p", start: 0, end: 432000}, true},
+		{"FOR VALUES FROM (864000) TO (1296000)", slotRange{name: "p", start: 864000, end: 1296000}, true},
+		{"DEFAULT", slotRange{}, false},
+		{"FOR VALUES FROM (MINVALUE) TO ('10')", slotRange{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parsePartitionBound("p", tt.bound)
+		if ok != tt.wantOk || got != tt.want {
+			t.Errorf("parsePartitionBound(%q) = %+v, %v, want %+v, %v", tt.bound, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
+
+func TestPlanPartition(t *testing.T) {
+	existing := []slotRange{{start: 100, end: 150}, {start: 250, end: 300}}
+	tests := []struct {
+		slot       uint64
+		start, end uint64
+	}{
+		{10, 0, 100},
+		{170, 150, 200},
+		{220, 200, 250},
+		{420, 400, 500},
+	}
+	for _, tt := range tests {
+		got := planPartition(existing, tt.slot, 100)
+		if got.start != tt.start || got.end != tt.end {
+			t.Errorf("planPartition(%d) = [%d, %d), want [%d, %d)", tt.slot, got.start, got.end, tt.start, tt.end)
+		}
+	}
+	if got := planPartition(nil, 432001, 432000).name; got != "events_s432000" {
+		t.Errorf("planPartition() name = %s, want events_s432000", got)
+	}
+}
+
+func TestPartitionOf(t *testing.T) {
+	r := &PostgresRepository{partitions: []slotRange{{start: 0, end: 100}, {start: 200, end: 300}}}
+	for slot, want := range map[uint64]int{0: 0, 99: 0, 100: -1, 250: 1, 300: -1} {
+		if got := r.partitionOf(slot); got != want {
+			t.Errorf("partitionOf(%d) = %d, want %d", slot, got, want)
+		}
+	}
+}
@@ -2,18 +2,42 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/gagliardetto/solana-go"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/lugondev/go-indexer-solana-starter/internal/models"
 )
 
+// PostgresOptions configure a PostgresRepository; the zero value keeps a
+// single events table.
+type PostgresOptions struct {
+	// PartitionSlots partitions a new events table by ranges of this many
+	// slots; zero leaves it unpartitioned.
+	PartitionSlots uint64
+	// CopyThreshold is the batch size from which SaveEvents writes with
+	// COPY instead of INSERTs; zero means defaultCopyThreshold.
+	CopyThreshold int
+	// RawDataCompression compresses the raw data of the events saved.
+	RawDataCompression RawDataCompression
+}
+
 type PostgresRepository struct {
 	pool *pgxpool.Pool
+	opts PostgresOptions
+
+	partitionsMu sync.Mutex
+	// partitions are the slot ranges of the events table known to exist,
+	// by start; nil until loaded.
+	partitions []slotRange
 }
 
-func NewPostgresRepository(connString string) (*PostgresRepository, error) {
+func NewPostgresRepository(connString string, opts PostgresOptions) (*PostgresRepository, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -33,54 +57,209 @@ func NewPostgresRepository(connString string) (*PostgresRepository, error) {
 
 	return &PostgresRepository{
 		pool: pool,
+		opts: opts,
 	}, nil
 }
 
-func (r *PostgresRepository) SaveEvent(ctx context.Context, event interface{}) error {
-	return fmt.Errorf("postgres repository not fully implemented yet")
+func (r *PostgresRepository) SaveEvent(ctx context.Context, e models.Event) error {
+	row, err := eventRow(e, r.opts.RawDataCompression)
+	if err != nil {
+		return err
+	}
+	if err := r.EnsurePartitions(ctx, e.Base().Slot); err != nil {
+		return err
+	}
+	var sequence int64
+	args := append(row[:len(row)-1:len(row)-1], e.Base().Network)
+	if err := r.pool.QueryRow(ctx, insertEventSQL, args...).Scan(&sequence); err != nil {
+		return fmt.Errorf("insert event: %w", err)
+	}
+	e.Base().Sequence = uint64(sequence)
+	return nil
 }
 
 func (r *PostgresRepository) GetEventsByTimeRange(ctx context.Context, from, to time.Time) ([]models.BaseEvent, error) {
-	return nil, fmt.Errorf("postgres repository not fully implemented yet")
+	var q sqlQuery
+	q.conds = append(q.conds, "block_time >= "+q.arg(from.UTC()), "block_time <= "+q.arg(to.UTC()))
+	q.tenant(ctx)
+
+	rows, err := r.pool.Query(ctx, "SELECT COALESCE(sequence, 0), event_data FROM events"+q.where()+" ORDER BY block_time, id", q.args...)
+	if err != nil {
+		return nil, fmt.Errorf("find events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []models.BaseEvent
+	for rows.Next() {
+		var (
+			sequence int64
+			data     []byte
+			event    models.BaseEvent
+		)
+		if err := rows.Scan(&sequence, &data); err != nil {
+			return nil, fmt.Errorf("scan event: %w", err)
+		}
+		if err := json.Unmarshal(data, &event); err != nil {
+			return nil, fmt.Errorf("decode event: %w", err)
+		}
+		if err := decompressRawData(&event); err != nil {
+			return nil, err
+		}
+		event.Sequence = uint64(sequence)
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("find events: %w", err)
+	}
+	return events, nil
 }
 
-func (r *PostgresRepository) GetEventsByType(ctx context.Context, eventType models.EventType, limit int) ([]interface{}, error) {
-	return nil, fmt.Errorf("postgres repository not fully implemented yet")
+func (r *PostgresRepository) GetEventsByType(ctx context.Context, eventType models.EventType, page PageOptions) (*EventPage, error) {
+	result, err := r.queryModels(ctx, EventFilter{EventTypes: []models.EventType{eventType}}, page)
+	if err != nil {
+		return nil, fmt.Errorf("find events by type: %w", err)
+	}
+	return result, nil
 }
 
 func (r *PostgresRepository) GetEventBySignature(ctx context.Context, signature string) (interface{}, error) {
-	return nil, fmt.Errorf("postgres repository not fully implemented yet")
+	var q sqlQuery
+	q.conds = append(q.conds, "signature = "+q.arg(signature))
+	q.tenant(ctx)
+
+	var (
+		sequence int64
+		data     []byte
+	)
+	err := r.pool.QueryRow(ctx, "SELECT COALESCE(sequence, 0), event_data FROM events"+q.where()+" ORDER BY id LIMIT 1", q.args...).Scan(&sequence, &data)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find event by signature: %w", err)
+	}
+	return decodeEventData(data, uint64(sequence))
 }
 
-func (r *PostgresRepository) Close(ctx context.Context) error {
-	r.pool.Close()
+func (r *PostgresRepository) GetEventsByAccount(ctx context.Context, account solana.PublicKey, opts AccountEventsOptions) (*EventPage, error) {
+	result, err := r.queryModels(ctx, EventFilter{EventTypes: opts.EventTypes, Account: &account}, opts.PageOptions)
+	if err != nil {
+		return nil, fmt.Errorf("find events by account: %w", err)
+	}
+	return result, nil
+}
+
+// SaveConfigChange records a config change. Saving the same change twice,
+// e.g. when a transaction is re-indexed, is a no-op.
+func (r *PostgresRepository) SaveConfigChange(ctx context.Context, change *models.ConfigChange) error {
+	config, err := json.Marshal(change.Config)
+	if err != nil {
+		return fmt.Errorf("encode config: %w", err)
+	}
+	_, err = r.pool.Exec(ctx, `
+INSERT INTO config_history (program_id, source, signature, slot, block_time, changed_fields, config, recorded_at)
+SELECT $1, $2, $3, $4, $5, $6, $7, $8
+WHERE NOT EXISTS (
+	SELECT 1 FROM config_history
+	WHERE program_id = $1 AND source = $2 AND COALESCE(signature, '') = $3 AND slot = $4
+)`,
+		change.ProgramID.String(), string(change.Source), change.Signature, int64(change.Slot),
+		change.BlockTime.UTC(), change.ChangedFields, config, change.RecordedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("save config change: %w", err)
+	}
 	return nil
 }
 
-func (r *PostgresRepository) CreateSchema(ctx context.Context) error {
-	schema := `
-	CREATE TABLE IF NOT EXISTS events (
-		id SERIAL PRIMARY KEY,
-		event_type VARCHAR(100) NOT NULL,
-		signature VARCHAR(255) UNIQUE NOT NULL,
-		slot BIGINT NOT NULL,
-		block_time TIMESTAMP NOT NULL,
-		program_id VARCHAR(44) NOT NULL,
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-		raw_data JSONB,
-		event_data JSONB NOT NULL
-	);
-
-	CREATE INDEX IF NOT EXISTS idx_events_event_type ON events(event_type);
-	CREATE INDEX IF NOT EXISTS idx_events_block_time ON events(block_time DESC);
-	CREATE INDEX IF NOT EXISTS idx_events_slot ON events(slot DESC);
-	CREATE INDEX IF NOT EXISTS idx_events_program_id ON events(program_id);
-	`
-
-	_, err := r.pool.Exec(ctx, schema)
+func (r *PostgresRepository) GetConfigHistory(ctx context.Context, limit int) ([]models.ConfigChange, error) {
+	rows, err := r.pool.Query(ctx, `
+SELECT program_id, source, COALESCE(signature, ''), slot, block_time, changed_fields, config, recorded_at
+FROM config_history ORDER BY slot DESC, recorded_at DESC LIMIT $1`, limit)
 	if err != nil {
-		return fmt.Errorf("create schema: %w", err)
+		return nil, fmt.Errorf("find config history: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []models.ConfigChange
+	for rows.Next() {
+		var (
+			change            models.ConfigChange
+			programID, source string
+			slot              int64
+			config            []byte
+		)
+		if err := rows.Scan(&programID, &source, &change.Signature, &slot, &change.BlockTime, &change.ChangedFields, &config, &change.RecordedAt); err != nil {
+			return nil, fmt.Errorf("scan config change: %w", err)
+		}
+		if change.ProgramID, err = solana.PublicKeyFromBase58(programID); err != nil {
+			return nil, fmt.Errorf("decode config change program: %w", err)
+		}
+		if err := json.Unmarshal(config, &change.Config); err != nil {
+			return nil, fmt.Errorf("decode config: %w", err)
+		}
+		change.Source = models.ConfigChangeSource(source)
+		change.Slot = uint64(slot)
+		changes = append(changes, change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("find config history: %w", err)
+	}
+	return changes, nil
+}
+
+// ExportEvents exports the stored event_data objects as the documents.
+func (r *PostgresRepository) ExportEvents(ctx context.Context, before time.Time, batchSize int, export func(ctx context.Context, events []ExportedEvent) error) (int64, error) {
+	var exported int64
+	for {
+		rows, err := r.pool.Query(ctx, "SELECT id, event_data FROM events WHERE block_time < $1 ORDER BY block_time, id LIMIT $2", before.UTC(), batchSize)
+		if err != nil {
+			return exported, fmt.Errorf("find events to export: %w", err)
+		}
+		var (
+			events []ExportedEvent
+			ids    []int64
+		)
+		for rows.Next() {
+			var (
+				id    int64
+				event ExportedEvent
+			)
+			if err := rows.Scan(&id, &event.Document); err != nil {
+				rows.Close()
+				return exported, fmt.Errorf("scan event to export: %w", err)
+			}
+			if err := json.Unmarshal(event.Document, &event.BaseEvent); err != nil {
+				rows.Close()
+				return exported, fmt.Errorf("decode event to export: %w", err)
+			}
+			events = append(events, event)
+			ids = append(ids, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return exported, fmt.Errorf("find events to export: %w", err)
+		}
+		if len(events) == 0 {
+			break
+		}
+
+		if err := export(ctx, events); err != nil {
+			return exported, fmt.Errorf("export events: %w", err)
+		}
+
+		tag, err := r.pool.Exec(ctx, "DELETE FROM events WHERE id = ANY($1)", ids)
+		if err != nil {
+			return exported, fmt.Errorf("delete exported events: %w", err)
+		}
+		exported += tag.RowsAffected()
+		if len(events) < batchSize || tag.RowsAffected() == 0 {
+			break
+		}
 	}
+	return exported, nil
+}
 
+func (r *PostgresRepository) Close(ctx context.Context) error {
+	r.pool.Close()
 	return nil
 }
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// sqlQuery collects the conditions and positional arguments of a query on
+// the events table.
+type sqlQuery struct {
+	conds []string
+	args  []interface{}
+}
+
+// arg adds a positional argument and returns its placeholder.
+func (q *sqlQuery) arg(v interface{}) string {
+	q.args = append(q.args, v)
+	return "$" + strconv.Itoa(len(q.args))
+}
+
+// tenant scopes the query to the tenant of ctx, if any, like tenantFilter.
+func (q *sqlQuery) tenant(ctx context.Context) {
+	if tenant := TenantFromContext(ctx); tenant != "" {
+		q.conds = append(q.conds, "event_data->>'tenant' = "+q.arg(tenant))
+	}
+}
+
+func (q *sqlQuery) where() string {
+	if len(q.conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(q.conds, " AND ")
+}
+
+// sqlFilter adds the conditions of f, which match those of mongoFilter.
+func (f EventFilter) sqlFilter(q *sqlQuery) {
+	if len(f.EventTypes) > 0 {
+		types := make([]string, len(f.EventTypes))
+		for i, t := range f.EventTypes {
+			types[i] = string(t)
+		}
+		q.conds = append(q.conds, "event_type = ANY("+q.arg(types)+")")
+	}
+	if !f.From.IsZero() {
+		q.conds = append(q.conds, "block_time >= "+q.arg(f.From))
+	}
+	if !f.To.IsZero() {
+		q.conds = append(q.conds, "block_time < "+q.arg(f.To))
+	}
+	if f.FromSlot > 0 {
+		q.conds = append(q.conds, "slot >= "+q.arg(int64(f.FromSlot)))
+	}
+	if f.ToSlot > 0 {
+		q.conds = append(q.conds, "slot <= "+q.arg(int64(f.ToSlot)))
+	}
+	if f.Network != "" {
+		q.conds = append(q.conds, "event_data->>'network' = "+q.arg(f.Network))
+	}
+	if f.Account != nil {
+		account := q.arg(f.Account.String())
+		or := make([]string, len(accountFields))
+		for i, field := range accountFields {
+			or[i] = "event_data->>'" + field + "' = " + account
+		}
+		q.conds = append(q.conds, "("+strings.Join(or, " OR ")+")")
+	}
+}
+
+// sqlFilter adds the deployment, the network and the events after p.After,
+// like mongoFilter, and returns the ORDER BY and LIMIT clauses of the page.
+func (p PageOptions) sqlFilter(q *sqlQuery) string {
+	if p.Deployment != "" {
+		q.conds = append(q.conds, "event_data->>'deployment' = "+q.arg(p.Deployment))
+	}
+	if p.Network != "" {
+		q.conds = append(q.conds, "event_data->>'network' = "+q.arg(p.Network))
+	}
+	dir, op := "DESC", "<"
+	if p.Ascending {
+		dir, op = "ASC", ">"
+	}
+	if p.BySequence {
+		// Sequences start at 1, so events without one sort first, as in
+		// MongoDB.
+		if p.After != nil {
+			q.conds = append(q.conds, "(slot, COALESCE(sequence, 0), signature) "+op+" ("+q.arg(int64(p.After.Slot))+", "+q.arg(int64(p.After.Sequence))+", "+q.arg(p.After.Signature)+")")
+		}
+		clauses := " ORDER BY slot " + dir + ", COALESCE(sequence, 0) " + dir + ", signature " + dir
+		if p.Limit > 0 {
+			clauses += " LIMIT " + q.arg(p.Limit+1)
+		}
+		return clauses
+	}
+	if p.After != nil {
+		q.conds = append(q.conds, "(slot, signature) "+op+" ("+q.arg(int64(p.After.Slot))+", "+q.arg(p.After.Signature)+")")
+	}
+	clauses := " ORDER BY slot " + dir + ", signature " + dir
+	if p.Limit > 0 {
+		clauses += " LIMIT " + q.arg(p.Limit+1)
+	}
+	return clauses
+}
+
+// QueryEvents returns one page of the events matching query.Filter, as the
+// stored event_data objects.
+func (r *PostgresRepository) QueryEvents(ctx context.Context, query EventQuery) (*EventPage, error) {
+	return r.queryEvents(ctx, query.Filter, query.Page, func(data []byte, _ uint64) (interface{}, error) {
+		var event map[string]interface{}
+		if err := json.Unmarshal(data, &event); err != nil {
+			return nil, fmt.Errorf("decode event: %w", err)
+		}
+		if err := decompressRawData(event); err != nil {
+			return nil, err
+		}
+		if len(query.Fields) > 0 {
+			projected := make(map[string]interface{}, len(query.Fields))
+			for _, field := range query.Fields {
+				if v, ok := event[field]; ok {
+					projected[field] = v
+				}
+			}
+			event = projected
+		}
+		return event, nil
+	})
+}
+
+// queryModels returns one page of the events matching filter decoded like
+// DecodeEvent, as MongoRepository returns them.
+func (r *PostgresRepository) queryModels(ctx context.Context, filter EventFilter, page PageOptions) (*EventPage, error) {
+	return r.queryEvents(ctx, filter, page, decodeEventData)
+}
+
+func (r *PostgresRepository) queryEvents(ctx context.Context, filter EventFilter, page PageOptions, decode func(data []byte, sequence uint64) (interface{}, error)) (*EventPage, error) {
+	var q sqlQuery
+	filter.sqlFilter(&q)
+	q.tenant(ctx)
+	clauses := page.sqlFilter(&q)
+
+	rows, err := r.pool.Query(ctx, "SELECT slot, COALESCE(sequence, 0), signature, event_data FROM events"+q.where()+clauses, q.args...)
+	if err != nil {
+		return nil, fmt.Errorf("query events: %w", err)
+	}
+	defer rows.Close()
+
+	result := &EventPage{Events: []interface{}{}}
+	var last Cursor
+	for rows.Next() {
+		if page.Limit > 0 && len(result.Events) == page.Limit {
+			result.Next = &last
+			break
+		}
+		var (
+			slot, sequence int64
+			data           []byte
+		)
+		if err := rows.Scan(&slot, &sequence, &last.Signature, &data); err != nil {
+			return nil, fmt.Errorf("scan event: %w", err)
+		}
+		last.Slot = uint64(slot)
+		if page.BySequence {
+			last.Sequence = uint64(sequence)
+		}
+
+		event, err := decode(data, uint64(sequence))
+		if err != nil {
+			return nil, err
+		}
+		result.Events = append(result.Events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query events: %w", err)
+	}
+	return result, nil
+}
+
+// decodeEventData decodes a stored event_data object into the model of its
+// type, like DecodeEvent, or into a map for a type without one. The
+// sequence is kept in its own column, so it is set from there.
+func decodeEventData(data []byte, sequence uint64) (interface{}, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("decode event: %w", err)
+	}
+	var eventType string
+	_ = json.Unmarshal(doc["event_type"], &eventType)
+
+	var model models.Event
+	if m, ok := models.NewEventModel(models.EventType(eventType)); ok {
+		model = m
+	} else if _, ok := doc["fields"]; ok {
+		model = &models.LogEvent{}
+	} else {
+		var event map[string]interface{}
+		if err := json.Unmarshal(data, &event); err != nil {
+			return nil, fmt.Errorf("decode event: %w", err)
+		}
+		if err := decompressRawData(event); err != nil {
+			return nil, err
+		}
+		return event, nil
+	}
+	if err := json.Unmarshal(data, model); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", eventType, err)
+	}
+	if sequence > 0 {
+		model.Base().Sequence = sequence
+	}
+	if err := decompressRawData(model); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
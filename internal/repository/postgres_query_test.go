@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+func TestEventQuery_SQL(t *testing.T) {
+	from := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	account := solana.PublicKey{1}
+	tests := []struct {
+		name      string
+		filter    EventFilter
+		page      PageOptions
+		wantWhere string
+		wantOrder string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "everything newest first",
+			wantOrder: " ORDER BY slot DESC, signature DESC",
+		},
+		{
+			name:      "types, time and slot ranges",
+			filter:    EventFilter{EventTypes: []models.EventType{models.EventTypeCounterReset, models.EventTypeCounterAdded}, From: from, FromSlot: 10, ToSlot: 20},
+			page:      PageOptions{Limit: 50, Ascending: true},
+			wantWhere: " WHERE event_type = ANY($1) AND block_time >= $2 AND slot >= $3 AND slot <= $4",
+			wantOrder: " ORDER BY slot ASC, signature ASC LIMIT $5",
+			wantArgs:  []interface{}{[]string{"CounterResetEvent", "CounterAddedEvent"}, from, int64(10), int64(20), 51},
+		},
+		{
+			name:      "deployment after cursor",
+			page:      PageOptions{Deployment: "devnet", After: &Cursor{Slot: 5, Signature: "s"}},
+			wantWhere: " WHERE event_data->>'deployment' = $1 AND (slot, signature) < ($2, $3)",
+			wantOrder: " ORDER BY slot DESC, signature DESC",
+			wantArgs:  []interface{}{"devnet", int64(5), "s"},
+		},
+		{
+			name:      "network",
+			page:      PageOptions{Network: "mainnet"},
+			wantWhere: " WHERE event_data->>'network' = $1",
+			wantOrder: " ORDER BY slot DESC, signature DESC",
+			wantArgs:  []interface{}{"mainnet"},
+		},
+		{
+			name:      "type oldest first after cursor",
+			filter:    EventFilter{EventTypes: []models.EventType{models.EventTypeCounterReset}},
+			page:      PageOptions{Limit: 2, Ascending: true, After: &Cursor{Slot: 5, Signature: "s"}},
+			wantWhere: " WHERE event_type = ANY($1) AND (slot, signature) > ($2, $3)",
+			wantOrder: " ORDER BY slot ASC, signature ASC LIMIT $4",
+			wantArgs:  []interface{}{[]string{"CounterResetEvent"}, int64(5), "s", 3},
+		},
+		{
+			name:      "by sequence after cursor",
+			page:      PageOptions{Limit: 20, BySequence: true, After: &Cursor{Slot: 5, Sequence: 7, Signature: "s"}},
+			wantWhere: " WHERE (slot, COALESCE(sequence, 0), signature) < ($1, $2, $3)",
+			wantOrder: " ORDER BY slot DESC, COALESCE(sequence, 0) DESC, signature DESC LIMIT $4",
+			wantArgs:  []interface{}{int64(5), int64(7), "s", 21},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var q sqlQuery
+			tt.filter.sqlFilter(&q)
+			order := tt.page.sqlFilter(&q)
+			if got := q.where(); got != tt.wantWhere {
+				t.Errorf("where() = %q, want %q", got, tt.wantWhere)
+			}
+			if order != tt.wantOrder {
+				t.Errorf("sqlFilter() = %q, want %q", order, tt.wantOrder)
+			}
+			if !reflect.DeepEqual(q.args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", q.args, tt.wantArgs)
+			}
+		})
+	}
+
+	t.Run("account", func(t *testing.T) {
+		var q sqlQuery
+		EventFilter{Account: &account}.sqlFilter(&q)
+		where := q.where()
+		if strings.Count(where, "= $1") != len(accountFields) || !strings.Contains(where, "event_data->>'mint' = $1 OR ") {
+			t.Errorf("where() = %q, want every account field matched against $1", where)
+		}
+		if !reflect.DeepEqual(q.args, []interface{}{account.String()}) {
+			t.Errorf("args = %v, want [%s]", q.args, account)
+		}
+	})
+}
+
+func TestDecodeEventData(t *testing.T) {
+	minted, err := models.NewTokensMintedEvent(solana.PublicKey{1}, solana.PublicKey{2}, 500, 1700000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	minted.EventType = models.EventTypeTokensMinted
+	minted.Signature = "sig"
+	minted.RawData = []byte("raw event data")
+	row, err := eventRow(minted, RawDataGzip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := row[6].(json.RawMessage)
+
+	event, err := decodeEventData(stored, 7)
+	if err != nil {
+		t.Fatalf("decodeEventData() error = %v", err)
+	}
+	got, ok := event.(*models.TokensMintedEvent)
+	if !ok {
+		t.Fatalf("decodeEventData() = %T, want *models.TokensMintedEvent", event)
+	}
+	if got.Amount != 500 || got.Signature != "sig" || got.Sequence != 7 {
+		t.Errorf("decodeEventData() = %+v, want amount 500, signature sig and sequence 7", got)
+	}
+	if string(got.RawData) != "raw event data" || got.RawDataEncoding != "" {
+		t.Errorf("RawData = %q (%q), want it decompressed", got.RawData, got.RawDataEncoding)
+	}
+
+	tests := []struct {
+		name string
+		data string
+		want interface{}
+	}{
+		{
+			name: "log event",
+			data: `{"event_type":"Swap","fields":{"amount":5}}`,
+			want: &models.LogEvent{},
+		},
+		{
+			name: "type without a model",
+			data: `{"event_type":"Unknown","amount":5}`,
+			want: map[string]interface{}{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := decodeEventData([]byte(tt.data), 0)
+			if err != nil {
+				t.Fatalf("decodeEventData() error = %v", err)
+			}
+			if reflect.TypeOf(event) != reflect.TypeOf(tt.want) {
+				t.Errorf("decodeEventData() = %T, want %T", event, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// RawDataCompression is how BaseEvent.RawData is compressed when an event
+// is stored. The compression is recorded in BaseEvent.RawDataEncoding, so
+// events stored with another compression, or none, still read back.
+type RawDataCompression string
+
+const (
+	RawDataUncompressed RawDataCompression = ""
+	RawDataGzip         RawDataCompression = "gzip"
+	RawDataZstd         RawDataCompression = "zstd"
+)
+
+// ParseRawDataCompression parses RAW_DATA_COMPRESSION: none, gzip or zstd.
+func ParseRawDataCompression(s string) (RawDataCompression, error) {
+	switch s {
+	case "", "none":
+		return RawDataUncompressed, nil
+	case string(RawDataGzip):
+		return RawDataGzip, nil
+	case string(RawDataZstd):
+		return RawDataZstd, nil
+	default:
+		return "", fmt.Errorf("unknown raw data compression %q", s)
+	}
+}
+
+var (
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+	zstdErr     error
+)
+
+// zstdCodec returns the encoder and decoder shared by all events; both are
+// safe for concurrent EncodeAll and DecodeAll calls.
+func zstdCodec() (*zstd.Encoder, *zstd.Decoder, error) {
+	zstdOnce.Do(func() {
+		if zstdEncoder, zstdErr = zstd.NewWriter(nil); zstdErr != nil {
+			return
+		}
+		zstdDecoder, zstdErr = zstd.NewReader(nil)
+	})
+	return zstdEncoder, zstdDecoder, zstdErr
+}
+
+func compress(data []byte, c RawDataCompression) ([]byte, error) {
+	switch c {
+	case RawDataGzip:
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(data); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case RawDataZstd:
+		enc, _, err := zstdCodec()
+		if err != nil {
+			return nil, err
+		}
+		return enc.EncodeAll(data, nil), nil
+	default:
+		return nil, fmt.Errorf("unknown raw data compression %q", c)
+	}
+}
+
+func decompress(data []byte, c RawDataCompression) ([]byte, error) {
+	switch c {
+	case RawDataGzip:
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		return io.ReadAll(zr)
+	case RawDataZstd:
+		_, dec, err := zstdCodec()
+		if err != nil {
+			return nil, err
+		}
+		return dec.DecodeAll(data, nil)
+	default:
+		return nil, fmt.Errorf("unknown raw data compression %q", c)
+	}
+}
+
+// compressRawData compresses the raw data of event in place for storing it
+// and returns a function restoring the original, so callers and sinks keep
+// seeing the raw data uncompressed.
+func compressRawData(event models.Event, c RawDataCompression) (restore func(), err error) {
+	if c == RawDataUncompressed {
+		return func() {}, nil
+	}
+	base := event.Base()
+	if len(base.RawData) == 0 || base.RawDataEncoding != "" {
+		return func() {}, nil
+	}
+	compressed, err := compress(base.RawData, c)
+	if err != nil {
+		return nil, fmt.Errorf("compress %s raw data: %w", base.EventType, err)
+	}
+	raw := base.RawData
+	base.RawData, base.RawDataEncoding = compressed, string(c)
+	return func() { base.RawData, base.RawDataEncoding = raw, "" }, nil
+}
+
+// decompressRawData decompresses the raw data of an event read back: a
+// model or, as Postgres returns events, a JSON document.
+func decompressRawData(event interface{}) error {
+	switch e := event.(type) {
+	case models.Event:
+		base := e.Base()
+		if base.RawDataEncoding == "" {
+			return nil
+		}
+		raw, err := decompress(base.RawData, RawDataCompression(base.RawDataEncoding))
+		if err != nil {
+			return fmt.Errorf("decompress %s raw data: %w", base.EventType, err)
+		}
+		base.RawData, base.RawDataEncoding = raw, ""
+	case map[string]interface{}:
+		encoding, _ := e["raw_data_encoding"].(string)
+		encoded, _ := e["raw_data"].(string)
+		if encoding == "" || encoded == "" {
+			return nil
+		}
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return fmt.Errorf("decode raw data: %w", err)
+		}
+		raw, err := decompress(data, RawDataCompression(encoding))
+		if err != nil {
+			return fmt.Errorf("decompress %v raw data: %w", e["event_type"], err)
+		}
+		e["raw_data"] = base64.StdEncoding.EncodeToString(raw)
+		delete(e, "raw_data_encoding")
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestParseRawDataCompression(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    RawDataCompression
+		wantErr bool
+	}{
+		{"", RawDataUncompressed, false},
+		{"none", RawDataUncompressed, false},
+		{"gzip", RawDataGzip, false},
+		{"zstd", RawDataZstd, false},
+		{"lz4", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseRawDataCompression(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseRawDataCompression(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestRawData_RoundTrip(t *testing.T) {
+	raw := bytes.Repeat([]byte("instruction data "), 64)
+	for _, c := range []RawDataCompression{RawDataGzip, RawDataZstd} {
+		t.Run(string(c), func(t *testing.T) {
+			event := &models.CounterResetEvent{BaseEvent: models.BaseEvent{EventType: models.EventTypeCounterReset, RawData: raw}}
+
+			restore, err := compressRawData(event, c)
+			if err != nil {
+				t.Fatalf("compressRawData() error = %v", err)
+			}
+			if event.RawDataEncoding != string(c) || len(event.RawData) >= len(raw) {
+				t.Errorf("compressRawData() = %d bytes with encoding %q, want fewer than %d with %q", len(event.RawData), event.RawDataEncoding, len(raw), c)
+			}
+			stored := *event
+
+			restore()
+			if !bytes.Equal(event.RawData, raw) || event.RawDataEncoding != "" {
+				t.Errorf("restore() did not restore the raw data")
+			}
+
+			if err := decompressRawData(&stored); err != nil {
+				t.Fatalf("decompressRawData() error = %v", err)
+			}
+			if !bytes.Equal(stored.RawData, raw) || stored.RawDataEncoding != "" {
+				t.Errorf("decompressRawData() = %q, %q, want the original raw data", stored.RawData, stored.RawDataEncoding)
+			}
+		})
+	}
+}
+
+// TestRawData_StoredDocument stores an event the way MongoRepository.SaveEvent
+// does and reads it back through DecodeEvent.
+func TestRawData_StoredDocument(t *testing.T) {
+	raw := bytes.Repeat([]byte{0xf3, 0x2a, 0, 0, 0, 0, 0, 0, 0, 7}, 32)
+	event := &models.CounterResetEvent{BaseEvent: models.BaseEvent{EventType: models.EventTypeCounterReset, Signature: "sig", RawData: raw}, OldValue: 7}
+
+	restore, err := compressRawData(event, RawDataZstd)
+	if err != nil {
+		t.Fatalf("compressRawData() error = %v", err)
+	}
+	doc, err := bson.Marshal(event)
+	restore()
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if _, stored, _ := bson.Raw(doc).Lookup("raw_data").BinaryOK(); len(stored) >= len(raw) {
+		t.Errorf("stored raw_data = %d bytes, want fewer than %d", len(stored), len(raw))
+	}
+
+	decoded, err := DecodeEvent(doc)
+	if err != nil {
+		t.Fatalf("DecodeEvent() error = %v", err)
+	}
+	got, ok := decoded.(*models.CounterResetEvent)
+	if !ok || !bytes.Equal(got.RawData, raw) || got.RawDataEncoding != "" || got.OldValue != 7 {
+		t.Errorf("DecodeEvent() = %+v, want the event with its raw data", decoded)
+	}
+}
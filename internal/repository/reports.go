@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// ReportStore is implemented by repositories that can persist scheduled
+// reports.
+type ReportStore interface {
+	// SaveReport creates or replaces the report with the same name.
+	SaveReport(ctx context.Context, report *models.Report) error
+	GetReports(ctx context.Context) ([]models.Report, error)
+	// GetReport returns nil when no report has the name.
+	GetReport(ctx context.Context, name string) (*models.Report, error)
+	DeleteReport(ctx context.Context, name string) (bool, error)
+	RecordReportRun(ctx context.Context, name string, at time.Time, runErr string) error
+}
@@ -4,13 +4,36 @@ import (
 	"context"
 	"time"
 
+	"github.com/gagliardetto/solana-go"
 	"github.com/lugondev/go-indexer-solana-starter/internal/models"
 )
 
 type Repository interface {
-	SaveEvent(ctx context.Context, event interface{}) error
+	SaveEvent(ctx context.Context, event models.Event) error
 	GetEventsByTimeRange(ctx context.Context, from, to time.Time) ([]models.BaseEvent, error)
-	GetEventsByType(ctx context.Context, eventType models.EventType, limit int) ([]interface{}, error)
+	GetEventsByType(ctx context.Context, eventType models.EventType, page PageOptions) (*EventPage, error)
 	GetEventBySignature(ctx context.Context, signature string) (interface{}, error)
+	GetEventsByAccount(ctx context.Context, account solana.PublicKey, opts AccountEventsOptions) (*EventPage, error)
+	SaveConfigChange(ctx context.Context, change *models.ConfigChange) error
+	GetConfigHistory(ctx context.Context, limit int) ([]models.ConfigChange, error)
 	Close(ctx context.Context) error
 }
+
+// AccountEventsOptions pages through the events that reference an account.
+type AccountEventsOptions struct {
+	PageOptions
+	// EventTypes restricts the timeline to these types; empty means all.
+	EventTypes []models.EventType
+}
+
+// Unwrap returns the innermost repository beneath any decorators such as
+// CachedRepository, so callers can reach backend specific methods.
+func Unwrap(repo Repository) Repository {
+	for {
+		wrapper, ok := repo.(interface{ Unwrap() Repository })
+		if !ok {
+			return repo
+		}
+		repo = wrapper.Unwrap()
+	}
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// RetentionPolicy bounds how long events are kept. A zero MaxAge keeps
+// events forever; PerType overrides MaxAge for individual event types, where
+// zero again means forever.
+type RetentionPolicy struct {
+	MaxAge  time.Duration
+	PerType map[models.EventType]time.Duration
+	// Archive copies expired events to an archive before deleting them.
+	Archive bool
+}
+
+// Pruner is implemented by repositories that can enforce a RetentionPolicy.
+type Pruner interface {
+	PruneEvents(ctx context.Context, policy RetentionPolicy, now time.Time) (int64, error)
+}
+
+// retentionRule selects expired events: those older than Before of
+// EventType, or, when EventType is empty, of any type not in Exclude.
+type retentionRule struct {
+	EventType models.EventType
+	Exclude   []models.EventType
+	Before    time.Time
+}
+
+func (p RetentionPolicy) rules(now time.Time) []retentionRule {
+	types := make([]models.EventType, 0, len(p.PerType))
+	for eventType := range p.PerType {
+		types = append(types, eventType)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+
+	var rules []retentionRule
+	for _, eventType := range types {
+		if maxAge := p.PerType[eventType]; maxAge > 0 {
+			rules = append(rules, retentionRule{EventType: eventType, Before: now.Add(-maxAge)})
+		}
+	}
+	if p.MaxAge > 0 {
+		rules = append(rules, retentionRule{Exclude: types, Before: now.Add(-p.MaxAge)})
+	}
+	return rules
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+func TestRetentionPolicy_Rules(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	policy := RetentionPolicy{
+		MaxAge: 30 * day,
+		PerType: map[models.EventType]time.Duration{
+			models.EventTypeCounterIncremented: 7 * day,
+			models.EventTypeConfigUpdated:      0, // kept forever
+		},
+	}
+
+	want := []retentionRule{
+		{EventType: models.EventTypeCounterIncremented, Before: now.Add(-7 * day)},
+		{
+			Exclude: []models.EventType{models.EventTypeConfigUpdated, models.EventTypeCounterIncremented},
+			Before:  now.Add(-30 * day),
+		},
+	}
+	if got := policy.rules(now); !reflect.DeepEqual(got, want) {
+		t.Errorf("rules() = %+v, want %+v", got, want)
+	}
+
+	if got := (RetentionPolicy{}).rules(now); len(got) != 0 {
+		t.Errorf("empty policy rules() = %+v, want none", got)
+	}
+}
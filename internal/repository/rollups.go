@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RollupStore is implemented by repositories that keep the event rollup
+// tables, "event_rollups_minute" and "event_rollups_hour".
+type RollupStore interface {
+	// AddEventRollups adds the counts of rollups to those of their buckets.
+	AddEventRollups(ctx context.Context, resolution models.RollupResolution, rollups []models.EventRollup) error
+}
+
+func rollupTable(resolution models.RollupResolution) string {
+	return "event_rollups_" + string(resolution)
+}
+
+func (r *MongoRepository) AddEventRollups(ctx context.Context, resolution models.RollupResolution, rollups []models.EventRollup) error {
+	if len(rollups) == 0 {
+		return nil
+	}
+	now := time.Now().UTC()
+	writes := make([]mongo.WriteModel, len(rollups))
+	for i, rollup := range rollups {
+		writes[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"bucket": rollup.Bucket, "event_type": rollup.EventType, "program_id": rollup.ProgramID}).
+			SetUpdate(bson.M{
+				"$inc": bson.M{"count": rollup.Count},
+				"$set": bson.M{"updated_at": now},
+			}).
+			SetUpsert(true)
+	}
+	if _, err := r.database.Collection(rollupTable(resolution)).BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+		return fmt.Errorf("add %s event rollups: %w", resolution, err)
+	}
+	return nil
+}
+
+func (r *MongoRepository) createRollupIndexes(ctx context.Context) error {
+	for _, resolution := range models.RollupResolutions {
+		_, err := r.database.Collection(rollupTable(resolution)).Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "bucket", Value: -1}, {Key: "event_type", Value: 1}, {Key: "program_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		})
+		if err != nil {
+			return fmt.Errorf("create %s event rollup indexes: %w", resolution, err)
+		}
+	}
+	return nil
+}
+
+func (r *PostgresRepository) AddEventRollups(ctx context.Context, resolution models.RollupResolution, rollups []models.EventRollup) error {
+	if len(rollups) == 0 {
+		return nil
+	}
+	var (
+		buckets    = make([]time.Time, len(rollups))
+		eventTypes = make([]string, len(rollups))
+		programs   = make([]string, len(rollups))
+		counts     = make([]int64, len(rollups))
+	)
+	for i, rollup := range rollups {
+		buckets[i], eventTypes[i], programs[i], counts[i] = rollup.Bucket, string(rollup.EventType), rollup.ProgramID, rollup.Count
+	}
+	table := rollupTable(resolution)
+	_, err := r.pool.Exec(ctx, "INSERT INTO "+table+" (bucket, event_type, program_id, count) "+
+		"SELECT * FROM unnest($1::timestamptz[], $2::text[], $3::text[], $4::bigint[]) "+
+		"ON CONFLICT (bucket, event_type, program_id) DO UPDATE SET count = "+table+".count + EXCLUDED.count, updated_at = now()",
+		buckets, eventTypes, programs, counts)
+	if err != nil {
+		return fmt.Errorf("add %s event rollups: %w", resolution, err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SavedQueryStore is implemented by repositories that can persist saved
+// queries.
+type SavedQueryStore interface {
+	// SaveQuery creates or replaces the query with the same name.
+	SaveQuery(ctx context.Context, query *models.SavedQuery) error
+	GetQueries(ctx context.Context) ([]models.SavedQuery, error)
+	// GetQuery returns nil when no query has the name.
+	GetQuery(ctx context.Context, name string) (*models.SavedQuery, error)
+	DeleteQuery(ctx context.Context, name string) (bool, error)
+}
+
+// EventQuery is an event query assembled at run time, e.g. from a saved
+// query.
+type EventQuery struct {
+	Filter EventFilter
+	Page   PageOptions
+	// Fields keeps only these fields of each event; empty keeps all.
+	Fields []string
+}
+
+// EventQuerier is implemented by repositories that can page through the
+// events matching an arbitrary filter.
+type EventQuerier interface {
+	QueryEvents(ctx context.Context, query EventQuery) (*EventPage, error)
+}
+
+func (r *MongoRepository) SaveQuery(ctx context.Context, query *models.SavedQuery) error {
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.savedQueries.ReplaceOne(ctx, bson.M{"_id": query.Name}, query, opts); err != nil {
+		return fmt.Errorf("save query: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoRepository) GetQueries(ctx context.Context) ([]models.SavedQuery, error) {
+	cursor, err := r.savedQueries.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("find saved queries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var queries []models.SavedQuery
+	if err := cursor.All(ctx, &queries); err != nil {
+		return nil, fmt.Errorf("decode saved queries: %w", err)
+	}
+	return queries, nil
+}
+
+func (r *MongoRepository) GetQuery(ctx context.Context, name string) (*models.SavedQuery, error) {
+	var query models.SavedQuery
+	err := r.savedQueries.FindOne(ctx, bson.M{"_id": name}).Decode(&query)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find saved query: %w", err)
+	}
+	return &query, nil
+}
+
+func (r *MongoRepository) DeleteQuery(ctx context.Context, name string) (bool, error) {
+	result, err := r.savedQueries.DeleteOne(ctx, bson.M{"_id": name})
+	if err != nil {
+		return false, fmt.Errorf("delete saved query: %w", err)
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// QueryEvents returns one page of the events matching query.Filter. Fields
+// are dropped after reading, since paging needs the slot and signature of
+// every event.
+func (r *MongoRepository) QueryEvents(ctx context.Context, query EventQuery) (*EventPage, error) {
+	names, err := r.filterCollections(ctx, query.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	decode := DecodeEvent
+	if len(query.Fields) > 0 {
+		decode = func(doc bson.Raw) (interface{}, error) {
+			return projectEvent(doc, query.Fields)
+		}
+	}
+	result, err := r.findPage(ctx, names, query.Filter.mongoFilter(), query.Page, decode)
+	if err != nil {
+		return nil, fmt.Errorf("query events: %w", err)
+	}
+	return result, nil
+}
+
+// projectEvent keeps the fields of an event document, like the Postgres
+// projection.
+func projectEvent(doc bson.Raw, fields []string) (interface{}, error) {
+	var event bson.M
+	if err := bson.Unmarshal(doc, &event); err != nil {
+		return nil, fmt.Errorf("decode event: %w", err)
+	}
+	projected := make(bson.M, len(fields))
+	for _, field := range fields {
+		if v, ok := event[field]; ok {
+			projected[field] = v
+		}
+	}
+	return projected, nil
+}
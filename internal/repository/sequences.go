@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SequenceStore is implemented by repositories that number the events of
+// each program and network as they are stored, see
+// models.BaseEvent.Sequence. A failed write may leave a gap, so consumers
+// must not wait for missing numbers.
+type SequenceStore interface {
+	// GetEventsAfterSequence returns up to limit events of program on
+	// network with a sequence above after, in sequence order. network is
+	// empty unless NETWORKS is set. It stops below the lowest sequence
+	// still being written, so no event appears after a consumer has read
+	// past its number.
+	GetEventsAfterSequence(ctx context.Context, network string, program solana.PublicKey, after uint64, limit int) ([]interface{}, error)
+}
+
+// sequenceCounter identifies the sequence counter of a program on a
+// network, which is empty unless NETWORKS is set.
+type sequenceCounter struct {
+	network string
+	program solana.PublicKey
+}
+
+func counterOf(event models.Event) sequenceCounter {
+	return sequenceCounter{network: event.Base().Network, program: event.Base().ProgramID}
+}
+
+// id is the _id of the counter document: the program, prefixed with the
+// network like the checkpoint of the program.
+func (c sequenceCounter) id() string {
+	return models.CheckpointID(c.network, c.program.String())
+}
+
+func (c sequenceCounter) String() string {
+	if c.network == "" {
+		return c.program.String()
+	}
+	return c.program.String() + " on " + c.network
+}
+
+// sequenceWatermark tracks the sequences reserved outside a transaction
+// that are not stored yet. Live polling and backfill write the same
+// program concurrently, so a lower sequence can be inserted after a higher
+// one; readers only see the events below the lowest of them.
+type sequenceWatermark struct {
+	mu sync.Mutex
+	// highest is the highest sequence reserved of each counter.
+	highest map[sequenceCounter]uint64
+	open    map[*sequenceReservation]struct{}
+}
+
+// sequenceReservation is a sequence being written. Until the number is
+// known, floor is the lowest it can be.
+type sequenceReservation struct {
+	counter sequenceCounter
+	floor   uint64
+}
+
+func newSequenceWatermark() *sequenceWatermark {
+	return &sequenceWatermark{
+		highest: make(map[sequenceCounter]uint64),
+		open:    make(map[*sequenceReservation]struct{}),
+	}
+}
+
+// begin is called before a sequence of counter is reserved, and end once
+// the event holding it is stored or has failed.
+func (w *sequenceWatermark) begin(counter sequenceCounter) *sequenceReservation {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	r := &sequenceReservation{counter: counter, floor: w.highest[counter] + 1}
+	w.open[r] = struct{}{}
+	return r
+}
+
+func (w *sequenceWatermark) reserved(r *sequenceReservation, sequence uint64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	r.floor = sequence
+	w.highest[r.counter] = max(w.highest[r.counter], sequence)
+}
+
+func (w *sequenceWatermark) end(r *sequenceReservation) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.open, r)
+}
+
+// below returns the lowest sequence of counter being written, false when
+// there is none.
+func (w *sequenceWatermark) below(counter sequenceCounter) (uint64, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var (
+		lowest uint64
+		found  bool
+	)
+	for r := range w.open {
+		if r.counter == counter && (!found || r.floor < lowest) {
+			lowest, found = r.floor, true
+		}
+	}
+	return lowest, found
+}
+
+// reserveSequences reserves n sequence numbers of a counter and returns the
+// first.
+func (r *MongoRepository) reserveSequences(ctx context.Context, counter sequenceCounter, n uint64) (uint64, error) {
+	var doc struct {
+		Value uint64 `bson:"value"`
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	update := bson.M{"$inc": bson.M{"value": int64(n)}}
+	if err := r.sequences.FindOneAndUpdate(ctx, bson.M{"_id": counter.id()}, update, opts).Decode(&doc); err != nil {
+		return 0, fmt.Errorf("reserve sequence of %s: %w", counter, err)
+	}
+	return doc.Value - n + 1, nil
+}
+
+// assignSequence numbers event. A number it already has is replaced: it
+// may come from a transaction that was rolled back, or from another store.
+// done must be called once the event is stored or has failed.
+//
+// Inside a transaction the counter stays locked until the transaction
+// ends, which orders concurrent writers already; outside of one the
+// number is tracked by the watermark until done.
+func (r *MongoRepository) assignSequence(ctx context.Context, e models.Event) (done func(), err error) {
+	counter := counterOf(e)
+	if mongo.SessionFromContext(ctx) != nil {
+		sequence, err := r.reserveSequences(ctx, counter, 1)
+		if err != nil {
+			return nil, err
+		}
+		e.Base().Sequence = sequence
+		return func() {}, nil
+	}
+
+	reservation := r.watermark.begin(counter)
+	sequence, err := r.reserveSequences(ctx, counter, 1)
+	if err != nil {
+		r.watermark.end(reservation)
+		return nil, err
+	}
+	r.watermark.reserved(reservation, sequence)
+	e.Base().Sequence = sequence
+	return func() { r.watermark.end(reservation) }, nil
+}
+
+func (r *MongoRepository) GetEventsAfterSequence(ctx context.Context, network string, program solana.PublicKey, after uint64, limit int) ([]interface{}, error) {
+	names, err := r.eventCollections(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	events := []interface{}{}
+	if len(names) == 0 {
+		return events, nil
+	}
+
+	sequence := bson.M{"$gt": after}
+	if below, ok := r.watermark.below(sequenceCounter{network: network, program: program}); ok {
+		sequence["$lt"] = below
+	}
+	filter := bson.M{"program_id": program, "sequence": sequence}
+	if network != "" {
+		filter["network"] = network
+	}
+	cursor, err := r.openEvents(ctx, names, filter, bson.D{{Key: "sequence", Value: 1}}, int64(limit))
+	if err != nil {
+		return nil, fmt.Errorf("find events after sequence: %w", err)
+	}
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		event, err := DecodeEvent(bson.Raw(cursor.Current))
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("find events after sequence: %w", err)
+	}
+	return events, nil
+}
+
+// The sequence counter row of a program stays locked until the event
+// holding the number is committed, so PostgreSQL commits sequences in
+// order and needs no watermark.
+func (r *PostgresRepository) GetEventsAfterSequence(ctx context.Context, network string, program solana.PublicKey, after uint64, limit int) ([]interface{}, error) {
+	q := &sqlQuery{}
+	q.conds = append(q.conds, "program_id = "+q.arg(program.String()), "sequence > "+q.arg(int64(after)))
+	if network != "" {
+		q.conds = append(q.conds, "event_data->>'network' = "+q.arg(network))
+	}
+	q.tenant(ctx)
+	rows, err := r.pool.Query(ctx, "SELECT event_type, event_data, sequence FROM events"+q.where()+" ORDER BY sequence LIMIT "+q.arg(limit), q.args...)
+	if err != nil {
+		return nil, fmt.Errorf("query events after sequence: %w", err)
+	}
+	defer rows.Close()
+
+	events := []interface{}{}
+	for rows.Next() {
+		var (
+			eventType string
+			data      []byte
+			sequence  int64
+		)
+		if err := rows.Scan(&eventType, &data, &sequence); err != nil {
+			return nil, fmt.Errorf("scan event: %w", err)
+		}
+		event, ok := models.NewEventModel(models.EventType(eventType))
+		if !ok {
+			event = &models.LogEvent{}
+		}
+		if err := json.Unmarshal(data, event); err != nil {
+			return nil, fmt.Errorf("decode %s: %w", eventType, err)
+		}
+		event.Base().Sequence = uint64(sequence)
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query events after sequence: %w", err)
+	}
+	return events, nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+func TestSequenceWatermark(t *testing.T) {
+	id := solana.MustPublicKeyFromBase58("CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc")
+	program := sequenceCounter{program: id}
+	other := sequenceCounter{program: solana.MustPublicKeyFromBase58("gARh1g6reuvsAHB7DXqiuYzzyiJeoiJmtmCpV8Y5uWC")}
+	otherNetwork := sequenceCounter{network: "devnet", program: id}
+	w := newSequenceWatermark()
+
+	if _, ok := w.below(program); ok {
+		t.Fatal("below() with nothing in flight = true, want false")
+	}
+
+	live := w.begin(program)
+	w.reserved(live, 5)
+	backfill := w.begin(program)
+	if got, ok := w.below(program); !ok || got != 5 {
+		t.Errorf("below() with 5 in flight = %d, %v, want 5, true", got, ok)
+	}
+	if _, ok := w.below(other); ok {
+		t.Error("below() of another program = true, want false")
+	}
+	if _, ok := w.below(otherNetwork); ok {
+		t.Error("below() of the program on another network = true, want false")
+	}
+
+	// The backfill number is not known yet, but cannot be lower than 6.
+	w.end(live)
+	if got, ok := w.below(program); !ok || got != 6 {
+		t.Errorf("below() while reserving = %d, %v, want 6, true", got, ok)
+	}
+	w.reserved(backfill, 6)
+	w.end(backfill)
+	if got, ok := w.below(program); ok {
+		t.Errorf("below() once stored = %d, true, want false", got)
+	}
+}
+
+func TestSequenceCounter_ID(t *testing.T) {
+	program := solana.MustPublicKeyFromBase58("CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc")
+	tests := []struct {
+		network string
+		want    string
+	}{
+		{"", "CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc"},
+		{"devnet", "devnet:CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc"},
+	}
+	for _, tt := range tests {
+		if got := (sequenceCounter{network: tt.network, program: program}).id(); got != tt.want {
+			t.Errorf("id() on %q = %q, want %q", tt.network, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// StateStore is implemented by repositories that can dump and restore
+// everything they store: the events with their dedup metadata, the
+// checkpoints and the projections built from the events.
+type StateStore interface {
+	// StateCollections lists the collections or tables holding state.
+	StateCollections(ctx context.Context) ([]string, error)
+	// DumpState calls fn with every document of a collection, in the
+	// backend's own JSON encoding.
+	DumpState(ctx context.Context, collection string, fn func(doc json.RawMessage) error) error
+	// ClearState deletes every document of a collection.
+	ClearState(ctx context.Context, collection string) error
+	// RestoreState inserts documents written by DumpState.
+	RestoreState(ctx context.Context, collection string, docs []json.RawMessage) error
+}
+
+func (r *MongoRepository) StateCollections(ctx context.Context) ([]string, error) {
+	names, err := r.database.ListCollectionNames(ctx, bson.M{"type": "collection"})
+	if err != nil {
+		return nil, fmt.Errorf("list collections: %w", err)
+	}
+	state := names[:0]
+	for _, name := range names {
+		if !strings.HasPrefix(name, "system.") {
+			state = append(state, name)
+		}
+	}
+	return state, nil
+}
+
+// DumpState writes documents as canonical extended JSON, which keeps BSON
+// types such as dates and 64-bit integers.
+func (r *MongoRepository) DumpState(ctx context.Context, collection string, fn func(doc json.RawMessage) error) error {
+	cursor, err := r.database.Collection(collection).Find(ctx, bson.M{})
+	if err != nil {
+		return fmt.Errorf("find %s: %w", collection, err)
+	}
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		doc, err := bson.MarshalExtJSON(bson.Raw(cursor.Current), true, false)
+		if err != nil {
+			return fmt.Errorf("encode %s document: %w", collection, err)
+		}
+		if err := fn(doc); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("read %s: %w", collection, err)
+	}
+	return nil
+}
+
+func (r *MongoRepository) ClearState(ctx context.Context, collection string) error {
+	if _, err := r.database.Collection(collection).DeleteMany(ctx, bson.M{}); err != nil {
+		return fmt.Errorf("clear %s: %w", collection, err)
+	}
+	return nil
+}
+
+func (r *MongoRepository) RestoreState(ctx context.Context, collection string, docs []json.RawMessage) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	if strings.HasPrefix(collection, "system.") || strings.ContainsAny(collection, "$\x00") {
+		return fmt.Errorf("collection name %q cannot be restored", collection)
+	}
+	values := make([]interface{}, len(docs))
+	for n, data := range docs {
+		var doc bson.D
+		if err := bson.UnmarshalExtJSON(data, true, &doc); err != nil {
+			return fmt.Errorf("decode %s document: %w", collection, err)
+		}
+		values[n] = doc
+	}
+	if _, err := r.database.Collection(collection).InsertMany(ctx, values); err != nil {
+		return fmt.Errorf("restore %s: %w", collection, err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// EventFilter selects events for streaming reads and deletes. Zero fields
+// match everything; From is inclusive and To exclusive, while both slot
+// bounds are inclusive.
+type EventFilter struct {
+	EventTypes []models.EventType
+	From       time.Time
+	To         time.Time
+	FromSlot   uint64
+	ToSlot     uint64
+	// Account matches events that reference the account in any role.
+	Account *solana.PublicKey
+	// Network matches the events indexed from one network of NETWORKS.
+	Network string
+}
+
+// EventStreamer is implemented by repositories that can iterate over large
+// result sets without loading them into memory. Events are passed to fn in
+// block time order; iteration stops at the first error fn returns.
+type EventStreamer interface {
+	StreamEvents(ctx context.Context, filter EventFilter, fn func(event ExportedEvent) error) error
+}
+
+// EventReplayer is implemented by repositories that can read the stored
+// events back to publish them again. Events are decoded as by DecodeEvent
+// and passed to fn in slot and sequence order; iteration stops at the
+// first error fn returns.
+type EventReplayer interface {
+	ReplayEvents(ctx context.Context, filter EventFilter, fn func(event interface{}) error) error
+}
+
+// EventDeleter is implemented by repositories that can delete the events
+// matching a filter, e.g. before re-indexing a slot range.
+type EventDeleter interface {
+	DeleteEvents(ctx context.Context, filter EventFilter) (int64, error)
+}
+
+// accountFields are the document fields of all event types that hold an
+// account address.
+var accountFields = []string{
+	"mint", "recipient", "from", "to", "owner", "user", "authority",
+	"admin", "nft_mint", "collection", "counter", "payer", "fee_collector",
+	"fee_destination", "seller", "buyer",
+}
+
+func (f EventFilter) mongoFilter() bson.M {
+	filter := bson.M{}
+	switch len(f.EventTypes) {
+	case 0:
+	case 1:
+		filter["event_type"] = f.EventTypes[0]
+	default:
+		filter["event_type"] = bson.M{"$in": f.EventTypes}
+	}
+
+	blockTime := bson.M{}
+	if !f.From.IsZero() {
+		blockTime["$gte"] = f.From
+	}
+	if !f.To.IsZero() {
+		blockTime["$lt"] = f.To
+	}
+	if len(blockTime) > 0 {
+		filter["block_time"] = blockTime
+	}
+
+	slot := bson.M{}
+	if f.FromSlot > 0 {
+		slot["$gte"] = f.FromSlot
+	}
+	if f.ToSlot > 0 {
+		slot["$lte"] = f.ToSlot
+	}
+	if len(slot) > 0 {
+		filter["slot"] = slot
+	}
+	if f.Network != "" {
+		filter["network"] = f.Network
+	}
+
+	if f.Account != nil {
+		or := make(bson.A, len(accountFields))
+		for i, field := range accountFields {
+			or[i] = bson.M{field: *f.Account}
+		}
+		filter["$or"] = or
+	}
+	return filter
+}
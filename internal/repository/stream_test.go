@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestEventFilter_MongoFilter(t *testing.T) {
+	account := solana.PublicKey{1}
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		filter   EventFilter
+		wantKeys []string
+	}{
+		{name: "empty", filter: EventFilter{}},
+		{name: "one type", filter: EventFilter{EventTypes: []models.EventType{models.EventTypeCounterReset}}, wantKeys: []string{"event_type"}},
+		{name: "time and slots", filter: EventFilter{From: from, FromSlot: 10, ToSlot: 20}, wantKeys: []string{"block_time", "slot"}},
+		{name: "account", filter: EventFilter{Account: &account}, wantKeys: []string{"$or"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.filter.mongoFilter()
+			if len(got) != len(tt.wantKeys) {
+				t.Fatalf("mongoFilter() = %v, want keys %v", got, tt.wantKeys)
+			}
+			for _, key := range tt.wantKeys {
+				if _, ok := got[key]; !ok {
+					t.Errorf("mongoFilter() = %v, missing %s", got, key)
+				}
+			}
+		})
+	}
+
+	slot := EventFilter{FromSlot: 10, ToSlot: 20}.mongoFilter()["slot"].(bson.M)
+	if slot["$gte"] != uint64(10) || slot["$lte"] != uint64(20) {
+		t.Errorf("slot filter = %v", slot)
+	}
+	multi := EventFilter{EventTypes: []models.EventType{"A", "B"}}.mongoFilter()["event_type"].(bson.M)
+	if len(multi["$in"].([]models.EventType)) != 2 {
+		t.Errorf("event_type filter = %v", multi)
+	}
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type tenantKey struct{}
+
+// WithTenant scopes the event reads made with ctx to the events of tenant.
+// Scoping is applied by the repository rather than by each caller so that no
+// query path can forget it.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant ctx is scoped to, or "" when it may
+// read every tenant's events.
+func TenantFromContext(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
+
+// tenantFilter adds the tenant of ctx, if any, to an event filter.
+func tenantFilter(ctx context.Context, filter bson.M) bson.M {
+	tenant := TenantFromContext(ctx)
+	if tenant == "" {
+		return filter
+	}
+	scoped := make(bson.M, len(filter)+1)
+	for k, v := range filter {
+		scoped[k] = v
+	}
+	scoped["tenant"] = tenant
+	return scoped
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestTenantFilter(t *testing.T) {
+	filter := bson.M{"event_type": "CounterIncrementedEvent"}
+
+	if got := tenantFilter(context.Background(), filter); !reflect.DeepEqual(got, filter) {
+		t.Errorf("tenantFilter() without tenant = %v, want %v", got, filter)
+	}
+
+	got := tenantFilter(WithTenant(context.Background(), "payments"), filter)
+	want := bson.M{"event_type": "CounterIncrementedEvent", "tenant": "payments"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tenantFilter() = %v, want %v", got, want)
+	}
+	if _, ok := filter["tenant"]; ok {
+		t.Errorf("tenantFilter() modified its argument")
+	}
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TokenAccountStore is implemented by repositories that keep the associated
+// token accounts of the wallets seen in token events.
+type TokenAccountStore interface {
+	// SaveTokenAccounts stores mappings that are not stored yet.
+	SaveTokenAccounts(ctx context.Context, accounts []models.TokenAccount) error
+	// GetTokenAccount returns nil if address is not a known token account.
+	GetTokenAccount(ctx context.Context, address string) (*models.TokenAccount, error)
+	// GetTokenAccountsByOwner returns the token accounts of a wallet by mint.
+	GetTokenAccountsByOwner(ctx context.Context, owner string) ([]models.TokenAccount, error)
+}
+
+func (r *MongoRepository) SaveTokenAccounts(ctx context.Context, accounts []models.TokenAccount) error {
+	if len(accounts) == 0 {
+		return nil
+	}
+	writes := make([]mongo.WriteModel, len(accounts))
+	for i, a := range accounts {
+		writes[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": a.Address}).
+			SetUpdate(bson.M{"$setOnInsert": bson.M{
+				"owner":         a.Owner,
+				"mint":          a.Mint,
+				"token_program": a.TokenProgram,
+				"slot":          a.Slot,
+			}}).
+			SetUpsert(true)
+	}
+	if _, err := r.tokenAccounts.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+		return fmt.Errorf("save token accounts: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoRepository) GetTokenAccount(ctx context.Context, address string) (*models.TokenAccount, error) {
+	var account models.TokenAccount
+	err := r.tokenAccounts.FindOne(ctx, bson.M{"_id": address}).Decode(&account)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find token account: %w", err)
+	}
+	return &account, nil
+}
+
+func (r *MongoRepository) GetTokenAccountsByOwner(ctx context.Context, owner string) ([]models.TokenAccount, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "mint", Value: 1}, {Key: "token_program", Value: 1}})
+	cursor, err := r.tokenAccounts.Find(ctx, bson.M{"owner": owner}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find token accounts: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var accounts []models.TokenAccount
+	if err := cursor.All(ctx, &accounts); err != nil {
+		return nil, fmt.Errorf("decode token accounts: %w", err)
+	}
+	return accounts, nil
+}
+
+func (r *MongoRepository) createTokenAccountIndexes(ctx context.Context) error {
+	_, err := r.tokenAccounts.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "owner", Value: 1}, {Key: "mint", Value: 1}},
+	})
+	if err != nil {
+		return fmt.Errorf("create token account indexes: %w", err)
+	}
+	return nil
+}
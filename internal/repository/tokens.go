@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TokenHolderStore is implemented by repositories that maintain token
+// supplies and holder balances as token events are indexed.
+type TokenHolderStore interface {
+	// RecordTokenMovement applies a movement to the balances of its parties
+	// and the supply of its mint. Each movement is applied once however often
+	// it is recorded.
+	RecordTokenMovement(ctx context.Context, m models.TokenMovement) error
+	// GetTokenSupply returns nil if no event of the mint was indexed.
+	GetTokenSupply(ctx context.Context, mint string) (*models.TokenSupply, error)
+	// GetTokenHolders returns the holders with a positive balance, largest
+	// balance first.
+	GetTokenHolders(ctx context.Context, mint string, opts HolderPageOptions) (*HolderPage, error)
+}
+
+// HolderCursor is the position of a holder in balance order. The owner
+// breaks ties between equal balances.
+type HolderCursor struct {
+	Balance uint64
+	Owner   string
+}
+
+// String encodes the cursor as an opaque URL safe token.
+func (c HolderCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(c.Balance, 10) + ":" + c.Owner))
+}
+
+// ParseHolderCursor decodes a token produced by HolderCursor.String.
+func ParseHolderCursor(s string) (*HolderCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	balance, owner, ok := strings.Cut(string(raw), ":")
+	if !ok || owner == "" {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	n, err := strconv.ParseUint(balance, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &HolderCursor{Balance: n, Owner: owner}, nil
+}
+
+type HolderPageOptions struct {
+	Limit int
+	// After continues from the last holder of a previous page.
+	After *HolderCursor
+}
+
+// HolderPage is one page of holders. Next is nil on the last page.
+type HolderPage struct {
+	Holders []models.TokenHolder
+	Next    *HolderCursor
+}
+
+func (r *MongoRepository) RecordTokenMovement(ctx context.Context, m models.TokenMovement) error {
+	// As with sales, the movement document makes recording idempotent and a
+	// failure part way leaves the movement partially applied.
+	res, err := r.tokenMovements.UpdateOne(ctx, bson.M{"_id": m.ID}, bson.M{
+		"$setOnInsert": bson.M{
+			"mint":   m.Mint,
+			"from":   m.From,
+			"to":     m.To,
+			"amount": m.Amount,
+			"slot":   m.Slot,
+		},
+	}, options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("upsert token movement: %w", err)
+	}
+	if res.UpsertedCount == 0 {
+		return nil
+	}
+
+	if m.From != "" {
+		if err := r.debitTokenHolder(ctx, m); err != nil {
+			return err
+		}
+	}
+	if m.To != "" {
+		filter := bson.M{"_id": m.Mint + ":" + m.To}
+		update := bson.M{
+			"$setOnInsert": bson.M{"mint": m.Mint, "owner": m.To},
+			"$inc":         bson.M{"balance": int64(m.Amount)},
+			"$max":         bson.M{"slot": m.Slot},
+		}
+		if _, err := r.tokenHolders.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true)); err != nil {
+			return fmt.Errorf("credit token holder: %w", err)
+		}
+	}
+
+	inc := bson.M{}
+	if m.From == "" {
+		inc["minted"] = int64(m.Amount)
+	}
+	if m.To == "" {
+		inc["burned"] = int64(m.Amount)
+	}
+	update := bson.M{"$max": bson.M{"slot": m.Slot}}
+	if len(inc) > 0 {
+		update["$inc"] = inc
+	}
+	if _, err := r.tokenSupplies.UpdateOne(ctx, bson.M{"_id": m.Mint}, update, options.Update().SetUpsert(true)); err != nil {
+		return fmt.Errorf("update token supply: %w", err)
+	}
+	return nil
+}
+
+// debitTokenHolder lowers the balance of m.From. A balance lower than the
+// amount means earlier movements were never indexed, e.g. because indexing
+// started after the mint; the balance is then clamped to zero.
+func (r *MongoRepository) debitTokenHolder(ctx context.Context, m models.TokenMovement) error {
+	id := m.Mint + ":" + m.From
+	filter := bson.M{"_id": id, "balance": bson.M{"$gte": int64(m.Amount)}}
+	update := bson.M{
+		"$inc": bson.M{"balance": -int64(m.Amount)},
+		"$max": bson.M{"slot": m.Slot},
+	}
+	res, err := r.tokenHolders.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("debit token holder: %w", err)
+	}
+	if res.MatchedCount > 0 {
+		return nil
+	}
+
+	log.Printf("warning: balance of %s in mint %s is below %d, history before slot %d is incomplete", m.From, m.Mint, m.Amount, m.Slot)
+	update = bson.M{
+		"$setOnInsert": bson.M{"mint": m.Mint, "owner": m.From},
+		"$set":         bson.M{"balance": int64(0)},
+		"$max":         bson.M{"slot": m.Slot},
+	}
+	if _, err := r.tokenHolders.UpdateOne(ctx, bson.M{"_id": id}, update, options.Update().SetUpsert(true)); err != nil {
+		return fmt.Errorf("debit token holder: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoRepository) GetTokenSupply(ctx context.Context, mint string) (*models.TokenSupply, error) {
+	var supply models.TokenSupply
+	err := r.tokenSupplies.FindOne(ctx, bson.M{"_id": mint}).Decode(&supply)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find token supply: %w", err)
+	}
+
+	if supply.Minted > supply.Burned {
+		supply.Supply = supply.Minted - supply.Burned
+	}
+	supply.Holders, err = r.tokenHolders.CountDocuments(ctx, bson.M{"mint": mint, "balance": bson.M{"$gt": 0}})
+	if err != nil {
+		return nil, fmt.Errorf("count token holders: %w", err)
+	}
+	return &supply, nil
+}
+
+func (r *MongoRepository) GetTokenHolders(ctx context.Context, mint string, opts HolderPageOptions) (*HolderPage, error) {
+	sort := bson.D{{Key: "balance", Value: -1}, {Key: "owner", Value: 1}}
+	findOpts := options.Find().SetSort(sort).SetLimit(int64(opts.Limit + 1))
+	cursor, err := r.tokenHolders.Find(ctx, holderPageFilter(mint, opts.After), findOpts)
+	if err != nil {
+		return nil, fmt.Errorf("find token holders: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var holders []models.TokenHolder
+	if err := cursor.All(ctx, &holders); err != nil {
+		return nil, fmt.Errorf("decode token holders: %w", err)
+	}
+
+	page := &HolderPage{Holders: holders}
+	if len(holders) > opts.Limit {
+		page.Holders = holders[:opts.Limit]
+		last := page.Holders[opts.Limit-1]
+		page.Next = &HolderCursor{Balance: last.Balance, Owner: last.Owner}
+	}
+	return page, nil
+}
+
+// holderPageFilter selects the holders of mint with a positive balance that
+// sort after the cursor.
+func holderPageFilter(mint string, after *HolderCursor) bson.M {
+	filter := bson.M{"mint": mint, "balance": bson.M{"$gt": 0}}
+	if after == nil {
+		return filter
+	}
+	filter["$or"] = bson.A{
+		bson.M{"balance": bson.M{"$lt": int64(after.Balance)}},
+		bson.M{"balance": int64(after.Balance), "owner": bson.M{"$gt": after.Owner}},
+	}
+	return filter
+}
+
+func (r *MongoRepository) createTokenHolderIndexes(ctx context.Context) error {
+	_, err := r.tokenHolders.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "mint", Value: 1}, {Key: "balance", Value: -1}, {Key: "owner", Value: 1}},
+	})
+	if err != nil {
+		return fmt.Errorf("create token holder indexes: %w", err)
+	}
+	return nil
+}
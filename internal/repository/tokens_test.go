@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestHolderCursor_RoundTrip(t *testing.T) {
+	want := HolderCursor{Balance: 1500, Owner: "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM"}
+	got, err := ParseHolderCursor(want.String())
+	if err != nil {
+		t.Fatalf("ParseHolderCursor() error = %v", err)
+	}
+	if *got != want {
+		t.Errorf("ParseHolderCursor() = %+v, want %+v", *got, want)
+	}
+
+	for _, bad := range []string{"", "!!", HolderCursor{Balance: 1}.String()} {
+		if _, err := ParseHolderCursor(bad); err == nil {
+			t.Errorf("ParseHolderCursor(%q) error = nil, want error", bad)
+		}
+	}
+}
+
+func TestHolderPageFilter(t *testing.T) {
+	got := holderPageFilter("mint", &HolderCursor{Balance: 50, Owner: "alice"})
+	want := bson.M{
+		"mint":    "mint",
+		"balance": bson.M{"$gt": 0},
+		"$or": bson.A{
+			bson.M{"balance": bson.M{"$lt": int64(50)}},
+			bson.M{"balance": int64(50), "owner": bson.M{"$gt": "alice"}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("holderPageFilter() = %v, want %v", got, want)
+	}
+}
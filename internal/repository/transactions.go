@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Transactor is implemented by repositories that can commit several writes
+// together, such as an event and the projections built from it.
+type Transactor interface {
+	// SupportsTransactions reports whether the database runs transactions.
+	SupportsTransactions() bool
+	// WithTransaction calls fn with a context in which the writes of the
+	// repository commit when fn succeeds and are discarded otherwise. fn
+	// may be called again after a transient error. Called within a
+	// transaction, fn joins it.
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// detectTransactions reports whether the deployment is a replica set or a
+// sharded cluster, the only ones MongoDB supports transactions on.
+func detectTransactions(ctx context.Context, client *mongo.Client) (bool, error) {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return false, fmt.Errorf("run hello: %w", err)
+	}
+	return hello.SetName != "" || hello.Msg == "isdbgrid", nil
+}
+
+func (r *MongoRepository) SupportsTransactions() bool {
+	return r.transactions
+}
+
+func (r *MongoRepository) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if mongo.SessionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+	session, err := r.client.StartSession()
+	if err != nil {
+		return fmt.Errorf("start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	})
+	return err
+}
+
+// sessionless hides the session, and with it the transaction, of a context
+// from the driver; it also hides every other value.
+type sessionless struct {
+	context.Context
+}
+
+func (sessionless) Value(key interface{}) interface{} {
+	return nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// mockRepository returns a repository on the mock deployment of mt.
+func mockRepository(mt *mtest.T) *MongoRepository {
+	return &MongoRepository{
+		client:         mt.Client,
+		database:       mt.DB,
+		nftMints:       mt.DB.Collection("nft_mints"),
+		nftSales:       mt.DB.Collection("nft_sales"),
+		nftSalesStats:  mt.DB.Collection("nft_sales_stats"),
+		tokenMovements: mt.DB.Collection("token_movements"),
+		tokenHolders:   mt.DB.Collection("token_holders"),
+		tokenSupplies:  mt.DB.Collection("token_supplies"),
+		transactions:   true,
+	}
+}
+
+func startedCommands(mt *mtest.T) []string {
+	var names []string
+	for _, e := range mt.GetAllStartedEvents() {
+		names = append(names, e.CommandName)
+	}
+	return names
+}
+
+// A replayed projection must not fail a write: any failed write aborts the
+// transaction it runs in, and with it the event being reindexed.
+func TestMongoRepository_ReplayInTransaction(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+	ctx := context.Background()
+	// matched is the reply to an upsert that found its document.
+	matched := mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 0})
+	upserted := mtest.CreateSuccessResponse(
+		bson.E{Key: "n", Value: 1},
+		bson.E{Key: "upserted", Value: bson.A{bson.D{{Key: "index", Value: 0}, {Key: "_id", Value: "x"}}}},
+	)
+	committed := mtest.CreateSuccessResponse()
+
+	movement := models.TokenMovement{ID: "sig:0", Mint: "mint", To: "owner", Amount: 5, Slot: 10}
+
+	mt.Run("token movement first recorded", func(mt *mtest.T) {
+		repo := mockRepository(mt)
+		mt.AddMockResponses(upserted, upserted, upserted, committed)
+
+		err := repo.WithTransaction(ctx, func(ctx context.Context) error {
+			return repo.RecordTokenMovement(ctx, movement)
+		})
+		if err != nil {
+			mt.Fatalf("WithTransaction() error = %v", err)
+		}
+		want := []string{"update", "update", "update", "commitTransaction"}
+		if got := startedCommands(mt); !reflect.DeepEqual(got, want) {
+			mt.Errorf("commands = %v, want %v", got, want)
+		}
+	})
+
+	mt.Run("token movement replayed", func(mt *mtest.T) {
+		repo := mockRepository(mt)
+		mt.AddMockResponses(matched, committed)
+
+		err := repo.WithTransaction(ctx, func(ctx context.Context) error {
+			return repo.RecordTokenMovement(ctx, movement)
+		})
+		if err != nil {
+			mt.Fatalf("WithTransaction() error = %v", err)
+		}
+		want := []string{"update", "commitTransaction"}
+		if got := startedCommands(mt); !reflect.DeepEqual(got, want) {
+			mt.Errorf("commands = %v, want %v", got, want)
+		}
+		update := mt.GetAllStartedEvents()[0].Command.Lookup("updates", "0")
+		if upsert, _ := update.Document().Lookup("upsert").BooleanOK(); !upsert {
+			mt.Errorf("movement update = %v, want an upsert", update)
+		}
+	})
+
+	mt.Run("nft sale replayed", func(mt *mtest.T) {
+		repo := mockRepository(mt)
+		collection := mtest.CreateCursorResponse(0, "db.nft_mints", mtest.FirstBatch,
+			bson.D{{Key: "_id", Value: "mint"}, {Key: "collection", Value: "apes"}})
+		mt.AddMockResponses(collection, matched, committed)
+
+		sale := &models.NftSoldEvent{
+			BaseEvent: models.BaseEvent{Signature: "sig", BlockTime: time.Unix(1700000000, 0).UTC()},
+			NftMint:   solana.SystemProgramID,
+			Price:     1000,
+		}
+		err := repo.WithTransaction(ctx, func(ctx context.Context) error {
+			return repo.RecordNftSale(ctx, sale)
+		})
+		if err != nil {
+			mt.Fatalf("WithTransaction() error = %v", err)
+		}
+		want := []string{"find", "update", "commitTransaction"}
+		if got := startedCommands(mt); !reflect.DeepEqual(got, want) {
+			mt.Errorf("commands = %v, want %v", got, want)
+		}
+	})
+}
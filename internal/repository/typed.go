@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// DecodeEvent decodes a stored event document into the model of its
+// event_type, or a *models.LogEvent for the events of log grammars. Other
+// documents decode into a bson.M, so every event marshals to a JSON object.
+func DecodeEvent(doc bson.Raw) (interface{}, error) {
+	var model interface{}
+	eventType, _ := doc.Lookup("event_type").StringValueOK()
+	if m, ok := models.NewEventModel(models.EventType(eventType)); ok {
+		model = m
+	} else if _, ok := doc.Lookup("fields").DocumentOK(); ok {
+		model = &models.LogEvent{}
+	} else {
+		var event bson.M
+		if err := bson.Unmarshal(doc, &event); err != nil {
+			return nil, fmt.Errorf("decode event: %w", err)
+		}
+		return event, nil
+	}
+	if err := bson.Unmarshal(doc, model); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", eventType, err)
+	}
+	if err := decompressRawData(model); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// TypedPage is one page of events of a single model. Next is nil on the
+// last page.
+type TypedPage[T models.Event] struct {
+	Events []T
+	Next   *Cursor
+}
+
+// GetTypedEvents returns one page of the events of eventType as their
+// model, e.g. GetTypedEvents[*models.TokensMintedEvent]. It fails when an
+// event is not a T.
+func GetTypedEvents[T models.Event](ctx context.Context, repo Repository, eventType models.EventType, page PageOptions) (*TypedPage[T], error) {
+	result, err := repo.GetEventsByType(ctx, eventType, page)
+	if err != nil {
+		return nil, err
+	}
+	typed := &TypedPage[T]{Events: make([]T, 0, len(result.Events)), Next: result.Next}
+	for _, event := range result.Events {
+		e, ok := event.(T)
+		if !ok {
+			var want T
+			return nil, fmt.Errorf("%s event is a %T, not a %T", eventType, event, want)
+		}
+		typed.Events = append(typed.Events, e)
+	}
+	return typed, nil
+}
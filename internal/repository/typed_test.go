@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestDecodeEvent(t *testing.T) {
+	minted := &models.TokensMintedEvent{
+		BaseEvent: models.BaseEvent{
+			EventType: models.EventTypeTokensMinted,
+			Signature: "sig",
+			Slot:      7,
+			BlockTime: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		Mint:      solana.PublicKey{1},
+		Recipient: solana.PublicKey{2},
+		Amount:    500,
+	}
+	logEvent := &models.LogEvent{
+		BaseEvent: models.BaseEvent{EventType: "SwapEvent", Signature: "swap"},
+		Fields:    map[string]interface{}{"pool": "abc"},
+	}
+
+	tests := []struct {
+		name string
+		doc  interface{}
+		want interface{}
+	}{
+		{"known type", minted, minted},
+		{"log grammar event", logEvent, logEvent},
+		{"other document", bson.M{"event_type": "Other", "name": "x"}, bson.M{"event_type": "Other", "name": "x"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := bson.Marshal(tt.doc)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			got, err := DecodeEvent(data)
+			if err != nil {
+				t.Fatalf("DecodeEvent() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeEvent() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+type typedRepository struct {
+	Repository
+	events []interface{}
+}
+
+func (r *typedRepository) GetEventsByType(ctx context.Context, eventType models.EventType, page PageOptions) (*EventPage, error) {
+	return &EventPage{Events: r.events, Next: &Cursor{Slot: 1, Signature: "next"}}, nil
+}
+
+func TestGetTypedEvents(t *testing.T) {
+	ctx := context.Background()
+	reset := &models.CounterResetEvent{BaseEvent: models.BaseEvent{Signature: "a"}}
+
+	repo := &typedRepository{events: []interface{}{reset}}
+	got, err := GetTypedEvents[*models.CounterResetEvent](ctx, repo, models.EventTypeCounterReset, PageOptions{})
+	if err != nil {
+		t.Fatalf("GetTypedEvents() error = %v", err)
+	}
+	if len(got.Events) != 1 || got.Events[0] != reset || got.Next == nil {
+		t.Errorf("GetTypedEvents() = %+v, want the reset event and a next cursor", got)
+	}
+
+	repo.events = append(repo.events, bson.M{"event_type": "CounterResetEvent"})
+	if _, err := GetTypedEvents[*models.CounterResetEvent](ctx, repo, models.EventTypeCounterReset, PageOptions{}); err == nil {
+		t.Errorf("GetTypedEvents() error = nil, want an error for an untyped event")
+	}
+}
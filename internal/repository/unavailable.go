@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// IsUnavailable reports whether err means the database could not be
+// reached, as opposed to it rejecting the request. Writes that fail this way
+// can be retried later unchanged.
+func IsUnavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var netErr net.Error
+	return mongo.IsNetworkError(err) || mongo.IsTimeout(err) ||
+		errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr)
+}
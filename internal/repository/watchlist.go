@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WatchlistStore is implemented by repositories that can persist the
+// address watchlist.
+type WatchlistStore interface {
+	// SaveWatchedAddress creates or replaces the entry of the address.
+	SaveWatchedAddress(ctx context.Context, watched *models.WatchedAddress) error
+	GetWatchedAddresses(ctx context.Context) ([]models.WatchedAddress, error)
+	// GetWatchedAddress returns nil when the address is not watched.
+	GetWatchedAddress(ctx context.Context, address string) (*models.WatchedAddress, error)
+	DeleteWatchedAddress(ctx context.Context, address string) (bool, error)
+}
+
+func (r *MongoRepository) SaveWatchedAddress(ctx context.Context, watched *models.WatchedAddress) error {
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.watchlist.ReplaceOne(ctx, bson.M{"_id": watched.Address}, watched, opts); err != nil {
+		return fmt.Errorf("save watched address: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoRepository) GetWatchedAddresses(ctx context.Context) ([]models.WatchedAddress, error) {
+	cursor, err := r.watchlist.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("find watched addresses: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var watched []models.WatchedAddress
+	if err := cursor.All(ctx, &watched); err != nil {
+		return nil, fmt.Errorf("decode watched addresses: %w", err)
+	}
+	return watched, nil
+}
+
+func (r *MongoRepository) GetWatchedAddress(ctx context.Context, address string) (*models.WatchedAddress, error) {
+	var watched models.WatchedAddress
+	err := r.watchlist.FindOne(ctx, bson.M{"_id": address}).Decode(&watched)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find watched address: %w", err)
+	}
+	return &watched, nil
+}
+
+func (r *MongoRepository) DeleteWatchedAddress(ctx context.Context, address string) (bool, error) {
+	result, err := r.watchlist.DeleteOne(ctx, bson.M{"_id": address})
+	if err != nil {
+		return false, fmt.Errorf("delete watched address: %w", err)
+	}
+	return result.DeletedCount > 0, nil
+}
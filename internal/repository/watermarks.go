@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// watermarksID is the _id of the watermarks document, suffixed with the
+// network for the networks of NETWORKS.
+const watermarksID = "indexer"
+
+// WatermarkStore is implemented by repositories that can persist the slot
+// watermarks, so consumers reading the database directly know how far the
+// data is complete and final.
+type WatermarkStore interface {
+	// GetWatermarks returns nil when none were saved yet for network,
+	// which is empty when a single network is indexed.
+	GetWatermarks(ctx context.Context, network string) (*models.Watermarks, error)
+	SaveWatermarks(ctx context.Context, network string, watermarks *models.Watermarks) error
+}
+
+func watermarksDocID(network string) string {
+	if network == "" {
+		return watermarksID
+	}
+	return watermarksID + ":" + network
+}
+
+func (r *MongoRepository) GetWatermarks(ctx context.Context, network string) (*models.Watermarks, error) {
+	var watermarks models.Watermarks
+	err := r.watermarks.FindOne(ctx, bson.M{"_id": watermarksDocID(network)}).Decode(&watermarks)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find watermarks: %w", err)
+	}
+	return &watermarks, nil
+}
+
+func (r *MongoRepository) SaveWatermarks(ctx context.Context, network string, watermarks *models.Watermarks) error {
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.watermarks.ReplaceOne(ctx, bson.M{"_id": watermarksDocID(network)}, watermarks, opts); err != nil {
+		return fmt.Errorf("save watermarks: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Package rollup keeps the event rollup tables, the events stored per
+// minute and per hour of block time by type and program, for dashboards
+// that chart indexing activity without querying the events.
+package rollup
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+type key struct {
+	bucket    time.Time
+	eventType models.EventType
+	programID string
+}
+
+// Tracker counts the stored events in memory and adds the counts to the
+// rollup tables on Flush, so that storing an event costs no extra write.
+type Tracker struct {
+	store repository.RollupStore
+
+	mu      sync.Mutex
+	pending map[models.RollupResolution]map[key]int64
+}
+
+func NewTracker(store repository.RollupStore) *Tracker {
+	t := &Tracker{store: store, pending: make(map[models.RollupResolution]map[key]int64)}
+	for _, resolution := range models.RollupResolutions {
+		t.pending[resolution] = make(map[key]int64)
+	}
+	return t
+}
+
+// Record counts one stored event.
+func (t *Tracker) Record(base models.BaseEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for resolution, pending := range t.pending {
+		k := key{
+			bucket:    base.BlockTime.UTC().Truncate(resolution.Duration()),
+			eventType: base.EventType,
+			programID: base.ProgramID.String(),
+		}
+		pending[k]++
+	}
+}
+
+// Flush adds the counts recorded since the last Flush to the store. What
+// fails to be stored is kept for the next Flush.
+func (t *Tracker) Flush(ctx context.Context) error {
+	var firstErr error
+	for _, resolution := range models.RollupResolutions {
+		t.mu.Lock()
+		pending := t.pending[resolution]
+		t.pending[resolution] = make(map[key]int64)
+		t.mu.Unlock()
+		if len(pending) == 0 {
+			continue
+		}
+
+		rollups := make([]models.EventRollup, 0, len(pending))
+		for k, count := range pending {
+			rollups = append(rollups, models.EventRollup{Bucket: k.bucket, EventType: k.eventType, ProgramID: k.programID, Count: count})
+		}
+		err := t.store.AddEventRollups(ctx, resolution, rollups)
+		if err == nil {
+			continue
+		}
+		if firstErr == nil {
+			firstErr = fmt.Errorf("%s rollups: %w", resolution, err)
+		}
+		t.mu.Lock()
+		for k, count := range pending {
+			t.pending[resolution][k] += count
+		}
+		t.mu.Unlock()
+	}
+	return firstErr
+}
+
+// Run flushes every interval until ctx is done.
+func (t *Tracker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := t.Flush(ctx); err != nil {
+			log.Printf("warning: failed to store event rollups: %v", err)
+		}
+	}
+}
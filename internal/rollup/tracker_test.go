@@ -0,0 +1,66 @@
+package rollup
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+type memStore struct {
+	rollups map[models.RollupResolution][]models.EventRollup
+	err     error
+}
+
+func (s *memStore) AddEventRollups(ctx context.Context, resolution models.RollupResolution, rollups []models.EventRollup) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.rollups[resolution] = append(s.rollups[resolution], rollups...)
+	return nil
+}
+
+func TestTracker(t *testing.T) {
+	store := &memStore{rollups: make(map[models.RollupResolution][]models.EventRollup)}
+	tracker := NewTracker(store)
+	program := solana.PublicKey{1}
+	hour := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	for _, at := range []time.Duration{10 * time.Second, 50 * time.Second, 90 * time.Second} {
+		tracker.Record(models.BaseEvent{EventType: models.EventTypeCounterReset, ProgramID: program, BlockTime: hour.Add(at)})
+	}
+
+	store.err = errors.New("unreachable")
+	if err := tracker.Flush(context.Background()); err == nil {
+		t.Fatal("Flush() error = nil, want error")
+	}
+	store.err = nil
+	if err := tracker.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	rollup := func(bucket time.Time, count int64) models.EventRollup {
+		return models.EventRollup{Bucket: bucket, EventType: models.EventTypeCounterReset, ProgramID: program.String(), Count: count}
+	}
+	want := map[models.RollupResolution][]models.EventRollup{
+		models.RollupMinute: {rollup(hour, 2), rollup(hour.Add(time.Minute), 1)},
+		models.RollupHour:   {rollup(hour, 3)},
+	}
+	for resolution, rollups := range store.rollups {
+		sort.Slice(rollups, func(i, j int) bool { return rollups[i].Bucket.Before(rollups[j].Bucket) })
+		if !reflect.DeepEqual(rollups, want[resolution]) {
+			t.Errorf("%s rollups = %+v, want %+v", resolution, rollups, want[resolution])
+		}
+	}
+
+	if err := tracker.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if n := len(store.rollups[models.RollupHour]); n != 1 {
+		t.Errorf("hour rollups after empty flush = %d, want 1", n)
+	}
+}
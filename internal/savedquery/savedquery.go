@@ -0,0 +1,210 @@
+// Package savedquery checks saved query definitions and turns them into
+// event filters by filling in their parameters.
+package savedquery
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+var (
+	placeholder = regexp.MustCompile(`^\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}$`)
+	paramName   = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,63}$`)
+)
+
+// Reserved are the names the API uses to page through results, which
+// parameters may not shadow.
+var Reserved = map[string]bool{"limit": true, "cursor": true, "order": true}
+
+// Error is a problem with one field of a definition or of a run.
+type Error struct {
+	Field   string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Field + " " + e.Message
+}
+
+// field is a filter value together with how to apply it to a filter.
+type field struct {
+	name  string
+	value string
+	apply func(filter *repository.EventFilter, value string, now time.Time) error
+}
+
+func fields(f models.SavedQueryFilter) []field {
+	var out []field
+	for i, value := range f.EventTypes {
+		out = append(out, field{fmt.Sprintf("filter.event_types[%d]", i), value, applyEventTypes})
+	}
+	return append(out,
+		field{"filter.account", f.Account, applyAccount},
+		field{"filter.from", f.From, applyTime(func(filter *repository.EventFilter) *time.Time { return &filter.From })},
+		field{"filter.to", f.To, applyTime(func(filter *repository.EventFilter) *time.Time { return &filter.To })},
+		field{"filter.window", f.Window, applyWindow},
+		field{"filter.from_slot", f.FromSlot, applySlot(func(filter *repository.EventFilter) *uint64 { return &filter.FromSlot })},
+		field{"filter.to_slot", f.ToSlot, applySlot(func(filter *repository.EventFilter) *uint64 { return &filter.ToSlot })},
+	)
+}
+
+// Validate checks a definition: parameters must be well named, declared
+// once and used, placeholders must name a parameter, and literal values
+// and defaults must parse.
+func Validate(q *models.SavedQuery) []Error {
+	var errs []Error
+
+	params := make(map[string]models.SavedQueryParam)
+	for _, p := range q.Params {
+		switch {
+		case !paramName.MatchString(p.Name):
+			errs = append(errs, Error{"params", fmt.Sprintf("name %q must be a letter or '_' followed by up to 63 letters, digits or '_'", p.Name)})
+		case Reserved[p.Name]:
+			errs = append(errs, Error{"params", fmt.Sprintf("name %q is reserved", p.Name)})
+		default:
+			if _, dup := params[p.Name]; dup {
+				errs = append(errs, Error{"params", fmt.Sprintf("%q is declared twice", p.Name)})
+			}
+		}
+		params[p.Name] = p
+	}
+
+	used := make(map[string]bool)
+	var filter repository.EventFilter
+	now := time.Now()
+	for _, f := range fields(q.Filter) {
+		value := f.value
+		if m := placeholder.FindStringSubmatch(value); m != nil {
+			p, ok := params[m[1]]
+			if !ok {
+				errs = append(errs, Error{f.name, fmt.Sprintf("uses undeclared parameter %q", m[1])})
+				continue
+			}
+			used[p.Name] = true
+			if value = p.Default; value == "" {
+				continue
+			}
+		}
+		if value == "" {
+			continue
+		}
+		if err := f.apply(&filter, value, now); err != nil {
+			errs = append(errs, Error{f.name, err.Error()})
+		}
+	}
+	for _, p := range q.Params {
+		if !used[p.Name] && paramName.MatchString(p.Name) {
+			errs = append(errs, Error{"params", fmt.Sprintf("%q is not used by the filter", p.Name)})
+		}
+	}
+
+	if q.Filter.Window != "" && q.Filter.From != "" {
+		errs = append(errs, Error{"filter.window", "cannot be combined with filter.from"})
+	}
+	switch q.Order {
+	case "", "asc", "desc":
+	default:
+		errs = append(errs, Error{"order", "must be 'asc' or 'desc'"})
+	}
+	return errs
+}
+
+// Resolve fills the parameters of q in from args, falling back to their
+// defaults, and returns the resulting filter. Window is measured back from
+// now.
+func Resolve(q *models.SavedQuery, args map[string]string, now time.Time) (repository.EventFilter, error) {
+	params := make(map[string]models.SavedQueryParam)
+	for _, p := range q.Params {
+		params[p.Name] = p
+	}
+	for name := range args {
+		if _, ok := params[name]; !ok {
+			return repository.EventFilter{}, &Error{name, "is not a parameter of this query"}
+		}
+	}
+
+	var filter repository.EventFilter
+	for _, f := range fields(q.Filter) {
+		value := f.value
+		if m := placeholder.FindStringSubmatch(value); m != nil {
+			p := params[m[1]]
+			if value = args[p.Name]; value == "" {
+				value = p.Default
+			}
+			if value == "" {
+				return repository.EventFilter{}, &Error{p.Name, "is required"}
+			}
+			if err := f.apply(&filter, value, now); err != nil {
+				return repository.EventFilter{}, &Error{p.Name, err.Error()}
+			}
+			continue
+		}
+		if value == "" {
+			continue
+		}
+		if err := f.apply(&filter, value, now); err != nil {
+			return repository.EventFilter{}, &Error{f.name, err.Error()}
+		}
+	}
+	return filter, nil
+}
+
+func applyEventTypes(filter *repository.EventFilter, value string, now time.Time) error {
+	for _, name := range strings.Split(value, ",") {
+		eventType := models.EventType(strings.TrimSpace(name))
+		if !eventType.Known() {
+			return fmt.Errorf("has unknown event type %q", name)
+		}
+		filter.EventTypes = append(filter.EventTypes, eventType)
+	}
+	return nil
+}
+
+func applyAccount(filter *repository.EventFilter, value string, now time.Time) error {
+	account, err := solana.PublicKeyFromBase58(value)
+	if err != nil {
+		return fmt.Errorf("must be a base58 public key")
+	}
+	filter.Account = &account
+	return nil
+}
+
+func applyTime(target func(*repository.EventFilter) *time.Time) func(*repository.EventFilter, string, time.Time) error {
+	return func(filter *repository.EventFilter, value string, now time.Time) error {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			if t, err = time.Parse(time.DateOnly, value); err != nil {
+				return fmt.Errorf("must be RFC 3339 or YYYY-MM-DD")
+			}
+		}
+		*target(filter) = t
+		return nil
+	}
+}
+
+func applyWindow(filter *repository.EventFilter, value string, now time.Time) error {
+	window, err := time.ParseDuration(value)
+	if err != nil || window <= 0 {
+		return fmt.Errorf("must be a positive duration, e.g. 24h")
+	}
+	filter.From = now.Add(-window)
+	return nil
+}
+
+func applySlot(target func(*repository.EventFilter) *uint64) func(*repository.EventFilter, string, time.Time) error {
+	return func(filter *repository.EventFilter, value string, now time.Time) error {
+		slot, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("must be a slot number")
+		}
+		*target(filter) = slot
+		return nil
+	}
+}
@@ -0,0 +1,144 @@
+package savedquery
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+const wallet = "9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin"
+
+func walletQuery() *models.SavedQuery {
+	return &models.SavedQuery{
+		Name: "wallet-activity",
+		Filter: models.SavedQueryFilter{
+			EventTypes: []string{"{{types}}"},
+			Account:    "{{ wallet }}",
+			Window:     "{{window}}",
+			FromSlot:   "100",
+		},
+		Params: []models.SavedQueryParam{
+			{Name: "wallet"},
+			{Name: "types", Default: "TokensMintedEvent,TokensTransferredEvent"},
+			{Name: "window", Default: "24h"},
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(q *models.SavedQuery)
+		want   []string
+	}{
+		{name: "valid", modify: func(q *models.SavedQuery) {}},
+		{
+			name:   "undeclared parameter",
+			modify: func(q *models.SavedQuery) { q.Filter.ToSlot = "{{until}}" },
+			want:   []string{"filter.to_slot"},
+		},
+		{
+			name:   "unused parameter",
+			modify: func(q *models.SavedQuery) { q.Params = append(q.Params, models.SavedQueryParam{Name: "extra"}) },
+			want:   []string{"params"},
+		},
+		{
+			name:   "reserved parameter",
+			modify: func(q *models.SavedQuery) { q.Params[0].Name = "limit"; q.Filter.Account = "{{limit}}" },
+			want:   []string{"params"},
+		},
+		{
+			name:   "bad default",
+			modify: func(q *models.SavedQuery) { q.Params[2].Default = "yesterday" },
+			want:   []string{"filter.window"},
+		},
+		{
+			name:   "bad literal",
+			modify: func(q *models.SavedQuery) { q.Filter.FromSlot = "-1" },
+			want:   []string{"filter.from_slot"},
+		},
+		{
+			name:   "window and from",
+			modify: func(q *models.SavedQuery) { q.Filter.From = "2026-01-01" },
+			want:   []string{"filter.window"},
+		},
+		{
+			name:   "order",
+			modify: func(q *models.SavedQuery) { q.Order = "newest" },
+			want:   []string{"order"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := walletQuery()
+			tt.modify(q)
+			var got []string
+			for _, e := range Validate(q) {
+				got = append(got, e.Field)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	account := solana.MustPublicKeyFromBase58(wallet)
+
+	tests := []struct {
+		name    string
+		args    map[string]string
+		want    repository.EventFilter
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			args: map[string]string{"wallet": wallet},
+			want: repository.EventFilter{
+				EventTypes: []models.EventType{models.EventTypeTokensMinted, models.EventTypeTokensTransferred},
+				Account:    &account,
+				From:       now.Add(-24 * time.Hour),
+				FromSlot:   100,
+			},
+		},
+		{
+			name: "arguments",
+			args: map[string]string{"wallet": wallet, "types": "NftSoldEvent", "window": "1h"},
+			want: repository.EventFilter{
+				EventTypes: []models.EventType{models.EventTypeNftSold},
+				Account:    &account,
+				From:       now.Add(-time.Hour),
+				FromSlot:   100,
+			},
+		},
+		{name: "missing required", args: map[string]string{}, wantErr: "wallet"},
+		{name: "unknown argument", args: map[string]string{"wallet": wallet, "owner": wallet}, wantErr: "owner"},
+		{name: "invalid argument", args: map[string]string{"wallet": "nope"}, wantErr: "wallet"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Resolve(walletQuery(), tt.args, now)
+			if tt.wantErr != "" {
+				e, ok := err.(*Error)
+				if !ok || e.Field != tt.wantErr {
+					t.Fatalf("Resolve() error = %v, want an error for %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Resolve() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package seen
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+)
+
+// bloomMagic starts a persisted filter and names its format version.
+const bloomMagic = "SEENBLM1"
+
+// BloomFilter is a fixed size bloom filter. It is not safe for concurrent
+// use.
+type BloomFilter struct {
+	bits  []uint64
+	m     uint64
+	k     uint32
+	count uint64
+}
+
+// NewBloomFilter sizes a filter for capacity keys at the false positive rate.
+func NewBloomFilter(capacity int, falsePositiveRate float64) *BloomFilter {
+	n := float64(max(capacity, 1))
+	m := uint64(math.Ceil(-n * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	m = max(m, 64)
+	k := uint32(math.Max(1, math.Round(float64(m)/n*math.Ln2)))
+	return &BloomFilter{bits: make([]uint64, (m+63)/64), m: m, k: k}
+}
+
+// locations derives the k bit positions of key by double hashing.
+func (f *BloomFilter) locations(key []byte, fn func(bit uint64) bool) bool {
+	h := fnv.New128a()
+	h.Write(key)
+	sum := h.Sum(nil)
+	h1 := binary.LittleEndian.Uint64(sum[:8])
+	h2 := binary.LittleEndian.Uint64(sum[8:]) | 1
+	for i := uint64(0); i < uint64(f.k); i++ {
+		if !fn((h1 + i*h2) % f.m) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *BloomFilter) Add(key []byte) {
+	f.locations(key, func(bit uint64) bool {
+		f.bits[bit/64] |= 1 << (bit % 64)
+		return true
+	})
+	f.count++
+}
+
+// MayContain reports false if key was certainly never added.
+func (f *BloomFilter) MayContain(key []byte) bool {
+	return f.locations(key, func(bit uint64) bool {
+		return f.bits[bit/64]&(1<<(bit%64)) != 0
+	})
+}
+
+// Count is the number of keys added, including repeats.
+func (f *BloomFilter) Count() uint64 {
+	return f.count
+}
+
+// EstimatedFalsePositiveRate is the expected rate at the current count.
+func (f *BloomFilter) EstimatedFalsePositiveRate() float64 {
+	return math.Pow(1-math.Exp(-float64(f.k)*float64(f.count)/float64(f.m)), float64(f.k))
+}
+
+func (f *BloomFilter) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	header := make([]byte, 0, len(bloomMagic)+20)
+	header = append(header, bloomMagic...)
+	header = binary.LittleEndian.AppendUint32(header, f.k)
+	header = binary.LittleEndian.AppendUint64(header, f.m)
+	header = binary.LittleEndian.AppendUint64(header, f.count)
+	if _, err := bw.Write(header); err != nil {
+		return 0, err
+	}
+	if err := binary.Write(bw, binary.LittleEndian, f.bits); err != nil {
+		return 0, err
+	}
+	return int64(len(header) + 8*len(f.bits)), bw.Flush()
+}
+
+var errBloomMismatch = errors.New("persisted bloom filter was sized differently")
+
+// readBloomInto replaces f with the filter persisted in r, which must have
+// the same size and number of hashes.
+func readBloomInto(f *BloomFilter, r io.Reader) error {
+	br := bufio.NewReader(r)
+	header := make([]byte, len(bloomMagic)+20)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return fmt.Errorf("read bloom filter header: %w", err)
+	}
+	if string(header[:len(bloomMagic)]) != bloomMagic {
+		return fmt.Errorf("not a bloom filter file")
+	}
+	rest := header[len(bloomMagic):]
+	k := binary.LittleEndian.Uint32(rest)
+	m := binary.LittleEndian.Uint64(rest[4:])
+	if k != f.k || m != f.m {
+		return errBloomMismatch
+	}
+
+	bits := make([]uint64, len(f.bits))
+	if err := binary.Read(br, binary.LittleEndian, bits); err != nil {
+		return fmt.Errorf("read bloom filter bits: %w", err)
+	}
+	f.bits = bits
+	f.count = binary.LittleEndian.Uint64(rest[12:])
+	return nil
+}
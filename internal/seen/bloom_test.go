@@ -0,0 +1,60 @@
+package seen
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func key(i int) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(i))
+}
+
+func TestBloomFilter(t *testing.T) {
+	f := NewBloomFilter(10000, 0.01)
+	for i := 0; i < 10000; i++ {
+		f.Add(key(i))
+	}
+	for i := 0; i < 10000; i++ {
+		if !f.MayContain(key(i)) {
+			t.Fatalf("MayContain(%d) = false after Add", i)
+		}
+	}
+
+	positives := 0
+	for i := 10000; i < 20000; i++ {
+		if f.MayContain(key(i)) {
+			positives++
+		}
+	}
+	if rate := float64(positives) / 10000; rate > 0.03 {
+		t.Errorf("false positive rate = %v, want about 0.01", rate)
+	}
+	if got := f.EstimatedFalsePositiveRate(); got < 0.005 || got > 0.02 {
+		t.Errorf("EstimatedFalsePositiveRate() = %v, want about 0.01", got)
+	}
+}
+
+func TestBloomFilter_Persistence(t *testing.T) {
+	f := NewBloomFilter(100, 0.01)
+	f.Add(key(1))
+
+	var buf bytes.Buffer
+	if _, err := f.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+
+	loaded := NewBloomFilter(100, 0.01)
+	if err := readBloomInto(loaded, bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatalf("readBloomInto() error = %v", err)
+	}
+	if !loaded.MayContain(key(1)) || loaded.Count() != 1 {
+		t.Errorf("loaded filter lost its contents: count %d", loaded.Count())
+	}
+
+	other := NewBloomFilter(1000, 0.01)
+	if err := readBloomInto(other, bytes.NewReader(buf.Bytes())); !errors.Is(err, errBloomMismatch) {
+		t.Errorf("readBloomInto() with another size error = %v, want %v", err, errBloomMismatch)
+	}
+}
@@ -0,0 +1,288 @@
+// Package seen remembers which transaction signatures were processed, so the
+// live and backfill paths skip duplicates without a database query for
+// every signature.
+package seen
+
+import (
+	"bufio"
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Confirmer reports whether a signature really was processed before. It is
+// asked only when the bloom filter says it may have been.
+type Confirmer func(ctx context.Context, signature solana.Signature) (bool, error)
+
+type Options struct {
+	// Size is the number of recent signatures remembered exactly.
+	Size int
+	// BloomCapacity and BloomFalsePositiveRate size the bloom filter that
+	// remembers older signatures.
+	BloomCapacity          int
+	BloomFalsePositiveRate float64
+	// Path persists the bloom filter across restarts, and Path + ".recent"
+	// the recent signatures; empty keeps both in memory only.
+	Path         string
+	SaveInterval time.Duration
+}
+
+// Stats are the cumulative counts of a cache since start.
+type Stats struct {
+	Checks uint64 `json:"checks"`
+	// RecentHits were found in the exact recent set.
+	RecentHits uint64 `json:"recent_hits"`
+	// BloomPositives were possibly seen and checked with the Confirmer;
+	// FalsePositives of them were not confirmed. Transactions that stored
+	// no events are never confirmed and count as false positives.
+	BloomPositives             uint64  `json:"bloom_positives"`
+	FalsePositives             uint64  `json:"false_positives"`
+	BloomCount                 uint64  `json:"bloom_count"`
+	EstimatedFalsePositiveRate float64 `json:"estimated_false_positive_rate"`
+}
+
+// Cache combines an LRU of recent signatures with a bloom filter of all
+// signatures added. A signature is only reported as seen when it is in the
+// LRU or the Confirmer confirms a bloom filter hit, so false positives cost
+// a lookup but never skip a transaction.
+type Cache struct {
+	opts    Options
+	confirm Confirmer
+
+	mu     sync.Mutex
+	recent map[solana.Signature]*list.Element
+	order  *list.List
+	bloom  *BloomFilter
+	stats  Stats
+	warned bool
+}
+
+// recentMagic starts a persisted recent set and names its format version.
+const recentMagic = "SEENRCT1"
+
+// New creates a cache, loading the bloom filter and the recent signatures
+// from opts.Path if they exist.
+func New(opts Options, confirm Confirmer) (*Cache, error) {
+	c := &Cache{
+		opts:    opts,
+		confirm: confirm,
+		recent:  make(map[solana.Signature]*list.Element, opts.Size),
+		order:   list.New(),
+		bloom:   NewBloomFilter(opts.BloomCapacity, opts.BloomFalsePositiveRate),
+	}
+	if opts.Path == "" {
+		return c, nil
+	}
+
+	if err := c.loadBloom(); err != nil {
+		return nil, err
+	}
+	if err := c.loadRecent(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Cache) loadBloom() error {
+	f, err := os.Open(c.opts.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("open seen signatures: %w", err)
+	}
+	defer f.Close()
+	err = readBloomInto(c.bloom, f)
+	if errors.Is(err, errBloomMismatch) {
+		log.Printf("warning: %s was written for a different bloom filter size, starting empty", c.opts.Path)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("load seen signatures: %w", err)
+	}
+	return nil
+}
+
+// loadRecent restores the recent set, oldest signature first, keeping the
+// newest opts.Size of them.
+func (c *Cache) loadRecent() error {
+	f, err := os.Open(c.recentPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("open recent signatures: %w", err)
+	}
+	defer f.Close()
+	br := bufio.NewReader(f)
+	magic := make([]byte, len(recentMagic))
+	if _, err := io.ReadFull(br, magic); err != nil || string(magic) != recentMagic {
+		return fmt.Errorf("load recent signatures: %s is not a recent signature file", c.recentPath())
+	}
+	for {
+		var signature solana.Signature
+		_, err := io.ReadFull(br, signature[:])
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("load recent signatures: %w", err)
+		}
+		c.addRecent(signature)
+	}
+}
+
+func (c *Cache) recentPath() string {
+	return c.opts.Path + ".recent"
+}
+
+// Seen reports whether signature was processed before.
+func (c *Cache) Seen(ctx context.Context, signature solana.Signature) (bool, error) {
+	c.mu.Lock()
+	c.stats.Checks++
+	if elem, ok := c.recent[signature]; ok {
+		c.order.MoveToFront(elem)
+		c.stats.RecentHits++
+		c.mu.Unlock()
+		return true, nil
+	}
+	maybe := c.bloom.MayContain(signature[:])
+	if maybe {
+		c.stats.BloomPositives++
+	}
+	c.mu.Unlock()
+	if !maybe {
+		return false, nil
+	}
+
+	confirmed, err := c.confirm(ctx, signature)
+	if err != nil {
+		return false, fmt.Errorf("confirm seen signature: %w", err)
+	}
+	if !confirmed {
+		c.mu.Lock()
+		c.stats.FalsePositives++
+		c.mu.Unlock()
+	}
+	return confirmed, nil
+}
+
+// Add records signature as processed.
+func (c *Cache) Add(signature solana.Signature) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.addRecent(signature) {
+		return
+	}
+	c.bloom.Add(signature[:])
+	if !c.warned && c.bloom.Count() > uint64(c.opts.BloomCapacity) {
+		c.warned = true
+		log.Printf("warning: seen signature bloom filter is over its capacity of %d, raise SEEN_BLOOM_CAPACITY", c.opts.BloomCapacity)
+	}
+}
+
+// addRecent moves signature to the front of the recent set, evicting the
+// oldest one when it is full, and reports whether it was not in it yet.
+func (c *Cache) addRecent(signature solana.Signature) bool {
+	if elem, ok := c.recent[signature]; ok {
+		c.order.MoveToFront(elem)
+		return false
+	}
+	if c.opts.Size > 0 {
+		c.recent[signature] = c.order.PushFront(signature)
+		if c.order.Len() > c.opts.Size {
+			oldest := c.order.Remove(c.order.Back()).(solana.Signature)
+			delete(c.recent, oldest)
+		}
+	}
+	return true
+}
+
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.BloomCount = c.bloom.Count()
+	stats.EstimatedFalsePositiveRate = c.bloom.EstimatedFalsePositiveRate()
+	return stats
+}
+
+// Save writes the bloom filter to opts.Path and the recent signatures next
+// to it, replacing each previous file only once the new one is complete.
+func (c *Cache) Save() error {
+	if c.opts.Path == "" {
+		return nil
+	}
+	c.mu.Lock()
+	bloom := &BloomFilter{bits: slices.Clone(c.bloom.bits), m: c.bloom.m, k: c.bloom.k, count: c.bloom.count}
+	recent := make([]solana.Signature, 0, c.order.Len())
+	for elem := c.order.Back(); elem != nil; elem = elem.Prev() {
+		recent = append(recent, elem.Value.(solana.Signature))
+	}
+	c.mu.Unlock()
+
+	if err := writeFile(c.opts.Path, func(w io.Writer) error {
+		_, err := bloom.WriteTo(w)
+		return err
+	}); err != nil {
+		return fmt.Errorf("save seen signatures: %w", err)
+	}
+	if err := writeFile(c.recentPath(), func(w io.Writer) error {
+		bw := bufio.NewWriter(w)
+		bw.WriteString(recentMagic)
+		for _, signature := range recent {
+			bw.Write(signature[:])
+		}
+		return bw.Flush()
+	}); err != nil {
+		return fmt.Errorf("save recent signatures: %w", err)
+	}
+	return nil
+}
+
+// writeFile writes path through a temporary file renamed over it.
+func writeFile(path string, write func(io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	err = write(tmp)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Run saves the cache every SaveInterval until ctx is done.
+func (c *Cache) Run(ctx context.Context) {
+	if c.opts.Path == "" || c.opts.SaveInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(c.opts.SaveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Save(); err != nil {
+				log.Printf("warning: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package seen
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+func signature(b byte) solana.Signature {
+	var sig solana.Signature
+	sig[0] = b
+	return sig
+}
+
+func TestCache_Seen(t *testing.T) {
+	stored := map[solana.Signature]bool{signature(1): true}
+	confirm := func(ctx context.Context, sig solana.Signature) (bool, error) {
+		return stored[sig], nil
+	}
+	c, err := New(Options{Size: 1, BloomCapacity: 100, BloomFalsePositiveRate: 0.01}, confirm)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	ctx := context.Background()
+
+	c.Add(signature(1))
+	c.Add(signature(2)) // evicts 1 from the recent set
+
+	tests := []struct {
+		sig  solana.Signature
+		want bool
+	}{
+		{signature(2), true}, // recent
+		{signature(1), true}, // bloom positive, confirmed
+		{signature(3), false},
+	}
+	for _, tt := range tests {
+		got, err := c.Seen(ctx, tt.sig)
+		if err != nil {
+			t.Fatalf("Seen() error = %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("Seen(%d) = %v, want %v", tt.sig[0], got, tt.want)
+		}
+	}
+
+	// Added but never stored, e.g. a transaction without events.
+	c.Add(signature(4))
+	c.Add(signature(5))
+	if got, _ := c.Seen(ctx, signature(4)); got {
+		t.Errorf("Seen(4) = true, want false when the store does not confirm it")
+	}
+
+	stats := c.Stats()
+	if stats.Checks != 4 || stats.RecentHits != 1 || stats.BloomPositives != 2 || stats.FalsePositives != 1 {
+		t.Errorf("Stats() = %+v, want 4 checks, 1 recent hit, 2 bloom positives, 1 false positive", stats)
+	}
+	if stats.BloomCount != 4 {
+		t.Errorf("BloomCount = %d, want 4", stats.BloomCount)
+	}
+}
+
+func TestCache_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seen.bloom")
+	opts := Options{Size: 10, BloomCapacity: 100, BloomFalsePositiveRate: 0.01, Path: path}
+	always := func(ctx context.Context, sig solana.Signature) (bool, error) { return true, nil }
+
+	c, err := New(opts, always)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	c.Add(signature(1))
+	if err := c.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	reloaded, err := New(opts, always)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got, _ := reloaded.Seen(context.Background(), signature(1)); !got {
+		t.Errorf("Seen() after reload = false, want true")
+	}
+	if got, _ := reloaded.Seen(context.Background(), signature(2)); got {
+		t.Errorf("Seen() of a new signature after reload = true, want false")
+	}
+}
+
+func TestCache_PersistRecent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seen.bloom")
+	never := func(ctx context.Context, sig solana.Signature) (bool, error) { return false, nil }
+
+	c, err := New(Options{Size: 3, BloomCapacity: 100, BloomFalsePositiveRate: 0.01, Path: path}, never)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	for b := byte(1); b <= 3; b++ {
+		c.Add(signature(b))
+	}
+	if err := c.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// A smaller recent set keeps the newest signatures.
+	reloaded, err := New(Options{Size: 2, BloomCapacity: 100, BloomFalsePositiveRate: 0.01, Path: path}, never)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	tests := []struct {
+		sig  solana.Signature
+		want bool
+	}{
+		{signature(1), false}, // evicted, and the store does not confirm it
+		{signature(2), true},
+		{signature(3), true},
+	}
+	for _, tt := range tests {
+		if got, _ := reloaded.Seen(context.Background(), tt.sig); got != tt.want {
+			t.Errorf("Seen(%d) after reload = %v, want %v", tt.sig[0], got, tt.want)
+		}
+	}
+	if stats := reloaded.Stats(); stats.RecentHits != 2 {
+		t.Errorf("RecentHits = %d, want 2", stats.RecentHits)
+	}
+}
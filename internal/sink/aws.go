@@ -0,0 +1,359 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+type AWSCredentials = aws.Credentials
+
+const (
+	defaultAWSAttempts     = 5
+	defaultAWSRetryBackoff = 200 * time.Millisecond
+	defaultAWSQueueSize    = 64
+)
+
+type AWSOptions struct {
+	Region        string
+	Endpoint      string
+	Credentials   AWSCredentials
+	DefaultTarget string
+	Routes        map[models.EventType]string
+	BatchSize     int
+	FlushInterval time.Duration
+	// MaxAttempts is how often a record is sent before it is given up on;
+	// zero means defaultAWSAttempts.
+	MaxAttempts int
+	// RetryBackoff is the delay before the first retry of the failed
+	// records of a batch, doubled on every further retry; zero means
+	// defaultAWSRetryBackoff.
+	RetryBackoff time.Duration
+	// QueueSize is how many full batches wait to be delivered in the
+	// background before further ones are given up on; zero means
+	// defaultAWSQueueSize.
+	QueueSize int
+	// OnFailure is called for every record given up on; nil logs it.
+	OnFailure func(event models.BaseEvent, err error)
+}
+
+type awsRecord struct {
+	base    models.BaseEvent
+	payload []byte
+}
+
+// awsFailure is a record a batch call did not deliver. Permanent failures,
+// such as a malformed message, are not retried.
+type awsFailure struct {
+	record    awsRecord
+	err       error
+	permanent bool
+}
+
+// awsClient signs and sends AWS JSON protocol requests.
+type awsClient struct {
+	httpClient *http.Client
+	signer     *v4.Signer
+	endpoint   string
+	region     string
+	service    string
+	jsonType   string
+	creds      AWSCredentials
+	now        func() time.Time
+}
+
+func newAWSClient(opts AWSOptions, service, jsonType string) *awsClient {
+	endpoint := opts.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.%s.amazonaws.com", service, opts.Region)
+	}
+	return &awsClient{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		signer:     v4.NewSigner(),
+		endpoint:   strings.TrimRight(endpoint, "/") + "/",
+		region:     opts.Region,
+		service:    service,
+		jsonType:   jsonType,
+		creds:      opts.Credentials,
+		now:        time.Now,
+	}
+}
+
+func (c *awsClient) call(ctx context.Context, target string, in, out interface{}) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", c.jsonType)
+	req.Header.Set("X-Amz-Target", target)
+	hash := sha256.Sum256(body)
+	if err := c.signer.SignHTTP(ctx, c.creds, req, hex.EncodeToString(hash[:]), c.service, c.region, c.now()); err != nil {
+		return fmt.Errorf("sign %s: %w", target, err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("send %s: %w", target, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read %s response: %w", target, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s failed with status %d: %s", target, resp.StatusCode, respBody)
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("decode %s response: %w", target, err)
+		}
+	}
+	return nil
+}
+
+// awsDelivery sends the batches of a sink, retrying the records that
+// failed with exponential backoff and reporting those it gives up on one
+// by one, so a failed batch never fails the publishing of an event.
+type awsDelivery struct {
+	maxAttempts int
+	backoff     time.Duration
+	onFailure   func(event models.BaseEvent, err error)
+}
+
+func newAWSDelivery(opts AWSOptions, sink string) awsDelivery {
+	d := awsDelivery{maxAttempts: opts.MaxAttempts, backoff: opts.RetryBackoff, onFailure: opts.OnFailure}
+	if d.maxAttempts <= 0 {
+		d.maxAttempts = defaultAWSAttempts
+	}
+	if d.backoff <= 0 {
+		d.backoff = defaultAWSRetryBackoff
+	}
+	if d.onFailure == nil {
+		d.onFailure = func(event models.BaseEvent, err error) {
+			log.Printf("warning: %s sink dropped %s %s: %v", sink, event.EventType, event.Signature, err)
+		}
+	}
+	return d
+}
+
+// drop reports the records of a batch the delivery queue had no room for.
+func (d awsDelivery) drop(target string, records []awsRecord) {
+	for _, record := range records {
+		d.onFailure(record.base, fmt.Errorf("deliver to %s: delivery queue full", target))
+	}
+}
+
+// deliver sends records to target with send, which returns the records it
+// did not deliver, or an error when the whole call failed.
+func (d awsDelivery) deliver(ctx context.Context, target string, records []awsRecord, send func(ctx context.Context, target string, records []awsRecord) ([]awsFailure, error)) {
+	delay := d.backoff
+	for attempt := 1; ; attempt++ {
+		failed, err := send(ctx, target, records)
+		if err != nil {
+			failed = make([]awsFailure, len(records))
+			for i, record := range records {
+				failed[i] = awsFailure{record: record, err: err}
+			}
+		}
+
+		var retry []awsRecord
+		for _, f := range failed {
+			if f.permanent || attempt >= d.maxAttempts || ctx.Err() != nil {
+				d.onFailure(f.record.base, fmt.Errorf("deliver to %s after %d attempts: %w", target, attempt, f.err))
+				continue
+			}
+			retry = append(retry, f.record)
+		}
+		if len(retry) == 0 {
+			return
+		}
+		records = retry
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// batcher buffers records per destination and hands full batches to flush,
+// either when a destination reaches batchSize or on every flushInterval.
+// Full batches are flushed by the caller of add, unless the batcher has a
+// queue: then they are flushed in the background and add never waits.
+type batcher[T any] struct {
+	mu        sync.Mutex
+	pending   map[string][]T
+	batchSize int
+	flush     func(ctx context.Context, target string, records []T) error
+	stop      chan struct{}
+	done      chan struct{}
+
+	// queue holds the full batches waiting for the background loop; nil
+	// flushes them in add. onDrop is called for a batch the full queue
+	// has no room for.
+	queue  chan queuedBatch[T]
+	onDrop func(target string, records []T)
+	closed bool
+}
+
+type queuedBatch[T any] struct {
+	target  string
+	records []T
+}
+
+func newBatcher[T any](batchSize int, interval time.Duration, flush func(ctx context.Context, target string, records []T) error) *batcher[T] {
+	b := &batcher[T]{
+		pending:   make(map[string][]T),
+		batchSize: batchSize,
+		flush:     flush,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go b.loop(interval)
+	return b
+}
+
+// newQueuedBatcher returns a batcher flushing full batches in the
+// background, holding up to queueSize of them.
+func newQueuedBatcher[T any](batchSize int, interval time.Duration, queueSize int, flush func(ctx context.Context, target string, records []T) error, onDrop func(target string, records []T)) *batcher[T] {
+	b := &batcher[T]{
+		pending:   make(map[string][]T),
+		batchSize: batchSize,
+		flush:     flush,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+		queue:     make(chan queuedBatch[T], queueSize),
+		onDrop:    onDrop,
+	}
+	go b.loop(interval)
+	return b
+}
+
+func (b *batcher[T]) add(ctx context.Context, target string, record T) error {
+	b.mu.Lock()
+	b.pending[target] = append(b.pending[target], record)
+	var full []T
+	if len(b.pending[target]) >= b.batchSize {
+		full = b.pending[target]
+		delete(b.pending, target)
+	}
+	if full != nil && b.queue != nil && !b.closed {
+		select {
+		case b.queue <- queuedBatch[T]{target: target, records: full}:
+		default:
+			b.onDrop(target, full)
+		}
+		full = nil
+	}
+	b.mu.Unlock()
+
+	if full == nil {
+		return nil
+	}
+	return b.flush(ctx, target, full)
+}
+
+func (b *batcher[T]) flushAll(ctx context.Context) error {
+	b.mu.Lock()
+	pending := b.pending
+	b.pending = make(map[string][]T)
+	b.mu.Unlock()
+
+	var firstErr error
+	for target, records := range pending {
+		if err := b.flush(ctx, target, records); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (b *batcher[T]) loop(interval time.Duration) {
+	defer close(b.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stop:
+			// add queues nothing once the batcher is closed.
+			for {
+				select {
+				case q := <-b.queue:
+					b.flushQueued(q)
+				default:
+					return
+				}
+			}
+		case q := <-b.queue:
+			b.flushQueued(q)
+		case <-ticker.C:
+			if err := b.flushAll(context.Background()); err != nil {
+				log.Printf("sink flush failed: %v", err)
+			}
+		}
+	}
+}
+
+func (b *batcher[T]) flushQueued(q queuedBatch[T]) {
+	if err := b.flush(context.Background(), q.target, q.records); err != nil {
+		log.Printf("sink flush failed: %v", err)
+	}
+}
+
+func (b *batcher[T]) close(ctx context.Context) error {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+	close(b.stop)
+	<-b.done
+	return b.flushAll(ctx)
+}
+
+func routeTarget(routes map[models.EventType]string, defaultTarget string, eventType models.EventType) string {
+	if target, ok := routes[eventType]; ok {
+		return target
+	}
+	return defaultTarget
+}
+
+func validateAWSOptions(opts AWSOptions, maxBatch int) (AWSOptions, error) {
+	if opts.Region == "" {
+		return opts, fmt.Errorf("aws region is required")
+	}
+	if opts.DefaultTarget == "" && len(opts.Routes) == 0 {
+		return opts, fmt.Errorf("aws sink needs a default target or at least one route")
+	}
+	if opts.BatchSize <= 0 || opts.BatchSize > maxBatch {
+		opts.BatchSize = maxBatch
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = time.Second
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = defaultAWSQueueSize
+	}
+	return opts, nil
+}
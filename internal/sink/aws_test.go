@@ -0,0 +1,386 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models/modelstest"
+)
+
+type recordedCall struct {
+	target string
+	body   map[string]interface{}
+}
+
+func newRecordingServer(t *testing.T, response string) (*httptest.Server, *[]recordedCall, *sync.Mutex) {
+	t.Helper()
+	var (
+		mu    sync.Mutex
+		calls []recordedCall
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ") {
+			t.Errorf("request is not signed")
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		mu.Lock()
+		calls = append(calls, recordedCall{target: r.Header.Get("X-Amz-Target"), body: body})
+		mu.Unlock()
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls, &mu
+}
+
+// waitForCalls waits until the server received n calls, as full batches are
+// delivered in the background.
+func waitForCalls(t *testing.T, calls *[]recordedCall, mu *sync.Mutex, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mu.Lock()
+		got := len(*calls)
+		mu.Unlock()
+		if got >= n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("calls = %d, want %d", got, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestKinesisSink_BatchesPerStream(t *testing.T) {
+	srv, calls, mu := newRecordingServer(t, `{"FailedRecordCount":0,"Records":[]}`)
+
+	s, err := NewKinesisSink(AWSOptions{
+		Region:        "us-east-1",
+		Endpoint:      srv.URL,
+		DefaultTarget: "default-stream",
+		Routes: map[models.EventType]string{
+			models.EventTypeTokensMinted: "token-stream",
+		},
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewKinesisSink() error = %v", err)
+	}
+
+	ctx := context.Background()
+	publish := func(eventType models.EventType, sig string) {
+		base := models.BaseEvent{EventType: eventType, Signature: sig}
+		if err := s.Publish(ctx, modelstest.Envelope(t, base, &base)); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+
+	publish(models.EventTypeCounterIncremented, "sig1")
+	publish(models.EventTypeTokensMinted, "sig2")
+	publish(models.EventTypeCounterIncremented, "sig3")
+	waitForCalls(t, calls, mu, 1)
+
+	mu.Lock()
+	if len(*calls) != 1 {
+		t.Fatalf("calls after full batch = %d, want 1", len(*calls))
+	}
+	first := (*calls)[0]
+	mu.Unlock()
+
+	if first.target != "Kinesis_20131202.PutRecords" {
+		t.Errorf("target = %q", first.target)
+	}
+	if first.body["StreamName"] != "default-stream" {
+		t.Errorf("StreamName = %v, want default-stream", first.body["StreamName"])
+	}
+	if records := first.body["Records"].([]interface{}); len(records) != 2 {
+		t.Errorf("records = %d, want 2", len(records))
+	}
+
+	if err := s.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(*calls) != 2 {
+		t.Fatalf("calls after close = %d, want 2", len(*calls))
+	}
+	if (*calls)[1].body["StreamName"] != "token-stream" {
+		t.Errorf("StreamName = %v, want token-stream", (*calls)[1].body["StreamName"])
+	}
+}
+
+func TestKinesisSink_ReportsFailedRecords(t *testing.T) {
+	srv, calls, mu := newRecordingServer(t, `{"FailedRecordCount":1,"Records":[{"ErrorCode":"ProvisionedThroughputExceededException","ErrorMessage":"slow down"}]}`)
+
+	var failed []string
+	s, err := NewKinesisSink(AWSOptions{
+		Region:        "us-east-1",
+		Endpoint:      srv.URL,
+		DefaultTarget: "stream",
+		BatchSize:     1,
+		FlushInterval: time.Hour,
+		MaxAttempts:   3,
+		RetryBackoff:  time.Millisecond,
+		OnFailure: func(event models.BaseEvent, err error) {
+			failed = append(failed, event.Signature+": "+err.Error())
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewKinesisSink() error = %v", err)
+	}
+
+	base := models.BaseEvent{EventType: models.EventTypeCounterReset, Signature: "sig"}
+	if err := s.Publish(context.Background(), modelstest.Envelope(t, base, &base)); err != nil {
+		t.Errorf("Publish() error = %v, want nil", err)
+	}
+	if err := s.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(*calls) != 3 {
+		t.Errorf("calls = %d, want 3", len(*calls))
+	}
+	if len(failed) != 1 || !strings.HasPrefix(failed[0], "sig: ") || !strings.Contains(failed[0], "ProvisionedThroughputExceededException") {
+		t.Errorf("failures = %v, want one throughput failure of sig", failed)
+	}
+}
+
+func TestSQSSink_RetriesOnlyFailedMessages(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		batches [][]string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body sqsSendMessageBatchInput
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		var messages []string
+		for _, e := range body.Entries {
+			messages = append(messages, e.MessageBody)
+		}
+		mu.Lock()
+		batches = append(batches, messages)
+		first := len(batches) == 1
+		mu.Unlock()
+		if first {
+			_, _ = w.Write([]byte(`{"Successful":[{"Id":"0"}],"Failed":[{"Id":"1","Code":"InternalError","SenderFault":false},{"Id":"2","Code":"InvalidMessageContents","SenderFault":true}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"Successful":[{"Id":"0"}],"Failed":[]}`))
+	}))
+	defer srv.Close()
+
+	var failed []string
+	s, err := NewSQSSink(AWSOptions{
+		Region:        "us-east-1",
+		Endpoint:      srv.URL,
+		DefaultTarget: "https://sqs.us-east-1.amazonaws.com/123/events",
+		BatchSize:     3,
+		FlushInterval: time.Hour,
+		RetryBackoff:  time.Millisecond,
+		OnFailure: func(event models.BaseEvent, err error) {
+			failed = append(failed, event.Signature)
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewSQSSink() error = %v", err)
+	}
+
+	for _, sig := range []string{"a", "b", "c"} {
+		base := models.BaseEvent{EventType: models.EventTypeCounterReset, Signature: sig}
+		if err := s.Publish(context.Background(), modelstest.Envelope(t, base, &base)); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	if err := s.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(batches) != 2 {
+		t.Fatalf("batches = %d, want 2", len(batches))
+	}
+	if len(batches[1]) != 1 || !strings.Contains(batches[1][0], `"signature":"b"`) {
+		t.Errorf("retried batch = %v, want only b", batches[1])
+	}
+	if len(failed) != 1 || failed[0] != "c" {
+		t.Errorf("failures = %v, want [c]", failed)
+	}
+}
+
+func TestSQSSink_PublishDoesNotWaitForRetries(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	var (
+		mu     sync.Mutex
+		failed []string
+	)
+	s, err := NewSQSSink(AWSOptions{
+		Region:        "us-east-1",
+		Endpoint:      srv.URL,
+		DefaultTarget: "https://sqs.us-east-1.amazonaws.com/123/events",
+		BatchSize:     1,
+		FlushInterval: time.Hour,
+		MaxAttempts:   3,
+		RetryBackoff:  200 * time.Millisecond,
+		QueueSize:     1,
+		OnFailure: func(event models.BaseEvent, err error) {
+			mu.Lock()
+			failed = append(failed, event.Signature)
+			mu.Unlock()
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewSQSSink() error = %v", err)
+	}
+
+	// The first batch is being retried, the second waits in the queue and
+	// the third finds it full; none of them hold up Publish.
+	start := time.Now()
+	for _, sig := range []string{"a", "b", "c"} {
+		base := models.BaseEvent{EventType: models.EventTypeCounterReset, Signature: sig}
+		if err := s.Publish(context.Background(), modelstest.Envelope(t, base, &base)); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+		if sig == "a" {
+			for calls.Load() == 0 {
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}
+	if elapsed := time.Since(start); elapsed >= 200*time.Millisecond {
+		t.Errorf("Publish() took %v, want it not to wait for retries", elapsed)
+	}
+
+	if err := s.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if got := calls.Load(); got != 6 {
+		t.Errorf("calls = %d, want 3 attempts of a and b", got)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	slices.Sort(failed)
+	if !slices.Equal(failed, []string{"a", "b", "c"}) {
+		t.Errorf("failures = %v, want [a b c]", failed)
+	}
+}
+
+func TestSQSSink_SkipsUnroutedEvents(t *testing.T) {
+	srv, calls, mu := newRecordingServer(t, `{"Successful":[],"Failed":[]}`)
+
+	s, err := NewSQSSink(AWSOptions{
+		Region:   "us-east-1",
+		Endpoint: srv.URL,
+		Routes: map[models.EventType]string{
+			models.EventTypeNftMinted: "https://sqs.us-east-1.amazonaws.com/123/nfts",
+		},
+		BatchSize:     10,
+		FlushInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewSQSSink() error = %v", err)
+	}
+
+	ctx := context.Background()
+	for _, eventType := range []models.EventType{models.EventTypeNftMinted, models.EventTypeCounterReset} {
+		base := models.BaseEvent{EventType: eventType, Signature: "sig"}
+		if err := s.Publish(ctx, modelstest.Envelope(t, base, &base)); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+
+	if err := s.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(*calls) != 1 {
+		t.Fatalf("calls = %d, want 1", len(*calls))
+	}
+	if (*calls)[0].target != "AmazonSQS.SendMessageBatch" {
+		t.Errorf("target = %q", (*calls)[0].target)
+	}
+	if entries := (*calls)[0].body["Entries"].([]interface{}); len(entries) != 1 {
+		t.Errorf("entries = %d, want 1", len(entries))
+	}
+}
+
+func TestValidateAWSOptions(t *testing.T) {
+	if _, err := NewSQSSink(AWSOptions{DefaultTarget: "q"}); err == nil {
+		t.Error("expected error for missing region")
+	}
+	if _, err := NewKinesisSink(AWSOptions{Region: "us-east-1"}); err == nil {
+		t.Error("expected error for missing target")
+	}
+
+	opts, err := validateAWSOptions(AWSOptions{Region: "us-east-1", DefaultTarget: "q", BatchSize: 50}, sqsMaxBatch)
+	if err != nil {
+		t.Fatalf("validateAWSOptions() error = %v", err)
+	}
+	if opts.BatchSize != sqsMaxBatch {
+		t.Errorf("BatchSize = %d, want %d", opts.BatchSize, sqsMaxBatch)
+	}
+}
+
+func TestSQSSink_ConsumerLag(t *testing.T) {
+	srv, calls, mu := newRecordingServer(t, `{"Attributes":{"ApproximateNumberOfMessages":"42","ApproximateNumberOfMessagesNotVisible":"3"}}`)
+
+	s, err := NewSQSSink(AWSOptions{
+		Region:        "us-east-1",
+		Endpoint:      srv.URL,
+		DefaultTarget: "https://sqs.us-east-1.amazonaws.com/123/events",
+		Routes: map[models.EventType]string{
+			models.EventTypeNftMinted: "https://sqs.us-east-1.amazonaws.com/123/nfts",
+		},
+		FlushInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewSQSSink() error = %v", err)
+	}
+	defer s.Close(context.Background())
+
+	tracker := NewLagTracker(time.Minute, s, NewRedisPubSubSink(nil, ""))
+	tracker.Poll(context.Background())
+	lags := tracker.Snapshot()
+
+	if len(lags) != 2 {
+		t.Fatalf("lags = %+v, want one per queue", lags)
+	}
+	for _, lag := range lags {
+		if lag.Sink != "sqs" || lag.Backlog != 42 || lag.InFlight != 3 || lag.Error != "" {
+			t.Errorf("lag = %+v", lag)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(*calls) != 2 || (*calls)[0].target != "AmazonSQS.GetQueueAttributes" {
+		t.Errorf("calls = %+v", *calls)
+	}
+}
@@ -0,0 +1,282 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+type InvalidationKey string
+
+const (
+	defaultInvalidationQueueSize    = 1024
+	defaultInvalidationAttempts     = 5
+	defaultInvalidationRetryBackoff = 500 * time.Millisecond
+)
+
+const (
+	InvalidationKeyMint       InvalidationKey = "mint"
+	InvalidationKeyCollection InvalidationKey = "collection"
+	InvalidationKeyWallet     InvalidationKey = "wallet"
+)
+
+type InvalidationOptions struct {
+	// Templates maps a key kind to a URL template. The placeholders {mint},
+	// {collection} and {wallet} are replaced with the matching address, and
+	// {event_type} and {signature} with the triggering event's values.
+	Templates   map[InvalidationKey]string
+	Method      string
+	BearerToken string
+	Timeout     time.Duration
+	// QueueSize is how many purges wait to be sent before further ones are
+	// dropped; zero means defaultInvalidationQueueSize.
+	QueueSize int
+	// MaxAttempts is how often a purge is sent before it is given up on;
+	// zero means defaultInvalidationAttempts.
+	MaxAttempts int
+	// RetryBackoff is the delay before the first retry of a purge, doubled
+	// on every further retry; zero means defaultInvalidationRetryBackoff.
+	RetryBackoff time.Duration
+	// OnFailure is called for every purge given up on; nil logs it.
+	OnFailure func(target string, err error)
+}
+
+// InvalidationSink calls downstream cache purge endpoints for every mint,
+// collection and wallet an indexed event touches. Purges are queued and
+// sent in the background with retries, so a slow or failing endpoint never
+// holds up or fails the processing of an event.
+type InvalidationSink struct {
+	httpClient  *http.Client
+	templates   map[InvalidationKey]string
+	method      string
+	token       string
+	maxAttempts int
+	backoff     time.Duration
+	onFailure   func(target string, err error)
+
+	mu     sync.Mutex
+	closed bool
+	queue  chan string
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewInvalidationSink(opts InvalidationOptions) (*InvalidationSink, error) {
+	if len(opts.Templates) == 0 {
+		return nil, fmt.Errorf("at least one invalidation url template is required")
+	}
+	if opts.Method == "" {
+		opts.Method = http.MethodPost
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = defaultInvalidationQueueSize
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaultInvalidationAttempts
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = defaultInvalidationRetryBackoff
+	}
+	if opts.OnFailure == nil {
+		opts.OnFailure = func(target string, err error) {
+			log.Printf("warning: cache invalidation dropped %s: %v", target, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &InvalidationSink{
+		httpClient:  &http.Client{Timeout: opts.Timeout},
+		templates:   opts.Templates,
+		method:      strings.ToUpper(opts.Method),
+		token:       opts.BearerToken,
+		maxAttempts: opts.MaxAttempts,
+		backoff:     opts.RetryBackoff,
+		onFailure:   opts.OnFailure,
+		queue:       make(chan string, opts.QueueSize),
+		ctx:         ctx,
+		cancel:      cancel,
+		done:        make(chan struct{}),
+	}
+	go s.loop()
+	return s, nil
+}
+
+func (s *InvalidationSink) Publish(ctx context.Context, event models.Envelope) error {
+	model, err := event.Event()
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool)
+	for kind, keys := range invalidationKeys(model) {
+		tmpl, ok := s.templates[kind]
+		if !ok {
+			continue
+		}
+		for _, key := range keys {
+			if key.IsZero() {
+				continue
+			}
+			target := expandInvalidationURL(tmpl, kind, key.String(), event.Base)
+			if seen[target] {
+				continue
+			}
+			seen[target] = true
+			s.enqueue(target)
+		}
+	}
+	return nil
+}
+
+// enqueue queues a purge of target, dropping it when the queue is full or
+// the sink is closed.
+func (s *InvalidationSink) enqueue(target string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		s.onFailure(target, fmt.Errorf("sink closed"))
+		return
+	}
+	select {
+	case s.queue <- target:
+	default:
+		s.onFailure(target, fmt.Errorf("queue full"))
+	}
+}
+
+func (s *InvalidationSink) loop() {
+	defer close(s.done)
+	for target := range s.queue {
+		s.purge(target)
+	}
+}
+
+// purge sends a purge of target, retrying it with exponential backoff.
+func (s *InvalidationSink) purge(target string) {
+	delay := s.backoff
+	for attempt := 1; ; attempt++ {
+		err := s.invalidate(s.ctx, target)
+		if err == nil {
+			return
+		}
+		if attempt >= s.maxAttempts || s.ctx.Err() != nil {
+			s.onFailure(target, fmt.Errorf("after %d attempts: %w", attempt, err))
+			return
+		}
+		select {
+		case <-s.ctx.Done():
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+func (s *InvalidationSink) invalidate(ctx context.Context, target string) error {
+	req, err := http.NewRequestWithContext(ctx, s.method, target, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("create invalidation request: %w", err)
+	}
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("invalidate %s: %w", target, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("invalidate %s: unexpected status %d", target, resp.StatusCode)
+	}
+	return nil
+}
+
+// Close sends the queued purges, giving up on those still pending when ctx
+// is done.
+func (s *InvalidationSink) Close(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+	case <-ctx.Done():
+		s.cancel()
+		<-s.done
+	}
+	s.cancel()
+	s.httpClient.CloseIdleConnections()
+	return nil
+}
+
+func expandInvalidationURL(tmpl string, kind InvalidationKey, key string, base models.BaseEvent) string {
+	return strings.NewReplacer(
+		"{"+string(kind)+"}", url.PathEscape(key),
+		"{event_type}", url.PathEscape(string(base.EventType)),
+		"{signature}", url.PathEscape(base.Signature),
+	).Replace(tmpl)
+}
+
+// invalidationKeys lists the addresses in event that downstream caches are
+// keyed by.
+func invalidationKeys(event interface{}) map[InvalidationKey][]solana.PublicKey {
+	switch e := event.(type) {
+	case *models.TokensMintedEvent:
+		return map[InvalidationKey][]solana.PublicKey{
+			InvalidationKeyMint:   {e.Mint},
+			InvalidationKeyWallet: {e.Recipient},
+		}
+	case *models.TokensTransferredEvent:
+		return map[InvalidationKey][]solana.PublicKey{
+			InvalidationKeyMint:   {e.Mint},
+			InvalidationKeyWallet: {e.From, e.To},
+		}
+	case *models.TokensBurnedEvent:
+		return map[InvalidationKey][]solana.PublicKey{
+			InvalidationKeyMint:   {e.Mint},
+			InvalidationKeyWallet: {e.Owner},
+		}
+	case *models.UserAccountCreatedEvent:
+		return map[InvalidationKey][]solana.PublicKey{
+			InvalidationKeyWallet: {e.User, e.Authority},
+		}
+	case *models.UserAccountUpdatedEvent:
+		return map[InvalidationKey][]solana.PublicKey{
+			InvalidationKeyWallet: {e.User},
+		}
+	case *models.NftMintedEvent:
+		return map[InvalidationKey][]solana.PublicKey{
+			InvalidationKeyMint:       {e.NftMint},
+			InvalidationKeyCollection: {e.Collection},
+			InvalidationKeyWallet:     {e.Owner},
+		}
+	case *models.NftSoldEvent:
+		return map[InvalidationKey][]solana.PublicKey{
+			InvalidationKeyMint:   {e.NftMint},
+			InvalidationKeyWallet: {e.Seller, e.Buyer},
+		}
+	case *models.CounterPaymentReceivedEvent:
+		return map[InvalidationKey][]solana.PublicKey{
+			InvalidationKeyWallet: {e.Payer},
+		}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,159 @@
+package sink
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models/modelstest"
+)
+
+func TestInvalidationSink_Publish(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		paths []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PURGE" {
+			t.Errorf("method = %s, want PURGE", r.Method)
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("missing bearer token")
+		}
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	s, err := NewInvalidationSink(InvalidationOptions{
+		Templates: map[InvalidationKey]string{
+			InvalidationKeyMint:       srv.URL + "/mints/{mint}",
+			InvalidationKeyCollection: srv.URL + "/collections/{collection}",
+			InvalidationKeyWallet:     srv.URL + "/wallets/{wallet}/{event_type}",
+		},
+		Method:      "purge",
+		BearerToken: "secret",
+	})
+	if err != nil {
+		t.Fatalf("NewInvalidationSink() error = %v", err)
+	}
+
+	mint := solana.PublicKey{1}
+	collection := solana.PublicKey{2}
+	owner := solana.PublicKey{3}
+	event := &models.NftMintedEvent{NftMint: mint, Collection: collection, Owner: owner}
+	base := models.BaseEvent{EventType: models.EventTypeNftMinted, Signature: "sig"}
+
+	if err := s.Publish(context.Background(), modelstest.Envelope(t, base, event)); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if err := s.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	want := []string{
+		"/collections/" + collection.String(),
+		"/mints/" + mint.String(),
+		"/wallets/" + owner.String() + "/NftMintedEvent",
+	}
+	sort.Strings(paths)
+	if len(paths) != len(want) {
+		t.Fatalf("paths = %v, want %v", paths, want)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Errorf("paths[%d] = %s, want %s", i, paths[i], want[i])
+		}
+	}
+}
+
+func TestInvalidationSink_SkipsZeroAndDuplicateKeys(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer srv.Close()
+
+	s, err := NewInvalidationSink(InvalidationOptions{
+		Templates: map[InvalidationKey]string{InvalidationKeyWallet: srv.URL + "/wallets/{wallet}"},
+	})
+	if err != nil {
+		t.Fatalf("NewInvalidationSink() error = %v", err)
+	}
+
+	wallet := solana.PublicKey{9}
+	event := &models.TokensTransferredEvent{From: wallet, To: wallet}
+	if err := s.Publish(context.Background(), modelstest.Envelope(t, models.BaseEvent{}, event)); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if err := s.Publish(context.Background(), modelstest.Envelope(t, models.BaseEvent{}, &models.CounterPaymentReceivedEvent{})); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	if err := s.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+}
+
+func TestInvalidationSink_RetriesHTTPErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int32
+		wantCalls    int32
+		wantFailures int
+	}{
+		{name: "recovers", failures: 2, wantCalls: 3},
+		{name: "gives up", failures: 10, wantCalls: 3, wantFailures: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if calls.Add(1) <= tt.failures {
+					w.WriteHeader(http.StatusBadGateway)
+				}
+			}))
+			defer srv.Close()
+
+			var failures []string
+			s, err := NewInvalidationSink(InvalidationOptions{
+				Templates:    map[InvalidationKey]string{InvalidationKeyMint: srv.URL + "/{mint}"},
+				MaxAttempts:  3,
+				RetryBackoff: time.Millisecond,
+				OnFailure:    func(target string, err error) { failures = append(failures, target) },
+			})
+			if err != nil {
+				t.Fatalf("NewInvalidationSink() error = %v", err)
+			}
+
+			// A failing purge never fails the event.
+			event := &models.TokensBurnedEvent{Mint: solana.PublicKey{1}}
+			if err := s.Publish(context.Background(), modelstest.Envelope(t, models.BaseEvent{}, event)); err != nil {
+				t.Fatalf("Publish() error = %v", err)
+			}
+			if err := s.Close(context.Background()); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", got, tt.wantCalls)
+			}
+			if len(failures) != tt.wantFailures {
+				t.Errorf("failures = %v, want %d", failures, tt.wantFailures)
+			}
+		})
+	}
+}
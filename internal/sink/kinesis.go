@@ -0,0 +1,106 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+const kinesisMaxBatch = 500
+
+type KinesisSink struct {
+	client        *awsClient
+	delivery      awsDelivery
+	batcher       *batcher[awsRecord]
+	routes        map[models.EventType]string
+	defaultStream string
+}
+
+type kinesisRecord struct {
+	Data         []byte `json:"Data"`
+	PartitionKey string `json:"PartitionKey"`
+}
+
+type kinesisPutRecordsInput struct {
+	StreamName string          `json:"StreamName"`
+	Records    []kinesisRecord `json:"Records"`
+}
+
+type kinesisPutRecordsOutput struct {
+	FailedRecordCount int `json:"FailedRecordCount"`
+	Records           []struct {
+		ErrorCode    string `json:"ErrorCode"`
+		ErrorMessage string `json:"ErrorMessage"`
+	} `json:"Records"`
+}
+
+func NewKinesisSink(opts AWSOptions) (*KinesisSink, error) {
+	opts, err := validateAWSOptions(opts, kinesisMaxBatch)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &KinesisSink{
+		client:        newAWSClient(opts, "kinesis", "application/x-amz-json-1.1"),
+		delivery:      newAWSDelivery(opts, "kinesis"),
+		routes:        opts.Routes,
+		defaultStream: opts.DefaultTarget,
+	}
+	s.batcher = newQueuedBatcher(opts.BatchSize, opts.FlushInterval, opts.QueueSize, s.flush, s.delivery.drop)
+	return s, nil
+}
+
+func (s *KinesisSink) Publish(ctx context.Context, event models.Envelope) error {
+	stream := routeTarget(s.routes, s.defaultStream, event.Type)
+	if stream == "" {
+		return nil
+	}
+	return s.batcher.add(ctx, stream, awsRecord{base: event.Base, payload: event.Payload})
+}
+
+// flush delivers a batch; records that cannot be delivered are reported
+// to OnFailure rather than failing the event that filled the batch.
+func (s *KinesisSink) flush(ctx context.Context, stream string, records []awsRecord) error {
+	s.delivery.deliver(ctx, stream, records, s.putRecords)
+	return nil
+}
+
+// putRecords returns the records Kinesis rejected, which are all
+// retryable: throughput exceeded or an internal failure.
+func (s *KinesisSink) putRecords(ctx context.Context, stream string, records []awsRecord) ([]awsFailure, error) {
+	input := kinesisPutRecordsInput{
+		StreamName: stream,
+		Records:    make([]kinesisRecord, len(records)),
+	}
+	for i, record := range records {
+		input.Records[i] = kinesisRecord{
+			Data:         record.payload,
+			PartitionKey: record.base.Signature,
+		}
+	}
+
+	var output kinesisPutRecordsOutput
+	if err := s.client.call(ctx, "Kinesis_20131202.PutRecords", input, &output); err != nil {
+		return nil, fmt.Errorf("put records to %s: %w", stream, err)
+	}
+	if output.FailedRecordCount == 0 {
+		return nil, nil
+	}
+	if len(output.Records) != len(records) {
+		return nil, fmt.Errorf("put records to %s: %d of %d records failed", stream, output.FailedRecordCount, len(records))
+	}
+
+	// Results are in the order of the records.
+	var failed []awsFailure
+	for i, r := range output.Records {
+		if r.ErrorCode != "" {
+			failed = append(failed, awsFailure{record: records[i], err: fmt.Errorf("%s: %s", r.ErrorCode, r.ErrorMessage)})
+		}
+	}
+	return failed, nil
+}
+
+func (s *KinesisSink) Close(ctx context.Context) error {
+	return s.batcher.close(ctx)
+}
@@ -0,0 +1,90 @@
+package sink
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// ConsumerLag reports how far the consumers of one sink destination are
+// behind the indexer.
+type ConsumerLag struct {
+	Sink   string `json:"sink"`
+	Target string `json:"target"`
+	// Backlog counts messages published but not yet received by a consumer.
+	Backlog int64 `json:"backlog"`
+	// InFlight counts messages received but not yet acknowledged.
+	InFlight  int64     `json:"in_flight"`
+	CheckedAt time.Time `json:"checked_at"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// LagReporter is implemented by sinks whose backend tracks consumer
+// progress. Redis pub/sub and Kinesis do not: pub/sub keeps no backlog, and
+// Kinesis leaves checkpoints to the consumer library.
+type LagReporter interface {
+	ConsumerLag(ctx context.Context) []ConsumerLag
+}
+
+// LagTracker polls LagReporters in the background and keeps the latest
+// results, so the API and metrics never wait on a backend.
+type LagTracker struct {
+	reporters []LagReporter
+	interval  time.Duration
+	mu        sync.RWMutex
+	latest    []ConsumerLag
+}
+
+// NewLagTracker tracks the sinks that implement LagReporter, returning nil
+// when none do.
+func NewLagTracker(interval time.Duration, sinks ...Sink) *LagTracker {
+	var reporters []LagReporter
+	for _, s := range sinks {
+		if r, ok := s.(LagReporter); ok {
+			reporters = append(reporters, r)
+		}
+	}
+	if len(reporters) == 0 {
+		return nil
+	}
+	return &LagTracker{reporters: reporters, interval: interval}
+}
+
+func (t *LagTracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		t.Poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *LagTracker) Poll(ctx context.Context) {
+	var lags []ConsumerLag
+	for _, r := range t.reporters {
+		for _, lag := range r.ConsumerLag(ctx) {
+			if lag.Error != "" {
+				log.Printf("warning: consumer lag for %s %s: %s", lag.Sink, lag.Target, lag.Error)
+			}
+			lags = append(lags, lag)
+		}
+	}
+
+	t.mu.Lock()
+	t.latest = lags
+	t.mu.Unlock()
+}
+
+// Snapshot returns the results of the last poll.
+func (t *LagTracker) Snapshot() []ConsumerLag {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return append([]ConsumerLag(nil), t.latest...)
+}
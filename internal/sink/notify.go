@@ -0,0 +1,258 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+const (
+	defaultNotifyMessage = "{{.event_type}} at slot {{.slot}}: {{.signature}}"
+	defaultTelegramAPI   = "https://api.telegram.org"
+	// maxNotifyMessage is the shorter of the Discord (2000) and Telegram
+	// (4096) message limits.
+	maxNotifyMessage = 2000
+)
+
+// NotifyRulesFile is the YAML file of notification rules NOTIFY_RULES_FILE
+// points to.
+type NotifyRulesFile struct {
+	Rules []NotifyRuleSpec `json:"rules" yaml:"rules"`
+}
+
+// NotifyRuleSpec posts a message to Discord, Telegram or both whenever an
+// event of one of its types is indexed.
+type NotifyRuleSpec struct {
+	Name       string   `json:"name" yaml:"name"`
+	EventTypes []string `json:"event_types" yaml:"event_types"`
+	// Message is a text/template executed on the JSON fields of the event,
+	// such as {{.admin}} or {{.signature}}; empty posts the event type,
+	// slot and signature.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+	// DiscordWebhook is the URL of a Discord channel webhook.
+	DiscordWebhook string `json:"discord_webhook,omitempty" yaml:"discord_webhook,omitempty"`
+	// TelegramChatID is the chat the bot of TELEGRAM_BOT_TOKEN posts to.
+	TelegramChatID string `json:"telegram_chat_id,omitempty" yaml:"telegram_chat_id,omitempty"`
+}
+
+type notifyRule struct {
+	name           string
+	eventTypes     map[models.EventType]bool
+	message        *template.Template
+	discordWebhook string
+	telegramChatID string
+}
+
+type NotifyOptions struct {
+	Rules []NotifyRuleSpec
+	// TelegramToken is the bot token rules with a Telegram chat post with.
+	TelegramToken string
+	// TelegramAPI is the Bot API server; empty uses api.telegram.org.
+	TelegramAPI string
+	Timeout     time.Duration
+}
+
+// NotifySink posts a templated message for every event matching one of
+// its rules to the Discord webhook and Telegram chat of the rule.
+type NotifySink struct {
+	httpClient    *http.Client
+	rules         []notifyRule
+	telegramToken string
+	telegramAPI   string
+}
+
+func NewNotifySink(opts NotifyOptions) (*NotifySink, error) {
+	if len(opts.Rules) == 0 {
+		return nil, fmt.Errorf("at least one notification rule is required")
+	}
+	if opts.TelegramAPI == "" {
+		opts.TelegramAPI = defaultTelegramAPI
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+
+	s := &NotifySink{
+		httpClient:    &http.Client{Timeout: opts.Timeout},
+		telegramToken: opts.TelegramToken,
+		telegramAPI:   strings.TrimSuffix(opts.TelegramAPI, "/"),
+	}
+	for i, spec := range opts.Rules {
+		rule, err := newNotifyRule(spec, opts.TelegramToken != "")
+		if err != nil {
+			name := spec.Name
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			return nil, fmt.Errorf("notification rule %s: %w", name, err)
+		}
+		s.rules = append(s.rules, rule)
+	}
+	return s, nil
+}
+
+func newNotifyRule(spec NotifyRuleSpec, telegram bool) (notifyRule, error) {
+	rule := notifyRule{
+		name:           spec.Name,
+		eventTypes:     make(map[models.EventType]bool, len(spec.EventTypes)),
+		discordWebhook: spec.DiscordWebhook,
+		telegramChatID: spec.TelegramChatID,
+	}
+	if len(spec.EventTypes) == 0 {
+		return rule, fmt.Errorf("event_types is required")
+	}
+	for _, t := range spec.EventTypes {
+		eventType := models.EventType(t)
+		if !eventType.Known() {
+			return rule, fmt.Errorf("unknown event type %q", t)
+		}
+		rule.eventTypes[eventType] = true
+	}
+	if spec.DiscordWebhook == "" && spec.TelegramChatID == "" {
+		return rule, fmt.Errorf("discord_webhook or telegram_chat_id is required")
+	}
+	if spec.DiscordWebhook != "" {
+		u, err := url.Parse(spec.DiscordWebhook)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return rule, fmt.Errorf("discord_webhook must be an http or https URL")
+		}
+	}
+	if spec.TelegramChatID != "" && !telegram {
+		return rule, fmt.Errorf("telegram_chat_id requires TELEGRAM_BOT_TOKEN")
+	}
+
+	message := spec.Message
+	if message == "" {
+		message = defaultNotifyMessage
+	}
+	tmpl, err := template.New(spec.Name).Parse(message)
+	if err != nil {
+		return rule, fmt.Errorf("message: %w", err)
+	}
+	rule.message = tmpl
+	return rule, nil
+}
+
+func (s *NotifySink) Publish(ctx context.Context, event models.Envelope) error {
+	var fields map[string]interface{}
+	for _, rule := range s.rules {
+		if !rule.eventTypes[event.Type] {
+			continue
+		}
+		if fields == nil {
+			var err error
+			if fields, err = notifyFields(event); err != nil {
+				return err
+			}
+		}
+		text, err := rule.render(fields)
+		if err != nil {
+			return err
+		}
+		if rule.discordWebhook != "" {
+			if err := s.postDiscord(ctx, rule.discordWebhook, text); err != nil {
+				return fmt.Errorf("notification rule %s: %w", rule.name, err)
+			}
+		}
+		if rule.telegramChatID != "" {
+			if err := s.postTelegram(ctx, rule.telegramChatID, text); err != nil {
+				return fmt.Errorf("notification rule %s: %w", rule.name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *NotifySink) Close(ctx context.Context) error {
+	s.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// notifyFields returns the JSON fields of the event, with those of its base
+// taking precedence as the payload may not carry them.
+func notifyFields(event models.Envelope) (map[string]interface{}, error) {
+	base, err := json.Marshal(event.Base)
+	if err != nil {
+		return nil, fmt.Errorf("marshal notification event: %w", err)
+	}
+	fields := make(map[string]interface{})
+	for _, data := range [][]byte{event.Payload, base} {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&fields); err != nil {
+			return nil, fmt.Errorf("decode notification event: %w", err)
+		}
+	}
+	delete(fields, "raw_data")
+	return fields, nil
+}
+
+func (r notifyRule) render(fields map[string]interface{}) (string, error) {
+	var buf strings.Builder
+	if err := r.message.Execute(&buf, fields); err != nil {
+		return "", fmt.Errorf("notification rule %s: render message: %w", r.name, err)
+	}
+	text := buf.String()
+	if runes := []rune(text); len(runes) > maxNotifyMessage {
+		text = string(runes[:maxNotifyMessage-1]) + "…"
+	}
+	return text, nil
+}
+
+func (s *NotifySink) postDiscord(ctx context.Context, webhook, text string) error {
+	return s.postJSON(ctx, webhook, map[string]string{"content": text}, "discord notification")
+}
+
+func (s *NotifySink) postTelegram(ctx context.Context, chatID, text string) error {
+	target := s.telegramAPI + "/bot" + s.telegramToken + "/sendMessage"
+	err := s.postJSON(ctx, target, map[string]string{"chat_id": chatID, "text": text}, "telegram notification")
+	if err != nil {
+		// The error may quote the URL, which holds the bot token.
+		return errors.New(strings.ReplaceAll(err.Error(), s.telegramToken, "<token>"))
+	}
+	return nil
+}
+
+func (s *NotifySink) postJSON(ctx context.Context, target string, payload interface{}, what string) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", what, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create %s: %w", what, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return doWriteRequest(s.httpClient, req, what, nil)
+}
+
+// ReadNotifyRules decodes a YAML notification rules file.
+func ReadNotifyRules(r io.Reader) ([]NotifyRuleSpec, error) {
+	var file NotifyRulesFile
+	if err := config.DecodeYAML(r, &file); err != nil {
+		return nil, fmt.Errorf("decode notification rules: %w", err)
+	}
+	return file.Rules, nil
+}
+
+// ReadNotifyRulesFile reads the rules of the file at path.
+func ReadNotifyRulesFile(path string) ([]NotifyRuleSpec, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open notification rules: %w", err)
+	}
+	defer f.Close()
+	return ReadNotifyRules(f)
+}
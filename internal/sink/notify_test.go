@@ -0,0 +1,146 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models/modelstest"
+)
+
+func TestNotifySink_Publish(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received = make(map[string]map[string]string)
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		mu.Lock()
+		received[r.URL.Path] = body
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	s, err := NewNotifySink(NotifyOptions{
+		Rules: []NotifyRuleSpec{
+			{
+				Name:           "paused",
+				EventTypes:     []string{"ProgramPausedEvent"},
+				Message:        "{{if .paused}}Paused{{else}}Unpaused{{end}} by {{.admin}} in {{.signature}}",
+				DiscordWebhook: srv.URL + "/discord",
+				TelegramChatID: "-100",
+			},
+			{
+				Name:           "config",
+				EventTypes:     []string{"ConfigUpdatedEvent"},
+				DiscordWebhook: srv.URL + "/config",
+			},
+		},
+		TelegramToken: "123:abc",
+		TelegramAPI:   srv.URL,
+	})
+	if err != nil {
+		t.Fatalf("NewNotifySink() error = %v", err)
+	}
+
+	base := models.BaseEvent{EventType: models.EventTypeProgramPaused, Signature: "sig1", Slot: 7}
+	event := &models.ProgramPausedEvent{Admin: solana.PublicKey{1}, Paused: true}
+	if err := s.Publish(context.Background(), modelstest.Envelope(t, base, event)); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	want := "Paused by " + solana.PublicKey{1}.String() + " in sig1"
+	if got := received["/discord"]["content"]; got != want {
+		t.Errorf("discord content = %q, want %q", got, want)
+	}
+	telegram := received["/bot123:abc/sendMessage"]
+	if telegram["chat_id"] != "-100" || telegram["text"] != want {
+		t.Errorf("telegram message = %v, want chat -100 and text %q", telegram, want)
+	}
+	if _, ok := received["/config"]; ok {
+		t.Errorf("config rule notified of a ProgramPausedEvent")
+	}
+
+	base = models.BaseEvent{EventType: models.EventTypeConfigUpdated, Signature: "sig2", Slot: 9}
+	if err := s.Publish(context.Background(), modelstest.Envelope(t, base, &models.ConfigUpdatedEvent{})); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if got, want := received["/config"]["content"], "ConfigUpdatedEvent at slot 9: sig2"; got != want {
+		t.Errorf("config content = %q, want %q", got, want)
+	}
+}
+
+func TestNotifySink_TelegramErrorHidesToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "chat not found", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	s, err := NewNotifySink(NotifyOptions{
+		Rules:         []NotifyRuleSpec{{Name: "paused", EventTypes: []string{"ProgramPausedEvent"}, TelegramChatID: "1"}},
+		TelegramToken: "123:secret",
+		TelegramAPI:   srv.URL,
+	})
+	if err != nil {
+		t.Fatalf("NewNotifySink() error = %v", err)
+	}
+	err = s.Publish(context.Background(), modelstest.Envelope(t, models.BaseEvent{EventType: models.EventTypeProgramPaused}, &models.ProgramPausedEvent{}))
+	if err == nil {
+		t.Fatal("Publish() error = nil, want the status")
+	}
+	if strings.Contains(err.Error(), "secret") {
+		t.Errorf("Publish() error = %v, want the token hidden", err)
+	}
+}
+
+func TestNewNotifySink_InvalidRules(t *testing.T) {
+	tests := []struct {
+		name string
+		rule NotifyRuleSpec
+		want string
+	}{
+		{"no event types", NotifyRuleSpec{DiscordWebhook: "https://discord.test/hook"}, "event_types is required"},
+		{"unknown event type", NotifyRuleSpec{EventTypes: []string{"Nope"}, DiscordWebhook: "https://discord.test/hook"}, "unknown event type"},
+		{"no target", NotifyRuleSpec{EventTypes: []string{"ProgramPausedEvent"}}, "discord_webhook or telegram_chat_id"},
+		{"bad webhook", NotifyRuleSpec{EventTypes: []string{"ProgramPausedEvent"}, DiscordWebhook: "discord"}, "http or https URL"},
+		{"no bot token", NotifyRuleSpec{EventTypes: []string{"ProgramPausedEvent"}, TelegramChatID: "1"}, "TELEGRAM_BOT_TOKEN"},
+		{"bad template", NotifyRuleSpec{EventTypes: []string{"ProgramPausedEvent"}, DiscordWebhook: "https://discord.test/hook", Message: "{{.admin"}, "message"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewNotifySink(NotifyOptions{Rules: []NotifyRuleSpec{tt.rule}})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("NewNotifySink() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadNotifyRules(t *testing.T) {
+	rules, err := ReadNotifyRules(strings.NewReader(`
+rules:
+  - name: paused
+    event_types: [ProgramPausedEvent, ConfigUpdatedEvent]
+    message: "Paused by {{.admin}}"
+    discord_webhook: https://discord.test/hook
+`))
+	if err != nil {
+		t.Fatalf("ReadNotifyRules() error = %v", err)
+	}
+	if len(rules) != 1 || rules[0].Name != "paused" || len(rules[0].EventTypes) != 2 || rules[0].Message != "Paused by {{.admin}}" {
+		t.Errorf("ReadNotifyRules() = %+v", rules)
+	}
+
+	if _, err := ReadNotifyRules(strings.NewReader("rules:\n  - name: x\n    chanel: y\n")); err == nil {
+		t.Errorf("ReadNotifyRules() error = nil, want an unknown field error")
+	}
+}
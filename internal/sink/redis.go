@@ -0,0 +1,43 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+type Publisher interface {
+	Publish(ctx context.Context, channel string, message []byte) error
+}
+
+// RedisPubSubSink fans out every event to "<prefix>" and to
+// "<prefix>:<event type>" so subscribers can listen to all or one type.
+type RedisPubSubSink struct {
+	publisher Publisher
+	prefix    string
+}
+
+func NewRedisPubSubSink(publisher Publisher, prefix string) *RedisPubSubSink {
+	if prefix == "" {
+		prefix = "solana_indexer:events"
+	}
+	return &RedisPubSubSink{
+		publisher: publisher,
+		prefix:    prefix,
+	}
+}
+
+func (s *RedisPubSubSink) Publish(ctx context.Context, event models.Envelope) error {
+	for _, channel := range []string{s.prefix, s.prefix + ":" + string(event.Type)} {
+		if err := s.publisher.Publish(ctx, channel, event.Payload); err != nil {
+			return fmt.Errorf("publish to %s: %w", channel, err)
+		}
+	}
+	return nil
+}
+
+// Close is a no-op; the publisher's connection is owned by the caller.
+func (s *RedisPubSubSink) Close(ctx context.Context) error {
+	return nil
+}
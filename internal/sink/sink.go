@@ -0,0 +1,21 @@
+package sink
+
+import (
+	"context"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// Sink receives every event after it has been persisted by the repository.
+type Sink interface {
+	Publish(ctx context.Context, event models.Envelope) error
+	Close(ctx context.Context) error
+}
+
+// Projection is a sink maintaining a read model in the event store, which
+// can be updated in the transaction that saves the event instead of after
+// it. Project returns the failures Publish would only log.
+type Projection interface {
+	Sink
+	Project(ctx context.Context, event models.Envelope) error
+}
@@ -0,0 +1,158 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+const sqsMaxBatch = 10
+
+type SQSSink struct {
+	client       *awsClient
+	delivery     awsDelivery
+	batcher      *batcher[awsRecord]
+	routes       map[models.EventType]string
+	defaultQueue string
+}
+
+type sqsMessageAttribute struct {
+	DataType    string `json:"DataType"`
+	StringValue string `json:"StringValue"`
+}
+
+type sqsBatchEntry struct {
+	ID                string                         `json:"Id"`
+	MessageBody       string                         `json:"MessageBody"`
+	MessageAttributes map[string]sqsMessageAttribute `json:"MessageAttributes"`
+}
+
+type sqsSendMessageBatchInput struct {
+	QueueURL string          `json:"QueueUrl"`
+	Entries  []sqsBatchEntry `json:"Entries"`
+}
+
+type sqsSendMessageBatchOutput struct {
+	Failed []struct {
+		ID          string `json:"Id"`
+		Code        string `json:"Code"`
+		Message     string `json:"Message"`
+		SenderFault bool   `json:"SenderFault"`
+	} `json:"Failed"`
+}
+
+func NewSQSSink(opts AWSOptions) (*SQSSink, error) {
+	opts, err := validateAWSOptions(opts, sqsMaxBatch)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &SQSSink{
+		client:       newAWSClient(opts, "sqs", "application/x-amz-json-1.0"),
+		delivery:     newAWSDelivery(opts, "sqs"),
+		routes:       opts.Routes,
+		defaultQueue: opts.DefaultTarget,
+	}
+	s.batcher = newQueuedBatcher(opts.BatchSize, opts.FlushInterval, opts.QueueSize, s.flush, s.delivery.drop)
+	return s, nil
+}
+
+func (s *SQSSink) Publish(ctx context.Context, event models.Envelope) error {
+	queueURL := routeTarget(s.routes, s.defaultQueue, event.Type)
+	if queueURL == "" {
+		return nil
+	}
+	return s.batcher.add(ctx, queueURL, awsRecord{base: event.Base, payload: event.Payload})
+}
+
+// flush delivers a batch; messages that cannot be delivered are reported
+// to OnFailure rather than failing the event that filled the batch.
+func (s *SQSSink) flush(ctx context.Context, queueURL string, records []awsRecord) error {
+	s.delivery.deliver(ctx, queueURL, records, s.sendMessageBatch)
+	return nil
+}
+
+// sendMessageBatch returns the messages SQS rejected. Sender faults, such
+// as a message that is too large, fail again when retried.
+func (s *SQSSink) sendMessageBatch(ctx context.Context, queueURL string, records []awsRecord) ([]awsFailure, error) {
+	input := sqsSendMessageBatchInput{
+		QueueURL: queueURL,
+		Entries:  make([]sqsBatchEntry, len(records)),
+	}
+	for i, record := range records {
+		input.Entries[i] = sqsBatchEntry{
+			ID:          strconv.Itoa(i),
+			MessageBody: string(record.payload),
+			MessageAttributes: map[string]sqsMessageAttribute{
+				"event_type": {DataType: "String", StringValue: string(record.base.EventType)},
+				"signature":  {DataType: "String", StringValue: record.base.Signature},
+			},
+		}
+	}
+
+	var output sqsSendMessageBatchOutput
+	if err := s.client.call(ctx, "AmazonSQS.SendMessageBatch", input, &output); err != nil {
+		return nil, fmt.Errorf("send message batch to %s: %w", queueURL, err)
+	}
+
+	var failed []awsFailure
+	for _, f := range output.Failed {
+		i, err := strconv.Atoi(f.ID)
+		if err != nil || i < 0 || i >= len(records) {
+			return nil, fmt.Errorf("send message batch to %s: unknown failed entry %q", queueURL, f.ID)
+		}
+		failed = append(failed, awsFailure{record: records[i], err: fmt.Errorf("%s: %s", f.Code, f.Message), permanent: f.SenderFault})
+	}
+	return failed, nil
+}
+
+type sqsGetQueueAttributesInput struct {
+	QueueURL       string   `json:"QueueUrl"`
+	AttributeNames []string `json:"AttributeNames"`
+}
+
+type sqsGetQueueAttributesOutput struct {
+	Attributes map[string]string `json:"Attributes"`
+}
+
+// ConsumerLag reports the approximate number of visible and in flight
+// messages of every queue the sink publishes to.
+func (s *SQSSink) ConsumerLag(ctx context.Context) []ConsumerLag {
+	seen := map[string]bool{s.defaultQueue: s.defaultQueue != ""}
+	for _, queueURL := range s.routes {
+		seen[queueURL] = queueURL != ""
+	}
+	queues := make([]string, 0, len(seen))
+	for queueURL, ok := range seen {
+		if ok {
+			queues = append(queues, queueURL)
+		}
+	}
+	sort.Strings(queues)
+
+	lags := make([]ConsumerLag, 0, len(queues))
+	for _, queueURL := range queues {
+		lag := ConsumerLag{Sink: "sqs", Target: queueURL, CheckedAt: s.client.now()}
+
+		var output sqsGetQueueAttributesOutput
+		err := s.client.call(ctx, "AmazonSQS.GetQueueAttributes", sqsGetQueueAttributesInput{
+			QueueURL:       queueURL,
+			AttributeNames: []string{"ApproximateNumberOfMessages", "ApproximateNumberOfMessagesNotVisible"},
+		}, &output)
+		if err != nil {
+			lag.Error = err.Error()
+		} else {
+			lag.Backlog, _ = strconv.ParseInt(output.Attributes["ApproximateNumberOfMessages"], 10, 64)
+			lag.InFlight, _ = strconv.ParseInt(output.Attributes["ApproximateNumberOfMessagesNotVisible"], 10, 64)
+		}
+		lags = append(lags, lag)
+	}
+	return lags
+}
+
+func (s *SQSSink) Close(ctx context.Context) error {
+	return s.batcher.close(ctx)
+}
@@ -0,0 +1,56 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// Switch publishes to a set of sinks that can be replaced while events are
+// published, e.g. when the configuration is reloaded.
+type Switch struct {
+	mu    sync.RWMutex
+	sinks []Sink
+}
+
+func NewSwitch(sinks ...Sink) *Switch {
+	return &Switch{sinks: sinks}
+}
+
+func (s *Switch) Publish(ctx context.Context, event models.Envelope) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, sink := range s.sinks {
+		if err := sink.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Replace publishes later events to sinks and returns the previous sinks,
+// which no longer receive events. The caller closes them.
+func (s *Switch) Replace(sinks ...Sink) []Sink {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous := s.sinks
+	s.sinks = sinks
+	return previous
+}
+
+func (s *Switch) Close(ctx context.Context) error {
+	return CloseAll(ctx, s.Replace())
+}
+
+// CloseAll closes every sink, returning the errors joined.
+func CloseAll(ctx context.Context, sinks []Sink) error {
+	var errs []error
+	for _, sink := range sinks {
+		if err := sink.Close(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,76 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+type WatchlistOptions struct {
+	// URL is notified of the transactions of addresses without a webhook of
+	// their own; empty notifies only those with one.
+	URL string
+	// Secret signs every body like WebhookOptions.Secret.
+	Secret string
+	// Webhook returns the webhook of a watched address, or "".
+	Webhook func(address string) string
+	Timeout time.Duration
+}
+
+// WatchlistSink notifies a webhook whenever an address of the watchlist
+// appears in an indexed transaction. It POSTs every WatchedTransactionEvent
+// as a JSON object and ignores other events.
+type WatchlistSink struct {
+	httpClient *http.Client
+	opts       WatchlistOptions
+}
+
+func NewWatchlistSink(opts WatchlistOptions) *WatchlistSink {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	return &WatchlistSink{httpClient: &http.Client{Timeout: opts.Timeout}, opts: opts}
+}
+
+func (s *WatchlistSink) Publish(ctx context.Context, event models.Envelope) error {
+	if event.Type != models.EventTypeWatchedTransaction {
+		return nil
+	}
+	model, err := event.Event()
+	if err != nil {
+		return err
+	}
+	e, ok := model.(*models.WatchedTransactionEvent)
+	if !ok {
+		return nil
+	}
+	target := s.opts.URL
+	if s.opts.Webhook != nil {
+		if hook := s.opts.Webhook(e.Address.String()); hook != "" {
+			target = hook
+		}
+	}
+	if target == "" {
+		return nil
+	}
+
+	body := event.Payload
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create watchlist notification: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.opts.Secret != "" {
+		req.Header.Set("X-Signature-256", "sha256="+signPayload(s.opts.Secret, body))
+	}
+	return doWriteRequest(s.httpClient, req, "watchlist notification", nil)
+}
+
+func (s *WatchlistSink) Close(ctx context.Context) error {
+	s.httpClient.CloseIdleConnections()
+	return nil
+}
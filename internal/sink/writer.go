@@ -0,0 +1,58 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// Writer is an output that takes events in batches, such as a secondary
+// database, a message broker or a webhook. WriterSink turns one into a Sink.
+type Writer interface {
+	Write(ctx context.Context, events []models.Envelope) error
+	Close(ctx context.Context) error
+}
+
+// WriterSink buffers events for a Writer. With a batch size of 1 every
+// event is written before Publish returns, so a failed write fails the
+// transaction and it is retried; larger batches are written in the
+// background and failed writes are only logged.
+type WriterSink struct {
+	name    string
+	writer  Writer
+	batcher *batcher[models.Envelope]
+}
+
+func NewWriterSink(name string, w Writer, batchSize int, flushInterval time.Duration) *WriterSink {
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	if flushInterval <= 0 {
+		flushInterval = time.Second
+	}
+	s := &WriterSink{name: name, writer: w}
+	s.batcher = newBatcher(batchSize, flushInterval, s.write)
+	return s
+}
+
+func (s *WriterSink) write(ctx context.Context, _ string, events []models.Envelope) error {
+	if err := s.writer.Write(ctx, events); err != nil {
+		return fmt.Errorf("write %d events to %s sink: %w", len(events), s.name, err)
+	}
+	return nil
+}
+
+func (s *WriterSink) Publish(ctx context.Context, event models.Envelope) error {
+	return s.batcher.add(ctx, "", event)
+}
+
+// Close flushes buffered events and closes the writer.
+func (s *WriterSink) Close(ctx context.Context) error {
+	flushErr := s.batcher.close(ctx)
+	if err := s.writer.Close(ctx); err != nil {
+		return fmt.Errorf("close %s sink: %w", s.name, err)
+	}
+	return flushErr
+}
@@ -0,0 +1,154 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models/modelstest"
+)
+
+type fakeWriter struct {
+	batches [][]models.Envelope
+	err     error
+}
+
+func (w *fakeWriter) Write(ctx context.Context, events []models.Envelope) error {
+	w.batches = append(w.batches, events)
+	return w.err
+}
+
+func (w *fakeWriter) Close(ctx context.Context) error {
+	return nil
+}
+
+func TestWriterSink(t *testing.T) {
+	ctx := context.Background()
+	base := models.BaseEvent{EventType: models.EventTypeTokensMinted, Signature: "sig"}
+
+	w := &fakeWriter{err: errors.New("down")}
+	s := NewWriterSink("fake", w, 1, time.Hour)
+	if err := s.Publish(ctx, modelstest.Envelope(t, base, &models.TokensMintedEvent{})); err == nil {
+		t.Errorf("Publish() error = nil, want the write error with a batch size of 1")
+	}
+
+	w = &fakeWriter{}
+	s = NewWriterSink("fake", w, 3, time.Hour)
+	for i := 0; i < 4; i++ {
+		if err := s.Publish(ctx, modelstest.Envelope(t, base, &models.TokensMintedEvent{Amount: uint64(i)})); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	if len(w.batches) != 1 || len(w.batches[0]) != 3 {
+		t.Fatalf("batches before Close = %d, want one of 3 events", len(w.batches))
+	}
+	if err := s.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if len(w.batches) != 2 || len(w.batches[1]) != 1 {
+		t.Errorf("Close() did not flush the remaining event: %d batches", len(w.batches))
+	}
+}
+
+func TestStdoutWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewStdoutWriter(&buf)
+	events := []models.Envelope{{Payload: json.RawMessage(`{"a":1}`)}, {Payload: json.RawMessage(`{"b":2}`)}}
+	if err := w.Write(context.Background(), events); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if got, want := buf.String(), "{\"a\":1}\n{\"b\":2}\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestWebhookWriter(t *testing.T) {
+	var body []byte
+	var signature string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get("X-Signature-256")
+	}))
+	defer srv.Close()
+
+	w, err := NewWebhookWriter(WebhookOptions{URL: srv.URL, Secret: "secret"})
+	if err != nil {
+		t.Fatalf("NewWebhookWriter() error = %v", err)
+	}
+	if err := w.Write(context.Background(), []models.Envelope{{Payload: json.RawMessage(`{"a":1}`)}}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if string(body) != `[{"a":1}]` {
+		t.Errorf("body = %s, want a JSON array of the events", body)
+	}
+	if want := "sha256=" + signPayload("secret", body); signature != want {
+		t.Errorf("X-Signature-256 = %q, want %q", signature, want)
+	}
+}
+
+func TestKafkaWriter(t *testing.T) {
+	failing := false
+	var got struct {
+		Records []kafkaRecord `json:"records"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/topics/events" {
+			t.Errorf("path = %s, want /topics/events", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		if failing {
+			io.WriteString(w, `{"offsets":[{"partition":0,"offset":1},{"error_code":50002,"error":"broker down"}]}`)
+			return
+		}
+		io.WriteString(w, `{"offsets":[{"partition":0,"offset":1}]}`)
+	}))
+	defer srv.Close()
+
+	w, err := NewKafkaWriter(KafkaOptions{RESTURL: srv.URL + "/", Topic: "events"})
+	if err != nil {
+		t.Fatalf("NewKafkaWriter() error = %v", err)
+	}
+	events := []models.Envelope{{Base: models.BaseEvent{Signature: "sig"}, Payload: json.RawMessage(`{"a":1}`)}}
+	if err := w.Write(context.Background(), events); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if len(got.Records) != 1 || got.Records[0].Key != "sig" {
+		t.Errorf("records = %+v, want one keyed by signature", got.Records)
+	}
+
+	failing = true
+	if err := w.Write(context.Background(), events); err == nil || !strings.Contains(err.Error(), "broker down") {
+		t.Errorf("Write() error = %v, want the record error", err)
+	}
+}
+
+func TestSwitch_Replace(t *testing.T) {
+	ctx := context.Background()
+	base := models.BaseEvent{EventType: models.EventTypeTokensMinted, Signature: "sig"}
+
+	before, after := &fakeWriter{}, &fakeWriter{}
+	s := NewSwitch(NewWriterSink("before", before, 1, time.Hour))
+	if err := s.Publish(ctx, modelstest.Envelope(t, base, &models.TokensMintedEvent{})); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	previous := s.Replace(NewWriterSink("after", after, 1, time.Hour))
+	if err := CloseAll(ctx, previous); err != nil {
+		t.Fatalf("CloseAll() error = %v", err)
+	}
+	if err := s.Publish(ctx, modelstest.Envelope(t, base, &models.TokensMintedEvent{})); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if len(before.batches) != 1 || len(after.batches) != 1 {
+		t.Errorf("batches = %d before and %d after Replace, want 1 and 1", len(before.batches), len(after.batches))
+	}
+}
@@ -0,0 +1,264 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/fieldcrypt"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+// RepositoryWriter saves events to a repository other than the primary one,
+// e.g. to keep a Postgres copy of a Mongo deployment. It owns the repository
+// and closes it.
+type RepositoryWriter struct {
+	repo   repository.Repository
+	cipher *fieldcrypt.Cipher
+}
+
+func NewRepositoryWriter(repo repository.Repository) *RepositoryWriter {
+	return &RepositoryWriter{repo: repo}
+}
+
+// SetCipher saves the fields the cipher encrypts encrypted, as the primary
+// repository stores them.
+func (w *RepositoryWriter) SetCipher(c *fieldcrypt.Cipher) {
+	w.cipher = c
+}
+
+func (w *RepositoryWriter) event(e models.Envelope) (models.Event, error) {
+	event, err := e.Event()
+	if err != nil || w.cipher == nil {
+		return event, err
+	}
+	return w.cipher.Encrypt(event)
+}
+
+func (w *RepositoryWriter) Write(ctx context.Context, events []models.Envelope) error {
+	if saver, ok := w.repo.(repository.BatchSaver); ok {
+		batch := make([]models.Event, 0, len(events))
+		for _, e := range events {
+			event, err := w.event(e)
+			if err != nil {
+				return err
+			}
+			batch = append(batch, event)
+		}
+		if err := saver.SaveEvents(ctx, batch); err != nil {
+			return fmt.Errorf("save %d events: %w", len(batch), err)
+		}
+		return nil
+	}
+	for _, e := range events {
+		event, err := w.event(e)
+		if err != nil {
+			return err
+		}
+		if err := w.repo.SaveEvent(ctx, event); err != nil {
+			return fmt.Errorf("save event %s: %w", e.Base.Signature, err)
+		}
+	}
+	return nil
+}
+
+func (w *RepositoryWriter) Close(ctx context.Context) error {
+	return w.repo.Close(ctx)
+}
+
+// StdoutWriter prints one JSON document per event and line.
+type StdoutWriter struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+func NewStdoutWriter(out io.Writer) *StdoutWriter {
+	return &StdoutWriter{out: out}
+}
+
+func (w *StdoutWriter) Write(ctx context.Context, events []models.Envelope) error {
+	var buf bytes.Buffer
+	for _, e := range events {
+		buf.Write(e.Payload)
+		buf.WriteByte('\n')
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err := w.out.Write(buf.Bytes())
+	return err
+}
+
+func (w *StdoutWriter) Close(ctx context.Context) error {
+	return nil
+}
+
+type WebhookOptions struct {
+	URL         string
+	BearerToken string
+	// Secret signs every body with HMAC-SHA256, sent hex encoded in the
+	// X-Signature-256 header as "sha256=<hex>".
+	Secret  string
+	Timeout time.Duration
+}
+
+// WebhookWriter POSTs every batch as a JSON array.
+type WebhookWriter struct {
+	httpClient *http.Client
+	opts       WebhookOptions
+}
+
+func NewWebhookWriter(opts WebhookOptions) (*WebhookWriter, error) {
+	if _, err := url.ParseRequestURI(opts.URL); err != nil {
+		return nil, fmt.Errorf("invalid webhook url: %w", err)
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	return &WebhookWriter{
+		httpClient: &http.Client{Timeout: opts.Timeout},
+		opts:       opts,
+	}, nil
+}
+
+func (w *WebhookWriter) Write(ctx context.Context, events []models.Envelope) error {
+	payload := make([]json.RawMessage, len(events))
+	for i, e := range events {
+		payload[i] = e.Payload
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal events: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.opts.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.opts.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+w.opts.BearerToken)
+	}
+	if w.opts.Secret != "" {
+		req.Header.Set("X-Signature-256", "sha256="+signPayload(w.opts.Secret, body))
+	}
+	return doWriteRequest(w.httpClient, req, "webhook", nil)
+}
+
+func (w *WebhookWriter) Close(ctx context.Context) error {
+	return nil
+}
+
+func signPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+type KafkaOptions struct {
+	// RESTURL is the base URL of a Kafka REST Proxy (v2 API).
+	RESTURL string
+	Topic   string
+	Timeout time.Duration
+}
+
+// KafkaWriter produces events through a Kafka REST Proxy, keyed by
+// transaction signature so the events of a transaction share a partition.
+type KafkaWriter struct {
+	httpClient *http.Client
+	endpoint   string
+}
+
+type kafkaRecord struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+func NewKafkaWriter(opts KafkaOptions) (*KafkaWriter, error) {
+	if _, err := url.ParseRequestURI(opts.RESTURL); err != nil {
+		return nil, fmt.Errorf("invalid kafka rest proxy url: %w", err)
+	}
+	if opts.Topic == "" {
+		return nil, fmt.Errorf("kafka topic is required")
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	return &KafkaWriter{
+		httpClient: &http.Client{Timeout: opts.Timeout},
+		endpoint:   strings.TrimRight(opts.RESTURL, "/") + "/topics/" + url.PathEscape(opts.Topic),
+	}, nil
+}
+
+func (w *KafkaWriter) Write(ctx context.Context, events []models.Envelope) error {
+	records := make([]kafkaRecord, len(events))
+	for i, e := range events {
+		records[i] = kafkaRecord{Key: e.Base.Signature, Value: e.Payload}
+	}
+	body, err := json.Marshal(map[string]interface{}{"records": records})
+	if err != nil {
+		return fmt.Errorf("marshal records: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+
+	// The proxy answers 200 even when single records fail.
+	var resp struct {
+		Offsets []struct {
+			ErrorCode *int   `json:"error_code"`
+			Error     string `json:"error"`
+		} `json:"offsets"`
+	}
+	if err := doWriteRequest(w.httpClient, req, "kafka produce", &resp); err != nil {
+		return err
+	}
+	for _, o := range resp.Offsets {
+		if o.ErrorCode != nil {
+			return fmt.Errorf("kafka produce failed with error %d: %s", *o.ErrorCode, o.Error)
+		}
+	}
+	return nil
+}
+
+func (w *KafkaWriter) Close(ctx context.Context) error {
+	return nil
+}
+
+// doWriteRequest sends req and decodes a successful response into out,
+// unless out is nil.
+func doWriteRequest(client *http.Client, req *http.Request, what string, out interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send %s request: %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s failed with status %d: %s", what, resp.StatusCode, body)
+	}
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode %s response: %w", what, err)
+	}
+	return nil
+}
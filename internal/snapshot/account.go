@@ -0,0 +1,142 @@
+package snapshot
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+const (
+	// CounterSize is the Anchor Counter account: discriminator, authority,
+	// count and bump.
+	CounterSize = 8 + 32 + 8 + 1
+	// UserAccountSize is the Anchor UserAccount account: discriminator,
+	// authority, points, created_at, updated_at and bump.
+	UserAccountSize = 8 + 32 + 8 + 8 + 8 + 1
+
+	userAccountSeed = "user_account"
+)
+
+var (
+	counterDiscriminator     = accountDiscriminator("Counter")
+	userAccountDiscriminator = accountDiscriminator("UserAccount")
+)
+
+func accountDiscriminator(name string) [8]byte {
+	hash := sha256.Sum256([]byte("account:" + name))
+	var d [8]byte
+	copy(d[:], hash[:8])
+	return d
+}
+
+// Account is an account as solana-test-validator loads it with --account
+// or --account-dir.
+type Account struct {
+	Address  solana.PublicKey
+	Owner    solana.PublicKey
+	Lamports uint64
+	Data     []byte
+}
+
+// RentExemptMinimum is the balance that makes an account of size bytes
+// rent exempt under the default rent parameters, matching
+// getMinimumBalanceForRentExemption on a fresh validator.
+func RentExemptMinimum(size int) uint64 {
+	const (
+		accountStorageOverhead = 128
+		lamportsPerByteYear    = 3480
+		exemptionYears         = 2
+	)
+	return uint64(accountStorageOverhead+size) * lamportsPerByteYear * exemptionYears
+}
+
+// CounterAccount renders c as an account of the counter program. Counters
+// are created from keypairs, so their bump is always zero.
+func CounterAccount(c Counter, counterProgram solana.PublicKey) Account {
+	data := make([]byte, 0, CounterSize)
+	data = append(data, counterDiscriminator[:]...)
+	data = append(data, c.Authority[:]...)
+	data = binary.LittleEndian.AppendUint64(data, c.Count)
+	data = append(data, 0)
+	return Account{
+		Address:  c.Address,
+		Owner:    counterProgram,
+		Lamports: RentExemptMinimum(CounterSize),
+		Data:     data,
+	}
+}
+
+// UserAccountAccount renders u as an account of the starter program. The
+// bump is derived from the authority, which must match the indexed address.
+func UserAccountAccount(u UserAccount, starterProgram solana.PublicKey) (Account, error) {
+	address, bump, err := solana.FindProgramAddress([][]byte{[]byte(userAccountSeed), u.Authority[:]}, starterProgram)
+	if err != nil {
+		return Account{}, fmt.Errorf("derive user account of %s: %w", u.Authority, err)
+	}
+	if !address.Equals(u.Address) {
+		return Account{}, fmt.Errorf("user account %s is not the PDA of authority %s", u.Address, u.Authority)
+	}
+
+	data := make([]byte, 0, UserAccountSize)
+	data = append(data, userAccountDiscriminator[:]...)
+	data = append(data, u.Authority[:]...)
+	data = binary.LittleEndian.AppendUint64(data, u.Points)
+	data = binary.LittleEndian.AppendUint64(data, uint64(u.CreatedAt))
+	data = binary.LittleEndian.AppendUint64(data, uint64(u.UpdatedAt))
+	data = append(data, bump)
+	return Account{
+		Address:  u.Address,
+		Owner:    starterProgram,
+		Lamports: RentExemptMinimum(UserAccountSize),
+		Data:     data,
+	}, nil
+}
+
+type accountJSON struct {
+	Pubkey  string `json:"pubkey"`
+	Account struct {
+		Lamports   uint64    `json:"lamports"`
+		Data       [2]string `json:"data"`
+		Owner      string    `json:"owner"`
+		Executable bool      `json:"executable"`
+		RentEpoch  uint64    `json:"rentEpoch"`
+		Space      int       `json:"space"`
+	} `json:"account"`
+}
+
+// MarshalJSON encodes the account in the format of `solana account
+// --output json`, which solana-test-validator reads.
+func (a Account) MarshalJSON() ([]byte, error) {
+	var out accountJSON
+	out.Pubkey = a.Address.String()
+	out.Account.Lamports = a.Lamports
+	out.Account.Data = [2]string{base64.StdEncoding.EncodeToString(a.Data), "base64"}
+	out.Account.Owner = a.Owner.String()
+	out.Account.Space = len(a.Data)
+	return json.Marshal(out)
+}
+
+// WriteDir writes each account to dir as <address>.json, ready for
+// `solana-test-validator --account-dir dir`.
+func WriteDir(dir string, accounts []Account) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create %s: %w", dir, err)
+	}
+	for _, a := range accounts {
+		data, err := json.MarshalIndent(a, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode account %s: %w", a.Address, err)
+		}
+		path := filepath.Join(dir, a.Address.String()+".json")
+		if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+			return fmt.Errorf("write %s: %w", path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+// Package snapshot rebuilds the current state of counters and user accounts
+// from indexed events and renders it as Solana account data, so a local
+// validator can start from the indexed state.
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+// EventTypes are the events the projections are built from.
+var EventTypes = []models.EventType{
+	models.EventTypeCounterInitialized,
+	models.EventTypeCounterIncremented,
+	models.EventTypeCounterDecremented,
+	models.EventTypeCounterAdded,
+	models.EventTypeCounterReset,
+	models.EventTypeCounterPaymentReceived,
+	models.EventTypeUserAccountCreated,
+	models.EventTypeUserAccountUpdated,
+}
+
+// Counter is the state of a counter account as of Slot. Program is the
+// counter deployment owning the account.
+type Counter struct {
+	Address   solana.PublicKey
+	Program   solana.PublicKey
+	Authority solana.PublicKey
+	Count     uint64
+	Slot      uint64
+}
+
+// UserAccount is the state of a user account as of Slot. CreatedAt and
+// UpdatedAt are unix timestamps as the program stores them.
+type UserAccount struct {
+	Address   solana.PublicKey
+	Authority solana.PublicKey
+	Points    uint64
+	CreatedAt int64
+	UpdatedAt int64
+	Slot      uint64
+}
+
+// Projections folds events into the latest state of every counter and user
+// account. Events may arrive in any order: an event older than the state
+// it would change is ignored, except for the fields only its kind carries.
+type Projections struct {
+	counters map[solana.PublicKey]*Counter
+	users    map[solana.PublicKey]*UserAccount
+}
+
+func NewProjections() *Projections {
+	return &Projections{
+		counters: make(map[solana.PublicKey]*Counter),
+		users:    make(map[solana.PublicKey]*UserAccount),
+	}
+}
+
+// eventFields are the fields of the projected events. Stored documents are
+// decoded field by field since their timestamps are extended JSON.
+type eventFields struct {
+	Counter      solana.PublicKey `json:"counter"`
+	User         solana.PublicKey `json:"user"`
+	Authority    solana.PublicKey `json:"authority"`
+	InitialCount uint64           `json:"initial_count"`
+	NewValue     uint64           `json:"new_value"`
+	NewCount     uint64           `json:"new_count"`
+	NewPoints    uint64           `json:"new_points"`
+	Timestamp    int64            `json:"timestamp"`
+}
+
+// Apply folds one stored event into the projections. Events of other types
+// are ignored.
+func (p *Projections) Apply(event repository.ExportedEvent) error {
+	var f eventFields
+	switch event.EventType {
+	case models.EventTypeCounterInitialized, models.EventTypeCounterIncremented,
+		models.EventTypeCounterDecremented, models.EventTypeCounterAdded,
+		models.EventTypeCounterReset, models.EventTypeCounterPaymentReceived,
+		models.EventTypeUserAccountCreated, models.EventTypeUserAccountUpdated:
+		if err := json.Unmarshal(event.Document, &f); err != nil {
+			return fmt.Errorf("decode %s %s: %w", event.EventType, event.Signature, err)
+		}
+	default:
+		return nil
+	}
+
+	switch event.EventType {
+	case models.EventTypeCounterInitialized:
+		c := p.counter(f.Counter, event.ProgramID)
+		c.Authority = f.Authority
+		p.setCount(c, event.Slot, f.InitialCount)
+	case models.EventTypeCounterIncremented, models.EventTypeCounterDecremented, models.EventTypeCounterAdded:
+		p.setCount(p.counter(f.Counter, event.ProgramID), event.Slot, f.NewValue)
+	case models.EventTypeCounterReset:
+		c := p.counter(f.Counter, event.ProgramID)
+		if c.Authority.IsZero() {
+			c.Authority = f.Authority
+		}
+		p.setCount(c, event.Slot, 0)
+	case models.EventTypeCounterPaymentReceived:
+		p.setCount(p.counter(f.Counter, event.ProgramID), event.Slot, f.NewCount)
+	case models.EventTypeUserAccountCreated:
+		u := p.user(f.User)
+		u.Authority = f.Authority
+		u.CreatedAt = f.Timestamp
+		if event.Slot >= u.Slot {
+			u.Slot = event.Slot
+			u.UpdatedAt = f.Timestamp
+		}
+	case models.EventTypeUserAccountUpdated:
+		u := p.user(f.User)
+		if event.Slot >= u.Slot {
+			u.Slot = event.Slot
+			u.Points = f.NewPoints
+			u.UpdatedAt = f.Timestamp
+		}
+	}
+	return nil
+}
+
+func (p *Projections) counter(address, program solana.PublicKey) *Counter {
+	c, ok := p.counters[address]
+	if !ok {
+		c = &Counter{Address: address}
+		p.counters[address] = c
+	}
+	if c.Program.IsZero() {
+		c.Program = program
+	}
+	return c
+}
+
+func (p *Projections) setCount(c *Counter, slot, count uint64) {
+	if slot >= c.Slot {
+		c.Slot = slot
+		c.Count = count
+	}
+}
+
+func (p *Projections) user(address solana.PublicKey) *UserAccount {
+	u, ok := p.users[address]
+	if !ok {
+		u = &UserAccount{Address: address}
+		p.users[address] = u
+	}
+	return u
+}
+
+// Counters returns the counters ordered by address. Counters whose
+// initialization was not indexed have no authority.
+func (p *Projections) Counters() []Counter {
+	out := make([]Counter, 0, len(p.counters))
+	for _, c := range p.counters {
+		out = append(out, *c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Address.String() < out[j].Address.String() })
+	return out
+}
+
+// UserAccounts returns the user accounts ordered by address.
+func (p *Projections) UserAccounts() []UserAccount {
+	out := make([]UserAccount, 0, len(p.users))
+	for _, u := range p.users {
+		out = append(out, *u)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Address.String() < out[j].Address.String() })
+	return out
+}
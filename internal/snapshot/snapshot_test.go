@@ -0,0 +1,184 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+)
+
+var (
+	starterProgram = solana.MustPublicKeyFromBase58("gARh1g6reuvsAHB7DXqiuYzzyiJeoiJmtmCpV8Y5uWC")
+	counterProgram = solana.MustPublicKeyFromBase58("CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc")
+	authority      = solana.MustPublicKeyFromBase58("9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM")
+	counterAddress = solana.MustPublicKeyFromBase58("4Nd1mBQtrMJVYVfKf2PJy9NZUZdTAsp7D4xWLs4gDB4T")
+)
+
+func event(t *testing.T, eventType models.EventType, slot uint64, doc map[string]interface{}) repository.ExportedEvent {
+	t.Helper()
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repository.ExportedEvent{
+		BaseEvent: models.BaseEvent{EventType: eventType, Slot: slot, ProgramID: counterProgram},
+		Document:  data,
+	}
+}
+
+func TestDiscriminators(t *testing.T) {
+	tests := []struct {
+		name string
+		got  [8]byte
+		want [8]byte
+	}{
+		{"Counter", counterDiscriminator, [8]byte{255, 176, 4, 245, 188, 253, 124, 25}},
+		{"UserAccount", userAccountDiscriminator, [8]byte{211, 33, 136, 16, 186, 110, 242, 127}},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s discriminator = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestProjections_Counter(t *testing.T) {
+	counter := counterAddress.String()
+	tests := []struct {
+		name   string
+		events []repository.ExportedEvent
+		want   uint64
+	}{
+		{
+			name: "in order",
+			events: []repository.ExportedEvent{
+				event(t, models.EventTypeCounterInitialized, 10, map[string]interface{}{"counter": counter, "authority": authority.String(), "initial_count": 5}),
+				event(t, models.EventTypeCounterIncremented, 11, map[string]interface{}{"counter": counter, "new_value": 6}),
+				event(t, models.EventTypeCounterAdded, 12, map[string]interface{}{"counter": counter, "new_value": 16}),
+			},
+			want: 16,
+		},
+		{
+			name: "out of order",
+			events: []repository.ExportedEvent{
+				event(t, models.EventTypeCounterPaymentReceived, 12, map[string]interface{}{"counter": counter, "new_count": 7}),
+				event(t, models.EventTypeCounterInitialized, 10, map[string]interface{}{"counter": counter, "authority": authority.String(), "initial_count": 5}),
+				event(t, models.EventTypeCounterDecremented, 11, map[string]interface{}{"counter": counter, "new_value": 4}),
+			},
+			want: 7,
+		},
+		{
+			name: "reset",
+			events: []repository.ExportedEvent{
+				event(t, models.EventTypeCounterInitialized, 10, map[string]interface{}{"counter": counter, "authority": authority.String(), "initial_count": 5}),
+				event(t, models.EventTypeCounterReset, 11, map[string]interface{}{"counter": counter, "authority": authority.String()}),
+			},
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProjections()
+			for _, e := range tt.events {
+				if err := p.Apply(e); err != nil {
+					t.Fatalf("Apply() error = %v", err)
+				}
+			}
+			counters := p.Counters()
+			if len(counters) != 1 {
+				t.Fatalf("Counters() = %d counters, want 1", len(counters))
+			}
+			if counters[0].Count != tt.want {
+				t.Errorf("Count = %d, want %d", counters[0].Count, tt.want)
+			}
+			if !counters[0].Authority.Equals(authority) {
+				t.Errorf("Authority = %s, want %s", counters[0].Authority, authority)
+			}
+			if !counters[0].Program.Equals(counterProgram) {
+				t.Errorf("Program = %s, want %s", counters[0].Program, counterProgram)
+			}
+		})
+	}
+}
+
+func TestCounterAccount(t *testing.T) {
+	account := CounterAccount(Counter{Address: counterAddress, Authority: authority, Count: 42}, counterProgram)
+
+	if len(account.Data) != CounterSize {
+		t.Fatalf("len(Data) = %d, want %d", len(account.Data), CounterSize)
+	}
+	if !bytes.Equal(account.Data[8:40], authority[:]) {
+		t.Errorf("authority = %x, want %x", account.Data[8:40], authority[:])
+	}
+	if got := binary.LittleEndian.Uint64(account.Data[40:48]); got != 42 {
+		t.Errorf("count = %d, want 42", got)
+	}
+	if !account.Owner.Equals(counterProgram) {
+		t.Errorf("Owner = %s, want %s", account.Owner, counterProgram)
+	}
+	if account.Lamports != 1231920 {
+		t.Errorf("Lamports = %d, want 1231920", account.Lamports)
+	}
+}
+
+func TestUserAccountAccount(t *testing.T) {
+	address, bump, err := solana.FindProgramAddress([][]byte{[]byte("user_account"), authority[:]}, starterProgram)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	account, err := UserAccountAccount(UserAccount{Address: address, Authority: authority, Points: 300, CreatedAt: 1700000000, UpdatedAt: 1700000100}, starterProgram)
+	if err != nil {
+		t.Fatalf("UserAccountAccount() error = %v", err)
+	}
+	if len(account.Data) != UserAccountSize {
+		t.Fatalf("len(Data) = %d, want %d", len(account.Data), UserAccountSize)
+	}
+	if got := binary.LittleEndian.Uint64(account.Data[40:48]); got != 300 {
+		t.Errorf("points = %d, want 300", got)
+	}
+	if got := int64(binary.LittleEndian.Uint64(account.Data[56:64])); got != 1700000100 {
+		t.Errorf("updated_at = %d, want 1700000100", got)
+	}
+	if got := account.Data[UserAccountSize-1]; got != bump {
+		t.Errorf("bump = %d, want %d", got, bump)
+	}
+
+	if _, err := UserAccountAccount(UserAccount{Address: counterAddress, Authority: authority}, starterProgram); err == nil {
+		t.Error("UserAccountAccount() with a non-PDA address error = nil, want error")
+	}
+}
+
+func TestWriteDir(t *testing.T) {
+	dir := t.TempDir()
+	account := CounterAccount(Counter{Address: counterAddress, Authority: authority, Count: 1}, counterProgram)
+	if err := WriteDir(dir, []Account{account}); err != nil {
+		t.Fatalf("WriteDir() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, counterAddress.String()+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got accountJSON
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Pubkey != counterAddress.String() || got.Account.Owner != counterProgram.String() {
+		t.Errorf("pubkey, owner = %s, %s, want %s, %s", got.Pubkey, got.Account.Owner, counterAddress, counterProgram)
+	}
+	if got.Account.Data != [2]string{base64.StdEncoding.EncodeToString(account.Data), "base64"} {
+		t.Errorf("data = %v, want base64 account data", got.Account.Data)
+	}
+	if got.Account.Space != CounterSize {
+		t.Errorf("space = %d, want %d", got.Account.Space, CounterSize)
+	}
+}
@@ -0,0 +1,250 @@
+// Package spool is a bounded FIFO of records on local disk, used to keep
+// indexing while the database is unreachable and replay what was buffered
+// once it is back.
+package spool
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// segmentSize is the size at which Append starts a new segment file. Drain
+// reads one segment at a time, so it also bounds Drain's memory use.
+const segmentSize = 4 << 20
+
+type segment struct {
+	seq  uint64
+	size int64
+}
+
+// Spool stores records as lines in numbered segment files. Records appended
+// before a restart are found again by Open.
+type Spool struct {
+	dir      string
+	maxBytes int64
+
+	mu       sync.Mutex
+	segments []segment
+	bytes    int64
+	records  int
+	file     *os.File
+	nextSeq  uint64
+}
+
+// Open opens the spool in dir, creating the directory if needed. Full
+// reports true once the records take maxBytes or more.
+func Open(dir string, maxBytes int64) (*Spool, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create spool directory: %w", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read spool directory: %w", err)
+	}
+
+	s := &Spool{dir: dir, maxBytes: maxBytes, nextSeq: 1}
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".jsonl")
+		if !ok {
+			continue
+		}
+		seq, err := strconv.ParseUint(name, 10, 64)
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read spool segment: %w", err)
+		}
+		s.segments = append(s.segments, segment{seq: seq, size: int64(len(data))})
+		s.bytes += int64(len(data))
+		s.records += countRecords(data)
+		s.nextSeq = max(s.nextSeq, seq+1)
+	}
+	sort.Slice(s.segments, func(i, j int) bool { return s.segments[i].seq < s.segments[j].seq })
+	return s, nil
+}
+
+func (s *Spool) path(seq uint64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%020d.jsonl", seq))
+}
+
+// Append adds a record, which must not contain newlines. It does not check
+// the limit: callers stop appending once Full reports true, so the spool
+// can exceed maxBytes by what was appended since.
+func (s *Spool) Append(record []byte) error {
+	if bytes.IndexByte(record, '\n') >= 0 {
+		return fmt.Errorf("spool record contains a newline")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil || s.segments[len(s.segments)-1].size >= segmentSize {
+		if s.file != nil {
+			s.file.Close()
+		}
+		f, err := os.OpenFile(s.path(s.nextSeq), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			s.file = nil
+			return fmt.Errorf("create spool segment: %w", err)
+		}
+		s.file = f
+		s.segments = append(s.segments, segment{seq: s.nextSeq})
+		s.nextSeq++
+	}
+
+	n, err := s.file.Write(append(record, '\n'))
+	s.segments[len(s.segments)-1].size += int64(n)
+	s.bytes += int64(n)
+	if err != nil {
+		return fmt.Errorf("write spool record: %w", err)
+	}
+	s.records++
+	return nil
+}
+
+// Len returns the number of buffered records.
+func (s *Spool) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.records
+}
+
+// Bytes returns the size of the buffered records.
+func (s *Spool) Bytes() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.bytes
+}
+
+// Stats is a snapshot of a spool's size.
+type Stats struct {
+	Records  int   `json:"records"`
+	Bytes    int64 `json:"bytes"`
+	MaxBytes int64 `json:"max_bytes"`
+}
+
+func (s *Spool) Stats() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return Stats{Records: s.records, Bytes: s.bytes, MaxBytes: s.maxBytes}
+}
+
+// Full reports whether the buffered records reached the size limit.
+func (s *Spool) Full() bool {
+	return s.Bytes() >= s.maxBytes
+}
+
+// Drain passes the buffered records to fn, oldest first, and removes each
+// segment once fn accepted all of its records. It stops at the first error
+// fn returns, keeping that record and the ones after it, and returns how
+// many records were drained.
+func (s *Spool) Drain(ctx context.Context, fn func(ctx context.Context, record []byte) error) (int, error) {
+	var drained int
+	for {
+		if err := ctx.Err(); err != nil {
+			return drained, err
+		}
+
+		s.mu.Lock()
+		if len(s.segments) == 0 {
+			s.mu.Unlock()
+			return drained, nil
+		}
+		seg := s.segments[0]
+		if len(s.segments) == 1 && s.file != nil {
+			// Appends move on to a new segment while this one is drained.
+			s.file.Close()
+			s.file = nil
+		}
+		s.mu.Unlock()
+
+		path := s.path(seg.seq)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return drained, fmt.Errorf("read spool segment: %w", err)
+		}
+
+		records := bytes.SplitAfter(data, []byte{'\n'})
+		for n, record := range records {
+			if len(record) == 0 {
+				continue
+			}
+			if err := fn(ctx, bytes.TrimSuffix(record, []byte{'\n'})); err != nil {
+				rest := bytes.Join(records[n:], nil)
+				if writeErr := s.rewrite(seg, rest, countRecords(bytes.Join(records[:n], nil))); writeErr != nil {
+					return drained, writeErr
+				}
+				return drained, err
+			}
+			drained++
+		}
+
+		if err := os.Remove(path); err != nil {
+			return drained, fmt.Errorf("remove spool segment: %w", err)
+		}
+		s.mu.Lock()
+		s.segments = s.segments[1:]
+		s.bytes -= seg.size
+		s.records -= countRecords(data)
+		s.mu.Unlock()
+	}
+}
+
+func countRecords(data []byte) int {
+	var n int
+	for _, line := range bytes.Split(data, []byte{'\n'}) {
+		if len(line) > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// rewrite replaces the first segment with its undrained records rest, after
+// done records were drained from it.
+func (s *Spool) rewrite(seg segment, rest []byte, done int) error {
+	if done == 0 {
+		return nil
+	}
+	tmp := s.path(seg.seq) + ".tmp"
+	if err := os.WriteFile(tmp, rest, 0o644); err != nil {
+		return fmt.Errorf("rewrite spool segment: %w", err)
+	}
+	if err := os.Rename(tmp, s.path(seg.seq)); err != nil {
+		return fmt.Errorf("rewrite spool segment: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.segments[0].size = int64(len(rest))
+	s.bytes -= seg.size - int64(len(rest))
+	s.records -= done
+	return nil
+}
+
+// Dir returns the directory the spool is stored in.
+func (s *Spool) Dir() string {
+	return s.dir
+}
+
+// Close closes the segment being appended to. Buffered records stay on disk
+// for the next Open.
+func (s *Spool) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
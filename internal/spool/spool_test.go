@@ -0,0 +1,116 @@
+package spool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func appendRecords(t *testing.T, s *Spool, from, to int) {
+	t.Helper()
+	for n := from; n < to; n++ {
+		if err := s.Append([]byte(fmt.Sprintf(`{"n":%d}`, n))); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+}
+
+func TestSpool_Drain(t *testing.T) {
+	errDown := errors.New("database down")
+	tests := []struct {
+		name     string
+		failAt   int
+		wantN    int
+		wantLeft int
+	}{
+		{name: "all records", failAt: -1, wantN: 5, wantLeft: 0},
+		{name: "stops at failure", failAt: 3, wantN: 3, wantLeft: 2},
+		{name: "first record fails", failAt: 0, wantN: 0, wantLeft: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Open(t.TempDir(), 1<<20)
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+			appendRecords(t, s, 0, 5)
+
+			var got []string
+			n, err := s.Drain(context.Background(), func(ctx context.Context, record []byte) error {
+				if len(got) == tt.failAt {
+					return errDown
+				}
+				got = append(got, string(record))
+				return nil
+			})
+			if tt.failAt >= 0 && !errors.Is(err, errDown) {
+				t.Errorf("Drain() error = %v, want %v", err, errDown)
+			}
+			if n != tt.wantN {
+				t.Errorf("Drain() = %d, want %d", n, tt.wantN)
+			}
+			if s.Len() != tt.wantLeft {
+				t.Errorf("Len() = %d, want %d", s.Len(), tt.wantLeft)
+			}
+
+			// The rest is drained in order on the next attempt.
+			if _, err := s.Drain(context.Background(), func(ctx context.Context, record []byte) error {
+				got = append(got, string(record))
+				return nil
+			}); err != nil {
+				t.Fatalf("Drain() error = %v", err)
+			}
+			for n, record := range got {
+				if want := fmt.Sprintf(`{"n":%d}`, n); record != want {
+					t.Errorf("record %d = %s, want %s", n, record, want)
+				}
+			}
+			if s.Len() != 0 || s.Bytes() != 0 {
+				t.Errorf("Len(), Bytes() = %d, %d, want 0, 0", s.Len(), s.Bytes())
+			}
+		})
+	}
+}
+
+func TestSpool_Reopen(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(dir, 40)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	appendRecords(t, s, 0, 3)
+	if s.Full() {
+		t.Error("Full() = true after 24 of 40 bytes")
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	s, err = Open(dir, 40)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if s.Len() != 3 || s.Bytes() != 24 {
+		t.Errorf("Len(), Bytes() after reopening = %d, %d, want 3, 24", s.Len(), s.Bytes())
+	}
+	appendRecords(t, s, 3, 5)
+	if !s.Full() {
+		t.Error("Full() = false after 40 of 40 bytes")
+	}
+
+	var got int
+	if _, err := s.Drain(context.Background(), func(ctx context.Context, record []byte) error {
+		if want := fmt.Sprintf(`{"n":%d}`, got); string(record) != want {
+			t.Errorf("record %d = %s, want %s", got, record, want)
+		}
+		got++
+		return nil
+	}); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+	if got != 5 {
+		t.Errorf("drained %d records, want 5", got)
+	}
+}
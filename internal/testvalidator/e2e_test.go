@@ -0,0 +1,140 @@
+//go:build e2e
+
+package testvalidator_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/config"
+	"github.com/lugondev/go-indexer-solana-starter/internal/indexer"
+	"github.com/lugondev/go-indexer-solana-starter/internal/repository"
+	"github.com/lugondev/go-indexer-solana-starter/internal/testvalidator"
+)
+
+// TestEndToEnd deploys the programs to a local validator, sends the
+// scripted transactions and checks that the indexer stores their events.
+//
+//	STARTER_PROGRAM_SO=../starter_program/target/deploy/starter_program.so \
+//	COUNTER_PROGRAM_SO=../starter_program/target/deploy/counter_program.so \
+//	go test -tags e2e ./internal/testvalidator
+//
+// With TEST_VALIDATOR_URL set it uses that running validator instead, which
+// must have both programs deployed.
+func TestEndToEnd(t *testing.T) {
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("config.Load() error = %v", err)
+	}
+	starter := testvalidator.Starter{
+		ProgramID:        solana.MustPublicKeyFromBase58(cfg.StarterProgramID),
+		CounterProgramID: solana.MustPublicKeyFromBase58(cfg.CounterProgramID),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	v := startValidator(ctx, t, starter)
+	defer v.Close()
+
+	payer, err := solana.NewRandomPrivateKey()
+	if err != nil {
+		t.Fatalf("NewRandomPrivateKey() error = %v", err)
+	}
+	script, err := testvalidator.RunScript(ctx, v, starter, payer)
+	if err != nil {
+		t.Fatalf("RunScript() error = %v (validator log: %s)", err, v.LogPath())
+	}
+
+	cfg.SolanaRPCURL = v.RPCURL
+	cfg.StartSlot = 0
+	cfg.PollInterval = 200 * time.Millisecond
+	cfg.DatabaseName = fmt.Sprintf("solana_indexer_e2e_%d", time.Now().UnixNano())
+	t.Logf("indexing into database %s", cfg.DatabaseName)
+
+	idx, err := indexer.New(cfg)
+	if err != nil {
+		t.Fatalf("indexer.New() error = %v", err)
+	}
+	indexCtx, stopIndexer := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() { done <- idx.Start(indexCtx) }()
+	defer func() {
+		stopIndexer()
+		<-done
+		idx.Shutdown(context.Background())
+	}()
+
+	repo, err := indexer.NewRepository(cfg)
+	if err != nil {
+		t.Fatalf("NewRepository() error = %v", err)
+	}
+	defer repo.Close(context.Background())
+
+	waitForEvents(ctx, t, repo, script)
+}
+
+func startValidator(ctx context.Context, t *testing.T, starter testvalidator.Starter) *testvalidator.Validator {
+	t.Helper()
+
+	if url := os.Getenv("TEST_VALIDATOR_URL"); url != "" {
+		v, err := testvalidator.Connect(ctx, url, starter.ProgramID, starter.CounterProgramID)
+		if err != nil {
+			t.Fatalf("Connect() error = %v", err)
+		}
+		return v
+	}
+
+	starterSO, counterSO := os.Getenv("STARTER_PROGRAM_SO"), os.Getenv("COUNTER_PROGRAM_SO")
+	if starterSO == "" || counterSO == "" {
+		t.Skip("TEST_VALIDATOR_URL or STARTER_PROGRAM_SO and COUNTER_PROGRAM_SO are required")
+	}
+	v, err := testvalidator.Start(ctx, testvalidator.Options{
+		Programs: []testvalidator.Program{
+			{ID: starter.ProgramID, Path: starterSO},
+			{ID: starter.CounterProgramID, Path: counterSO},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	return v
+}
+
+func waitForEvents(ctx context.Context, t *testing.T, repo repository.Repository, script *testvalidator.ScriptResult) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Minute)
+	for {
+		missing := make(map[string]string)
+		for eventType, want := range script.Expected {
+			page, err := repo.GetEventsByType(ctx, eventType, repository.PageOptions{Limit: want + 10})
+			if err != nil {
+				t.Fatalf("GetEventsByType(%s) error = %v", eventType, err)
+			}
+			if len(page.Events) < want {
+				missing[string(eventType)] = fmt.Sprintf("%d of %d", len(page.Events), want)
+			}
+		}
+		for _, sig := range script.Signatures {
+			event, err := repo.GetEventBySignature(ctx, sig.String())
+			if err != nil {
+				t.Fatalf("GetEventBySignature(%s) error = %v", sig, err)
+			}
+			if event == nil {
+				missing[sig.String()] = "no event"
+			}
+		}
+		if len(missing) == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("events not indexed: %v", missing)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
@@ -0,0 +1,150 @@
+package testvalidator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+// userAccountSeed is the PDA seed of the starter program's user accounts.
+const userAccountSeed = "user_account"
+
+// Starter builds starter program instructions. The counter instructions go
+// through the starter program, which calls the counter program by CPI.
+type Starter struct {
+	ProgramID        solana.PublicKey
+	CounterProgramID solana.PublicKey
+}
+
+// InitializeCounter creates counter, which must sign the transaction.
+func (s Starter) InitializeCounter(counter, authority solana.PublicKey) solana.Instruction {
+	return solana.NewInstruction(s.ProgramID, solana.AccountMetaSlice{
+		solana.Meta(counter).WRITE().SIGNER(),
+		solana.Meta(authority).WRITE().SIGNER(),
+		solana.Meta(s.CounterProgramID),
+		solana.Meta(solana.SystemProgramID),
+	}, discriminator("initialize_counter"))
+}
+
+func (s Starter) IncrementCounter(counter, authority solana.PublicKey) solana.Instruction {
+	return solana.NewInstruction(s.ProgramID, solana.AccountMetaSlice{
+		solana.Meta(counter).WRITE(),
+		solana.Meta(authority).SIGNER(),
+		solana.Meta(s.CounterProgramID),
+	}, discriminator("increment_counter"))
+}
+
+func (s Starter) AddToCounter(counter, authority solana.PublicKey, value uint64) solana.Instruction {
+	return solana.NewInstruction(s.ProgramID, solana.AccountMetaSlice{
+		solana.Meta(counter).WRITE(),
+		solana.Meta(authority).SIGNER(),
+		solana.Meta(s.CounterProgramID),
+	}, binary.LittleEndian.AppendUint64(discriminator("add_to_counter"), value))
+}
+
+func (s Starter) CreateUserAccount(authority solana.PublicKey) (solana.Instruction, error) {
+	userAccount, err := s.UserAccount(authority)
+	if err != nil {
+		return nil, err
+	}
+	return solana.NewInstruction(s.ProgramID, solana.AccountMetaSlice{
+		solana.Meta(userAccount).WRITE(),
+		solana.Meta(authority).WRITE().SIGNER(),
+		solana.Meta(solana.SystemProgramID),
+	}, discriminator("create_user_account")), nil
+}
+
+func (s Starter) UpdateUserAccount(authority solana.PublicKey, points uint64) (solana.Instruction, error) {
+	userAccount, err := s.UserAccount(authority)
+	if err != nil {
+		return nil, err
+	}
+	return solana.NewInstruction(s.ProgramID, solana.AccountMetaSlice{
+		solana.Meta(userAccount).WRITE(),
+		solana.Meta(authority).SIGNER(),
+	}, binary.LittleEndian.AppendUint64(discriminator("update_user_account"), points)), nil
+}
+
+// UserAccount derives the user account PDA of authority.
+func (s Starter) UserAccount(authority solana.PublicKey) (solana.PublicKey, error) {
+	addr, _, err := solana.FindProgramAddress([][]byte{[]byte(userAccountSeed), authority[:]}, s.ProgramID)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("derive user account: %w", err)
+	}
+	return addr, nil
+}
+
+// discriminator is the Anchor instruction discriminator: the first 8 bytes
+// of sha256("global:<name>").
+func discriminator(name string) []byte {
+	sum := sha256.Sum256([]byte("global:" + name))
+	return sum[:8]
+}
+
+// ScriptResult records what RunScript sent and the events the indexer
+// should store for it.
+type ScriptResult struct {
+	Counter    solana.PublicKey
+	Signatures []solana.Signature
+	Expected   map[models.EventType]int
+}
+
+// RunScript funds payer and sends a fixed sequence of transactions that
+// covers the counter and user account events: it initializes a counter,
+// increments it twice, adds 5, then creates and updates a user account.
+func RunScript(ctx context.Context, v *Validator, starter Starter, payer solana.PrivateKey) (*ScriptResult, error) {
+	if err := v.Airdrop(ctx, payer.PublicKey(), 10*solana.LAMPORTS_PER_SOL); err != nil {
+		return nil, fmt.Errorf("fund payer: %w", err)
+	}
+
+	counter, err := solana.NewRandomPrivateKey()
+	if err != nil {
+		return nil, fmt.Errorf("generate counter key: %w", err)
+	}
+	authority := payer.PublicKey()
+
+	createUser, err := starter.CreateUserAccount(authority)
+	if err != nil {
+		return nil, err
+	}
+	updateUser, err := starter.UpdateUserAccount(authority, 42)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := []struct {
+		name        string
+		instruction solana.Instruction
+		signers     []solana.PrivateKey
+	}{
+		{"initialize_counter", starter.InitializeCounter(counter.PublicKey(), authority), []solana.PrivateKey{counter}},
+		{"increment_counter", starter.IncrementCounter(counter.PublicKey(), authority), nil},
+		{"increment_counter", starter.IncrementCounter(counter.PublicKey(), authority), nil},
+		{"add_to_counter", starter.AddToCounter(counter.PublicKey(), authority, 5), nil},
+		{"create_user_account", createUser, nil},
+		{"update_user_account", updateUser, nil},
+	}
+
+	result := &ScriptResult{
+		Counter: counter.PublicKey(),
+		Expected: map[models.EventType]int{
+			models.EventTypeCounterInitialized: 1,
+			models.EventTypeCounterIncremented: 2,
+			models.EventTypeCounterAdded:       1,
+			models.EventTypeUserAccountCreated: 1,
+			models.EventTypeUserAccountUpdated: 1,
+		},
+	}
+	for _, step := range steps {
+		sig, err := v.Send(ctx, []solana.Instruction{step.instruction}, payer, step.signers...)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", step.name, err)
+		}
+		result.Signatures = append(result.Signatures, sig)
+	}
+	return result, nil
+}
@@ -0,0 +1,26 @@
+package testvalidator
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDiscriminator(t *testing.T) {
+	// Values from idl/starter_program.json.
+	tests := []struct {
+		name string
+		want []byte
+	}{
+		{"initialize_counter", []byte{67, 89, 100, 87, 231, 172, 35, 124}},
+		{"increment_counter", []byte{16, 125, 2, 171, 73, 24, 207, 229}},
+		{"add_to_counter", []byte{225, 240, 2, 99, 160, 40, 215, 27}},
+		{"create_user_account", []byte{146, 68, 100, 69, 63, 46, 182, 199}},
+		{"update_user_account", []byte{147, 83, 243, 122, 110, 128, 92, 33}},
+	}
+
+	for _, tt := range tests {
+		if got := discriminator(tt.name); !bytes.Equal(got, tt.want) {
+			t.Errorf("discriminator(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
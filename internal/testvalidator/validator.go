@@ -0,0 +1,263 @@
+// Package testvalidator runs solana-test-validator with the starter and
+// counter programs loaded, or connects to one already running, for
+// end-to-end tests against a real ledger.
+package testvalidator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// Program is a compiled program (the .so from `anchor build`) loaded into
+// the genesis block under ID.
+type Program struct {
+	ID   solana.PublicKey
+	Path string
+}
+
+type Options struct {
+	// Binary defaults to solana-test-validator on the PATH.
+	Binary   string
+	Programs []Program
+	// LedgerDir defaults to a temporary directory removed by Close.
+	LedgerDir string
+	// AccountDir, if set, preloads the account JSON files in it, such as
+	// those written by `indexer snapshot`.
+	AccountDir string
+	// RPCPort defaults to 8899. The faucet and gossip ports keep the
+	// validator's defaults, so only one validator can run at a time.
+	RPCPort      int
+	StartTimeout time.Duration
+}
+
+// Validator is a running solana-test-validator.
+type Validator struct {
+	RPCURL string
+	Client *rpc.Client
+
+	cmd          *exec.Cmd
+	exited       chan struct{}
+	waitErr      error
+	ledgerDir    string
+	removeLedger bool
+}
+
+// Start launches a fresh validator with the given programs deployed and
+// waits until its RPC endpoint reports healthy.
+func Start(ctx context.Context, opts Options) (*Validator, error) {
+	if opts.Binary == "" {
+		opts.Binary = "solana-test-validator"
+	}
+	if opts.RPCPort == 0 {
+		opts.RPCPort = 8899
+	}
+	if opts.StartTimeout == 0 {
+		opts.StartTimeout = time.Minute
+	}
+
+	binary, err := exec.LookPath(opts.Binary)
+	if err != nil {
+		return nil, fmt.Errorf("find %s: %w", opts.Binary, err)
+	}
+
+	v := &Validator{
+		RPCURL:    fmt.Sprintf("http://127.0.0.1:%d", opts.RPCPort),
+		exited:    make(chan struct{}),
+		ledgerDir: opts.LedgerDir,
+	}
+	if v.ledgerDir == "" {
+		if v.ledgerDir, err = os.MkdirTemp("", "test-ledger-"); err != nil {
+			return nil, fmt.Errorf("create ledger directory: %w", err)
+		}
+		v.removeLedger = true
+	}
+
+	args := []string{"--reset", "--quiet", "--ledger", v.ledgerDir, "--rpc-port", strconv.Itoa(opts.RPCPort)}
+	for _, p := range opts.Programs {
+		if _, err := os.Stat(p.Path); err != nil {
+			v.cleanup()
+			return nil, fmt.Errorf("program %s: %w", p.ID, err)
+		}
+		args = append(args, "--bpf-program", p.ID.String(), p.Path)
+	}
+	if opts.AccountDir != "" {
+		args = append(args, "--account-dir", opts.AccountDir)
+	}
+
+	// Not tied to ctx: Close stops the validator gracefully instead.
+	v.cmd = exec.Command(binary, args...)
+	if err := v.cmd.Start(); err != nil {
+		v.cleanup()
+		return nil, fmt.Errorf("start %s: %w", opts.Binary, err)
+	}
+	go func() {
+		v.waitErr = v.cmd.Wait()
+		close(v.exited)
+	}()
+
+	v.Client = rpc.New(v.RPCURL)
+	if err := v.waitReady(ctx, opts.StartTimeout); err != nil {
+		v.Close()
+		return nil, err
+	}
+	return v, nil
+}
+
+// Connect uses a validator that is already running at rpcURL, e.g. one
+// started by `anchor localnet`, after checking that it is healthy and that
+// every program is deployed. Close leaves it running.
+func Connect(ctx context.Context, rpcURL string, programs ...solana.PublicKey) (*Validator, error) {
+	v := &Validator{
+		RPCURL: rpcURL,
+		Client: rpc.New(rpcURL),
+		exited: make(chan struct{}),
+	}
+	if err := v.waitReady(ctx, 10*time.Second); err != nil {
+		return nil, err
+	}
+	for _, id := range programs {
+		info, err := v.Client.GetAccountInfo(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("program %s: %w", id, err)
+		}
+		if info.Value == nil || !info.Value.Executable {
+			return nil, fmt.Errorf("program %s is not deployed on %s", id, rpcURL)
+		}
+	}
+	return v, nil
+}
+
+func (v *Validator) waitReady(ctx context.Context, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		if health, err := v.Client.GetHealth(ctx); err == nil && health == "ok" {
+			return nil
+		}
+
+		select {
+		case <-v.exited:
+			return fmt.Errorf("validator exited during startup: %v (see %s)", v.waitErr, v.LogPath())
+		case <-ctx.Done():
+			return fmt.Errorf("validator not healthy after %s: %w", timeout, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// LogPath is the validator's log file, useful when a test fails. It is
+// empty for a validator from Connect.
+func (v *Validator) LogPath() string {
+	if v.ledgerDir == "" {
+		return ""
+	}
+	return filepath.Join(v.ledgerDir, "validator.log")
+}
+
+// Close stops the validator and removes its temporary ledger.
+func (v *Validator) Close() error {
+	var err error
+	if v.cmd != nil && v.cmd.Process != nil {
+		select {
+		case <-v.exited:
+		default:
+			_ = v.cmd.Process.Signal(os.Interrupt)
+			select {
+			case <-v.exited:
+			case <-time.After(10 * time.Second):
+				err = v.cmd.Process.Kill()
+				<-v.exited
+			}
+		}
+	}
+	v.cleanup()
+	return err
+}
+
+func (v *Validator) cleanup() {
+	if v.removeLedger {
+		_ = os.RemoveAll(v.ledgerDir)
+	}
+}
+
+// Airdrop funds an account from the validator's faucet and waits for the
+// transfer to be confirmed.
+func (v *Validator) Airdrop(ctx context.Context, to solana.PublicKey, lamports uint64) error {
+	sig, err := v.Client.RequestAirdrop(ctx, to, lamports, rpc.CommitmentConfirmed)
+	if err != nil {
+		return fmt.Errorf("request airdrop: %w", err)
+	}
+	return v.Confirm(ctx, sig)
+}
+
+// Send signs and submits a transaction paid by payer, then waits for it to
+// be confirmed. Extra signers sign alongside the payer.
+func (v *Validator) Send(ctx context.Context, instructions []solana.Instruction, payer solana.PrivateKey, signers ...solana.PrivateKey) (solana.Signature, error) {
+	latest, err := v.Client.GetLatestBlockhash(ctx, rpc.CommitmentConfirmed)
+	if err != nil {
+		return solana.Signature{}, fmt.Errorf("get latest blockhash: %w", err)
+	}
+
+	tx, err := solana.NewTransaction(instructions, latest.Value.Blockhash, solana.TransactionPayer(payer.PublicKey()))
+	if err != nil {
+		return solana.Signature{}, fmt.Errorf("build transaction: %w", err)
+	}
+
+	keys := append([]solana.PrivateKey{payer}, signers...)
+	if _, err := tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+		for i := range keys {
+			if keys[i].PublicKey().Equals(key) {
+				return &keys[i]
+			}
+		}
+		return nil
+	}); err != nil {
+		return solana.Signature{}, fmt.Errorf("sign transaction: %w", err)
+	}
+
+	sig, err := v.Client.SendTransactionWithOpts(ctx, tx, rpc.TransactionOpts{PreflightCommitment: rpc.CommitmentConfirmed})
+	if err != nil {
+		return solana.Signature{}, fmt.Errorf("send transaction: %w", err)
+	}
+	return sig, v.Confirm(ctx, sig)
+}
+
+// Confirm waits until a transaction reaches confirmed commitment.
+func (v *Validator) Confirm(ctx context.Context, sig solana.Signature) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		out, err := v.Client.GetSignatureStatuses(ctx, false, sig)
+		if err == nil && len(out.Value) == 1 && out.Value[0] != nil {
+			status := out.Value[0]
+			if status.Err != nil {
+				return fmt.Errorf("transaction %s failed: %v", sig, status.Err)
+			}
+			if status.ConfirmationStatus == rpc.ConfirmationStatusConfirmed || status.ConfirmationStatus == rpc.ConfirmationStatusFinalized {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("confirm %s: %w", sig, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
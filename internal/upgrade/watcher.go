@@ -0,0 +1,170 @@
+// Package upgrade watches the upgradeable loader deployments of the indexed
+// programs and reports their upgrades, after which events may no longer
+// decode with the IDL or decoder the indexer started with.
+package upgrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	solanaClient "github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// signatureLookback is the number of the latest transactions of a program
+// data account searched for the upgrade; others, such as authority
+// changes, may follow it.
+const signatureLookback = 10
+
+// Source reads program deployments; *solanaClient.Client implements it.
+type Source interface {
+	GetProgramDeployment(ctx context.Context, program solana.PublicKey) (*solanaClient.ProgramDeployment, error)
+	GetProgramExecutable(ctx context.Context, program solana.PublicKey) ([]byte, error)
+	GetSignaturesForAddress(ctx context.Context, address solana.PublicKey, limit int, before, until *solana.Signature) ([]*rpc.TransactionSignature, error)
+}
+
+// Deployment is a deployment of a program, identified by its slot.
+type Deployment struct {
+	Slot uint64
+	// Hash is solanaClient.ProgramHash of the deployed executable.
+	Hash string
+}
+
+// Upgrade is a new deployment of a program.
+type Upgrade struct {
+	Program     solana.PublicKey
+	ProgramData solana.PublicKey
+	Deployment
+	// Signature and BlockTime are those of the upgrade transaction; the
+	// signature is zero when it was not found.
+	Signature solana.Signature
+	BlockTime time.Time
+	Previous  Deployment
+}
+
+type Options struct {
+	Interval time.Duration
+}
+
+// Watcher checks the deployments of programs every interval and calls
+// onUpgrade for each new one. The first deployment seen of a program is its
+// baseline, unless Seed gave one.
+type Watcher struct {
+	source    Source
+	programs  []solana.PublicKey
+	onUpgrade func(ctx context.Context, u Upgrade) error
+	opts      Options
+
+	mu    sync.Mutex
+	known map[solana.PublicKey]Deployment
+}
+
+func NewWatcher(source Source, programs []solana.PublicKey, onUpgrade func(ctx context.Context, u Upgrade) error, opts Options) *Watcher {
+	if opts.Interval <= 0 {
+		opts.Interval = 5 * time.Minute
+	}
+	return &Watcher{
+		source:    source,
+		programs:  programs,
+		onUpgrade: onUpgrade,
+		opts:      opts,
+		known:     make(map[solana.PublicKey]Deployment),
+	}
+}
+
+// Seed sets the deployment of program last recorded, so an upgrade made
+// while the indexer was stopped is still reported.
+func (w *Watcher) Seed(program solana.PublicKey, d Deployment) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.known[program] = d
+}
+
+// Deployment returns the current deployment of program, false before it was
+// first checked.
+func (w *Watcher) Deployment(program solana.PublicKey) (Deployment, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	d, ok := w.known[program]
+	return d, ok
+}
+
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.CheckOnce(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("error checking program upgrades: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckOnce checks every program once. An upgrade onUpgrade fails for is
+// reported again by the next check.
+func (w *Watcher) CheckOnce(ctx context.Context) error {
+	var errs []error
+	for _, program := range w.programs {
+		if err := w.check(ctx, program); err != nil {
+			errs = append(errs, fmt.Errorf("check %s: %w", program, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (w *Watcher) check(ctx context.Context, program solana.PublicKey) error {
+	deployment, err := w.source.GetProgramDeployment(ctx, program)
+	if err != nil {
+		return err
+	}
+	if deployment == nil {
+		// Not upgradeable.
+		return nil
+	}
+	previous, known := w.Deployment(program)
+	if known && previous.Slot == deployment.Slot {
+		return nil
+	}
+
+	executable, err := w.source.GetProgramExecutable(ctx, program)
+	if err != nil {
+		return err
+	}
+	current := Deployment{Slot: deployment.Slot, Hash: solanaClient.ProgramHash(executable)}
+	if !known {
+		log.Printf("program %s deployed at slot %d with hash %s", program, current.Slot, current.Hash)
+		w.Seed(program, current)
+		return nil
+	}
+
+	u := Upgrade{Program: program, ProgramData: deployment.ProgramData, Deployment: current, Previous: previous}
+	sigs, err := w.source.GetSignaturesForAddress(ctx, deployment.ProgramData, signatureLookback, nil, nil)
+	if err != nil {
+		return fmt.Errorf("find upgrade transaction: %w", err)
+	}
+	for _, sig := range sigs {
+		if sig.Slot == deployment.Slot && sig.Err == nil {
+			u.Signature = sig.Signature
+			if sig.BlockTime != nil {
+				u.BlockTime = sig.BlockTime.Time()
+			}
+			break
+		}
+	}
+	if err := w.onUpgrade(ctx, u); err != nil {
+		return err
+	}
+	w.Seed(program, current)
+	return nil
+}
@@ -0,0 +1,109 @@
+package upgrade
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	solanaClient "github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+type fakeSource struct {
+	slot       uint64
+	executable []byte
+	sigs       []*rpc.TransactionSignature
+}
+
+func (s *fakeSource) GetProgramDeployment(ctx context.Context, program solana.PublicKey) (*solanaClient.ProgramDeployment, error) {
+	return &solanaClient.ProgramDeployment{ProgramData: solana.PublicKey{9}, Slot: s.slot}, nil
+}
+
+func (s *fakeSource) GetProgramExecutable(ctx context.Context, program solana.PublicKey) ([]byte, error) {
+	return s.executable, nil
+}
+
+func (s *fakeSource) GetSignaturesForAddress(ctx context.Context, address solana.PublicKey, limit int, before, until *solana.Signature) ([]*rpc.TransactionSignature, error) {
+	return s.sigs, nil
+}
+
+func TestWatcherCheckOnce(t *testing.T) {
+	program := solana.PublicKey{1}
+	blockTime := solana.UnixTimeSeconds(1700000000)
+	source := &fakeSource{slot: 100, executable: []byte("v1")}
+
+	var upgrades []Upgrade
+	fail := false
+	w := NewWatcher(source, []solana.PublicKey{program}, func(ctx context.Context, u Upgrade) error {
+		if fail {
+			return errors.New("store failed")
+		}
+		upgrades = append(upgrades, u)
+		return nil
+	}, Options{})
+
+	// The first deployment seen is the baseline.
+	if err := w.CheckOnce(context.Background()); err != nil {
+		t.Fatalf("CheckOnce() error = %v", err)
+	}
+	if len(upgrades) != 0 {
+		t.Fatalf("CheckOnce() reported %d upgrades of the baseline, want 0", len(upgrades))
+	}
+
+	source.slot, source.executable = 200, []byte("v2")
+	source.sigs = []*rpc.TransactionSignature{
+		{Signature: solana.Signature{3}, Slot: 250},
+		{Signature: solana.Signature{2}, Slot: 200, BlockTime: &blockTime},
+	}
+	fail = true
+	if err := w.CheckOnce(context.Background()); err == nil {
+		t.Fatalf("CheckOnce() error = nil, want the onUpgrade error")
+	}
+	fail = false
+	if err := w.CheckOnce(context.Background()); err != nil {
+		t.Fatalf("CheckOnce() error = %v", err)
+	}
+	if len(upgrades) != 1 {
+		t.Fatalf("CheckOnce() reported %d upgrades, want 1", len(upgrades))
+	}
+	u := upgrades[0]
+	if u.Slot != 200 || u.Previous.Slot != 100 {
+		t.Errorf("upgrade slots = %d from %d, want 200 from 100", u.Slot, u.Previous.Slot)
+	}
+	if u.Hash != solanaClient.ProgramHash([]byte("v2")) || u.Previous.Hash != solanaClient.ProgramHash([]byte("v1")) {
+		t.Errorf("upgrade hashes = %s from %s, want those of v2 and v1", u.Hash, u.Previous.Hash)
+	}
+	if u.Signature != (solana.Signature{2}) || !u.BlockTime.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("upgrade transaction = %s at %v, want the one at the deployment slot", u.Signature, u.BlockTime)
+	}
+
+	if err := w.CheckOnce(context.Background()); err != nil {
+		t.Fatalf("CheckOnce() error = %v", err)
+	}
+	if len(upgrades) != 1 {
+		t.Errorf("CheckOnce() reported the same upgrade again")
+	}
+}
+
+func TestWatcherSeed(t *testing.T) {
+	program := solana.PublicKey{1}
+	source := &fakeSource{slot: 100, executable: []byte("v1")}
+	var upgrades []Upgrade
+	w := NewWatcher(source, []solana.PublicKey{program}, func(ctx context.Context, u Upgrade) error {
+		upgrades = append(upgrades, u)
+		return nil
+	}, Options{})
+	w.Seed(program, Deployment{Slot: 50, Hash: "old"})
+
+	if err := w.CheckOnce(context.Background()); err != nil {
+		t.Fatalf("CheckOnce() error = %v", err)
+	}
+	if len(upgrades) != 1 || upgrades[0].Previous.Hash != "old" {
+		t.Fatalf("CheckOnce() upgrades = %+v, want one from the seeded deployment", upgrades)
+	}
+	if !upgrades[0].Signature.IsZero() {
+		t.Errorf("upgrade signature = %s, want zero without a transaction at the slot", upgrades[0].Signature)
+	}
+}
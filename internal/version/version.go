@@ -0,0 +1,63 @@
+// Package version holds build metadata injected at link time:
+//
+//	go build -ldflags "-X github.com/lugondev/go-indexer-solana-starter/internal/version.Version=v1.2.0 \
+//	  -X github.com/lugondev/go-indexer-solana-starter/internal/version.Commit=$(git rev-parse --short HEAD)"
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time,omitempty"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get returns the build metadata. When no commit was injected it falls back
+// to the VCS revision the Go toolchain stamps into the binary.
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+
+	if info.Commit == "" {
+		if bi, ok := debug.ReadBuildInfo(); ok {
+			for _, setting := range bi.Settings {
+				switch setting.Key {
+				case "vcs.revision":
+					info.Commit = setting.Value
+				case "vcs.time":
+					if info.BuildTime == "" {
+						info.BuildTime = setting.Value
+					}
+				}
+			}
+		}
+	}
+	if len(info.Commit) > 12 {
+		info.Commit = info.Commit[:12]
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+
+	return info
+}
+
+// String identifies the build, e.g. "v1.2.0+3f2a9c1d0b7e". It is recorded on
+// every indexed event.
+func (i Info) String() string {
+	return i.Version + "+" + i.Commit
+}
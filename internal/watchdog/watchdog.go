@@ -0,0 +1,265 @@
+// Package watchdog raises alerts when the indexer stops making progress or
+// fails too often, logging them, exposing them to /health and the metrics
+// and, optionally, POSTing them to a webhook.
+package watchdog
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/failure"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+type AlertKind string
+
+const (
+	// AlertStall is raised when neither the processed slot nor the
+	// finalized watermark has moved for Options.StallAfter.
+	AlertStall AlertKind = "stall"
+	// AlertErrors is raised when pipeline errors exceed
+	// Options.MaxErrorsPerMinute over Options.ErrorWindow.
+	AlertErrors AlertKind = "errors"
+)
+
+// AlertKinds are the kinds of alert the watchdog raises.
+var AlertKinds = []AlertKind{AlertStall, AlertErrors}
+
+type Alert struct {
+	Kind    AlertKind `json:"kind"`
+	Message string    `json:"message"`
+	// Since is when the alert was first raised.
+	Since time.Time `json:"since"`
+}
+
+// notification is the body POSTed to Options.WebhookURL when an alert is
+// raised or resolved.
+type notification struct {
+	Status string `json:"status"`
+	Alert
+	At time.Time `json:"at"`
+}
+
+type Options struct {
+	Interval time.Duration
+	// StallAfter raises a stall alert when indexing has not progressed for
+	// this long; zero disables the check.
+	StallAfter time.Duration
+	// MaxErrorsPerMinute raises an error alert when the pipeline errors of
+	// the last ErrorWindow average more than this per minute; zero disables
+	// the check.
+	MaxErrorsPerMinute float64
+	ErrorWindow        time.Duration
+	// WebhookURL is notified of raised and resolved alerts; empty only
+	// logs them. WebhookSecret signs every body like the webhook sink.
+	WebhookURL    string
+	WebhookSecret string
+}
+
+// Source reports the progress and errors of the indexer;
+// *indexer.Indexer implements it.
+type Source interface {
+	Watermarks() models.Watermarks
+	PipelineErrors() map[failure.Kind]uint64
+}
+
+// Pauser is implemented by sources whose indexing can be paused, which is
+// not a stall.
+type Pauser interface {
+	Paused() bool
+}
+
+type errorSample struct {
+	at    time.Time
+	total uint64
+}
+
+// Watchdog checks its source every interval.
+type Watchdog struct {
+	source     Source
+	opts       Options
+	httpClient *http.Client
+	now        func() time.Time
+
+	// progress, progressAt and samples are only used by Check.
+	progress   models.Watermarks
+	progressAt time.Time
+	samples    []errorSample
+
+	mu     sync.RWMutex
+	alerts map[AlertKind]Alert
+}
+
+func New(source Source, opts Options) *Watchdog {
+	if opts.Interval <= 0 {
+		opts.Interval = 30 * time.Second
+	}
+	if opts.ErrorWindow <= 0 {
+		opts.ErrorWindow = 5 * time.Minute
+	}
+	return &Watchdog{
+		source:     source,
+		opts:       opts,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		now:        time.Now,
+		alerts:     make(map[AlertKind]Alert),
+	}
+}
+
+func (w *Watchdog) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		w.Check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check evaluates the alerts against the source. Raised and resolved
+// alerts are logged and notified.
+func (w *Watchdog) Check(ctx context.Context) {
+	now := w.now().UTC()
+	w.check(ctx, AlertStall, w.stalled(now), now)
+	w.check(ctx, AlertErrors, w.failing(now), now)
+}
+
+// stalled returns why indexing looks stalled, or "".
+func (w *Watchdog) stalled(now time.Time) string {
+	watermarks := w.source.Watermarks()
+	if p, ok := w.source.(Pauser); ok && p.Paused() {
+		// Paused on purpose; the stall is timed from the resume.
+		w.progress, w.progressAt = watermarks, now
+		return ""
+	}
+	if w.progressAt.IsZero() || watermarks.Processed > w.progress.Processed || watermarks.Finalized > w.progress.Finalized {
+		w.progress, w.progressAt = watermarks, now
+	}
+	if w.opts.StallAfter <= 0 {
+		return ""
+	}
+	if idle := now.Sub(w.progressAt); idle >= w.opts.StallAfter {
+		return fmt.Sprintf("no progress for %s: processed slot %d, finalized watermark %d",
+			idle.Truncate(time.Second), w.progress.Processed, w.progress.Finalized)
+	}
+	return ""
+}
+
+// failing returns why the error rate is too high, or "". The rate is only
+// judged once a full window has been sampled.
+func (w *Watchdog) failing(now time.Time) string {
+	var total uint64
+	for _, n := range w.source.PipelineErrors() {
+		total += n
+	}
+	w.samples = append(w.samples, errorSample{at: now, total: total})
+	// Keep the newest sample at least ErrorWindow old as the baseline.
+	for len(w.samples) > 1 && now.Sub(w.samples[1].at) >= w.opts.ErrorWindow {
+		w.samples = w.samples[1:]
+	}
+	if w.opts.MaxErrorsPerMinute <= 0 {
+		return ""
+	}
+	oldest := w.samples[0]
+	elapsed := now.Sub(oldest.at)
+	if elapsed < w.opts.ErrorWindow {
+		return ""
+	}
+	rate := float64(total-oldest.total) / elapsed.Minutes()
+	if rate > w.opts.MaxErrorsPerMinute {
+		return fmt.Sprintf("%.1f pipeline errors per minute over the last %s, above %g",
+			rate, elapsed.Truncate(time.Second), w.opts.MaxErrorsPerMinute)
+	}
+	return ""
+}
+
+func (w *Watchdog) check(ctx context.Context, kind AlertKind, message string, now time.Time) {
+	w.mu.Lock()
+	alert, raised := w.alerts[kind]
+	switch {
+	case message != "" && raised:
+		alert.Message = message
+		w.alerts[kind] = alert
+		w.mu.Unlock()
+		return
+	case message != "":
+		alert = Alert{Kind: kind, Message: message, Since: now}
+		w.alerts[kind] = alert
+	case raised:
+		delete(w.alerts, kind)
+	default:
+		w.mu.Unlock()
+		return
+	}
+	w.mu.Unlock()
+
+	status := "firing"
+	if message == "" {
+		status = "resolved"
+		log.Printf("watchdog: %s alert resolved", kind)
+	} else {
+		log.Printf("warning: watchdog: %s", message)
+	}
+	if err := w.notify(ctx, notification{Status: status, Alert: alert, At: now}); err != nil && ctx.Err() == nil {
+		log.Printf("warning: watchdog: failed to notify %s alert: %v", kind, err)
+	}
+}
+
+func (w *Watchdog) notify(ctx context.Context, n notification) error {
+	if w.opts.WebhookURL == "" {
+		return nil
+	}
+	body, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("marshal alert: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.opts.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create alert request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.opts.WebhookSecret != "" {
+		mac := hmac.New(sha256.New, []byte(w.opts.WebhookSecret))
+		mac.Write(body)
+		req.Header.Set("X-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("send alert: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("alert webhook failed with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Alerts returns the raised alerts in AlertKinds order; it is never nil.
+func (w *Watchdog) Alerts() []Alert {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	alerts := []Alert{}
+	for _, kind := range AlertKinds {
+		if a, ok := w.alerts[kind]; ok {
+			alerts = append(alerts, a)
+		}
+	}
+	return alerts
+}
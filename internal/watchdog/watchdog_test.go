@@ -0,0 +1,158 @@
+package watchdog
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lugondev/go-indexer-solana-starter/internal/failure"
+	"github.com/lugondev/go-indexer-solana-starter/internal/models"
+)
+
+type fakeSource struct {
+	watermarks models.Watermarks
+	errors     uint64
+}
+
+func (s *fakeSource) Watermarks() models.Watermarks {
+	return s.watermarks
+}
+
+func (s *fakeSource) PipelineErrors() map[failure.Kind]uint64 {
+	return map[failure.Kind]uint64{failure.KindRPC: s.errors, failure.KindDecode: 0}
+}
+
+// clock advances by step on every call.
+func clock(step time.Duration) func() time.Time {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		now = now.Add(step)
+		return now
+	}
+}
+
+func kinds(alerts []Alert) []AlertKind {
+	var out []AlertKind
+	for _, a := range alerts {
+		out = append(out, a.Kind)
+	}
+	return out
+}
+
+func TestWatchdog_Stall(t *testing.T) {
+	var received []notification
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var n notification
+		if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
+			t.Errorf("decode notification: %v", err)
+		}
+		if r.Header.Get("X-Signature-256") == "" {
+			t.Errorf("notification is not signed")
+		}
+		received = append(received, n)
+	}))
+	defer srv.Close()
+
+	source := &fakeSource{watermarks: models.Watermarks{Processed: 10, Finalized: 5}}
+	w := New(source, Options{StallAfter: time.Minute, WebhookURL: srv.URL, WebhookSecret: "secret"})
+	w.now = clock(30 * time.Second)
+	ctx := context.Background()
+
+	w.Check(ctx) // first progress
+	w.Check(ctx) // 30s without progress
+	if got := w.Alerts(); len(got) != 0 {
+		t.Fatalf("Alerts() = %v, want none before StallAfter", got)
+	}
+	w.Check(ctx) // 60s without progress
+	w.Check(ctx)
+	if got := kinds(w.Alerts()); len(got) != 1 || got[0] != AlertStall {
+		t.Fatalf("Alerts() = %v, want [stall]", got)
+	}
+
+	source.watermarks.Finalized = 6
+	w.Check(ctx)
+	if got := w.Alerts(); len(got) != 0 {
+		t.Errorf("Alerts() = %v, want the stall resolved by finalized progress", got)
+	}
+
+	if len(received) != 2 || received[0].Status != "firing" || received[0].Kind != AlertStall || received[1].Status != "resolved" {
+		t.Errorf("notifications = %+v, want stall firing then resolved", received)
+	}
+}
+
+type pausableSource struct {
+	fakeSource
+	paused bool
+}
+
+func (s *pausableSource) Paused() bool {
+	return s.paused
+}
+
+func TestWatchdog_Paused(t *testing.T) {
+	source := &pausableSource{fakeSource: fakeSource{watermarks: models.Watermarks{Processed: 10}}, paused: true}
+	w := New(source, Options{StallAfter: time.Minute})
+	w.now = clock(30 * time.Second)
+	ctx := context.Background()
+
+	for range 4 {
+		w.Check(ctx)
+	}
+	if got := w.Alerts(); len(got) != 0 {
+		t.Fatalf("Alerts() = %v, want none while paused", got)
+	}
+
+	source.paused = false
+	w.Check(ctx) // 30s since the resume
+	if got := w.Alerts(); len(got) != 0 {
+		t.Fatalf("Alerts() = %v, want none before StallAfter from the resume", got)
+	}
+	w.Check(ctx)
+	if got := kinds(w.Alerts()); len(got) != 1 || got[0] != AlertStall {
+		t.Errorf("Alerts() = %v, want [stall]", got)
+	}
+}
+
+func TestWatchdog_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		perCheck  uint64
+		max       float64
+		wantAlert bool
+	}{
+		{"below threshold", 1, 5, false},
+		{"at threshold", 5, 5, false},
+		{"above threshold", 6, 5, true},
+		{"disabled", 100, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &fakeSource{}
+			w := New(source, Options{MaxErrorsPerMinute: tt.max, ErrorWindow: 2 * time.Minute})
+			w.now = clock(time.Minute)
+			for range 4 {
+				w.Check(context.Background())
+				source.errors += tt.perCheck
+			}
+			got := len(w.Alerts()) == 1 && w.Alerts()[0].Kind == AlertErrors
+			if got != tt.wantAlert {
+				t.Errorf("error alert = %v, want %v (alerts %v)", got, tt.wantAlert, w.Alerts())
+			}
+		})
+	}
+}
+
+func TestWatchdog_ErrorsNeedFullWindow(t *testing.T) {
+	source := &fakeSource{}
+	w := New(source, Options{MaxErrorsPerMinute: 1, ErrorWindow: 5 * time.Minute})
+	w.now = clock(time.Minute)
+	w.Check(context.Background())
+	source.errors = 100
+	w.Check(context.Background())
+	if got := w.Alerts(); len(got) != 0 {
+		t.Errorf("Alerts() = %v, want none before a full window", got)
+	}
+}
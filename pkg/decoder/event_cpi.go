@@ -0,0 +1,56 @@
+package decoder
+
+import (
+	"bytes"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	solanaClient "github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// eventIxTag prefixes the instruction data of an Anchor emit_cpi! event:
+// the first 8 bytes of SHA256("anchor:event"), little-endian.
+var eventIxTag = []byte{0xe4, 0x45, 0xa5, 0x2e, 0x51, 0xcb, 0x9a, 0x1d}
+
+// ParseEventCPI returns the event payload (discriminator and Borsh data)
+// of an instruction a program invoked on itself with emit_cpi!, or false
+// if data is not an event instruction.
+func ParseEventCPI(data []byte) ([]byte, bool) {
+	if len(data) < len(eventIxTag)+8 || !bytes.HasPrefix(data, eventIxTag) {
+		return nil, false
+	}
+	return data[len(eventIxTag):], true
+}
+
+// EventData returns the event payloads program wrote in its logs or
+// emitted with emit_cpi! in tx, logs first.
+func EventData(program solana.PublicKey, tx *rpc.GetTransactionResult, mode ProgramDataMode) [][]byte {
+	if tx == nil || tx.Meta == nil {
+		return nil
+	}
+	payloads := ProgramDataOf(tx.Meta.LogMessages, program, mode)
+	if tx.Transaction != nil {
+		if txObj, err := tx.Transaction.GetTransaction(); err == nil {
+			accounts := solanaClient.ResolveAccountKeys(txObj.Message, tx.Meta.LoadedAddresses)
+			payloads = append(payloads, EventCPIData(program, tx.Meta, accounts)...)
+		}
+	}
+	return payloads
+}
+
+// EventCPIData returns the event payloads program emitted with emit_cpi!,
+// in execution order.
+func EventCPIData(program solana.PublicKey, meta *rpc.TransactionMeta, accounts []solana.PublicKey) [][]byte {
+	var events [][]byte
+	for _, set := range meta.InnerInstructions {
+		for _, ix := range set.Instructions {
+			if int(ix.ProgramIDIndex) >= len(accounts) || !accounts[ix.ProgramIDIndex].Equals(program) {
+				continue
+			}
+			if data, ok := ParseEventCPI(ix.Data); ok {
+				events = append(events, data)
+			}
+		}
+	}
+	return events
+}
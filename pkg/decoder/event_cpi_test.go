@@ -0,0 +1,40 @@
+package decoder
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"slices"
+	"testing"
+)
+
+func TestParseEventCPI(t *testing.T) {
+	sum := sha256.Sum256([]byte("anchor:event"))
+	tag := sum[:8]
+	slices.Reverse(tag)
+	if !bytes.Equal(tag, eventIxTag) {
+		t.Fatalf("eventIxTag = %x, want %x", eventIxTag, tag)
+	}
+
+	minted := sha256.Sum256([]byte("event:TokensMintedEvent"))
+	burned := sha256.Sum256([]byte("event:TokensBurnedEvent"))
+	event := append(slices.Clone(minted[:8]), 0x01, 0x02)
+	tests := []struct {
+		name   string
+		data   []byte
+		want   []byte
+		wantOk bool
+	}{
+		{"event instruction", append(slices.Clone(eventIxTag), event...), event, true},
+		{"other instruction", append(slices.Clone(burned[:8]), event...), nil, false},
+		{"no discriminator", append(slices.Clone(eventIxTag), 0x01, 0x02), nil, false},
+		{"empty", nil, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseEventCPI(tt.data)
+			if ok != tt.wantOk || !bytes.Equal(got, tt.want) {
+				t.Errorf("ParseEventCPI() = %x, %v, want %x, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
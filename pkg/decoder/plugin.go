@@ -0,0 +1,72 @@
+package decoder
+
+import (
+	"fmt"
+	"plugin"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// LoadPlugin opens a Go plugin, built with go build -buildmode=plugin, and
+// registers the decoder it exports. The plugin does not need to import this
+// module; it exports
+//
+//	var ProgramID string // base58
+//	func DecodeEvent(data []byte) (string, interface{}, error)
+//
+// where DecodeEvent returns the event's type and value, and an empty type
+// for data it does not know. Loading a plugin again returns its program.
+// Plugins must be built with the same Go version and versions of shared
+// dependencies as the indexer, and only load when PluginsSupported.
+func LoadPlugin(path string) (solana.PublicKey, error) {
+	if !PluginsSupported {
+		return solana.PublicKey{}, fmt.Errorf("open decoder plugin %s: this build of the indexer cannot load plugins, it needs cgo", path)
+	}
+
+	registry.Lock()
+	program, loaded := registry.plugins[path]
+	registry.Unlock()
+	if loaded {
+		return program, nil
+	}
+
+	p, err := plugin.Open(path)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("open decoder plugin: %w", err)
+	}
+	sym, err := p.Lookup("ProgramID")
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("decoder plugin %s: %w", path, err)
+	}
+	id, ok := sym.(*string)
+	if !ok {
+		return solana.PublicKey{}, fmt.Errorf("decoder plugin %s: ProgramID is a %T, not a string", path, sym)
+	}
+	program, err = solana.PublicKeyFromBase58(*id)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("decoder plugin %s: ProgramID: %w", path, err)
+	}
+	sym, err = p.Lookup("DecodeEvent")
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("decoder plugin %s: %w", path, err)
+	}
+	decode, ok := sym.(func([]byte) (string, interface{}, error))
+	if !ok {
+		return solana.PublicKey{}, fmt.Errorf("decoder plugin %s: DecodeEvent is a %T, not a func([]byte) (string, interface{}, error)", path, sym)
+	}
+
+	err = register(program, DecoderFunc(func(data []byte) (string, interface{}, error) {
+		eventType, event, err := decode(data)
+		if err == nil && eventType == "" {
+			err = ErrUnknownEvent
+		}
+		return eventType, event, err
+	}))
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("decoder plugin %s: %w", path, err)
+	}
+	registry.Lock()
+	registry.plugins[path] = program
+	registry.Unlock()
+	return program, nil
+}
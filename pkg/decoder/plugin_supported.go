@@ -0,0 +1,7 @@
+//go:build cgo && (linux || darwin || freebsd)
+
+package decoder
+
+// PluginsSupported reports whether LoadPlugin can load plugins in this
+// build: the plugin package needs cgo on Linux, macOS or FreeBSD.
+const PluginsSupported = true
@@ -0,0 +1,195 @@
+package decoder
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+const (
+	programDataPrefix = "Program data:"
+	programLogPrefix  = "Program log: "
+)
+
+type ProgramDataMode string
+
+const (
+	// ProgramDataStrict only accepts top-level "Program data: <base64>" lines
+	// with standard padded base64, exactly as emitted by sol_log_data.
+	ProgramDataStrict ProgramDataMode = "strict"
+	// ProgramDataLenient additionally accepts data logs nested behind one or
+	// more "Program log: " prefixes (as re-logged by some CPI layers),
+	// surrounding whitespace, multiple space separated slices, and unpadded
+	// or URL-safe base64.
+	ProgramDataLenient ProgramDataMode = "lenient"
+)
+
+var programDataEncodings = []*base64.Encoding{
+	base64.StdEncoding,
+	base64.RawStdEncoding,
+	base64.URLEncoding,
+	base64.RawURLEncoding,
+}
+
+func ParseProgramDataMode(s string) (ProgramDataMode, error) {
+	switch mode := ProgramDataMode(strings.ToLower(strings.TrimSpace(s))); mode {
+	case ProgramDataStrict, ProgramDataLenient:
+		return mode, nil
+	case "":
+		return ProgramDataLenient, nil
+	default:
+		return "", fmt.Errorf("unknown program data mode: %s", s)
+	}
+}
+
+// ParseProgramData extracts event payloads from transaction logs using
+// lenient matching.
+func ParseProgramData(logs []string) [][]byte {
+	return ParseProgramDataWithMode(logs, ProgramDataLenient)
+}
+
+func ParseProgramDataWithMode(logs []string, mode ProgramDataMode) [][]byte {
+	var programData [][]byte
+
+	for _, log := range logs {
+		var (
+			data []byte
+			ok   bool
+		)
+		if mode == ProgramDataStrict {
+			data, ok = parseProgramDataStrict(log)
+		} else {
+			data, ok = parseProgramDataLenient(log)
+		}
+		if ok {
+			programData = append(programData, data)
+		}
+	}
+
+	return programData
+}
+
+// CountProgramData counts the "Program data:" lines of logs that mode
+// matches, including those whose payload it fails to decode.
+func CountProgramData(logs []string, mode ProgramDataMode) int {
+	var n int
+	for _, log := range logs {
+		if mode == ProgramDataStrict {
+			if strings.HasPrefix(log, programDataPrefix+" ") {
+				n++
+			}
+		} else if strings.HasPrefix(stripProgramLog(log), programDataPrefix) {
+			n++
+		}
+	}
+	return n
+}
+
+func parseProgramDataStrict(log string) ([]byte, bool) {
+	payload, ok := strings.CutPrefix(log, programDataPrefix+" ")
+	if !ok || payload == "" {
+		return nil, false
+	}
+
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// stripProgramLog removes the "Program log: " prefixes a data log may be
+// nested behind.
+func stripProgramLog(log string) string {
+	line := strings.TrimSpace(log)
+	for {
+		rest, ok := strings.CutPrefix(line, programLogPrefix)
+		if !ok {
+			return line
+		}
+		line = strings.TrimSpace(rest)
+	}
+}
+
+func parseProgramDataLenient(log string) ([]byte, bool) {
+	payload, ok := strings.CutPrefix(stripProgramLog(log), programDataPrefix)
+	if !ok {
+		return nil, false
+	}
+
+	fields := strings.Fields(payload)
+	if len(fields) == 0 {
+		return nil, false
+	}
+
+	// sol_log_data logs each slice as its own base64 field; the event is
+	// their concatenation.
+	var data []byte
+	for _, field := range fields {
+		decoded, ok := decodeBase64Any(field)
+		if !ok {
+			return nil, false
+		}
+		data = append(data, decoded...)
+	}
+	return data, true
+}
+
+func decodeBase64Any(s string) ([]byte, bool) {
+	for _, enc := range programDataEncodings {
+		if data, err := enc.DecodeString(s); err == nil {
+			return data, true
+		}
+	}
+	return nil, false
+}
+
+// ProgramDataOf is ParseProgramDataWithMode for the payloads program wrote
+// itself, leaving out those of the programs it invoked or was invoked by.
+func ProgramDataOf(logs []string, program solana.PublicKey, mode ProgramDataMode) [][]byte {
+	var (
+		stack []string
+		own   []string
+	)
+	id := program.String()
+	for _, log := range logs {
+		if invoked, ok := InvokedProgram(log); ok {
+			stack = append(stack, invoked)
+			continue
+		}
+		if IsProgramExit(log) {
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			continue
+		}
+		if len(stack) > 0 && stack[len(stack)-1] == id {
+			own = append(own, log)
+		}
+	}
+	return ParseProgramDataWithMode(own, mode)
+}
+
+// InvokedProgram reports the program ID of a "Program <id> invoke [n]" line.
+func InvokedProgram(log string) (string, bool) {
+	rest, ok := strings.CutPrefix(log, "Program ")
+	if !ok {
+		return "", false
+	}
+	program, depth, ok := strings.Cut(rest, " invoke [")
+	if !ok || !strings.HasSuffix(depth, "]") {
+		return "", false
+	}
+	return program, true
+}
+
+// IsProgramExit reports whether log is the "Program <id> success" or
+// "Program <id> failed: <reason>" line ending a program's invocation.
+func IsProgramExit(log string) bool {
+	if !strings.HasPrefix(log, "Program ") || strings.HasPrefix(log, programLogPrefix) {
+		return false
+	}
+	return strings.HasSuffix(log, " success") || strings.Contains(log, " failed: ")
+}
@@ -0,0 +1,191 @@
+package decoder
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+func TestParseProgramDataWithMode(t *testing.T) {
+	// The log lines below are synthetic, not captured from transactions: each
+	// wraps the same made-up payload, a TokensMintedEvent discriminator and
+	// a few bytes, in one of the log shapes the modes tell apart.
+	discriminator := sha256.Sum256([]byte("event:TokensMintedEvent"))
+	payload := append(discriminator[:8], 0x01, 0x02, 0x03, 0xfb, 0xff)
+	std := base64.StdEncoding.EncodeToString(payload)
+	rawStd := base64.RawStdEncoding.EncodeToString(payload)
+	url := base64.URLEncoding.EncodeToString(payload)
+	head := base64.StdEncoding.EncodeToString(payload[:8])
+	tail := base64.StdEncoding.EncodeToString(payload[8:])
+
+	tests := []struct {
+		name        string
+		log         string
+		wantStrict  bool
+		wantLenient bool
+	}{
+		{
+			name:        "top level data log",
+			log:         "Program data: " + std,
+			wantStrict:  true,
+			wantLenient: true,
+		},
+		{
+			name:        "nested behind program log",
+			log:         "Program log: Program data: " + std,
+			wantLenient: true,
+		},
+		{
+			name:        "doubly nested behind program log",
+			log:         "Program log: Program log: Program data: " + std,
+			wantLenient: true,
+		},
+		{
+			name:        "trailing whitespace and carriage return",
+			log:         "Program data: " + std + " \r\n",
+			wantLenient: true,
+		},
+		{
+			name:        "extra spaces after prefix",
+			log:         "Program data:   " + std,
+			wantLenient: true,
+		},
+		{
+			name:        "unpadded base64",
+			log:         "Program data: " + rawStd,
+			wantLenient: true,
+		},
+		{
+			name:        "url safe base64",
+			log:         "Program data: " + url,
+			wantLenient: true,
+		},
+		{
+			name:        "multiple sol_log_data slices",
+			log:         "Program data: " + head + " " + tail,
+			wantLenient: true,
+		},
+		{
+			name: "empty data log",
+			log:  "Program data: ",
+		},
+		{
+			name: "invalid base64",
+			log:  "Program data: not*base64",
+		},
+		{
+			name: "instruction log",
+			log:  "Program log: Instruction: MintTokens",
+		},
+		{
+			name: "invoke log",
+			log:  "Program gARh1g6reuvsAHB7DXqiuYzzyiJeoiJmtmCpV8Y5uWC invoke [1]",
+		},
+		{
+			name: "prefix without separator",
+			log:  "Program data:" + std,
+			// Lenient mode tolerates the missing space.
+			wantLenient: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, mode := range []ProgramDataMode{ProgramDataStrict, ProgramDataLenient} {
+				want := tt.wantStrict
+				if mode == ProgramDataLenient {
+					want = tt.wantLenient
+				}
+
+				got := ParseProgramDataWithMode([]string{tt.log}, mode)
+				if !want {
+					if len(got) != 0 {
+						t.Errorf("%s: got %d payloads, want none", mode, len(got))
+					}
+					continue
+				}
+
+				if len(got) != 1 {
+					t.Fatalf("%s: got %d payloads, want 1", mode, len(got))
+				}
+				if !bytes.Equal(got[0], payload) {
+					t.Errorf("%s: payload = %x, want %x", mode, got[0], payload)
+				}
+			}
+		})
+	}
+}
+
+func TestParseProgramDataMode(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    ProgramDataMode
+		wantErr bool
+	}{
+		{in: "", want: ProgramDataLenient},
+		{in: "strict", want: ProgramDataStrict},
+		{in: " Lenient ", want: ProgramDataLenient},
+		{in: "loose", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseProgramDataMode(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseProgramDataMode(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseProgramDataMode(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCountProgramData(t *testing.T) {
+	logs := []string{
+		"Program gARh1g6reuvsAHB7DXqiuYzzyiJeoiJmtmCpV8Y5uWC invoke [1]",
+		"Program data: AQID",
+		"Program data: not base64!",
+		"Program log: Program data: AQID",
+		"Program log: Instruction: MintTokens",
+	}
+
+	tests := []struct {
+		mode ProgramDataMode
+		want int
+	}{
+		{ProgramDataStrict, 2},
+		{ProgramDataLenient, 3},
+	}
+	for _, tt := range tests {
+		if got := CountProgramData(logs, tt.mode); got != tt.want {
+			t.Errorf("CountProgramData(%s) = %d, want %d", tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestProgramDataOf(t *testing.T) {
+	program := solana.MustPublicKeyFromBase58("gARh1g6reuvsAHB7DXqiuYzzyiJeoiJmtmCpV8Y5uWC")
+	other := solana.MustPublicKeyFromBase58("CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc")
+	data := func(s string) string { return "Program data: " + base64.StdEncoding.EncodeToString([]byte(s)) }
+	logs := []string{
+		data("before"),
+		"Program " + program.String() + " invoke [1]",
+		data("first"),
+		"Program " + other.String() + " invoke [2]",
+		data("nested"),
+		"Program " + other.String() + " success",
+		data("second"),
+		"Program " + program.String() + " success",
+		"Program " + other.String() + " invoke [1]",
+		data("other"),
+		"Program " + other.String() + " failed: custom program error: 0x1",
+	}
+
+	got := ProgramDataOf(logs, program, ProgramDataStrict)
+	if len(got) != 2 || string(got[0]) != "first" || string(got[1]) != "second" {
+		t.Errorf("ProgramDataOf() = %q, want [first second]", got)
+	}
+}
@@ -0,0 +1,81 @@
+// Package decoder lets programs add the decoders of their own programs to
+// the indexer, registered from code built into the binary or loaded from Go
+// plugins.
+package decoder
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// ErrUnknownEvent is returned by a Decoder for payloads that are not an
+// event it can decode, as opposed to a known event that fails to decode.
+var ErrUnknownEvent = errors.New("unknown event")
+
+// Decoder decodes the events a program emits in its "Program data:" logs
+// or with emit_cpi!: an event's discriminator and Borsh data. It returns
+// the event's type, e.g. "VaultDepositedEvent", and value, or
+// ErrUnknownEvent for data it does not know.
+type Decoder interface {
+	DecodeEvent(data []byte) (eventType string, event interface{}, err error)
+}
+
+// DecoderFunc adapts a function to a Decoder.
+type DecoderFunc func(data []byte) (string, interface{}, error)
+
+func (f DecoderFunc) DecodeEvent(data []byte) (string, interface{}, error) {
+	return f(data)
+}
+
+// Registration is a decoder registered for a program.
+type Registration struct {
+	Program solana.PublicKey
+	Decoder Decoder
+}
+
+var registry = struct {
+	sync.Mutex
+	decoders map[solana.PublicKey]Decoder
+	// plugins maps the paths of the loaded plugins to their program.
+	plugins map[string]solana.PublicKey
+}{decoders: make(map[solana.PublicKey]Decoder), plugins: make(map[string]solana.PublicKey)}
+
+// Register makes the indexer decode the events of program with d. It is
+// meant to be called from the init function of the package providing d,
+// and panics if program already has a decoder or d is nil.
+func Register(program solana.PublicKey, d Decoder) {
+	if d == nil {
+		panic(fmt.Sprintf("decoder: Register decoder of %s is nil", program))
+	}
+	if err := register(program, d); err != nil {
+		panic("decoder: Register: " + err.Error())
+	}
+}
+
+func register(program solana.PublicKey, d Decoder) error {
+	registry.Lock()
+	defer registry.Unlock()
+	if _, dup := registry.decoders[program]; dup {
+		return fmt.Errorf("program %s already has a decoder", program)
+	}
+	registry.decoders[program] = d
+	return nil
+}
+
+// Registered returns the registered decoders, ordered by program.
+func Registered() []Registration {
+	registry.Lock()
+	defer registry.Unlock()
+	registrations := make([]Registration, 0, len(registry.decoders))
+	for program, d := range registry.decoders {
+		registrations = append(registrations, Registration{Program: program, Decoder: d})
+	}
+	sort.Slice(registrations, func(i, j int) bool {
+		return registrations[i].Program.String() < registrations[j].Program.String()
+	})
+	return registrations
+}
@@ -0,0 +1,35 @@
+package decoder
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+func TestRegister(t *testing.T) {
+	program := solana.MustPublicKeyFromBase58("CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc")
+	d := DecoderFunc(func(data []byte) (string, interface{}, error) {
+		return "Vaulted", string(data), nil
+	})
+	Register(program, d)
+	defer func() {
+		registry.Lock()
+		delete(registry.decoders, program)
+		registry.Unlock()
+	}()
+
+	registered := Registered()
+	if len(registered) != 1 || registered[0].Program != program {
+		t.Fatalf("Registered() = %v, want the decoder of %s", registered, program)
+	}
+	if eventType, event, err := registered[0].Decoder.DecodeEvent([]byte("x")); eventType != "Vaulted" || event != "x" || err != nil {
+		t.Errorf("DecodeEvent() = %s, %v, %v, want Vaulted, x, nil", eventType, event, err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Register() of a program twice did not panic")
+		}
+	}()
+	Register(program, d)
+}
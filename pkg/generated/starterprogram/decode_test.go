@@ -0,0 +1,105 @@
+package starterprogram
+
+import (
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+func TestDecodeAccount(t *testing.T) {
+	authority := solana.MustPublicKeyFromBase58("9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM")
+	le64 := func(v uint64) []byte { return binary.LittleEndian.AppendUint64(nil, v) }
+	concat := func(parts ...[]byte) []byte {
+		var out []byte
+		for _, p := range parts {
+			out = append(out, p...)
+		}
+		return out
+	}
+	executedAt := int64(1767225600)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name: "counter",
+			data: concat(CounterDiscriminator[:], authority[:], le64(42), []byte{254}),
+			want: &Counter{Authority: authority, Count: 42, Bump: 254},
+		},
+		{
+			name: "user account",
+			data: concat(UserAccountDiscriminator[:], authority[:], le64(7), le64(1700000000), le64(1700000100), []byte{1}),
+			want: &UserAccount{Authority: authority, Points: 7, CreatedAt: 1700000000, UpdatedAt: 1700000100, Bump: 1},
+		},
+		{
+			name: "upgrade proposal with option and enum",
+			data: concat(UpgradeProposalDiscriminator[:], le64(3), authority[:], authority[:],
+				[]byte{2, 0, 0, 0}, []byte("v2"), []byte{byte(ProposalStatusExecuted)},
+				le64(5), le64(1), le64(1700000000), le64(1700086400), []byte{1}, le64(uint64(executedAt)), []byte{9}),
+			want: &UpgradeProposal{
+				ProposalID: 3, Proposer: authority, NewProgramData: authority, Description: "v2",
+				Status: ProposalStatusExecuted, VotesFor: 5, VotesAgainst: 1,
+				CreatedAt: 1700000000, VotingEndsAt: 1700086400, ExecutedAt: &executedAt, Bump: 9,
+			},
+		},
+		{name: "truncated", data: concat(CounterDiscriminator[:], authority[:]), wantErr: true},
+		{name: "unknown discriminator", data: le64(1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeAccount(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeAccount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeAccount() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := DecodeAccount(le64(1)); !errors.Is(err, ErrUnknownAccount) {
+		t.Errorf("DecodeAccount() error = %v, want ErrUnknownAccount", err)
+	}
+}
+
+func TestDecodeInstruction(t *testing.T) {
+	data := append(AddToCounterInstructionDiscriminator[:], binary.LittleEndian.AppendUint64(nil, 5)...)
+	got, err := DecodeInstruction(data)
+	if err != nil {
+		t.Fatalf("DecodeInstruction() error = %v", err)
+	}
+	if want := (&AddToCounterArgs{Value: 5}); !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeInstruction() = %+v, want %+v", got, want)
+	}
+	if name := InstructionNames[AddToCounterInstructionDiscriminator]; name != "add_to_counter" {
+		t.Errorf("InstructionNames[AddToCounterInstructionDiscriminator] = %q, want %q", name, "add_to_counter")
+	}
+	if _, err := DecodeInstruction([]byte{1}); !errors.Is(err, ErrUnknownInstruction) {
+		t.Errorf("DecodeInstruction() error = %v, want ErrUnknownInstruction", err)
+	}
+}
+
+func TestDecodeEvent(t *testing.T) {
+	mint := solana.MustPublicKeyFromBase58("9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM")
+	data := append(NftListingCancelledEventDiscriminator[:], mint[:]...)
+	data = append(data, mint[:]...)
+	data = binary.LittleEndian.AppendUint64(data, 1700000000)
+
+	got, err := DecodeEvent(data)
+	if err != nil {
+		t.Fatalf("DecodeEvent() error = %v", err)
+	}
+	if want := (&NftListingCancelledEvent{NftMint: mint, Seller: mint, Timestamp: 1700000000}); !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeEvent() = %+v, want %+v", got, want)
+	}
+	if _, err := DecodeEvent(data[:20]); err == nil {
+		t.Errorf("DecodeEvent() of truncated data: want error")
+	}
+}
@@ -0,0 +1,68 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/lugondev/go-indexer-solana-starter/pkg/decoder"
+	"github.com/lugondev/go-indexer-solana-starter/pkg/generated/starterprogram"
+)
+
+// DecodeFunc decodes one event payload, an event's discriminator and Borsh
+// data, into the event's name and value. It returns an
+// empty name for data it does not know.
+type DecodeFunc func(data []byte) (name string, event interface{}, err error)
+
+// LogDecoder decodes the events a program logs with sol_log_data, as
+// Anchor's emit! does, or emits with emit_cpi!, extracted the way the
+// standalone indexer does. Only the events of the program itself are
+// decoded, not those of programs it invokes, and failed transactions have
+// no events.
+type LogDecoder struct {
+	decode DecodeFunc
+}
+
+func NewLogDecoder(decode DecodeFunc) *LogDecoder {
+	return &LogDecoder{decode: decode}
+}
+
+// StarterProgramDecoder decodes the events of the starter program with its
+// generated bindings; Event.Data holds a pointer to a starterprogram event.
+func StarterProgramDecoder() *LogDecoder {
+	return NewLogDecoder(func(data []byte) (string, interface{}, error) {
+		event, err := starterprogram.DecodeEvent(data)
+		if errors.Is(err, starterprogram.ErrUnknownEvent) {
+			return "", nil, nil
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		return starterprogram.EventNames[[8]byte(data[:8])], event, nil
+	})
+}
+
+func (d *LogDecoder) Decode(ctx context.Context, program solana.PublicKey, tx Transaction) ([]Event, error) {
+	if tx.Result == nil || tx.Result.Meta == nil || tx.Result.Meta.Err != nil {
+		return nil, nil
+	}
+	var events []Event
+	for _, data := range decoder.EventData(program, tx.Result, decoder.ProgramDataStrict) {
+		name, event, err := d.decode(data)
+		if err != nil {
+			return nil, err
+		}
+		if name == "" {
+			continue
+		}
+		events = append(events, Event{
+			Program:   program,
+			Signature: tx.Signature,
+			Slot:      tx.Slot,
+			BlockTime: tx.BlockTime,
+			Name:      name,
+			Data:      event,
+		})
+	}
+	return events, nil
+}
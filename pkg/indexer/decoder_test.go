@@ -0,0 +1,61 @@
+package indexer
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestLogDecoder_Decode(t *testing.T) {
+	program := solana.MustPublicKeyFromBase58("gARh1g6reuvsAHB7DXqiuYzzyiJeoiJmtmCpV8Y5uWC")
+	other := solana.MustPublicKeyFromBase58("CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc")
+	data := func(s string) string { return "Program data: " + base64.StdEncoding.EncodeToString([]byte(s)) }
+	logs := []string{
+		"Program " + program.String() + " invoke [1]",
+		data("first"),
+		"Program " + other.String() + " invoke [2]",
+		data("nested"),
+		"Program " + other.String() + " success",
+		data("unknown"),
+		"Program data: not base64!",
+		data("second"),
+		"Program " + program.String() + " success",
+	}
+
+	decoder := NewLogDecoder(func(data []byte) (string, interface{}, error) {
+		if string(data) == "unknown" {
+			return "", nil, nil
+		}
+		return "Event", string(data), nil
+	})
+	tx := Transaction{Signature: solana.Signature{1}, Slot: 7, Result: &rpc.GetTransactionResult{Meta: &rpc.TransactionMeta{LogMessages: logs}}}
+	events, err := decoder.Decode(context.Background(), program, tx)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	var got []interface{}
+	for _, e := range events {
+		if e.Program != program || e.Slot != 7 || e.Signature != tx.Signature {
+			t.Errorf("Decode() event = %+v, want the program and transaction", e)
+		}
+		got = append(got, e.Data)
+	}
+	if len(got) != 2 || got[0] != "first" || got[1] != "second" {
+		t.Errorf("Decode() data = %v, want [first second]", got)
+	}
+
+	tx.Result.Meta.Err = "custom program error"
+	if events, _ := decoder.Decode(context.Background(), program, tx); len(events) != 0 {
+		t.Errorf("Decode() of a failed transaction = %d events, want 0", len(events))
+	}
+}
+
+func TestStarterProgramDecoder_SkipsUnknownEvents(t *testing.T) {
+	name, event, err := StarterProgramDecoder().decode([]byte("12345678"))
+	if name != "" || event != nil || err != nil {
+		t.Errorf("decode() = %q, %v, %v, want an unknown event skipped", name, event, err)
+	}
+}
@@ -0,0 +1,270 @@
+// Package indexer runs indexing in-process: an Indexer reads the
+// transactions of programs from a Source, decodes their events with a
+// Decoder and hands them to a Sink. Each part is an interface, so a service
+// can embed the indexer with its own transport, programs and storage.
+//
+// The standalone indexer under cmd/ adds databases, an API and operations
+// tooling on top of the same idea; this package has no dependency on them
+// but extracts events from transactions with the same code.
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// Transaction is a confirmed transaction of a watched program.
+type Transaction struct {
+	Signature solana.Signature
+	Slot      uint64
+	BlockTime time.Time
+	Result    *rpc.GetTransactionResult
+}
+
+// Event is an event decoded from a transaction.
+type Event struct {
+	Program   solana.PublicKey
+	Signature solana.Signature
+	Slot      uint64
+	BlockTime time.Time
+	// Name is the event's name in the program's IDL, e.g.
+	// "TokensMintedEvent".
+	Name string
+	// Data is the decoded event, of a type the Decoder chooses.
+	Data interface{}
+}
+
+// Source reads the transactions of a program.
+type Source interface {
+	// Transactions returns up to limit transactions of program following
+	// after, oldest first. With a nil after it returns the newest ones.
+	Transactions(ctx context.Context, program solana.PublicKey, after *solana.Signature, limit int) ([]Transaction, error)
+}
+
+// Decoder extracts the events of a program from a transaction. It returns
+// none for transactions without any.
+type Decoder interface {
+	Decode(ctx context.Context, program solana.PublicKey, tx Transaction) ([]Event, error)
+}
+
+// Sink receives the events of each transaction, in order.
+type Sink interface {
+	Publish(ctx context.Context, events []Event) error
+}
+
+// SinkFunc adapts a function to a Sink.
+type SinkFunc func(ctx context.Context, events []Event) error
+
+func (f SinkFunc) Publish(ctx context.Context, events []Event) error {
+	return f(ctx, events)
+}
+
+// Options configure an Indexer.
+type Options struct {
+	// Programs are the programs indexed; at least one is required.
+	Programs []solana.PublicKey
+	// After resumes programs from the signature of the last transaction
+	// they had indexed, see Indexer.Cursor.
+	After map[solana.PublicKey]solana.Signature
+	// PollInterval is the time Run waits between polls; default 5s.
+	PollInterval time.Duration
+	// BatchSize is the number of transactions read per program and poll;
+	// default 20.
+	BatchSize int
+	// MaxAttempts is how many polls in a row the sink may reject the
+	// events of a transaction before it is skipped; default 5.
+	MaxAttempts int
+	// OnError is called with the errors Run continues past; by default
+	// they are dropped.
+	OnError func(err error)
+	// OnSkip is called for every transaction the cursor moves past without
+	// its events being published: those the Decoder fails on and those the
+	// Sink rejected MaxAttempts times. By default they are passed to
+	// OnError.
+	OnSkip func(tx Transaction, err error)
+}
+
+const (
+	defaultPollInterval = 5 * time.Second
+	defaultBatchSize    = 20
+	defaultMaxAttempts  = 5
+)
+
+// Indexer polls a Source for the transactions of its programs. Events are
+// delivered at least once: the cursor of a program only moves past a
+// transaction once the sink accepted its events, or once the transaction
+// is skipped, so a transaction that can never be indexed does not hold its
+// program back.
+type Indexer struct {
+	source  Source
+	decoder Decoder
+	sink    Sink
+	opts    Options
+
+	mu      sync.Mutex
+	cursors map[solana.PublicKey]solana.Signature
+	// failed counts the polls in a row the sink rejected the next
+	// transaction of a program.
+	failed map[solana.PublicKey]failedPublish
+}
+
+type failedPublish struct {
+	signature solana.Signature
+	attempts  int
+}
+
+// SkipError is passed to Options.OnError for a skipped transaction when
+// Options.OnSkip is not set.
+type SkipError struct {
+	Program   solana.PublicKey
+	Signature solana.Signature
+	Err       error
+}
+
+func (e *SkipError) Error() string {
+	return fmt.Sprintf("skipped %s of %s: %v", e.Signature, e.Program, e.Err)
+}
+
+func (e *SkipError) Unwrap() error { return e.Err }
+
+func New(source Source, decoder Decoder, sink Sink, opts Options) (*Indexer, error) {
+	if source == nil || decoder == nil || sink == nil {
+		return nil, errors.New("source, decoder and sink are required")
+	}
+	if len(opts.Programs) == 0 {
+		return nil, errors.New("at least one program is required")
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultPollInterval
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultBatchSize
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaultMaxAttempts
+	}
+	cursors := make(map[solana.PublicKey]solana.Signature, len(opts.After))
+	for program, sig := range opts.After {
+		cursors[program] = sig
+	}
+	return &Indexer{
+		source:  source,
+		decoder: decoder,
+		sink:    sink,
+		opts:    opts,
+		cursors: cursors,
+		failed:  make(map[solana.PublicKey]failedPublish),
+	}, nil
+}
+
+// Cursor returns the signature of the last transaction of program whose
+// events were published, false before the first.
+func (i *Indexer) Cursor(program solana.PublicKey) (solana.Signature, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	sig, ok := i.cursors[program]
+	return sig, ok
+}
+
+// Run polls until ctx is done, passing the errors of a poll to
+// Options.OnError, and returns ctx's error.
+func (i *Indexer) Run(ctx context.Context) error {
+	ticker := time.NewTicker(i.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := i.Poll(ctx); err != nil && ctx.Err() == nil && i.opts.OnError != nil {
+			i.opts.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll indexes one batch of transactions of every program and returns how
+// many the cursors moved past. A failing program does not hold back the others; their
+// errors are joined.
+func (i *Indexer) Poll(ctx context.Context) (int, error) {
+	var (
+		indexed int
+		errs    []error
+	)
+	for _, program := range i.opts.Programs {
+		n, err := i.pollProgram(ctx, program)
+		indexed += n
+		if err != nil {
+			errs = append(errs, fmt.Errorf("index %s: %w", program, err))
+		}
+	}
+	return indexed, errors.Join(errs...)
+}
+
+func (i *Indexer) pollProgram(ctx context.Context, program solana.PublicKey) (int, error) {
+	var after *solana.Signature
+	if sig, ok := i.Cursor(program); ok {
+		after = &sig
+	}
+	txs, err := i.source.Transactions(ctx, program, after, i.opts.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for n, tx := range txs {
+		events, err := i.decoder.Decode(ctx, program, tx)
+		if err != nil {
+			// Decoding the same transaction again fails the same way.
+			i.skip(program, tx, fmt.Errorf("decode: %w", err))
+			continue
+		}
+		if len(events) > 0 {
+			if err := i.sink.Publish(ctx, events); err != nil {
+				if ctx.Err() != nil || !i.giveUp(program, tx.Signature) {
+					return n, fmt.Errorf("publish events of %s: %w", tx.Signature, err)
+				}
+				i.skip(program, tx, fmt.Errorf("publish after %d attempts: %w", i.opts.MaxAttempts, err))
+				continue
+			}
+		}
+		i.advance(program, tx.Signature)
+	}
+	return len(txs), nil
+}
+
+// giveUp counts a rejected publish of signature and reports whether it
+// was the last attempt.
+func (i *Indexer) giveUp(program solana.PublicKey, signature solana.Signature) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	f := i.failed[program]
+	if f.signature != signature {
+		f = failedPublish{signature: signature}
+	}
+	f.attempts++
+	i.failed[program] = f
+	return f.attempts >= i.opts.MaxAttempts
+}
+
+func (i *Indexer) skip(program solana.PublicKey, tx Transaction, err error) {
+	switch {
+	case i.opts.OnSkip != nil:
+		i.opts.OnSkip(tx, err)
+	case i.opts.OnError != nil:
+		i.opts.OnError(&SkipError{Program: program, Signature: tx.Signature, Err: err})
+	}
+	i.advance(program, tx.Signature)
+}
+
+func (i *Indexer) advance(program solana.PublicKey, signature solana.Signature) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.cursors[program] = signature
+	delete(i.failed, program)
+}
@@ -0,0 +1,143 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// fakeSource serves txs in order, each after the one before it.
+type fakeSource struct {
+	txs []Transaction
+}
+
+func (s *fakeSource) Transactions(ctx context.Context, program solana.PublicKey, after *solana.Signature, limit int) ([]Transaction, error) {
+	start := 0
+	if after != nil {
+		start = slices.IndexFunc(s.txs, func(tx Transaction) bool { return tx.Signature == *after }) + 1
+	}
+	return s.txs[start:min(len(s.txs), start+limit)], nil
+}
+
+// slotDecoder emits one event per transaction, named after nothing.
+type slotDecoder struct{}
+
+func (slotDecoder) Decode(ctx context.Context, program solana.PublicKey, tx Transaction) ([]Event, error) {
+	return []Event{{Program: program, Signature: tx.Signature, Slot: tx.Slot, Name: "Event"}}, nil
+}
+
+func TestIndexer_Poll(t *testing.T) {
+	ctx := context.Background()
+	program := solana.MustPublicKeyFromBase58("CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc")
+	source := &fakeSource{}
+	for slot := uint64(1); slot <= 5; slot++ {
+		source.txs = append(source.txs, Transaction{Signature: solana.Signature{byte(slot)}, Slot: slot, Result: &rpc.GetTransactionResult{}})
+	}
+
+	var published []uint64
+	failAt := uint64(4)
+	sink := SinkFunc(func(ctx context.Context, events []Event) error {
+		if events[0].Slot == failAt {
+			return errors.New("sink down")
+		}
+		published = append(published, events[0].Slot)
+		return nil
+	})
+	idx, err := New(source, slotDecoder{}, sink, Options{Programs: []solana.PublicKey{program}, BatchSize: 2})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if n, err := idx.Poll(ctx); n != 2 || err != nil {
+		t.Fatalf("Poll() = %d, %v, want 2, nil", n, err)
+	}
+	if n, err := idx.Poll(ctx); n != 1 || err == nil {
+		t.Fatalf("Poll() = %d, %v, want 1 and the sink error", n, err)
+	}
+	if sig, ok := idx.Cursor(program); !ok || sig != source.txs[2].Signature {
+		t.Errorf("Cursor() = %s, %v, want the signature of slot 3", sig, ok)
+	}
+
+	failAt = 0
+	for {
+		n, err := idx.Poll(ctx)
+		if err != nil {
+			t.Fatalf("Poll() error = %v", err)
+		}
+		if n == 0 {
+			break
+		}
+	}
+	if want := []uint64{1, 2, 3, 4, 5}; !slices.Equal(published, want) {
+		t.Errorf("published slots = %v, want %v", published, want)
+	}
+}
+
+func TestNew_Validates(t *testing.T) {
+	sink := SinkFunc(func(ctx context.Context, events []Event) error { return nil })
+	if _, err := New(&fakeSource{}, slotDecoder{}, sink, Options{}); err == nil {
+		t.Error("New() without programs error = nil")
+	}
+	if _, err := New(nil, slotDecoder{}, sink, Options{Programs: []solana.PublicKey{{}}}); err == nil {
+		t.Error("New() without source error = nil")
+	}
+}
+
+// poisonDecoder fails on the transactions of bad slots.
+type poisonDecoder struct {
+	bad map[uint64]bool
+}
+
+func (d poisonDecoder) Decode(ctx context.Context, program solana.PublicKey, tx Transaction) ([]Event, error) {
+	if d.bad[tx.Slot] {
+		return nil, errors.New("malformed event")
+	}
+	return slotDecoder{}.Decode(ctx, program, tx)
+}
+
+func TestIndexer_SkipsPoisonTransactions(t *testing.T) {
+	ctx := context.Background()
+	program := solana.MustPublicKeyFromBase58("CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc")
+	source := &fakeSource{}
+	for slot := uint64(1); slot <= 4; slot++ {
+		source.txs = append(source.txs, Transaction{Signature: solana.Signature{byte(slot)}, Slot: slot, Result: &rpc.GetTransactionResult{}})
+	}
+
+	var published, skipped []uint64
+	sink := SinkFunc(func(ctx context.Context, events []Event) error {
+		if events[0].Slot == 3 {
+			return errors.New("rejected")
+		}
+		published = append(published, events[0].Slot)
+		return nil
+	})
+	idx, err := New(source, poisonDecoder{bad: map[uint64]bool{2: true}}, sink, Options{
+		Programs:    []solana.PublicKey{program},
+		BatchSize:   10,
+		MaxAttempts: 2,
+		OnSkip:      func(tx Transaction, err error) { skipped = append(skipped, tx.Slot) },
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if _, err := idx.Poll(ctx); err == nil {
+		t.Fatal("Poll() error = nil, want the rejected publish")
+	}
+	if sig, _ := idx.Cursor(program); sig != source.txs[1].Signature {
+		t.Errorf("Cursor() = %s, want past the undecodable slot 2", sig)
+	}
+	if _, err := idx.Poll(ctx); err != nil {
+		t.Fatalf("Poll() error = %v, want slot 3 skipped", err)
+	}
+	if want := []uint64{1, 4}; !slices.Equal(published, want) {
+		t.Errorf("published slots = %v, want %v", published, want)
+	}
+	if want := []uint64{2, 3}; !slices.Equal(skipped, want) {
+		t.Errorf("skipped slots = %v, want %v", skipped, want)
+	}
+}
@@ -0,0 +1,131 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	solanaClient "github.com/lugondev/go-indexer-solana-starter/pkg/solana"
+)
+
+// signaturesPageSize is the most signatures getSignaturesForAddress returns
+// per call.
+const signaturesPageSize = 1000
+
+// rpcClient is the part of the pkg/solana Client RPCSource uses.
+type rpcClient interface {
+	GetSignaturesForAddress(ctx context.Context, address solana.PublicKey, limit int, before, until *solana.Signature) ([]*rpc.TransactionSignature, error)
+	GetTransaction(ctx context.Context, signature solana.Signature) (*rpc.GetTransactionResult, error)
+}
+
+// RPCSource reads transactions over JSON-RPC with a pkg/solana Client, so
+// the client's endpoints, budgets and circuit breaker apply.
+type RPCSource struct {
+	client rpcClient
+
+	mu      sync.Mutex
+	backlog map[solana.PublicKey]*signatureBacklog
+}
+
+// signatureBacklog holds the signatures of a program listed after a
+// signature, oldest first, so a program catching up lists each signature
+// once rather than paging back to its cursor on every call.
+type signatureBacklog struct {
+	after solana.Signature
+	sigs  []*rpc.TransactionSignature
+}
+
+func NewRPCSource(client *solanaClient.Client) *RPCSource {
+	return &RPCSource{client: client, backlog: make(map[solana.PublicKey]*signatureBacklog)}
+}
+
+// Transactions fetches the oldest limit transactions of program following
+// after, none skipped however many arrived since. The signatures are listed
+// back to after once and kept until they are returned, so later calls only
+// list the signatures that arrived since. Failed transactions are returned
+// too; their Result.Meta.Err is set.
+func (s *RPCSource) Transactions(ctx context.Context, program solana.PublicKey, after *solana.Signature, limit int) ([]Transaction, error) {
+	var sigs []*rpc.TransactionSignature
+	if after == nil {
+		page, err := s.client.GetSignaturesForAddress(ctx, program, limit, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		slices.Reverse(page)
+		sigs = page
+	} else {
+		backlog, err := s.listAfter(ctx, program, *after, limit)
+		if err != nil {
+			return nil, err
+		}
+		sigs = backlog[:min(len(backlog), limit)]
+	}
+
+	txs := make([]Transaction, 0, len(sigs))
+	for _, sig := range sigs {
+		result, err := s.client.GetTransaction(ctx, sig.Signature)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", sig.Signature, err)
+		}
+		tx := Transaction{Signature: sig.Signature, Slot: sig.Slot, Result: result}
+		if sig.BlockTime != nil {
+			tx.BlockTime = sig.BlockTime.Time().UTC()
+		} else if result != nil && result.BlockTime != nil {
+			tx.BlockTime = result.BlockTime.Time().UTC()
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+// listAfter returns the backlog of program following after, listing the
+// signatures newer than the backlog when it holds fewer than limit.
+func (s *RPCSource) listAfter(ctx context.Context, program solana.PublicKey, after solana.Signature, limit int) ([]*rpc.TransactionSignature, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := s.backlog[program]
+	switch {
+	case b == nil:
+		b = &signatureBacklog{after: after}
+	case b.after == after:
+	default:
+		// The caller moved past some of the backlog, or resumed elsewhere.
+		i := slices.IndexFunc(b.sigs, func(sig *rpc.TransactionSignature) bool { return sig.Signature == after })
+		if i < 0 {
+			b = &signatureBacklog{after: after}
+		} else {
+			b = &signatureBacklog{after: after, sigs: b.sigs[i+1:]}
+		}
+	}
+	s.backlog[program] = b
+	if len(b.sigs) >= limit {
+		return b.sigs, nil
+	}
+
+	until := b.after
+	if len(b.sigs) > 0 {
+		until = b.sigs[len(b.sigs)-1].Signature
+	}
+	var (
+		newer  []*rpc.TransactionSignature
+		before *solana.Signature
+	)
+	for {
+		page, err := s.client.GetSignaturesForAddress(ctx, program, signaturesPageSize, before, &until)
+		if err != nil {
+			return nil, err
+		}
+		newer = append(newer, page...)
+		if len(page) < signaturesPageSize {
+			break
+		}
+		before = &page[len(page)-1].Signature
+	}
+	slices.Reverse(newer)
+	b.sigs = append(b.sigs, newer...)
+	return b.sigs, nil
+}
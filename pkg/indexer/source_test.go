@@ -0,0 +1,75 @@
+package indexer
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// fakeRPC holds the signatures of one program, newest first, as
+// getSignaturesForAddress lists them.
+type fakeRPC struct {
+	sigs   []*rpc.TransactionSignature
+	listed int
+}
+
+func (c *fakeRPC) GetSignaturesForAddress(ctx context.Context, address solana.PublicKey, limit int, before, until *solana.Signature) ([]*rpc.TransactionSignature, error) {
+	start, end := 0, len(c.sigs)
+	if before != nil {
+		start = slices.IndexFunc(c.sigs, func(s *rpc.TransactionSignature) bool { return s.Signature == *before }) + 1
+	}
+	if until != nil {
+		end = slices.IndexFunc(c.sigs, func(s *rpc.TransactionSignature) bool { return s.Signature == *until })
+	}
+	page := c.sigs[start:min(end, start+limit)]
+	c.listed += len(page)
+	return slices.Clone(page), nil
+}
+
+func (c *fakeRPC) GetTransaction(ctx context.Context, signature solana.Signature) (*rpc.GetTransactionResult, error) {
+	return &rpc.GetTransactionResult{}, nil
+}
+
+func TestRPCSource_ListsSignaturesOnce(t *testing.T) {
+	ctx := context.Background()
+	program := solana.MustPublicKeyFromBase58("CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc")
+	client := &fakeRPC{}
+	for slot := uint64(10); slot >= 1; slot-- {
+		client.sigs = append(client.sigs, &rpc.TransactionSignature{Signature: solana.Signature{byte(slot)}, Slot: slot})
+	}
+	source := &RPCSource{client: client, backlog: make(map[solana.PublicKey]*signatureBacklog)}
+
+	var slots []uint64
+	after := solana.Signature{1}
+	for {
+		txs, err := source.Transactions(ctx, program, &after, 3)
+		if err != nil {
+			t.Fatalf("Transactions() error = %v", err)
+		}
+		if len(txs) == 0 {
+			break
+		}
+		for _, tx := range txs {
+			slots = append(slots, tx.Slot)
+		}
+		after = txs[len(txs)-1].Signature
+	}
+	if want := []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10}; !slices.Equal(slots, want) {
+		t.Errorf("slots = %v, want %v", slots, want)
+	}
+	if client.listed != 9 {
+		t.Errorf("signatures listed = %d, want each of the 9 once", client.listed)
+	}
+
+	// A caller resuming from an older signature lists back to it again.
+	txs, err := source.Transactions(ctx, program, &solana.Signature{4}, 2)
+	if err != nil {
+		t.Fatalf("Transactions() error = %v", err)
+	}
+	if len(txs) != 2 || txs[0].Slot != 5 || txs[1].Slot != 6 {
+		t.Errorf("Transactions() after slot 4 = %+v, want slots 5 and 6", txs)
+	}
+}
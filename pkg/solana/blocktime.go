@@ -0,0 +1,97 @@
+package solana
+
+import (
+	"container/list"
+	"context"
+	"log"
+	"sync"
+)
+
+// BlockTimeStore persists slot block times across restarts.
+type BlockTimeStore interface {
+	// GetBlockTime reports false when the slot is not stored.
+	GetBlockTime(ctx context.Context, slot uint64) (int64, bool, error)
+	SaveBlockTime(ctx context.Context, slot uint64, blockTime int64) error
+}
+
+type blockTimeEntry struct {
+	slot      uint64
+	blockTime int64
+}
+
+// BlockTimeCache maps slots to block times: the most recent in an LRU and,
+// with a store, all of them persistently. A slot's block time never
+// changes, so entries are never invalidated.
+type BlockTimeCache struct {
+	size  int
+	store BlockTimeStore
+
+	mu      sync.Mutex
+	entries map[uint64]*list.Element
+	order   *list.List
+}
+
+// NewBlockTimeCache keeps size slots in memory. store may be nil.
+func NewBlockTimeCache(size int, store BlockTimeStore) *BlockTimeCache {
+	return &BlockTimeCache{
+		size:    size,
+		store:   store,
+		entries: make(map[uint64]*list.Element, size),
+		order:   list.New(),
+	}
+}
+
+// Get returns the block time of slot from memory or the store. Store
+// errors are logged and reported as a miss.
+func (c *BlockTimeCache) Get(ctx context.Context, slot uint64) (int64, bool) {
+	c.mu.Lock()
+	if el, ok := c.entries[slot]; ok {
+		c.order.MoveToFront(el)
+		blockTime := el.Value.(blockTimeEntry).blockTime
+		c.mu.Unlock()
+		return blockTime, true
+	}
+	c.mu.Unlock()
+
+	if c.store == nil {
+		return 0, false
+	}
+	blockTime, ok, err := c.store.GetBlockTime(ctx, slot)
+	if err != nil {
+		log.Printf("warning: failed to read block time of slot %d: %v", slot, err)
+		return 0, false
+	}
+	if ok {
+		c.remember(slot, blockTime)
+	}
+	return blockTime, ok
+}
+
+// Put records the block time of slot. It is only written to the store the
+// first time the slot is seen since it left memory.
+func (c *BlockTimeCache) Put(ctx context.Context, slot uint64, blockTime int64) {
+	if !c.remember(slot, blockTime) || c.store == nil {
+		return
+	}
+	if err := c.store.SaveBlockTime(ctx, slot, blockTime); err != nil {
+		log.Printf("warning: failed to save block time of slot %d: %v", slot, err)
+	}
+}
+
+// remember adds slot to the LRU and reports whether it was new.
+func (c *BlockTimeCache) remember(slot uint64, blockTime int64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[slot]; ok {
+		c.order.MoveToFront(el)
+		return false
+	}
+	c.entries[slot] = c.order.PushFront(blockTimeEntry{slot: slot, blockTime: blockTime})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(blockTimeEntry).slot)
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+package solana
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+type memBlockTimes struct {
+	times map[uint64]int64
+	saves int
+}
+
+func (m *memBlockTimes) GetBlockTime(ctx context.Context, slot uint64) (int64, bool, error) {
+	t, ok := m.times[slot]
+	return t, ok, nil
+}
+
+func (m *memBlockTimes) SaveBlockTime(ctx context.Context, slot uint64, blockTime int64) error {
+	m.times[slot] = blockTime
+	m.saves++
+	return nil
+}
+
+func TestBlockTimeCache(t *testing.T) {
+	ctx := context.Background()
+	store := &memBlockTimes{times: map[uint64]int64{5: 500}}
+	cache := NewBlockTimeCache(2, store)
+
+	cache.Put(ctx, 1, 100)
+	cache.Put(ctx, 1, 100)
+	cache.Put(ctx, 2, 200)
+	if store.saves != 2 {
+		t.Errorf("saves = %d, want 2", store.saves)
+	}
+
+	tests := []struct {
+		slot   uint64
+		want   int64
+		wantOK bool
+	}{
+		{1, 100, true},
+		{5, 500, true}, // from the store, evicting slot 2
+		{9, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := cache.Get(ctx, tt.slot)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("Get(%d) = %d, %v, want %d, %v", tt.slot, got, ok, tt.want, tt.wantOK)
+		}
+	}
+	if _, ok := cache.entries[2]; ok {
+		t.Error("slot 2 is still in memory, want it evicted")
+	}
+	if got, ok := cache.Get(ctx, 2); !ok || got != 200 {
+		t.Errorf("Get(2) after eviction = %d, %v, want 200 from the store", got, ok)
+	}
+}
+
+func TestClient_GetBlockTimeCached(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":1700000000}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(srv.URL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetBlockTimeCache(NewBlockTimeCache(10, nil))
+
+	for n := 0; n < 3; n++ {
+		got, err := client.GetBlockTime(context.Background(), 42)
+		if err != nil {
+			t.Fatalf("GetBlockTime() error = %v", err)
+		}
+		if got != 1700000000 {
+			t.Errorf("GetBlockTime() = %d, want 1700000000", got)
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("RPC calls = %d, want 1", got)
+	}
+}
@@ -0,0 +1,74 @@
+package solana
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := NewCircuitBreaker(2, 30*time.Second)
+	b.now = func() time.Time { return now }
+
+	call := func(failed bool) {
+		t.Helper()
+		if err := b.Allow(); err != nil {
+			t.Fatalf("Allow() error = %v, want nil in state %s", err, b.Status().State)
+		}
+		b.Record(failed)
+	}
+
+	call(true)
+	if got := b.Status().State; got != BreakerClosed {
+		t.Errorf("state after 1 failure = %s, want %s", got, BreakerClosed)
+	}
+	call(true)
+	if got := b.Status().State; got != BreakerOpen {
+		t.Errorf("state after 2 failures = %s, want %s", got, BreakerOpen)
+	}
+	if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Allow() while open error = %v, want %v", err, ErrCircuitOpen)
+	}
+
+	// After the cooldown a single probe goes through; a failed probe reopens.
+	now = now.Add(30 * time.Second)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("Allow() after cooldown error = %v", err)
+	}
+	if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("second Allow() while probing error = %v, want %v", err, ErrCircuitOpen)
+	}
+	b.Record(true)
+	if got := b.Status(); got.State != BreakerOpen || !got.OpenedAt.Equal(now) {
+		t.Errorf("status after failed probe = %+v, want open since %v", got, now)
+	}
+
+	now = now.Add(30 * time.Second)
+	call(false)
+	if got := b.Status(); got.State != BreakerClosed || got.ConsecutiveFailures != 0 || got.OpenedAt != nil {
+		t.Errorf("status after successful probe = %+v, want closed", got)
+	}
+}
+
+func TestRPCMetrics(t *testing.T) {
+	m := newRPCMetrics()
+	m.record("getTransaction", 2*time.Second, nil)
+	m.record("getTransaction", time.Second, errors.New("boom"))
+	m.record("getSlot", time.Second, nil)
+	m.reject("getSlot")
+
+	want := []MethodStats{
+		{Method: "getSlot", Calls: 1, Rejected: 1, DurationSeconds: 1},
+		{Method: "getTransaction", Calls: 2, Errors: 1, DurationSeconds: 3},
+	}
+	got := m.snapshot()
+	if len(got) != len(want) {
+		t.Fatalf("snapshot() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("snapshot()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package solana
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// ErrBudgetExhausted is returned without calling any endpoint once the
+// daily request budgets of every endpoint are used up.
+var ErrBudgetExhausted = errors.New("rpc request budget of every endpoint is exhausted")
+
+// Budget caps the requests made to one endpoint. Zero fields are
+// unlimited.
+type Budget struct {
+	PerSecond float64
+	// PerDay resets at midnight UTC.
+	PerDay int64
+}
+
+// Endpoint is an RPC endpoint the client may call. Endpoints are tried in
+// order: a call goes to the first whose budget has room.
+type Endpoint struct {
+	// Name identifies the endpoint in metrics; URLs often embed API keys.
+	Name   string
+	URL    string
+	Budget Budget
+}
+
+// EndpointStatus is the request budget consumption of one endpoint.
+type EndpointStatus struct {
+	Name      string  `json:"name"`
+	PerSecond float64 `json:"per_second,omitempty"`
+	PerDay    int64   `json:"per_day,omitempty"`
+	// Requests counts the calls made since start, UsedToday those of the
+	// current UTC day.
+	Requests  int64 `json:"requests"`
+	UsedToday int64 `json:"used_today"`
+	// Skipped counts the times a call found no room in the budget and
+	// went to another endpoint or waited.
+	Skipped int64 `json:"skipped"`
+}
+
+type endpoint struct {
+	name   string
+	rpc    *rpc.Client
+	budget Budget
+
+	mu       sync.Mutex
+	tokens   float64
+	refilled time.Time
+	day      time.Time
+	status   EndpointStatus
+}
+
+func newEndpoint(e Endpoint) *endpoint {
+	return &endpoint{
+		name:   e.Name,
+		rpc:    rpc.New(e.URL),
+		budget: e.Budget,
+		tokens: math.Max(e.Budget.PerSecond, 1),
+		status: EndpointStatus{Name: e.Name, PerSecond: e.Budget.PerSecond, PerDay: e.Budget.PerDay},
+	}
+}
+
+// reserve takes one request from the budget. Otherwise it reports how long
+// until the per second budget has room, or ok false when the daily budget
+// is used up.
+func (e *endpoint) reserve(now time.Time) (wait time.Duration, ok bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if day := now.UTC().Truncate(24 * time.Hour); !day.Equal(e.day) {
+		e.day, e.status.UsedToday = day, 0
+	}
+	if e.budget.PerDay > 0 && e.status.UsedToday >= e.budget.PerDay {
+		e.status.Skipped++
+		return 0, false
+	}
+	if e.budget.PerSecond > 0 {
+		// Up to one second of requests can be made in a burst.
+		if !e.refilled.IsZero() {
+			e.tokens = math.Min(e.tokens+now.Sub(e.refilled).Seconds()*e.budget.PerSecond, math.Max(e.budget.PerSecond, 1))
+		}
+		e.refilled = now
+		if e.tokens < 1 {
+			e.status.Skipped++
+			return time.Duration((1 - e.tokens) / e.budget.PerSecond * float64(time.Second)), true
+		}
+		e.tokens--
+	}
+	e.status.Requests++
+	e.status.UsedToday++
+	return 0, true
+}
+
+func (e *endpoint) snapshot(now time.Time) EndpointStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	status := e.status
+	if !now.UTC().Truncate(24 * time.Hour).Equal(e.day) {
+		status.UsedToday = 0
+	}
+	return status
+}
@@ -0,0 +1,100 @@
+package solana
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestEndpoint_Reserve(t *testing.T) {
+	now := time.Date(2026, 1, 1, 23, 59, 59, 0, time.UTC)
+	e := newEndpoint(Endpoint{Name: "primary", URL: "http://rpc.test", Budget: Budget{PerSecond: 2, PerDay: 3}})
+
+	for n := 0; n < 2; n++ {
+		if wait, ok := e.reserve(now); wait != 0 || !ok {
+			t.Fatalf("reserve() #%d = %v, %v, want room", n+1, wait, ok)
+		}
+	}
+	if wait, ok := e.reserve(now); wait != 500*time.Millisecond || !ok {
+		t.Errorf("reserve() over the per second budget = %v, %v, want 500ms, true", wait, ok)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if wait, ok := e.reserve(now); wait != 0 || !ok {
+		t.Errorf("reserve() after a refill = %v, %v, want room", wait, ok)
+	}
+	now = now.Add(time.Second / 4)
+	if _, ok := e.reserve(now); ok {
+		t.Errorf("reserve() over the daily budget = ok, want exhausted")
+	}
+
+	now = now.Add(time.Second)
+	if wait, ok := e.reserve(now); wait != 0 || !ok {
+		t.Errorf("reserve() on the next day = %v, %v, want room", wait, ok)
+	}
+	status := e.snapshot(now)
+	if status.Requests != 4 || status.UsedToday != 1 || status.Skipped != 2 {
+		t.Errorf("snapshot() = %+v, want 4 requests, 1 used today, 2 skipped", status)
+	}
+}
+
+func slotServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":42}`))
+	}))
+}
+
+func TestClient_SwitchesEndpointOnExhaustedBudget(t *testing.T) {
+	primary, fallback := slotServer(), slotServer()
+	defer primary.Close()
+	defer fallback.Close()
+
+	c, err := NewClientWithEndpoints([]Endpoint{
+		{Name: "primary", URL: primary.URL, Budget: Budget{PerDay: 2}},
+		{Name: "fallback1", URL: fallback.URL, Budget: Budget{PerDay: 1}},
+	}, "")
+	if err != nil {
+		t.Fatalf("NewClientWithEndpoints() error = %v", err)
+	}
+
+	for n := 0; n < 3; n++ {
+		if _, err := c.GetSlot(context.Background(), rpc.CommitmentFinalized); err != nil {
+			t.Fatalf("GetSlot() #%d error = %v", n+1, err)
+		}
+	}
+	if _, err := c.GetSlot(context.Background(), rpc.CommitmentFinalized); !errors.Is(err, ErrBudgetExhausted) {
+		t.Errorf("GetSlot() error = %v, want ErrBudgetExhausted", err)
+	}
+	status := c.Status()
+	if len(status.Endpoints) != 2 || status.Endpoints[0].UsedToday != 2 || status.Endpoints[1].UsedToday != 1 {
+		t.Errorf("Status().Endpoints = %+v", status.Endpoints)
+	}
+	if len(status.Methods) != 1 || status.Methods[0].Rejected != 1 {
+		t.Errorf("Status().Methods = %+v, want 1 rejected getSlot", status.Methods)
+	}
+}
+
+func TestNewClientWithEndpoints_Invalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoints []Endpoint
+	}{
+		{"none", nil},
+		{"no URL", []Endpoint{{Name: "primary"}}},
+		{"duplicate name", []Endpoint{{Name: "a", URL: "http://a.test"}, {Name: "a", URL: "http://b.test"}}},
+		{"negative budget", []Endpoint{{Name: "a", URL: "http://a.test", Budget: Budget{PerDay: -1}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewClientWithEndpoints(tt.endpoints, ""); err == nil {
+				t.Errorf("NewClientWithEndpoints() error = nil, want an error")
+			}
+		})
+	}
+}
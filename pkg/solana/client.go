@@ -2,29 +2,182 @@ package solana
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/gagliardetto/solana-go"
 	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/ws"
 )
 
+var ErrAccountNotFound = errors.New("account not found")
+
 type Client struct {
-	rpc *rpc.Client
+	endpoints []*endpoint
+	wsURL     string
+	metrics   *rpcMetrics
+	breaker   *CircuitBreaker
+	scheduler *Scheduler
+	now       func() time.Time
+	// blockTimes caches the block times GetTransaction and GetBlockTime
+	// return; nil disables it.
+	blockTimes *BlockTimeCache
 }
 
 func NewClient(rpcURL, wsURL string) (*Client, error) {
 	if rpcURL == "" {
 		return nil, fmt.Errorf("rpcURL cannot be empty")
 	}
+	return NewClientWithEndpoints([]Endpoint{{Name: "primary", URL: rpcURL}}, wsURL)
+}
+
+// NewClientWithEndpoints returns a client calling the first of endpoints
+// whose budget has room, so a provider whose quota is used up is switched
+// away from until it has room again.
+func NewClientWithEndpoints(endpoints []Endpoint, wsURL string) (*Client, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("at least one endpoint is required")
+	}
+	c := &Client{wsURL: wsURL, metrics: newRPCMetrics(), now: time.Now}
+	seen := make(map[string]bool, len(endpoints))
+	for _, e := range endpoints {
+		if e.Name == "" || e.URL == "" {
+			return nil, fmt.Errorf("endpoint name and URL cannot be empty")
+		}
+		if seen[e.Name] {
+			return nil, fmt.Errorf("duplicate endpoint %q", e.Name)
+		}
+		if e.Budget.PerSecond < 0 || e.Budget.PerDay < 0 {
+			return nil, fmt.Errorf("endpoint %s: budget cannot be negative", e.Name)
+		}
+		seen[e.Name] = true
+		c.endpoints = append(c.endpoints, newEndpoint(e))
+	}
+	return c, nil
+}
+
+// SetCircuitBreaker makes the client stop calling the endpoint while b is
+// open. Call it before the client is used.
+func (c *Client) SetCircuitBreaker(b *CircuitBreaker) {
+	c.breaker = b
+}
+
+// SetScheduler makes every call wait for a slot of s at the priority of its
+// context. Call it before the client is used.
+func (c *Client) SetScheduler(s *Scheduler) {
+	c.scheduler = s
+}
+
+// SetBlockTimeCache makes GetBlockTime answer from cache, filled with the
+// block times of the transactions and slots fetched. Call it before the
+// client is used.
+func (c *Client) SetBlockTimeCache(cache *BlockTimeCache) {
+	c.blockTimes = cache
+}
+
+// Status returns the per method stats, the budget consumption of every
+// endpoint and the circuit breaker state.
+func (c *Client) Status() RPCStatus {
+	status := RPCStatus{Methods: c.metrics.snapshot()}
+	for _, e := range c.endpoints {
+		status.Endpoints = append(status.Endpoints, e.snapshot(c.now()))
+	}
+	if c.breaker != nil {
+		breaker := c.breaker.Status()
+		status.Circuit = &breaker
+	}
+	if c.scheduler != nil {
+		scheduler := c.scheduler.Status()
+		status.Scheduler = &scheduler
+	}
+	return status
+}
+
+// observe makes an RPC call, once the scheduler grants it a slot, through
+// the circuit breaker on the first endpoint with budget left and records
+// its metrics. Not-found answers and calls cut short by ctx do not count as
+// endpoint failures.
+func (c *Client) observe(ctx context.Context, method string, call func(client *rpc.Client) error) (err error) {
+	if c.scheduler != nil {
+		priority := priorityOf(ctx)
+		if err := c.scheduler.Acquire(ctx, priority); err != nil {
+			return err
+		}
+		defer func() {
+			c.scheduler.Release(priority, callFailed(ctx, err))
+		}()
+	}
+	if c.breaker != nil {
+		if err := c.breaker.Allow(); err != nil {
+			c.metrics.reject(method)
+			return err
+		}
+	}
+	e, err := c.endpoint(ctx)
+	if err != nil {
+		if c.breaker != nil {
+			c.breaker.Cancel()
+		}
+		c.metrics.reject(method)
+		return err
+	}
+
+	start := time.Now()
+	err = call(e.rpc)
+	c.metrics.record(method, time.Since(start), err)
+
+	if c.breaker != nil {
+		c.breaker.Record(callFailed(ctx, err))
+	}
+	return err
+}
 
-	client := rpc.New(rpcURL)
-	return &Client{
-		rpc: client,
-	}, nil
+func callFailed(ctx context.Context, err error) bool {
+	return err != nil && !errors.Is(err, rpc.ErrNotFound) && !errors.Is(err, ErrCircuitOpen) &&
+		!errors.Is(err, ErrBudgetExhausted) && ctx.Err() == nil
 }
 
-func (c *Client) GetSlot(ctx context.Context) (uint64, error) {
-	slot, err := c.rpc.GetSlot(ctx, rpc.CommitmentConfirmed)
+// endpoint returns the first endpoint with budget left. When every
+// endpoint with a daily budget left is over its per second budget, it waits
+// for the first to have room.
+func (c *Client) endpoint(ctx context.Context) (*endpoint, error) {
+	for {
+		var wait time.Duration
+		now := c.now()
+		for _, e := range c.endpoints {
+			w, ok := e.reserve(now)
+			if !ok {
+				continue
+			}
+			if w == 0 {
+				return e, nil
+			}
+			if wait == 0 || w < wait {
+				wait = w
+			}
+		}
+		if wait == 0 {
+			return nil, ErrBudgetExhausted
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// GetSlot returns the slot the cluster has reached at commitment.
+func (c *Client) GetSlot(ctx context.Context, commitment rpc.CommitmentType) (uint64, error) {
+	var slot uint64
+	err := c.observe(ctx, "getSlot", func(client *rpc.Client) (err error) {
+		slot, err = client.GetSlot(ctx, commitment)
+		return err
+	})
 	if err != nil {
 		return 0, fmt.Errorf("get slot: %w", err)
 	}
@@ -32,21 +185,40 @@ func (c *Client) GetSlot(ctx context.Context) (uint64, error) {
 }
 
 func (c *Client) GetTransaction(ctx context.Context, signature solana.Signature) (*rpc.GetTransactionResult, error) {
-	out, err := c.rpc.GetTransaction(
-		ctx,
-		signature,
-		&rpc.GetTransactionOpts{
-			Encoding:                       solana.EncodingBase64,
-			Commitment:                     rpc.CommitmentConfirmed,
-			MaxSupportedTransactionVersion: nil,
-		},
-	)
+	var out *rpc.GetTransactionResult
+	err := c.observe(ctx, "getTransaction", func(client *rpc.Client) (err error) {
+		out, err = client.GetTransaction(
+			ctx,
+			signature,
+			&rpc.GetTransactionOpts{
+				Encoding:                       solana.EncodingBase64,
+				Commitment:                     rpc.CommitmentConfirmed,
+				MaxSupportedTransactionVersion: &rpc.MaxSupportedTransactionVersion0,
+			},
+		)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("get transaction: %w", err)
 	}
+	if c.blockTimes != nil && out != nil && out.BlockTime != nil {
+		c.blockTimes.Put(ctx, out.Slot, int64(*out.BlockTime))
+	}
 	return out, nil
 }
 
+// ResolveAccountKeys returns the full account list of a transaction: the
+// static message keys followed by the writable and then the read-only
+// addresses loaded from address lookup tables, matching the indexes used by
+// compiled instructions in v0 transactions.
+func ResolveAccountKeys(message solana.Message, loaded rpc.LoadedAddresses) []solana.PublicKey {
+	keys := make([]solana.PublicKey, 0, len(message.AccountKeys)+len(loaded.Writable)+len(loaded.ReadOnly))
+	keys = append(keys, message.AccountKeys...)
+	keys = append(keys, loaded.Writable...)
+	keys = append(keys, loaded.ReadOnly...)
+	return keys
+}
+
 func (c *Client) GetSignaturesForAddress(ctx context.Context, address solana.PublicKey, limit int, before, until *solana.Signature) ([]*rpc.TransactionSignature, error) {
 	opts := &rpc.GetSignaturesForAddressOpts{
 		Limit: &limit,
@@ -58,7 +230,11 @@ func (c *Client) GetSignaturesForAddress(ctx context.Context, address solana.Pub
 		opts.Until = *until
 	}
 
-	sigs, err := c.rpc.GetSignaturesForAddress(ctx, address)
+	var sigs []*rpc.TransactionSignature
+	err := c.observe(ctx, "getSignaturesForAddress", func(client *rpc.Client) (err error) {
+		sigs, err = client.GetSignaturesForAddressWithOpts(ctx, address, opts)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("get signatures for address: %w", err)
 	}
@@ -66,14 +242,139 @@ func (c *Client) GetSignaturesForAddress(ctx context.Context, address solana.Pub
 }
 
 func (c *Client) GetBlockTime(ctx context.Context, slot uint64) (int64, error) {
-	blockTime, err := c.rpc.GetBlockTime(ctx, slot)
+	if c.blockTimes != nil {
+		if blockTime, ok := c.blockTimes.Get(ctx, slot); ok {
+			return blockTime, nil
+		}
+	}
+
+	var blockTime *solana.UnixTimeSeconds
+	err := c.observe(ctx, "getBlockTime", func(client *rpc.Client) (err error) {
+		blockTime, err = client.GetBlockTime(ctx, slot)
+		return err
+	})
 	if err != nil {
 		return 0, fmt.Errorf("get block time: %w", err)
 	}
 	if blockTime == nil {
 		return 0, fmt.Errorf("block time is nil")
 	}
-	return blockTime.Time().Unix(), nil
+	if c.blockTimes != nil {
+		c.blockTimes.Put(ctx, slot, int64(*blockTime))
+	}
+	return int64(*blockTime), nil
+}
+
+// GetAccountData returns the raw data of account and the slot it was read at.
+func (c *Client) GetAccountData(ctx context.Context, account solana.PublicKey) ([]byte, uint64, error) {
+	return c.getAccountData(ctx, account, nil)
+}
+
+// getAccountData reads the data of account, only slice of it when not nil.
+func (c *Client) getAccountData(ctx context.Context, account solana.PublicKey, slice *rpc.DataSlice) ([]byte, uint64, error) {
+	var out *rpc.GetAccountInfoResult
+	err := c.observe(ctx, "getAccountInfo", func(client *rpc.Client) (err error) {
+		out, err = client.GetAccountInfoWithOpts(ctx, account, &rpc.GetAccountInfoOpts{
+			Encoding:   solana.EncodingBase64,
+			Commitment: rpc.CommitmentConfirmed,
+			DataSlice:  slice,
+		})
+		return err
+	})
+	if errors.Is(err, rpc.ErrNotFound) {
+		return nil, 0, fmt.Errorf("%w: %s", ErrAccountNotFound, account)
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("get account info: %w", err)
+	}
+	if out == nil || out.Value == nil || out.Value.Data == nil {
+		return nil, 0, fmt.Errorf("%w: %s", ErrAccountNotFound, account)
+	}
+	return out.Value.Data.GetBinary(), out.Context.Slot, nil
+}
+
+// ProgramAccount is the size and balance of an account owned by a program.
+type ProgramAccount struct {
+	Address  solana.PublicKey
+	Lamports uint64
+	Size     uint64
+}
+
+// GetProgramAccounts lists the accounts owned by program. The RPC has no way
+// to return a size without the data, so every account is downloaded whole.
+func (c *Client) GetProgramAccounts(ctx context.Context, program solana.PublicKey) ([]ProgramAccount, error) {
+	var out rpc.GetProgramAccountsResult
+	err := c.observe(ctx, "getProgramAccounts", func(client *rpc.Client) (err error) {
+		out, err = client.GetProgramAccountsWithOpts(ctx, program, &rpc.GetProgramAccountsOpts{
+			Encoding:   solana.EncodingBase64,
+			Commitment: rpc.CommitmentConfirmed,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get program accounts: %w", err)
+	}
+
+	accounts := make([]ProgramAccount, 0, len(out))
+	for _, keyed := range out {
+		if keyed == nil || keyed.Account == nil {
+			continue
+		}
+		account := ProgramAccount{Address: keyed.Pubkey, Lamports: keyed.Account.Lamports}
+		if keyed.Account.Data != nil {
+			account.Size = uint64(len(keyed.Account.Data.GetBinary()))
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, nil
+}
+
+// GetRentExemptMinimum returns the balance an account of size bytes needs to
+// be rent exempt.
+func (c *Client) GetRentExemptMinimum(ctx context.Context, size uint64) (uint64, error) {
+	var lamports uint64
+	err := c.observe(ctx, "getMinimumBalanceForRentExemption", func(client *rpc.Client) (err error) {
+		lamports, err = client.GetMinimumBalanceForRentExemption(ctx, size, rpc.CommitmentConfirmed)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("get minimum balance for rent exemption: %w", err)
+	}
+	return lamports, nil
+}
+
+// SubscribeAccount streams updates of account to handler over the websocket
+// endpoint until ctx is cancelled or the subscription fails.
+func (c *Client) SubscribeAccount(ctx context.Context, account solana.PublicKey, handler func(slot uint64, data []byte)) error {
+	if c.wsURL == "" {
+		return fmt.Errorf("websocket URL is not configured")
+	}
+
+	wsClient, err := ws.Connect(ctx, c.wsURL)
+	if err != nil {
+		return fmt.Errorf("connect websocket: %w", err)
+	}
+	defer wsClient.Close()
+
+	sub, err := wsClient.AccountSubscribe(account, rpc.CommitmentConfirmed)
+	if err != nil {
+		return fmt.Errorf("subscribe account: %w", err)
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		result, err := sub.Recv(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("receive account update: %w", err)
+		}
+		if result == nil || result.Value.Data == nil {
+			continue
+		}
+		handler(result.Context.Slot, result.Value.Data.GetBinary())
+	}
 }
 
 type Block struct {
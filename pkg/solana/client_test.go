@@ -2,6 +2,9 @@ package solana
 
 import (
 	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
 )
 
 func TestNewClient(t *testing.T) {
@@ -44,3 +47,27 @@ func TestNewClient(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveAccountKeys(t *testing.T) {
+	static := []solana.PublicKey{{1}, {2}}
+	loaded := rpc.LoadedAddresses{
+		Writable: solana.PublicKeySlice{{3}},
+		ReadOnly: solana.PublicKeySlice{{4}, {5}},
+	}
+
+	got := ResolveAccountKeys(solana.Message{AccountKeys: static}, loaded)
+	want := []solana.PublicKey{{1}, {2}, {3}, {4}, {5}}
+	if len(got) != len(want) {
+		t.Fatalf("ResolveAccountKeys() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].Equals(want[i]) {
+			t.Errorf("keys[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+
+	legacy := ResolveAccountKeys(solana.Message{AccountKeys: static}, rpc.LoadedAddresses{})
+	if len(legacy) != len(static) {
+		t.Errorf("legacy keys = %v, want %v", legacy, static)
+	}
+}
@@ -0,0 +1,77 @@
+package solana
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// MethodStats are the cumulative counts of one RPC method since start.
+type MethodStats struct {
+	Method string `json:"method"`
+	Calls  int64  `json:"calls"`
+	Errors int64  `json:"errors"`
+	// Rejected calls were not made because the circuit breaker was open or
+	// every endpoint's budget was exhausted.
+	Rejected        int64   `json:"rejected"`
+	DurationSeconds float64 `json:"duration_seconds"`
+}
+
+// RPCStatus is the state of the client's RPC endpoints. Circuit is nil when
+// the client has no circuit breaker.
+type RPCStatus struct {
+	Methods   []MethodStats    `json:"methods"`
+	Endpoints []EndpointStatus `json:"endpoints,omitempty"`
+	Circuit   *BreakerStatus   `json:"circuit,omitempty"`
+	// Scheduler is nil when calls are not scheduled.
+	Scheduler *SchedulerStatus `json:"scheduler,omitempty"`
+}
+
+type rpcMetrics struct {
+	mu      sync.Mutex
+	methods map[string]*MethodStats
+}
+
+func newRPCMetrics() *rpcMetrics {
+	return &rpcMetrics{methods: make(map[string]*MethodStats)}
+}
+
+func (m *rpcMetrics) method(name string) *MethodStats {
+	s, ok := m.methods[name]
+	if !ok {
+		s = &MethodStats{Method: name}
+		m.methods[name] = s
+	}
+	return s
+}
+
+func (m *rpcMetrics) record(name string, elapsed time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := m.method(name)
+	s.Calls++
+	s.DurationSeconds += elapsed.Seconds()
+	if err != nil {
+		s.Errors++
+	}
+}
+
+func (m *rpcMetrics) reject(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.method(name).Rejected++
+}
+
+// snapshot returns the stats of every method called so far, by name.
+func (m *rpcMetrics) snapshot() []MethodStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := make([]MethodStats, 0, len(m.methods))
+	for _, s := range m.methods {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Method < stats[j].Method })
+	return stats
+}
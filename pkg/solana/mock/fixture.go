@@ -0,0 +1,80 @@
+// Package mock serves recorded Solana JSON-RPC responses, so the indexer
+// can be run and tested offline against real transactions.
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Fixture is the recorded history of one address: its signatures as
+// returned by getSignaturesForAddress, newest first, and the
+// getTransaction result of each, as the indexer requests them (base64
+// encoded, confirmed commitment).
+type Fixture struct {
+	Address      string                     `json:"address"`
+	Signatures   []SignatureInfo            `json:"signatures"`
+	Transactions map[string]json.RawMessage `json:"transactions"`
+}
+
+// SignatureInfo is an entry of a getSignaturesForAddress result.
+type SignatureInfo struct {
+	Signature          string          `json:"signature"`
+	Slot               uint64          `json:"slot"`
+	Err                json.RawMessage `json:"err"`
+	Memo               *string         `json:"memo"`
+	BlockTime          *int64          `json:"blockTime"`
+	ConfirmationStatus string          `json:"confirmationStatus,omitempty"`
+}
+
+// ReadFixture reads a fixture from a JSON file.
+func ReadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read fixture: %w", err)
+	}
+	var f Fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("decode fixture %s: %w", path, err)
+	}
+	for _, s := range f.Signatures {
+		if _, ok := f.Transactions[s.Signature]; !ok {
+			return nil, fmt.Errorf("fixture %s: no transaction for signature %s", path, s.Signature)
+		}
+	}
+	return &f, nil
+}
+
+// LoadDir reads every *.json fixture in dir, in file name order.
+func LoadDir(dir string) ([]*Fixture, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("list fixtures: %w", err)
+	}
+	sort.Strings(paths)
+
+	fixtures := make([]*Fixture, 0, len(paths))
+	for _, path := range paths {
+		f, err := ReadFixture(path)
+		if err != nil {
+			return nil, err
+		}
+		fixtures = append(fixtures, f)
+	}
+	return fixtures, nil
+}
+
+// WriteFile writes the fixture as indented JSON, so recordings diff well.
+func (f *Fixture) WriteFile(path string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode fixture: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("write fixture: %w", err)
+	}
+	return nil
+}
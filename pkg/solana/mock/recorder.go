@@ -0,0 +1,97 @@
+package mock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Recorder records fixtures from a live RPC endpoint. It stores the raw
+// responses, so fixtures replay exactly what the endpoint sent.
+type Recorder struct {
+	rpcURL     string
+	httpClient *http.Client
+}
+
+func NewRecorder(rpcURL string) *Recorder {
+	return &Recorder{
+		rpcURL:     rpcURL,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Record fetches the latest limit signatures of address and their
+// transactions.
+func (r *Recorder) Record(ctx context.Context, address string, limit int) (*Fixture, error) {
+	f := &Fixture{Address: address, Transactions: make(map[string]json.RawMessage)}
+	err := r.call(ctx, "getSignaturesForAddress", []interface{}{
+		address,
+		map[string]interface{}{"limit": limit, "commitment": "confirmed"},
+	}, &f.Signatures)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, info := range f.Signatures {
+		var tx json.RawMessage
+		err := r.call(ctx, "getTransaction", []interface{}{
+			info.Signature,
+			map[string]interface{}{
+				"encoding":                       "base64",
+				"commitment":                     "confirmed",
+				"maxSupportedTransactionVersion": 0,
+			},
+		}, &tx)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %s: %w", info.Signature, err)
+		}
+		f.Transactions[info.Signature] = tx
+	}
+	return f, nil
+}
+
+func (r *Recorder) call(ctx context.Context, method string, params []interface{}, out interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal %s request: %w", method, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.rpcURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create %s request: %w", method, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("send %s request: %w", method, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s failed with status %d: %s", method, resp.StatusCode, msg)
+	}
+
+	var rpcResp struct {
+		Result json.RawMessage `json:"result"`
+		Error  *rpcError       `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return fmt.Errorf("decode %s response: %w", method, err)
+	}
+	if rpcResp.Error != nil {
+		return fmt.Errorf("%s failed with error %d: %s", method, rpcResp.Error.Code, rpcResp.Error.Message)
+	}
+	if err := json.Unmarshal(rpcResp.Result, out); err != nil {
+		return fmt.Errorf("decode %s result: %w", method, err)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package mock
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+)
+
+const maxSignaturesLimit = 1000
+
+// Server answers the JSON-RPC methods the indexer uses from fixtures:
+// getSignaturesForAddress honours limit, before and until like a validator,
+// getTransaction returns the recorded result or null, and getSlot the
+// highest recorded slot. Other methods fail with "method not found".
+type Server struct {
+	signatures   map[string][]SignatureInfo
+	transactions map[string]json.RawMessage
+	blockTimes   map[uint64]int64
+	slot         uint64
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+// NewServer serves fixtures. Fixtures of the same address are concatenated
+// in order, so later fixtures should hold older signatures.
+func NewServer(fixtures ...*Fixture) *Server {
+	s := &Server{
+		signatures:   make(map[string][]SignatureInfo),
+		transactions: make(map[string]json.RawMessage),
+		blockTimes:   make(map[uint64]int64),
+		calls:        make(map[string]int),
+	}
+	for _, f := range fixtures {
+		s.signatures[f.Address] = append(s.signatures[f.Address], f.Signatures...)
+		for sig, tx := range f.Transactions {
+			s.transactions[sig] = tx
+		}
+		for _, info := range f.Signatures {
+			s.slot = max(s.slot, info.Slot)
+			if info.BlockTime != nil {
+				s.blockTimes[info.Slot] = *info.BlockTime
+			}
+		}
+	}
+	return s
+}
+
+// Calls returns how often method was called.
+func (s *Server) Calls(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method]
+}
+
+type rpcRequest struct {
+	JSONRPC string            `json:"jsonrpc"`
+	ID      json.RawMessage   `json:"id"`
+	Method  string            `json:"method"`
+	Params  []json.RawMessage `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "JSON-RPC requests must be POSTed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req rpcRequest
+	resp := map[string]interface{}{"jsonrpc": "2.0"}
+	if err := json.Unmarshal(body, &req); err != nil {
+		resp["id"] = nil
+		resp["error"] = rpcError{Code: -32700, Message: "parse error"}
+	} else {
+		resp["id"] = req.ID
+		result, rpcErr := s.call(req.Method, req.Params)
+		if rpcErr != nil {
+			resp["error"] = rpcErr
+		} else {
+			resp["result"] = result
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (s *Server) call(method string, params []json.RawMessage) (interface{}, *rpcError) {
+	s.mu.Lock()
+	s.calls[method]++
+	s.mu.Unlock()
+
+	switch method {
+	case "getHealth":
+		return "ok", nil
+	case "getVersion":
+		return map[string]interface{}{"solana-core": "mock", "feature-set": 0}, nil
+	case "getSlot":
+		return s.slot, nil
+	case "getBlockTime":
+		var slot uint64
+		if len(params) < 1 || json.Unmarshal(params[0], &slot) != nil {
+			return nil, invalidParams("expected a slot")
+		}
+		blockTime, ok := s.blockTimes[slot]
+		if !ok {
+			return nil, &rpcError{Code: -32004, Message: "Block not available for slot"}
+		}
+		return blockTime, nil
+	case "getSignaturesForAddress":
+		return s.getSignaturesForAddress(params)
+	case "getTransaction":
+		var signature string
+		if len(params) < 1 || json.Unmarshal(params[0], &signature) != nil {
+			return nil, invalidParams("expected a base58 signature")
+		}
+		if tx, ok := s.transactions[signature]; ok {
+			return tx, nil
+		}
+		return nil, nil
+	default:
+		return nil, &rpcError{Code: -32601, Message: "method not found: " + method}
+	}
+}
+
+func invalidParams(msg string) *rpcError {
+	return &rpcError{Code: -32602, Message: "invalid params: " + msg}
+}
+
+func (s *Server) getSignaturesForAddress(params []json.RawMessage) (interface{}, *rpcError) {
+	var address string
+	if len(params) < 1 || json.Unmarshal(params[0], &address) != nil {
+		return nil, invalidParams("expected a base58 address")
+	}
+	var opts struct {
+		Limit  int    `json:"limit"`
+		Before string `json:"before"`
+		Until  string `json:"until"`
+	}
+	if len(params) > 1 && json.Unmarshal(params[1], &opts) != nil {
+		return nil, invalidParams("malformed config object")
+	}
+	if opts.Limit <= 0 || opts.Limit > maxSignaturesLimit {
+		opts.Limit = maxSignaturesLimit
+	}
+
+	sigs := s.signatures[address]
+	start := 0
+	if opts.Before != "" {
+		start = -1
+		for i, info := range sigs {
+			if info.Signature == opts.Before {
+				start = i + 1
+				break
+			}
+		}
+		if start < 0 {
+			return []SignatureInfo{}, nil
+		}
+	}
+
+	out := make([]SignatureInfo, 0, min(len(sigs)-start, opts.Limit))
+	for _, info := range sigs[start:] {
+		if info.Signature == opts.Until || len(out) == opts.Limit {
+			break
+		}
+		out = append(out, info)
+	}
+	return out, nil
+}
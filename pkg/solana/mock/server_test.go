@@ -0,0 +1,141 @@
+package mock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func rpcCall(t *testing.T, handler http.Handler, method string, params ...interface{}) (json.RawMessage, *rpcError) {
+	t.Helper()
+	body, _ := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": method, "params": params})
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body)))
+
+	var resp struct {
+		Result json.RawMessage `json:"result"`
+		Error  *rpcError       `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode %s response: %v", method, err)
+	}
+	return resp.Result, resp.Error
+}
+
+func testFixture() *Fixture {
+	blockTime := int64(1767225600)
+	f := &Fixture{Address: "Program1111", Transactions: make(map[string]json.RawMessage)}
+	for i, sig := range []string{"sigD", "sigC", "sigB", "sigA"} {
+		f.Signatures = append(f.Signatures, SignatureInfo{Signature: sig, Slot: uint64(104 - i), BlockTime: &blockTime})
+		f.Transactions[sig] = json.RawMessage(fmt.Sprintf(`{"slot":%d}`, 104-i))
+	}
+	return f
+}
+
+func TestServer_GetSignaturesForAddress(t *testing.T) {
+	srv := NewServer(testFixture())
+
+	tests := []struct {
+		name string
+		opts map[string]interface{}
+		want []string
+	}{
+		{"all", nil, []string{"sigD", "sigC", "sigB", "sigA"}},
+		{"limit", map[string]interface{}{"limit": 2}, []string{"sigD", "sigC"}},
+		{"before", map[string]interface{}{"before": "sigC"}, []string{"sigB", "sigA"}},
+		{"until", map[string]interface{}{"until": "sigB"}, []string{"sigD", "sigC"}},
+		{"before and until", map[string]interface{}{"before": "sigD", "until": "sigA"}, []string{"sigC", "sigB"}},
+		{"unknown before", map[string]interface{}{"before": "sigX"}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := []interface{}{"Program1111"}
+			if tt.opts != nil {
+				params = append(params, tt.opts)
+			}
+			raw, rpcErr := rpcCall(t, srv, "getSignaturesForAddress", params...)
+			if rpcErr != nil {
+				t.Fatalf("getSignaturesForAddress error = %s", rpcErr.Message)
+			}
+			var infos []SignatureInfo
+			if err := json.Unmarshal(raw, &infos); err != nil {
+				t.Fatalf("decode result: %v", err)
+			}
+			got := make([]string, len(infos))
+			for i, info := range infos {
+				got[i] = info.Signature
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("signatures = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("signatures = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestServer(t *testing.T) {
+	srv := NewServer(testFixture())
+
+	if raw, _ := rpcCall(t, srv, "getSlot"); string(raw) != "104" {
+		t.Errorf("getSlot = %s, want 104", raw)
+	}
+	if raw, _ := rpcCall(t, srv, "getBlockTime", 103); string(raw) != "1767225600" {
+		t.Errorf("getBlockTime = %s, want 1767225600", raw)
+	}
+	if _, rpcErr := rpcCall(t, srv, "getBlockTime", 1); rpcErr == nil || rpcErr.Code != -32004 {
+		t.Errorf("getBlockTime of an unknown slot error = %+v, want -32004", rpcErr)
+	}
+	if raw, _ := rpcCall(t, srv, "getTransaction", "sigB", map[string]interface{}{"encoding": "base64"}); string(raw) != `{"slot":102}` {
+		t.Errorf("getTransaction = %s, want the recorded result", raw)
+	}
+	if raw, _ := rpcCall(t, srv, "getTransaction", "sigX"); string(raw) != "null" {
+		t.Errorf("getTransaction of an unknown signature = %s, want null", raw)
+	}
+	if _, rpcErr := rpcCall(t, srv, "getAccountInfo", "Program1111"); rpcErr == nil || rpcErr.Code != -32601 {
+		t.Errorf("getAccountInfo error = %+v, want method not found", rpcErr)
+	}
+	if got := srv.Calls("getTransaction"); got != 2 {
+		t.Errorf("Calls(getTransaction) = %d, want 2", got)
+	}
+}
+
+func TestRecorder_Record(t *testing.T) {
+	live := httptest.NewServer(NewServer(testFixture()))
+	defer live.Close()
+
+	f, err := NewRecorder(live.URL).Record(context.Background(), "Program1111", 3)
+	if err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if len(f.Signatures) != 3 || len(f.Transactions) != 3 {
+		t.Fatalf("Record() = %d signatures and %d transactions, want 3", len(f.Signatures), len(f.Transactions))
+	}
+
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	if err := f.WriteFile(path); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	read, err := ReadFixture(path)
+	if err != nil {
+		t.Fatalf("ReadFixture() error = %v", err)
+	}
+	var tx struct {
+		Slot uint64 `json:"slot"`
+	}
+	if err := json.Unmarshal(read.Transactions["sigB"], &tx); err != nil || tx.Slot != 102 {
+		t.Errorf("ReadFixture() transaction sigB = %s, want slot 102", read.Transactions["sigB"])
+	}
+	if read.Signatures[2].Signature != "sigB" || *read.Signatures[2].BlockTime != 1767225600 {
+		t.Errorf("ReadFixture() signature = %+v, want sigB", read.Signatures[2])
+	}
+}
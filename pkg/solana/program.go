@@ -0,0 +1,104 @@
+package solana
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+const (
+	// upgradeableProgramState is the state of an upgradeable loader program
+	// account, followed by the address of its program data account.
+	upgradeableProgramState = 2
+	// programDataHeaderSize is the size of the state, deployment slot and
+	// optional upgrade authority preceding the ELF in a program data account.
+	programDataHeaderSize = 4 + 8 + 1 + 32
+)
+
+// ProgramDeployment is the deployment of an upgradeable loader program.
+type ProgramDeployment struct {
+	ProgramData solana.PublicKey
+	// Slot is the slot the program was last deployed or upgraded at.
+	Slot uint64
+	// Authority may upgrade the program; nil once it is immutable.
+	Authority *solana.PublicKey
+}
+
+// GetProgramDeployment reads the deployment of an upgradeable loader
+// program. It returns nil for programs of the other loaders, which cannot
+// be upgraded.
+func (c *Client) GetProgramDeployment(ctx context.Context, program solana.PublicKey) (*ProgramDeployment, error) {
+	data, _, err := c.GetAccountData(ctx, program)
+	if err != nil {
+		return nil, err
+	}
+	programData, ok := programDataAddress(data)
+	if !ok {
+		return nil, nil
+	}
+	offset, length := uint64(0), uint64(programDataHeaderSize)
+	data, _, err = c.getAccountData(ctx, programData, &rpc.DataSlice{Offset: &offset, Length: &length})
+	if err != nil {
+		return nil, fmt.Errorf("program data of %s: %w", program, err)
+	}
+	return parseProgramDataHeader(programData, data)
+}
+
+// parseProgramDataHeader reads the header of a program data account.
+func parseProgramDataHeader(programData solana.PublicKey, data []byte) (*ProgramDeployment, error) {
+	if len(data) < programDataHeaderSize {
+		return nil, fmt.Errorf("program data %s is %d bytes, too short for its header", programData, len(data))
+	}
+	d := &ProgramDeployment{ProgramData: programData, Slot: binary.LittleEndian.Uint64(data[4:12])}
+	if data[12] == 1 {
+		authority := solana.PublicKeyFromBytes(data[13:45])
+		d.Authority = &authority
+	}
+	return d, nil
+}
+
+// GetProgramExecutable returns the deployed ELF of program: the program
+// account's data for the non-upgradeable loaders, the program data account's
+// past its header for the upgradeable one. The ELF is padded with zeros to
+// the size reserved for upgrades.
+func (c *Client) GetProgramExecutable(ctx context.Context, program solana.PublicKey) ([]byte, error) {
+	data, _, err := c.GetAccountData(ctx, program)
+	if err != nil {
+		return nil, err
+	}
+	programData, ok := programDataAddress(data)
+	if !ok {
+		return data, nil
+	}
+	data, _, err = c.GetAccountData(ctx, programData)
+	if err != nil {
+		return nil, fmt.Errorf("program data of %s: %w", program, err)
+	}
+	if len(data) < programDataHeaderSize {
+		return nil, fmt.Errorf("program data of %s is %d bytes, too short for its header", program, len(data))
+	}
+	return data[programDataHeaderSize:], nil
+}
+
+// programDataAddress returns the program data account an upgradeable
+// loader program account points to; ok is false for other accounts.
+func programDataAddress(data []byte) (solana.PublicKey, bool) {
+	if len(data) != 4+32 || binary.LittleEndian.Uint32(data) != upgradeableProgramState {
+		return solana.PublicKey{}, false
+	}
+	return solana.PublicKeyFromBytes(data[4:]), true
+}
+
+// ProgramHash returns the hex SHA-256 of a program's ELF with the trailing
+// zero padding removed, the hash solana-verify reports for a deployed or
+// locally built program.
+func ProgramHash(executable []byte) string {
+	sum := sha256.Sum256(bytes.TrimRight(executable, "\x00"))
+	return hex.EncodeToString(sum[:])
+}
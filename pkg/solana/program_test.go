@@ -0,0 +1,81 @@
+package solana
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+func TestProgramDataAddress(t *testing.T) {
+	programData := solana.MustPublicKeyFromBase58("CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc")
+	upgradeable := binary.LittleEndian.AppendUint32(nil, upgradeableProgramState)
+	upgradeable = append(upgradeable, programData[:]...)
+	buffer := binary.LittleEndian.AppendUint32(nil, 1)
+	buffer = append(buffer, programData[:]...)
+
+	tests := []struct {
+		name   string
+		data   []byte
+		want   solana.PublicKey
+		wantOK bool
+	}{
+		{name: "upgradeable program", data: upgradeable, want: programData, wantOK: true},
+		{name: "buffer account", data: buffer},
+		{name: "non-upgradeable ELF", data: []byte("\x7fELF")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := programDataAddress(tt.data)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("programDataAddress() = %s, %v, want %s, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestProgramHash(t *testing.T) {
+	elf := []byte("\x7fELF program")
+	padded := append(append([]byte{}, elf...), make([]byte, 64)...)
+	if got, want := ProgramHash(padded), ProgramHash(elf); got != want {
+		t.Errorf("ProgramHash() of padded ELF = %s, want %s", got, want)
+	}
+	// SHA-256 of the empty input.
+	if got, want := ProgramHash(nil), "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"; got != want {
+		t.Errorf("ProgramHash(nil) = %s, want %s", got, want)
+	}
+}
+
+func TestParseProgramDataHeader(t *testing.T) {
+	programData := solana.MustPublicKeyFromBase58("CounzVsCGF4VzNkAwePKC9mXr6YWiFYF4kLW6YdV8Cc")
+	authority := solana.MustPublicKeyFromBase58("11111111111111111111111111111112")
+	header := func(withAuthority bool) []byte {
+		data := binary.LittleEndian.AppendUint32(nil, 3)
+		data = binary.LittleEndian.AppendUint64(data, 123456)
+		if withAuthority {
+			data = append(data, 1)
+			return append(data, authority[:]...)
+		}
+		return append(data, make([]byte, 33)...)
+	}
+
+	d, err := parseProgramDataHeader(programData, header(true))
+	if err != nil {
+		t.Fatalf("parseProgramDataHeader() error = %v", err)
+	}
+	if d.ProgramData != programData || d.Slot != 123456 || d.Authority == nil || *d.Authority != authority {
+		t.Errorf("parseProgramDataHeader() = %+v, want slot 123456 and authority %s", d, authority)
+	}
+
+	d, err = parseProgramDataHeader(programData, header(false))
+	if err != nil {
+		t.Fatalf("parseProgramDataHeader() error = %v", err)
+	}
+	if d.Authority != nil {
+		t.Errorf("parseProgramDataHeader() authority = %s, want nil for an immutable program", d.Authority)
+	}
+
+	if _, err := parseProgramDataHeader(programData, header(true)[:20]); err == nil {
+		t.Errorf("parseProgramDataHeader() of a short header error = nil, want an error")
+	}
+}
@@ -0,0 +1,211 @@
+package solana
+
+import (
+	"context"
+	"math"
+	"sync"
+)
+
+// Priority ranks the work RPC calls are made for. The scheduler hands
+// scarce call slots to higher priorities first, in proportion to their
+// weights.
+type Priority int
+
+const (
+	// PriorityLive is polling new transactions; calls default to it.
+	PriorityLive Priority = iota
+	// PriorityRepair is catching up on a backlog, e.g. after downtime.
+	PriorityRepair
+	// PriorityBackfill is indexing history on request.
+	PriorityBackfill
+)
+
+// Priorities lists every priority, highest first.
+var Priorities = []Priority{PriorityLive, PriorityRepair, PriorityBackfill}
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityLive:
+		return "live"
+	case PriorityRepair:
+		return "repair"
+	case PriorityBackfill:
+		return "backfill"
+	}
+	return "unknown"
+}
+
+// ParsePriority returns the priority named s.
+func ParsePriority(s string) (Priority, bool) {
+	for _, p := range Priorities {
+		if p.String() == s {
+			return p, true
+		}
+	}
+	return 0, false
+}
+
+type priorityKey struct{}
+
+// WithPriority makes the RPC calls made with ctx scheduled at p.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+func priorityOf(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok && p >= PriorityLive && p <= PriorityBackfill {
+		return p
+	}
+	return PriorityLive
+}
+
+// PriorityStats are the scheduling counts of one priority.
+type PriorityStats struct {
+	Priority string `json:"priority"`
+	Weight   int    `json:"weight"`
+	Granted  int64  `json:"granted"`
+	Waiting  int    `json:"waiting"`
+	InFlight int    `json:"in_flight"`
+}
+
+// SchedulerStatus is a snapshot of a scheduler. Limit is the number of
+// calls currently allowed in flight, between 1 and MaxInFlight.
+type SchedulerStatus struct {
+	Limit       int             `json:"limit"`
+	MaxInFlight int             `json:"max_in_flight"`
+	Priorities  []PriorityStats `json:"priorities"`
+}
+
+type schedulerWaiter struct {
+	ready   chan struct{}
+	granted bool
+}
+
+// Scheduler caps the RPC calls in flight and, when calls queue for a slot,
+// grants slots to the priorities by smooth weighted round robin. The cap
+// halves on every failed call and grows back by one per limit successful
+// calls, so lower priorities are throttled first while the endpoint
+// struggles.
+type Scheduler struct {
+	max     int
+	weights [3]int
+
+	mu       sync.Mutex
+	limit    float64
+	inFlight [3]int
+	queues   [3][]*schedulerWaiter
+	current  [3]int
+	granted  [3]int64
+}
+
+// NewScheduler returns a scheduler allowing up to maxInFlight calls at
+// once. Priorities without a positive weight get weight 1.
+func NewScheduler(maxInFlight int, weights map[Priority]int) *Scheduler {
+	if maxInFlight < 1 {
+		maxInFlight = 1
+	}
+	s := &Scheduler{max: maxInFlight, limit: float64(maxInFlight)}
+	for _, p := range Priorities {
+		s.weights[p] = max(weights[p], 1)
+	}
+	return s
+}
+
+func (s *Scheduler) total() int {
+	return s.inFlight[PriorityLive] + s.inFlight[PriorityRepair] + s.inFlight[PriorityBackfill]
+}
+
+func (s *Scheduler) queued() bool {
+	return len(s.queues[PriorityLive])+len(s.queues[PriorityRepair])+len(s.queues[PriorityBackfill]) > 0
+}
+
+// Acquire waits for a call slot for p. Every successful Acquire must be
+// followed by Release.
+func (s *Scheduler) Acquire(ctx context.Context, p Priority) error {
+	s.mu.Lock()
+	if !s.queued() && s.total() < int(s.limit) {
+		s.inFlight[p]++
+		s.granted[p]++
+		s.mu.Unlock()
+		return nil
+	}
+	w := &schedulerWaiter{ready: make(chan struct{})}
+	s.queues[p] = append(s.queues[p], w)
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if w.granted {
+			s.inFlight[p]--
+			s.dispatch()
+		} else {
+			for i, queued := range s.queues[p] {
+				if queued == w {
+					s.queues[p] = append(s.queues[p][:i], s.queues[p][i+1:]...)
+					break
+				}
+			}
+		}
+		return ctx.Err()
+	}
+}
+
+// Release frees the slot of a call at p and adapts the limit to its
+// outcome.
+func (s *Scheduler) Release(p Priority, failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inFlight[p]--
+	if failed {
+		s.limit = math.Max(1, s.limit/2)
+	} else {
+		s.limit = math.Min(float64(s.max), s.limit+1/s.limit)
+	}
+	s.dispatch()
+}
+
+// dispatch grants free slots to the queued calls.
+func (s *Scheduler) dispatch() {
+	for s.total() < int(s.limit) && s.queued() {
+		next, total := -1, 0
+		for _, p := range Priorities {
+			if len(s.queues[p]) == 0 {
+				continue
+			}
+			s.current[p] += s.weights[p]
+			total += s.weights[p]
+			if next < 0 || s.current[p] > s.current[next] {
+				next = int(p)
+			}
+		}
+		s.current[next] -= total
+
+		w := s.queues[next][0]
+		s.queues[next] = s.queues[next][1:]
+		w.granted = true
+		s.inFlight[next]++
+		s.granted[next]++
+		close(w.ready)
+	}
+}
+
+// Status returns a snapshot of the scheduler, by priority.
+func (s *Scheduler) Status() SchedulerStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := SchedulerStatus{Limit: int(s.limit), MaxInFlight: s.max}
+	for _, p := range Priorities {
+		status.Priorities = append(status.Priorities, PriorityStats{
+			Priority: p.String(),
+			Weight:   s.weights[p],
+			Granted:  s.granted[p],
+			Waiting:  len(s.queues[p]),
+			InFlight: s.inFlight[p],
+		})
+	}
+	return status
+}
@@ -0,0 +1,110 @@
+package solana
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// queue makes n calls at p wait for a slot of s, returning the order they
+// are granted in.
+func queue(t *testing.T, s *Scheduler, p Priority, n int, granted chan<- Priority) {
+	t.Helper()
+	for range n {
+		go func() {
+			if err := s.Acquire(context.Background(), p); err != nil {
+				t.Errorf("Acquire() error = %v", err)
+				return
+			}
+			granted <- p
+		}()
+	}
+}
+
+func waiting(s *Scheduler) int {
+	var n int
+	for _, p := range s.Status().Priorities {
+		n += p.Waiting
+	}
+	return n
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestScheduler_WeightedGrants(t *testing.T) {
+	s := NewScheduler(1, map[Priority]int{PriorityLive: 3, PriorityBackfill: 1})
+	if err := s.Acquire(context.Background(), PriorityBackfill); err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	granted := make(chan Priority, 8)
+	queue(t, s, PriorityLive, 4, granted)
+	queue(t, s, PriorityBackfill, 4, granted)
+	waitFor(t, func() bool { return waiting(s) == 8 })
+
+	counts := make(map[Priority]int)
+	held := PriorityBackfill
+	for range 4 {
+		s.Release(held, false)
+		held = <-granted
+		counts[held]++
+	}
+	if counts[PriorityLive] != 3 || counts[PriorityBackfill] != 1 {
+		t.Errorf("first 4 grants = %v, want 3 live and 1 backfill", counts)
+	}
+}
+
+func TestScheduler_LimitAdapts(t *testing.T) {
+	s := NewScheduler(8, nil)
+	ctx := context.Background()
+
+	for _, want := range []int{4, 2, 1, 1} {
+		if err := s.Acquire(ctx, PriorityLive); err != nil {
+			t.Fatalf("Acquire() error = %v", err)
+		}
+		s.Release(PriorityLive, true)
+		if got := s.Status().Limit; got != want {
+			t.Errorf("Limit after failure = %d, want %d", got, want)
+		}
+	}
+	for range 3 {
+		_ = s.Acquire(ctx, PriorityLive)
+		s.Release(PriorityLive, false)
+	}
+	if got := s.Status().Limit; got != 2 {
+		t.Errorf("Limit after 3 successes = %d, want 2", got)
+	}
+}
+
+func TestScheduler_CancelledWaiter(t *testing.T) {
+	s := NewScheduler(1, nil)
+	if err := s.Acquire(context.Background(), PriorityLive); err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Acquire(ctx, PriorityBackfill) }()
+	waitFor(t, func() bool { return waiting(s) == 1 })
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Acquire() error = %v, want %v", err, context.Canceled)
+	}
+	if got := waiting(s); got != 0 {
+		t.Errorf("waiting = %d, want 0", got)
+	}
+
+	s.Release(PriorityLive, false)
+	if err := s.Acquire(context.Background(), PriorityLive); err != nil {
+		t.Errorf("Acquire() after release error = %v", err)
+	}
+}
@@ -16,7 +16,7 @@ go mod verify
 # Build
 echo ""
 echo "✓ Building indexer..."
-go build -o indexer cmd/indexer/main.go
+go build -o indexer ./cmd/indexer
 
 # Check binary
 echo ""